@@ -113,5 +113,34 @@ func (c *Counter) ValueOf(aggType aggregation.Type) float64 {
 	}
 }
 
+// CounterSnapshot is a snapshot of the counter state.
+type CounterSnapshot struct {
+	Sum   int64
+	SumSq int64
+	Count int64
+	Max   int64
+	Min   int64
+}
+
+// Snapshot returns a snapshot of the counter state.
+func (c *Counter) Snapshot() CounterSnapshot {
+	return CounterSnapshot{
+		Sum:   c.sum,
+		SumSq: c.sumSq,
+		Count: c.count,
+		Max:   c.max,
+		Min:   c.min,
+	}
+}
+
+// Restore restores the counter state from a snapshot.
+func (c *Counter) Restore(snapshot CounterSnapshot) {
+	c.sum = snapshot.Sum
+	c.sumSq = snapshot.SumSq
+	c.count = snapshot.Count
+	c.max = snapshot.Max
+	c.min = snapshot.Min
+}
+
 // Close closes the counter.
 func (c *Counter) Close() {}
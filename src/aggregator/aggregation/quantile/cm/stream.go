@@ -187,6 +187,46 @@ func (s *stream) ResetSetData(quantiles []float64) {
 	s.compressMinRank = 0
 }
 
+func (s *stream) Snapshot() StreamSnapshot {
+	s.Flush()
+	samples := make([]SampleSnapshot, 0, s.samples.Len())
+	for sample := s.samples.Front(); sample != nil; sample = sample.next {
+		samples = append(samples, SampleSnapshot{
+			Value:    sample.value,
+			NumRanks: sample.numRanks,
+			Delta:    sample.delta,
+		})
+	}
+	return StreamSnapshot{
+		NumValues: s.numValues,
+		Samples:   samples,
+	}
+}
+
+func (s *stream) Restore(snapshot StreamSnapshot) {
+	// Release existing samples and clear the buffers before restoring.
+	for sample := s.samples.Front(); sample != nil; {
+		next := sample.next
+		s.releaseSampleFn(sample)
+		sample = next
+	}
+	s.samples.Reset()
+	s.bufLess = s.bufLess[:0]
+	s.bufMore = s.bufMore[:0]
+	s.insertAndCompressCounter = 0
+	s.flushCounter = 0
+	s.insertCursor = nil
+	s.compressCursor = nil
+	s.compressMinRank = 0
+
+	for _, ss := range snapshot.Samples {
+		sample := s.acquireSampleFn()
+		sample.setData(ss.Value, ss.NumRanks, ss.Delta)
+		s.samples.PushBack(sample)
+	}
+	s.numValues = snapshot.NumValues
+}
+
 func (s *stream) Close() {
 	if s.closed {
 		return
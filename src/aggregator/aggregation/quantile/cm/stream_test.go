@@ -225,6 +225,33 @@ func TestStreamAddToMinHeap(t *testing.T) {
 	require.Equal(t, 2, cap(heap))
 }
 
+func TestStreamSnapshotRestore(t *testing.T) {
+	opts := testStreamOptions()
+	s := NewStream(testQuantiles, opts)
+	for i := 0; i < 10000; i++ {
+		s.Add(float64(i))
+	}
+	snapshot := s.Snapshot()
+	require.Equal(t, int64(10000), snapshot.NumValues)
+
+	restored := NewStream(testQuantiles, opts)
+	restored.Add(1234.0)
+	restored.Restore(snapshot)
+	require.Equal(t, s.Min(), restored.Min())
+	require.Equal(t, s.Max(), restored.Max())
+	for _, q := range testQuantiles {
+		require.Equal(t, s.Quantile(q), restored.Quantile(q))
+	}
+
+	// Values added after restoring are merged with the restored samples.
+	restored.Add(20000.0)
+	restored.Add(-1.0)
+	restored.Flush()
+	require.Equal(t, -1.0, restored.Min())
+	require.Equal(t, 20000.0, restored.Max())
+	require.Equal(t, int64(10002), restored.Snapshot().NumValues)
+}
+
 func testStreamWithIncreasingSamples(t *testing.T, opts Options) {
 	numSamples := 100000
 	s := NewStream(testQuantiles, opts)
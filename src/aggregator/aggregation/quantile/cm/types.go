@@ -65,6 +65,27 @@ type Stream interface {
 
 	// ResetSetData resets the stream and sets data.
 	ResetSetData(quantiles []float64)
+
+	// Snapshot flushes the internal buffer and returns a snapshot of the
+	// samples in the stream.
+	Snapshot() StreamSnapshot
+
+	// Restore replaces the samples in the stream with those in the snapshot.
+	Restore(snapshot StreamSnapshot)
+}
+
+// SampleSnapshot is a snapshot of a sampled value.
+type SampleSnapshot struct {
+	Value    float64
+	NumRanks int64
+	Delta    int64
+}
+
+// StreamSnapshot is a snapshot of the samples in a stream, which can be used
+// to restore the stream to the same state.
+type StreamSnapshot struct {
+	NumValues int64
+	Samples   []SampleSnapshot
 }
 
 // StreamAlloc allocates a stream.
@@ -128,5 +128,31 @@ func (t *Timer) ValueOf(aggType aggregation.Type) float64 {
 	return 0
 }
 
+// TimerSnapshot is a snapshot of the timer state.
+type TimerSnapshot struct {
+	Count  int64
+	Sum    float64
+	SumSq  float64
+	Stream cm.StreamSnapshot
+}
+
+// Snapshot returns a snapshot of the timer state.
+func (t *Timer) Snapshot() TimerSnapshot {
+	return TimerSnapshot{
+		Count:  t.count,
+		Sum:    t.sum,
+		SumSq:  t.sumSq,
+		Stream: t.stream.Snapshot(),
+	}
+}
+
+// Restore restores the timer state from a snapshot.
+func (t *Timer) Restore(snapshot TimerSnapshot) {
+	t.count = snapshot.Count
+	t.sum = snapshot.Sum
+	t.sumSq = snapshot.SumSq
+	t.stream.Restore(snapshot.Stream)
+}
+
 // Close closes the timer.
 func (t *Timer) Close() { t.stream.Close() }
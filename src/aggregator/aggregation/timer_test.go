@@ -169,3 +169,24 @@ func TestTimerAggregationsNotExpensive(t *testing.T) {
 	// Closing the timer a second time should be a no op.
 	timer.Close()
 }
+
+func TestTimerSnapshotRestore(t *testing.T) {
+	opts := NewOptions()
+	opts.HasExpensiveAggregations = true
+
+	timer := NewTimer(testQuantiles, cm.NewOptions(), opts)
+	for i := 1; i <= 100; i++ {
+		timer.Add(float64(i))
+	}
+
+	restored := NewTimer(testQuantiles, cm.NewOptions(), opts)
+	restored.Restore(timer.Snapshot())
+	require.Equal(t, timer.Count(), restored.Count())
+	require.Equal(t, timer.Sum(), restored.Sum())
+	require.Equal(t, timer.SumSq(), restored.SumSq())
+	require.Equal(t, timer.Min(), restored.Min())
+	require.Equal(t, timer.Max(), restored.Max())
+	for _, q := range testQuantiles {
+		require.Equal(t, timer.Quantile(q), restored.Quantile(q))
+	}
+}
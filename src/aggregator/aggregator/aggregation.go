@@ -21,10 +21,24 @@
 package aggregator
 
 import (
+	"errors"
+
 	"github.com/m3db/m3/src/aggregator/aggregation"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
 )
 
+var (
+	errUnexpectedAggregationSnapshot = errors.New("unexpected aggregation snapshot type")
+)
+
+// aggregationSnapshot is a snapshot of a type-specific aggregation. Only the
+// field corresponding to the aggregation type is set.
+type aggregationSnapshot struct {
+	Counter *aggregation.CounterSnapshot
+	Timer   *aggregation.TimerSnapshot
+	Gauge   *aggregation.GaugeSnapshot
+}
+
 // counterAggregation is a counter aggregation.
 type counterAggregation struct {
 	aggregation.Counter
@@ -37,6 +51,19 @@ func newCounterAggregation(c aggregation.Counter) counterAggregation {
 func (c *counterAggregation) Add(value float64)                    { c.Counter.Update(int64(value)) }
 func (c *counterAggregation) AddUnion(mu unaggregated.MetricUnion) { c.Counter.Update(mu.CounterVal) }
 
+func (c *counterAggregation) Snapshot() aggregationSnapshot {
+	snapshot := c.Counter.Snapshot()
+	return aggregationSnapshot{Counter: &snapshot}
+}
+
+func (c *counterAggregation) Restore(snapshot aggregationSnapshot) error {
+	if snapshot.Counter == nil {
+		return errUnexpectedAggregationSnapshot
+	}
+	c.Counter.Restore(*snapshot.Counter)
+	return nil
+}
+
 // timerAggregation is a timer aggregation.
 type timerAggregation struct {
 	aggregation.Timer
@@ -46,6 +73,19 @@ func newTimerAggregation(t aggregation.Timer) timerAggregation   { return timerA
 func (t *timerAggregation) Add(value float64)                    { t.Timer.Add(value) }
 func (t *timerAggregation) AddUnion(mu unaggregated.MetricUnion) { t.Timer.AddBatch(mu.BatchTimerVal) }
 
+func (t *timerAggregation) Snapshot() aggregationSnapshot {
+	snapshot := t.Timer.Snapshot()
+	return aggregationSnapshot{Timer: &snapshot}
+}
+
+func (t *timerAggregation) Restore(snapshot aggregationSnapshot) error {
+	if snapshot.Timer == nil {
+		return errUnexpectedAggregationSnapshot
+	}
+	t.Timer.Restore(*snapshot.Timer)
+	return nil
+}
+
 // gaugeAggregation is a gauge aggregation.
 type gaugeAggregation struct {
 	aggregation.Gauge
@@ -54,3 +94,16 @@ type gaugeAggregation struct {
 func newGaugeAggregation(g aggregation.Gauge) gaugeAggregation   { return gaugeAggregation{Gauge: g} }
 func (g *gaugeAggregation) Add(value float64)                    { g.Gauge.Update(value) }
 func (g *gaugeAggregation) AddUnion(mu unaggregated.MetricUnion) { g.Gauge.Update(mu.GaugeVal) }
+
+func (g *gaugeAggregation) Snapshot() aggregationSnapshot {
+	snapshot := g.Gauge.Snapshot()
+	return aggregationSnapshot{Gauge: &snapshot}
+}
+
+func (g *gaugeAggregation) Restore(snapshot aggregationSnapshot) error {
+	if snapshot.Gauge == nil {
+		return errUnexpectedAggregationSnapshot
+	}
+	g.Gauge.Restore(*snapshot.Gauge)
+	return nil
+}
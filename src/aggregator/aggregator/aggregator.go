@@ -24,6 +24,7 @@ import (
 	"context"
 	"errors"
 	"math"
+	"os"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -41,6 +42,7 @@ import (
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
 	"github.com/m3db/m3x/clock"
 	"github.com/m3db/m3x/instrument"
+	"github.com/m3db/m3x/log"
 
 	"github.com/uber-go/tally"
 )
@@ -56,6 +58,7 @@ var (
 	errInvalidMetricType             = errors.New("invalid metric type")
 	errActivePlacementChanged        = errors.New("active placement has changed")
 	errShardNotOwned                 = errors.New("aggregator shard is not owned")
+	errCheckpointsOutdated           = errors.New("shard checkpoints are outdated")
 )
 
 // Aggregator aggregates different types of metrics.
@@ -79,6 +82,9 @@ type Aggregator interface {
 	// Status returns the run-time status of the aggregator.
 	Status() RuntimeStatus
 
+	// Inspect returns the aggregation states of the metrics matching the query.
+	Inspect(query InspectQuery) (InspectResult, error)
+
 	// Close closes the aggregator.
 	Close() error
 }
@@ -100,6 +106,10 @@ type aggregator struct {
 	flushHandler      handler.Handler
 	adminClient       client.AdminClient
 	resignTimeout     time.Duration
+	checkpointer      *shardCheckpointer
+	checkpointEvery   time.Duration
+	maxCheckpointAge  time.Duration
+	logger            log.Logger
 
 	shardSetID          uint32
 	shardSetOpen        bool
@@ -120,7 +130,7 @@ func NewAggregator(opts Options) Aggregator {
 	iOpts := opts.InstrumentOptions()
 	scope := iOpts.MetricsScope()
 	samplingRate := iOpts.MetricsSamplingRate()
-	return &aggregator{
+	agg := &aggregator{
 		opts:              opts,
 		nowFn:             opts.ClockOptions().NowFn(),
 		shardFn:           opts.ShardFn(),
@@ -133,33 +143,65 @@ func NewAggregator(opts Options) Aggregator {
 		flushHandler:      opts.FlushHandler(),
 		adminClient:       opts.AdminClient(),
 		resignTimeout:     opts.ResignTimeout(),
+		logger:            iOpts.Logger(),
 		metrics:           newAggregatorMetrics(scope, samplingRate, opts.MaxAllowedForwardingDelayFn()),
 		doneCh:            make(chan struct{}),
 		sleepFn:           time.Sleep,
 	}
+	if checkpointOpts := opts.CheckpointOptions(); checkpointOpts != nil {
+		agg.checkpointer = newShardCheckpointer(checkpointOpts)
+		agg.checkpointEvery = checkpointOpts.CheckpointEvery()
+		agg.maxCheckpointAge = checkpointOpts.MaxCheckpointAge()
+	}
+	return agg
 }
 
 func (agg *aggregator) Open() error {
 	agg.Lock()
-	defer agg.Unlock()
-
 	if agg.state != aggregatorNotOpen {
+		agg.Unlock()
 		return errAggregatorAlreadyOpenOrClosed
 	}
 	if err := agg.placementManager.Open(); err != nil {
+		agg.Unlock()
 		return err
 	}
+	agg.Unlock()
+
+	// NB: the shard checkpoints are read from disk without holding the lock, and
+	// the shards are restored from them as they are added while processing the
+	// placement, which happens before the aggregator is marked as open so no
+	// metrics are accepted until restoration completes.
+	for {
+		checkpoints := agg.readCheckpoints()
+		agg.Lock()
+		err := agg.openWithLock(checkpoints)
+		agg.Unlock()
+		if err != errCheckpointsOutdated {
+			return err
+		}
+	}
+}
+
+func (agg *aggregator) openWithLock(checkpoints shardCheckpoints) error {
+	if agg.state != aggregatorNotOpen {
+		return errAggregatorAlreadyOpenOrClosed
+	}
 	stagedPlacement, placement, err := agg.placementManager.Placement()
 	if err != nil {
 		return err
 	}
-	if err := agg.processPlacementWithLock(stagedPlacement, placement); err != nil {
+	if err := agg.processPlacementWithLock(stagedPlacement, placement, checkpoints); err != nil {
 		return err
 	}
 	if agg.checkInterval > 0 {
 		agg.wg.Add(1)
 		go agg.tick()
 	}
+	if agg.checkpointer != nil {
+		agg.wg.Add(1)
+		go agg.checkpoint()
+	}
 	agg.state = aggregatorOpen
 	return nil
 }
@@ -243,6 +285,45 @@ func (agg *aggregator) Status() RuntimeStatus {
 	}
 }
 
+func (agg *aggregator) Inspect(query InspectQuery) (InspectResult, error) {
+	var shards []*aggregatorShard
+	if query.ID != nil {
+		shard, err := agg.shardFor(id.RawID(query.ID))
+		if err != nil {
+			return InspectResult{}, err
+		}
+		shards = []*aggregatorShard{shard}
+	} else {
+		agg.RLock()
+		if agg.state != aggregatorOpen {
+			agg.RUnlock()
+			return InspectResult{}, errAggregatorNotOpenOrClosed
+		}
+		shards = agg.ownedShardsWithLock()
+		agg.RUnlock()
+	}
+
+	flushTimes, err := agg.flushTimesManager.Get()
+	if err != nil {
+		agg.logger.Errorf("could not get flush times for inspection: %v", err)
+	}
+	res := InspectResult{Shards: make([]ShardInspectResult, 0, len(shards))}
+	for _, shard := range shards {
+		shardRes, err := shard.Inspect(query)
+		if err == errAggregatorShardClosed {
+			continue
+		}
+		if err != nil {
+			return InspectResult{}, err
+		}
+		if flushTimes != nil {
+			shardRes.FlushTimes = flushTimes.ByShard[shard.ID()]
+		}
+		res.Shards = append(res.Shards, shardRes)
+	}
+	return res, nil
+}
+
 func (agg *aggregator) Close() error {
 	agg.Lock()
 	defer agg.Unlock()
@@ -251,6 +332,9 @@ func (agg *aggregator) Close() error {
 		return errAggregatorNotOpenOrClosed
 	}
 	close(agg.doneCh)
+	if agg.checkpointer != nil {
+		agg.checkpointShards(agg.ownedShardsWithLock())
+	}
 	for _, shardID := range agg.shardIDs {
 		agg.shards[shardID].Close()
 	}
@@ -267,21 +351,32 @@ func (agg *aggregator) Close() error {
 
 func (agg *aggregator) shardFor(id id.RawID) (*aggregatorShard, error) {
 	agg.RLock()
-	shard, err := agg.shardForWithLock(id, noUpdateShards)
+	shard, err := agg.shardForWithLock(id, noUpdateShards, nil)
 	if err == nil || err != errActivePlacementChanged {
 		agg.RUnlock()
 		return shard, err
 	}
 	agg.RUnlock()
 
-	agg.Lock()
-	shard, err = agg.shardForWithLock(id, updateShards)
-	agg.Unlock()
-
-	return shard, err
+	// NB: the checkpoints of the shards added by the new placement are read from
+	// disk before taking the lock, and the placement is processed again should it
+	// have changed in the meantime.
+	for {
+		checkpoints := agg.readCheckpoints()
+		agg.Lock()
+		shard, err = agg.shardForWithLock(id, updateShards, checkpoints)
+		agg.Unlock()
+		if err != errCheckpointsOutdated {
+			return shard, err
+		}
+	}
 }
 
-func (agg *aggregator) shardForWithLock(id id.RawID, updateShardsType updateShardsType) (*aggregatorShard, error) {
+func (agg *aggregator) shardForWithLock(
+	id id.RawID,
+	updateShardsType updateShardsType,
+	checkpoints shardCheckpoints,
+) (*aggregatorShard, error) {
 	if agg.state != aggregatorOpen {
 		return nil, errAggregatorNotOpenOrClosed
 	}
@@ -293,7 +388,7 @@ func (agg *aggregator) shardForWithLock(id id.RawID, updateShardsType updateShar
 		if updateShardsType == noUpdateShards {
 			return nil, errActivePlacementChanged
 		}
-		if err := agg.processPlacementWithLock(stagedPlacement, placement); err != nil {
+		if err := agg.processPlacementWithLock(stagedPlacement, placement, checkpoints); err != nil {
 			return nil, err
 		}
 	}
@@ -308,6 +403,7 @@ func (agg *aggregator) shardForWithLock(id id.RawID, updateShardsType updateShar
 func (agg *aggregator) processPlacementWithLock(
 	newStagedPlacement placement.ActiveStagedPlacement,
 	newPlacement placement.Placement,
+	checkpoints shardCheckpoints,
 ) error {
 	// If someone has already processed the placement ahead of us, do nothing.
 	if !agg.shouldProcessPlacementWithLock(newStagedPlacement, newPlacement) {
@@ -322,7 +418,10 @@ func (agg *aggregator) processPlacementWithLock(
 	} else {
 		return err
 	}
-	agg.updateShardsWithLock(newStagedPlacement, newPlacement, newShardSet)
+	if !agg.hasCheckpointsWithLock(newShardSet, checkpoints) {
+		return errCheckpointsOutdated
+	}
+	agg.updateShardsWithLock(newStagedPlacement, newPlacement, newShardSet, checkpoints)
 	if err := agg.updateShardSetIDWithLock(instance); err != nil {
 		return err
 	}
@@ -437,6 +536,7 @@ func (agg *aggregator) updateShardsWithLock(
 	newStagedPlacement placement.ActiveStagedPlacement,
 	newPlacement placement.Placement,
 	newShardSet shard.Shards,
+	checkpoints shardCheckpoints,
 ) {
 	var (
 		incoming []*aggregatorShard
@@ -455,6 +555,7 @@ func (agg *aggregator) updateShardsWithLock(
 	var (
 		newShards   = newShardSet.All()
 		newShardIDs []uint32
+		added       []*aggregatorShard
 	)
 	if numShards := len(newShards); numShards > 0 {
 		newShardIDs = make([]uint32, 0, numShards)
@@ -468,6 +569,7 @@ func (agg *aggregator) updateShardsWithLock(
 			incoming[shardID] = agg.shards[shardID]
 		} else {
 			incoming[shardID] = newAggregatorShard(shardID, agg.opts)
+			added = append(added, incoming[shardID])
 			agg.metrics.shards.add.Inc(1)
 		}
 		shardTimeRange := timeRange{
@@ -477,6 +579,10 @@ func (agg *aggregator) updateShardsWithLock(
 		incoming[shardID].SetWriteableRange(shardTimeRange)
 	}
 
+	// Newly added shards are restored after their writeable ranges are set so
+	// that windows outside of the ranges are dropped during restoration.
+	agg.restoreShardsWithLock(added, checkpoints)
+
 	agg.shardIDs = newShardIDs
 	agg.shards = incoming
 	agg.currStagedPlacement = newStagedPlacement
@@ -560,6 +666,162 @@ func (agg *aggregator) closeShardsAsync(shards []*aggregatorShard) {
 	}
 }
 
+// shardCheckpoints are the checkpoints read from disk keyed by shard id, where
+// a nil checkpoint means the shard has no checkpoint to restore from.
+type shardCheckpoints map[uint32]*shardCheckpoint
+
+// readCheckpoints reads from disk the checkpoints of the shards owned by the
+// instance in the current placement that have not been added yet. It is called
+// without holding the lock so that reading checkpoints does not block writes.
+func (agg *aggregator) readCheckpoints() shardCheckpoints {
+	if agg.checkpointer == nil {
+		return nil
+	}
+	_, placement, err := agg.placementManager.Placement()
+	if err != nil {
+		return nil
+	}
+	instance, err := agg.placementManager.InstanceFrom(placement)
+	if err != nil {
+		return nil
+	}
+	var toRead []uint32
+	agg.RLock()
+	for _, shard := range instance.Shards().All() {
+		if shardID := shard.ID(); !agg.ownsShardWithLock(shardID) {
+			toRead = append(toRead, shardID)
+		}
+	}
+	agg.RUnlock()
+
+	var (
+		nowNanos    = agg.nowFn().UnixNano()
+		checkpoints = make(shardCheckpoints, len(toRead))
+	)
+	for _, shardID := range toRead {
+		checkpoints[shardID] = agg.readCheckpoint(shardID, nowNanos)
+	}
+	return checkpoints
+}
+
+func (agg *aggregator) readCheckpoint(shardID uint32, nowNanos int64) *shardCheckpoint {
+	checkpoint, err := agg.checkpointer.Read(shardID)
+	if os.IsNotExist(err) {
+		agg.metrics.checkpoint.restoreNotFound.Inc(1)
+		return nil
+	}
+	if err != nil {
+		agg.metrics.checkpoint.restoreErrors.Inc(1)
+		agg.logger.Errorf("unable to read checkpoint for shard %d: %v", shardID, err)
+		return nil
+	}
+	if nowNanos-checkpoint.CheckpointNanos > agg.maxCheckpointAge.Nanoseconds() {
+		agg.metrics.checkpoint.restoreStale.Inc(1)
+		return nil
+	}
+	return &checkpoint
+}
+
+func (agg *aggregator) ownsShardWithLock(shardID uint32) bool {
+	return int(shardID) < len(agg.shards) && agg.shards[shardID] != nil
+}
+
+// hasCheckpointsWithLock returns true if the checkpoints have been read for all
+// the shards in the new shard set that are not owned yet.
+func (agg *aggregator) hasCheckpointsWithLock(
+	newShardSet shard.Shards,
+	checkpoints shardCheckpoints,
+) bool {
+	if agg.checkpointer == nil {
+		return true
+	}
+	for _, shard := range newShardSet.All() {
+		shardID := shard.ID()
+		if agg.ownsShardWithLock(shardID) {
+			continue
+		}
+		if _, exists := checkpoints[shardID]; !exists {
+			return false
+		}
+	}
+	return true
+}
+
+// restoreShardsWithLock restores the given shards from their checkpoints if any.
+func (agg *aggregator) restoreShardsWithLock(
+	shards []*aggregatorShard,
+	checkpoints shardCheckpoints,
+) {
+	if agg.checkpointer == nil {
+		return
+	}
+	for _, shard := range shards {
+		checkpoint := checkpoints[shard.ID()]
+		if checkpoint == nil {
+			continue
+		}
+		numRestored, err := shard.Restore(*checkpoint)
+		agg.metrics.checkpoint.restoredEntries.Inc(int64(numRestored))
+		if err != nil {
+			agg.metrics.checkpoint.restoreErrors.Inc(1)
+			agg.logger.Errorf("unable to restore shard %d from checkpoint: %v", shard.ID(), err)
+			continue
+		}
+		agg.metrics.checkpoint.restoreSuccess.Inc(1)
+	}
+}
+
+func (agg *aggregator) ownedShardsWithLock() []*aggregatorShard {
+	shards := make([]*aggregatorShard, 0, len(agg.shardIDs))
+	for _, shardID := range agg.shardIDs {
+		shards = append(shards, agg.shards[shardID])
+	}
+	return shards
+}
+
+func (agg *aggregator) checkpoint() {
+	defer agg.wg.Done()
+
+	ticker := time.NewTicker(agg.checkpointEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-agg.doneCh:
+			return
+		case <-ticker.C:
+			agg.RLock()
+			if agg.state != aggregatorOpen {
+				agg.RUnlock()
+				return
+			}
+			shards := agg.ownedShardsWithLock()
+			agg.RUnlock()
+			agg.checkpointShards(shards)
+		}
+	}
+}
+
+func (agg *aggregator) checkpointShards(shards []*aggregatorShard) {
+	start := agg.nowFn()
+	for _, shard := range shards {
+		checkpoint, err := shard.Checkpoint()
+		if err == errAggregatorShardClosed {
+			continue
+		}
+		if err == nil {
+			err = agg.checkpointer.Write(checkpoint)
+		}
+		if err != nil {
+			agg.metrics.checkpoint.writeErrors.Inc(1)
+			agg.logger.Errorf("unable to checkpoint shard %d: %v", shard.ID(), err)
+			continue
+		}
+		agg.metrics.checkpoint.writeSuccess.Inc(1)
+	}
+	agg.metrics.checkpoint.duration.Record(agg.nowFn().Sub(start))
+}
+
 func (agg *aggregator) tick() {
 	defer agg.wg.Done()
 
@@ -866,6 +1128,30 @@ func newAggregatorPlacementMetrics(scope tally.Scope) aggregatorPlacementMetrics
 	}
 }
 
+type aggregatorCheckpointMetrics struct {
+	writeSuccess    tally.Counter
+	writeErrors     tally.Counter
+	duration        tally.Timer
+	restoreSuccess  tally.Counter
+	restoreErrors   tally.Counter
+	restoreNotFound tally.Counter
+	restoreStale    tally.Counter
+	restoredEntries tally.Counter
+}
+
+func newAggregatorCheckpointMetrics(scope tally.Scope) aggregatorCheckpointMetrics {
+	return aggregatorCheckpointMetrics{
+		writeSuccess:    scope.Counter("write-success"),
+		writeErrors:     scope.Counter("write-errors"),
+		duration:        scope.Timer("duration"),
+		restoreSuccess:  scope.Counter("restore-success"),
+		restoreErrors:   scope.Counter("restore-errors"),
+		restoreNotFound: scope.Counter("restore-not-found"),
+		restoreStale:    scope.Counter("restore-stale"),
+		restoredEntries: scope.Counter("restored-entries"),
+	}
+}
+
 type aggregatorShardSetIDMetrics struct {
 	open    tally.Counter
 	close   tally.Counter
@@ -900,6 +1186,7 @@ type aggregatorMetrics struct {
 	shards       aggregatorShardsMetrics
 	shardSetID   aggregatorShardSetIDMetrics
 	tick         aggregatorTickMetrics
+	checkpoint   aggregatorCheckpointMetrics
 }
 
 func newAggregatorMetrics(
@@ -914,6 +1201,7 @@ func newAggregatorMetrics(
 	shardsScope := scope.SubScope("shards")
 	shardSetIDScope := scope.SubScope("shard-set-id")
 	tickScope := scope.SubScope("tick")
+	checkpointScope := scope.SubScope("checkpoint")
 	return aggregatorMetrics{
 		counters:     scope.Counter("counters"),
 		timers:       scope.Counter("timers"),
@@ -928,6 +1216,7 @@ func newAggregatorMetrics(
 		shards:       newAggregatorShardsMetrics(shardsScope),
 		shardSetID:   newAggregatorShardSetIDMetrics(shardSetIDScope),
 		tick:         newAggregatorTickMetrics(tickScope),
+		checkpoint:   newAggregatorCheckpointMetrics(checkpointScope),
 	}
 }
 
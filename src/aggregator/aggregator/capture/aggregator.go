@@ -135,6 +135,10 @@ func (agg *aggregator) Resign() error              { return nil }
 func (agg *aggregator) Status() aggr.RuntimeStatus { return aggr.RuntimeStatus{} }
 func (agg *aggregator) Close() error               { return nil }
 
+func (agg *aggregator) Inspect(aggr.InspectQuery) (aggr.InspectResult, error) {
+	return aggr.InspectResult{}, nil
+}
+
 func (agg *aggregator) NumMetricsAdded() int {
 	agg.RLock()
 	numMetricsAdded := agg.numMetricsAdded
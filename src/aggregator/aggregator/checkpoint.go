@@ -0,0 +1,560 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/adler32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	raggregation "github.com/m3db/m3/src/aggregator/aggregation"
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/cm"
+	"github.com/m3db/m3/src/aggregator/generated/proto/checkpointpb"
+	maggregation "github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/metrics/pipeline/applied"
+	"github.com/m3db/m3/src/metrics/policy"
+)
+
+const (
+	// checkpointMagic identifies aggregator checkpoint files.
+	checkpointMagic uint32 = 0x4d334143
+
+	// checkpointVersion is the current version of the checkpoint file format.
+	// It must be incremented whenever the layout of the encoded checkpoint changes
+	// in a way that is not backward compatible.
+	checkpointVersion uint32 = 1
+
+	// The header consists of the magic number, the format version and the
+	// payload length, and the payload is followed by its adler32 checksum.
+	checkpointHeaderSize   = 16
+	checkpointChecksumSize = 4
+
+	checkpointFilePrefix    = "shard-"
+	checkpointFileSuffix    = ".checkpoint"
+	checkpointTmpFileSuffix = ".tmp"
+)
+
+var (
+	errCheckpointTooShort         = errors.New("checkpoint is too short")
+	errCheckpointInvalidMagic     = errors.New("checkpoint has invalid magic number")
+	errCheckpointChecksumMismatch = errors.New("checkpoint checksum mismatch")
+	errCheckpointLengthMismatch   = errors.New("checkpoint payload length mismatch")
+	errCheckpointShardMismatch    = errors.New("checkpoint shard does not match aggregator shard")
+	errUnknownMetricCategory      = errors.New("unknown metric category")
+	errUnknownIDPrefixSuffixType  = errors.New("unknown id prefix suffix type")
+)
+
+// restoreWindowFilterFn determines whether an aggregation window starting at
+// the given time with the given resolution should be restored.
+type restoreWindowFilterFn func(windowStartNanos int64, resolution time.Duration) bool
+
+// windowSnapshot is a snapshot of an aggregation window in an element.
+type windowSnapshot struct {
+	StartAtNanos int64
+	SourcesSeen  []byte
+	Aggregation  aggregationSnapshot
+}
+
+// elemSnapshot is a snapshot of the unconsumed aggregation windows in an element
+// alongside the states needed to apply transformations when they are consumed.
+type elemSnapshot struct {
+	LastConsumedAtNanos  int64
+	LastConsumedValues   []float64
+	LastCumulativeValues []float64
+	DeferredAtNanos      int64
+	DeferredValues       []float64
+	Windows              []windowSnapshot
+}
+
+// filter returns a snapshot containing only the windows accepted by the filter.
+func (s elemSnapshot) filter(resolution time.Duration, filterFn restoreWindowFilterFn) elemSnapshot {
+	filtered := s
+	filtered.Windows = make([]windowSnapshot, 0, len(s.Windows))
+	for _, window := range s.Windows {
+		if filterFn(window.StartAtNanos, resolution) {
+			filtered.Windows = append(filtered.Windows, window)
+		}
+	}
+	return filtered
+}
+
+func (s windowSnapshot) toProto(pb *checkpointpb.WindowSnapshot) {
+	pb.StartAtNanos = s.StartAtNanos
+	pb.SourcesSeen = s.SourcesSeen
+	s.Aggregation.toProto(&pb.Aggregation)
+}
+
+func (s *windowSnapshot) fromProto(pb checkpointpb.WindowSnapshot) {
+	s.StartAtNanos = pb.StartAtNanos
+	s.SourcesSeen = pb.SourcesSeen
+	s.Aggregation.fromProto(pb.Aggregation)
+}
+
+func (s elemSnapshot) toProto(pb *checkpointpb.ElemSnapshot) {
+	pb.LastConsumedAtNanos = s.LastConsumedAtNanos
+	pb.LastConsumedValues = s.LastConsumedValues
+	pb.LastCumulativeValues = s.LastCumulativeValues
+	pb.DeferredAtNanos = s.DeferredAtNanos
+	pb.DeferredValues = s.DeferredValues
+	pb.Windows = make([]checkpointpb.WindowSnapshot, len(s.Windows))
+	for i, window := range s.Windows {
+		window.toProto(&pb.Windows[i])
+	}
+}
+
+func (s *elemSnapshot) fromProto(pb checkpointpb.ElemSnapshot) {
+	s.LastConsumedAtNanos = pb.LastConsumedAtNanos
+	s.LastConsumedValues = pb.LastConsumedValues
+	s.LastCumulativeValues = pb.LastCumulativeValues
+	s.DeferredAtNanos = pb.DeferredAtNanos
+	s.DeferredValues = pb.DeferredValues
+	s.Windows = nil
+	if len(pb.Windows) > 0 {
+		s.Windows = make([]windowSnapshot, len(pb.Windows))
+	}
+	for i, window := range pb.Windows {
+		s.Windows[i].fromProto(window)
+	}
+}
+
+func (s aggregationSnapshot) toProto(pb *checkpointpb.AggregationSnapshot) {
+	if s.Counter != nil {
+		pb.Counter = &checkpointpb.CounterSnapshot{
+			Sum:   s.Counter.Sum,
+			SumSq: s.Counter.SumSq,
+			Count: s.Counter.Count,
+			Max:   s.Counter.Max,
+			Min:   s.Counter.Min,
+		}
+	}
+	if s.Timer != nil {
+		samples := make([]checkpointpb.SampleSnapshot, 0, len(s.Timer.Stream.Samples))
+		for _, sample := range s.Timer.Stream.Samples {
+			samples = append(samples, checkpointpb.SampleSnapshot{
+				Value:    sample.Value,
+				NumRanks: sample.NumRanks,
+				Delta:    sample.Delta,
+			})
+		}
+		pb.Timer = &checkpointpb.TimerSnapshot{
+			Count: s.Timer.Count,
+			Sum:   s.Timer.Sum,
+			SumSq: s.Timer.SumSq,
+			Stream: checkpointpb.StreamSnapshot{
+				NumValues: s.Timer.Stream.NumValues,
+				Samples:   samples,
+			},
+		}
+	}
+	if s.Gauge != nil {
+		pb.Gauge = &checkpointpb.GaugeSnapshot{
+			Last:  s.Gauge.Last,
+			Sum:   s.Gauge.Sum,
+			SumSq: s.Gauge.SumSq,
+			Count: s.Gauge.Count,
+			Max:   s.Gauge.Max,
+			Min:   s.Gauge.Min,
+		}
+	}
+}
+
+func (s *aggregationSnapshot) fromProto(pb checkpointpb.AggregationSnapshot) {
+	*s = aggregationSnapshot{}
+	if pb.Counter != nil {
+		s.Counter = &raggregation.CounterSnapshot{
+			Sum:   pb.Counter.Sum,
+			SumSq: pb.Counter.SumSq,
+			Count: pb.Counter.Count,
+			Max:   pb.Counter.Max,
+			Min:   pb.Counter.Min,
+		}
+	}
+	if pb.Timer != nil {
+		var samples []cm.SampleSnapshot
+		if len(pb.Timer.Stream.Samples) > 0 {
+			samples = make([]cm.SampleSnapshot, 0, len(pb.Timer.Stream.Samples))
+		}
+		for _, sample := range pb.Timer.Stream.Samples {
+			samples = append(samples, cm.SampleSnapshot{
+				Value:    sample.Value,
+				NumRanks: sample.NumRanks,
+				Delta:    sample.Delta,
+			})
+		}
+		s.Timer = &raggregation.TimerSnapshot{
+			Count: pb.Timer.Count,
+			Sum:   pb.Timer.Sum,
+			SumSq: pb.Timer.SumSq,
+			Stream: cm.StreamSnapshot{
+				NumValues: pb.Timer.Stream.NumValues,
+				Samples:   samples,
+			},
+		}
+	}
+	if pb.Gauge != nil {
+		s.Gauge = &raggregation.GaugeSnapshot{
+			Last:  pb.Gauge.Last,
+			Sum:   pb.Gauge.Sum,
+			SumSq: pb.Gauge.SumSq,
+			Count: pb.Gauge.Count,
+			Max:   pb.Gauge.Max,
+			Min:   pb.Gauge.Min,
+		}
+	}
+}
+
+// aggregationCheckpoint is the checkpoint of an aggregation of an entry.
+type aggregationCheckpoint struct {
+	AggregationID      maggregation.ID
+	StoragePolicy      policy.StoragePolicy
+	Pipeline           applied.Pipeline
+	NumForwardedTimes  int
+	IDPrefixSuffixType IDPrefixSuffixType
+	Elem               elemSnapshot
+}
+
+func newAggregationCheckpoint(
+	key aggregationKey,
+	snapshot elemSnapshot,
+) aggregationCheckpoint {
+	return aggregationCheckpoint{
+		AggregationID:      key.aggregationID,
+		StoragePolicy:      key.storagePolicy,
+		Pipeline:           key.pipeline,
+		NumForwardedTimes:  key.numForwardedTimes,
+		IDPrefixSuffixType: key.idPrefixSuffixType,
+		Elem:               snapshot,
+	}
+}
+
+func (c aggregationCheckpoint) aggregationKey() aggregationKey {
+	return aggregationKey{
+		aggregationID:      c.AggregationID,
+		storagePolicy:      c.StoragePolicy,
+		pipeline:           c.Pipeline,
+		numForwardedTimes:  c.NumForwardedTimes,
+		idPrefixSuffixType: c.IDPrefixSuffixType,
+	}
+}
+
+func (c aggregationCheckpoint) toProto(pb *checkpointpb.AggregationCheckpoint) error {
+	if err := c.AggregationID.ToProto(&pb.AggregationId); err != nil {
+		return err
+	}
+	if err := c.StoragePolicy.ToProto(&pb.StoragePolicy); err != nil {
+		return err
+	}
+	if err := c.Pipeline.ToProto(&pb.Pipeline); err != nil {
+		return err
+	}
+	switch c.IDPrefixSuffixType {
+	case WithPrefixWithSuffix:
+		pb.IdPrefixSuffixType = checkpointpb.IDPrefixSuffixType_WITH_PREFIX_WITH_SUFFIX
+	case NoPrefixNoSuffix:
+		pb.IdPrefixSuffixType = checkpointpb.IDPrefixSuffixType_NO_PREFIX_NO_SUFFIX
+	default:
+		return errUnknownIDPrefixSuffixType
+	}
+	pb.NumForwardedTimes = int32(c.NumForwardedTimes)
+	c.Elem.toProto(&pb.Elem)
+	return nil
+}
+
+func (c *aggregationCheckpoint) fromProto(pb checkpointpb.AggregationCheckpoint) error {
+	if err := c.AggregationID.FromProto(pb.AggregationId); err != nil {
+		return err
+	}
+	if err := c.StoragePolicy.FromProto(pb.StoragePolicy); err != nil {
+		return err
+	}
+	if err := c.Pipeline.FromProto(pb.Pipeline); err != nil {
+		return err
+	}
+	switch pb.IdPrefixSuffixType {
+	case checkpointpb.IDPrefixSuffixType_WITH_PREFIX_WITH_SUFFIX:
+		c.IDPrefixSuffixType = WithPrefixWithSuffix
+	case checkpointpb.IDPrefixSuffixType_NO_PREFIX_NO_SUFFIX:
+		c.IDPrefixSuffixType = NoPrefixNoSuffix
+	default:
+		return errUnknownIDPrefixSuffixType
+	}
+	c.NumForwardedTimes = int(pb.NumForwardedTimes)
+	c.Elem.fromProto(pb.Elem)
+	return nil
+}
+
+// entryCheckpoint is the checkpoint of an entry.
+type entryCheckpoint struct {
+	MetricCategory      metricCategory
+	MetricType          metric.Type
+	ID                  []byte
+	HasDefaultMetadatas bool
+	CutoverNanos        int64
+	Aggregations        []aggregationCheckpoint
+}
+
+// metricListID returns the id of the list an aggregation with the given key
+// for this entry belongs to.
+func (c entryCheckpoint) metricListID(key aggregationKey) (metricListID, error) {
+	resolution := key.storagePolicy.Resolution().Window
+	switch c.MetricCategory {
+	case untimedMetric:
+		return standardMetricListID{resolution: resolution}.toMetricListID(), nil
+	case forwardedMetric:
+		return forwardedMetricListID{
+			resolution:        resolution,
+			numForwardedTimes: key.numForwardedTimes,
+		}.toMetricListID(), nil
+	case timedMetric:
+		return timedMetricListID{resolution: resolution}.toMetricListID(), nil
+	default:
+		return metricListID{}, errUnknownMetricCategory
+	}
+}
+
+func (c entryCheckpoint) toProto(pb *checkpointpb.EntryCheckpoint) error {
+	switch c.MetricCategory {
+	case untimedMetric:
+		pb.MetricCategory = checkpointpb.MetricCategory_UNTIMED
+	case forwardedMetric:
+		pb.MetricCategory = checkpointpb.MetricCategory_FORWARDED
+	case timedMetric:
+		pb.MetricCategory = checkpointpb.MetricCategory_TIMED
+	default:
+		return errUnknownMetricCategory
+	}
+	if err := c.MetricType.ToProto(&pb.MetricType); err != nil {
+		return err
+	}
+	pb.Id = c.ID
+	pb.HasDefaultMetadatas = c.HasDefaultMetadatas
+	pb.CutoverNanos = c.CutoverNanos
+	pb.Aggregations = make([]checkpointpb.AggregationCheckpoint, len(c.Aggregations))
+	for i, aggCheckpoint := range c.Aggregations {
+		if err := aggCheckpoint.toProto(&pb.Aggregations[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *entryCheckpoint) fromProto(pb checkpointpb.EntryCheckpoint) error {
+	switch pb.MetricCategory {
+	case checkpointpb.MetricCategory_UNTIMED:
+		c.MetricCategory = untimedMetric
+	case checkpointpb.MetricCategory_FORWARDED:
+		c.MetricCategory = forwardedMetric
+	case checkpointpb.MetricCategory_TIMED:
+		c.MetricCategory = timedMetric
+	default:
+		return errUnknownMetricCategory
+	}
+	if err := c.MetricType.FromProto(pb.MetricType); err != nil {
+		return err
+	}
+	c.ID = pb.Id
+	c.HasDefaultMetadatas = pb.HasDefaultMetadatas
+	c.CutoverNanos = pb.CutoverNanos
+	c.Aggregations = nil
+	if len(pb.Aggregations) > 0 {
+		c.Aggregations = make([]aggregationCheckpoint, len(pb.Aggregations))
+	}
+	for i, aggCheckpoint := range pb.Aggregations {
+		if err := c.Aggregations[i].fromProto(aggCheckpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shardCheckpoint is the checkpoint of all the entries in a shard.
+type shardCheckpoint struct {
+	Shard           uint32
+	CheckpointNanos int64
+	Entries         []entryCheckpoint
+}
+
+func (c shardCheckpoint) toProto(pb *checkpointpb.ShardCheckpoint) error {
+	pb.Shard = c.Shard
+	pb.CheckpointNanos = c.CheckpointNanos
+	pb.Entries = make([]checkpointpb.EntryCheckpoint, len(c.Entries))
+	for i, entry := range c.Entries {
+		if err := entry.toProto(&pb.Entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *shardCheckpoint) fromProto(pb checkpointpb.ShardCheckpoint) error {
+	c.Shard = pb.Shard
+	c.CheckpointNanos = pb.CheckpointNanos
+	c.Entries = nil
+	if len(pb.Entries) > 0 {
+		c.Entries = make([]entryCheckpoint, len(pb.Entries))
+	}
+	for i, entry := range pb.Entries {
+		if err := c.Entries[i].fromProto(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeShardCheckpoint encodes the shard checkpoint using the current format
+// version, the payload is the checkpoint encoded as a protobuf message.
+func encodeShardCheckpoint(checkpoint shardCheckpoint) ([]byte, error) {
+	var pb checkpointpb.ShardCheckpoint
+	if err := checkpoint.toProto(&pb); err != nil {
+		return nil, err
+	}
+	payloadLen := pb.Size()
+	data := make([]byte, checkpointHeaderSize+payloadLen+checkpointChecksumSize)
+	binary.BigEndian.PutUint32(data[0:], checkpointMagic)
+	binary.BigEndian.PutUint32(data[4:], checkpointVersion)
+	binary.BigEndian.PutUint64(data[8:], uint64(payloadLen))
+	payload := data[checkpointHeaderSize : checkpointHeaderSize+payloadLen]
+	if _, err := pb.MarshalTo(payload); err != nil {
+		return nil, err
+	}
+	binary.BigEndian.PutUint32(data[checkpointHeaderSize+payloadLen:], adler32.Checksum(payload))
+	return data, nil
+}
+
+// decodeShardCheckpoint decodes the shard checkpoint, verifying its integrity.
+func decodeShardCheckpoint(data []byte) (shardCheckpoint, error) {
+	if len(data) < checkpointHeaderSize+checkpointChecksumSize {
+		return shardCheckpoint{}, errCheckpointTooShort
+	}
+	if magic := binary.BigEndian.Uint32(data[0:]); magic != checkpointMagic {
+		return shardCheckpoint{}, errCheckpointInvalidMagic
+	}
+	version := binary.BigEndian.Uint32(data[4:])
+	payloadLen := binary.BigEndian.Uint64(data[8:])
+	if payloadLen != uint64(len(data)-checkpointHeaderSize-checkpointChecksumSize) {
+		return shardCheckpoint{}, errCheckpointLengthMismatch
+	}
+	payload := data[checkpointHeaderSize : checkpointHeaderSize+int(payloadLen)]
+	checksum := binary.BigEndian.Uint32(data[checkpointHeaderSize+int(payloadLen):])
+	if adler32.Checksum(payload) != checksum {
+		return shardCheckpoint{}, errCheckpointChecksumMismatch
+	}
+
+	var checkpoint shardCheckpoint
+	switch version {
+	case 1:
+		var pb checkpointpb.ShardCheckpoint
+		if err := pb.Unmarshal(payload); err != nil {
+			return shardCheckpoint{}, err
+		}
+		if err := checkpoint.fromProto(pb); err != nil {
+			return shardCheckpoint{}, err
+		}
+	default:
+		return shardCheckpoint{}, fmt.Errorf("unsupported checkpoint version %d", version)
+	}
+	return checkpoint, nil
+}
+
+// shardCheckpointer persists shard checkpoints to and reads them from local disk.
+type shardCheckpointer struct {
+	sync.Mutex
+
+	directory        string
+	newFileMode      os.FileMode
+	newDirectoryMode os.FileMode
+}
+
+func newShardCheckpointer(opts CheckpointOptions) *shardCheckpointer {
+	return &shardCheckpointer{
+		directory:        opts.Directory(),
+		newFileMode:      opts.NewFileMode(),
+		newDirectoryMode: opts.NewDirectoryMode(),
+	}
+}
+
+// Write atomically writes the checkpoint to disk, replacing the previous
+// checkpoint of the same shard if any.
+func (c *shardCheckpointer) Write(checkpoint shardCheckpoint) error {
+	data, err := encodeShardCheckpoint(checkpoint)
+	if err != nil {
+		return err
+	}
+	c.Lock()
+	defer c.Unlock()
+
+	if err := os.MkdirAll(c.directory, c.newDirectoryMode); err != nil {
+		return err
+	}
+	filePath := c.filePath(checkpoint.Shard)
+	tmpFilePath := filePath + checkpointTmpFileSuffix
+	fd, err := os.OpenFile(tmpFilePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, c.newFileMode)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(fd)
+	if _, err := w.Write(data); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		return err
+	}
+	if err := fd.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFilePath, filePath)
+}
+
+// Read reads the checkpoint of a given shard from disk. If there is no
+// checkpoint for the shard, an error satisfying os.IsNotExist is returned.
+func (c *shardCheckpointer) Read(shard uint32) (shardCheckpoint, error) {
+	data, err := ioutil.ReadFile(c.filePath(shard))
+	if err != nil {
+		return shardCheckpoint{}, err
+	}
+	checkpoint, err := decodeShardCheckpoint(data)
+	if err != nil {
+		return shardCheckpoint{}, err
+	}
+	if checkpoint.Shard != shard {
+		return shardCheckpoint{}, errCheckpointShardMismatch
+	}
+	return checkpoint, nil
+}
+
+func (c *shardCheckpointer) filePath(shard uint32) string {
+	fileName := fmt.Sprintf("%s%d%s", checkpointFilePrefix, shard, checkpointFileSuffix)
+	return filepath.Join(c.directory, fileName)
+}
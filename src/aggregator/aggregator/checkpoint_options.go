@@ -0,0 +1,160 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+const (
+	defaultCheckpointEvery    = 10 * time.Second
+	defaultMaxCheckpointAge   = 10 * time.Minute
+	defaultCheckpointFileMode = os.FileMode(0666)
+	defaultCheckpointDirMode  = os.ModeDir | os.FileMode(0755)
+)
+
+var (
+	errNoCheckpointDirectory       = errors.New("no checkpoint directory set")
+	errNonPositiveCheckpointEvery  = errors.New("checkpoint interval must be positive")
+	errNonPositiveMaxCheckpointAge = errors.New("max checkpoint age must be positive")
+)
+
+// CheckpointOptions provide a set of options for checkpointing the aggregator
+// in-memory states to local disk.
+type CheckpointOptions interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetDirectory sets the directory checkpoint files are written to.
+	SetDirectory(value string) CheckpointOptions
+
+	// Directory returns the directory checkpoint files are written to.
+	Directory() string
+
+	// SetCheckpointEvery sets how frequently the shard states are checkpointed.
+	SetCheckpointEvery(value time.Duration) CheckpointOptions
+
+	// CheckpointEvery returns how frequently the shard states are checkpointed.
+	CheckpointEvery() time.Duration
+
+	// SetMaxCheckpointAge sets the maximum age of a checkpoint that is eligible
+	// for restoring, checkpoints older than this are discarded during restore.
+	SetMaxCheckpointAge(value time.Duration) CheckpointOptions
+
+	// MaxCheckpointAge returns the maximum age of a checkpoint that is eligible
+	// for restoring, checkpoints older than this are discarded during restore.
+	MaxCheckpointAge() time.Duration
+
+	// SetNewFileMode sets the file mode for new checkpoint files.
+	SetNewFileMode(value os.FileMode) CheckpointOptions
+
+	// NewFileMode returns the file mode for new checkpoint files.
+	NewFileMode() os.FileMode
+
+	// SetNewDirectoryMode sets the file mode for new checkpoint directories.
+	SetNewDirectoryMode(value os.FileMode) CheckpointOptions
+
+	// NewDirectoryMode returns the file mode for new checkpoint directories.
+	NewDirectoryMode() os.FileMode
+}
+
+type checkpointOptions struct {
+	directory        string
+	checkpointEvery  time.Duration
+	maxCheckpointAge time.Duration
+	newFileMode      os.FileMode
+	newDirectoryMode os.FileMode
+}
+
+// NewCheckpointOptions create a new set of checkpoint options.
+func NewCheckpointOptions() CheckpointOptions {
+	return &checkpointOptions{
+		checkpointEvery:  defaultCheckpointEvery,
+		maxCheckpointAge: defaultMaxCheckpointAge,
+		newFileMode:      defaultCheckpointFileMode,
+		newDirectoryMode: defaultCheckpointDirMode,
+	}
+}
+
+func (o *checkpointOptions) Validate() error {
+	if o.directory == "" {
+		return errNoCheckpointDirectory
+	}
+	if o.checkpointEvery <= 0 {
+		return errNonPositiveCheckpointEvery
+	}
+	if o.maxCheckpointAge <= 0 {
+		return errNonPositiveMaxCheckpointAge
+	}
+	return nil
+}
+
+func (o *checkpointOptions) SetDirectory(value string) CheckpointOptions {
+	opts := *o
+	opts.directory = value
+	return &opts
+}
+
+func (o *checkpointOptions) Directory() string {
+	return o.directory
+}
+
+func (o *checkpointOptions) SetCheckpointEvery(value time.Duration) CheckpointOptions {
+	opts := *o
+	opts.checkpointEvery = value
+	return &opts
+}
+
+func (o *checkpointOptions) CheckpointEvery() time.Duration {
+	return o.checkpointEvery
+}
+
+func (o *checkpointOptions) SetMaxCheckpointAge(value time.Duration) CheckpointOptions {
+	opts := *o
+	opts.maxCheckpointAge = value
+	return &opts
+}
+
+func (o *checkpointOptions) MaxCheckpointAge() time.Duration {
+	return o.maxCheckpointAge
+}
+
+func (o *checkpointOptions) SetNewFileMode(value os.FileMode) CheckpointOptions {
+	opts := *o
+	opts.newFileMode = value
+	return &opts
+}
+
+func (o *checkpointOptions) NewFileMode() os.FileMode {
+	return o.newFileMode
+}
+
+func (o *checkpointOptions) SetNewDirectoryMode(value os.FileMode) CheckpointOptions {
+	opts := *o
+	opts.newDirectoryMode = value
+	return &opts
+}
+
+func (o *checkpointOptions) NewDirectoryMode() os.FileMode {
+	return o.newDirectoryMode
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/aggregation"
+	"github.com/m3db/m3/src/aggregator/aggregation/quantile/cm"
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/metrics/metric"
+	"github.com/m3db/m3/src/metrics/policy"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testShardCheckpoint = shardCheckpoint{
+		Shard:           testShard,
+		CheckpointNanos: 12345,
+		Entries: []entryCheckpoint{
+			{
+				MetricCategory:      untimedMetric,
+				MetricType:          metric.CounterType,
+				ID:                  []byte("foo"),
+				HasDefaultMetadatas: true,
+				CutoverNanos:        1234,
+				Aggregations: []aggregationCheckpoint{
+					{
+						StoragePolicy:     policy.MustParseStoragePolicy("10s:2d"),
+						NumForwardedTimes: 0,
+						Elem: elemSnapshot{
+							LastConsumedAtNanos: 1000,
+							Windows: []windowSnapshot{
+								{
+									StartAtNanos: 10000,
+									Aggregation: aggregationSnapshot{
+										Counter: &aggregation.CounterSnapshot{Sum: 123, Count: 2},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				MetricCategory: timedMetric,
+				MetricType:     metric.TimerType,
+				ID:             []byte("bar"),
+				Aggregations: []aggregationCheckpoint{
+					{
+						StoragePolicy:      policy.MustParseStoragePolicy("1m:40d"),
+						IDPrefixSuffixType: NoPrefixNoSuffix,
+						Elem: elemSnapshot{
+							Windows: []windowSnapshot{
+								{
+									StartAtNanos: 60000,
+									SourcesSeen:  []byte{1, 2},
+									Aggregation: aggregationSnapshot{
+										Timer: &aggregation.TimerSnapshot{
+											Count: 2,
+											Sum:   3.5,
+											SumSq: 6.25,
+											Stream: cm.StreamSnapshot{
+												NumValues: 2,
+												Samples: []cm.SampleSnapshot{
+													{Value: 1.5, NumRanks: 1},
+													{Value: 2.0, NumRanks: 1, Delta: 1},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				MetricCategory: forwardedMetric,
+				MetricType:     metric.GaugeType,
+				ID:             []byte("baz"),
+				Aggregations: []aggregationCheckpoint{
+					{
+						StoragePolicy:     policy.MustParseStoragePolicy("10s:2d"),
+						NumForwardedTimes: 1,
+						Elem: elemSnapshot{
+							LastConsumedValues: []float64{4.5},
+							Windows: []windowSnapshot{
+								{
+									StartAtNanos: 20000,
+									Aggregation: aggregationSnapshot{
+										Gauge: &aggregation.GaugeSnapshot{Last: 4.5, Sum: 4.5, Count: 1, Max: 4.5, Min: 4.5},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+)
+
+func TestShardCheckpointEncodeDecodeRoundtrip(t *testing.T) {
+	data, err := encodeShardCheckpoint(testShardCheckpoint)
+	require.NoError(t, err)
+	require.Equal(t, checkpointMagic, binary.BigEndian.Uint32(data[0:]))
+	require.Equal(t, checkpointVersion, binary.BigEndian.Uint32(data[4:]))
+
+	decoded, err := decodeShardCheckpoint(data)
+	require.NoError(t, err)
+	require.Equal(t, testShardCheckpoint, decoded)
+}
+
+func TestShardCheckpointDecodeErrors(t *testing.T) {
+	data, err := encodeShardCheckpoint(testShardCheckpoint)
+	require.NoError(t, err)
+
+	_, err = decodeShardCheckpoint(data[:checkpointHeaderSize])
+	require.Equal(t, errCheckpointTooShort, err)
+
+	invalidMagic := append([]byte(nil), data...)
+	binary.BigEndian.PutUint32(invalidMagic[0:], 0)
+	_, err = decodeShardCheckpoint(invalidMagic)
+	require.Equal(t, errCheckpointInvalidMagic, err)
+
+	_, err = decodeShardCheckpoint(data[:len(data)-1])
+	require.Equal(t, errCheckpointLengthMismatch, err)
+
+	corrupted := append([]byte(nil), data...)
+	corrupted[checkpointHeaderSize]++
+	_, err = decodeShardCheckpoint(corrupted)
+	require.Equal(t, errCheckpointChecksumMismatch, err)
+
+	unsupportedVersion := append([]byte(nil), data...)
+	binary.BigEndian.PutUint32(unsupportedVersion[4:], checkpointVersion+1)
+	_, err = decodeShardCheckpoint(unsupportedVersion)
+	require.Error(t, err)
+}
+
+func TestShardCheckpointerWriteRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	checkpointer := newShardCheckpointer(NewCheckpointOptions().SetDirectory(dir))
+	_, err = checkpointer.Read(testShard)
+	require.True(t, os.IsNotExist(err))
+
+	require.NoError(t, checkpointer.Write(testShardCheckpoint))
+	checkpoint, err := checkpointer.Read(testShard)
+	require.NoError(t, err)
+	require.Equal(t, testShardCheckpoint, checkpoint)
+
+	// Writing again replaces the previous checkpoint.
+	updated := testShardCheckpoint
+	updated.CheckpointNanos = 23456
+	require.NoError(t, checkpointer.Write(updated))
+	checkpoint, err = checkpointer.Read(testShard)
+	require.NoError(t, err)
+	require.Equal(t, updated, checkpoint)
+
+	// Reading a checkpoint with a different shard returns an error.
+	mismatched := testShardCheckpoint
+	mismatched.Shard = testShard + 1
+	data, err := encodeShardCheckpoint(mismatched)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(checkpointer.filePath(testShard), data, 0644))
+	_, err = checkpointer.Read(testShard)
+	require.Equal(t, errCheckpointShardMismatch, err)
+}
+
+func TestAggregatorShardCheckpointRestore(t *testing.T) {
+	opts := NewOptions().SetEntryCheckInterval(0)
+	shard := newAggregatorShard(testShard, opts)
+	defer shard.Close()
+	shard.SetWriteableRange(timeRange{cutoverNanos: 0, cutoffNanos: math.MaxInt64})
+	require.NoError(t, shard.AddUntimed(testUntimedMetric, testStagedMetadatas))
+
+	checkpoint, err := shard.Checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, testShard, checkpoint.Shard)
+	require.Equal(t, 1, len(checkpoint.Entries))
+	require.Equal(t, []byte(testUntimedMetric.ID), checkpoint.Entries[0].ID)
+	require.True(t, len(checkpoint.Entries[0].Aggregations) > 0)
+
+	// Restoring into a shard that owns all the windows restores everything.
+	restored := newAggregatorShard(testShard, opts)
+	defer restored.Close()
+	restored.SetWriteableRange(timeRange{cutoverNanos: 0, cutoffNanos: math.MaxInt64})
+	numRestored, err := restored.Restore(checkpoint)
+	require.NoError(t, err)
+	require.Equal(t, 1, numRestored)
+
+	restoredCheckpoint, err := restored.Checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, checkpoint.Entries, restoredCheckpoint.Entries)
+
+	// Restoring into a shard that has been cut off drops all the windows.
+	cutoff := newAggregatorShard(testShard, opts)
+	defer cutoff.Close()
+	cutoff.SetWriteableRange(timeRange{cutoverNanos: 0, cutoffNanos: 0})
+	numRestored, err = cutoff.Restore(checkpoint)
+	require.NoError(t, err)
+	require.Equal(t, 1, numRestored)
+
+	cutoffCheckpoint, err := cutoff.Checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(cutoffCheckpoint.Entries))
+	for _, agg := range cutoffCheckpoint.Entries[0].Aggregations {
+		require.Equal(t, 0, len(agg.Elem.Windows))
+	}
+
+	// Restoring a checkpoint of a different shard is an error.
+	other := newAggregatorShard(testShard+1, opts)
+	defer other.Close()
+	_, err = other.Restore(checkpoint)
+	require.Equal(t, errCheckpointShardMismatch, err)
+}
+
+func TestAggregatorRestoresAddedShardsFromCheckpoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "checkpoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Unix(0, 12345)
+	source := newAggregatorShard(testShard, NewOptions().SetEntryCheckInterval(0))
+	defer source.Close()
+	source.SetWriteableRange(timeRange{cutoverNanos: 0, cutoffNanos: math.MaxInt64})
+	require.NoError(t, source.AddUntimed(testUntimedMetric, testStagedMetadatas))
+	checkpoint, err := source.Checkpoint()
+	require.NoError(t, err)
+	checkpoint.CheckpointNanos = now.UnixNano()
+
+	opts := testOptions(ctrl).
+		SetEntryCheckInterval(0).
+		SetCheckpointOptions(NewCheckpointOptions().SetDirectory(dir))
+	agg := NewAggregator(opts).(*aggregator)
+	agg.nowFn = func() time.Time { return now }
+	require.NoError(t, agg.checkpointer.Write(checkpoint))
+
+	// Shards cannot be added until their checkpoints have been read.
+	shardSet := shard.NewShards([]shard.Shard{
+		shard.NewShard(testShard).SetCutoverNanos(0).SetCutoffNanos(math.MaxInt64),
+	})
+	require.False(t, agg.hasCheckpointsWithLock(shardSet, nil))
+	checkpoints := shardCheckpoints{testShard: agg.readCheckpoint(testShard, now.UnixNano())}
+	require.True(t, agg.hasCheckpointsWithLock(shardSet, checkpoints))
+
+	// Adding a shard after the aggregator is opened restores it from its checkpoint.
+	agg.updateShardsWithLock(nil, nil, shardSet, checkpoints)
+	added := agg.shards[testShard]
+	defer added.Close()
+	restored, err := added.Checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, checkpoint.Entries, restored.Entries)
+
+	// Shards that are already owned are not restored again.
+	other := testShardCheckpoint
+	other.CheckpointNanos = now.UnixNano()
+	require.NoError(t, agg.checkpointer.Write(other))
+	require.True(t, agg.hasCheckpointsWithLock(shardSet, nil))
+	agg.updateShardsWithLock(nil, nil, shardSet, shardCheckpoints{testShard: &other})
+	require.True(t, added == agg.shards[testShard])
+	restored, err = added.Checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, checkpoint.Entries, restored.Entries)
+
+	// Stale checkpoints are not restored from.
+	require.Nil(t, agg.readCheckpoint(testShard, now.Add(agg.maxCheckpointAge+time.Second).UnixNano()))
+}
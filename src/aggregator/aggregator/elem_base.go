@@ -52,6 +52,10 @@ const (
 	// compute first-order derivatives. This applies to the most common usecases
 	// without imposing significant bookkeeping overhead.
 	maxSupportedTransformationDerivativeOrder = 1
+
+	// Maximum number of cumulative transformations that is supported. Each
+	// cumulative transformation requires keeping its previous result per value.
+	maxSupportedCumulativeTransformations = 1
 )
 
 var (
@@ -139,6 +143,17 @@ type metricElem interface {
 		onForwardedFlushedFn onForwardingElemFlushedFn,
 	) bool
 
+	// Snapshot returns a snapshot of the aggregation windows that have not
+	// been consumed yet.
+	Snapshot() elemSnapshot
+
+	// Restore restores the aggregation windows from a snapshot.
+	Restore(snapshot elemSnapshot) error
+
+	// Inspect returns the current values of the aggregation windows that have
+	// not been consumed.
+	Inspect() ElemInspectResult
+
 	// MarkAsTombstoned marks an element as tombstoned, which means this element
 	// will be deleted once its aggregated values have been flushed.
 	MarkAsTombstoned()
@@ -378,6 +393,9 @@ type parsedPipeline struct {
 	// Whether the source pipeline contains derivative transformations at its head.
 	HasDerivativeTransform bool
 
+	// Whether the source pipeline contains cumulative transformations at its head.
+	HasCumulativeTransform bool
+
 	// Sub-pipline containing only transformation operations from the head
 	// of the source pipeline this parsed pipeline was derived from.
 	Transformations applied.Pipeline
@@ -404,7 +422,9 @@ type parsedPipeline struct {
 // * Pipeline that starts with a transformation operation and contains at least one
 //   rollup operation. Additionally, the transformation derivative order computed from
 //   the list of transformations must be no more than the maximum transformation derivative
-//   order that is supported.
+//   order that is supported, the number of cumulative transformations must be no more than
+//   the maximum number of cumulative transformations that is supported, and a transformation
+//   producing multiple outputs must be the last transformation before the rollup operation.
 func newParsedPipeline(pipeline applied.Pipeline) (parsedPipeline, error) {
 	if pipeline.IsEmpty() {
 		return parsedPipeline{}, nil
@@ -412,6 +432,7 @@ func newParsedPipeline(pipeline applied.Pipeline) (parsedPipeline, error) {
 	var (
 		firstRollupOpIdx              = -1
 		transformationDerivativeOrder int
+		numCumulativeTransformations  int
 		numSteps                      = pipeline.Len()
 	)
 	for i := 0; i < numSteps; i++ {
@@ -431,6 +452,15 @@ func newParsedPipeline(pipeline applied.Pipeline) (parsedPipeline, error) {
 			if transformOp.Type.IsBinaryTransform() {
 				transformationDerivativeOrder++
 			}
+			if transformOp.Type.IsCumulativeTransform() {
+				numCumulativeTransformations++
+			}
+			// The extra outputs of a multi-output transformation are not processed by
+			// subsequent transformations, so it must be the last transformation.
+			if transformOp.Type.IsUnaryMultiOutputTransform() &&
+				(i+1 >= numSteps || pipeline.At(i+1).Type != mpipeline.RollupOpType) {
+				return parsedPipeline{}, fmt.Errorf("pipeline %v step %d transformation %v must be followed by a rollup operation", pipeline, i, transformOp.Type)
+			}
 		}
 	}
 	if firstRollupOpIdx == -1 {
@@ -445,11 +475,28 @@ func newParsedPipeline(pipeline applied.Pipeline) (parsedPipeline, error) {
 	if transformationDerivativeOrder > maxSupportedTransformationDerivativeOrder {
 		return parsedPipeline{}, fmt.Errorf("pipeline %v transformation derivative order is %d higher than supported %d", pipeline, transformationDerivativeOrder, maxSupportedTransformationDerivativeOrder)
 	}
+	if numCumulativeTransformations > maxSupportedCumulativeTransformations {
+		return parsedPipeline{}, fmt.Errorf("pipeline %v number of cumulative transformations is %d higher than supported %d", pipeline, numCumulativeTransformations, maxSupportedCumulativeTransformations)
+	}
 	return parsedPipeline{
 		HasDerivativeTransform: transformationDerivativeOrder > 0,
+		HasCumulativeTransform: numCumulativeTransformations > 0,
 		Transformations:        pipeline.SubPipeline(0, firstRollupOpIdx),
 		HasRollup:              true,
 		Rollup:                 pipeline.At(firstRollupOpIdx).Rollup,
 		Remainder:              pipeline.SubPipeline(firstRollupOpIdx+1, numSteps),
 	}, nil
 }
+
+// resetLastValues resizes the given slice to hold the given number of values,
+// and resets all values to NaN.
+func resetLastValues(values []float64, n int) []float64 {
+	if cap(values) < n {
+		values = make([]float64, n)
+	}
+	values = values[:n]
+	for i := 0; i < len(values); i++ {
+		values[i] = nan
+	}
+	return values
+}
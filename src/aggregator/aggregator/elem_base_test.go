@@ -568,3 +568,92 @@ func TestParsePipelineTransformationDerivativeOrderTooHigh(t *testing.T) {
 	require.Error(t, err)
 	require.True(t, strings.Contains(err.Error(), "transformation derivative order is 2 higher than supported 1"))
 }
+
+func TestParsePipelineWithCumulativeTransformation(t *testing.T) {
+	p := applied.NewPipeline([]applied.OpUnion{
+		{
+			Type:           pipeline.TransformationOpType,
+			Transformation: pipeline.TransformationOp{Type: transformation.Increase},
+		},
+		{
+			Type:           pipeline.TransformationOpType,
+			Transformation: pipeline.TransformationOp{Type: transformation.Add},
+		},
+		{
+			Type: pipeline.RollupOpType,
+			Rollup: applied.RollupOp{
+				ID:            []byte("foo"),
+				AggregationID: maggregation.MustCompressTypes(maggregation.Sum),
+			},
+		},
+	})
+	expected := parsedPipeline{
+		HasDerivativeTransform: true,
+		HasCumulativeTransform: true,
+		Transformations: applied.NewPipeline([]applied.OpUnion{
+			{
+				Type:           pipeline.TransformationOpType,
+				Transformation: pipeline.TransformationOp{Type: transformation.Increase},
+			},
+			{
+				Type:           pipeline.TransformationOpType,
+				Transformation: pipeline.TransformationOp{Type: transformation.Add},
+			},
+		}),
+		HasRollup: true,
+		Rollup: applied.RollupOp{
+			ID:            []byte("foo"),
+			AggregationID: maggregation.MustCompressTypes(maggregation.Sum),
+		},
+		Remainder: applied.NewPipeline([]applied.OpUnion{}),
+	}
+	parsed, err := newParsedPipeline(p)
+	require.NoError(t, err)
+	require.Equal(t, expected, parsed)
+}
+
+func TestParsePipelineTooManyCumulativeTransformations(t *testing.T) {
+	p := applied.NewPipeline([]applied.OpUnion{
+		{
+			Type:           pipeline.TransformationOpType,
+			Transformation: pipeline.TransformationOp{Type: transformation.Add},
+		},
+		{
+			Type:           pipeline.TransformationOpType,
+			Transformation: pipeline.TransformationOp{Type: transformation.Add},
+		},
+		{
+			Type: pipeline.RollupOpType,
+			Rollup: applied.RollupOp{
+				ID:            []byte("foo"),
+				AggregationID: maggregation.MustCompressTypes(maggregation.Sum),
+			},
+		},
+	})
+	_, err := newParsedPipeline(p)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "number of cumulative transformations is 2 higher than supported 1"))
+}
+
+func TestParsePipelineMultiOutputTransformationNotFollowedByRollup(t *testing.T) {
+	p := applied.NewPipeline([]applied.OpUnion{
+		{
+			Type:           pipeline.TransformationOpType,
+			Transformation: pipeline.TransformationOp{Type: transformation.Reset},
+		},
+		{
+			Type:           pipeline.TransformationOpType,
+			Transformation: pipeline.TransformationOp{Type: transformation.Absolute},
+		},
+		{
+			Type: pipeline.RollupOpType,
+			Rollup: applied.RollupOp{
+				ID:            []byte("foo"),
+				AggregationID: maggregation.MustCompressTypes(maggregation.Sum),
+			},
+		},
+	})
+	_, err := newParsedPipeline(p)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "transformation Reset must be followed by a rollup operation"))
+}
@@ -1625,6 +1625,185 @@ func TestGaugeElemConsumeCustomAggregationCustomPipeline(t *testing.T) {
 	require.Equal(t, 0, len(e.values))
 }
 
+func TestGaugeElemConsumeCumulativeTransformation(t *testing.T) {
+	alignedstartAtNanos := []int64{
+		time.Unix(210, 0).UnixNano(),
+		time.Unix(220, 0).UnixNano(),
+		time.Unix(230, 0).UnixNano(),
+		time.Unix(240, 0).UnixNano(),
+	}
+	gaugeVals := []float64{10.0, 20.0, 30.0}
+	aggregationTypes := maggregation.Types{maggregation.Last}
+	isEarlierThanFn := isStandardMetricEarlierThan
+	timestampNanosFn := standardMetricTimestampNanos
+	opts := NewOptions().SetDiscardNaNAggregatedValues(false)
+	p := applied.NewPipeline([]applied.OpUnion{
+		{
+			Type:           pipeline.TransformationOpType,
+			Transformation: pipeline.TransformationOp{Type: transformation.Add},
+		},
+		{
+			Type: pipeline.RollupOpType,
+			Rollup: applied.RollupOp{
+				ID:            []byte("foo.bar"),
+				AggregationID: maggregation.MustCompressTypes(maggregation.Sum),
+			},
+		},
+	})
+	e := testGaugeElem(alignedstartAtNanos[:3], gaugeVals, aggregationTypes, p, opts)
+	require.Equal(t, 1, len(e.lastCumulativeValues))
+	require.True(t, math.IsNaN(e.lastCumulativeValues[0]))
+
+	aggKey := aggregationKey{
+		aggregationID:     maggregation.MustCompressTypes(maggregation.Sum),
+		storagePolicy:     testStoragePolicy,
+		pipeline:          applied.NewPipeline([]applied.OpUnion{}),
+		numForwardedTimes: testNumForwardedTimes + 1,
+	}
+
+	// Consume one value.
+	expectedForwardedRes := []testForwardedMetricWithMetadata{
+		{
+			aggregationKey: aggKey,
+			timeNanos:      time.Unix(220, 0).UnixNano(),
+			value:          10.0,
+		},
+	}
+	localFn, localRes := testFlushLocalMetricFn()
+	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	onForwardedFlushedFn, _ := testOnForwardedFlushedFn()
+	require.False(t, e.Consume(alignedstartAtNanos[1], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	verifyForwardedMetrics(t, expectedForwardedRes, *forwardRes)
+	require.Equal(t, 0, len(*localRes))
+	require.Equal(t, []float64{10.0}, e.lastCumulativeValues)
+
+	// Consume all values and verify the results accumulate across flushes.
+	expectedForwardedRes = []testForwardedMetricWithMetadata{
+		{
+			aggregationKey: aggKey,
+			timeNanos:      time.Unix(230, 0).UnixNano(),
+			value:          30.0,
+		},
+		{
+			aggregationKey: aggKey,
+			timeNanos:      time.Unix(240, 0).UnixNano(),
+			value:          60.0,
+		},
+	}
+	localFn, localRes = testFlushLocalMetricFn()
+	forwardFn, forwardRes = testFlushForwardedMetricFn()
+	onForwardedFlushedFn, _ = testOnForwardedFlushedFn()
+	require.False(t, e.Consume(alignedstartAtNanos[3], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	verifyForwardedMetrics(t, expectedForwardedRes, *forwardRes)
+	require.Equal(t, 0, len(*localRes))
+	require.Equal(t, 0, len(e.values))
+	require.Equal(t, []float64{60.0}, e.lastCumulativeValues)
+}
+
+func TestGaugeElemConsumeMultiOutputTransformation(t *testing.T) {
+	alignedstartAtNanos := []int64{
+		time.Unix(210, 0).UnixNano(),
+		time.Unix(220, 0).UnixNano(),
+		time.Unix(230, 0).UnixNano(),
+	}
+	gaugeVals := []float64{10.0, 20.0}
+	aggregationTypes := maggregation.Types{maggregation.Last}
+	isEarlierThanFn := isStandardMetricEarlierThan
+	timestampNanosFn := standardMetricTimestampNanos
+	opts := NewOptions().SetDiscardNaNAggregatedValues(false)
+	p := applied.NewPipeline([]applied.OpUnion{
+		{
+			Type:           pipeline.TransformationOpType,
+			Transformation: pipeline.TransformationOp{Type: transformation.Reset},
+		},
+		{
+			Type: pipeline.RollupOpType,
+			Rollup: applied.RollupOp{
+				ID:            []byte("foo.bar"),
+				AggregationID: maggregation.MustCompressTypes(maggregation.Sum),
+			},
+		},
+	})
+	e := testGaugeElem(alignedstartAtNanos[:2], gaugeVals, aggregationTypes, p, opts)
+
+	aggKey := aggregationKey{
+		aggregationID:     maggregation.MustCompressTypes(maggregation.Sum),
+		storagePolicy:     testStoragePolicy,
+		pipeline:          applied.NewPipeline([]applied.OpUnion{}),
+		numForwardedTimes: testNumForwardedTimes + 1,
+	}
+
+	// Consume all values and verify the zero value following each value is
+	// deferred to the next window and forwarded alongside its value.
+	expectedForwardedRes := []testForwardedMetricWithMetadata{
+		{
+			aggregationKey: aggKey,
+			timeNanos:      time.Unix(220, 0).UnixNano(),
+			value:          10.0,
+		},
+		{
+			aggregationKey: aggKey,
+			timeNanos:      time.Unix(230, 0).UnixNano(),
+			value:          0.0,
+		},
+		{
+			aggregationKey: aggKey,
+			timeNanos:      time.Unix(230, 0).UnixNano(),
+			value:          20.0,
+		},
+	}
+	localFn, localRes := testFlushLocalMetricFn()
+	forwardFn, forwardRes := testFlushForwardedMetricFn()
+	onForwardedFlushedFn, _ := testOnForwardedFlushedFn()
+	require.False(t, e.Consume(alignedstartAtNanos[2], isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	verifyForwardedMetrics(t, expectedForwardedRes, *forwardRes)
+	require.Equal(t, 0, len(*localRes))
+	require.Equal(t, 0, len(e.values))
+	require.Equal(t, alignedstartAtNanos[2], e.deferredAtNanos)
+	require.Equal(t, []float64{0.0}, e.deferredValues)
+	forwarded := *forwardRes
+
+	// Consume again and verify the last zero value is forwarded on its own
+	// once its window is ready for consumption.
+	expectedForwardedRes = []testForwardedMetricWithMetadata{
+		{
+			aggregationKey: aggKey,
+			timeNanos:      time.Unix(240, 0).UnixNano(),
+			value:          0.0,
+		},
+	}
+	localFn, localRes = testFlushLocalMetricFn()
+	forwardFn, forwardRes = testFlushForwardedMetricFn()
+	onForwardedFlushedFn, _ = testOnForwardedFlushedFn()
+	require.False(t, e.Consume(time.Unix(240, 0).UnixNano(), isEarlierThanFn, timestampNanosFn, localFn, forwardFn, onForwardedFlushedFn))
+	verifyForwardedMetrics(t, expectedForwardedRes, *forwardRes)
+	require.Equal(t, 0, len(*localRes))
+	require.Equal(t, 0, len(e.deferredValues))
+	forwarded = append(forwarded, *forwardRes...)
+
+	// Verify the destination element accepts all the forwarded values, which
+	// are batched by their timestamps from the same source as the forwarding
+	// writer does.
+	dest := MustNewGaugeElem(testGaugeID, testStoragePolicy, maggregation.Types{maggregation.Count}, applied.DefaultPipeline, testNumForwardedTimes+1, WithPrefixWithSuffix, opts)
+	var (
+		batchTimes  []int64
+		batchValues = make(map[int64][]float64)
+	)
+	for _, res := range forwarded {
+		if _, exists := batchValues[res.timeNanos]; !exists {
+			batchTimes = append(batchTimes, res.timeNanos)
+		}
+		batchValues[res.timeNanos] = append(batchValues[res.timeNanos], res.value)
+	}
+	for _, timeNanos := range batchTimes {
+		require.NoError(t, dest.AddUnique(time.Unix(0, timeNanos), batchValues[timeNanos], testShard))
+	}
+	require.Equal(t, 3, len(dest.values))
+	for i, expected := range []float64{1, 2, 1} {
+		require.Equal(t, expected, dest.values[i].lockedAgg.aggregation.ValueOf(maggregation.Count))
+	}
+}
+
 func TestGaugeElemClose(t *testing.T) {
 	e := testGaugeElem(testAlignedStarts[:len(testAlignedStarts)-1], testGaugeVals, maggregation.DefaultTypes, applied.DefaultPipeline, NewOptions())
 	require.False(t, e.closed)
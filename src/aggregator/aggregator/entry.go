@@ -31,6 +31,7 @@ import (
 	"github.com/m3db/m3/src/aggregator/rate"
 	"github.com/m3db/m3/src/aggregator/runtime"
 	"github.com/m3db/m3/src/metrics/aggregation"
+	"github.com/m3db/m3/src/metrics/filters"
 	"github.com/m3db/m3/src/metrics/metadata"
 	"github.com/m3db/m3/src/metrics/metric"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
@@ -306,6 +307,111 @@ func (e *Entry) TryExpire(now time.Time) bool {
 	return true
 }
 
+// Checkpoint returns a checkpoint of the aggregations in the entry, or false
+// if the entry is closed or has no aggregations.
+func (e *Entry) Checkpoint(key entryKey) (entryCheckpoint, bool, error) {
+	e.RLock()
+	defer e.RUnlock()
+
+	if e.closed || len(e.aggregations) == 0 {
+		return entryCheckpoint{}, false, nil
+	}
+	elemID := e.aggregations[0].elem.Value.(metricElem).ID()
+	checkpoint := entryCheckpoint{
+		MetricCategory:      key.metricCategory,
+		MetricType:          key.metricType,
+		ID:                  append([]byte(nil), elemID...),
+		HasDefaultMetadatas: e.hasDefaultMetadatas,
+		CutoverNanos:        e.cutoverNanos,
+		Aggregations:        make([]aggregationCheckpoint, 0, len(e.aggregations)),
+	}
+	for _, val := range e.aggregations {
+		snapshot := val.elem.Value.(metricElem).Snapshot()
+		checkpoint.Aggregations = append(checkpoint.Aggregations, newAggregationCheckpoint(val.key, snapshot))
+	}
+	return checkpoint, true, nil
+}
+
+// Restore restores the aggregations in the entry from a checkpoint. Only the
+// aggregation windows accepted by the filter function are restored.
+func (e *Entry) Restore(checkpoint entryCheckpoint, filterFn restoreWindowFilterFn) error {
+	e.Lock()
+	defer e.Unlock()
+
+	if e.closed {
+		return errEntryClosed
+	}
+	var (
+		elemID          = e.maybeCopyIDWithLock(checkpoint.ID)
+		newAggregations = e.aggregations
+	)
+	for _, aggCheckpoint := range checkpoint.Aggregations {
+		key := aggCheckpoint.aggregationKey()
+		listID, err := checkpoint.metricListID(key)
+		if err != nil {
+			return err
+		}
+		newAggregations, err = e.addNewAggregationKeyWithLock(checkpoint.MetricType, elemID, key, listID, newAggregations)
+		if err != nil {
+			return err
+		}
+		idx := newAggregations.index(key)
+		resolution := key.storagePolicy.Resolution().Window
+		snapshot := aggCheckpoint.Elem.filter(resolution, filterFn)
+		if err := newAggregations[idx].elem.Value.(metricElem).Restore(snapshot); err != nil {
+			return err
+		}
+	}
+	e.aggregations = newAggregations
+	if checkpoint.MetricCategory == untimedMetric {
+		e.hasDefaultMetadatas = checkpoint.HasDefaultMetadatas
+		e.cutoverNanos = checkpoint.CutoverNanos
+	}
+	return nil
+}
+
+// MatchesFilter returns true if the id of the metric aggregated by the entry
+// matches the filter, or false if the entry is closed or has no aggregations.
+func (e *Entry) MatchesFilter(filter filters.Filter) bool {
+	e.RLock()
+	defer e.RUnlock()
+
+	if e.closed || len(e.aggregations) == 0 {
+		return false
+	}
+	return filter.Matches(e.aggregations[0].elem.Value.(metricElem).ID())
+}
+
+// Inspect returns the aggregation states of the entry, or false if the entry
+// is closed or has no aggregations.
+func (e *Entry) Inspect(key entryKey) (EntryInspectResult, bool) {
+	e.RLock()
+	defer e.RUnlock()
+
+	if e.closed || len(e.aggregations) == 0 {
+		return EntryInspectResult{}, false
+	}
+	res := EntryInspectResult{
+		ID:                  string(e.aggregations[0].elem.Value.(metricElem).ID()),
+		MetricCategory:      key.metricCategory.String(),
+		MetricType:          key.metricType.String(),
+		HasDefaultMetadatas: e.hasDefaultMetadatas,
+		CutoverNanos:        e.cutoverNanos,
+		LastAccessNanos:     atomic.LoadInt64(&e.lastAccessNanos),
+		NumWriters:          atomic.LoadInt32(&e.numWriters),
+		Elems:               make([]ElemInspectResult, 0, len(e.aggregations)),
+	}
+	if e.rateLimiter != nil {
+		res.RateLimit.LimitPerSecond = e.rateLimiter.Limit()
+	}
+	for _, val := range e.aggregations {
+		elemRes := val.elem.Value.(metricElem).Inspect()
+		elemRes.Pipeline = val.key.pipeline.String()
+		res.Elems = append(res.Elems, elemRes)
+	}
+	return res, true
+}
+
 func (e *Entry) writeBatchTimerWithMetadatas(
 	metric unaggregated.MetricUnion,
 	metadatas metadata.StagedMetadatas,
@@ -63,10 +63,13 @@ type GaugeElem struct {
 	elemBase
 	gaugeElemBase
 
-	values              []timedGauge // metric aggregations sorted by time in ascending order
-	toConsume           []timedGauge // small buffer to avoid memory allocations during consumption
-	lastConsumedAtNanos int64        // last consumed at in Unix nanoseconds
-	lastConsumedValues  []float64    // last consumed values
+	values               []timedGauge // metric aggregations sorted by time in ascending order
+	toConsume            []timedGauge // small buffer to avoid memory allocations during consumption
+	lastConsumedAtNanos  int64        // last consumed at in Unix nanoseconds
+	lastConsumedValues   []float64    // last consumed values
+	lastCumulativeValues []float64    // last cumulative transformation results
+	deferredAtNanos      int64        // start time of the window the deferred values are flushed with
+	deferredValues       []float64    // deferred multi-output transformation results, NaN if none
 }
 
 // NewGaugeElem creates a new element for the given metric type.
@@ -125,18 +128,17 @@ func (e *GaugeElem) ResetSetData(
 	if err := e.gaugeElemBase.ResetSetData(e.aggTypesOpts, aggTypes, useDefaultAggregation); err != nil {
 		return err
 	}
+	numAggTypes := len(e.aggTypes)
+	e.deferredValues = e.deferredValues[:0]
 	// If the pipeline contains derivative transformations, we need to store past
 	// values in order to compute the derivatives.
-	if !e.parsedPipeline.HasDerivativeTransform {
-		return nil
-	}
-	numAggTypes := len(e.aggTypes)
-	if cap(e.lastConsumedValues) < numAggTypes {
-		e.lastConsumedValues = make([]float64, numAggTypes)
+	if e.parsedPipeline.HasDerivativeTransform {
+		e.lastConsumedValues = resetLastValues(e.lastConsumedValues, numAggTypes)
 	}
-	e.lastConsumedValues = e.lastConsumedValues[:numAggTypes]
-	for i := 0; i < len(e.lastConsumedValues); i++ {
-		e.lastConsumedValues[i] = nan
+	// If the pipeline contains cumulative transformations, we need to store past
+	// transformation results in order to accumulate values across flushes.
+	if e.parsedPipeline.HasCumulativeTransform {
+		e.lastCumulativeValues = resetLastValues(e.lastCumulativeValues, numAggTypes)
 	}
 	return nil
 }
@@ -246,9 +248,11 @@ func (e *GaugeElem) Consume(
 
 	// Process the aggregations that are ready for consumption.
 	for i := range e.toConsume {
-		timeNanos := timestampNanosFn(e.toConsume[i].startAtNanos, resolution)
+		startAtNanos := e.toConsume[i].startAtNanos
+		e.flushDeferredValues(startAtNanos, timestampNanosFn, flushForwardedFn)
+		timeNanos := timestampNanosFn(startAtNanos, resolution)
 		e.toConsume[i].lockedAgg.Lock()
-		e.processValueWithAggregationLock(timeNanos, e.toConsume[i].lockedAgg, flushLocalFn, flushForwardedFn)
+		e.processValueWithAggregationLock(startAtNanos, timeNanos, e.toConsume[i].lockedAgg, flushLocalFn, flushForwardedFn)
 		// Closes the aggregation object after it's processed.
 		e.toConsume[i].lockedAgg.closed = true
 		e.toConsume[i].lockedAgg.aggregation.Close()
@@ -265,6 +269,12 @@ func (e *GaugeElem) Consume(
 		e.toConsume[i].lockedAgg.Unlock()
 		e.toConsume[i].Reset()
 	}
+	// Flush the deferred values on their own if their window is ready for
+	// consumption but has not received any values.
+	if len(e.deferredValues) > 0 && isEarlierThanFn(e.deferredAtNanos, resolution, targetNanos) {
+		e.flushDeferredValues(e.deferredAtNanos, timestampNanosFn, flushForwardedFn)
+	}
+	canCollect = canCollect && len(e.deferredValues) == 0
 
 	if e.parsedPipeline.HasRollup {
 		forwardedAggregationKey, _ := e.ForwardedAggregationKey()
@@ -274,6 +284,152 @@ func (e *GaugeElem) Consume(
 	return canCollect
 }
 
+// Snapshot returns a snapshot of the aggregation windows that have not been
+// consumed alongside the states needed to process them.
+// NB: the last consumed states are only updated by the flushing goroutine and
+// may be slightly stale if a flush is in progress.
+func (e *GaugeElem) Snapshot() elemSnapshot {
+	e.RLock()
+	if e.closed {
+		e.RUnlock()
+		return elemSnapshot{}
+	}
+	snapshot := elemSnapshot{
+		LastConsumedAtNanos: e.lastConsumedAtNanos,
+		Windows:             make([]windowSnapshot, 0, len(e.values)),
+	}
+	if len(e.lastConsumedValues) > 0 {
+		snapshot.LastConsumedValues = make([]float64, len(e.lastConsumedValues))
+		copy(snapshot.LastConsumedValues, e.lastConsumedValues)
+	}
+	if len(e.lastCumulativeValues) > 0 {
+		snapshot.LastCumulativeValues = make([]float64, len(e.lastCumulativeValues))
+		copy(snapshot.LastCumulativeValues, e.lastCumulativeValues)
+	}
+	if len(e.deferredValues) > 0 {
+		snapshot.DeferredAtNanos = e.deferredAtNanos
+		snapshot.DeferredValues = make([]float64, len(e.deferredValues))
+		copy(snapshot.DeferredValues, e.deferredValues)
+	}
+	for i := range e.values {
+		lockedAgg := e.values[i].lockedAgg
+		lockedAgg.Lock()
+		if lockedAgg.closed {
+			lockedAgg.Unlock()
+			continue
+		}
+		window := windowSnapshot{
+			StartAtNanos: e.values[i].startAtNanos,
+			Aggregation:  lockedAgg.aggregation.Snapshot(),
+		}
+		if lockedAgg.sourcesSeen != nil {
+			// NB: marshaling a bitset never fails.
+			window.SourcesSeen, _ = lockedAgg.sourcesSeen.MarshalBinary()
+		}
+		lockedAgg.Unlock()
+		snapshot.Windows = append(snapshot.Windows, window)
+	}
+	e.RUnlock()
+	return snapshot
+}
+
+// Restore restores the aggregation windows and the states needed to process
+// them from a snapshot, replacing the existing aggregations for the same windows.
+func (e *GaugeElem) Restore(snapshot elemSnapshot) error {
+	for _, window := range snapshot.Windows {
+		createOpts := createAggregationOptions{initSourceSet: window.SourcesSeen != nil}
+		lockedAgg, err := e.findOrCreate(window.StartAtNanos, createOpts)
+		if err != nil {
+			return err
+		}
+		lockedAgg.Lock()
+		if lockedAgg.closed {
+			lockedAgg.Unlock()
+			return errAggregationClosed
+		}
+		if err := lockedAgg.aggregation.Restore(window.Aggregation); err != nil {
+			lockedAgg.Unlock()
+			return err
+		}
+		if window.SourcesSeen != nil {
+			if lockedAgg.sourcesSeen == nil {
+				lockedAgg.sourcesSeen = bitset.New(defaultNumSources)
+			}
+			if err := lockedAgg.sourcesSeen.UnmarshalBinary(window.SourcesSeen); err != nil {
+				lockedAgg.Unlock()
+				return err
+			}
+		}
+		lockedAgg.Unlock()
+	}
+
+	e.Lock()
+	e.lastConsumedAtNanos = snapshot.LastConsumedAtNanos
+	if len(snapshot.LastConsumedValues) == len(e.lastConsumedValues) {
+		copy(e.lastConsumedValues, snapshot.LastConsumedValues)
+	}
+	if len(snapshot.LastCumulativeValues) == len(e.lastCumulativeValues) {
+		copy(e.lastCumulativeValues, snapshot.LastCumulativeValues)
+	}
+	if len(snapshot.DeferredValues) == len(e.aggTypes) {
+		e.deferredAtNanos = snapshot.DeferredAtNanos
+		e.deferredValues = append(e.deferredValues[:0], snapshot.DeferredValues...)
+	}
+	e.Unlock()
+	return nil
+}
+
+// Inspect returns the current values of the aggregation windows that have not
+// been consumed. Each window is locked only while its values are read so
+// inspecting an element does not block ingestion for long.
+func (e *GaugeElem) Inspect() ElemInspectResult {
+	e.RLock()
+	if e.closed {
+		e.RUnlock()
+		return ElemInspectResult{}
+	}
+	res := ElemInspectResult{
+		StoragePolicy:       e.sp.String(),
+		AggregationTypes:    make([]string, 0, len(e.aggTypes)),
+		NumForwardedTimes:   e.numForwardedTimes,
+		Tombstoned:          e.tombstoned,
+		LastConsumedAtNanos: e.lastConsumedAtNanos,
+		Windows:             make([]WindowInspectResult, 0, len(e.values)),
+	}
+	for _, aggType := range e.aggTypes {
+		res.AggregationTypes = append(res.AggregationTypes, aggType.String())
+	}
+	if forwardedID, ok := e.ForwardedID(); ok {
+		res.ForwardedID = string(forwardedID)
+	}
+	for i := range e.values {
+		lockedAgg := e.values[i].lockedAgg
+		lockedAgg.Lock()
+		if lockedAgg.closed {
+			lockedAgg.Unlock()
+			continue
+		}
+		window := WindowInspectResult{
+			StartAtNanos: e.values[i].startAtNanos,
+			Values:       make(map[string]float64, len(e.aggTypes)),
+		}
+		for _, aggType := range e.aggTypes {
+			if value := lockedAgg.aggregation.ValueOf(aggType); !math.IsNaN(value) {
+				window.Values[aggType.String()] = value
+			}
+		}
+		if lockedAgg.sourcesSeen != nil {
+			for id, ok := lockedAgg.sourcesSeen.NextSet(0); ok; id, ok = lockedAgg.sourcesSeen.NextSet(id + 1) {
+				window.SourcesSeen = append(window.SourcesSeen, uint32(id))
+			}
+		}
+		lockedAgg.Unlock()
+		res.Windows = append(res.Windows, window)
+	}
+	e.RUnlock()
+	return res
+}
+
 // Close closes the element.
 func (e *GaugeElem) Close() {
 	e.Lock()
@@ -299,6 +455,8 @@ func (e *GaugeElem) Close() {
 	e.values = e.values[:0]
 	e.toConsume = e.toConsume[:0]
 	e.lastConsumedValues = e.lastConsumedValues[:0]
+	e.lastCumulativeValues = e.lastCumulativeValues[:0]
+	e.deferredValues = e.deferredValues[:0]
 	e.gaugeElemBase.Close()
 	aggTypesPool := e.aggTypesOpts.TypesPool()
 	pool := e.ElemPool(e.opts)
@@ -401,6 +559,7 @@ func (e *GaugeElem) indexOfWithLock(alignedStart int64) (int, bool) {
 }
 
 func (e *GaugeElem) processValueWithAggregationLock(
+	startAtNanos int64,
 	timeNanos int64,
 	lockedAgg *lockedGaugeAggregation,
 	flushLocalFn flushLocalMetricFn,
@@ -411,14 +570,19 @@ func (e *GaugeElem) processValueWithAggregationLock(
 		discardNaNValues = e.opts.DiscardNaNAggregatedValues()
 	)
 	for aggTypeIdx, aggType := range e.aggTypes {
-		value := lockedAgg.aggregation.ValueOf(aggType)
+		var (
+			value    = lockedAgg.aggregation.ValueOf(aggType)
+			extraDp  transformation.Datapoint
+			hasExtra bool
+		)
 		for i := 0; i < transformations.Len(); i++ {
 			transformType := transformations.At(i).Transformation.Type
-			if transformType.IsUnaryTransform() {
+			switch {
+			case transformType.IsUnaryTransform():
 				fn := transformType.MustUnaryTransform()
 				res := fn(transformation.Datapoint{TimeNanos: timeNanos, Value: value})
 				value = res.Value
-			} else {
+			case transformType.IsBinaryTransform():
 				fn := transformType.MustBinaryTransform()
 				prev := transformation.Datapoint{TimeNanos: e.lastConsumedAtNanos, Value: e.lastConsumedValues[aggTypeIdx]}
 				curr := transformation.Datapoint{TimeNanos: timeNanos, Value: value}
@@ -429,22 +593,86 @@ func (e *GaugeElem) processValueWithAggregationLock(
 				// derivative transformations, we need to store an array of values here.
 				e.lastConsumedValues[aggTypeIdx] = value
 				value = res.Value
+			case transformType.IsCumulativeTransform():
+				fn := transformType.MustCumulativeTransform()
+				prev := transformation.Datapoint{TimeNanos: e.lastConsumedAtNanos, Value: e.lastCumulativeValues[aggTypeIdx]}
+				curr := transformation.Datapoint{TimeNanos: timeNanos, Value: value}
+				res := fn(prev, curr)
+				// NB: unlike derivative transformations, cumulative transformations are
+				// applied to their own previous result so the result is recorded instead.
+				e.lastCumulativeValues[aggTypeIdx] = res.Value
+				value = res.Value
+			default:
+				// NB: the pipeline is validated to only contain a multi-output transformation
+				// as its last transformation so the extra output is not transformed further.
+				fn := transformType.MustUnaryMultiOutputTransform()
+				res, extra := fn(transformation.Datapoint{TimeNanos: timeNanos, Value: value}, e.sp.Resolution().Window)
+				value = res.Value
+				extraDp, hasExtra = extra, true
 			}
 		}
 		if discardNaNValues && math.IsNaN(value) {
 			continue
 		}
+		e.flushValue(aggType, timeNanos, value, flushLocalFn, flushForwardedFn)
+		if !hasExtra || (discardNaNValues && extraDp.IsEmpty()) {
+			continue
+		}
 		if !e.parsedPipeline.HasRollup {
-			switch e.idPrefixSuffixType {
-			case NoPrefixNoSuffix:
-				flushLocalFn(nil, e.id, nil, timeNanos, value, e.sp)
-			case WithPrefixWithSuffix:
-				flushLocalFn(e.FullPrefix(e.opts), e.id, e.TypeStringFor(e.aggTypesOpts, aggType), timeNanos, value, e.sp)
-			}
-		} else {
-			forwardedAggregationKey, _ := e.ForwardedAggregationKey()
-			flushForwardedFn(e.writeForwardedMetricFn, forwardedAggregationKey, timeNanos, value)
+			e.flushValue(aggType, extraDp.TimeNanos, extraDp.Value, flushLocalFn, flushForwardedFn)
+			continue
+		}
+		// NB: the destination aggregator accepts a single write from each source per
+		// window, and the extra output falls into the same window as the current value
+		// so it is deferred to the next window and forwarded alongside its values.
+		if len(e.deferredValues) == 0 {
+			e.deferredValues = resetLastValues(e.deferredValues, len(e.aggTypes))
 		}
+		e.deferredAtNanos = startAtNanos + e.sp.Resolution().Window.Nanoseconds()
+		e.deferredValues[aggTypeIdx] = extraDp.Value
 	}
 	e.lastConsumedAtNanos = timeNanos
 }
+
+// flushDeferredValues forwards the deferred values if they are deferred to a
+// window starting no later than the given window start time.
+func (e *GaugeElem) flushDeferredValues(
+	startAtNanos int64,
+	timestampNanosFn timestampNanosFn,
+	flushForwardedFn flushForwardedMetricFn,
+) {
+	if len(e.deferredValues) == 0 || e.deferredAtNanos > startAtNanos {
+		return
+	}
+	var (
+		timeNanos                  = timestampNanosFn(e.deferredAtNanos, e.sp.Resolution().Window)
+		forwardedAggregationKey, _ = e.ForwardedAggregationKey()
+	)
+	for _, value := range e.deferredValues {
+		if math.IsNaN(value) {
+			continue
+		}
+		flushForwardedFn(e.writeForwardedMetricFn, forwardedAggregationKey, timeNanos, value)
+	}
+	e.deferredValues = e.deferredValues[:0]
+}
+
+func (e *GaugeElem) flushValue(
+	aggType maggregation.Type,
+	timeNanos int64,
+	value float64,
+	flushLocalFn flushLocalMetricFn,
+	flushForwardedFn flushForwardedMetricFn,
+) {
+	if !e.parsedPipeline.HasRollup {
+		switch e.idPrefixSuffixType {
+		case NoPrefixNoSuffix:
+			flushLocalFn(nil, e.id, nil, timeNanos, value, e.sp)
+		case WithPrefixWithSuffix:
+			flushLocalFn(e.FullPrefix(e.opts), e.id, e.TypeStringFor(e.aggTypesOpts, aggType), timeNanos, value, e.sp)
+		}
+	} else {
+		forwardedAggregationKey, _ := e.ForwardedAggregationKey()
+		flushForwardedFn(e.writeForwardedMetricFn, forwardedAggregationKey, timeNanos, value)
+	}
+}
@@ -75,6 +75,7 @@ func (c FlushHandlerConfiguration) NewHandler(
 	var (
 		handlers       = make([]Handler, 0, len(c.Handlers))
 		sharderRouters = make([]SharderRouter, 0, len(c.Handlers))
+		producers      []producer.Producer
 		store          kv.Store
 		err            error
 	)
@@ -93,9 +94,10 @@ func (c FlushHandlerConfiguration) NewHandler(
 			return nil, err
 		}
 		if hc.DynamicBackend != nil {
-			handlers, sharderRouters, err = hc.DynamicBackend.constructDynamicBackend(
+			handlers, sharderRouters, producers, err = hc.DynamicBackend.constructDynamicBackend(
 				handlers,
 				sharderRouters,
+				producers,
 				cs,
 				store,
 				c.Writer,
@@ -129,10 +131,16 @@ func (c FlushHandlerConfiguration) NewHandler(
 		shardedHandler := NewShardedHandler(sharderRouters, writerOpts)
 		handlers = append(handlers, shardedHandler)
 	}
+	var h Handler
 	if len(handlers) == 1 {
-		return handlers[0], nil
+		h = handlers[0]
+	} else {
+		h = NewBroadcastHandler(handlers)
 	}
-	return NewBroadcastHandler(handlers), nil
+	if len(producers) > 0 {
+		h = newProducerStatsHandler(h, producers)
+	}
+	return h, nil
 }
 
 type writerConfiguration struct {
@@ -225,48 +233,46 @@ type dynamicBackendConfiguration struct {
 func (c *dynamicBackendConfiguration) constructDynamicBackend(
 	handlers []Handler,
 	sharderRouters []SharderRouter,
+	producers []producer.Producer,
 	cs client.Client,
 	store kv.Store,
 	cfg *writerConfiguration,
 	instrumentOpts instrument.Options,
-) ([]Handler, []SharderRouter, error) {
+) ([]Handler, []SharderRouter, []producer.Producer, error) {
+	scope := instrumentOpts.MetricsScope().Tagged(map[string]string{
+		"backend":   c.Name,
+		"component": "producer",
+	})
+	producerOpts := instrumentOpts.SetMetricsScope(scope)
+	p, err := c.newProducer(cs, producerOpts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	producers = append(producers, p)
 	if c.ProtobufEnabled != nil && *c.ProtobufEnabled {
-		h, err := c.newProtobufHandler(
-			cs,
-			cfg,
-			instrumentOpts,
-		)
-		if err != nil {
-			return nil, nil, err
-		}
-		handlers = append(handlers, h)
+		handlers = append(handlers, NewProtobufHandler(p, cfg.NewWriterOptions(producerOpts)))
 		instrumentOpts.Logger().Infof("created flush handler %s with protobuf encoding", c.Name)
-		return handlers, sharderRouters, nil
+		return handlers, sharderRouters, producers, nil
 	}
 
 	sharderRouter, err := c.newSharderRouter(
-		cs,
+		p,
 		store,
+		scope,
 		instrumentOpts,
 	)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	sharderRouters = append(sharderRouters, sharderRouter)
 	instrumentOpts.Logger().Infof("created flush handler %s with msgpack encoding", c.Name)
-	return handlers, sharderRouters, nil
+	return handlers, sharderRouters, producers, nil
 }
 
-func (c *dynamicBackendConfiguration) newProtobufHandler(
+func (c *dynamicBackendConfiguration) newProducer(
 	cs client.Client,
-	cfg *writerConfiguration,
 	instrumentOpts instrument.Options,
-) (Handler, error) {
-	scope := instrumentOpts.MetricsScope().Tagged(map[string]string{
-		"backend":   c.Name,
-		"component": "producer",
-	})
-	instrumentOpts = instrumentOpts.SetMetricsScope(scope)
+) (producer.Producer, error) {
 	p, err := c.Producer.NewProducer(cs, instrumentOpts)
 	if err != nil {
 		return nil, err
@@ -280,32 +286,15 @@ func (c *dynamicBackendConfiguration) newProtobufHandler(
 		p.RegisterFilter(sid, f)
 		logger.Infof("registered filter for consumer service: %s", sid.String())
 	}
-	wOpts := cfg.NewWriterOptions(instrumentOpts)
-	return NewProtobufHandler(p, wOpts), nil
+	return p, nil
 }
 
 func (c *dynamicBackendConfiguration) newSharderRouter(
-	cs client.Client,
+	p producer.Producer,
 	store kv.Store,
+	scope tally.Scope,
 	instrumentOpts instrument.Options,
 ) (SharderRouter, error) {
-	scope := instrumentOpts.MetricsScope().Tagged(map[string]string{
-		"backend":   c.Name,
-		"component": "producer",
-	})
-	p, err := c.Producer.NewProducer(cs, instrumentOpts.SetMetricsScope(scope))
-	if err != nil {
-		return SharderRouter{}, err
-	}
-	if err := p.Init(); err != nil {
-		return SharderRouter{}, err
-	}
-	logger := instrumentOpts.Logger()
-	for _, filter := range c.Filters {
-		sid, f := filter.NewConsumerServiceFilter()
-		p.RegisterFilter(sid, f)
-		logger.Infof("registered filter for consumer service: %s", sid.String())
-	}
 	r := router.NewWithAckRouter(p)
 	if c.TrafficControl != nil {
 		tc, err := c.TrafficControl.NewTrafficController(store, instrumentOpts)
@@ -0,0 +1,54 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"github.com/m3db/m3/src/msg/producer"
+)
+
+// ProducerStatsReporter reports the stats of the m3msg producers of a handler.
+type ProducerStatsReporter interface {
+	// ProducerStats returns the stats of the messages in flight of the producers.
+	ProducerStats() []producer.TopicStats
+}
+
+type producerStatsHandler struct {
+	Handler
+
+	producers []producer.Producer
+}
+
+// newProducerStatsHandler wraps a handler to report the stats of the
+// producers the handler writes to.
+func newProducerStatsHandler(h Handler, producers []producer.Producer) Handler {
+	return producerStatsHandler{
+		Handler:   h,
+		producers: producers,
+	}
+}
+
+func (h producerStatsHandler) ProducerStats() []producer.TopicStats {
+	stats := make([]producer.TopicStats, 0, len(h.producers))
+	for _, p := range h.producers {
+		stats = append(stats, p.Stats())
+	}
+	return stats
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"sort"
+
+	schema "github.com/m3db/m3/src/aggregator/generated/proto/flush"
+	"github.com/m3db/m3/src/metrics/filters"
+)
+
+// InspectQuery selects the entries whose aggregation states are inspected.
+type InspectQuery struct {
+	// ID of the metric to inspect. If set, the filter is ignored.
+	ID []byte
+
+	// Filter on the metric IDs, matching all metrics if not set.
+	Filter filters.Filter
+
+	// Maximum number of entries returned per shard, unlimited if zero.
+	Limit int
+}
+
+// InspectResult contains the inspected aggregation states of the aggregator.
+type InspectResult struct {
+	Shards []ShardInspectResult `json:"shards"`
+}
+
+// ShardInspectResult contains the inspected aggregation states of a shard.
+type ShardInspectResult struct {
+	Shard                 uint32                  `json:"shard"`
+	CutoverNanos          int64                   `json:"cutoverNanos"`
+	CutoffNanos           int64                   `json:"cutoffNanos"`
+	EarliestWritableNanos int64                   `json:"earliestWritableNanos"`
+	LatestWriteableNanos  int64                   `json:"latestWriteableNanos"`
+	NewMetricRateLimit    RateLimitInspectResult  `json:"newMetricRateLimit"`
+	FlushTimes            *schema.ShardFlushTimes `json:"flushTimes,omitempty"`
+	Lists                 []ListInspectResult     `json:"lists"`
+	Entries               []EntryInspectResult    `json:"entries"`
+}
+
+// RateLimitInspectResult contains the state of a rate limiter.
+type RateLimitInspectResult struct {
+	// Limit per second, or zero if there is no limit.
+	LimitPerSecond int64 `json:"limitPerSecond"`
+
+	// Whether the limit is not yet enforced because the rate limiter is warming up.
+	WarmingUp bool `json:"warmingUp,omitempty"`
+}
+
+// ListInspectResult contains the state of a metric list.
+type ListInspectResult struct {
+	Type              string `json:"type"`
+	Resolution        string `json:"resolution"`
+	NumForwardedTimes int    `json:"numForwardedTimes,omitempty"`
+	NumElems          int    `json:"numElems"`
+	LastFlushedNanos  int64  `json:"lastFlushedNanos"`
+}
+
+// EntryInspectResult contains the aggregation states of an entry.
+type EntryInspectResult struct {
+	ID                  string                 `json:"id"`
+	MetricCategory      string                 `json:"metricCategory"`
+	MetricType          string                 `json:"metricType"`
+	HasDefaultMetadatas bool                   `json:"hasDefaultMetadatas"`
+	CutoverNanos        int64                  `json:"cutoverNanos"`
+	LastAccessNanos     int64                  `json:"lastAccessNanos"`
+	NumWriters          int32                  `json:"numWriters"`
+	RateLimit           RateLimitInspectResult `json:"rateLimit"`
+	Elems               []ElemInspectResult    `json:"elems"`
+}
+
+// ElemInspectResult contains the aggregation states of an element.
+type ElemInspectResult struct {
+	StoragePolicy       string                `json:"storagePolicy"`
+	AggregationTypes    []string              `json:"aggregationTypes"`
+	Pipeline            string                `json:"pipeline"`
+	NumForwardedTimes   int                   `json:"numForwardedTimes,omitempty"`
+	ForwardedID         string                `json:"forwardedID,omitempty"`
+	Tombstoned          bool                  `json:"tombstoned,omitempty"`
+	LastConsumedAtNanos int64                 `json:"lastConsumedAtNanos"`
+	Windows             []WindowInspectResult `json:"windows"`
+}
+
+// WindowInspectResult contains the aggregated values of an aggregation window
+// that has not been consumed yet.
+type WindowInspectResult struct {
+	StartAtNanos int64 `json:"startAtNanos"`
+
+	// Current values by aggregation type. NaN values are omitted since they
+	// cannot be represented in JSON.
+	Values map[string]float64 `json:"values"`
+
+	// IDs of the sources that have forwarded values for the window, only
+	// present for forwarded metrics.
+	SourcesSeen []uint32 `json:"sourcesSeen,omitempty"`
+}
+
+func (c metricCategory) String() string {
+	switch c {
+	case untimedMetric:
+		return "untimed"
+	case forwardedMetric:
+		return "forwarded"
+	case timedMetric:
+		return "timed"
+	default:
+		return "unknown"
+	}
+}
+
+// Inspect returns the states of the metric lists sorted by type and resolution.
+func (l *metricLists) Inspect() []ListInspectResult {
+	l.RLock()
+	defer l.RUnlock()
+
+	ids := make([]metricListID, 0, len(l.lists))
+	for id := range l.lists {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].listType != ids[j].listType {
+			return ids[i].listType < ids[j].listType
+		}
+		ri, rj := l.lists[ids[i]].Resolution(), l.lists[ids[j]].Resolution()
+		if ri != rj {
+			return ri < rj
+		}
+		return ids[i].forwarded.numForwardedTimes < ids[j].forwarded.numForwardedTimes
+	})
+	res := make([]ListInspectResult, 0, len(ids))
+	for _, id := range ids {
+		list := l.lists[id]
+		res = append(res, ListInspectResult{
+			Type:              id.listType.String(),
+			Resolution:        list.Resolution().String(),
+			NumForwardedTimes: id.forwarded.numForwardedTimes,
+			NumElems:          list.Len(),
+			LastFlushedNanos:  list.LastFlushedNanos(),
+		})
+	}
+	return res
+}
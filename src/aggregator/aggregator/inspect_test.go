@@ -0,0 +1,95 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package aggregator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/m3db/m3/src/metrics/filters"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregatorShardInspect(t *testing.T) {
+	shard := newAggregatorShard(testShard, NewOptions().SetEntryCheckInterval(0))
+	defer shard.Close()
+	shard.SetWriteableRange(timeRange{cutoverNanos: 0, cutoffNanos: math.MaxInt64})
+	require.NoError(t, shard.AddUntimed(testUntimedMetric, testStagedMetadatas))
+
+	res, err := shard.Inspect(InspectQuery{ID: testUntimedMetric.ID})
+	require.NoError(t, err)
+	require.Equal(t, testShard, res.Shard)
+	require.Equal(t, int64(math.MaxInt64), res.CutoffNanos)
+	require.Equal(t, 1, len(res.Entries))
+	require.True(t, len(res.Lists) > 0)
+
+	entry := res.Entries[0]
+	require.Equal(t, string(testUntimedMetric.ID), entry.ID)
+	require.Equal(t, "untimed", entry.MetricCategory)
+	require.Equal(t, "counter", entry.MetricType)
+	require.True(t, len(entry.Elems) > 0)
+	for _, elem := range entry.Elems {
+		require.Equal(t, 1, len(elem.Windows))
+		require.Contains(t, elem.Windows[0].Values, elem.AggregationTypes[0])
+	}
+
+	// Inspecting a metric that does not exist returns no entries.
+	res, err = shard.Inspect(InspectQuery{ID: []byte("nonexistent")})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(res.Entries))
+
+	// Inspecting with a filter returns the matching entries only.
+	matching, err := filters.NewFilter([]byte("fo*"))
+	require.NoError(t, err)
+	res, err = shard.Inspect(InspectQuery{Filter: matching})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res.Entries))
+
+	nonMatching, err := filters.NewFilter([]byte("bar*"))
+	require.NoError(t, err)
+	res, err = shard.Inspect(InspectQuery{Filter: nonMatching})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(res.Entries))
+
+	// Inspecting with a limit returns at most the limit number of entries.
+	require.NoError(t, shard.AddUntimed(testGauge, testStagedMetadatas))
+	res, err = shard.Inspect(InspectQuery{})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(res.Entries))
+	res, err = shard.Inspect(InspectQuery{Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res.Entries))
+
+	// The filter is applied before the limit so that unmatched entries do not
+	// count towards the limit.
+	gaugeFilter, err := filters.NewFilter([]byte("testGauge"))
+	require.NoError(t, err)
+	res, err = shard.Inspect(InspectQuery{Filter: gaugeFilter, Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res.Entries))
+	require.Equal(t, string(testGaugeID), res.Entries[0].ID)
+
+	// Inspecting a closed shard returns an error.
+	shard.Close()
+	_, err = shard.Inspect(InspectQuery{})
+	require.Equal(t, errAggregatorShardClosed, err)
+}
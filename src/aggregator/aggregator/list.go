@@ -54,6 +54,9 @@ type metricList interface {
 	// PushBack pushes a metric element to the back of the list.
 	PushBack(value metricElem) (*list.Element, error)
 
+	// LastFlushedNanos returns the last flushed time in Unix nanoseconds.
+	LastFlushedNanos() int64
+
 	// Close closes the metric list.
 	Close()
 }
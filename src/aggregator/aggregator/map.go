@@ -36,6 +36,7 @@ import (
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
 	"github.com/m3db/m3x/clock"
 	"github.com/m3db/m3x/close"
+	xerrors "github.com/m3db/m3x/errors"
 
 	"github.com/uber-go/tally"
 )
@@ -72,6 +73,11 @@ type hashedEntry struct {
 	entry *Entry
 }
 
+var (
+	inspectMetricCategories = []metricCategory{untimedMetric, forwardedMetric, timedMetric}
+	inspectMetricTypes      = []metric.Type{metric.CounterType, metric.TimerType, metric.GaugeType}
+)
+
 type metricMapMetrics struct {
 	newEntries                 tally.Counter
 	noRateLimitWarmup          tally.Counter
@@ -205,6 +211,132 @@ func (m *metricMap) Tick(target time.Duration) tickResult {
 	return mapTickRes
 }
 
+// Checkpoint returns the checkpoints of the entries in the map.
+func (m *metricMap) Checkpoint() ([]entryCheckpoint, error) {
+	var (
+		checkpoints []entryCheckpoint
+		multiErr    = xerrors.NewMultiError()
+	)
+	m.forEachEntry(func(entry hashedEntry) {
+		checkpoint, ok, err := entry.entry.Checkpoint(entry.key)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			return
+		}
+		if ok {
+			checkpoints = append(checkpoints, checkpoint)
+		}
+	})
+	return checkpoints, multiErr.FinalError()
+}
+
+// Restore restores entries from their checkpoints, returning the number of
+// entries restored. Restored entries are not subject to the new metric rate
+// limits since they are not new to the aggregator.
+func (m *metricMap) Restore(
+	checkpoints []entryCheckpoint,
+	filterFn restoreWindowFilterFn,
+) (int, error) {
+	var (
+		numRestored int
+		multiErr    = xerrors.NewMultiError()
+	)
+	for _, checkpoint := range checkpoints {
+		key := entryKey{
+			metricCategory: checkpoint.MetricCategory,
+			metricType:     checkpoint.MetricType,
+			idHash:         hash.Murmur3Hash128(checkpoint.ID),
+		}
+		m.Lock()
+		if m.closed {
+			m.Unlock()
+			return numRestored, errMetricMapClosed
+		}
+		entry, found := m.lookupEntryWithLock(key)
+		if !found {
+			entry = m.insertEntryWithLock(key)
+		}
+		entry.IncWriter()
+		m.Unlock()
+		if !found {
+			m.metrics.newEntries.Inc(1)
+		}
+
+		err := entry.Restore(checkpoint, filterFn)
+		entry.DecWriter()
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			continue
+		}
+		numRestored++
+	}
+	return numRestored, multiErr.FinalError()
+}
+
+// Inspect returns the aggregation states of the entries matching the query
+// alongside the state of the new metric rate limiter and the metric lists.
+// Entries are visited in batches so that inspecting the map does not block
+// ingestion for long.
+func (m *metricMap) Inspect(query InspectQuery) (
+	[]EntryInspectResult,
+	RateLimitInspectResult,
+	[]ListInspectResult,
+) {
+	var (
+		entries  []EntryInspectResult
+		hasLimit = query.Limit > 0
+	)
+	if query.ID != nil {
+		idHash := hash.Murmur3Hash128(query.ID)
+		matched := make([]hashedEntry, 0, len(inspectMetricCategories))
+		m.RLock()
+		for _, category := range inspectMetricCategories {
+			for _, metricType := range inspectMetricTypes {
+				key := entryKey{metricCategory: category, metricType: metricType, idHash: idHash}
+				if entry, found := m.lookupEntryWithLock(key); found {
+					matched = append(matched, hashedEntry{key: key, entry: entry})
+				}
+			}
+		}
+		m.RUnlock()
+		for _, entry := range matched {
+			if hasLimit && len(entries) >= query.Limit {
+				break
+			}
+			if res, ok := entry.entry.Inspect(entry.key); ok {
+				entries = append(entries, res)
+			}
+		}
+	} else {
+		// The iteration stops as soon as the limit is reached so that the
+		// remaining entries are neither visited nor materialized.
+		// Entries are matched against the filter before they are inspected so
+		// that the aggregation states of unmatched entries are not materialized.
+		m.forEachEntryUntil(func(entry hashedEntry) bool {
+			if query.Filter != nil && !entry.entry.MatchesFilter(query.Filter) {
+				return true
+			}
+			res, ok := entry.entry.Inspect(entry.key)
+			if !ok {
+				return true
+			}
+			entries = append(entries, res)
+			return !hasLimit || len(entries) < query.Limit
+		})
+	}
+
+	var rateLimit RateLimitInspectResult
+	m.RLock()
+	if m.rateLimiter != nil {
+		rateLimit.LimitPerSecond = m.rateLimiter.Limit()
+		warmupEnd := m.firstInsertAt.Add(m.runtimeOpts.WriteNewMetricNoLimitWarmupDuration())
+		rateLimit.WarmingUp = m.nowFn().Before(warmupEnd)
+	}
+	m.RUnlock()
+
+	return entries, rateLimit, m.metricLists.Inspect()
+}
+
 func (m *metricMap) SetRuntimeOptions(opts runtime.Options) {
 	m.Lock()
 	m.runtimeOpts = opts
@@ -274,12 +406,7 @@ func (m *metricMap) findOrCreate(key entryKey) (*Entry, error) {
 		m.Unlock()
 		return nil, err
 	}
-	entry = m.entryPool.Get()
-	entry.ResetSetData(m.metricLists, m.runtimeOpts, m.opts)
-	m.entries[key] = m.entryList.PushBack(hashedEntry{
-		key:   key,
-		entry: entry,
-	})
+	entry = m.insertEntryWithLock(key)
 	entry.IncWriter()
 	m.Unlock()
 	m.metrics.newEntries.Inc(1)
@@ -287,6 +414,16 @@ func (m *metricMap) findOrCreate(key entryKey) (*Entry, error) {
 	return entry, nil
 }
 
+func (m *metricMap) insertEntryWithLock(key entryKey) *Entry {
+	entry := m.entryPool.Get()
+	entry.ResetSetData(m.metricLists, m.runtimeOpts, m.opts)
+	m.entries[key] = m.entryList.PushBack(hashedEntry{
+		key:   key,
+		entry: entry,
+	})
+	return entry
+}
+
 func (m *metricMap) lookupEntryWithLock(key entryKey) (*Entry, bool) {
 	elem, exists := m.entries[key]
 	if !exists {
@@ -407,6 +544,15 @@ func (m *metricMap) purgeExpired(
 }
 
 func (m *metricMap) forEachEntry(entryFn hashedEntryFn) {
+	m.forEachEntryUntil(func(entry hashedEntry) bool {
+		entryFn(entry)
+		return true
+	})
+}
+
+// forEachEntryUntil applies the function to each entry until the function
+// returns false.
+func (m *metricMap) forEachEntryUntil(entryFn hashedEntryUntilFn) {
 	// Determine batch size.
 	m.RLock()
 	elemsLen := m.entryList.Len()
@@ -430,13 +576,20 @@ func (m *metricMap) forEachEntry(entryFn hashedEntryFn) {
 		}
 		m.RUnlock()
 
+		done := false
 		for _, entry := range currEntries {
-			entryFn(entry)
+			if !entryFn(entry) {
+				done = true
+				break
+			}
 		}
 		for i := range currEntries {
 			currEntries[i] = emptyHashedEntry
 		}
 		currEntries = currEntries[:0]
+		if done {
+			return
+		}
 	}
 }
 
@@ -474,3 +627,5 @@ func (m *metricMap) applyNewMetricRateLimitWithLock(now time.Time) error {
 }
 
 type hashedEntryFn func(hashedEntry)
+
+type hashedEntryUntilFn func(hashedEntry) bool
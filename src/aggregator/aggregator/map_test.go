@@ -151,6 +151,32 @@ func TestMetricMapAddUntimedNoRateLimit(t *testing.T) {
 	require.Equal(t, 3, m.metricLists.Len())
 }
 
+func TestMetricMapForEachEntryUntil(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := testOptions(ctrl)
+	m := newMetricMap(testShard, opts)
+	for _, id := range []string{"foo", "bar", "baz"} {
+		require.NoError(t, m.AddUntimed(unaggregated.MetricUnion{
+			Type:     metric.GaugeType,
+			ID:       []byte(id),
+			GaugeVal: 123.456,
+		}, testDefaultStagedMetadatas))
+	}
+
+	var visited int
+	m.forEachEntryUntil(func(hashedEntry) bool {
+		visited++
+		return visited < 2
+	})
+	require.Equal(t, 2, visited)
+
+	visited = 0
+	m.forEachEntry(func(hashedEntry) { visited++ })
+	require.Equal(t, 3, visited)
+}
+
 func TestMetricMapSetRuntimeOptions(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -290,6 +290,14 @@ type Options interface {
 	// GaugeElemPool returns the gauge element pool.
 	GaugeElemPool() GaugeElemPool
 
+	// SetCheckpointOptions sets the checkpoint options, or nil to disable
+	// checkpointing aggregation states to local disk.
+	SetCheckpointOptions(value CheckpointOptions) Options
+
+	// CheckpointOptions returns the checkpoint options, or nil if checkpointing
+	// aggregation states to local disk is disabled.
+	CheckpointOptions() CheckpointOptions
+
 	/// Read-only derived options.
 
 	// FullCounterPrefix returns the full prefix for counters.
@@ -338,6 +346,7 @@ type options struct {
 	counterElemPool                  CounterElemPool
 	timerElemPool                    TimerElemPool
 	gaugeElemPool                    GaugeElemPool
+	checkpointOpts                   CheckpointOptions
 
 	// Derived options.
 	fullCounterPrefix []byte
@@ -732,6 +741,16 @@ func (o *options) GaugeElemPool() GaugeElemPool {
 	return o.gaugeElemPool
 }
 
+func (o *options) SetCheckpointOptions(value CheckpointOptions) Options {
+	opts := *o
+	opts.checkpointOpts = value
+	return &opts
+}
+
+func (o *options) CheckpointOptions() CheckpointOptions {
+	return o.checkpointOpts
+}
+
 func (o *options) FullCounterPrefix() []byte {
 	return o.fullCounterPrefix
 }
@@ -225,3 +225,11 @@ func TestSetGaugeElemPool(t *testing.T) {
 	o := NewOptions().SetGaugeElemPool(value)
 	require.Equal(t, value, o.GaugeElemPool())
 }
+
+func TestSetCheckpointOptions(t *testing.T) {
+	o := NewOptions()
+	require.Nil(t, o.CheckpointOptions())
+	value := NewCheckpointOptions().SetDirectory("/var/lib/m3aggregator")
+	o = o.SetCheckpointOptions(value)
+	require.Equal(t, value, o.CheckpointOptions())
+}
@@ -224,6 +224,73 @@ func (s *aggregatorShard) AddForwarded(
 	return nil
 }
 
+// Checkpoint returns a checkpoint of the aggregation states in the shard.
+func (s *aggregatorShard) Checkpoint() (shardCheckpoint, error) {
+	s.RLock()
+	if s.closed {
+		s.RUnlock()
+		return shardCheckpoint{}, errAggregatorShardClosed
+	}
+	s.RUnlock()
+
+	checkpointNanos := s.nowFn().UnixNano()
+	entries, err := s.metricMap.Checkpoint()
+	if err != nil {
+		return shardCheckpoint{}, err
+	}
+	return shardCheckpoint{
+		Shard:           s.shard,
+		CheckpointNanos: checkpointNanos,
+		Entries:         entries,
+	}, nil
+}
+
+// Restore restores the aggregation states in the shard from a checkpoint,
+// returning the number of entries restored. Only the aggregation windows
+// overlapping with the time range between the shard cutover time and the
+// shard cutoff time are restored since the shard does not own data outside
+// of this time range.
+func (s *aggregatorShard) Restore(checkpoint shardCheckpoint) (int, error) {
+	if checkpoint.Shard != s.shard {
+		return 0, errCheckpointShardMismatch
+	}
+	s.RLock()
+	if s.closed {
+		s.RUnlock()
+		return 0, errAggregatorShardClosed
+	}
+	cutoverNanos := s.cutoverNanos
+	cutoffNanos := s.cutoffNanos
+	s.RUnlock()
+
+	filterFn := func(windowStartNanos int64, resolution time.Duration) bool {
+		windowEndNanos := windowStartNanos + resolution.Nanoseconds()
+		return windowEndNanos > cutoverNanos && windowStartNanos < cutoffNanos
+	}
+	return s.metricMap.Restore(checkpoint.Entries, filterFn)
+}
+
+// Inspect returns the aggregation states of the entries in the shard matching
+// the query alongside the shard states.
+func (s *aggregatorShard) Inspect(query InspectQuery) (ShardInspectResult, error) {
+	s.RLock()
+	if s.closed {
+		s.RUnlock()
+		return ShardInspectResult{}, errAggregatorShardClosed
+	}
+	res := ShardInspectResult{
+		Shard:                 s.shard,
+		CutoverNanos:          s.cutoverNanos,
+		CutoffNanos:           s.cutoffNanos,
+		EarliestWritableNanos: s.earliestWritableNanos,
+		LatestWriteableNanos:  s.latestWriteableNanos,
+	}
+	s.RUnlock()
+
+	res.Entries, res.NewMetricRateLimit, res.Lists = s.metricMap.Inspect(query)
+	return res, nil
+}
+
 func (s *aggregatorShard) Tick(target time.Duration) tickResult {
 	return s.metricMap.Tick(target)
 }
@@ -28,6 +28,7 @@ import (
 	"github.com/m3db/m3/src/cluster/kv"
 	"github.com/m3db/m3/src/cluster/placement"
 	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/clock"
 	"github.com/m3db/m3x/instrument"
 	"github.com/m3db/m3x/pool"
@@ -49,6 +50,7 @@ type Configuration struct {
 	QueueSize                  int                            `yaml:"queueSize"`
 	QueueDropType              *DropType                      `yaml:"queueDropType"`
 	Connection                 ConnectionConfiguration        `yaml:"connection"`
+	TLS                        *xtls.Configuration            `yaml:"tls"`
 }
 
 // NewAdminClient creates a new admin client.
@@ -84,6 +86,13 @@ func (c *Configuration) newClientOptions(
 ) (Options, error) {
 	scope := instrumentOpts.MetricsScope()
 	connectionOpts := c.Connection.NewConnectionOptions(scope.SubScope("connection"))
+	if c.TLS != nil {
+		tlsDialer, err := c.TLS.NewDialer()
+		if err != nil {
+			return nil, err
+		}
+		connectionOpts = connectionOpts.SetTLSDialer(tlsDialer)
+	}
 	kvOpts, err := c.PlacementKV.NewOverrideOptions()
 	if err != nil {
 		return nil, err
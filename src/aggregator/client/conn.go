@@ -27,6 +27,7 @@ import (
 	"sync"
 	"time"
 
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/clock"
 	"github.com/m3db/m3x/retry"
 
@@ -59,9 +60,10 @@ type connection struct {
 	maxThreshold   int
 	maxDuration    time.Duration
 	writeRetryOpts retry.Options
+	tlsDialer      *xtls.Dialer
 	rngFn          retry.RngFn
 
-	conn                    *net.TCPConn
+	conn                    net.Conn
 	numFailures             int
 	threshold               int
 	lastConnectAttemptNanos int64
@@ -86,6 +88,7 @@ func newConnection(addr string, opts ConnectionOptions) *connection {
 		maxThreshold:   opts.MaxReconnectThreshold(),
 		maxDuration:    opts.MaxReconnectDuration(),
 		writeRetryOpts: opts.WriteRetryOptions(),
+		tlsDialer:      opts.TLSDialer(),
 		rngFn:          rand.New(rand.NewSource(time.Now().UnixNano())).Int63n,
 		nowFn:          opts.ClockOptions().NowFn(),
 		sleepFn:        time.Sleep,
@@ -178,10 +181,18 @@ func (c *connection) connectWithLock() error {
 		c.metrics.setKeepAliveError.Inc(1)
 	}
 
+	if c.tlsDialer != nil {
+		conn, err = c.tlsDialer.Client(tcpConn, c.addr, c.connTimeout)
+		if err != nil {
+			c.metrics.tlsHandshakeError.Inc(1)
+			return err
+		}
+	}
+
 	if c.conn != nil {
 		c.conn.Close() // nolint: errcheck
 	}
-	c.conn = tcpConn
+	c.conn = conn
 	return nil
 }
 
@@ -245,6 +256,7 @@ type connectionMetrics struct {
 	writeRetries          tally.Counter
 	setKeepAliveError     tally.Counter
 	setWriteDeadlineError tally.Counter
+	tlsHandshakeError     tally.Counter
 }
 
 func newConnectionMetrics(scope tally.Scope) connectionMetrics {
@@ -258,5 +270,7 @@ func newConnectionMetrics(scope tally.Scope) connectionMetrics {
 			Counter(errorMetric),
 		setWriteDeadlineError: scope.Tagged(map[string]string{errorMetricType: "set-write-deadline"}).
 			Counter(errorMetric),
+		tlsHandshakeError: scope.Tagged(map[string]string{errorMetricType: "tls-handshake"}).
+			Counter(errorMetric),
 	}
 }
@@ -24,6 +24,7 @@ import (
 	"math"
 	"time"
 
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/clock"
 	"github.com/m3db/m3x/instrument"
 	"github.com/m3db/m3x/retry"
@@ -105,6 +106,12 @@ type ConnectionOptions interface {
 
 	// WriteRetryOptions returns the retry options for retrying failed writes.
 	WriteRetryOptions() retry.Options
+
+	// SetTLSDialer sets the TLS dialer, if set connections are made over TLS.
+	SetTLSDialer(value *xtls.Dialer) ConnectionOptions
+
+	// TLSDialer returns the TLS dialer.
+	TLSDialer() *xtls.Dialer
 }
 
 type connectionOptions struct {
@@ -118,6 +125,7 @@ type connectionOptions struct {
 	multiplier     int
 	maxDuration    time.Duration
 	writeRetryOpts retry.Options
+	tlsDialer      *xtls.Dialer
 }
 
 // NewConnectionOptions create a new set of connection options.
@@ -241,3 +249,13 @@ func (o *connectionOptions) SetWriteRetryOptions(value retry.Options) Connection
 func (o *connectionOptions) WriteRetryOptions() retry.Options {
 	return o.writeRetryOpts
 }
+
+func (o *connectionOptions) SetTLSDialer(value *xtls.Dialer) ConnectionOptions {
+	opts := *o
+	opts.tlsDialer = value
+	return &opts
+}
+
+func (o *connectionOptions) TLSDialer() *xtls.Dialer {
+	return o.tlsDialer
+}
@@ -0,0 +1,3527 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: github.com/m3db/m3/src/aggregator/generated/proto/checkpointpb/checkpoint.proto
+
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package checkpointpb
+
+import proto "github.com/gogo/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import _ "github.com/gogo/protobuf/gogoproto"
+import aggregationpb "github.com/m3db/m3/src/metrics/generated/proto/aggregationpb"
+import metricpb "github.com/m3db/m3/src/metrics/generated/proto/metricpb"
+import pipelinepb "github.com/m3db/m3/src/metrics/generated/proto/pipelinepb"
+import policypb "github.com/m3db/m3/src/metrics/generated/proto/policypb"
+
+import encoding_binary "encoding/binary"
+
+import io "io"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type MetricCategory int32
+
+const (
+	MetricCategory_UNKNOWN   MetricCategory = 0
+	MetricCategory_UNTIMED   MetricCategory = 1
+	MetricCategory_FORWARDED MetricCategory = 2
+	MetricCategory_TIMED     MetricCategory = 3
+)
+
+var MetricCategory_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "UNTIMED",
+	2: "FORWARDED",
+	3: "TIMED",
+}
+var MetricCategory_value = map[string]int32{
+	"UNKNOWN":   0,
+	"UNTIMED":   1,
+	"FORWARDED": 2,
+	"TIMED":     3,
+}
+
+func (x MetricCategory) String() string {
+	return proto.EnumName(MetricCategory_name, int32(x))
+}
+func (MetricCategory) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_checkpoint_77ad5683ee0f967c, []int{0}
+}
+
+type IDPrefixSuffixType int32
+
+const (
+	IDPrefixSuffixType_WITH_PREFIX_WITH_SUFFIX IDPrefixSuffixType = 0
+	IDPrefixSuffixType_NO_PREFIX_NO_SUFFIX     IDPrefixSuffixType = 1
+)
+
+var IDPrefixSuffixType_name = map[int32]string{
+	0: "WITH_PREFIX_WITH_SUFFIX",
+	1: "NO_PREFIX_NO_SUFFIX",
+}
+var IDPrefixSuffixType_value = map[string]int32{
+	"WITH_PREFIX_WITH_SUFFIX": 0,
+	"NO_PREFIX_NO_SUFFIX":     1,
+}
+
+func (x IDPrefixSuffixType) String() string {
+	return proto.EnumName(IDPrefixSuffixType_name, int32(x))
+}
+func (IDPrefixSuffixType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_checkpoint_77ad5683ee0f967c, []int{1}
+}
+
+type ShardCheckpoint struct {
+	Shard           uint32            `protobuf:"varint,1,opt,name=shard,proto3" json:"shard,omitempty"`
+	CheckpointNanos int64             `protobuf:"varint,2,opt,name=checkpoint_nanos,json=checkpointNanos,proto3" json:"checkpoint_nanos,omitempty"`
+	Entries         []EntryCheckpoint `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries"`
+}
+
+func (m *ShardCheckpoint) Reset()         { *m = ShardCheckpoint{} }
+func (m *ShardCheckpoint) String() string { return proto.CompactTextString(m) }
+func (*ShardCheckpoint) ProtoMessage()    {}
+func (*ShardCheckpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkpoint_77ad5683ee0f967c, []int{0}
+}
+func (m *ShardCheckpoint) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ShardCheckpoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ShardCheckpoint.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *ShardCheckpoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShardCheckpoint.Merge(dst, src)
+}
+func (m *ShardCheckpoint) XXX_Size() int {
+	return m.Size()
+}
+func (m *ShardCheckpoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShardCheckpoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShardCheckpoint proto.InternalMessageInfo
+
+func (m *ShardCheckpoint) GetShard() uint32 {
+	if m != nil {
+		return m.Shard
+	}
+	return 0
+}
+
+func (m *ShardCheckpoint) GetCheckpointNanos() int64 {
+	if m != nil {
+		return m.CheckpointNanos
+	}
+	return 0
+}
+
+func (m *ShardCheckpoint) GetEntries() []EntryCheckpoint {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type EntryCheckpoint struct {
+	MetricCategory      MetricCategory          `protobuf:"varint,1,opt,name=metric_category,json=metricCategory,proto3,enum=checkpointpb.MetricCategory" json:"metric_category,omitempty"`
+	MetricType          metricpb.MetricType     `protobuf:"varint,2,opt,name=metric_type,json=metricType,proto3,enum=metricpb.MetricType" json:"metric_type,omitempty"`
+	Id                  []byte                  `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	HasDefaultMetadatas bool                    `protobuf:"varint,4,opt,name=has_default_metadatas,json=hasDefaultMetadatas,proto3" json:"has_default_metadatas,omitempty"`
+	CutoverNanos        int64                   `protobuf:"varint,5,opt,name=cutover_nanos,json=cutoverNanos,proto3" json:"cutover_nanos,omitempty"`
+	Aggregations        []AggregationCheckpoint `protobuf:"bytes,6,rep,name=aggregations,proto3" json:"aggregations"`
+}
+
+func (m *EntryCheckpoint) Reset()         { *m = EntryCheckpoint{} }
+func (m *EntryCheckpoint) String() string { return proto.CompactTextString(m) }
+func (*EntryCheckpoint) ProtoMessage()    {}
+func (*EntryCheckpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkpoint_77ad5683ee0f967c, []int{1}
+}
+func (m *EntryCheckpoint) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *EntryCheckpoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_EntryCheckpoint.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *EntryCheckpoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EntryCheckpoint.Merge(dst, src)
+}
+func (m *EntryCheckpoint) XXX_Size() int {
+	return m.Size()
+}
+func (m *EntryCheckpoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_EntryCheckpoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EntryCheckpoint proto.InternalMessageInfo
+
+func (m *EntryCheckpoint) GetMetricCategory() MetricCategory {
+	if m != nil {
+		return m.MetricCategory
+	}
+	return MetricCategory_UNKNOWN
+}
+
+func (m *EntryCheckpoint) GetMetricType() metricpb.MetricType {
+	if m != nil {
+		return m.MetricType
+	}
+	return metricpb.MetricType_UNKNOWN
+}
+
+func (m *EntryCheckpoint) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *EntryCheckpoint) GetHasDefaultMetadatas() bool {
+	if m != nil {
+		return m.HasDefaultMetadatas
+	}
+	return false
+}
+
+func (m *EntryCheckpoint) GetCutoverNanos() int64 {
+	if m != nil {
+		return m.CutoverNanos
+	}
+	return 0
+}
+
+func (m *EntryCheckpoint) GetAggregations() []AggregationCheckpoint {
+	if m != nil {
+		return m.Aggregations
+	}
+	return nil
+}
+
+type AggregationCheckpoint struct {
+	AggregationId      aggregationpb.AggregationID `protobuf:"bytes,1,opt,name=aggregation_id,json=aggregationId,proto3" json:"aggregation_id"`
+	StoragePolicy      policypb.StoragePolicy      `protobuf:"bytes,2,opt,name=storage_policy,json=storagePolicy,proto3" json:"storage_policy"`
+	Pipeline           pipelinepb.AppliedPipeline  `protobuf:"bytes,3,opt,name=pipeline,proto3" json:"pipeline"`
+	NumForwardedTimes  int32                       `protobuf:"varint,4,opt,name=num_forwarded_times,json=numForwardedTimes,proto3" json:"num_forwarded_times,omitempty"`
+	IdPrefixSuffixType IDPrefixSuffixType          `protobuf:"varint,5,opt,name=id_prefix_suffix_type,json=idPrefixSuffixType,proto3,enum=checkpointpb.IDPrefixSuffixType" json:"id_prefix_suffix_type,omitempty"`
+	Elem               ElemSnapshot                `protobuf:"bytes,6,opt,name=elem,proto3" json:"elem"`
+}
+
+func (m *AggregationCheckpoint) Reset()         { *m = AggregationCheckpoint{} }
+func (m *AggregationCheckpoint) String() string { return proto.CompactTextString(m) }
+func (*AggregationCheckpoint) ProtoMessage()    {}
+func (*AggregationCheckpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkpoint_77ad5683ee0f967c, []int{2}
+}
+func (m *AggregationCheckpoint) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AggregationCheckpoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AggregationCheckpoint.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *AggregationCheckpoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AggregationCheckpoint.Merge(dst, src)
+}
+func (m *AggregationCheckpoint) XXX_Size() int {
+	return m.Size()
+}
+func (m *AggregationCheckpoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_AggregationCheckpoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AggregationCheckpoint proto.InternalMessageInfo
+
+func (m *AggregationCheckpoint) GetAggregationId() aggregationpb.AggregationID {
+	if m != nil {
+		return m.AggregationId
+	}
+	return aggregationpb.AggregationID{}
+}
+
+func (m *AggregationCheckpoint) GetStoragePolicy() policypb.StoragePolicy {
+	if m != nil {
+		return m.StoragePolicy
+	}
+	return policypb.StoragePolicy{}
+}
+
+func (m *AggregationCheckpoint) GetPipeline() pipelinepb.AppliedPipeline {
+	if m != nil {
+		return m.Pipeline
+	}
+	return pipelinepb.AppliedPipeline{}
+}
+
+func (m *AggregationCheckpoint) GetNumForwardedTimes() int32 {
+	if m != nil {
+		return m.NumForwardedTimes
+	}
+	return 0
+}
+
+func (m *AggregationCheckpoint) GetIdPrefixSuffixType() IDPrefixSuffixType {
+	if m != nil {
+		return m.IdPrefixSuffixType
+	}
+	return IDPrefixSuffixType_WITH_PREFIX_WITH_SUFFIX
+}
+
+func (m *AggregationCheckpoint) GetElem() ElemSnapshot {
+	if m != nil {
+		return m.Elem
+	}
+	return ElemSnapshot{}
+}
+
+type ElemSnapshot struct {
+	LastConsumedAtNanos  int64            `protobuf:"varint,1,opt,name=last_consumed_at_nanos,json=lastConsumedAtNanos,proto3" json:"last_consumed_at_nanos,omitempty"`
+	LastConsumedValues   []float64        `protobuf:"fixed64,2,rep,packed,name=last_consumed_values,json=lastConsumedValues,proto3" json:"last_consumed_values,omitempty"`
+	LastCumulativeValues []float64        `protobuf:"fixed64,3,rep,packed,name=last_cumulative_values,json=lastCumulativeValues,proto3" json:"last_cumulative_values,omitempty"`
+	DeferredAtNanos      int64            `protobuf:"varint,4,opt,name=deferred_at_nanos,json=deferredAtNanos,proto3" json:"deferred_at_nanos,omitempty"`
+	DeferredValues       []float64        `protobuf:"fixed64,5,rep,packed,name=deferred_values,json=deferredValues,proto3" json:"deferred_values,omitempty"`
+	Windows              []WindowSnapshot `protobuf:"bytes,6,rep,name=windows,proto3" json:"windows"`
+}
+
+func (m *ElemSnapshot) Reset()         { *m = ElemSnapshot{} }
+func (m *ElemSnapshot) String() string { return proto.CompactTextString(m) }
+func (*ElemSnapshot) ProtoMessage()    {}
+func (*ElemSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkpoint_77ad5683ee0f967c, []int{3}
+}
+func (m *ElemSnapshot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ElemSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ElemSnapshot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *ElemSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ElemSnapshot.Merge(dst, src)
+}
+func (m *ElemSnapshot) XXX_Size() int {
+	return m.Size()
+}
+func (m *ElemSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_ElemSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ElemSnapshot proto.InternalMessageInfo
+
+func (m *ElemSnapshot) GetLastConsumedAtNanos() int64 {
+	if m != nil {
+		return m.LastConsumedAtNanos
+	}
+	return 0
+}
+
+func (m *ElemSnapshot) GetLastConsumedValues() []float64 {
+	if m != nil {
+		return m.LastConsumedValues
+	}
+	return nil
+}
+
+func (m *ElemSnapshot) GetLastCumulativeValues() []float64 {
+	if m != nil {
+		return m.LastCumulativeValues
+	}
+	return nil
+}
+
+func (m *ElemSnapshot) GetDeferredAtNanos() int64 {
+	if m != nil {
+		return m.DeferredAtNanos
+	}
+	return 0
+}
+
+func (m *ElemSnapshot) GetDeferredValues() []float64 {
+	if m != nil {
+		return m.DeferredValues
+	}
+	return nil
+}
+
+func (m *ElemSnapshot) GetWindows() []WindowSnapshot {
+	if m != nil {
+		return m.Windows
+	}
+	return nil
+}
+
+type WindowSnapshot struct {
+	StartAtNanos int64               `protobuf:"varint,1,opt,name=start_at_nanos,json=startAtNanos,proto3" json:"start_at_nanos,omitempty"`
+	SourcesSeen  []byte              `protobuf:"bytes,2,opt,name=sources_seen,json=sourcesSeen,proto3" json:"sources_seen,omitempty"`
+	Aggregation  AggregationSnapshot `protobuf:"bytes,3,opt,name=aggregation,proto3" json:"aggregation"`
+}
+
+func (m *WindowSnapshot) Reset()         { *m = WindowSnapshot{} }
+func (m *WindowSnapshot) String() string { return proto.CompactTextString(m) }
+func (*WindowSnapshot) ProtoMessage()    {}
+func (*WindowSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkpoint_77ad5683ee0f967c, []int{4}
+}
+func (m *WindowSnapshot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WindowSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WindowSnapshot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *WindowSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WindowSnapshot.Merge(dst, src)
+}
+func (m *WindowSnapshot) XXX_Size() int {
+	return m.Size()
+}
+func (m *WindowSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_WindowSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WindowSnapshot proto.InternalMessageInfo
+
+func (m *WindowSnapshot) GetStartAtNanos() int64 {
+	if m != nil {
+		return m.StartAtNanos
+	}
+	return 0
+}
+
+func (m *WindowSnapshot) GetSourcesSeen() []byte {
+	if m != nil {
+		return m.SourcesSeen
+	}
+	return nil
+}
+
+func (m *WindowSnapshot) GetAggregation() AggregationSnapshot {
+	if m != nil {
+		return m.Aggregation
+	}
+	return AggregationSnapshot{}
+}
+
+type AggregationSnapshot struct {
+	Counter *CounterSnapshot `protobuf:"bytes,1,opt,name=counter,proto3" json:"counter,omitempty"`
+	Timer   *TimerSnapshot   `protobuf:"bytes,2,opt,name=timer,proto3" json:"timer,omitempty"`
+	Gauge   *GaugeSnapshot   `protobuf:"bytes,3,opt,name=gauge,proto3" json:"gauge,omitempty"`
+}
+
+func (m *AggregationSnapshot) Reset()         { *m = AggregationSnapshot{} }
+func (m *AggregationSnapshot) String() string { return proto.CompactTextString(m) }
+func (*AggregationSnapshot) ProtoMessage()    {}
+func (*AggregationSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkpoint_77ad5683ee0f967c, []int{5}
+}
+func (m *AggregationSnapshot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AggregationSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AggregationSnapshot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *AggregationSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AggregationSnapshot.Merge(dst, src)
+}
+func (m *AggregationSnapshot) XXX_Size() int {
+	return m.Size()
+}
+func (m *AggregationSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_AggregationSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AggregationSnapshot proto.InternalMessageInfo
+
+func (m *AggregationSnapshot) GetCounter() *CounterSnapshot {
+	if m != nil {
+		return m.Counter
+	}
+	return nil
+}
+
+func (m *AggregationSnapshot) GetTimer() *TimerSnapshot {
+	if m != nil {
+		return m.Timer
+	}
+	return nil
+}
+
+func (m *AggregationSnapshot) GetGauge() *GaugeSnapshot {
+	if m != nil {
+		return m.Gauge
+	}
+	return nil
+}
+
+type CounterSnapshot struct {
+	Sum   int64 `protobuf:"varint,1,opt,name=sum,proto3" json:"sum,omitempty"`
+	SumSq int64 `protobuf:"varint,2,opt,name=sum_sq,json=sumSq,proto3" json:"sum_sq,omitempty"`
+	Count int64 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Max   int64 `protobuf:"varint,4,opt,name=max,proto3" json:"max,omitempty"`
+	Min   int64 `protobuf:"varint,5,opt,name=min,proto3" json:"min,omitempty"`
+}
+
+func (m *CounterSnapshot) Reset()         { *m = CounterSnapshot{} }
+func (m *CounterSnapshot) String() string { return proto.CompactTextString(m) }
+func (*CounterSnapshot) ProtoMessage()    {}
+func (*CounterSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkpoint_77ad5683ee0f967c, []int{6}
+}
+func (m *CounterSnapshot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CounterSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CounterSnapshot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *CounterSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CounterSnapshot.Merge(dst, src)
+}
+func (m *CounterSnapshot) XXX_Size() int {
+	return m.Size()
+}
+func (m *CounterSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_CounterSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CounterSnapshot proto.InternalMessageInfo
+
+func (m *CounterSnapshot) GetSum() int64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *CounterSnapshot) GetSumSq() int64 {
+	if m != nil {
+		return m.SumSq
+	}
+	return 0
+}
+
+func (m *CounterSnapshot) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *CounterSnapshot) GetMax() int64 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+func (m *CounterSnapshot) GetMin() int64 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+type TimerSnapshot struct {
+	Count  int64          `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Sum    float64        `protobuf:"fixed64,2,opt,name=sum,proto3" json:"sum,omitempty"`
+	SumSq  float64        `protobuf:"fixed64,3,opt,name=sum_sq,json=sumSq,proto3" json:"sum_sq,omitempty"`
+	Stream StreamSnapshot `protobuf:"bytes,4,opt,name=stream,proto3" json:"stream"`
+}
+
+func (m *TimerSnapshot) Reset()         { *m = TimerSnapshot{} }
+func (m *TimerSnapshot) String() string { return proto.CompactTextString(m) }
+func (*TimerSnapshot) ProtoMessage()    {}
+func (*TimerSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkpoint_77ad5683ee0f967c, []int{7}
+}
+func (m *TimerSnapshot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TimerSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TimerSnapshot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *TimerSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TimerSnapshot.Merge(dst, src)
+}
+func (m *TimerSnapshot) XXX_Size() int {
+	return m.Size()
+}
+func (m *TimerSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_TimerSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TimerSnapshot proto.InternalMessageInfo
+
+func (m *TimerSnapshot) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *TimerSnapshot) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *TimerSnapshot) GetSumSq() float64 {
+	if m != nil {
+		return m.SumSq
+	}
+	return 0
+}
+
+func (m *TimerSnapshot) GetStream() StreamSnapshot {
+	if m != nil {
+		return m.Stream
+	}
+	return StreamSnapshot{}
+}
+
+type StreamSnapshot struct {
+	NumValues int64            `protobuf:"varint,1,opt,name=num_values,json=numValues,proto3" json:"num_values,omitempty"`
+	Samples   []SampleSnapshot `protobuf:"bytes,2,rep,name=samples,proto3" json:"samples"`
+}
+
+func (m *StreamSnapshot) Reset()         { *m = StreamSnapshot{} }
+func (m *StreamSnapshot) String() string { return proto.CompactTextString(m) }
+func (*StreamSnapshot) ProtoMessage()    {}
+func (*StreamSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkpoint_77ad5683ee0f967c, []int{8}
+}
+func (m *StreamSnapshot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StreamSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StreamSnapshot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *StreamSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamSnapshot.Merge(dst, src)
+}
+func (m *StreamSnapshot) XXX_Size() int {
+	return m.Size()
+}
+func (m *StreamSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamSnapshot proto.InternalMessageInfo
+
+func (m *StreamSnapshot) GetNumValues() int64 {
+	if m != nil {
+		return m.NumValues
+	}
+	return 0
+}
+
+func (m *StreamSnapshot) GetSamples() []SampleSnapshot {
+	if m != nil {
+		return m.Samples
+	}
+	return nil
+}
+
+type SampleSnapshot struct {
+	Value    float64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	NumRanks int64   `protobuf:"varint,2,opt,name=num_ranks,json=numRanks,proto3" json:"num_ranks,omitempty"`
+	Delta    int64   `protobuf:"varint,3,opt,name=delta,proto3" json:"delta,omitempty"`
+}
+
+func (m *SampleSnapshot) Reset()         { *m = SampleSnapshot{} }
+func (m *SampleSnapshot) String() string { return proto.CompactTextString(m) }
+func (*SampleSnapshot) ProtoMessage()    {}
+func (*SampleSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkpoint_77ad5683ee0f967c, []int{9}
+}
+func (m *SampleSnapshot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SampleSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SampleSnapshot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *SampleSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SampleSnapshot.Merge(dst, src)
+}
+func (m *SampleSnapshot) XXX_Size() int {
+	return m.Size()
+}
+func (m *SampleSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_SampleSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SampleSnapshot proto.InternalMessageInfo
+
+func (m *SampleSnapshot) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *SampleSnapshot) GetNumRanks() int64 {
+	if m != nil {
+		return m.NumRanks
+	}
+	return 0
+}
+
+func (m *SampleSnapshot) GetDelta() int64 {
+	if m != nil {
+		return m.Delta
+	}
+	return 0
+}
+
+type GaugeSnapshot struct {
+	Last  float64 `protobuf:"fixed64,1,opt,name=last,proto3" json:"last,omitempty"`
+	Sum   float64 `protobuf:"fixed64,2,opt,name=sum,proto3" json:"sum,omitempty"`
+	SumSq float64 `protobuf:"fixed64,3,opt,name=sum_sq,json=sumSq,proto3" json:"sum_sq,omitempty"`
+	Count int64   `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	Max   float64 `protobuf:"fixed64,5,opt,name=max,proto3" json:"max,omitempty"`
+	Min   float64 `protobuf:"fixed64,6,opt,name=min,proto3" json:"min,omitempty"`
+}
+
+func (m *GaugeSnapshot) Reset()         { *m = GaugeSnapshot{} }
+func (m *GaugeSnapshot) String() string { return proto.CompactTextString(m) }
+func (*GaugeSnapshot) ProtoMessage()    {}
+func (*GaugeSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_checkpoint_77ad5683ee0f967c, []int{10}
+}
+func (m *GaugeSnapshot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GaugeSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GaugeSnapshot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *GaugeSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GaugeSnapshot.Merge(dst, src)
+}
+func (m *GaugeSnapshot) XXX_Size() int {
+	return m.Size()
+}
+func (m *GaugeSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_GaugeSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GaugeSnapshot proto.InternalMessageInfo
+
+func (m *GaugeSnapshot) GetLast() float64 {
+	if m != nil {
+		return m.Last
+	}
+	return 0
+}
+
+func (m *GaugeSnapshot) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *GaugeSnapshot) GetSumSq() float64 {
+	if m != nil {
+		return m.SumSq
+	}
+	return 0
+}
+
+func (m *GaugeSnapshot) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *GaugeSnapshot) GetMax() float64 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+func (m *GaugeSnapshot) GetMin() float64 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ShardCheckpoint)(nil), "checkpointpb.ShardCheckpoint")
+	proto.RegisterType((*EntryCheckpoint)(nil), "checkpointpb.EntryCheckpoint")
+	proto.RegisterType((*AggregationCheckpoint)(nil), "checkpointpb.AggregationCheckpoint")
+	proto.RegisterType((*ElemSnapshot)(nil), "checkpointpb.ElemSnapshot")
+	proto.RegisterType((*WindowSnapshot)(nil), "checkpointpb.WindowSnapshot")
+	proto.RegisterType((*AggregationSnapshot)(nil), "checkpointpb.AggregationSnapshot")
+	proto.RegisterType((*CounterSnapshot)(nil), "checkpointpb.CounterSnapshot")
+	proto.RegisterType((*TimerSnapshot)(nil), "checkpointpb.TimerSnapshot")
+	proto.RegisterType((*StreamSnapshot)(nil), "checkpointpb.StreamSnapshot")
+	proto.RegisterType((*SampleSnapshot)(nil), "checkpointpb.SampleSnapshot")
+	proto.RegisterType((*GaugeSnapshot)(nil), "checkpointpb.GaugeSnapshot")
+	proto.RegisterEnum("checkpointpb.MetricCategory", MetricCategory_name, MetricCategory_value)
+	proto.RegisterEnum("checkpointpb.IDPrefixSuffixType", IDPrefixSuffixType_name, IDPrefixSuffixType_value)
+}
+func (m *ShardCheckpoint) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ShardCheckpoint) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Shard != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Shard))
+	}
+	if m.CheckpointNanos != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.CheckpointNanos))
+	}
+	if len(m.Entries) > 0 {
+		for _, msg := range m.Entries {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintCheckpoint(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *EntryCheckpoint) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EntryCheckpoint) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.MetricCategory != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.MetricCategory))
+	}
+	if m.MetricType != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.MetricType))
+	}
+	if len(m.Id) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if m.HasDefaultMetadatas {
+		dAtA[i] = 0x20
+		i++
+		if m.HasDefaultMetadatas {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.CutoverNanos != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.CutoverNanos))
+	}
+	if len(m.Aggregations) > 0 {
+		for _, msg := range m.Aggregations {
+			dAtA[i] = 0x32
+			i++
+			i = encodeVarintCheckpoint(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *AggregationCheckpoint) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AggregationCheckpoint) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	dAtA[i] = 0xa
+	i++
+	i = encodeVarintCheckpoint(dAtA, i, uint64(m.AggregationId.Size()))
+	n1, err := m.AggregationId.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n1
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintCheckpoint(dAtA, i, uint64(m.StoragePolicy.Size()))
+	n2, err := m.StoragePolicy.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n2
+	dAtA[i] = 0x1a
+	i++
+	i = encodeVarintCheckpoint(dAtA, i, uint64(m.Pipeline.Size()))
+	n3, err := m.Pipeline.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n3
+	if m.NumForwardedTimes != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.NumForwardedTimes))
+	}
+	if m.IdPrefixSuffixType != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.IdPrefixSuffixType))
+	}
+	dAtA[i] = 0x32
+	i++
+	i = encodeVarintCheckpoint(dAtA, i, uint64(m.Elem.Size()))
+	n4, err := m.Elem.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n4
+	return i, nil
+}
+
+func (m *ElemSnapshot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ElemSnapshot) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.LastConsumedAtNanos != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.LastConsumedAtNanos))
+	}
+	if len(m.LastConsumedValues) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(len(m.LastConsumedValues)*8))
+		for _, num := range m.LastConsumedValues {
+			f5 := math.Float64bits(float64(num))
+			encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(f5))
+			i += 8
+		}
+	}
+	if len(m.LastCumulativeValues) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(len(m.LastCumulativeValues)*8))
+		for _, num := range m.LastCumulativeValues {
+			f6 := math.Float64bits(float64(num))
+			encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(f6))
+			i += 8
+		}
+	}
+	if m.DeferredAtNanos != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.DeferredAtNanos))
+	}
+	if len(m.DeferredValues) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(len(m.DeferredValues)*8))
+		for _, num := range m.DeferredValues {
+			f7 := math.Float64bits(float64(num))
+			encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(f7))
+			i += 8
+		}
+	}
+	if len(m.Windows) > 0 {
+		for _, msg := range m.Windows {
+			dAtA[i] = 0x32
+			i++
+			i = encodeVarintCheckpoint(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *WindowSnapshot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WindowSnapshot) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.StartAtNanos != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.StartAtNanos))
+	}
+	if len(m.SourcesSeen) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(len(m.SourcesSeen)))
+		i += copy(dAtA[i:], m.SourcesSeen)
+	}
+	dAtA[i] = 0x1a
+	i++
+	i = encodeVarintCheckpoint(dAtA, i, uint64(m.Aggregation.Size()))
+	n8, err := m.Aggregation.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n8
+	return i, nil
+}
+
+func (m *AggregationSnapshot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AggregationSnapshot) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Counter != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Counter.Size()))
+		n9, err := m.Counter.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
+	}
+	if m.Timer != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Timer.Size()))
+		n10, err := m.Timer.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	if m.Gauge != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Gauge.Size()))
+		n11, err := m.Gauge.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	return i, nil
+}
+
+func (m *CounterSnapshot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CounterSnapshot) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Sum != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Sum))
+	}
+	if m.SumSq != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.SumSq))
+	}
+	if m.Count != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Count))
+	}
+	if m.Max != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Max))
+	}
+	if m.Min != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Min))
+	}
+	return i, nil
+}
+
+func (m *TimerSnapshot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TimerSnapshot) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Count != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Count))
+	}
+	if m.Sum != 0 {
+		dAtA[i] = 0x11
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Sum))))
+		i += 8
+	}
+	if m.SumSq != 0 {
+		dAtA[i] = 0x19
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.SumSq))))
+		i += 8
+	}
+	dAtA[i] = 0x22
+	i++
+	i = encodeVarintCheckpoint(dAtA, i, uint64(m.Stream.Size()))
+	n12, err := m.Stream.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n12
+	return i, nil
+}
+
+func (m *StreamSnapshot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StreamSnapshot) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.NumValues != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.NumValues))
+	}
+	if len(m.Samples) > 0 {
+		for _, msg := range m.Samples {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintCheckpoint(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *SampleSnapshot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SampleSnapshot) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Value != 0 {
+		dAtA[i] = 0x9
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+		i += 8
+	}
+	if m.NumRanks != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.NumRanks))
+	}
+	if m.Delta != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Delta))
+	}
+	return i, nil
+}
+
+func (m *GaugeSnapshot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GaugeSnapshot) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Last != 0 {
+		dAtA[i] = 0x9
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Last))))
+		i += 8
+	}
+	if m.Sum != 0 {
+		dAtA[i] = 0x11
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Sum))))
+		i += 8
+	}
+	if m.SumSq != 0 {
+		dAtA[i] = 0x19
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.SumSq))))
+		i += 8
+	}
+	if m.Count != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintCheckpoint(dAtA, i, uint64(m.Count))
+	}
+	if m.Max != 0 {
+		dAtA[i] = 0x29
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Max))))
+		i += 8
+	}
+	if m.Min != 0 {
+		dAtA[i] = 0x31
+		i++
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Min))))
+		i += 8
+	}
+	return i, nil
+}
+
+func encodeVarintCheckpoint(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *ShardCheckpoint) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Shard != 0 {
+		n += 1 + sovCheckpoint(uint64(m.Shard))
+	}
+	if m.CheckpointNanos != 0 {
+		n += 1 + sovCheckpoint(uint64(m.CheckpointNanos))
+	}
+	if len(m.Entries) > 0 {
+		for _, e := range m.Entries {
+			l = e.Size()
+			n += 1 + l + sovCheckpoint(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *EntryCheckpoint) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.MetricCategory != 0 {
+		n += 1 + sovCheckpoint(uint64(m.MetricCategory))
+	}
+	if m.MetricType != 0 {
+		n += 1 + sovCheckpoint(uint64(m.MetricType))
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovCheckpoint(uint64(l))
+	}
+	if m.HasDefaultMetadatas {
+		n += 2
+	}
+	if m.CutoverNanos != 0 {
+		n += 1 + sovCheckpoint(uint64(m.CutoverNanos))
+	}
+	if len(m.Aggregations) > 0 {
+		for _, e := range m.Aggregations {
+			l = e.Size()
+			n += 1 + l + sovCheckpoint(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *AggregationCheckpoint) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.AggregationId.Size()
+	n += 1 + l + sovCheckpoint(uint64(l))
+	l = m.StoragePolicy.Size()
+	n += 1 + l + sovCheckpoint(uint64(l))
+	l = m.Pipeline.Size()
+	n += 1 + l + sovCheckpoint(uint64(l))
+	if m.NumForwardedTimes != 0 {
+		n += 1 + sovCheckpoint(uint64(m.NumForwardedTimes))
+	}
+	if m.IdPrefixSuffixType != 0 {
+		n += 1 + sovCheckpoint(uint64(m.IdPrefixSuffixType))
+	}
+	l = m.Elem.Size()
+	n += 1 + l + sovCheckpoint(uint64(l))
+	return n
+}
+
+func (m *ElemSnapshot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.LastConsumedAtNanos != 0 {
+		n += 1 + sovCheckpoint(uint64(m.LastConsumedAtNanos))
+	}
+	if len(m.LastConsumedValues) > 0 {
+		n += 1 + sovCheckpoint(uint64(len(m.LastConsumedValues)*8)) + len(m.LastConsumedValues)*8
+	}
+	if len(m.LastCumulativeValues) > 0 {
+		n += 1 + sovCheckpoint(uint64(len(m.LastCumulativeValues)*8)) + len(m.LastCumulativeValues)*8
+	}
+	if m.DeferredAtNanos != 0 {
+		n += 1 + sovCheckpoint(uint64(m.DeferredAtNanos))
+	}
+	if len(m.DeferredValues) > 0 {
+		n += 1 + sovCheckpoint(uint64(len(m.DeferredValues)*8)) + len(m.DeferredValues)*8
+	}
+	if len(m.Windows) > 0 {
+		for _, e := range m.Windows {
+			l = e.Size()
+			n += 1 + l + sovCheckpoint(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *WindowSnapshot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.StartAtNanos != 0 {
+		n += 1 + sovCheckpoint(uint64(m.StartAtNanos))
+	}
+	l = len(m.SourcesSeen)
+	if l > 0 {
+		n += 1 + l + sovCheckpoint(uint64(l))
+	}
+	l = m.Aggregation.Size()
+	n += 1 + l + sovCheckpoint(uint64(l))
+	return n
+}
+
+func (m *AggregationSnapshot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Counter != nil {
+		l = m.Counter.Size()
+		n += 1 + l + sovCheckpoint(uint64(l))
+	}
+	if m.Timer != nil {
+		l = m.Timer.Size()
+		n += 1 + l + sovCheckpoint(uint64(l))
+	}
+	if m.Gauge != nil {
+		l = m.Gauge.Size()
+		n += 1 + l + sovCheckpoint(uint64(l))
+	}
+	return n
+}
+
+func (m *CounterSnapshot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Sum != 0 {
+		n += 1 + sovCheckpoint(uint64(m.Sum))
+	}
+	if m.SumSq != 0 {
+		n += 1 + sovCheckpoint(uint64(m.SumSq))
+	}
+	if m.Count != 0 {
+		n += 1 + sovCheckpoint(uint64(m.Count))
+	}
+	if m.Max != 0 {
+		n += 1 + sovCheckpoint(uint64(m.Max))
+	}
+	if m.Min != 0 {
+		n += 1 + sovCheckpoint(uint64(m.Min))
+	}
+	return n
+}
+
+func (m *TimerSnapshot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Count != 0 {
+		n += 1 + sovCheckpoint(uint64(m.Count))
+	}
+	if m.Sum != 0 {
+		n += 9
+	}
+	if m.SumSq != 0 {
+		n += 9
+	}
+	l = m.Stream.Size()
+	n += 1 + l + sovCheckpoint(uint64(l))
+	return n
+}
+
+func (m *StreamSnapshot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.NumValues != 0 {
+		n += 1 + sovCheckpoint(uint64(m.NumValues))
+	}
+	if len(m.Samples) > 0 {
+		for _, e := range m.Samples {
+			l = e.Size()
+			n += 1 + l + sovCheckpoint(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SampleSnapshot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Value != 0 {
+		n += 9
+	}
+	if m.NumRanks != 0 {
+		n += 1 + sovCheckpoint(uint64(m.NumRanks))
+	}
+	if m.Delta != 0 {
+		n += 1 + sovCheckpoint(uint64(m.Delta))
+	}
+	return n
+}
+
+func (m *GaugeSnapshot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Last != 0 {
+		n += 9
+	}
+	if m.Sum != 0 {
+		n += 9
+	}
+	if m.SumSq != 0 {
+		n += 9
+	}
+	if m.Count != 0 {
+		n += 1 + sovCheckpoint(uint64(m.Count))
+	}
+	if m.Max != 0 {
+		n += 9
+	}
+	if m.Min != 0 {
+		n += 9
+	}
+	return n
+}
+
+func sovCheckpoint(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozCheckpoint(x uint64) (n int) {
+	return sovCheckpoint(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *ShardCheckpoint) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheckpoint
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ShardCheckpoint: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ShardCheckpoint: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Shard", wireType)
+			}
+			m.Shard = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Shard |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CheckpointNanos", wireType)
+			}
+			m.CheckpointNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CheckpointNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Entries", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Entries = append(m.Entries, EntryCheckpoint{})
+			if err := m.Entries[len(m.Entries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheckpoint(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EntryCheckpoint) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheckpoint
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EntryCheckpoint: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EntryCheckpoint: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricCategory", wireType)
+			}
+			m.MetricCategory = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MetricCategory |= (MetricCategory(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricType", wireType)
+			}
+			m.MetricType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MetricType |= (metricpb.MetricType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HasDefaultMetadatas", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.HasDefaultMetadatas = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CutoverNanos", wireType)
+			}
+			m.CutoverNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CutoverNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Aggregations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Aggregations = append(m.Aggregations, AggregationCheckpoint{})
+			if err := m.Aggregations[len(m.Aggregations)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheckpoint(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AggregationCheckpoint) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheckpoint
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AggregationCheckpoint: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AggregationCheckpoint: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AggregationId", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.AggregationId.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoragePolicy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.StoragePolicy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pipeline", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Pipeline.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NumForwardedTimes", wireType)
+			}
+			m.NumForwardedTimes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NumForwardedTimes |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IdPrefixSuffixType", wireType)
+			}
+			m.IdPrefixSuffixType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.IdPrefixSuffixType |= (IDPrefixSuffixType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Elem", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Elem.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheckpoint(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ElemSnapshot) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheckpoint
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ElemSnapshot: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ElemSnapshot: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastConsumedAtNanos", wireType)
+			}
+			m.LastConsumedAtNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastConsumedAtNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+				iNdEx += 8
+				v2 := float64(math.Float64frombits(v))
+				m.LastConsumedValues = append(m.LastConsumedValues, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowCheckpoint
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthCheckpoint
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				elementCount = packedLen / 8
+				if elementCount != 0 && len(m.LastConsumedValues) == 0 {
+					m.LastConsumedValues = make([]float64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					v2 := float64(math.Float64frombits(v))
+					m.LastConsumedValues = append(m.LastConsumedValues, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastConsumedValues", wireType)
+			}
+		case 3:
+			if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+				iNdEx += 8
+				v2 := float64(math.Float64frombits(v))
+				m.LastCumulativeValues = append(m.LastCumulativeValues, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowCheckpoint
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthCheckpoint
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				elementCount = packedLen / 8
+				if elementCount != 0 && len(m.LastCumulativeValues) == 0 {
+					m.LastCumulativeValues = make([]float64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					v2 := float64(math.Float64frombits(v))
+					m.LastCumulativeValues = append(m.LastCumulativeValues, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastCumulativeValues", wireType)
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeferredAtNanos", wireType)
+			}
+			m.DeferredAtNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DeferredAtNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+				iNdEx += 8
+				v2 := float64(math.Float64frombits(v))
+				m.DeferredValues = append(m.DeferredValues, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowCheckpoint
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthCheckpoint
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				elementCount = packedLen / 8
+				if elementCount != 0 && len(m.DeferredValues) == 0 {
+					m.DeferredValues = make([]float64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					v2 := float64(math.Float64frombits(v))
+					m.DeferredValues = append(m.DeferredValues, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeferredValues", wireType)
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Windows", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Windows = append(m.Windows, WindowSnapshot{})
+			if err := m.Windows[len(m.Windows)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheckpoint(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WindowSnapshot) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheckpoint
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WindowSnapshot: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WindowSnapshot: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartAtNanos", wireType)
+			}
+			m.StartAtNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartAtNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SourcesSeen", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SourcesSeen = append(m.SourcesSeen[:0], dAtA[iNdEx:postIndex]...)
+			if m.SourcesSeen == nil {
+				m.SourcesSeen = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Aggregation", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Aggregation.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheckpoint(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AggregationSnapshot) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheckpoint
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AggregationSnapshot: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AggregationSnapshot: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Counter", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Counter == nil {
+				m.Counter = &CounterSnapshot{}
+			}
+			if err := m.Counter.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timer", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Timer == nil {
+				m.Timer = &TimerSnapshot{}
+			}
+			if err := m.Timer.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Gauge", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Gauge == nil {
+				m.Gauge = &GaugeSnapshot{}
+			}
+			if err := m.Gauge.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheckpoint(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CounterSnapshot) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheckpoint
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CounterSnapshot: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CounterSnapshot: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sum", wireType)
+			}
+			m.Sum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sum |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SumSq", wireType)
+			}
+			m.SumSq = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SumSq |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Max", wireType)
+			}
+			m.Max = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Max |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Min", wireType)
+			}
+			m.Min = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Min |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheckpoint(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TimerSnapshot) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheckpoint
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TimerSnapshot: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TimerSnapshot: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sum", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Sum = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SumSq", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.SumSq = float64(math.Float64frombits(v))
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stream", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Stream.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheckpoint(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StreamSnapshot) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheckpoint
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StreamSnapshot: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StreamSnapshot: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NumValues", wireType)
+			}
+			m.NumValues = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NumValues |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Samples = append(m.Samples, SampleSnapshot{})
+			if err := m.Samples[len(m.Samples)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheckpoint(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SampleSnapshot) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheckpoint
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SampleSnapshot: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SampleSnapshot: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = float64(math.Float64frombits(v))
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NumRanks", wireType)
+			}
+			m.NumRanks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NumRanks |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Delta", wireType)
+			}
+			m.Delta = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Delta |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheckpoint(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GaugeSnapshot) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCheckpoint
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GaugeSnapshot: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GaugeSnapshot: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Last", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Last = float64(math.Float64frombits(v))
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sum", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Sum = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SumSq", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.SumSq = float64(math.Float64frombits(v))
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Max", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Max = float64(math.Float64frombits(v))
+		case 6:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Min", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Min = float64(math.Float64frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCheckpoint(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCheckpoint
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipCheckpoint(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowCheckpoint
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowCheckpoint
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthCheckpoint
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowCheckpoint
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipCheckpoint(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthCheckpoint = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowCheckpoint   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() {
+	proto.RegisterFile("github.com/m3db/m3/src/aggregator/generated/proto/checkpointpb/checkpoint.proto", fileDescriptor_checkpoint_77ad5683ee0f967c)
+}
+
+var fileDescriptor_checkpoint_77ad5683ee0f967c = []byte{
+	// 1180 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0x4d, 0x6f, 0x23, 0x45,
+	0x13, 0xf6, 0xf8, 0x2b, 0x49, 0xf9, 0x23, 0xde, 0x4e, 0xf2, 0xc6, 0x4a, 0x76, 0xfd, 0x7a, 0xbd,
+	0x48, 0x98, 0x48, 0xd8, 0xe0, 0x2c, 0x42, 0x42, 0x2c, 0x52, 0x36, 0x4e, 0x58, 0x83, 0xe2, 0x44,
+	0xe3, 0x2c, 0x09, 0x5c, 0x46, 0x6d, 0x4f, 0xdb, 0x1e, 0xad, 0xe7, 0x63, 0xa7, 0x7b, 0x92, 0xcd,
+	0x1f, 0xe0, 0xc0, 0x09, 0xfe, 0x02, 0xe2, 0x27, 0x70, 0xe6, 0xca, 0x1e, 0xf7, 0xc8, 0x09, 0xa1,
+	0xe4, 0x8f, 0xa0, 0xee, 0xe9, 0xb6, 0x67, 0x26, 0x59, 0x89, 0x85, 0x93, 0xbb, 0xeb, 0xa9, 0xa7,
+	0xaa, 0xa6, 0xea, 0xa9, 0x19, 0xc3, 0xf1, 0xc4, 0x62, 0xd3, 0x60, 0xd8, 0x1a, 0xb9, 0x76, 0xdb,
+	0xde, 0x35, 0x87, 0x6d, 0x7b, 0xb7, 0x4d, 0xfd, 0x51, 0x1b, 0x4f, 0x26, 0x3e, 0x99, 0x60, 0xe6,
+	0xfa, 0xed, 0x09, 0x71, 0x88, 0x8f, 0x19, 0x31, 0xdb, 0x9e, 0xef, 0x32, 0xb7, 0x3d, 0x9a, 0x92,
+	0xd1, 0x0b, 0xcf, 0xb5, 0x1c, 0xe6, 0x0d, 0x23, 0x97, 0x96, 0x40, 0x51, 0x31, 0x0a, 0x6f, 0x7d,
+	0x18, 0x09, 0x3f, 0x71, 0x27, 0x6e, 0x18, 0x62, 0x18, 0x8c, 0xc5, 0x2d, 0x8c, 0xc7, 0x4f, 0x21,
+	0x79, 0xab, 0xff, 0x96, 0x6a, 0x6c, 0xc2, 0x7c, 0x6b, 0x44, 0x6f, 0x95, 0xa2, 0xaa, 0xb4, 0x5c,
+	0xc7, 0x1b, 0x46, 0x6f, 0x32, 0x5e, 0xf7, 0x1d, 0xe3, 0x85, 0x76, 0x6f, 0x28, 0x0f, 0x32, 0xca,
+	0xb3, 0x77, 0x8c, 0xe2, 0x59, 0x1e, 0x99, 0x59, 0x0e, 0xf1, 0x86, 0xf3, 0xe3, 0xbf, 0xac, 0xc7,
+	0x73, 0x67, 0xd6, 0xe8, 0xca, 0x1b, 0xca, 0x43, 0x18, 0xa5, 0xf1, 0x93, 0x06, 0xab, 0x83, 0x29,
+	0xf6, 0xcd, 0xfd, 0x79, 0xab, 0xd1, 0x3a, 0xe4, 0x28, 0x37, 0x55, 0xb5, 0xba, 0xd6, 0x2c, 0xe9,
+	0xe1, 0x05, 0x7d, 0x00, 0x95, 0xc5, 0x38, 0x0c, 0x07, 0x3b, 0x2e, 0xad, 0xa6, 0xeb, 0x5a, 0x33,
+	0xa3, 0xaf, 0x2e, 0xec, 0x7d, 0x6e, 0x46, 0x4f, 0x60, 0x89, 0x38, 0xcc, 0xb7, 0x08, 0xad, 0x66,
+	0xea, 0x99, 0x66, 0xa1, 0xf3, 0xa0, 0x15, 0x9d, 0x64, 0xeb, 0xc0, 0x61, 0xfe, 0xd5, 0x22, 0xe1,
+	0xd3, 0xec, 0xeb, 0x3f, 0xff, 0x9f, 0xd2, 0x15, 0xa7, 0xf1, 0x7b, 0x1a, 0x56, 0x13, 0x2e, 0xe8,
+	0x00, 0x56, 0xc3, 0x07, 0x33, 0x46, 0x98, 0x91, 0x89, 0xeb, 0x5f, 0x89, 0xea, 0xca, 0x9d, 0xfb,
+	0xf1, 0xd0, 0x47, 0xc2, 0x69, 0x5f, 0xfa, 0xe8, 0x65, 0x3b, 0x76, 0x47, 0x9f, 0x40, 0x41, 0x86,
+	0x61, 0x57, 0x1e, 0x11, 0xf5, 0x97, 0x3b, 0xeb, 0x2d, 0x35, 0x2b, 0x49, 0x3f, 0xbd, 0xf2, 0x88,
+	0x0e, 0xf6, 0xfc, 0x8c, 0xca, 0x90, 0xb6, 0xcc, 0x6a, 0xa6, 0xae, 0x35, 0x8b, 0x7a, 0xda, 0x32,
+	0x51, 0x07, 0x36, 0xa6, 0x98, 0x1a, 0x26, 0x19, 0xe3, 0x60, 0xc6, 0x0c, 0x9b, 0x30, 0x6c, 0x62,
+	0x86, 0x69, 0x35, 0x5b, 0xd7, 0x9a, 0xcb, 0xfa, 0xda, 0x14, 0xd3, 0x6e, 0x88, 0x1d, 0x29, 0x08,
+	0x3d, 0x82, 0xd2, 0x28, 0x60, 0xee, 0x05, 0xf1, 0x65, 0xf3, 0x72, 0xa2, 0x79, 0x45, 0x69, 0x0c,
+	0x3b, 0x77, 0x04, 0xc5, 0x88, 0xf2, 0x68, 0x35, 0x2f, 0xda, 0xf7, 0x28, 0xfe, 0x8c, 0x7b, 0x0b,
+	0x8f, 0x5b, 0x4d, 0x8c, 0xd1, 0x1b, 0x3f, 0x67, 0x60, 0xe3, 0x4e, 0x6f, 0xd4, 0x83, 0x72, 0xc4,
+	0xd3, 0xb0, 0xc2, 0x61, 0x17, 0x3a, 0xf7, 0x5b, 0xb1, 0x3d, 0x88, 0xe6, 0xea, 0x75, 0x65, 0x8e,
+	0x52, 0xc4, 0xa5, 0x67, 0xa2, 0x2e, 0x94, 0x29, 0x73, 0x7d, 0x3c, 0x21, 0x46, 0x28, 0x2d, 0xd1,
+	0xd6, 0x42, 0x67, 0xb3, 0xa5, 0x24, 0xd7, 0x1a, 0x84, 0xf8, 0x89, 0xb8, 0xab, 0x28, 0x34, 0x6a,
+	0x44, 0x4f, 0x60, 0x59, 0x09, 0x5c, 0x34, 0xba, 0xd0, 0xd9, 0x6e, 0x2d, 0xc4, 0xdf, 0xda, 0xf3,
+	0xbc, 0x99, 0x45, 0xcc, 0x13, 0x69, 0x91, 0x31, 0xe6, 0x14, 0xd4, 0x82, 0x35, 0x27, 0xb0, 0x8d,
+	0xb1, 0xeb, 0x5f, 0x62, 0xdf, 0x24, 0xa6, 0xc1, 0x2c, 0x9b, 0x84, 0xf3, 0xc8, 0xe9, 0xf7, 0x9c,
+	0xc0, 0x3e, 0x54, 0xc8, 0x29, 0x07, 0xd0, 0x00, 0x36, 0x2c, 0xd3, 0xf0, 0x7c, 0x32, 0xb6, 0x5e,
+	0x19, 0x34, 0x18, 0xf3, 0x1f, 0x21, 0x89, 0x9c, 0x90, 0x44, 0x3d, 0xde, 0xf1, 0x5e, 0xf7, 0x44,
+	0x78, 0x0e, 0x84, 0xa3, 0x90, 0x07, 0xb2, 0xcc, 0xa4, 0x0d, 0x3d, 0x86, 0x2c, 0x99, 0x11, 0xbb,
+	0x9a, 0x17, 0xf5, 0x6f, 0x25, 0x44, 0x3f, 0x23, 0xf6, 0xc0, 0xc1, 0x1e, 0x9d, 0xba, 0x6a, 0x58,
+	0xc2, 0xbb, 0xf1, 0x5b, 0x1a, 0x8a, 0x51, 0x10, 0xed, 0xc2, 0xff, 0x66, 0x98, 0x32, 0x63, 0xe4,
+	0x3a, 0x34, 0xb0, 0x89, 0x69, 0x60, 0xb5, 0x6f, 0x9a, 0x90, 0xcc, 0x1a, 0x47, 0xf7, 0x25, 0xb8,
+	0x27, 0x77, 0xee, 0x23, 0x58, 0x8f, 0x93, 0x2e, 0xf0, 0x2c, 0x20, 0x7c, 0x45, 0x33, 0x4d, 0x4d,
+	0x47, 0x51, 0xca, 0x37, 0x02, 0x41, 0x8f, 0x55, 0x9a, 0xc0, 0x0e, 0x66, 0x98, 0x59, 0x17, 0x44,
+	0x71, 0x32, 0x82, 0x23, 0xe2, 0xed, 0xcf, 0x41, 0xc9, 0xda, 0x81, 0x7b, 0x26, 0x19, 0x13, 0xdf,
+	0x8f, 0xd6, 0x95, 0x0d, 0xdf, 0x03, 0x0a, 0x50, 0x35, 0xbd, 0x0f, 0x73, 0x93, 0x0a, 0x9d, 0x13,
+	0xa1, 0xcb, 0xca, 0x2c, 0x83, 0x7e, 0x0e, 0x4b, 0x97, 0x96, 0x63, 0xba, 0x97, 0x4a, 0xf1, 0x89,
+	0xad, 0x3e, 0x13, 0x60, 0xa2, 0x7b, 0x8a, 0xd2, 0xf8, 0x45, 0x83, 0x72, 0xdc, 0x03, 0xbd, 0xc7,
+	0x35, 0x89, 0x7d, 0x96, 0x6c, 0x5d, 0x51, 0x58, 0x55, 0x7d, 0x0f, 0xa1, 0x48, 0xdd, 0xc0, 0x1f,
+	0x11, 0x6a, 0x50, 0x42, 0x1c, 0xa1, 0xdb, 0xa2, 0x5e, 0x90, 0xb6, 0x01, 0x21, 0x0e, 0xea, 0x41,
+	0x21, 0xa2, 0x76, 0xa9, 0xcc, 0x87, 0x6f, 0xdd, 0xc7, 0x44, 0x89, 0x51, 0x6e, 0xe3, 0x57, 0x0d,
+	0xd6, 0xee, 0x70, 0x45, 0x9f, 0xc2, 0xd2, 0xc8, 0x0d, 0x1c, 0x46, 0x7c, 0xb9, 0x83, 0x89, 0xb7,
+	0xe5, 0x7e, 0x08, 0x2a, 0x7f, 0x5d, 0x79, 0xa3, 0x8f, 0x21, 0xc7, 0x55, 0xee, 0xcb, 0x7d, 0xdb,
+	0x8e, 0xd3, 0xb8, 0xce, 0x17, 0xa4, 0xd0, 0x93, 0x53, 0x26, 0x38, 0x98, 0x2c, 0x56, 0x2c, 0x46,
+	0xf9, 0x92, 0x43, 0x0b, 0x8a, 0xf0, 0x6c, 0x30, 0x58, 0x4d, 0x54, 0x80, 0x2a, 0x90, 0xa1, 0x81,
+	0x2d, 0x5b, 0xca, 0x8f, 0x68, 0x03, 0xf2, 0x34, 0xb0, 0x0d, 0xfa, 0x52, 0x7e, 0x12, 0x72, 0x34,
+	0xb0, 0x07, 0x2f, 0xf9, 0x97, 0x44, 0x14, 0x2b, 0xd2, 0x65, 0xf4, 0xf0, 0xc2, 0xe9, 0x36, 0x7e,
+	0x25, 0x45, 0xc3, 0x8f, 0xc2, 0x62, 0x39, 0xf2, 0x8d, 0xc8, 0x8f, 0x8d, 0x1f, 0x34, 0x28, 0xc5,
+	0x9e, 0x60, 0x11, 0x4b, 0x4b, 0xc4, 0xe2, 0xa5, 0xf0, 0xac, 0x5a, 0xb2, 0x94, 0x8c, 0x30, 0xca,
+	0x52, 0x3e, 0x83, 0x3c, 0x65, 0x3e, 0xc1, 0xb6, 0xc8, 0x7b, 0x4b, 0x61, 0x03, 0x81, 0x25, 0xc6,
+	0x27, 0x19, 0x0d, 0x1b, 0xca, 0x71, 0x1c, 0x3d, 0x00, 0xe0, 0xaf, 0x1b, 0x29, 0xea, 0xb0, 0xa2,
+	0x15, 0x27, 0xb0, 0x17, 0x7a, 0xa6, 0xd8, 0xf6, 0x66, 0x72, 0xff, 0x6e, 0x67, 0x13, 0x60, 0x52,
+	0xcf, 0x92, 0xd2, 0xf8, 0x16, 0xca, 0x71, 0x07, 0xfe, 0xec, 0x22, 0x95, 0xc8, 0xa4, 0xe9, 0xe1,
+	0x05, 0x6d, 0x03, 0x4f, 0x69, 0xf8, 0xd8, 0x79, 0xa1, 0x3e, 0xc5, 0xcb, 0x4e, 0x60, 0xeb, 0xfc,
+	0xce, 0x29, 0x26, 0x99, 0x31, 0xac, 0x5a, 0x2f, 0x2e, 0x8d, 0xef, 0x35, 0x28, 0xc5, 0xa6, 0x8c,
+	0x10, 0x64, 0xf9, 0x9e, 0xcb, 0xc8, 0xe2, 0xfc, 0xcf, 0x9b, 0x3a, 0x9f, 0x49, 0xf6, 0x8e, 0xf9,
+	0xe6, 0x42, 0x7a, 0x64, 0xbe, 0x79, 0x69, 0xb1, 0x9c, 0x9d, 0x2e, 0x94, 0xe3, 0x9f, 0x6a, 0x54,
+	0x80, 0xa5, 0xe7, 0xfd, 0xaf, 0xfb, 0xc7, 0x67, 0xfd, 0x4a, 0x2a, 0xbc, 0x9c, 0xf6, 0x8e, 0x0e,
+	0xba, 0x15, 0x0d, 0x95, 0x60, 0xe5, 0xf0, 0x58, 0x3f, 0xdb, 0xd3, 0xbb, 0x07, 0xdd, 0x4a, 0x1a,
+	0xad, 0x40, 0x2e, 0x44, 0x32, 0x3b, 0x5f, 0x01, 0xba, 0xfd, 0x6a, 0x46, 0xdb, 0xb0, 0x79, 0xd6,
+	0x3b, 0x7d, 0x66, 0x9c, 0xe8, 0x07, 0x87, 0xbd, 0x73, 0x43, 0x9c, 0x07, 0xcf, 0x0f, 0x0f, 0x7b,
+	0xe7, 0x95, 0x14, 0xda, 0x84, 0xb5, 0xfe, 0xb1, 0x82, 0xfa, 0xc7, 0x0a, 0xd0, 0x9e, 0x9e, 0xbf,
+	0xbe, 0xae, 0x69, 0x6f, 0xae, 0x6b, 0xda, 0x5f, 0xd7, 0x35, 0xed, 0xc7, 0x9b, 0x5a, 0xea, 0xcd,
+	0x4d, 0x2d, 0xf5, 0xc7, 0x4d, 0x2d, 0xf5, 0xdd, 0x17, 0xff, 0xed, 0x7f, 0xed, 0x30, 0x2f, 0x6c,
+	0xbb, 0x7f, 0x0f, 0x00, 0xb3, 0xbb, 0xf5, 0x52, 0x20, 0x0b, 0x00, 0x00,
+}
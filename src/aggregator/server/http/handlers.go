@@ -24,18 +24,37 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/metrics/filters"
+	"github.com/m3db/m3/src/msg/producer"
 	xerrors "github.com/m3db/m3x/errors"
 )
 
 // A list of HTTP endpoints.
 const (
-	HealthPath = "/health"
-	ResignPath = "/resign"
-	StatusPath = "/status"
+	HealthPath        = "/health"
+	ResignPath        = "/resign"
+	StatusPath        = "/status"
+	InspectPath       = "/inspect"
+	ProducerStatsPath = "/producer/stats"
+)
+
+// A list of query parameters of the inspect endpoint.
+const (
+	inspectIDParam     = "id"
+	inspectFilterParam = "filter"
+	inspectLimitParam  = "limit"
+)
+
+// A list of query parameters of the producer stats endpoint.
+const (
+	producerStatsConsumerServiceParam = "consumerService"
+	producerStatsInFlightParam        = "inFlight"
 )
 
 var (
@@ -43,10 +62,12 @@ var (
 	errRequestMustBePost = xerrors.NewInvalidParamsError(errors.New("request must be POST"))
 )
 
-func registerHandlers(mux *http.ServeMux, aggregator aggregator.Aggregator) {
+func registerHandlers(mux *http.ServeMux, aggregator aggregator.Aggregator, opts Options) {
 	registerHealthHandler(mux)
 	registerResignHandler(mux, aggregator)
 	registerStatusHandler(mux, aggregator)
+	registerInspectHandler(mux, aggregator, opts)
+	registerProducerStatsHandler(mux, opts)
 }
 
 func registerHealthHandler(mux *http.ServeMux) {
@@ -92,6 +113,111 @@ func registerStatusHandler(mux *http.ServeMux, aggregator aggregator.Aggregator)
 	})
 }
 
+func registerInspectHandler(mux *http.ServeMux, aggregator aggregator.Aggregator, opts Options) {
+	mux.HandleFunc(InspectPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if httpMethod := strings.ToUpper(r.Method); httpMethod != http.MethodGet {
+			writeErrorResponse(w, errRequestMustBeGet)
+			return
+		}
+
+		query, err := parseInspectQuery(r, opts)
+		if err != nil {
+			writeErrorResponse(w, xerrors.NewInvalidParamsError(err))
+			return
+		}
+		result, err := aggregator.Inspect(query)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+		writeInspectResponse(w, result)
+	})
+}
+
+func registerProducerStatsHandler(mux *http.ServeMux, opts Options) {
+	mux.HandleFunc(ProducerStatsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if httpMethod := strings.ToUpper(r.Method); httpMethod != http.MethodGet {
+			writeErrorResponse(w, errRequestMustBeGet)
+			return
+		}
+
+		var stats []producer.TopicStats
+		if reporter := opts.ProducerStatsReporter(); reporter != nil {
+			stats = reporter.ProducerStats()
+		}
+		values := r.URL.Query()
+		stats = filterProducerStats(
+			stats,
+			values.Get(producerStatsConsumerServiceParam),
+			values.Get(producerStatsInFlightParam) == "true",
+		)
+		writeProducerStatsResponse(w, stats)
+	})
+}
+
+// filterProducerStats filters the stats by consumer service if specified, and
+// drops the shards without messages in flight if inFlightOnly is true.
+func filterProducerStats(
+	stats []producer.TopicStats,
+	consumerService string,
+	inFlightOnly bool,
+) []producer.TopicStats {
+	filtered := make([]producer.TopicStats, 0, len(stats))
+	for _, ts := range stats {
+		css := make([]producer.ConsumerServiceStats, 0, len(ts.ConsumerServices))
+		for _, cs := range ts.ConsumerServices {
+			if consumerService != "" && cs.ConsumerService != consumerService {
+				continue
+			}
+			if inFlightOnly {
+				shards := make([]producer.ShardStats, 0, len(cs.Shards))
+				for _, s := range cs.Shards {
+					if s.NumInFlight > 0 {
+						shards = append(shards, s)
+					}
+				}
+				cs.Shards = shards
+			}
+			css = append(css, cs)
+		}
+		ts.ConsumerServices = css
+		filtered = append(filtered, ts)
+	}
+	return filtered
+}
+
+func parseInspectQuery(r *http.Request, opts Options) (aggregator.InspectQuery, error) {
+	var (
+		values = r.URL.Query()
+		query  aggregator.InspectQuery
+	)
+	if id := values.Get(inspectIDParam); id != "" {
+		query.ID = []byte(id)
+	}
+	if filter := values.Get(inspectFilterParam); filter != "" {
+		filterValues, err := filters.ParseTagFilterValueMap(filter)
+		if err != nil {
+			return aggregator.InspectQuery{}, err
+		}
+		query.Filter, err = filters.NewTagsFilter(filterValues, filters.Conjunction, opts.TagsFilterOptions())
+		if err != nil {
+			return aggregator.InspectQuery{}, err
+		}
+	}
+	if limit := values.Get(inspectLimitParam); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return aggregator.InspectQuery{}, fmt.Errorf("invalid limit: %s", limit)
+		}
+		query.Limit = n
+	}
+	return query, nil
+}
+
 // Response is an HTTP response.
 type Response struct {
 	State string `json:"state,omitempty"`
@@ -104,12 +230,30 @@ type StatusResponse struct {
 	Status aggregator.RuntimeStatus `json:"status,omitempty"`
 }
 
+// InspectResponse is an inspect response.
+type InspectResponse struct {
+	Response
+	Result aggregator.InspectResult `json:"result"`
+}
+
+// ProducerStatsResponse is a producer stats response.
+type ProducerStatsResponse struct {
+	Response
+	Producers []producer.TopicStats `json:"producers"`
+}
+
 // NewResponse creates a new empty response.
 func NewResponse() Response { return Response{} }
 
 // NewStatusResponse creates a new empty status response.
 func NewStatusResponse() StatusResponse { return StatusResponse{} }
 
+// NewInspectResponse creates a new empty inspect response.
+func NewInspectResponse() InspectResponse { return InspectResponse{} }
+
+// NewProducerStatsResponse creates a new empty producer stats response.
+func NewProducerStatsResponse() ProducerStatsResponse { return ProducerStatsResponse{} }
+
 func newSuccessResponse() Response {
 	return Response{State: "OK"}
 }
@@ -137,6 +281,18 @@ func writeStatusResponse(w http.ResponseWriter, status aggregator.RuntimeStatus)
 	writeResponse(w, response, nil)
 }
 
+func writeInspectResponse(w http.ResponseWriter, result aggregator.InspectResult) {
+	response := NewInspectResponse()
+	response.Result = result
+	writeResponse(w, response, nil)
+}
+
+func writeProducerStatsResponse(w http.ResponseWriter, stats []producer.TopicStats) {
+	response := NewProducerStatsResponse()
+	response.Producers = stats
+	writeResponse(w, response, nil)
+}
+
 func writeResponse(w http.ResponseWriter, resp interface{}, err error) {
 	buf := bytes.NewBuffer(nil)
 	if encodeErr := json.NewEncoder(buf).Encode(&resp); encodeErr != nil {
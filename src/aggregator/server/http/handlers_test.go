@@ -0,0 +1,133 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3/src/aggregator/aggregator"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInspectQuery(t *testing.T) {
+	opts := NewOptions()
+
+	req := httptest.NewRequest(http.MethodGet, "/inspect?id=foo&limit=10", nil)
+	query, err := parseInspectQuery(req, opts)
+	require.NoError(t, err)
+	require.Equal(t, []byte("foo"), query.ID)
+	require.Nil(t, query.Filter)
+	require.Equal(t, 10, query.Limit)
+
+	req = httptest.NewRequest(http.MethodGet, "/inspect?filter=name:foo*+env:prod", nil)
+	query, err = parseInspectQuery(req, opts)
+	require.NoError(t, err)
+	require.Nil(t, query.ID)
+	require.NotNil(t, query.Filter)
+	require.True(t, query.Filter.Matches([]byte("foo.bar+env=prod")))
+	require.False(t, query.Filter.Matches([]byte("foo.bar+env=staging")))
+	require.False(t, query.Filter.Matches([]byte("baz+env=prod")))
+	require.Equal(t, 0, query.Limit)
+}
+
+func TestParseInspectQueryErrors(t *testing.T) {
+	opts := NewOptions()
+	for _, rawQuery := range []string{
+		"limit=foo",
+		"limit=-1",
+		"filter=invalid",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/inspect?"+rawQuery, nil)
+		_, err := parseInspectQuery(req, opts)
+		require.Error(t, err, rawQuery)
+	}
+}
+
+func TestInspectHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expected := aggregator.InspectResult{
+		Shards: []aggregator.ShardInspectResult{
+			{
+				Shard: 1,
+				Entries: []aggregator.EntryInspectResult{
+					{ID: "foo", MetricCategory: "untimed", MetricType: "counter"},
+				},
+			},
+		},
+	}
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().
+		Inspect(gomock.Any()).
+		DoAndReturn(func(query aggregator.InspectQuery) (aggregator.InspectResult, error) {
+			require.Equal(t, []byte("foo"), query.ID)
+			require.Equal(t, 5, query.Limit)
+			return expected, nil
+		})
+
+	mux := http.NewServeMux()
+	registerHandlers(mux, agg, NewOptions())
+
+	req := httptest.NewRequest(http.MethodGet, InspectPath+"?id=foo&limit=5", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp InspectResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, expected, resp.Result)
+}
+
+func TestInspectHandlerErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	agg := aggregator.NewMockAggregator(ctrl)
+	agg.EXPECT().
+		Inspect(gomock.Any()).
+		Return(aggregator.InspectResult{}, errors.New("inspect error"))
+
+	mux := http.NewServeMux()
+	registerHandlers(mux, agg, NewOptions())
+
+	inputs := []struct {
+		method       string
+		target       string
+		expectedCode int
+	}{
+		{method: http.MethodPost, target: InspectPath, expectedCode: http.StatusBadRequest},
+		{method: http.MethodGet, target: InspectPath + "?limit=foo", expectedCode: http.StatusBadRequest},
+		{method: http.MethodGet, target: InspectPath, expectedCode: http.StatusInternalServerError},
+	}
+	for _, input := range inputs {
+		req := httptest.NewRequest(input.method, input.target, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		require.Equal(t, input.expectedCode, rec.Code, input.target)
+	}
+}
@@ -20,11 +20,18 @@
 
 package http
 
-import "time"
+import (
+	"time"
+
+	"github.com/m3db/m3/src/aggregator/aggregator/handler"
+	"github.com/m3db/m3/src/metrics/filters"
+	"github.com/m3db/m3/src/metrics/metric/id/m3"
+)
 
 const (
 	defaultReadTimeout  = 10 * time.Second
 	defaultWriteTimeout = 10 * time.Second
+	defaultNameTagKey   = "name"
 )
 
 // Options is a set of server options.
@@ -40,11 +47,29 @@ type Options interface {
 
 	// WriteTimeout returns the write timeout.
 	WriteTimeout() time.Duration
+
+	// SetTagsFilterOptions sets the options used to filter metrics by tags
+	// when inspecting aggregation states.
+	SetTagsFilterOptions(value filters.TagsFilterOptions) Options
+
+	// TagsFilterOptions returns the options used to filter metrics by tags
+	// when inspecting aggregation states.
+	TagsFilterOptions() filters.TagsFilterOptions
+
+	// SetProducerStatsReporter sets the reporter of the stats of the m3msg
+	// producers of the flush handler.
+	SetProducerStatsReporter(value handler.ProducerStatsReporter) Options
+
+	// ProducerStatsReporter returns the reporter of the stats of the m3msg
+	// producers of the flush handler.
+	ProducerStatsReporter() handler.ProducerStatsReporter
 }
 
 type options struct {
-	readTimeout  time.Duration
-	writeTimeout time.Duration
+	readTimeout       time.Duration
+	writeTimeout      time.Duration
+	tagsFilterOptions filters.TagsFilterOptions
+	producerStats     handler.ProducerStatsReporter
 }
 
 // NewOptions creates a new set of server options.
@@ -52,6 +77,11 @@ func NewOptions() Options {
 	return &options{
 		readTimeout:  defaultReadTimeout,
 		writeTimeout: defaultWriteTimeout,
+		tagsFilterOptions: filters.TagsFilterOptions{
+			NameTagKey:          []byte(defaultNameTagKey),
+			NameAndTagsFn:       m3.NameAndTags,
+			SortedTagIteratorFn: m3.NewSortedTagIterator,
+		},
 	}
 }
 
@@ -74,3 +104,23 @@ func (o *options) SetWriteTimeout(value time.Duration) Options {
 func (o *options) WriteTimeout() time.Duration {
 	return o.writeTimeout
 }
+
+func (o *options) SetTagsFilterOptions(value filters.TagsFilterOptions) Options {
+	opts := *o
+	opts.tagsFilterOptions = value
+	return &opts
+}
+
+func (o *options) TagsFilterOptions() filters.TagsFilterOptions {
+	return o.tagsFilterOptions
+}
+
+func (o *options) SetProducerStatsReporter(value handler.ProducerStatsReporter) Options {
+	opts := *o
+	opts.producerStats = value
+	return &opts
+}
+
+func (o *options) ProducerStatsReporter() handler.ProducerStatsReporter {
+	return o.producerStats
+}
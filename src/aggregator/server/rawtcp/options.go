@@ -21,6 +21,8 @@
 package rawtcp
 
 import (
+	"crypto/tls"
+
 	"github.com/m3db/m3/src/metrics/encoding/msgpack"
 	"github.com/m3db/m3/src/metrics/encoding/protobuf"
 	"github.com/m3db/m3x/clock"
@@ -79,6 +81,12 @@ type Options interface {
 
 	// ErrorLogLimitPerSecond returns the error log limit per second.
 	ErrorLogLimitPerSecond() int64
+
+	// SetTLSConfig sets the TLS config, if set connections are served over TLS.
+	SetTLSConfig(value *tls.Config) Options
+
+	// TLSConfig returns the TLS config.
+	TLSConfig() *tls.Config
 }
 
 type options struct {
@@ -89,6 +97,7 @@ type options struct {
 	protobufItOpts       protobuf.UnaggregatedOptions
 	readBufferSize       int
 	errLogLimitPerSecond int64
+	tlsConfig            *tls.Config
 }
 
 // NewOptions creates a new set of server options.
@@ -173,3 +182,13 @@ func (o *options) SetErrorLogLimitPerSecond(value int64) Options {
 func (o *options) ErrorLogLimitPerSecond() int64 {
 	return o.errLogLimitPerSecond
 }
+
+func (o *options) SetTLSConfig(value *tls.Config) Options {
+	opts := *o
+	opts.tlsConfig = value
+	return &opts
+}
+
+func (o *options) TLSConfig() *tls.Config {
+	return o.tlsConfig
+}
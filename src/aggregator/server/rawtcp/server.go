@@ -38,6 +38,7 @@ import (
 	"github.com/m3db/m3/src/metrics/metadata"
 	"github.com/m3db/m3/src/metrics/metric/aggregated"
 	"github.com/m3db/m3/src/metrics/metric/unaggregated"
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/log"
 	xserver "github.com/m3db/m3x/server"
 
@@ -53,7 +54,11 @@ func NewServer(address string, aggregator aggregator.Aggregator, opts Options) x
 	iOpts := opts.InstrumentOptions()
 	handlerScope := iOpts.MetricsScope().Tagged(map[string]string{"handler": "rawtcp"})
 	handler := NewHandler(aggregator, opts.SetInstrumentOptions(iOpts.SetMetricsScope(handlerScope)))
-	return xserver.NewServer(address, handler, opts.ServerOptions())
+	server := xserver.NewServer(address, handler, opts.ServerOptions())
+	if tlsConfig := opts.TLSConfig(); tlsConfig != nil {
+		return xtls.NewServer(address, server, tlsConfig)
+	}
+	return server
 }
 
 type handlerMetrics struct {
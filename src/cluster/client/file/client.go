@@ -0,0 +1,282 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package file provides a cluster client backed by local files for single
+// node and development deployments that do not run etcd.
+package file
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/kv"
+	filekv "github.com/m3db/m3/src/cluster/kv/file"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3x/instrument"
+)
+
+const (
+	defaultNamespace  = "_kv"
+	fileNameSeparator = "_"
+	fileSuffix        = ".kvlog"
+)
+
+var (
+	errNoRootDir               = errors.New("no root dir")
+	errHeartbeatNotSupported   = errors.New("heartbeats are not supported by the local cluster client")
+	errLeaderElectNotSupported = errors.New("leader election is not supported by the local cluster client")
+)
+
+// NewClient returns a cluster client that persists every store as a file
+// under the configured root directory.
+func NewClient(opts Options) (client.Client, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &fileClient{opts: opts}, nil
+}
+
+// openStores holds the stores opened by any client in this process keyed by
+// file path, so that clients created for the same root dir (e.g. by a
+// database node and a coordinator running in the same process) share a store
+// rather than appending to the same file independently.
+var openStores = struct {
+	sync.Mutex
+	byPath map[string]filekv.Store
+}{byPath: make(map[string]filekv.Store)}
+
+type fileClient struct {
+	opts Options
+}
+
+func (c *fileClient) Services(opts services.OverrideOptions) (services.Services, error) {
+	if opts == nil {
+		opts = services.NewOverrideOptions()
+	}
+	return services.NewServices(c.opts.ServicesOptions().
+		SetKVGen(c.kvGen()).
+		SetHeartbeatGen(heartbeatGen).
+		SetLeaderGen(leaderGen).
+		SetNamespaceOptions(opts.NamespaceOptions()).
+		SetInstrumentsOptions(c.opts.InstrumentOptions()),
+	)
+}
+
+func (c *fileClient) KV() (kv.Store, error) {
+	return c.Txn()
+}
+
+func (c *fileClient) Txn() (kv.TxnStore, error) {
+	return c.TxnStore(kv.NewOverrideOptions())
+}
+
+func (c *fileClient) Store(opts kv.OverrideOptions) (kv.Store, error) {
+	return c.TxnStore(opts)
+}
+
+func (c *fileClient) TxnStore(opts kv.OverrideOptions) (kv.TxnStore, error) {
+	opts = c.sanitizeOptions(opts)
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return c.store(opts.Zone(), opts.Namespace(), opts.Environment())
+}
+
+func (c *fileClient) kvGen() services.KVGen {
+	return services.KVGen(func(zone string) (kv.Store, error) {
+		return c.store(zone)
+	})
+}
+
+func (c *fileClient) store(zone string, namespaces ...string) (kv.TxnStore, error) {
+	path, err := filepath.Abs(filepath.Join(c.opts.RootDir(), fileName(zone, namespaces...)))
+	if err != nil {
+		return nil, err
+	}
+
+	openStores.Lock()
+	defer openStores.Unlock()
+
+	if store, ok := openStores.byPath[path]; ok {
+		return store, nil
+	}
+
+	store, err := filekv.NewStore(filekv.NewOptions().
+		SetFilePath(path).
+		SetSyncWrites(c.opts.SyncWrites()).
+		SetInstrumentOptions(c.opts.InstrumentOptions()))
+	if err != nil {
+		return nil, err
+	}
+	openStores.byPath[path] = store
+	return store, nil
+}
+
+func (c *fileClient) sanitizeOptions(opts kv.OverrideOptions) kv.OverrideOptions {
+	if opts.Zone() == "" {
+		opts = opts.SetZone(c.opts.Zone())
+	}
+	if opts.Environment() == "" {
+		opts = opts.SetEnvironment(c.opts.Env())
+	}
+	if opts.Namespace() == "" {
+		opts = opts.SetNamespace(defaultNamespace)
+	}
+	return opts
+}
+
+func fileName(zone string, namespaces ...string) string {
+	parts := make([]string, 0, 1+len(namespaces))
+	parts = append(parts, zone)
+	for _, ns := range namespaces {
+		if ns != "" {
+			parts = append(parts, ns)
+		}
+	}
+	s := strings.Join(parts, fileNameSeparator)
+	return strings.Replace(s, string(os.PathSeparator), fileNameSeparator, -1) + fileSuffix
+}
+
+func heartbeatGen(sid services.ServiceID) (services.HeartbeatService, error) {
+	return nil, errHeartbeatNotSupported
+}
+
+func leaderGen(sid services.ServiceID, opts services.ElectionOptions) (services.LeaderService, error) {
+	return nil, errLeaderElectNotSupported
+}
+
+// Options are the options for the local cluster client.
+type Options interface {
+	// Zone is the default zone of the client.
+	Zone() string
+	// SetZone sets the Zone.
+	SetZone(value string) Options
+
+	// Env is the default environment of the client.
+	Env() string
+	// SetEnv sets the Env.
+	SetEnv(value string) Options
+
+	// RootDir is the directory holding the store files.
+	RootDir() string
+	// SetRootDir sets the RootDir.
+	SetRootDir(value string) Options
+
+	// SyncWrites determines whether every write is fsynced.
+	SyncWrites() bool
+	// SetSyncWrites sets the SyncWrites.
+	SetSyncWrites(value bool) Options
+
+	// ServicesOptions is the options for the services client.
+	ServicesOptions() services.Options
+	// SetServicesOptions sets the ServicesOptions.
+	SetServicesOptions(value services.Options) Options
+
+	// InstrumentOptions is the instrument options.
+	InstrumentOptions() instrument.Options
+	// SetInstrumentOptions sets the InstrumentOptions.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// Validate validates the Options.
+	Validate() error
+}
+
+type options struct {
+	zone       string
+	env        string
+	rootDir    string
+	syncWrites bool
+	sdOpts     services.Options
+	iOpts      instrument.Options
+}
+
+// NewOptions creates a new set of options.
+func NewOptions() Options {
+	return options{
+		syncWrites: filekv.NewOptions().SyncWrites(),
+		sdOpts:     services.NewOptions(),
+		iOpts:      instrument.NewOptions(),
+	}
+}
+
+func (o options) Validate() error {
+	if o.rootDir == "" {
+		return errNoRootDir
+	}
+	return nil
+}
+
+func (o options) Zone() string {
+	return o.zone
+}
+
+func (o options) SetZone(value string) Options {
+	o.zone = value
+	return o
+}
+
+func (o options) Env() string {
+	return o.env
+}
+
+func (o options) SetEnv(value string) Options {
+	o.env = value
+	return o
+}
+
+func (o options) RootDir() string {
+	return o.rootDir
+}
+
+func (o options) SetRootDir(value string) Options {
+	o.rootDir = value
+	return o
+}
+
+func (o options) SyncWrites() bool {
+	return o.syncWrites
+}
+
+func (o options) SetSyncWrites(value bool) Options {
+	o.syncWrites = value
+	return o
+}
+
+func (o options) ServicesOptions() services.Options {
+	return o.sdOpts
+}
+
+func (o options) SetServicesOptions(value services.Options) Options {
+	o.sdOpts = value
+	return o
+}
+
+func (o options) InstrumentOptions() instrument.Options {
+	return o.iOpts
+}
+
+func (o options) SetInstrumentOptions(value instrument.Options) Options {
+	o.iOpts = value
+	return o
+}
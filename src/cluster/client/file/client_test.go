@@ -0,0 +1,151 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/kvtest"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/services"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReuseKVStore(t *testing.T) {
+	opts, cleanup := testOptions(t)
+	defer cleanup()
+
+	c, err := NewClient(opts)
+	require.NoError(t, err)
+
+	store1, err := c.Txn()
+	require.NoError(t, err)
+	store2, err := c.KV()
+	require.NoError(t, err)
+	require.Equal(t, store1, store2)
+
+	store3, err := c.Store(kv.NewOverrideOptions().SetNamespace("ns"))
+	require.NoError(t, err)
+	require.NotEqual(t, store1, store3)
+
+	// Clients over the same root dir share their stores.
+	c2, err := NewClient(opts)
+	require.NoError(t, err)
+	store4, err := c2.Store(kv.NewOverrideOptions().SetNamespace("ns"))
+	require.NoError(t, err)
+	require.Equal(t, store3, store4)
+
+	path := filepath.Join(opts.RootDir(), "zone_env_ns.kvlog")
+	_, err = os.Stat(path)
+	require.NoError(t, err)
+}
+
+func TestPlacementSurvivesRestart(t *testing.T) {
+	opts, cleanup := testOptions(t)
+	defer cleanup()
+
+	sid := services.NewServiceID().SetName("m3db").SetZone("zone").SetEnvironment("env")
+	c, err := NewClient(opts)
+	require.NoError(t, err)
+	svcs, err := c.Services(nil)
+	require.NoError(t, err)
+	ps, err := svcs.PlacementService(sid, placement.NewOptions())
+	require.NoError(t, err)
+
+	instances := []placement.Instance{
+		placement.NewEmptyInstance("i1", "r1", "zone", "endpoint1", 1),
+		placement.NewEmptyInstance("i2", "r2", "zone", "endpoint2", 1),
+	}
+	p, err := ps.BuildInitialPlacement(instances, 8, 1)
+	require.NoError(t, err)
+
+	store, err := c.Txn()
+	require.NoError(t, err)
+	_, err = store.Set("foo", &kvtest.Foo{Msg: "bar"})
+	require.NoError(t, err)
+
+	// A new client over the same root dir sees everything written before,
+	// including after the stores are reopened from disk.
+	closeOpenStores(t)
+	c, err = NewClient(opts)
+	require.NoError(t, err)
+	svcs, err = c.Services(nil)
+	require.NoError(t, err)
+	ps, err = svcs.PlacementService(sid, placement.NewOptions())
+	require.NoError(t, err)
+
+	restored, err := ps.Placement()
+	require.NoError(t, err)
+	require.Equal(t, p.Version(), restored.Version())
+	require.Equal(t, p.NumInstances(), restored.NumInstances())
+	require.Equal(t, p.NumShards(), restored.NumShards())
+
+	store, err = c.Txn()
+	require.NoError(t, err)
+	v, err := store.Get("foo")
+	require.NoError(t, err)
+	var msg kvtest.Foo
+	require.NoError(t, v.Unmarshal(&msg))
+	require.Equal(t, "bar", msg.Msg)
+}
+
+func TestHeartbeatNotSupported(t *testing.T) {
+	opts, cleanup := testOptions(t)
+	defer cleanup()
+
+	c, err := NewClient(opts)
+	require.NoError(t, err)
+	svcs, err := c.Services(nil)
+	require.NoError(t, err)
+
+	sid := services.NewServiceID().SetName("m3db").SetZone("zone")
+	_, err = svcs.HeartbeatService(sid)
+	require.Equal(t, errHeartbeatNotSupported, err)
+}
+
+func closeOpenStores(t *testing.T) {
+	openStores.Lock()
+	defer openStores.Unlock()
+
+	for path, store := range openStores.byPath {
+		require.NoError(t, store.Close())
+		delete(openStores.byPath, path)
+	}
+}
+
+func testOptions(t *testing.T) (Options, func()) {
+	dir, err := ioutil.TempDir("", "cluster-file-client")
+	require.NoError(t, err)
+	opts := NewOptions().
+		SetZone("zone").
+		SetEnv("env").
+		SetRootDir(dir).
+		SetSyncWrites(false)
+	return opts, func() {
+		closeOpenStores(t)
+		os.RemoveAll(dir)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package file
+
+import (
+	"github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3x/instrument"
+)
+
+// Configuration is the config for the local cluster client.
+type Configuration struct {
+	Zone       string `yaml:"zone"`
+	Env        string `yaml:"env"`
+	Service    string `yaml:"service"`
+	RootDir    string `yaml:"rootDir" validate:"nonzero"`
+	SyncWrites *bool  `yaml:"syncWrites"`
+}
+
+// NewClient creates a new local cluster client.
+func (cfg Configuration) NewClient(iOpts instrument.Options) (client.Client, error) {
+	return NewClient(cfg.NewOptions().SetInstrumentOptions(iOpts))
+}
+
+// NewOptions returns a new Options.
+func (cfg Configuration) NewOptions() Options {
+	opts := NewOptions().
+		SetZone(cfg.Zone).
+		SetEnv(cfg.Env).
+		SetRootDir(cfg.RootDir)
+	if cfg.SyncWrites != nil {
+		opts = opts.SetSyncWrites(*cfg.SyncWrites)
+	}
+	return opts
+}
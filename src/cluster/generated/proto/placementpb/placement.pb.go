@@ -89,6 +89,9 @@ type Placement struct {
 	// max_shard_set_id stores the maximum shard set id used to guarantee unique
 	// shard set id generations across placement changes.
 	MaxShardSetId uint32 `protobuf:"varint,7,opt,name=max_shard_set_id,json=maxShardSetId,proto3" json:"max_shard_set_id,omitempty"`
+	// zone_replica_factors stores the number of replicas of each shard placed
+	// in each zone for placements spanning multiple zones.
+	ZoneReplicaFactors map[string]uint32 `protobuf:"bytes,8,rep,name=zone_replica_factors,json=zoneReplicaFactors" json:"zone_replica_factors,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
 }
 
 func (m *Placement) Reset()                    { *m = Placement{} }
@@ -145,6 +148,13 @@ func (m *Placement) GetMaxShardSetId() uint32 {
 	return 0
 }
 
+func (m *Placement) GetZoneReplicaFactors() map[string]uint32 {
+	if m != nil {
+		return m.ZoneReplicaFactors
+	}
+	return nil
+}
+
 type Instance struct {
 	Id             string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	IsolationGroup string   `protobuf:"bytes,2,opt,name=isolation_group,json=isolationGroup,proto3" json:"isolation_group,omitempty"`
@@ -384,6 +394,22 @@ func (m *Placement) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintPlacement(dAtA, i, uint64(m.MaxShardSetId))
 	}
+	if len(m.ZoneReplicaFactors) > 0 {
+		for k, _ := range m.ZoneReplicaFactors {
+			dAtA[i] = 0x42
+			i++
+			v := m.ZoneReplicaFactors[k]
+			mapSize := 1 + len(k) + sovPlacement(uint64(len(k))) + 1 + sovPlacement(uint64(v))
+			i = encodeVarintPlacement(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintPlacement(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x10
+			i++
+			i = encodeVarintPlacement(dAtA, i, uint64(v))
+		}
+	}
 	return i, nil
 }
 
@@ -579,6 +605,14 @@ func (m *Placement) Size() (n int) {
 	if m.MaxShardSetId != 0 {
 		n += 1 + sovPlacement(uint64(m.MaxShardSetId))
 	}
+	if len(m.ZoneReplicaFactors) > 0 {
+		for k, v := range m.ZoneReplicaFactors {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovPlacement(uint64(len(k))) + 1 + sovPlacement(uint64(v))
+			n += mapEntrySize + 1 + sovPlacement(uint64(mapEntrySize))
+		}
+	}
 	return n
 }
 
@@ -938,6 +972,113 @@ func (m *Placement) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ZoneReplicaFactors", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlacement
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlacement
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ZoneReplicaFactors == nil {
+				m.ZoneReplicaFactors = make(map[string]uint32)
+			}
+			var mapkey string
+			var mapvalue uint32
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPlacement
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPlacement
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthPlacement
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPlacement
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= (uint32(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipPlacement(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthPlacement
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.ZoneReplicaFactors[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPlacement(dAtA[iNdEx:])
@@ -1588,45 +1729,47 @@ func init() {
 }
 
 var fileDescriptorPlacement = []byte{
-	// 628 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0xdf, 0x6e, 0xd3, 0x3e,
-	0x14, 0x5e, 0xd2, 0xb5, 0x6b, 0x4e, 0xd7, 0xfe, 0x2a, 0x4b, 0xbf, 0x11, 0x0d, 0x51, 0x4a, 0xd1,
-	0x44, 0x35, 0x44, 0x23, 0x6d, 0x5c, 0xa0, 0xdd, 0x75, 0x68, 0x4c, 0x99, 0xca, 0x84, 0xdc, 0x69,
-	0x17, 0xdc, 0x44, 0x6e, 0xe2, 0xb6, 0x16, 0x8d, 0x1d, 0xd9, 0xce, 0xd8, 0x78, 0x03, 0xee, 0x78,
-	0x0f, 0x5e, 0x84, 0x4b, 0x1e, 0x01, 0x8d, 0x87, 0xe0, 0x16, 0xc5, 0x49, 0xfa, 0x47, 0xec, 0xee,
-	0x9c, 0xef, 0x7c, 0xf6, 0xf9, 0xfc, 0xf9, 0x1c, 0xb8, 0x98, 0x31, 0x3d, 0x4f, 0x27, 0x83, 0x50,
-	0xc4, 0x5e, 0x7c, 0x1c, 0x4d, 0xbc, 0xf8, 0xd8, 0x53, 0x32, 0xf4, 0xc2, 0x45, 0xaa, 0x34, 0x95,
-	0xde, 0x8c, 0x72, 0x2a, 0x89, 0xa6, 0x91, 0x97, 0x48, 0xa1, 0x85, 0x97, 0x2c, 0x48, 0x48, 0x63,
-	0xca, 0x75, 0x32, 0x59, 0xc5, 0x03, 0x53, 0x43, 0x8d, 0xb5, 0x62, 0xef, 0x8f, 0x0d, 0xce, 0x87,
-	0x32, 0x47, 0x6f, 0xc1, 0x61, 0x5c, 0x69, 0xc2, 0x43, 0xaa, 0x5c, 0xab, 0x5b, 0xe9, 0x37, 0x8e,
-	0x0e, 0x06, 0x6b, 0xf4, 0xc1, 0x92, 0x3a, 0xf0, 0x4b, 0xde, 0x19, 0xd7, 0xf2, 0x0e, 0xaf, 0xce,
-	0xa1, 0x03, 0x68, 0x49, 0x9a, 0x2c, 0x58, 0x48, 0x82, 0x29, 0x09, 0xb5, 0x90, 0xae, 0xdd, 0xb5,
-	0xfa, 0x4d, 0xdc, 0x2c, 0xd0, 0x77, 0x06, 0x44, 0x4f, 0x00, 0x78, 0x1a, 0x07, 0x6a, 0x4e, 0x64,
-	0xa4, 0xdc, 0x8a, 0xa1, 0x38, 0x3c, 0x8d, 0xc7, 0x06, 0xc8, 0xca, 0x4c, 0xe5, 0x55, 0x1a, 0xb9,
-	0xdb, 0x5d, 0xab, 0x5f, 0xc7, 0x0e, 0x53, 0xe3, 0x1c, 0x40, 0xcf, 0x60, 0x37, 0x4c, 0xb5, 0xb8,
-	0xa1, 0x32, 0xd0, 0x2c, 0xa6, 0x6e, 0xb5, 0x6b, 0xf5, 0x2b, 0xb8, 0x51, 0x60, 0x57, 0x2c, 0xa6,
-	0xe8, 0x29, 0x34, 0x98, 0x0a, 0x62, 0x26, 0xa5, 0x90, 0x34, 0x72, 0x6b, 0xe6, 0x0a, 0x60, 0xea,
-	0x7d, 0x81, 0xa0, 0x17, 0xd0, 0x8e, 0xc9, 0x6d, 0xde, 0x23, 0x50, 0x54, 0x07, 0x2c, 0x72, 0x77,
-	0x72, 0xa9, 0x31, 0xb9, 0x35, 0x9d, 0xc6, 0x54, 0xfb, 0xd1, 0xfe, 0x18, 0x5a, 0x9b, 0xcf, 0x45,
-	0x6d, 0xa8, 0x7c, 0xa2, 0x77, 0xae, 0xd5, 0xb5, 0xfa, 0x0e, 0xce, 0x42, 0xf4, 0x12, 0xaa, 0x37,
-	0x64, 0x91, 0x52, 0xf3, 0xd8, 0xc6, 0xd1, 0xff, 0x1b, 0xb6, 0x95, 0xa7, 0x71, 0xce, 0x39, 0xb1,
-	0xdf, 0x58, 0xbd, 0xaf, 0x36, 0xd4, 0x4b, 0x1c, 0xb5, 0xc0, 0x66, 0x51, 0x71, 0x9d, 0xcd, 0x32,
-	0x69, 0xff, 0x31, 0x25, 0x16, 0x44, 0x33, 0xc1, 0x83, 0x99, 0x14, 0x69, 0x62, 0xee, 0x75, 0x70,
-	0x6b, 0x09, 0x9f, 0x67, 0x28, 0x42, 0xb0, 0xfd, 0x45, 0x70, 0x6a, 0xfc, 0x73, 0xb0, 0x89, 0xd1,
-	0x1e, 0xd4, 0x3e, 0x53, 0x36, 0x9b, 0x6b, 0x63, 0x5b, 0x13, 0x17, 0x19, 0xda, 0x87, 0x3a, 0xe5,
-	0x51, 0x22, 0x18, 0xd7, 0xc6, 0x2f, 0x07, 0x2f, 0x73, 0x74, 0x08, 0xb5, 0xe2, 0x27, 0x6a, 0xe6,
-	0xdb, 0xd1, 0x86, 0x7e, 0xe3, 0x05, 0x2e, 0x18, 0xa8, 0x0b, 0xbb, 0x0f, 0x78, 0x06, 0x6a, 0x69,
-	0x58, 0xd6, 0x69, 0x2e, 0x94, 0xe6, 0x24, 0xa6, 0x6e, 0x3d, 0xef, 0x54, 0xe6, 0x99, 0xe2, 0x44,
-	0x48, 0xed, 0x3a, 0xe6, 0x94, 0x89, 0x7b, 0xdf, 0x2d, 0xa8, 0x9a, 0x1e, 0x6b, 0x46, 0x34, 0x8d,
-	0x11, 0xaf, 0xa0, 0xaa, 0x34, 0xd1, 0xb9, 0xad, 0xad, 0xa3, 0x47, 0xff, 0xca, 0x1a, 0x67, 0x65,
-	0x9c, 0xb3, 0xd0, 0x63, 0x70, 0x94, 0x48, 0x65, 0x48, 0x33, 0x5d, 0xb9, 0x27, 0xf5, 0x1c, 0xf0,
-	0x23, 0xf4, 0x1c, 0x9a, 0xe5, 0xcc, 0x70, 0xc2, 0x85, 0x32, 0xf6, 0x54, 0x70, 0x39, 0x48, 0x97,
-	0x19, 0x56, 0x0e, 0xd6, 0x74, 0x5a, 0x70, 0xd6, 0x06, 0x6b, 0x3a, 0x35, 0x94, 0xde, 0x05, 0xa0,
-	0xe5, 0x1e, 0x8c, 0x39, 0x49, 0xd4, 0x5c, 0x68, 0x85, 0x5e, 0x83, 0xa3, 0xca, 0xa4, 0xd8, 0x9d,
-	0xbd, 0x87, 0x77, 0x07, 0xaf, 0x88, 0x87, 0x27, 0x00, 0xab, 0x57, 0xa0, 0x36, 0xec, 0xfa, 0x97,
-	0xfe, 0x95, 0x3f, 0x1c, 0xf9, 0x1f, 0xfd, 0xcb, 0xf3, 0xf6, 0x16, 0x6a, 0x82, 0x33, 0xbc, 0x1e,
-	0xfa, 0xa3, 0xe1, 0xe9, 0xe8, 0xac, 0x6d, 0xa1, 0x06, 0xec, 0x8c, 0xce, 0x86, 0xd7, 0x59, 0xcd,
-	0x3e, 0x6d, 0xff, 0xb8, 0xef, 0x58, 0x3f, 0xef, 0x3b, 0xd6, 0xaf, 0xfb, 0x8e, 0xf5, 0xed, 0x77,
-	0x67, 0x6b, 0x52, 0x33, 0x1b, 0x7e, 0xfc, 0x37, 0x00, 0x00, 0xff, 0xff, 0x38, 0x9b, 0x13, 0x65,
-	0x2f, 0x04, 0x00, 0x00,
+	// 662 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x75, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0xae, 0x93, 0x26, 0x8d, 0x27, 0x75, 0x88, 0x56, 0xa5, 0xb5, 0x8a, 0x28, 0x25, 0xa8, 0xa2,
+	0x2a, 0x22, 0x91, 0x5a, 0x0e, 0xa8, 0xb7, 0x14, 0x85, 0xca, 0x55, 0xa8, 0xd0, 0xa6, 0xea, 0xa1,
+	0x97, 0xe0, 0xd8, 0x9b, 0xc4, 0x22, 0xde, 0xb5, 0x76, 0xd7, 0x85, 0xf6, 0x0d, 0xb8, 0xf1, 0x08,
+	0xdc, 0x79, 0x11, 0x8e, 0x3c, 0x02, 0x82, 0x17, 0x61, 0xbd, 0xb6, 0x13, 0x47, 0x6d, 0x0f, 0x8e,
+	0x76, 0xbe, 0xf9, 0xe6, 0x67, 0xbf, 0x99, 0x0d, 0x9c, 0x4d, 0x02, 0x39, 0x8d, 0x47, 0x6d, 0x8f,
+	0x85, 0x9d, 0xf0, 0xc8, 0x1f, 0xa9, 0x9f, 0x8e, 0xe0, 0x5e, 0xc7, 0x9b, 0xc5, 0x42, 0x12, 0xde,
+	0x99, 0x10, 0x4a, 0xb8, 0x2b, 0x89, 0xdf, 0x89, 0x38, 0x93, 0xac, 0x13, 0xcd, 0x5c, 0x8f, 0x84,
+	0x84, 0xca, 0x68, 0xb4, 0x38, 0xb7, 0xb5, 0x0f, 0xd5, 0x0b, 0xce, 0xd6, 0x8f, 0x55, 0x30, 0x3f,
+	0xe6, 0x36, 0x7a, 0x07, 0x66, 0x40, 0x85, 0x74, 0xa9, 0x47, 0x84, 0x6d, 0xec, 0x96, 0xf7, 0xeb,
+	0x87, 0x7b, 0xed, 0x02, 0xbd, 0x3d, 0xa7, 0xb6, 0x9d, 0x9c, 0xd7, 0xa3, 0x92, 0xdf, 0xe0, 0x45,
+	0x1c, 0xda, 0x83, 0x06, 0x27, 0xd1, 0x2c, 0xf0, 0xdc, 0xe1, 0xd8, 0xf5, 0x24, 0xe3, 0x76, 0x69,
+	0xd7, 0xd8, 0xb7, 0xb0, 0x95, 0xa1, 0xef, 0x35, 0x88, 0x9e, 0x02, 0xd0, 0x38, 0x1c, 0x8a, 0xa9,
+	0xcb, 0x7d, 0x61, 0x97, 0x35, 0xc5, 0x54, 0xc8, 0x40, 0x03, 0x89, 0x3b, 0x10, 0xa9, 0x97, 0xf8,
+	0xf6, 0xaa, 0x72, 0xd7, 0x54, 0x11, 0x31, 0x48, 0x01, 0xf4, 0x1c, 0xd6, 0xbd, 0x58, 0xb2, 0x6b,
+	0xc2, 0x87, 0x32, 0x08, 0x89, 0x5d, 0x51, 0x84, 0x32, 0xae, 0x67, 0xd8, 0x85, 0x82, 0xd0, 0x33,
+	0xa8, 0xab, 0x0c, 0x61, 0xc0, 0x39, 0xe3, 0x2a, 0x45, 0x55, 0xa7, 0x50, 0x49, 0x3f, 0x64, 0x08,
+	0x7a, 0x09, 0xcd, 0xd0, 0xfd, 0x9a, 0xd6, 0x18, 0x0a, 0x22, 0x87, 0x81, 0x6f, 0xaf, 0xa5, 0xad,
+	0x2a, 0x5c, 0x57, 0x1a, 0x10, 0xe9, 0xf8, 0xe8, 0x13, 0x6c, 0xdc, 0x32, 0x4a, 0x86, 0xcb, 0xd7,
+	0x12, 0x76, 0x4d, 0x2b, 0xd4, 0x7e, 0x40, 0xa1, 0x2b, 0x15, 0x82, 0x8b, 0x57, 0xce, 0xa4, 0x42,
+	0xb7, 0x77, 0x1c, 0xdb, 0x03, 0x68, 0x2c, 0x0b, 0x8a, 0x9a, 0x50, 0xfe, 0x4c, 0x6e, 0xd4, 0x10,
+	0x8c, 0x7d, 0x13, 0x27, 0x47, 0xf4, 0x0a, 0x2a, 0xd7, 0xee, 0x2c, 0x26, 0x5a, 0xce, 0xfa, 0xe1,
+	0xe3, 0xa5, 0xb2, 0x79, 0x34, 0x4e, 0x39, 0xc7, 0xa5, 0xb7, 0xc6, 0x76, 0x0f, 0xb6, 0x1e, 0xe8,
+	0xe1, 0x9e, 0xec, 0x1b, 0xc5, 0xec, 0x56, 0x21, 0x4d, 0xeb, 0x5b, 0x09, 0x6a, 0x79, 0x7a, 0xd4,
+	0x80, 0x92, 0x52, 0x29, 0x8d, 0x53, 0x27, 0xa5, 0xe1, 0xa3, 0x40, 0xb0, 0x99, 0x2b, 0x03, 0x46,
+	0x87, 0x13, 0xce, 0xe2, 0x48, 0x27, 0x30, 0x71, 0x63, 0x0e, 0x9f, 0x26, 0x28, 0x42, 0xb0, 0x9a,
+	0xdc, 0x5b, 0x0f, 0xda, 0xc4, 0xfa, 0x8c, 0x36, 0xa1, 0xfa, 0x85, 0x04, 0x93, 0xa9, 0xd4, 0xf3,
+	0xb5, 0x70, 0x66, 0xa1, 0x6d, 0xa8, 0x11, 0xea, 0x47, 0x2c, 0xa0, 0x52, 0x0f, 0xd6, 0xc4, 0x73,
+	0x1b, 0x1d, 0x40, 0x35, 0x5b, 0x99, 0xaa, 0x56, 0x1f, 0x2d, 0xc9, 0xa0, 0x87, 0x86, 0x33, 0x06,
+	0xda, 0x85, 0xf5, 0x7b, 0x86, 0x0b, 0x62, 0x31, 0x59, 0x55, 0x69, 0xca, 0x84, 0xa4, 0xae, 0x5a,
+	0xa1, 0x5a, 0x5a, 0x29, 0xb7, 0x93, 0x8e, 0x23, 0xc6, 0xa5, 0x6d, 0xea, 0x28, 0x7d, 0x6e, 0xfd,
+	0x34, 0xa0, 0xa2, 0x6b, 0x14, 0x84, 0xb0, 0xb4, 0x10, 0xaf, 0xa1, 0xa2, 0x24, 0x92, 0xa9, 0x7e,
+	0x8d, 0xc3, 0xad, 0xbb, 0x6d, 0x0d, 0x12, 0x37, 0x4e, 0x59, 0xe8, 0x09, 0x98, 0x82, 0xc5, 0xdc,
+	0x23, 0x49, 0x5f, 0xa9, 0x26, 0xb5, 0x14, 0x50, 0x5d, 0xbd, 0x00, 0x2b, 0x5f, 0x6e, 0xea, 0x52,
+	0x26, 0xb4, 0x3c, 0x65, 0x9c, 0x6f, 0xfc, 0x79, 0x82, 0xe5, 0x2f, 0x60, 0x3c, 0xce, 0x38, 0x85,
+	0x17, 0x30, 0x1e, 0x6b, 0x4a, 0xeb, 0x0c, 0xd0, 0x7c, 0x1d, 0x07, 0xd4, 0x8d, 0xc4, 0x94, 0x49,
+	0x81, 0xde, 0xa8, 0xd2, 0xb9, 0x91, 0x3d, 0xf2, 0xcd, 0xfb, 0x57, 0x18, 0x2f, 0x88, 0x07, 0xc7,
+	0x00, 0x8b, 0x5b, 0xa8, 0xfd, 0x59, 0x77, 0xce, 0x9d, 0x0b, 0xa7, 0xdb, 0x77, 0xae, 0x9c, 0xf3,
+	0xd3, 0xe6, 0x0a, 0xb2, 0xc0, 0xec, 0x5e, 0x76, 0x9d, 0x7e, 0xf7, 0xa4, 0xdf, 0x6b, 0x1a, 0xa8,
+	0x0e, 0x6b, 0xfd, 0x5e, 0xf7, 0x32, 0xf1, 0x95, 0x4e, 0x9a, 0xbf, 0xfe, 0xee, 0x18, 0xbf, 0xd5,
+	0xf7, 0x47, 0x7d, 0xdf, 0xff, 0xed, 0xac, 0x8c, 0xaa, 0xfa, 0xaf, 0xe8, 0xe8, 0x3f, 0x7d, 0x85,
+	0xc1, 0x50, 0xd8, 0x04, 0x00, 0x00,
 }
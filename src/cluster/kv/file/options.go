@@ -0,0 +1,155 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package file
+
+import (
+	"errors"
+
+	"github.com/m3db/m3x/instrument"
+)
+
+const (
+	defaultSyncWrites         = true
+	defaultMaxHistory         = 100
+	defaultCompactionMinBytes = 4 << 20
+)
+
+var (
+	errNoFilePath                = errors.New("no file path")
+	errNoInstrumentOptions       = errors.New("no instrument options")
+	errInvalidMaxHistory         = errors.New("max history must be positive")
+	errInvalidCompactionMinBytes = errors.New("compaction min bytes must not be negative")
+)
+
+// Options are options for the file backed kv store.
+type Options interface {
+	// FilePath is the path of the log file backing the store.
+	FilePath() string
+	// SetFilePath sets the FilePath.
+	SetFilePath(value string) Options
+
+	// SyncWrites determines whether every write is fsynced before it is
+	// acknowledged.
+	SyncWrites() bool
+	// SetSyncWrites sets the SyncWrites.
+	SetSyncWrites(value bool) Options
+
+	// MaxHistory is the number of versions of each key held by the store,
+	// older versions are dropped from memory and from the log when it is
+	// compacted.
+	MaxHistory() int
+	// SetMaxHistory sets the MaxHistory.
+	SetMaxHistory(value int) Options
+
+	// CompactionMinBytes is the size the log must reach before it is
+	// compacted, the log is compacted once it has also doubled in size
+	// since the last compaction.
+	CompactionMinBytes() int64
+	// SetCompactionMinBytes sets the CompactionMinBytes.
+	SetCompactionMinBytes(value int64) Options
+
+	// InstrumentOptions is the instrument options.
+	InstrumentOptions() instrument.Options
+	// SetInstrumentOptions sets the InstrumentOptions.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// Validate validates the Options.
+	Validate() error
+}
+
+type options struct {
+	filePath           string
+	syncWrites         bool
+	maxHistory         int
+	compactionMinBytes int64
+	iOpts              instrument.Options
+}
+
+// NewOptions creates a new set of options.
+func NewOptions() Options {
+	return options{
+		syncWrites:         defaultSyncWrites,
+		maxHistory:         defaultMaxHistory,
+		compactionMinBytes: defaultCompactionMinBytes,
+		iOpts:              instrument.NewOptions(),
+	}
+}
+
+func (o options) Validate() error {
+	if o.filePath == "" {
+		return errNoFilePath
+	}
+	if o.maxHistory <= 0 {
+		return errInvalidMaxHistory
+	}
+	if o.compactionMinBytes < 0 {
+		return errInvalidCompactionMinBytes
+	}
+	if o.iOpts == nil {
+		return errNoInstrumentOptions
+	}
+	return nil
+}
+
+func (o options) FilePath() string {
+	return o.filePath
+}
+
+func (o options) SetFilePath(value string) Options {
+	o.filePath = value
+	return o
+}
+
+func (o options) SyncWrites() bool {
+	return o.syncWrites
+}
+
+func (o options) SetSyncWrites(value bool) Options {
+	o.syncWrites = value
+	return o
+}
+
+func (o options) MaxHistory() int {
+	return o.maxHistory
+}
+
+func (o options) SetMaxHistory(value int) Options {
+	o.maxHistory = value
+	return o
+}
+
+func (o options) CompactionMinBytes() int64 {
+	return o.compactionMinBytes
+}
+
+func (o options) SetCompactionMinBytes(value int64) Options {
+	o.compactionMinBytes = value
+	return o
+}
+
+func (o options) InstrumentOptions() instrument.Options {
+	return o.iOpts
+}
+
+func (o options) SetInstrumentOptions(value instrument.Options) Options {
+	o.iOpts = value
+	return o
+}
@@ -0,0 +1,705 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package file provides a kv store persisted to a local log file that is
+// compacted as it grows, intended for single node and development deployments
+// that do not run etcd.
+package file
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3x/log"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	defaultDirMode  = 0755
+	defaultFileMode = 0644
+
+	// recordHeaderLen is the length of the size and checksum prefix of a record.
+	recordHeaderLen = 8
+	// maxRecordLen bounds the size of a single record to detect garbage sizes
+	// in a corrupted log.
+	maxRecordLen = 1 << 28
+	// versionLen is the length of the version prefix of the data of an
+	// opSetVersion op.
+	versionLen = 8
+
+	lockFileSuffix    = ".lock"
+	compactFileSuffix = ".compact"
+)
+
+var (
+	errStoreClosed   = errors.New("store is closed")
+	errInvalidOp     = errors.New("invalid op")
+	errInvalidCond   = errors.New("invalid condition")
+	errCorruptRecord = errors.New("corrupt record")
+	errBadRequest    = errors.New("bad request")
+)
+
+// Store is a kv.TxnStore persisted to a local file.
+type Store interface {
+	kv.TxnStore
+
+	// Close closes the store and its backing file.
+	Close() error
+}
+
+type opType uint8
+
+const (
+	opSet opType = iota + 1
+	opDelete
+	// opSetVersion sets a value at the version stored in the op, it is used
+	// by compacted logs which no longer hold every version of a key.
+	opSetVersion
+)
+
+type logOp struct {
+	opType opType
+	key    string
+	data   []byte
+}
+
+// store keeps the latest versions of every key in memory and appends each
+// mutation to the log file before applying it, replaying the log on open.
+// Replaying the mutations in order reproduces the same versions, so values,
+// versions and history all survive restarts. Once the log has grown to twice
+// the size it had after the last compaction it is compacted by rewriting it
+// with only the versions still held in memory.
+type store struct {
+	sync.RWMutex
+
+	revision   int
+	values     map[string][]*value
+	watchables map[string]kv.ValueWatchable
+
+	path               string
+	lockFile           *os.File
+	file               *os.File
+	size               int64
+	compactedSize      int64
+	maxHistory         int
+	compactionMinBytes int64
+	syncWrites         bool
+	logger             log.Logger
+	err                error
+	closed             bool
+}
+
+// NewStore opens or creates the file at the configured path and returns a
+// store holding its contents. The store holds an exclusive lock on the file
+// until it is closed, so a file can only be opened by a single store.
+func NewStore(opts Options) (Store, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := opts.FilePath()
+	if err := os.MkdirAll(filepath.Dir(path), defaultDirMode); err != nil {
+		return nil, err
+	}
+
+	// NB: The log file is replaced when it is compacted, so the lock is held
+	// on a separate file that is never replaced.
+	lockFile, err := acquireLock(path + lockFileSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, defaultFileMode)
+	if err != nil {
+		lockFile.Close()
+		return nil, err
+	}
+
+	s := &store{
+		values:             make(map[string][]*value),
+		watchables:         make(map[string]kv.ValueWatchable),
+		path:               path,
+		lockFile:           lockFile,
+		file:               f,
+		maxHistory:         opts.MaxHistory(),
+		compactionMinBytes: opts.CompactionMinBytes(),
+		syncWrites:         opts.SyncWrites(),
+		logger:             opts.InstrumentOptions().Logger(),
+	}
+	if err := s.replay(); err != nil {
+		f.Close()
+		lockFile.Close()
+		return nil, fmt.Errorf("could not replay kv log %s: %v", path, err)
+	}
+	s.compactedSize = s.size
+	s.maybeCompactWithLock()
+	return s, nil
+}
+
+// acquireLock opens the file at the given path and takes an exclusive lock
+// on it, failing immediately if the lock is held by another store.
+func acquireLock(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, defaultFileMode)
+	if err != nil {
+		return nil, err
+	}
+	if err := unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB); err != nil {
+		f.Close()
+		if err == unix.EWOULDBLOCK {
+			return nil, fmt.Errorf("kv log lock %s is held by another process", path)
+		}
+		return nil, fmt.Errorf("could not lock kv log lock %s: %v", path, err)
+	}
+	return f, nil
+}
+
+func (s *store) Get(key string) (kv.Value, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.getWithLock(key)
+}
+
+func (s *store) getWithLock(key string) (kv.Value, error) {
+	vals := s.values[key]
+	if len(vals) == 0 {
+		return nil, kv.ErrNotFound
+	}
+	return vals[len(vals)-1], nil
+}
+
+func (s *store) Watch(key string) (kv.ValueWatch, error) {
+	s.Lock()
+	vals := s.values[key]
+	watchable, ok := s.watchables[key]
+	if !ok {
+		watchable = kv.NewValueWatchable()
+		s.watchables[key] = watchable
+	}
+	s.Unlock()
+
+	if !ok && len(vals) != 0 {
+		watchable.Update(vals[len(vals)-1])
+	}
+
+	_, watch, _ := watchable.Watch()
+	return watch, nil
+}
+
+// History returns the values in the version range [from, to) that are still
+// held by the store, at most MaxHistory versions are held for each key.
+func (s *store) History(key string, from, to int) ([]kv.Value, error) {
+	if from <= 0 || to <= 0 || from > to {
+		return nil, errBadRequest
+	}
+	if from == to {
+		return nil, nil
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	vals := s.values[key]
+	if len(vals) == 0 {
+		return nil, kv.ErrNotFound
+	}
+
+	var res []kv.Value
+	for _, v := range vals {
+		if v.version >= from && v.version < to {
+			res = append(res, v)
+		}
+	}
+	return res, nil
+}
+
+func (s *store) Set(key string, v proto.Message) (int, error) {
+	data, err := proto.Marshal(v)
+	if err != nil {
+		return 0, err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.append([]logOp{{opType: opSet, key: key, data: data}}); err != nil {
+		return 0, err
+	}
+	version := s.setWithLock(key, data)
+	s.maybeCompactWithLock()
+	return version, nil
+}
+
+func (s *store) SetIfNotExists(key string, v proto.Message) (int, error) {
+	data, err := proto.Marshal(v)
+	if err != nil {
+		return 0, err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if len(s.values[key]) != 0 {
+		return 0, kv.ErrAlreadyExists
+	}
+
+	if err := s.append([]logOp{{opType: opSet, key: key, data: data}}); err != nil {
+		return 0, err
+	}
+	version := s.setWithLock(key, data)
+	s.maybeCompactWithLock()
+	return version, nil
+}
+
+func (s *store) CheckAndSet(key string, version int, v proto.Message) (int, error) {
+	data, err := proto.Marshal(v)
+	if err != nil {
+		return 0, err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if version != s.versionWithLock(key) {
+		return 0, kv.ErrVersionMismatch
+	}
+
+	if err := s.append([]logOp{{opType: opSet, key: key, data: data}}); err != nil {
+		return 0, err
+	}
+	newVersion := s.setWithLock(key, data)
+	s.maybeCompactWithLock()
+	return newVersion, nil
+}
+
+func (s *store) Delete(key string) (kv.Value, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	prev, err := s.getWithLock(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.append([]logOp{{opType: opDelete, key: key}}); err != nil {
+		return nil, err
+	}
+	s.deleteWithLock(key)
+	s.maybeCompactWithLock()
+	return prev, nil
+}
+
+// Commit supports the same conditions and ops as the etcd backed store, i.e.
+// version equality conditions and set ops. All ops of a transaction are
+// persisted as a single record so they are replayed atomically.
+func (s *store) Commit(conditions []kv.Condition, ops []kv.Op) (kv.Response, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, condition := range conditions {
+		if condition.CompareType() != kv.CompareEqual || condition.TargetType() != kv.TargetVersion {
+			return nil, errInvalidCond
+		}
+		expectedVersion, ok := condition.Value().(int)
+		if !ok {
+			return nil, errInvalidCond
+		}
+		if s.versionWithLock(condition.Key()) != expectedVersion {
+			return nil, kv.ErrConditionCheckFailed
+		}
+	}
+
+	logOps := make([]logOp, 0, len(ops))
+	for _, op := range ops {
+		setOp, ok := op.(kv.SetOp)
+		if !ok || op.Type() != kv.OpSet {
+			return nil, errInvalidOp
+		}
+		data, err := proto.Marshal(setOp.Value)
+		if err != nil {
+			return nil, err
+		}
+		logOps = append(logOps, logOp{opType: opSet, key: setOp.Key(), data: data})
+	}
+
+	if err := s.append(logOps); err != nil {
+		return nil, err
+	}
+
+	oprs := make([]kv.OpResponse, 0, len(ops))
+	for i, op := range logOps {
+		version := s.setWithLock(op.key, op.data)
+		oprs = append(oprs, kv.NewOpResponse(ops[i]).SetValue(version))
+	}
+	s.maybeCompactWithLock()
+	return kv.NewResponse().SetResponses(oprs), nil
+}
+
+func (s *store) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return errStoreClosed
+	}
+	s.closed = true
+	err := s.file.Close()
+	// NB: Closing the lock file releases the lock.
+	if lockErr := s.lockFile.Close(); err == nil {
+		err = lockErr
+	}
+	return err
+}
+
+func (s *store) versionWithLock(key string) int {
+	vals := s.values[key]
+	if len(vals) == 0 {
+		return 0
+	}
+	return vals[len(vals)-1].version
+}
+
+// setWithLock sets the next version of the key and returns it.
+func (s *store) setWithLock(key string, data []byte) int {
+	version := s.versionWithLock(key) + 1
+	s.setVersionWithLock(key, version, data)
+	return version
+}
+
+// setVersionWithLock sets the value of the key at the given version and only
+// keeps the latest MaxHistory versions of the key.
+func (s *store) setVersionWithLock(key string, version int, data []byte) {
+	s.revision++
+	v := &value{
+		version:  version,
+		revision: s.revision,
+		data:     data,
+	}
+
+	vals := append(s.values[key], v)
+	if n := len(vals) - s.maxHistory; n > 0 {
+		// Copy the retained versions so the trimmed ones can be collected.
+		vals = append([]*value(nil), vals[n:]...)
+	}
+	s.values[key] = vals
+
+	if watchable, ok := s.watchables[key]; ok {
+		watchable.Update(v)
+	}
+}
+
+func (s *store) deleteWithLock(key string) {
+	if _, ok := s.values[key]; !ok {
+		return
+	}
+	delete(s.values, key)
+	if watchable, ok := s.watchables[key]; ok {
+		watchable.Update(nil)
+	}
+}
+
+// append writes a record holding the given ops to the log, it must be called
+// with the lock held.
+func (s *store) append(ops []logOp) error {
+	if s.closed {
+		return errStoreClosed
+	}
+	if s.err != nil {
+		return s.err
+	}
+
+	b := encodeRecord(ops)
+	if _, err := s.file.Write(b); err != nil {
+		// Drop any partially written record so later records are not
+		// appended after garbage, and stop accepting writes if that fails.
+		if truncErr := s.file.Truncate(s.size); truncErr != nil {
+			s.err = fmt.Errorf("kv log is in an unknown state: %v", truncErr)
+		}
+		return err
+	}
+	if s.syncWrites {
+		if err := s.file.Sync(); err != nil {
+			return err
+		}
+	}
+	s.size += int64(len(b))
+	return nil
+}
+
+// maybeCompactWithLock compacts the log once it has doubled in size since
+// the last compaction. A failed compaction leaves the log as it was, so the
+// error is only logged and the compaction retried after the next write.
+func (s *store) maybeCompactWithLock() {
+	if s.size < s.compactionMinBytes || s.size < 2*s.compactedSize {
+		return
+	}
+	if err := s.compactWithLock(); err != nil {
+		s.logger.Warnf("could not compact kv log %s: %v", s.path, err)
+	}
+}
+
+// compactWithLock rewrites the log with a record for every version held in
+// memory and replaces the log with it.
+func (s *store) compactWithLock() error {
+	compactPath := s.path + compactFileSuffix
+	f, err := os.OpenFile(compactPath, os.O_CREATE|os.O_RDWR|os.O_TRUNC|os.O_APPEND, defaultFileMode)
+	if err != nil {
+		return err
+	}
+
+	size, err := s.writeSnapshotWithLock(f)
+	if err == nil {
+		err = f.Sync()
+	}
+	if err == nil {
+		err = os.Rename(compactPath, s.path)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(compactPath)
+		return err
+	}
+
+	// The log is replaced from here on so the old file is closed regardless
+	// of whether syncing the directory succeeds.
+	if err := s.file.Close(); err != nil {
+		s.logger.Warnf("could not close compacted kv log %s: %v", s.path, err)
+	}
+	s.file = f
+	s.size = size
+	s.compactedSize = size
+	return syncDir(filepath.Dir(s.path))
+}
+
+func (s *store) writeSnapshotWithLock(f *os.File) (int64, error) {
+	var (
+		w    = bufio.NewWriter(f)
+		size int64
+	)
+	for key, vals := range s.values {
+		for _, v := range vals {
+			data := make([]byte, versionLen+len(v.data))
+			binary.BigEndian.PutUint64(data, uint64(v.version))
+			copy(data[versionLen:], v.data)
+			b := encodeRecord([]logOp{{opType: opSetVersion, key: key, data: data}})
+			if _, err := w.Write(b); err != nil {
+				return 0, err
+			}
+			size += int64(len(b))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (s *store) replay() error {
+	var (
+		r       = bufio.NewReader(s.file)
+		header  [recordHeaderLen]byte
+		payload []byte
+	)
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			if err == io.ErrUnexpectedEOF {
+				return s.truncate(err)
+			}
+			return err
+		}
+
+		size := binary.BigEndian.Uint32(header[:4])
+		checksum := binary.BigEndian.Uint32(header[4:])
+		if size > maxRecordLen {
+			return s.truncate(errCorruptRecord)
+		}
+		if cap(payload) < int(size) {
+			payload = make([]byte, size)
+		}
+		payload = payload[:size]
+		if _, err := io.ReadFull(r, payload); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return s.truncate(err)
+			}
+			return err
+		}
+		if crc32.ChecksumIEEE(payload) != checksum {
+			return s.truncate(errCorruptRecord)
+		}
+
+		ops, err := decodeRecord(payload)
+		if err != nil {
+			return s.truncate(err)
+		}
+		for _, op := range ops {
+			if err := s.apply(op); err != nil {
+				return err
+			}
+		}
+		s.size += int64(recordHeaderLen + len(payload))
+	}
+}
+
+func (s *store) apply(op logOp) error {
+	switch op.opType {
+	case opSet:
+		s.setWithLock(op.key, op.data)
+		return nil
+	case opSetVersion:
+		if len(op.data) < versionLen {
+			return errCorruptRecord
+		}
+		version := int(binary.BigEndian.Uint64(op.data))
+		if version <= s.versionWithLock(op.key) {
+			return errCorruptRecord
+		}
+		s.setVersionWithLock(op.key, version, op.data[versionLen:])
+		return nil
+	case opDelete:
+		s.deleteWithLock(op.key)
+		return nil
+	default:
+		return errInvalidOp
+	}
+}
+
+// truncate drops everything after the last complete record, which is left
+// behind by a crash in the middle of a write.
+func (s *store) truncate(reason error) error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	s.logger.Warnf("truncating %d bytes from the tail of kv log %s: %v",
+		info.Size()-s.size, s.file.Name(), reason)
+	return s.file.Truncate(s.size)
+}
+
+// encodeRecord encodes ops as a record in the format:
+// [size uint32][crc32 uint32][numOps uint16]{[type uint8][keyLen uint32][key][dataLen uint32][data]}...
+func encodeRecord(ops []logOp) []byte {
+	size := 2
+	for _, op := range ops {
+		size += 1 + 4 + len(op.key) + 4 + len(op.data)
+	}
+
+	b := make([]byte, recordHeaderLen+size)
+	payload := b[recordHeaderLen:]
+	binary.BigEndian.PutUint16(payload, uint16(len(ops)))
+	idx := 2
+	for _, op := range ops {
+		payload[idx] = byte(op.opType)
+		idx++
+		binary.BigEndian.PutUint32(payload[idx:], uint32(len(op.key)))
+		idx += 4
+		idx += copy(payload[idx:], op.key)
+		binary.BigEndian.PutUint32(payload[idx:], uint32(len(op.data)))
+		idx += 4
+		idx += copy(payload[idx:], op.data)
+	}
+
+	binary.BigEndian.PutUint32(b, uint32(size))
+	binary.BigEndian.PutUint32(b[4:], crc32.ChecksumIEEE(payload))
+	return b
+}
+
+func decodeRecord(payload []byte) ([]logOp, error) {
+	if len(payload) < 2 {
+		return nil, errCorruptRecord
+	}
+	numOps := int(binary.BigEndian.Uint16(payload))
+	ops := make([]logOp, 0, numOps)
+	idx := 2
+	for i := 0; i < numOps; i++ {
+		if idx+5 > len(payload) {
+			return nil, errCorruptRecord
+		}
+		t := opType(payload[idx])
+		idx++
+		key, n, err := decodeBytes(payload[idx:])
+		if err != nil {
+			return nil, err
+		}
+		idx += n
+		data, n, err := decodeBytes(payload[idx:])
+		if err != nil {
+			return nil, err
+		}
+		idx += n
+		// Copy the data since the payload buffer is reused across records.
+		ops = append(ops, logOp{opType: t, key: string(key), data: append([]byte(nil), data...)})
+	}
+	if idx != len(payload) {
+		return nil, errCorruptRecord
+	}
+	return ops, nil
+}
+
+func decodeBytes(b []byte) ([]byte, int, error) {
+	if len(b) < 4 {
+		return nil, 0, errCorruptRecord
+	}
+	l := int(binary.BigEndian.Uint32(b))
+	if l > len(b)-4 {
+		return nil, 0, errCorruptRecord
+	}
+	return b[4 : 4+l], 4 + l, nil
+}
+
+type value struct {
+	version  int
+	revision int
+	data     []byte
+}
+
+func (v *value) Version() int                      { return v.version }
+func (v *value) Unmarshal(msg proto.Message) error { return proto.Unmarshal(v.data, msg) }
+func (v *value) IsNewer(other kv.Value) bool {
+	otherValue, ok := other.(*value)
+	if !ok || v.revision == otherValue.revision {
+		return v.version > other.Version()
+	}
+	return v.revision > otherValue.revision
+}
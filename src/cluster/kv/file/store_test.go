@@ -0,0 +1,291 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package file
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/kvtest"
+	"github.com/m3db/m3/src/cluster/kv"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSurvivesRestart(t *testing.T) {
+	opts, cleanup := testOptions(t)
+	defer cleanup()
+
+	s := newTestStore(t, opts)
+	version, err := s.Set("foo", newMsg("1"))
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+	version, err = s.CheckAndSet("foo", 1, newMsg("2"))
+	require.NoError(t, err)
+	require.Equal(t, 2, version)
+	_, err = s.CheckAndSet("foo", 1, newMsg("3"))
+	require.Equal(t, kv.ErrVersionMismatch, err)
+
+	version, err = s.SetIfNotExists("bar", newMsg("bar1"))
+	require.NoError(t, err)
+	require.Equal(t, 1, version)
+	_, err = s.SetIfNotExists("bar", newMsg("bar2"))
+	require.Equal(t, kv.ErrAlreadyExists, err)
+
+	_, err = s.Set("baz", newMsg("baz1"))
+	require.NoError(t, err)
+	_, err = s.Delete("baz")
+	require.NoError(t, err)
+	_, err = s.Delete("baz")
+	require.Equal(t, kv.ErrNotFound, err)
+	require.NoError(t, s.Close())
+
+	s = newTestStore(t, opts)
+	defer s.Close()
+
+	verifyValue(t, s, "foo", "2", 2)
+	verifyValue(t, s, "bar", "bar1", 1)
+	_, err = s.Get("baz")
+	require.Equal(t, kv.ErrNotFound, err)
+
+	history, err := s.History("foo", 1, 3)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	for i, v := range history {
+		require.Equal(t, i+1, v.Version())
+	}
+
+	// Versions continue from where they were before the restart.
+	version, err = s.CheckAndSet("foo", 2, newMsg("3"))
+	require.NoError(t, err)
+	require.Equal(t, 3, version)
+}
+
+func TestStoreCommit(t *testing.T) {
+	opts, cleanup := testOptions(t)
+	defer cleanup()
+
+	s := newTestStore(t, opts)
+	_, err := s.Set("foo", newMsg("1"))
+	require.NoError(t, err)
+
+	resp, err := s.Commit(
+		[]kv.Condition{
+			kv.NewCondition().
+				SetKey("foo").
+				SetValue(1).
+				SetCompareType(kv.CompareEqual).
+				SetTargetType(kv.TargetVersion),
+		},
+		[]kv.Op{
+			kv.NewSetOp("foo", newMsg("2")),
+			kv.NewSetOp("bar", newMsg("bar1")),
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Responses(), 2)
+	require.Equal(t, "foo", resp.Responses()[0].Key())
+	require.Equal(t, 2, resp.Responses()[0].Value())
+	require.Equal(t, 1, resp.Responses()[1].Value())
+
+	_, err = s.Commit(
+		[]kv.Condition{
+			kv.NewCondition().
+				SetKey("foo").
+				SetValue(1).
+				SetCompareType(kv.CompareEqual).
+				SetTargetType(kv.TargetVersion),
+		},
+		[]kv.Op{kv.NewSetOp("bar", newMsg("bar2"))},
+	)
+	require.Equal(t, kv.ErrConditionCheckFailed, err)
+	require.NoError(t, s.Close())
+
+	s = newTestStore(t, opts)
+	defer s.Close()
+
+	verifyValue(t, s, "foo", "2", 2)
+	verifyValue(t, s, "bar", "bar1", 1)
+}
+
+func TestStoreWatch(t *testing.T) {
+	opts, cleanup := testOptions(t)
+	defer cleanup()
+
+	s := newTestStore(t, opts)
+	defer s.Close()
+
+	_, err := s.Set("foo", newMsg("1"))
+	require.NoError(t, err)
+
+	w, err := s.Watch("foo")
+	require.NoError(t, err)
+	<-w.C()
+	require.Equal(t, 1, w.Get().Version())
+
+	_, err = s.Set("foo", newMsg("2"))
+	require.NoError(t, err)
+	select {
+	case <-w.C():
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out waiting for watch update")
+	}
+	require.Equal(t, 2, w.Get().Version())
+}
+
+func TestStoreTruncatesPartialRecord(t *testing.T) {
+	opts, cleanup := testOptions(t)
+	defer cleanup()
+
+	s := newTestStore(t, opts)
+	_, err := s.Set("foo", newMsg("1"))
+	require.NoError(t, err)
+	_, err = s.Set("foo", newMsg("2"))
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	// Simulate a crash in the middle of writing the last record.
+	info, err := os.Stat(opts.FilePath())
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(opts.FilePath(), info.Size()-3))
+
+	s = newTestStore(t, opts)
+	verifyValue(t, s, "foo", "1", 1)
+
+	// Writes after the truncation are appended to the last complete record.
+	_, err = s.Set("foo", newMsg("3"))
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	s = newTestStore(t, opts)
+	defer s.Close()
+	verifyValue(t, s, "foo", "3", 2)
+}
+
+func TestStoreClosed(t *testing.T) {
+	opts, cleanup := testOptions(t)
+	defer cleanup()
+
+	s := newTestStore(t, opts)
+	require.NoError(t, s.Close())
+
+	_, err := s.Set("foo", newMsg("1"))
+	require.Equal(t, errStoreClosed, err)
+	require.Equal(t, errStoreClosed, s.Close())
+}
+
+func TestStoreLocked(t *testing.T) {
+	opts, cleanup := testOptions(t)
+	defer cleanup()
+
+	s := newTestStore(t, opts)
+	_, err := NewStore(opts)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "held by another process")
+	require.NoError(t, s.Close())
+
+	// The lock is released when the store is closed.
+	s = newTestStore(t, opts)
+	require.NoError(t, s.Close())
+}
+
+func TestStoreCompaction(t *testing.T) {
+	opts, cleanup := testOptions(t)
+	defer cleanup()
+
+	opts = opts.SetMaxHistory(2).SetCompactionMinBytes(256)
+	s := newTestStore(t, opts)
+	for i := 1; i <= 20; i++ {
+		version, err := s.Set("foo", newMsg(fmt.Sprintf("foo%d", i)))
+		require.NoError(t, err)
+		require.Equal(t, i, version)
+	}
+	_, err := s.Set("bar", newMsg("bar1"))
+	require.NoError(t, err)
+	_, err = s.Set("baz", newMsg("baz1"))
+	require.NoError(t, err)
+	_, err = s.Delete("baz")
+	require.NoError(t, err)
+
+	// Only the latest versions are held in memory.
+	history, err := s.History("foo", 1, 21)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, 19, history[0].Version())
+	require.Equal(t, 20, history[1].Version())
+
+	// The log has been compacted rather than holding every write.
+	info, err := os.Stat(opts.FilePath())
+	require.NoError(t, err)
+	require.True(t, info.Size() < 512, "log size %d", info.Size())
+	require.NoError(t, s.Close())
+
+	s = newTestStore(t, opts)
+	verifyValue(t, s, "foo", "foo20", 20)
+	verifyValue(t, s, "bar", "bar1", 1)
+	_, err = s.Get("baz")
+	require.Equal(t, kv.ErrNotFound, err)
+	history, err = s.History("foo", 1, 21)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, 19, history[0].Version())
+
+	// Versions continue from where they were before the compaction.
+	version, err := s.CheckAndSet("foo", 20, newMsg("foo21"))
+	require.NoError(t, err)
+	require.Equal(t, 21, version)
+	require.NoError(t, s.Close())
+
+	s = newTestStore(t, opts)
+	defer s.Close()
+	verifyValue(t, s, "foo", "foo21", 21)
+}
+
+func testOptions(t *testing.T) (Options, func()) {
+	dir, err := ioutil.TempDir("", "kv-file-store")
+	require.NoError(t, err)
+	opts := NewOptions().SetFilePath(filepath.Join(dir, "kv", "store.kvlog"))
+	return opts, func() { os.RemoveAll(dir) }
+}
+
+func newTestStore(t *testing.T, opts Options) Store {
+	s, err := NewStore(opts)
+	require.NoError(t, err)
+	return s
+}
+
+func verifyValue(t *testing.T, s kv.Store, key, expected string, version int) {
+	v, err := s.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, version, v.Version())
+
+	var msg kvtest.Foo
+	require.NoError(t, v.Unmarshal(&msg))
+	require.Equal(t, expected, msg.Msg)
+}
+
+func newMsg(msg string) *kvtest.Foo {
+	return &kvtest.Foo{Msg: msg}
+}
@@ -567,3 +567,45 @@ type shardSetMetadata struct {
 	groups map[string]struct{}
 	shards shard.Shards
 }
+
+func (a mirroredAlgorithm) Rebalance(
+	p placement.Placement,
+) (placement.Placement, error) {
+	if err := a.IsCompatibleWith(p); err != nil {
+		return nil, err
+	}
+
+	p, _, err := a.MarkAllShardsAvailable(p)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrorPlacement, err := mirrorFromPlacement(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if mirrorPlacement, err = a.shardedAlgo.Rebalance(mirrorPlacement); err != nil {
+		return nil, err
+	}
+
+	return placementFromMirror(mirrorPlacement, p.Instances(), p.ReplicaFactor())
+}
+
+func (a mirroredAlgorithm) UpdateInstanceWeights(
+	p placement.Placement,
+	weights map[string]uint32,
+) (placement.Placement, error) {
+	if err := a.IsCompatibleWith(p); err != nil {
+		return nil, err
+	}
+
+	// Instances in the same shard set must share the same weight, which is
+	// validated when the instances are grouped into the mirror placement.
+	p, err := updateInstanceWeights(p, weights)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Rebalance(p)
+}
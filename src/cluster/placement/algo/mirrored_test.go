@@ -1573,3 +1573,68 @@ func TestMirrorAlgoWithSimpleShardStateType(t *testing.T) {
 	assert.NoError(t, placement.Validate(p))
 	verifyAllShardsInAvailableState(t, p)
 }
+
+func TestMirrorUpdateInstanceWeights(t *testing.T) {
+	i1 := placement.NewInstance().
+		SetID("i1").
+		SetIsolationGroup("r1").
+		SetEndpoint("endpoint1").
+		SetShardSetID(1).
+		SetWeight(1)
+	i2 := placement.NewInstance().
+		SetID("i2").
+		SetIsolationGroup("r2").
+		SetEndpoint("endpoint2").
+		SetShardSetID(1).
+		SetWeight(1)
+	i3 := placement.NewInstance().
+		SetID("i3").
+		SetIsolationGroup("r1").
+		SetEndpoint("endpoint3").
+		SetShardSetID(2).
+		SetWeight(1)
+	i4 := placement.NewInstance().
+		SetID("i4").
+		SetIsolationGroup("r2").
+		SetEndpoint("endpoint4").
+		SetShardSetID(2).
+		SetWeight(1)
+
+	a := NewAlgorithm(placement.NewOptions().SetIsMirrored(true))
+	p, err := a.InitialPlacement([]placement.Instance{i1, i2, i3, i4}, []uint32{0, 1, 2, 3}, 2)
+	require.NoError(t, err)
+	for _, instance := range p.Instances() {
+		assert.Equal(t, 2, loadOnInstance(instance))
+	}
+
+	// Instances in the same shard set must have the same weight.
+	_, err = a.UpdateInstanceWeights(p, map[string]uint32{"i3": 3})
+	require.Error(t, err)
+
+	p, err = a.UpdateInstanceWeights(p, map[string]uint32{"i3": 3, "i4": 3})
+	require.NoError(t, err)
+	require.NoError(t, placement.Validate(p))
+
+	mi1, _ := p.Instance("i1")
+	mi2, _ := p.Instance("i2")
+	mi3, _ := p.Instance("i3")
+	mi4, _ := p.Instance("i4")
+	assert.Equal(t, 1, loadOnInstance(mi1))
+	assert.Equal(t, 1, loadOnInstance(mi2))
+	assert.Equal(t, 3, loadOnInstance(mi3))
+	assert.Equal(t, 3, loadOnInstance(mi4))
+	assert.True(t, mi1.Shards().Equals(mi2.Shards()))
+	assert.Equal(t, mi3.Shards().AllIDs(), mi4.Shards().AllIDs())
+	for _, s := range mi3.Shards().ShardsForState(shard.Initializing) {
+		assert.Equal(t, "i1", s.SourceID())
+	}
+	for _, s := range mi4.Shards().ShardsForState(shard.Initializing) {
+		assert.Equal(t, "i2", s.SourceID())
+	}
+
+	p, err = a.Rebalance(p)
+	require.NoError(t, err)
+	mi3, _ = p.Instance("i3")
+	assert.Equal(t, 3, mi3.Shards().NumShards())
+	verifyAllShardsInAvailableState(t, p)
+}
@@ -157,3 +157,24 @@ func (a nonShardedAlgorithm) MarkAllShardsAvailable(
 	// There is no shards in non-sharded algorithm.
 	return p, false, nil
 }
+
+func (a nonShardedAlgorithm) Rebalance(
+	p placement.Placement,
+) (placement.Placement, error) {
+	if err := a.IsCompatibleWith(p); err != nil {
+		return nil, err
+	}
+	// There is no shards in non-sharded algorithm.
+	return p, nil
+}
+
+func (a nonShardedAlgorithm) UpdateInstanceWeights(
+	p placement.Placement,
+	weights map[string]uint32,
+) (placement.Placement, error) {
+	if err := a.IsCompatibleWith(p); err != nil {
+		return nil, err
+	}
+
+	return updateInstanceWeights(p, weights)
+}
@@ -23,6 +23,7 @@ package algo
 import (
 	"errors"
 	"fmt"
+	"reflect"
 
 	"github.com/m3db/m3/src/cluster/placement"
 )
@@ -48,11 +49,31 @@ func (a shardedPlacementAlgorithm) IsCompatibleWith(p placement.Placement) error
 	return nil
 }
 
+// optionsForPlacement returns the options with the zone replica factors
+// persisted with the placement, the configured zone replica factors are only
+// used for placements that have none.
+func (a shardedPlacementAlgorithm) optionsForPlacement(
+	p placement.Placement,
+) (placement.Options, error) {
+	zoneRFs := p.ZoneReplicaFactors()
+	if len(zoneRFs) == 0 {
+		return a.opts, nil
+	}
+	if configured := a.opts.ZoneReplicaFactors(); len(configured) > 0 && !reflect.DeepEqual(configured, zoneRFs) {
+		return nil, fmt.Errorf("zone replica factors %v do not match the zone replica factors %v of the placement", configured, zoneRFs)
+	}
+	return a.opts.SetZoneReplicaFactors(zoneRFs), nil
+}
+
 func (a shardedPlacementAlgorithm) InitialPlacement(
 	instances []placement.Instance,
 	shards []uint32,
 	rf int,
 ) (placement.Placement, error) {
+	if zoneRFs := a.opts.ZoneReplicaFactors(); len(zoneRFs) > 0 && sumZoneReplicaFactors(zoneRFs) != rf {
+		return nil, fmt.Errorf("replica factor %d does not match the sum of zone replica factors %v", rf, zoneRFs)
+	}
+
 	ph := newInitHelper(placement.Instances(instances).Clone(), shards, a.opts)
 	if err := ph.placeShards(newShards(shards), nil, ph.Instances()); err != nil {
 		return nil, err
@@ -77,8 +98,17 @@ func (a shardedPlacementAlgorithm) AddReplica(p placement.Placement) (placement.
 		return nil, err
 	}
 
+	opts, err := a.optionsForPlacement(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if zoneRFs := opts.ZoneReplicaFactors(); len(zoneRFs) > 0 && p.ReplicaFactor()+1 > sumZoneReplicaFactors(zoneRFs) {
+		return nil, fmt.Errorf("could not add replica, replica factor %d would exceed the sum of zone replica factors %v", p.ReplicaFactor()+1, zoneRFs)
+	}
+
 	p = p.Clone()
-	ph := newAddReplicaHelper(p, a.opts)
+	ph := newAddReplicaHelper(p, opts)
 	if err := ph.placeShards(newShards(p.Shards()), nil, nonLeavingInstances(ph.Instances())); err != nil {
 		return nil, err
 	}
@@ -87,7 +117,7 @@ func (a shardedPlacementAlgorithm) AddReplica(p placement.Placement) (placement.
 		return nil, err
 	}
 
-	return tryCleanupShardState(ph.generatePlacement(), a.opts)
+	return tryCleanupShardState(ph.generatePlacement(), opts)
 }
 
 func (a shardedPlacementAlgorithm) RemoveInstances(
@@ -98,9 +128,14 @@ func (a shardedPlacementAlgorithm) RemoveInstances(
 		return nil, err
 	}
 
+	opts, err := a.optionsForPlacement(p)
+	if err != nil {
+		return nil, err
+	}
+
 	p = p.Clone()
 	for _, instanceID := range instanceIDs {
-		ph, leavingInstance, err := newRemoveInstanceHelper(p, instanceID, a.opts)
+		ph, leavingInstance, err := newRemoveInstanceHelper(p, instanceID, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -117,7 +152,7 @@ func (a shardedPlacementAlgorithm) RemoveInstances(
 			return nil, err
 		}
 	}
-	return tryCleanupShardState(p, a.opts)
+	return tryCleanupShardState(p, opts)
 }
 
 func (a shardedPlacementAlgorithm) AddInstances(
@@ -128,9 +163,14 @@ func (a shardedPlacementAlgorithm) AddInstances(
 		return nil, err
 	}
 
+	opts, err := a.optionsForPlacement(p)
+	if err != nil {
+		return nil, err
+	}
+
 	p = p.Clone()
 	for _, instance := range instances {
-		ph, addingInstance, err := newAddInstanceHelper(p, instance, a.opts, withLeavingShardsOnly)
+		ph, addingInstance, err := newAddInstanceHelper(p, instance, opts, withLeavingShardsOnly)
 		if err != nil {
 			return nil, err
 		}
@@ -142,7 +182,7 @@ func (a shardedPlacementAlgorithm) AddInstances(
 		p = ph.generatePlacement()
 	}
 
-	return tryCleanupShardState(p, a.opts)
+	return tryCleanupShardState(p, opts)
 }
 
 func (a shardedPlacementAlgorithm) ReplaceInstances(
@@ -154,8 +194,13 @@ func (a shardedPlacementAlgorithm) ReplaceInstances(
 		return nil, err
 	}
 
+	opts, err := a.optionsForPlacement(p)
+	if err != nil {
+		return nil, err
+	}
+
 	p = p.Clone()
-	ph, leavingInstances, addingInstances, err := newReplaceInstanceHelper(p, leavingInstanceIDs, addingInstances, a.opts)
+	ph, leavingInstances, addingInstances, err := newReplaceInstanceHelper(p, leavingInstanceIDs, addingInstances, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -169,13 +214,13 @@ func (a shardedPlacementAlgorithm) ReplaceInstances(
 			return nil, err
 		}
 		load := loadOnInstance(leavingInstance)
-		if load != 0 && !a.opts.AllowPartialReplace() {
+		if load != 0 && !opts.AllowPartialReplace() {
 			return nil, fmt.Errorf("could not fully replace all shards from %s, %d shards left unassigned",
 				leavingInstance.ID(), load)
 		}
 	}
 
-	if a.opts.AllowPartialReplace() {
+	if opts.AllowPartialReplace() {
 		// Place the shards left on the leaving instance to the rest of the cluster.
 		for _, leavingInstance := range leavingInstances {
 			if err = ph.placeShards(leavingInstance.Shards().All(), leavingInstance, ph.Instances()); err != nil {
@@ -194,7 +239,7 @@ func (a shardedPlacementAlgorithm) ReplaceInstances(
 			return nil, err
 		}
 	}
-	return tryCleanupShardState(p, a.opts)
+	return tryCleanupShardState(p, opts)
 }
 
 func (a shardedPlacementAlgorithm) MarkShardsAvailable(
@@ -218,3 +263,45 @@ func (a shardedPlacementAlgorithm) MarkAllShardsAvailable(
 
 	return markAllShardsAvailable(p, a.opts)
 }
+
+func (a shardedPlacementAlgorithm) Rebalance(
+	p placement.Placement,
+) (placement.Placement, error) {
+	if err := a.IsCompatibleWith(p); err != nil {
+		return nil, err
+	}
+
+	opts, err := a.optionsForPlacement(p)
+	if err != nil {
+		return nil, err
+	}
+
+	p = p.Clone()
+	ph := newHelper(p, p.ReplicaFactor(), opts)
+	// NB: The safe optimization only moves shards that have not been placed
+	// yet, which are never present in an existing placement, so rebalancing
+	// has to move Initializing and Available shards. Only the underloaded
+	// instances take shards from the most overloaded instances, but the
+	// number of moved shards is not guaranteed to be the minimum.
+	if err := ph.optimize(unsafe); err != nil {
+		return nil, err
+	}
+
+	return tryCleanupShardState(ph.generatePlacement(), opts)
+}
+
+func (a shardedPlacementAlgorithm) UpdateInstanceWeights(
+	p placement.Placement,
+	weights map[string]uint32,
+) (placement.Placement, error) {
+	if err := a.IsCompatibleWith(p); err != nil {
+		return nil, err
+	}
+
+	p, err := updateInstanceWeights(p, weights)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Rebalance(p)
+}
@@ -25,6 +25,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/m3db/m3/src/cluster/placement"
 	"github.com/m3db/m3/src/cluster/shard"
@@ -102,6 +103,7 @@ type helper struct {
 	uniqueShards        []uint32
 	instances           map[string]placement.Instance
 	maxShardSetID       uint32
+	zoneQuotas          map[string]int
 	log                 log.Logger
 	opts                placement.Options
 }
@@ -198,6 +200,7 @@ func newHelper(p placement.Placement, targetRF int, opts placement.Options) plac
 		instances:     make(map[string]placement.Instance, p.NumInstances()),
 		uniqueShards:  p.Shards(),
 		maxShardSetID: p.MaxShardSetID(),
+		zoneQuotas:    zoneReplicaQuotas(opts.ZoneReplicaFactors(), targetRF),
 		log:           opts.InstrumentOptions().Logger(),
 		opts:          opts,
 	}
@@ -241,33 +244,79 @@ func (ph *helper) scanCurrentLoad() {
 }
 
 func (ph *helper) buildTargetLoad() {
+	if len(ph.zoneQuotas) == 0 {
+		targetLoad := make(map[string]int, len(ph.instances))
+		ph.buildTargetLoadForInstances(targetLoad, ph.Instances(), ph.groupToWeightMap, ph.totalWeight, ph.rf)
+		ph.targetLoad = targetLoad
+		return
+	}
+
+	// Each zone holds a fixed number of replicas, so the target load is
+	// computed independently for the instances within each zone.
+	instancesByZone := make(map[string][]placement.Instance, len(ph.zoneQuotas))
+	for _, instance := range ph.instances {
+		instancesByZone[instance.Zone()] = append(instancesByZone[instance.Zone()], instance)
+	}
+
+	targetLoad := make(map[string]int, len(ph.instances))
+	for zone, instances := range instancesByZone {
+		var (
+			groupWeights = make(map[string]uint32)
+			totalWeight  uint32
+		)
+		for _, instance := range instances {
+			if instance.IsLeaving() {
+				continue
+			}
+			groupWeights[instance.IsolationGroup()] += instance.Weight()
+			totalWeight += instance.Weight()
+		}
+		ph.buildTargetLoadForInstances(targetLoad, instances, groupWeights, totalWeight, ph.zoneQuotas[zone])
+	}
+	ph.targetLoad = targetLoad
+}
+
+func (ph *helper) buildTargetLoadForInstances(
+	targetLoad map[string]int,
+	instances []placement.Instance,
+	groupWeights map[string]uint32,
+	totalWeight uint32,
+	rf int,
+) {
+	if rf <= 0 || totalWeight == 0 {
+		for _, instance := range instances {
+			if !instance.IsLeaving() {
+				targetLoad[instance.ID()] = 0
+			}
+		}
+		return
+	}
+
 	overWeightedGroups := 0
 	overWeight := uint32(0)
-	for _, weight := range ph.groupToWeightMap {
-		if isOverWeighted(weight, ph.totalWeight, ph.rf) {
+	for _, weight := range groupWeights {
+		if isOverWeighted(weight, totalWeight, rf) {
 			overWeightedGroups++
 			overWeight += weight
 		}
 	}
 
-	targetLoad := make(map[string]int, len(ph.instances))
-	for _, instance := range ph.instances {
+	for _, instance := range instances {
 		if instance.IsLeaving() {
 			// We should not set a target load for leaving instances.
 			continue
 		}
-		igWeight := ph.groupToWeightMap[instance.IsolationGroup()]
-		if isOverWeighted(igWeight, ph.totalWeight, ph.rf) {
+		igWeight := groupWeights[instance.IsolationGroup()]
+		if isOverWeighted(igWeight, totalWeight, rf) {
 			// If the instance is on a over-sized isolation group, the target load
 			// equals (shardLen / capacity of the isolation group).
 			targetLoad[instance.ID()] = int(math.Ceil(float64(ph.getShardLen()) * float64(instance.Weight()) / float64(igWeight)))
 		} else {
 			// If the instance is on a normal isolation group, get the target load
 			// with aware of other over-sized isolation group.
-			targetLoad[instance.ID()] = ph.getShardLen() * (ph.rf - overWeightedGroups) * int(instance.Weight()) / int(ph.totalWeight-overWeight)
+			targetLoad[instance.ID()] = ph.getShardLen() * (rf - overWeightedGroups) * int(instance.Weight()) / int(totalWeight-overWeight)
 		}
 	}
-	ph.targetLoad = targetLoad
 }
 
 func (ph *helper) Instances() []placement.Instance {
@@ -404,7 +453,8 @@ func (ph *helper) generatePlacement() placement.Placement {
 		SetIsSharded(true).
 		SetIsMirrored(ph.opts.IsMirrored()).
 		SetCutoverNanos(ph.opts.PlacementCutoverNanosFn()()).
-		SetMaxShardSetID(maxShardSetID)
+		SetMaxShardSetID(maxShardSetID).
+		SetZoneReplicaFactors(ph.opts.ZoneReplicaFactors())
 }
 
 func (ph *helper) placeShards(
@@ -601,9 +651,30 @@ func (ph *helper) canAssignInstance(shardID uint32, from, to placement.Instance)
 		// and i1 should be able to take it and mark it as "Available"
 		return false
 	}
+	if !ph.canAssignZone(shardID, from, to) {
+		return false
+	}
 	return ph.CanMoveShard(shardID, from, to.IsolationGroup())
 }
 
+// canAssignZone checks if the zone of the instance still has room for
+// another replica of the shard in a placement spanning multiple zones.
+func (ph *helper) canAssignZone(shardID uint32, from, to placement.Instance) bool {
+	if len(ph.zoneQuotas) == 0 {
+		return true
+	}
+	if from != nil && from.Zone() == to.Zone() {
+		return true
+	}
+	replicas := 0
+	for instance := range ph.shardToInstanceMap[shardID] {
+		if instance.Zone() == to.Zone() {
+			replicas++
+		}
+	}
+	return replicas < ph.zoneQuotas[to.Zone()]
+}
+
 func (ph *helper) assignShardToInstance(s shard.Shard, to placement.Instance) {
 	to.Shards().Add(s)
 
@@ -680,6 +751,48 @@ func (h *instanceHeap) Pop() interface{} {
 	return instance
 }
 
+// zoneReplicaQuotas distributes the target replica factor across zones
+// in sorted zone order until each zone reaches its replica factor, so
+// adding replicas one at a time eventually reaches the configured layout.
+func zoneReplicaQuotas(zoneRFs map[string]int, targetRF int) map[string]int {
+	if len(zoneRFs) == 0 {
+		return nil
+	}
+
+	zones := make([]string, 0, len(zoneRFs))
+	for zone := range zoneRFs {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+
+	quotas := make(map[string]int, len(zones))
+	for assigned := 0; assigned < targetRF; {
+		progressed := false
+		for _, zone := range zones {
+			if assigned == targetRF {
+				break
+			}
+			if quotas[zone] < zoneRFs[zone] {
+				quotas[zone]++
+				assigned++
+				progressed = true
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+	return quotas
+}
+
+func sumZoneReplicaFactors(zoneRFs map[string]int) int {
+	sum := 0
+	for _, rf := range zoneRFs {
+		sum += rf
+	}
+	return sum
+}
+
 func isOverWeighted(igWeight, totalWeight uint32, rf int) bool {
 	return float64(igWeight)/float64(totalWeight) >= 1.0/float64(rf)
 }
@@ -861,3 +974,26 @@ func markAllShardsAvailable(
 	}
 	return p, updated, nil
 }
+
+// updateInstanceWeights returns a copy of the placement with the weights of
+// the given instances updated.
+func updateInstanceWeights(
+	p placement.Placement,
+	weights map[string]uint32,
+) (placement.Placement, error) {
+	p = p.Clone()
+	for id, weight := range weights {
+		instance, ok := p.Instance(id)
+		if !ok {
+			return nil, fmt.Errorf("instance %s does not exist in the placement", id)
+		}
+		if instance.IsLeaving() {
+			return nil, fmt.Errorf("could not update weight for leaving instance %s", id)
+		}
+		if weight == 0 {
+			return nil, fmt.Errorf("invalid weight %d for instance %s", weight, id)
+		}
+		instance.SetWeight(weight)
+	}
+	return p, nil
+}
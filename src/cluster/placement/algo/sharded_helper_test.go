@@ -611,6 +611,15 @@ func TestMarkShardAsAvailableWithValidation(t *testing.T) {
 	assert.NoError(t, placement.Validate(p))
 }
 
+func TestZoneReplicaQuotas(t *testing.T) {
+	zoneRFs := map[string]int{"b": 1, "a": 2}
+	assert.Nil(t, zoneReplicaQuotas(nil, 3))
+	assert.Equal(t, map[string]int{"a": 1}, zoneReplicaQuotas(zoneRFs, 1))
+	assert.Equal(t, map[string]int{"a": 1, "b": 1}, zoneReplicaQuotas(zoneRFs, 2))
+	assert.Equal(t, map[string]int{"a": 2, "b": 1}, zoneReplicaQuotas(zoneRFs, 3))
+	assert.Equal(t, map[string]int{"a": 2, "b": 1}, zoneReplicaQuotas(zoneRFs, 4))
+}
+
 func TestRemoveInstanceFromArray(t *testing.T) {
 	instances := []placement.Instance{
 		placement.NewEmptyInstance("i1", "", "", "endpoint", 1),
@@ -1226,6 +1226,174 @@ func TestGoodCaseWithSimpleShardStateType(t *testing.T) {
 	verifyAllShardsInAvailableState(t, p)
 }
 
+func TestRebalance(t *testing.T) {
+	i1 := placement.NewEmptyInstance("i1", "r1", "z1", "e1", 1)
+	for id := uint32(0); id < 6; id++ {
+		i1.Shards().Add(shard.NewShard(id).SetState(shard.Available))
+	}
+	i2 := placement.NewEmptyInstance("i2", "r2", "z1", "e2", 1)
+	for id := uint32(6); id < 8; id++ {
+		i2.Shards().Add(shard.NewShard(id).SetState(shard.Available))
+	}
+
+	p := placement.NewPlacement().
+		SetInstances([]placement.Instance{i1, i2}).
+		SetShards([]uint32{0, 1, 2, 3, 4, 5, 6, 7}).
+		SetReplicaFactor(1).
+		SetIsSharded(true)
+
+	a := newShardedAlgorithm(placement.NewOptions())
+	p, err := a.Rebalance(p)
+	require.NoError(t, err)
+	require.NoError(t, placement.Validate(p))
+
+	i1, _ = p.Instance("i1")
+	i2, _ = p.Instance("i2")
+	assert.Equal(t, 4, loadOnInstance(i1))
+	assert.Equal(t, 2, i1.Shards().NumShardsForState(shard.Leaving))
+	assert.Equal(t, 4, loadOnInstance(i2))
+	initShards := i2.Shards().ShardsForState(shard.Initializing)
+	assert.Equal(t, 2, len(initShards))
+	for _, s := range initShards {
+		assert.Equal(t, "i1", s.SourceID())
+	}
+
+	// Rebalancing a balanced placement does not move any shards.
+	p, _ = mustMarkAllShardsAsAvailable(t, p, nil)
+	p, err = a.Rebalance(p)
+	require.NoError(t, err)
+	verifyAllShardsInAvailableState(t, p)
+	for _, instance := range p.Instances() {
+		assert.Equal(t, 4, loadOnInstance(instance))
+	}
+}
+
+func TestUpdateInstanceWeights(t *testing.T) {
+	i1 := placement.NewEmptyInstance("i1", "r1", "z1", "e1", 1)
+	i2 := placement.NewEmptyInstance("i2", "r2", "z1", "e2", 1)
+	i3 := placement.NewEmptyInstance("i3", "r3", "z1", "e3", 1)
+
+	a := newShardedAlgorithm(placement.NewOptions())
+	p, err := a.InitialPlacement([]placement.Instance{i1, i2, i3}, []uint32{0, 1, 2, 3, 4, 5}, 1)
+	require.NoError(t, err)
+	p, _ = mustMarkAllShardsAsAvailable(t, p, nil)
+	for _, instance := range p.Instances() {
+		assert.Equal(t, 2, loadOnInstance(instance))
+	}
+
+	_, err = a.UpdateInstanceWeights(p, map[string]uint32{"i4": 2})
+	require.Error(t, err)
+
+	_, err = a.UpdateInstanceWeights(p, map[string]uint32{"i3": 0})
+	require.Error(t, err)
+
+	newP, err := a.UpdateInstanceWeights(p, map[string]uint32{"i3": 4})
+	require.NoError(t, err)
+	require.NoError(t, placement.Validate(newP))
+
+	// The original placement is not modified.
+	i3, _ = p.Instance("i3")
+	assert.Equal(t, uint32(1), i3.Weight())
+
+	i3, _ = newP.Instance("i3")
+	assert.Equal(t, uint32(4), i3.Weight())
+	assert.Equal(t, 4, loadOnInstance(i3))
+	assert.Equal(t, 2, i3.Shards().NumShardsForState(shard.Initializing))
+	for _, id := range []string{"i1", "i2"} {
+		instance, _ := newP.Instance(id)
+		assert.Equal(t, 1, loadOnInstance(instance))
+		assert.Equal(t, 1, instance.Shards().NumShardsForState(shard.Leaving))
+	}
+}
+
+func TestZoneReplicaFactorsAcrossThreeZones(t *testing.T) {
+	instances := []placement.Instance{
+		placement.NewEmptyInstance("i1", "r1", "z1", "e1", 1),
+		placement.NewEmptyInstance("i2", "r2", "z1", "e2", 1),
+		placement.NewEmptyInstance("i3", "r3", "z2", "e3", 1),
+		placement.NewEmptyInstance("i4", "r4", "z2", "e4", 1),
+		placement.NewEmptyInstance("i5", "r5", "z3", "e5", 1),
+		placement.NewEmptyInstance("i6", "r6", "z3", "e6", 1),
+	}
+	ids := make([]uint32, 64)
+	for i := range ids {
+		ids[i] = uint32(i)
+	}
+
+	zoneRFs := map[string]int{"z1": 1, "z2": 1, "z3": 1}
+	a := newShardedAlgorithm(placement.NewOptions().SetZoneReplicaFactors(zoneRFs))
+	p, err := a.InitialPlacement(instances, ids, 3)
+	require.NoError(t, err)
+	require.NoError(t, placement.Validate(p))
+	validateZoneReplicas(t, p, zoneRFs)
+	for _, instance := range p.Instances() {
+		assert.Equal(t, 32, loadOnInstance(instance))
+	}
+
+	_, err = a.AddReplica(p)
+	require.Error(t, err)
+}
+
+func TestZoneReplicaFactorsUneven(t *testing.T) {
+	instances := []placement.Instance{
+		placement.NewEmptyInstance("i1", "r1", "z1", "e1", 1),
+		placement.NewEmptyInstance("i2", "r2", "z1", "e2", 1),
+		placement.NewEmptyInstance("i3", "r3", "z1", "e3", 1),
+		placement.NewEmptyInstance("i4", "r4", "z1", "e4", 1),
+		placement.NewEmptyInstance("i5", "r5", "z2", "e5", 1),
+		placement.NewEmptyInstance("i6", "r6", "z2", "e6", 1),
+	}
+	ids := make([]uint32, 64)
+	for i := range ids {
+		ids[i] = uint32(i)
+	}
+
+	zoneRFs := map[string]int{"z1": 2, "z2": 1}
+	opts := placement.NewOptions().SetZoneReplicaFactors(zoneRFs)
+	a := newShardedAlgorithm(opts)
+
+	_, err := a.InitialPlacement(instances, ids, 2)
+	require.Error(t, err)
+
+	p, err := a.InitialPlacement(instances, ids, 3)
+	require.NoError(t, err)
+	require.NoError(t, placement.Validate(p))
+	validateZoneReplicas(t, p, zoneRFs)
+	require.Equal(t, zoneRFs, p.ZoneReplicaFactors())
+
+	// The zone replica factors are persisted with the placement and used for
+	// later operations without being configured again.
+	pb, err := p.Proto()
+	require.NoError(t, err)
+	p, err = placement.NewPlacementFromProto(pb)
+	require.NoError(t, err)
+	require.Equal(t, zoneRFs, p.ZoneReplicaFactors())
+	opts = placement.NewOptions()
+	a = newShardedAlgorithm(opts)
+
+	_, err = newShardedAlgorithm(opts.SetZoneReplicaFactors(map[string]int{"z1": 1, "z2": 2})).
+		AddInstances(p, []placement.Instance{placement.NewEmptyInstance("i7", "r7", "z2", "e7", 1)})
+	require.Error(t, err)
+
+	p, _ = mustMarkAllShardsAsAvailable(t, p, opts)
+	p, err = a.AddInstances(p, []placement.Instance{placement.NewEmptyInstance("i7", "r7", "z2", "e7", 1)})
+	require.NoError(t, err)
+	require.NoError(t, placement.Validate(p))
+	validateZoneReplicas(t, p, zoneRFs)
+
+	p, _ = mustMarkAllShardsAsAvailable(t, p, opts)
+	p, err = a.RemoveInstances(p, []string{"i1"})
+	require.NoError(t, err)
+	require.NoError(t, placement.Validate(p))
+	validateZoneReplicas(t, p, zoneRFs)
+
+	p, _ = mustMarkAllShardsAsAvailable(t, p, opts)
+	p, err = a.ReplaceInstances(p, []string{"i5"}, []placement.Instance{placement.NewEmptyInstance("i8", "r8", "z2", "e8", 1)})
+	require.NoError(t, err)
+	require.NoError(t, placement.Validate(p))
+	validateZoneReplicas(t, p, zoneRFs)
+}
+
 func verifyAllShardsInAvailableState(t *testing.T, p placement.Placement) {
 	for _, instance := range p.Instances() {
 		s := instance.Shards()
@@ -1265,6 +1433,20 @@ func validateCutoverCutoffNanos(t *testing.T, p placement.Placement, opts placem
 	assert.Equal(t, opts.PlacementCutoverNanosFn()(), p.CutoverNanos())
 }
 
+func validateZoneReplicas(t *testing.T, p placement.Placement, zoneRFs map[string]int) {
+	for _, shardID := range p.Shards() {
+		replicas := make(map[string]int, len(zoneRFs))
+		for _, instance := range p.Instances() {
+			s, ok := instance.Shards().Shard(shardID)
+			if !ok || s.State() == shard.Leaving {
+				continue
+			}
+			replicas[instance.Zone()]++
+		}
+		assert.Equal(t, zoneRFs, replicas, fmt.Sprintf("unexpected zone replicas for shard %d", shardID))
+	}
+}
+
 func validateDistribution(t *testing.T, p placement.Placement, expectPeakOverAvg float64) {
 	assert.NoError(t, placement.Validate(p), "placement validation failed")
 	ph := NewPlacementHelper(p, placement.NewOptions()).(*helper)
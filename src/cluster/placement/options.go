@@ -65,6 +65,7 @@ type options struct {
 	isStaged            bool
 	iopts               instrument.Options
 	validZone           string
+	zoneReplicaFactors  map[string]int
 	dryrun              bool
 	placementCutOverFn  TimeNanosFn
 	shardCutOverFn      TimeNanosFn
@@ -173,6 +174,15 @@ func (o options) SetValidZone(z string) Options {
 	return o
 }
 
+func (o options) ZoneReplicaFactors() map[string]int {
+	return o.zoneReplicaFactors
+}
+
+func (o options) SetZoneReplicaFactors(value map[string]int) Options {
+	o.zoneReplicaFactors = value
+	return o
+}
+
 func (o options) PlacementCutoverNanosFn() TimeNanosFn {
 	return o.placementCutOverFn
 }
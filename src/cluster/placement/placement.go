@@ -53,6 +53,7 @@ type placement struct {
 	isMirrored       bool
 	cutoverNanos     int64
 	maxShardSetID    uint32
+	zoneRFs          map[string]int
 	version          int
 }
 
@@ -79,6 +80,13 @@ func NewPlacementFromProto(p *placementpb.Placement) (Placement, error) {
 		}
 		instances = append(instances, pi)
 	}
+	var zoneRFs map[string]int
+	if len(p.ZoneReplicaFactors) > 0 {
+		zoneRFs = make(map[string]int, len(p.ZoneReplicaFactors))
+		for zone, rf := range p.ZoneReplicaFactors {
+			zoneRFs[zone] = int(rf)
+		}
+	}
 
 	return NewPlacement().
 		SetInstances(instances).
@@ -87,7 +95,8 @@ func NewPlacementFromProto(p *placementpb.Placement) (Placement, error) {
 		SetIsSharded(p.IsSharded).
 		SetCutoverNanos(p.CutoverTime).
 		SetIsMirrored(p.IsMirrored).
-		SetMaxShardSetID(p.MaxShardSetId), nil
+		SetMaxShardSetID(p.MaxShardSetId).
+		SetZoneReplicaFactors(zoneRFs), nil
 }
 
 func (p *placement) InstancesForShard(shard uint32) []Instance {
@@ -184,6 +193,15 @@ func (p *placement) SetMaxShardSetID(v uint32) Placement {
 	return p
 }
 
+func (p *placement) ZoneReplicaFactors() map[string]int {
+	return p.zoneRFs
+}
+
+func (p *placement) SetZoneReplicaFactors(value map[string]int) Placement {
+	p.zoneRFs = value
+	return p
+}
+
 func (p *placement) CutoverNanos() int64 {
 	return p.cutoverNanos
 }
@@ -218,15 +236,23 @@ func (p *placement) Proto() (*placementpb.Placement, error) {
 		}
 		instances[instance.ID()] = pi
 	}
+	var zoneRFs map[string]uint32
+	if len(p.zoneRFs) > 0 {
+		zoneRFs = make(map[string]uint32, len(p.zoneRFs))
+		for zone, rf := range p.zoneRFs {
+			zoneRFs[zone] = uint32(rf)
+		}
+	}
 
 	return &placementpb.Placement{
-		Instances:     instances,
-		ReplicaFactor: uint32(p.ReplicaFactor()),
-		NumShards:     uint32(p.NumShards()),
-		IsSharded:     p.IsSharded(),
-		CutoverTime:   p.CutoverNanos(),
-		IsMirrored:    p.IsMirrored(),
-		MaxShardSetId: p.MaxShardSetID(),
+		Instances:          instances,
+		ReplicaFactor:      uint32(p.ReplicaFactor()),
+		NumShards:          uint32(p.NumShards()),
+		IsSharded:          p.IsSharded(),
+		CutoverTime:        p.CutoverNanos(),
+		IsMirrored:         p.IsMirrored(),
+		MaxShardSetId:      p.MaxShardSetID(),
+		ZoneReplicaFactors: zoneRFs,
 	}, nil
 }
 
@@ -239,6 +265,7 @@ func (p *placement) Clone() Placement {
 		SetIsMirrored(p.IsMirrored()).
 		SetCutoverNanos(p.CutoverNanos()).
 		SetMaxShardSetID(p.MaxShardSetID()).
+		SetZoneReplicaFactors(p.ZoneReplicaFactors()).
 		SetVersion(p.Version())
 }
 
@@ -296,6 +323,7 @@ func (placements Placements) ActiveIndex(timeNanos int64) int {
 // - Each shard shows up rf times.
 // - There is one Initializing shard for each Leaving shard.
 // - The instances with same shard_set_id owns the same shards.
+// - Each shard has at most the replica factor of each zone in that zone.
 func Validate(p Placement) error {
 	if p.IsMirrored() && !p.IsSharded() {
 		return errMirrorNotSharded
@@ -392,6 +420,51 @@ func Validate(p Placement) error {
 			return fmt.Errorf("invalid shard count for shard %d: expected %d, actual %d", shard, p.ReplicaFactor(), c)
 		}
 	}
+	return validateZoneReplicaFactors(p)
+}
+
+// validateZoneReplicaFactors ensures the replicas of each shard are spread
+// across zones according to the zone replica factors. The zone replica
+// factors may add up to more than the replica factor while replicas are
+// being added one at a time, in which case no zone holds more than its
+// replica factor.
+func validateZoneReplicaFactors(p Placement) error {
+	zoneRFs := p.ZoneReplicaFactors()
+	if len(zoneRFs) == 0 {
+		return nil
+	}
+
+	sum := 0
+	for zone, rf := range zoneRFs {
+		if rf <= 0 {
+			return fmt.Errorf("invalid placement, zone %s has invalid replica factor %d", zone, rf)
+		}
+		sum += rf
+	}
+	if sum < p.ReplicaFactor() {
+		return fmt.Errorf("invalid placement, replica factor %d is larger than the sum of zone replica factors %v", p.ReplicaFactor(), zoneRFs)
+	}
+
+	zoneCounts := make(map[uint32]map[string]int, p.NumShards())
+	for _, instance := range p.Instances() {
+		if _, ok := zoneRFs[instance.Zone()]; !ok {
+			return fmt.Errorf("invalid placement, instance %s is in zone %s with no replica factor", instance.ID(), instance.Zone())
+		}
+		for _, s := range instance.Shards().All() {
+			if s.State() == shard.Leaving {
+				continue
+			}
+			counts, ok := zoneCounts[s.ID()]
+			if !ok {
+				counts = make(map[string]int, len(zoneRFs))
+				zoneCounts[s.ID()] = counts
+			}
+			counts[instance.Zone()]++
+			if counts[instance.Zone()] > zoneRFs[instance.Zone()] {
+				return fmt.Errorf("invalid placement, shard %d has more than %d replicas in zone %s", s.ID(), zoneRFs[instance.Zone()], instance.Zone())
+			}
+		}
+	}
 	return nil
 }
 
@@ -377,6 +377,44 @@ func TestValidateInstanceWithShardSetIDLargerThanMax(t *testing.T) {
 	assert.Contains(t, err.Error(), "larger than max shard set id")
 }
 
+func TestValidateZoneReplicaFactors(t *testing.T) {
+	i1 := NewEmptyInstance("i1", "r1", "z1", "e1", 1)
+	i1.Shards().Add(shard.NewShard(1).SetState(shard.Available))
+	i2 := NewEmptyInstance("i2", "r2", "z1", "e2", 1)
+	i2.Shards().Add(shard.NewShard(1).SetState(shard.Available))
+	i3 := NewEmptyInstance("i3", "r3", "z2", "e3", 1)
+	i3.Shards().Add(shard.NewShard(1).SetState(shard.Available))
+
+	p := NewPlacement().
+		SetInstances([]Instance{i1, i2, i3}).
+		SetShards([]uint32{1}).
+		SetReplicaFactor(3).
+		SetIsSharded(true)
+
+	p = p.SetZoneReplicaFactors(map[string]int{"z1": 2, "z2": 1})
+	assert.NoError(t, Validate(p))
+
+	// The zone replica factors may add up to more than the replica factor
+	// while replicas are added.
+	p = p.SetZoneReplicaFactors(map[string]int{"z1": 2, "z2": 2})
+	assert.NoError(t, Validate(p))
+
+	p = p.SetZoneReplicaFactors(map[string]int{"z1": 1, "z2": 2})
+	err := Validate(p)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "more than 1 replicas in zone z1")
+
+	p = p.SetZoneReplicaFactors(map[string]int{"z1": 2})
+	err = Validate(p)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "larger than the sum of zone replica factors")
+
+	p = p.SetZoneReplicaFactors(map[string]int{"z1": 2, "z3": 1})
+	err = Validate(p)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "in zone z2 with no replica factor")
+}
+
 func TestInstance(t *testing.T) {
 	i1 := NewInstance().
 		SetID("id").
@@ -552,6 +590,9 @@ func TestConvertBetweenProtoAndPlacement(t *testing.T) {
 		IsSharded:     true,
 		CutoverTime:   1234,
 		MaxShardSetId: 1,
+		ZoneReplicaFactors: map[string]uint32{
+			"z1": 2,
+		},
 	}
 
 	p, err := NewPlacementFromProto(placementProto)
@@ -562,6 +603,7 @@ func TestConvertBetweenProtoAndPlacement(t *testing.T) {
 	assert.Equal(t, []uint32{0, 1, 2}, p.Shards())
 	assert.Equal(t, int64(1234), p.CutoverNanos())
 	assert.Equal(t, uint32(1), p.MaxShardSetID())
+	assert.Equal(t, map[string]int{"z1": 2}, p.ZoneReplicaFactors())
 	instances := p.Instances()
 	assert.Equal(t, uint32(0), instances[0].ShardSetID())
 	assert.Equal(t, uint32(1), instances[1].ShardSetID())
@@ -572,6 +614,7 @@ func TestConvertBetweenProtoAndPlacement(t *testing.T) {
 	assert.Equal(t, placementProto.NumShards, placementProtoNew.NumShards)
 	assert.Equal(t, placementProto.CutoverTime, placementProtoNew.CutoverTime)
 	assert.Equal(t, placementProto.MaxShardSetId, placementProtoNew.MaxShardSetId)
+	assert.Equal(t, placementProto.ZoneReplicaFactors, placementProtoNew.ZoneReplicaFactors)
 	for id, h := range placementProto.Instances {
 		instance := placementProtoNew.Instances[id]
 		assert.Equal(t, h.Id, instance.Id)
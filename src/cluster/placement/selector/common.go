@@ -31,7 +31,8 @@ var (
 )
 
 // getValidCandidates finds the instances that are not already in
-// the placement and in the valid zone.
+// the placement and in the valid zone, or in one of the zones with a
+// replica factor for placements spanning multiple zones.
 func getValidCandidates(
 	p placement.Placement,
 	candidates []placement.Instance,
@@ -66,6 +67,22 @@ func filterZones(
 		return []placement.Instance{}
 	}
 
+	// The zone replica factors persisted with the placement take precedence
+	// over the configured ones.
+	zoneRFs := p.ZoneReplicaFactors()
+	if len(zoneRFs) == 0 && opts != nil {
+		zoneRFs = opts.ZoneReplicaFactors()
+	}
+	if len(zoneRFs) > 0 {
+		validInstances := make([]placement.Instance, 0, len(candidates))
+		for _, instance := range candidates {
+			if _, ok := zoneRFs[instance.Zone()]; ok {
+				validInstances = append(validInstances, instance)
+			}
+		}
+		return validInstances
+	}
+
 	var validZone string
 	if opts != nil {
 		validZone = opts.ValidZone()
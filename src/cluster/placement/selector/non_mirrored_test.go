@@ -275,6 +275,21 @@ func TestFilterZones(t *testing.T) {
 	}
 }
 
+func TestFilterZonesMultiZone(t *testing.T) {
+	i1 := placement.NewInstance().SetID("i1").SetZone("z1")
+	i2 := placement.NewInstance().SetID("i2").SetZone("z2")
+	i3 := placement.NewInstance().SetID("i3").SetZone("z3")
+
+	opts := placement.NewOptions().SetZoneReplicaFactors(map[string]int{"z1": 2, "z2": 1})
+	res := filterZones(placement.NewPlacement(), []placement.Instance{i1, i2, i3}, opts)
+	assert.Equal(t, []placement.Instance{i1, i2}, res)
+
+	// The zone replica factors of the placement take precedence.
+	p := placement.NewPlacement().SetZoneReplicaFactors(map[string]int{"z3": 1})
+	res = filterZones(p, []placement.Instance{i1, i2, i3}, opts)
+	assert.Equal(t, []placement.Instance{i3}, res)
+}
+
 func TestSelectAddingInstanceForNonMirrored(t *testing.T) {
 	i1 := placement.NewInstance().
 		SetID("i1").
@@ -295,3 +295,49 @@ func (ps *placementService) MarkAllShardsAvailable() (placement.Placement, error
 
 	return ps.CheckAndSet(tempPlacement, curPlacement.Version())
 }
+
+func (ps *placementService) Rebalance() (placement.Placement, error) {
+	curPlacement, err := ps.Placement()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ps.opts.ValidateFnBeforeUpdate()(curPlacement); err != nil {
+		return nil, err
+	}
+
+	tempPlacement, err := ps.algo.Rebalance(curPlacement)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := placement.Validate(tempPlacement); err != nil {
+		return nil, err
+	}
+
+	return ps.CheckAndSet(tempPlacement, curPlacement.Version())
+}
+
+func (ps *placementService) UpdateInstanceWeights(
+	weights map[string]uint32,
+) (placement.Placement, error) {
+	curPlacement, err := ps.Placement()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ps.opts.ValidateFnBeforeUpdate()(curPlacement); err != nil {
+		return nil, err
+	}
+
+	tempPlacement, err := ps.algo.UpdateInstanceWeights(curPlacement, weights)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := placement.Validate(tempPlacement); err != nil {
+		return nil, err
+	}
+
+	return ps.CheckAndSet(tempPlacement, curPlacement.Version())
+}
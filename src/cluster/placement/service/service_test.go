@@ -927,6 +927,45 @@ func TestValidateFnBeforeUpdate(t *testing.T) {
 	assert.Equal(t, expectErr, err)
 }
 
+func TestRebalanceAndUpdateInstanceWeights(t *testing.T) {
+	ps := NewPlacementService(newMockStorage(), placement.NewOptions().SetValidZone("z1"))
+
+	_, err := ps.Rebalance()
+	assert.Error(t, err)
+
+	_, err = ps.BuildInitialPlacement([]placement.Instance{
+		placement.NewEmptyInstance("i1", "r1", "z1", "endpoint1", 1),
+		placement.NewEmptyInstance("i2", "r2", "z1", "endpoint2", 1),
+	}, 8, 1)
+	require.NoError(t, err)
+	markAllInstancesAvailable(t, ps)
+
+	p, err := ps.Rebalance()
+	require.NoError(t, err)
+	for _, instance := range p.Instances() {
+		assert.Equal(t, 4, instance.Shards().NumShards())
+		assert.True(t, instance.IsAvailable())
+	}
+
+	_, err = ps.UpdateInstanceWeights(map[string]uint32{"i3": 1})
+	assert.Error(t, err)
+
+	p, err = ps.UpdateInstanceWeights(map[string]uint32{"i2": 3})
+	require.NoError(t, err)
+	i2, ok := p.Instance("i2")
+	require.True(t, ok)
+	assert.Equal(t, uint32(3), i2.Weight())
+	assert.Equal(t, 6, i2.Shards().NumShards())
+	assert.Equal(t, 2, i2.Shards().NumShardsForState(shard.Initializing))
+
+	markAllInstancesAvailable(t, ps)
+	p, err = ps.Placement()
+	require.NoError(t, err)
+	i1, ok := p.Instance("i1")
+	require.True(t, ok)
+	assert.Equal(t, 2, i1.Shards().NumShards())
+}
+
 func newMockStorage() placement.Storage {
 	return storage.NewPlacementStorage(mem.NewStore(), "", nil)
 }
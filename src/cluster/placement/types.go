@@ -166,6 +166,14 @@ type Placement interface {
 	// shard set id generations across placement changes.
 	SetMaxShardSetID(value uint32) Placement
 
+	// ZoneReplicaFactors returns the number of replicas of each shard placed
+	// in each zone for placements spanning multiple zones.
+	ZoneReplicaFactors() map[string]int
+
+	// SetZoneReplicaFactors sets the number of replicas of each shard placed
+	// in each zone for placements spanning multiple zones.
+	SetZoneReplicaFactors(value map[string]int) Placement
+
 	// String returns a description of the placement
 	String() string
 
@@ -392,6 +400,16 @@ type Options interface {
 	// instance.
 	SetValidZone(z string) Options
 
+	// ZoneReplicaFactors returns the number of replicas of each shard that
+	// must be placed in each zone. When set the placement spans the given
+	// zones instead of a single valid zone, and the replica factors must add
+	// up to the replica factor of the placement.
+	ZoneReplicaFactors() map[string]int
+
+	// SetZoneReplicaFactors sets the number of replicas of each shard that
+	// must be placed in each zone.
+	SetZoneReplicaFactors(value map[string]int) Options
+
 	// PlacementCutoverNanosFn returns the TimeNanosFn for placement cutover time.
 	PlacementCutoverNanosFn() TimeNanosFn
 
@@ -518,6 +536,14 @@ type Service interface {
 
 	// MarkAllShardsAvailable marks shard states as available where applicable.
 	MarkAllShardsAvailable() (Placement, error)
+
+	// Rebalance moves shards from overloaded instances to underloaded instances
+	// in the placement.
+	Rebalance() (Placement, error)
+
+	// UpdateInstanceWeights updates the weights of the given instances and
+	// rebalances the placement accordingly.
+	UpdateInstanceWeights(weights map[string]uint32) (Placement, error)
 }
 
 // Algorithm places shards on instances.
@@ -549,6 +575,15 @@ type Algorithm interface {
 
 	// MarkAllShardsAvailable marks shard states as available where applicable.
 	MarkAllShardsAvailable(p Placement) (Placement, bool, error)
+
+	// Rebalance moves shards from overloaded instances to underloaded
+	// instances in the placement, preferring shards that are still being
+	// initialized over available shards.
+	Rebalance(p Placement) (Placement, error)
+
+	// UpdateInstanceWeights updates the weights of the given instances and
+	// rebalances the placement accordingly.
+	UpdateInstanceWeights(p Placement, weights map[string]uint32) (Placement, error)
 }
 
 // InstanceSelector selects valid instances for the placement change.
@@ -655,8 +655,18 @@ func NewServiceFromProto(
 		r = append(r, instance)
 	}
 
+	var zoneRFs map[string]int
+	if len(p.ZoneReplicaFactors) > 0 {
+		zoneRFs = make(map[string]int, len(p.ZoneReplicaFactors))
+		for zone, rf := range p.ZoneReplicaFactors {
+			zoneRFs[zone] = int(rf)
+		}
+	}
+
 	return NewService().
-		SetReplication(NewServiceReplication().SetReplicas(int(p.ReplicaFactor))).
+		SetReplication(NewServiceReplication().
+			SetReplicas(int(p.ReplicaFactor)).
+			SetZoneReplicaFactors(zoneRFs)).
 		SetSharding(NewServiceSharding().SetNumShards(int(p.NumShards)).SetIsSharded(p.IsSharded)).
 		SetInstances(r), nil
 }
@@ -673,7 +683,9 @@ func NewServiceFromPlacement(p placement.Placement, sid ServiceID) Service {
 	}
 
 	return NewService().
-		SetReplication(NewServiceReplication().SetReplicas(p.ReplicaFactor())).
+		SetReplication(NewServiceReplication().
+			SetReplicas(p.ReplicaFactor()).
+			SetZoneReplicaFactors(p.ZoneReplicaFactors())).
 		SetSharding(NewServiceSharding().SetNumShards(p.NumShards()).SetIsSharded(p.IsSharded())).
 		SetInstances(serviceInstances)
 }
@@ -703,12 +715,19 @@ func (s *service) SetSharding(ss ServiceSharding) Service       { s.sharding = s
 func NewServiceReplication() ServiceReplication { return new(serviceReplication) }
 
 type serviceReplication struct {
-	replicas int
+	replicas           int
+	zoneReplicaFactors map[string]int
 }
 
 func (r *serviceReplication) Replicas() int                          { return r.replicas }
+func (r *serviceReplication) ZoneReplicaFactors() map[string]int     { return r.zoneReplicaFactors }
 func (r *serviceReplication) SetReplicas(rep int) ServiceReplication { r.replicas = rep; return r }
 
+func (r *serviceReplication) SetZoneReplicaFactors(value map[string]int) ServiceReplication {
+	r.zoneReplicaFactors = value
+	return r
+}
+
 // NewServiceSharding creates a new ServiceSharding.
 func NewServiceSharding() ServiceSharding { return new(serviceSharding) }
 
@@ -741,6 +760,7 @@ func NewServiceInstanceFromProto(
 		SetServiceID(sid).
 		SetInstanceID(instance.Id).
 		SetEndpoint(instance.Endpoint).
+		SetZone(instance.Zone).
 		SetShards(shards), nil
 }
 
@@ -753,6 +773,7 @@ func NewServiceInstanceFromPlacementInstance(
 		SetServiceID(sid).
 		SetInstanceID(instance.ID()).
 		SetEndpoint(instance.Endpoint()).
+		SetZone(instance.Zone()).
 		SetShards(instance.Shards())
 }
 
@@ -760,15 +781,18 @@ type serviceInstance struct {
 	service  ServiceID
 	id       string
 	endpoint string
+	zone     string
 	shards   shard.Shards
 }
 
 func (i *serviceInstance) InstanceID() string                       { return i.id }
 func (i *serviceInstance) Endpoint() string                         { return i.endpoint }
+func (i *serviceInstance) Zone() string                             { return i.zone }
 func (i *serviceInstance) Shards() shard.Shards                     { return i.shards }
 func (i *serviceInstance) ServiceID() ServiceID                     { return i.service }
 func (i *serviceInstance) SetInstanceID(id string) ServiceInstance  { i.id = id; return i }
 func (i *serviceInstance) SetEndpoint(e string) ServiceInstance     { i.endpoint = e; return i }
+func (i *serviceInstance) SetZone(z string) ServiceInstance         { i.zone = z; return i }
 func (i *serviceInstance) SetShards(s shard.Shards) ServiceInstance { i.shards = s; return i }
 
 func (i *serviceInstance) SetServiceID(service ServiceID) ServiceInstance {
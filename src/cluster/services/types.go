@@ -184,6 +184,13 @@ type ServiceReplication interface {
 
 	// SetReplicas sets the count of replicas.
 	SetReplicas(r int) ServiceReplication
+
+	// ZoneReplicaFactors is the count of replicas placed in each zone, it is
+	// empty if the replicas are not spread across zones.
+	ZoneReplicaFactors() map[string]int
+
+	// SetZoneReplicaFactors sets the count of replicas placed in each zone.
+	SetZoneReplicaFactors(value map[string]int) ServiceReplication
 }
 
 // ServiceSharding describes the sharding of a service.
@@ -221,6 +228,12 @@ type ServiceInstance interface {
 	// SetEndpoint sets the endpoint of the instance.
 	SetEndpoint(e string) ServiceInstance
 
+	// Zone returns the zone of the instance.
+	Zone() string
+
+	// SetZone sets the zone of the instance.
+	SetZone(z string) ServiceInstance
+
 	// Shards returns the shards of the instance.
 	Shards() shard.Shards
 
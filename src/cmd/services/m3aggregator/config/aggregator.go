@@ -149,6 +149,9 @@ type AggregatorConfiguration struct {
 
 	// Pool of entries.
 	EntryPool pool.ObjectPoolConfiguration `yaml:"entryPool"`
+
+	// Checkpointing of in-memory aggregation states, disabled if not set.
+	Checkpoint *checkpointConfiguration `yaml:"checkpoint"`
 }
 
 // NewAggregatorOptions creates a new set of aggregator options.
@@ -321,6 +324,15 @@ func (c *AggregatorConfiguration) NewAggregatorOptions(
 		opts = opts.SetDiscardNaNAggregatedValues(*c.DiscardNaNAggregatedValues)
 	}
 
+	// Set checkpoint options.
+	if c.Checkpoint != nil {
+		checkpointOpts, err := c.Checkpoint.NewCheckpointOptions()
+		if err != nil {
+			return nil, err
+		}
+		opts = opts.SetCheckpointOptions(checkpointOpts)
+	}
+
 	// Set counter elem pool.
 	iOpts = instrumentOpts.SetMetricsScope(scope.SubScope("counter-elem-pool"))
 	counterElemPoolOpts := c.CounterElemPool.NewObjectPoolOptions(iOpts)
@@ -706,6 +718,31 @@ func (c flushManagerConfiguration) NewFlushManagerOptions(
 	return opts, nil
 }
 
+type checkpointConfiguration struct {
+	// Directory where checkpoints are stored.
+	Directory string `yaml:"directory" validate:"nonzero"`
+
+	// How frequently the aggregation states are checkpointed.
+	CheckpointEvery time.Duration `yaml:"checkpointEvery"`
+
+	// Maximum age of a checkpoint for it to be restored.
+	MaxCheckpointAge time.Duration `yaml:"maxCheckpointAge"`
+}
+
+func (c checkpointConfiguration) NewCheckpointOptions() (aggregator.CheckpointOptions, error) {
+	opts := aggregator.NewCheckpointOptions().SetDirectory(c.Directory)
+	if c.CheckpointEvery != 0 {
+		opts = opts.SetCheckpointEvery(c.CheckpointEvery)
+	}
+	if c.MaxCheckpointAge != 0 {
+		opts = opts.SetMaxCheckpointAge(c.MaxCheckpointAge)
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
 // jitterBucket determines the max jitter percent for lists whose flush
 // intervals are no more than the bucket flush interval.
 type jitterBucket struct {
@@ -27,6 +27,7 @@ import (
 	"github.com/m3db/m3/src/aggregator/server/rawtcp"
 	"github.com/m3db/m3/src/metrics/encoding/msgpack"
 	"github.com/m3db/m3/src/metrics/encoding/protobuf"
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/instrument"
 	"github.com/m3db/m3x/pool"
 	"github.com/m3db/m3x/retry"
@@ -58,12 +59,15 @@ type RawTCPServerConfiguration struct {
 
 	// Protobuf iterator configuration.
 	ProtobufIterator protobufUnaggregatedIteratorConfiguration `yaml:"protobufIterator"`
+
+	// TLS configuration, if set connections are served over TLS.
+	TLS *xtls.Configuration `yaml:"tls"`
 }
 
 // NewServerOptions create a new set of raw TCP server options.
 func (c *RawTCPServerConfiguration) NewServerOptions(
 	instrumentOpts instrument.Options,
-) rawtcp.Options {
+) (rawtcp.Options, error) {
 	opts := rawtcp.NewOptions().SetInstrumentOptions(instrumentOpts)
 
 	// Set server options.
@@ -92,7 +96,14 @@ func (c *RawTCPServerConfiguration) NewServerOptions(
 	if c.ErrorLogLimitPerSecond != nil {
 		opts = opts.SetErrorLogLimitPerSecond(*c.ErrorLogLimitPerSecond)
 	}
-	return opts
+	if c.TLS != nil {
+		tlsConfig, err := c.TLS.NewServerConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts = opts.SetTLSConfig(tlsConfig)
+	}
+	return opts, nil
 }
 
 // msgpackUnaggregatedIteratorConfiguration contains configuration for msgpack unaggregated iterator.
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	m3aggregator "github.com/m3db/m3/src/aggregator/aggregator"
+	"github.com/m3db/m3/src/aggregator/aggregator/handler"
 	"github.com/m3db/m3/src/cmd/services/m3aggregator/config"
 	"github.com/m3db/m3/src/cmd/services/m3aggregator/serve"
 	xconfig "github.com/m3db/m3x/config"
@@ -78,7 +79,10 @@ func main() {
 	rawTCPAddr := cfg.RawTCP.ListenAddress
 	rawTCPServerScope := scope.SubScope("rawtcp-server").Tagged(map[string]string{"server": "rawtcp"})
 	iOpts := instrumentOpts.SetMetricsScope(rawTCPServerScope)
-	rawTCPServerOpts := cfg.RawTCP.NewServerOptions(iOpts)
+	rawTCPServerOpts, err := cfg.RawTCP.NewServerOptions(iOpts)
+	if err != nil {
+		logger.Fatalf("error creating the raw TCP server options: %v", err)
+	}
 
 	// Create the http server options.
 	httpAddr := cfg.HTTP.ListenAddress
@@ -100,6 +104,9 @@ func main() {
 	if err != nil {
 		logger.Fatalf("error creating aggregator options: %v", err)
 	}
+	if reporter, ok := aggregatorOpts.FlushHandler().(handler.ProducerStatsReporter); ok {
+		httpServerOpts = httpServerOpts.SetProducerStatsReporter(reporter)
+	}
 	aggregator := m3aggregator.NewAggregator(aggregatorOpts)
 	if err := aggregator.Open(); err != nil {
 		logger.Fatalf("error opening the aggregator: %v", err)
@@ -70,12 +70,46 @@ func TestDownsamplerAggregationWithAutoMappingRules(t *testing.T) {
 
 func TestDownsamplerAggregationWithRulesStore(t *testing.T) {
 	testDownsampler := newTestDownsampler(t, testDownsamplerOptions{})
+
+	addTestMappingRule(t, testDownsampler, "default")
+	waitForTestMappingRule(t, testDownsampler, map[string]string{
+		"__name__": "foo",
+		"app":      "test123",
+	})
+
+	// Test expected output
+	testDownsamplerAggregation(t, testDownsampler)
+}
+
+func TestDownsamplerAggregationWithRulesNamespace(t *testing.T) {
+	testDownsampler := newTestDownsampler(t, testDownsamplerOptions{
+		rulesNamespace: "tenant",
+	})
+
+	// Metrics are matched against the rules namespace even if they
+	// are tagged with another namespace.
+	addTestMappingRule(t, testDownsampler, "tenant")
+	waitForTestMappingRule(t, testDownsampler, map[string]string{
+		"__name__":  "foo",
+		"app":       "test123",
+		"namespace": "default",
+	})
+
+	// Test expected output
+	testDownsamplerAggregation(t, testDownsampler)
+}
+
+func addTestMappingRule(
+	t *testing.T,
+	testDownsampler testDownsampler,
+	namespace string,
+) {
 	rulesStore := testDownsampler.rulesStore
 
 	// Create rules
 	nss, err := rulesStore.ReadNamespaces()
 	require.NoError(t, err)
-	_, err = nss.AddNamespace("default", testUpdateMetadata())
+	_, err = nss.AddNamespace(namespace, testUpdateMetadata())
 	require.NoError(t, err)
 
 	rule := view.MappingRule{
@@ -86,23 +120,26 @@ func TestDownsamplerAggregationWithRulesStore(t *testing.T) {
 		StoragePolicies: testAggregationStoragePolicies,
 	}
 
-	rs := rules.NewEmptyRuleSet("default", testUpdateMetadata())
+	rs := rules.NewEmptyRuleSet(namespace, testUpdateMetadata())
 	_, err = rs.AddMappingRule(rule, testUpdateMetadata())
 	require.NoError(t, err)
 
 	err = rulesStore.WriteAll(nss, rs)
 	require.NoError(t, err)
+}
 
+func waitForTestMappingRule(
+	t *testing.T,
+	testDownsampler testDownsampler,
+	tags map[string]string,
+) {
 	logger := testDownsampler.instrumentOpts.Logger().
 		WithFields(xlog.NewField("test", t.Name()))
 
 	// Wait for mapping rule to appear
 	logger.Infof("waiting for mapping rules to propagate")
 	matcher := testDownsampler.matcher
-	testMatchID := newTestID(t, map[string]string{
-		"__name__": "foo",
-		"app":      "test123",
-	})
+	testMatchID := newTestID(t, tags)
 	for {
 		now := time.Now().UnixNano()
 		res := matcher.ForwardMatch(testMatchID, now, now+1)
@@ -112,9 +149,6 @@ func TestDownsamplerAggregationWithRulesStore(t *testing.T) {
 		}
 		time.Sleep(100 * time.Millisecond)
 	}
-
-	// Test expected output
-	testDownsamplerAggregation(t, testDownsampler)
 }
 
 func testDownsamplerAggregation(
@@ -231,6 +265,7 @@ type testDownsampler struct {
 
 type testDownsamplerOptions struct {
 	autoMappingRules []MappingRule
+	rulesNamespace   string
 	clockOpts        clock.Options
 	instrumentOpts   instrument.Options
 }
@@ -275,6 +310,7 @@ func newTestDownsampler(t *testing.T, opts testDownsamplerOptions) testDownsampl
 	instance, err := cfg.NewDownsampler(DownsamplerOptions{
 		Storage:               storage,
 		RulesKVStore:          rulesKVStore,
+		RulesNamespace:        opts.rulesNamespace,
 		AutoMappingRules:      opts.autoMappingRules,
 		ClockOptions:          clockOpts,
 		InstrumentOptions:     instrumentOpts,
@@ -80,6 +80,7 @@ type DownsamplerOptions struct {
 	Storage                 storage.Storage
 	StorageFlushConcurrency int
 	RulesKVStore            kv.Store
+	RulesNamespace          string
 	AutoMappingRules        []MappingRule
 	NameTag                 string
 	ClockOptions            clock.Options
@@ -449,6 +450,14 @@ func (o DownsamplerOptions) newAggregatorMatcher(
 		SetInstrumentOptions(instrumentOpts).
 		SetRuleSetOptions(ruleSetOpts).
 		SetKVStore(rulesStore)
+	if o.RulesNamespace != "" {
+		// NB: Metrics are always matched against the rules namespace when set
+		// rather than the namespace tag of metrics, so that metrics cannot be
+		// matched against the rules of other namespaces.
+		opts = opts.
+			SetNamespaceTag(nil).
+			SetDefaultNamespace([]byte(o.RulesNamespace))
+	}
 
 	cacheOpts := cache.NewOptions().
 		SetClockOptions(clockOpts).
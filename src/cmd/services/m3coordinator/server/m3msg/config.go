@@ -23,6 +23,7 @@ package m3msg
 import (
 	"github.com/m3db/m3/src/metrics/encoding/msgpack"
 	"github.com/m3db/m3/src/msg/consumer"
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/instrument"
 	"github.com/m3db/m3x/pool"
 	"github.com/m3db/m3x/server"
@@ -38,6 +39,9 @@ type Configuration struct {
 
 	// Consumer configs the consumer.
 	Consumer consumer.Configuration `yaml:"consumer"`
+
+	// TLS configs the server TLS, if set connections are served over TLS.
+	TLS *xtls.Configuration `yaml:"tls"`
 }
 
 // NewServer creates a new server.
@@ -55,10 +59,18 @@ func (c Configuration) NewServer(
 	if err != nil {
 		return nil, err
 	}
-	return c.Server.NewServer(
+	s := c.Server.NewServer(
 		h,
 		iOpts.SetMetricsScope(scope),
-	), nil
+	)
+	if c.TLS == nil {
+		return s, nil
+	}
+	tlsConfig, err := c.TLS.NewServerConfig()
+	if err != nil {
+		return nil, err
+	}
+	return xtls.NewServer(c.Server.ListenAddress, s, tlsConfig), nil
 }
 
 type handlerConfiguration struct {
@@ -32,6 +32,8 @@ import (
 	coordinatorcfg "github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/dbnode/environment"
+	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/config/hostid"
 	"github.com/m3db/m3x/instrument"
 	xlog "github.com/m3db/m3x/log"
@@ -81,9 +83,17 @@ type DBConfiguration struct {
 	// The HTTP host and port on which to listen for the cluster service.
 	HTTPClusterListenAddress string `yaml:"httpClusterListenAddress" validate:"nonzero"`
 
+	// The gRPC host and port on which to listen for the node service, the
+	// gRPC node service is not served if not set.
+	GRPCNodeListenAddress string `yaml:"grpcNodeListenAddress"`
+
 	// The host and port on which to listen for debug endpoints.
 	DebugListenAddress string `yaml:"debugListenAddress"`
 
+	// TLS configuration of the node and cluster services, if set they are
+	// served over TLS connections.
+	TLS *xtls.Configuration `yaml:"tls"`
+
 	// HostID is the local host ID configuration.
 	HostID hostid.Configuration `yaml:"hostID"`
 
@@ -195,6 +205,14 @@ type CommitLogPolicy struct {
 
 	// The commit log block size.
 	BlockSize time.Duration `yaml:"blockSize" validate:"nonzero"`
+
+	// The compression applied to the chunks of newly written commit logs, commit
+	// logs are readable regardless of the compression they were written with.
+	Compression *commitlog.CompressionType `yaml:"compression"`
+
+	// The directory that commit logs no longer required for bootstrap are
+	// archived to rather than deleted, commit logs are deleted if not set.
+	ArchiveDirectory string `yaml:"archiveDirectory"`
 }
 
 // CalculationType is a type of configuration parameter.
@@ -101,6 +101,8 @@ db:
             calculationType: fixed
             size: 2097152
         blockSize: 10m
+        compression: snappy
+        archiveDirectory: /var/lib/m3db-archive
 
     fs:
         filePathPrefix: /var/lib/m3db
@@ -331,7 +333,9 @@ db:
   clusterListenAddress: 0.0.0.0:9001
   httpNodeListenAddress: 0.0.0.0:9002
   httpClusterListenAddress: 0.0.0.0:9003
+  grpcNodeListenAddress: ""
   debugListenAddress: 0.0.0.0:9004
+  tls: null
   hostID:
     resolver: config
     value: host1
@@ -403,6 +407,8 @@ db:
       size: 2097152
     queueChannel: null
     blockSize: 10m0s
+    compression: snappy
+    archiveDirectory: /var/lib/m3db-archive
   repair:
     enabled: false
     interval: 2h0m0s
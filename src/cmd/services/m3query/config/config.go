@@ -23,12 +23,20 @@ package config
 import (
 	"time"
 
+	clusterclient "github.com/m3db/m3/src/cluster/client"
 	etcdclient "github.com/m3db/m3/src/cluster/client/etcd"
+	fileclient "github.com/m3db/m3/src/cluster/client/file"
+	"github.com/m3db/m3/src/cluster/kv"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/ingest"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/server/m3msg"
+	"github.com/m3db/m3/src/metrics/rules"
+	rulekv "github.com/m3db/m3/src/metrics/rules/store/kv"
+	"github.com/m3db/m3/src/metrics/rules/validator"
+	"github.com/m3db/m3/src/query/auth"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage/m3"
+	xtls "github.com/m3db/m3/src/x/tls"
 	xconfig "github.com/m3db/m3x/config"
 	"github.com/m3db/m3x/config/listenaddress"
 	"github.com/m3db/m3x/instrument"
@@ -59,6 +67,11 @@ type Configuration struct {
 	// query endpoints.
 	Clusters m3.ClustersStaticConfiguration `yaml:"clusters"`
 
+	// TimeStitchingPolicy determines whether reads spanning the retention of
+	// several namespaces are split into ranges served by the finest resolution
+	// namespace retaining each range.
+	TimeStitchingPolicy m3.TimeStitchingPolicy `yaml:"timeStitchingPolicy"`
+
 	// LocalConfiguration is the local embedded configuration if running
 	// coordinator embedded in the DB.
 	Local *LocalConfiguration `yaml:"local"`
@@ -70,6 +83,9 @@ type Configuration struct {
 	// ListenAddress is the server listen address.
 	ListenAddress *listenaddress.Configuration `yaml:"listenAddress" validate:"nonzero"`
 
+	// TLS is the server TLS configuration, if set the server listens over TLS.
+	TLS *xtls.Configuration `yaml:"tls"`
+
 	// Filter is the read/write/complete tags filter configuration.
 	Filter FilterConfiguration `yaml:"filter"`
 
@@ -96,6 +112,18 @@ type Configuration struct {
 
 	// Limits specifies limits on per-query resource usage.
 	Limits LimitsConfiguration `yaml:"limits"`
+
+	// Rules is the configuration for the mapping and rollup rules management
+	// endpoints (optional).
+	Rules *RulesConfiguration `yaml:"rules"`
+
+	// Tenants is the configuration of the tenants sharing the coordinator,
+	// each with their own cluster namespaces (optional).
+	Tenants *TenantsConfiguration `yaml:"tenants"`
+
+	// Auth is the configuration of the authentication and role based
+	// authorization of requests to the HTTP API (optional).
+	Auth *auth.Configuration `yaml:"auth"`
 }
 
 // Filter is a query filter type.
@@ -124,6 +152,40 @@ type LimitsConfiguration struct {
 	MaxComputedDatapoints int64 `yaml:"maxComputedDatapoints"`
 }
 
+// TenantsConfiguration is the configuration of the tenants sharing the
+// coordinator. Writes and queries made for a tenant are routed to the cluster
+// namespaces of the tenant rather than the top level clusters.
+type TenantsConfiguration struct {
+	// Header is the request header carrying the tenant ID, defaults to
+	// "M3-Tenant".
+	Header string `yaml:"header"`
+
+	// Required rejects requests not made for a tenant, otherwise they are
+	// routed to the top level clusters.
+	Required bool `yaml:"required"`
+
+	// Tenants are the tenants.
+	Tenants []TenantConfiguration `yaml:"tenants" validate:"nonzero"`
+}
+
+// TenantConfiguration is the configuration of a tenant.
+type TenantConfiguration struct {
+	// ID is the tenant ID.
+	ID string `yaml:"id" validate:"nonzero"`
+
+	// Clusters is the DB cluster configurations for the unaggregated and
+	// aggregated namespaces of the tenant.
+	Clusters m3.ClustersStaticConfiguration `yaml:"clusters" validate:"nonzero"`
+
+	// RulesNamespace is the mapping and rollup rules namespace used to
+	// downsample the writes of the tenant, defaults to the tenant ID.
+	RulesNamespace string `yaml:"rulesNamespace"`
+
+	// Limits specifies limits on per-query resource usage of the tenant,
+	// defaults to the top level limits.
+	Limits *LimitsConfiguration `yaml:"limits"`
+}
+
 // IngestConfiguration is the configuration for ingestion server.
 type IngestConfiguration struct {
 	// Ingester is the configuration for storage based ingester.
@@ -133,6 +195,52 @@ type IngestConfiguration struct {
 	M3Msg m3msg.Configuration `yaml:"m3msg"`
 }
 
+// RulesConfiguration is the configuration for the mapping and rollup rules
+// management endpoints.
+type RulesConfiguration struct {
+	// KVConfig is the kv configuration for the rules store.
+	KVConfig kv.OverrideConfiguration `yaml:"kvConfig"`
+
+	// NamespacesKey is the kv key that holds the rule namespaces.
+	NamespacesKey string `yaml:"namespacesKey" validate:"nonzero"`
+
+	// RuleSetKeyFmt is the format of the kv keys that hold the rulesets.
+	RuleSetKeyFmt string `yaml:"ruleSetKeyFmt" validate:"nonzero"`
+
+	// PropagationDelay is the delay between a rule change and when it takes effect.
+	PropagationDelay time.Duration `yaml:"propagationDelay"`
+
+	// NameTagKey is the tag key of the metric name used when previewing
+	// how metrics are matched against rules, defaults to "name".
+	NameTagKey string `yaml:"nameTagKey"`
+
+	// Validation is the rules validation configuration.
+	Validation validator.Configuration `yaml:"validation"`
+}
+
+// NewValidator creates a new rules validator.
+func (c RulesConfiguration) NewValidator(clusterClient clusterclient.Client) (rules.Validator, error) {
+	return c.Validation.NewValidator(clusterClient)
+}
+
+// NewStore creates a new rules store that validates rulesets with the
+// validator before they are written.
+func (c RulesConfiguration) NewStore(
+	clusterClient clusterclient.Client,
+	rulesValidator rules.Validator,
+) (rules.Store, error) {
+	kvOpts, err := c.KVConfig.NewOverrideOptions()
+	if err != nil {
+		return nil, err
+	}
+	txnStore, err := clusterClient.TxnStore(kvOpts)
+	if err != nil {
+		return nil, err
+	}
+	storeOpts := rulekv.NewStoreOptions(c.NamespacesKey, c.RuleSetKeyFmt, rulesValidator)
+	return rulekv.NewStore(txnStore, storeOpts), nil
+}
+
 // LocalConfiguration is the local embedded configuration if running
 // coordinator embedded in the DB.
 type LocalConfiguration struct {
@@ -148,6 +256,10 @@ type LocalConfiguration struct {
 type ClusterManagementConfiguration struct {
 	// Etcd is the client configuration for etcd.
 	Etcd etcdclient.Configuration `yaml:"etcd"`
+
+	// Local is the client configuration for cluster metadata persisted to
+	// local files, used instead of etcd when set.
+	Local *fileclient.Configuration `yaml:"local"`
 }
 
 // RPCConfiguration is the RPC configuration for the coordinator for
@@ -0,0 +1,266 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/client"
+	etcdclient "github.com/m3db/m3/src/cluster/client/etcd"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/msg/producer"
+	"github.com/m3db/m3/src/msg/producer/config"
+	"github.com/m3db/m3/src/msg/producer/deadletter"
+	"github.com/m3db/m3/src/msg/producer/writer"
+	"github.com/m3db/m3/src/msg/topic"
+	xconfig "github.com/m3db/m3x/config"
+	"github.com/m3db/m3x/instrument"
+	xlog "github.com/m3db/m3x/log"
+
+	"github.com/pborman/getopt"
+)
+
+// reinjectConfiguration configs the producer used to re-inject dead letters.
+type reinjectConfiguration struct {
+	Etcd     *etcdclient.Configuration    `yaml:"etcd" validate:"nonzero"`
+	Producer config.ProducerConfiguration `yaml:"producer"`
+}
+
+func main() {
+	var (
+		optPath            = getopt.StringLong("path", 'p', "", "Dead letter directory [e.g. /var/lib/m3msg/deadletter]")
+		optConsumerService = getopt.StringLong("consumer-service", 's', "", "If set, only dead letters of this consumer service are processed")
+		optShowValue       = getopt.BoolLong("show-value", 'v', "If set, the value of each dead letter is printed in hex")
+		optReinject        = getopt.StringLong("reinject-config", 'r', "", "If set, dead letters are re-injected with the producer in this config file")
+		optKeep            = getopt.BoolLong("keep", 'k', "If set, re-injected dead letters are not removed from the directory")
+		log                = xlog.NewLogger(os.Stderr)
+	)
+	getopt.Parse()
+
+	if *optPath == "" {
+		getopt.Usage()
+		os.Exit(1)
+	}
+
+	var (
+		numRead      int
+		numProcessed int
+		numSkipped   int
+		p            producer.Producer
+		consumed     = newConsumedLetters()
+	)
+	fn := func(dl writer.DeadLetter) error {
+		printDeadLetter(dl, *optShowValue)
+		return nil
+	}
+	if *optReinject != "" {
+		var cfg reinjectConfiguration
+		if err := xconfig.LoadFile(&cfg, *optReinject, xconfig.Options{}); err != nil {
+			log.Fatalf("unable to load %s: %v", *optReinject, err)
+		}
+		iOpts := instrument.NewOptions().SetLogger(log)
+		cs, err := cfg.Etcd.NewClient(iOpts)
+		if err != nil {
+			log.Fatalf("unable to create etcd client: %v", err)
+		}
+		consumerServices, err := topicConsumerServices(cs, cfg.Producer.Writer)
+		if err != nil {
+			log.Fatalf("unable to get consumer services of topic %s: %v", cfg.Producer.Writer.TopicName, err)
+		}
+		p, err = cfg.Producer.NewProducer(cs, iOpts)
+		if err != nil {
+			log.Fatalf("unable to create producer: %v", err)
+		}
+		// Each dead letter is only re-injected to the consumer service that
+		// failed to consume it.
+		for key, sid := range consumerServices {
+			key := key
+			p.RegisterFilter(sid, func(m producer.Message) bool {
+				return m.(*message).consumerService == key
+			})
+		}
+		if err := p.Init(); err != nil {
+			log.Fatalf("unable to initialize producer: %v", err)
+		}
+		fn = func(dl writer.DeadLetter) error {
+			if _, ok := consumerServices[dl.ConsumerService]; !ok {
+				numSkipped++
+				log.Warnf("consumer service %s is not in the topic, dead letter is not re-injected", dl.ConsumerService)
+				return nil
+			}
+			return p.Produce(newMessage(numProcessed-1, dl, consumed))
+		}
+	}
+
+	isProcessed := func(dl writer.DeadLetter) bool {
+		return *optConsumerService == "" || dl.ConsumerService == *optConsumerService
+	}
+	err := deadletter.ReadDir(*optPath, func(dl writer.DeadLetter) error {
+		numRead++
+		if !isProcessed(dl) {
+			return nil
+		}
+		numProcessed++
+		return fn(dl)
+	})
+	if p != nil {
+		// Wait for the re-injected messages to be consumed before exiting.
+		p.Close(producer.WaitForConsumption)
+	}
+	if p != nil && !*optKeep {
+		// NB: The dead letters are read in the same order as they were
+		// re-injected, so the processed dead letters are counted again to
+		// find the ones that have been consumed.
+		var index int
+		if rErr := deadletter.RewriteDir(*optPath, func(dl writer.DeadLetter) bool {
+			if !isProcessed(dl) {
+				return true
+			}
+			index++
+			return !consumed.contains(index - 1)
+		}); rErr != nil {
+			log.Errorf("unable to remove re-injected dead letters: %v", rErr)
+		}
+	}
+	if err != nil {
+		log.Fatalf("unable to process dead letters: %v", err)
+	}
+	if p != nil {
+		log.Infof(
+			"read %d dead letters, processed %d, skipped %d, re-injected %d",
+			numRead, numProcessed, numSkipped, consumed.len(),
+		)
+		return
+	}
+	log.Infof("read %d dead letters, processed %d", numRead, numProcessed)
+}
+
+// topicConsumerServices returns the consumer services of the topic the
+// producer writes to, keyed by the consumer service IDs recorded in dead letters.
+func topicConsumerServices(
+	cs client.Client,
+	cfg config.WriterConfiguration,
+) (map[string]services.ServiceID, error) {
+	kvOpts, err := cfg.TopicServiceOverride.NewOverrideOptions()
+	if err != nil {
+		return nil, err
+	}
+	ts, err := topic.NewService(
+		topic.NewServiceOptions().
+			SetConfigService(cs).
+			SetKVOverrideOptions(kvOpts),
+	)
+	if err != nil {
+		return nil, err
+	}
+	t, err := ts.Get(cfg.TopicName)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]services.ServiceID, len(t.ConsumerServices()))
+	for _, c := range t.ConsumerServices() {
+		res[c.ServiceID().String()] = c.ServiceID()
+	}
+	return res, nil
+}
+
+func printDeadLetter(dl writer.DeadLetter, showValue bool) {
+	fmt.Printf(
+		"consumerService=%s shard=%d reason=%s writeTimes=%d init=%s dead=%s size=%d\n",
+		dl.ConsumerService,
+		dl.Shard,
+		dl.Reason.String(),
+		dl.WriteTimes,
+		time.Unix(0, dl.InitNanos).UTC().Format(time.RFC3339Nano),
+		time.Unix(0, dl.DeadNanos).UTC().Format(time.RFC3339Nano),
+		len(dl.Value),
+	)
+	if showValue {
+		fmt.Println(hex.EncodeToString(dl.Value))
+	}
+}
+
+// consumedLetters tracks the indexes of the re-injected dead letters that
+// have been consumed.
+type consumedLetters struct {
+	sync.Mutex
+
+	indexes map[int]struct{}
+}
+
+func newConsumedLetters() *consumedLetters {
+	return &consumedLetters{indexes: make(map[int]struct{})}
+}
+
+func (c *consumedLetters) add(index int) {
+	c.Lock()
+	c.indexes[index] = struct{}{}
+	c.Unlock()
+}
+
+func (c *consumedLetters) contains(index int) bool {
+	c.Lock()
+	_, ok := c.indexes[index]
+	c.Unlock()
+	return ok
+}
+
+func (c *consumedLetters) len() int {
+	c.Lock()
+	n := len(c.indexes)
+	c.Unlock()
+	return n
+}
+
+// message is a re-injected dead letter.
+type message struct {
+	index           int
+	consumerService string
+	shard           uint32
+	bytes           []byte
+	consumed        *consumedLetters
+}
+
+func newMessage(index int, dl writer.DeadLetter, consumed *consumedLetters) producer.Message {
+	return &message{
+		index:           index,
+		consumerService: dl.ConsumerService,
+		shard:           dl.Shard,
+		bytes:           dl.Value,
+		consumed:        consumed,
+	}
+}
+
+func (m *message) Shard() uint32 { return m.shard }
+func (m *message) Bytes() []byte { return m.bytes }
+func (m *message) Size() int     { return len(m.bytes) }
+
+func (m *message) Finalize(r producer.FinalizeReason) {
+	// NB: Only the dead letters consumed by their consumer service are
+	// removed, the dropped ones are kept to be re-injected again.
+	if r == producer.Consumed {
+		m.consumed.add(m.index)
+	}
+}
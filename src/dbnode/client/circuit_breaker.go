@@ -0,0 +1,355 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/clock"
+
+	"github.com/uber-go/tally"
+	"github.com/uber/tchannel-go"
+)
+
+const (
+	// defaultHostCircuitBreakerEnabled is the default host circuit breaker enabled value
+	defaultHostCircuitBreakerEnabled = false
+
+	// defaultHostCircuitBreakerWindow is the default host circuit breaker window
+	defaultHostCircuitBreakerWindow = 10 * time.Second
+
+	// defaultHostCircuitBreakerMinRequests is the default host circuit breaker min requests
+	defaultHostCircuitBreakerMinRequests = 20
+
+	// defaultHostCircuitBreakerErrorRateThreshold is the default host circuit breaker error rate threshold
+	defaultHostCircuitBreakerErrorRateThreshold = 0.5
+
+	// defaultHostCircuitBreakerTimeoutRateThreshold is the default host circuit breaker timeout rate threshold
+	defaultHostCircuitBreakerTimeoutRateThreshold = 0.2
+
+	// defaultHostCircuitBreakerOpenDuration is the default host circuit breaker open duration
+	defaultHostCircuitBreakerOpenDuration = 5 * time.Second
+)
+
+var (
+	errHostCircuitBreakerWindowInvalid       = errors.New("host circuit breaker window must be positive")
+	errHostCircuitBreakerMinRequestsInvalid  = errors.New("host circuit breaker min requests must be positive")
+	errHostCircuitBreakerErrorRateInvalid    = errors.New("host circuit breaker error rate threshold must be greater than 0 and at most 1")
+	errHostCircuitBreakerTimeoutRateInvalid  = errors.New("host circuit breaker timeout rate threshold must be greater than 0 and at most 1")
+	errHostCircuitBreakerOpenDurationInvalid = errors.New("host circuit breaker open duration must be positive")
+)
+
+type hostCircuitBreakerOptions struct {
+	enabled              bool
+	window               time.Duration
+	minRequests          int
+	errorRateThreshold   float64
+	timeoutRateThreshold float64
+	openDuration         time.Duration
+}
+
+// NewHostCircuitBreakerOptions creates a new set of host circuit breaker options.
+func NewHostCircuitBreakerOptions() HostCircuitBreakerOptions {
+	return &hostCircuitBreakerOptions{
+		enabled:              defaultHostCircuitBreakerEnabled,
+		window:               defaultHostCircuitBreakerWindow,
+		minRequests:          defaultHostCircuitBreakerMinRequests,
+		errorRateThreshold:   defaultHostCircuitBreakerErrorRateThreshold,
+		timeoutRateThreshold: defaultHostCircuitBreakerTimeoutRateThreshold,
+		openDuration:         defaultHostCircuitBreakerOpenDuration,
+	}
+}
+
+func (o *hostCircuitBreakerOptions) Validate() error {
+	if o.window <= 0 {
+		return errHostCircuitBreakerWindowInvalid
+	}
+	if o.minRequests <= 0 {
+		return errHostCircuitBreakerMinRequestsInvalid
+	}
+	if o.errorRateThreshold <= 0 || o.errorRateThreshold > 1 {
+		return errHostCircuitBreakerErrorRateInvalid
+	}
+	if o.timeoutRateThreshold <= 0 || o.timeoutRateThreshold > 1 {
+		return errHostCircuitBreakerTimeoutRateInvalid
+	}
+	if o.openDuration <= 0 {
+		return errHostCircuitBreakerOpenDurationInvalid
+	}
+	return nil
+}
+
+func (o *hostCircuitBreakerOptions) SetEnabled(value bool) HostCircuitBreakerOptions {
+	opts := *o
+	opts.enabled = value
+	return &opts
+}
+
+func (o *hostCircuitBreakerOptions) Enabled() bool {
+	return o.enabled
+}
+
+func (o *hostCircuitBreakerOptions) SetWindow(value time.Duration) HostCircuitBreakerOptions {
+	opts := *o
+	opts.window = value
+	return &opts
+}
+
+func (o *hostCircuitBreakerOptions) Window() time.Duration {
+	return o.window
+}
+
+func (o *hostCircuitBreakerOptions) SetMinRequests(value int) HostCircuitBreakerOptions {
+	opts := *o
+	opts.minRequests = value
+	return &opts
+}
+
+func (o *hostCircuitBreakerOptions) MinRequests() int {
+	return o.minRequests
+}
+
+func (o *hostCircuitBreakerOptions) SetErrorRateThreshold(value float64) HostCircuitBreakerOptions {
+	opts := *o
+	opts.errorRateThreshold = value
+	return &opts
+}
+
+func (o *hostCircuitBreakerOptions) ErrorRateThreshold() float64 {
+	return o.errorRateThreshold
+}
+
+func (o *hostCircuitBreakerOptions) SetTimeoutRateThreshold(value float64) HostCircuitBreakerOptions {
+	opts := *o
+	opts.timeoutRateThreshold = value
+	return &opts
+}
+
+func (o *hostCircuitBreakerOptions) TimeoutRateThreshold() float64 {
+	return o.timeoutRateThreshold
+}
+
+func (o *hostCircuitBreakerOptions) SetOpenDuration(value time.Duration) HostCircuitBreakerOptions {
+	opts := *o
+	opts.openDuration = value
+	return &opts
+}
+
+func (o *hostCircuitBreakerOptions) OpenDuration() time.Duration {
+	return o.openDuration
+}
+
+// CircuitBreakerState is the state of a host circuit breaker.
+type CircuitBreakerState int
+
+const (
+	// CircuitBreakerClosed allows all requests to the host.
+	CircuitBreakerClosed CircuitBreakerState = iota
+	// CircuitBreakerOpen fails all requests to the host immediately.
+	CircuitBreakerOpen
+	// CircuitBreakerHalfOpen allows a single probe request to the host
+	// to determine whether to close or reopen the circuit.
+	CircuitBreakerHalfOpen
+)
+
+func (s CircuitBreakerState) String() string {
+	switch s {
+	case CircuitBreakerClosed:
+		return "closed"
+	case CircuitBreakerOpen:
+		return "open"
+	case CircuitBreakerHalfOpen:
+		return "half_open"
+	}
+	return "unknown"
+}
+
+func errHostCircuitOpen(hostID string) error {
+	return fmt.Errorf("circuit breaker open for host: %s", hostID)
+}
+
+type hostCircuitBreakerMetrics struct {
+	state    tally.Gauge
+	opened   tally.Counter
+	rejected tally.Counter
+}
+
+func newHostCircuitBreakerMetrics(scope tally.Scope) hostCircuitBreakerMetrics {
+	scope = scope.SubScope("circuit-breaker")
+	return hostCircuitBreakerMetrics{
+		state:    scope.Gauge("state"),
+		opened:   scope.Counter("opened"),
+		rejected: scope.Counter("rejected"),
+	}
+}
+
+// hostCircuitBreaker tracks the error and timeout rates of requests to a host
+// over a window, opening the circuit to fail requests to the host immediately
+// once either rate crosses its threshold. After the open duration a single
+// probe request is let through, closing the circuit if it succeeds.
+type hostCircuitBreaker struct {
+	sync.Mutex
+
+	opts    HostCircuitBreakerOptions
+	nowFn   clock.NowFn
+	metrics hostCircuitBreakerMetrics
+
+	state         CircuitBreakerState
+	windowStart   time.Time
+	requests      int
+	errors        int
+	timeouts      int
+	openedAt      time.Time
+	probeInFlight bool
+}
+
+func newHostCircuitBreaker(
+	opts HostCircuitBreakerOptions,
+	nowFn clock.NowFn,
+	scope tally.Scope,
+) *hostCircuitBreaker {
+	b := &hostCircuitBreaker{
+		opts:        opts,
+		nowFn:       nowFn,
+		metrics:     newHostCircuitBreakerMetrics(scope),
+		windowStart: nowFn(),
+	}
+	b.metrics.state.Update(float64(CircuitBreakerClosed))
+	return b
+}
+
+// Allow returns whether a request may be sent to the host.
+func (b *hostCircuitBreaker) Allow() bool {
+	if !b.opts.Enabled() {
+		return true
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	switch b.state {
+	case CircuitBreakerOpen:
+		if b.nowFn().Sub(b.openedAt) < b.opts.OpenDuration() {
+			b.metrics.rejected.Inc(1)
+			return false
+		}
+		b.setStateWithLock(CircuitBreakerHalfOpen)
+		b.probeInFlight = true
+		return true
+	case CircuitBreakerHalfOpen:
+		if b.probeInFlight {
+			b.metrics.rejected.Inc(1)
+			return false
+		}
+		b.probeInFlight = true
+		return true
+	}
+	return true
+}
+
+// Record records the outcome of a request sent to the host.
+func (b *hostCircuitBreaker) Record(err error) {
+	if !b.opts.Enabled() {
+		return
+	}
+
+	// NB: bad requests are the fault of the caller rather than the host.
+	failed := err != nil && !IsBadRequestError(err)
+	timedOut := failed && isTimeoutError(err)
+
+	b.Lock()
+	defer b.Unlock()
+
+	switch b.state {
+	case CircuitBreakerOpen:
+		// Outcome of a request sent before the circuit opened.
+		return
+	case CircuitBreakerHalfOpen:
+		b.probeInFlight = false
+		if failed {
+			b.openWithLock()
+			return
+		}
+		b.setStateWithLock(CircuitBreakerClosed)
+		b.resetWindowWithLock(b.nowFn())
+		return
+	}
+
+	now := b.nowFn()
+	if now.Sub(b.windowStart) >= b.opts.Window() {
+		b.resetWindowWithLock(now)
+	}
+
+	b.requests++
+	if failed {
+		b.errors++
+	}
+	if timedOut {
+		b.timeouts++
+	}
+
+	if b.requests < b.opts.MinRequests() {
+		return
+	}
+	var (
+		errorRate   = float64(b.errors) / float64(b.requests)
+		timeoutRate = float64(b.timeouts) / float64(b.requests)
+	)
+	if errorRate >= b.opts.ErrorRateThreshold() ||
+		timeoutRate >= b.opts.TimeoutRateThreshold() {
+		b.openWithLock()
+	}
+}
+
+// State returns the current state of the circuit breaker.
+func (b *hostCircuitBreaker) State() CircuitBreakerState {
+	b.Lock()
+	state := b.state
+	b.Unlock()
+	return state
+}
+
+func (b *hostCircuitBreaker) openWithLock() {
+	b.setStateWithLock(CircuitBreakerOpen)
+	b.openedAt = b.nowFn()
+	b.metrics.opened.Inc(1)
+}
+
+func (b *hostCircuitBreaker) setStateWithLock(state CircuitBreakerState) {
+	b.state = state
+	b.metrics.state.Update(float64(state))
+}
+
+func (b *hostCircuitBreaker) resetWindowWithLock(now time.Time) {
+	b.windowStart = now
+	b.requests = 0
+	b.errors = 0
+	b.timeouts = 0
+}
+
+func isTimeoutError(err error) bool {
+	return err == context.DeadlineExceeded ||
+		tchannel.GetSystemErrorCode(err) == tchannel.ErrCodeTimeout
+}
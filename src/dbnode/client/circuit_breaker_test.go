@@ -0,0 +1,224 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"github.com/uber/tchannel-go"
+)
+
+type testCircuitBreakerClock struct {
+	sync.Mutex
+	now time.Time
+}
+
+func (c *testCircuitBreakerClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *testCircuitBreakerClock) Advance(d time.Duration) {
+	c.Lock()
+	c.now = c.now.Add(d)
+	c.Unlock()
+}
+
+func newTestHostCircuitBreaker() (*hostCircuitBreaker, *testCircuitBreakerClock, tally.TestScope) {
+	var (
+		clock = &testCircuitBreakerClock{now: time.Now()}
+		scope = tally.NewTestScope("", nil)
+		opts  = NewHostCircuitBreakerOptions().
+			SetEnabled(true).
+			SetWindow(10 * time.Second).
+			SetMinRequests(4).
+			SetErrorRateThreshold(0.5).
+			SetTimeoutRateThreshold(0.25).
+			SetOpenDuration(5 * time.Second)
+	)
+	return newHostCircuitBreaker(opts, clock.Now, scope), clock, scope
+}
+
+func TestHostCircuitBreakerOptionsValidate(t *testing.T) {
+	opts := NewHostCircuitBreakerOptions()
+	require.NoError(t, opts.Validate())
+
+	assert.Equal(t, errHostCircuitBreakerWindowInvalid, opts.SetWindow(0).Validate())
+	assert.Equal(t, errHostCircuitBreakerMinRequestsInvalid, opts.SetMinRequests(0).Validate())
+	assert.Equal(t, errHostCircuitBreakerErrorRateInvalid, opts.SetErrorRateThreshold(1.5).Validate())
+	assert.Equal(t, errHostCircuitBreakerTimeoutRateInvalid, opts.SetTimeoutRateThreshold(0).Validate())
+	assert.Equal(t, errHostCircuitBreakerOpenDurationInvalid, opts.SetOpenDuration(0).Validate())
+}
+
+func TestHostCircuitBreakerDisabledAlwaysAllows(t *testing.T) {
+	breaker := newHostCircuitBreaker(NewHostCircuitBreakerOptions(),
+		time.Now, tally.NoopScope)
+	for i := 0; i < 100; i++ {
+		breaker.Record(errors.New("an error"))
+	}
+	assert.True(t, breaker.Allow())
+	assert.Equal(t, CircuitBreakerClosed, breaker.State())
+}
+
+func TestHostCircuitBreakerOpensOnErrorRate(t *testing.T) {
+	breaker, _, scope := newTestHostCircuitBreaker()
+
+	breaker.Record(nil)
+	breaker.Record(errors.New("an error"))
+	breaker.Record(nil)
+	assert.Equal(t, CircuitBreakerClosed, breaker.State())
+
+	breaker.Record(errors.New("an error"))
+	assert.Equal(t, CircuitBreakerOpen, breaker.State())
+	assert.False(t, breaker.Allow())
+
+	counters := scope.Snapshot().Counters()
+	assert.Equal(t, int64(1), counters["circuit-breaker.opened+"].Value())
+	assert.Equal(t, int64(1), counters["circuit-breaker.rejected+"].Value())
+	gauges := scope.Snapshot().Gauges()
+	assert.Equal(t, float64(CircuitBreakerOpen), gauges["circuit-breaker.state+"].Value())
+}
+
+func TestHostCircuitBreakerOpensOnTimeoutRate(t *testing.T) {
+	breaker, _, _ := newTestHostCircuitBreaker()
+
+	for i := 0; i < 3; i++ {
+		breaker.Record(nil)
+	}
+	breaker.Record(tchannel.ErrTimeout)
+	assert.Equal(t, CircuitBreakerOpen, breaker.State())
+}
+
+func TestHostCircuitBreakerIgnoresBadRequests(t *testing.T) {
+	breaker, _, _ := newTestHostCircuitBreaker()
+
+	for i := 0; i < 10; i++ {
+		breaker.Record(tterrors.NewBadRequestError(errors.New("bad request")))
+	}
+	assert.Equal(t, CircuitBreakerClosed, breaker.State())
+}
+
+func TestHostCircuitBreakerWindowResets(t *testing.T) {
+	breaker, clock, _ := newTestHostCircuitBreaker()
+
+	breaker.Record(errors.New("an error"))
+	breaker.Record(errors.New("an error"))
+	breaker.Record(errors.New("an error"))
+
+	// Errors from the previous window are not counted.
+	clock.Advance(10 * time.Second)
+	breaker.Record(errors.New("an error"))
+	assert.Equal(t, CircuitBreakerClosed, breaker.State())
+}
+
+func TestHostCircuitBreakerHalfOpenProbe(t *testing.T) {
+	breaker, clock, _ := newTestHostCircuitBreaker()
+
+	for i := 0; i < 4; i++ {
+		breaker.Record(errors.New("an error"))
+	}
+	require.Equal(t, CircuitBreakerOpen, breaker.State())
+
+	// Failed probe reopens the circuit.
+	clock.Advance(5 * time.Second)
+	assert.True(t, breaker.Allow())
+	assert.Equal(t, CircuitBreakerHalfOpen, breaker.State())
+	assert.False(t, breaker.Allow())
+	breaker.Record(errors.New("an error"))
+	assert.Equal(t, CircuitBreakerOpen, breaker.State())
+	assert.False(t, breaker.Allow())
+
+	// Successful probe closes the circuit.
+	clock.Advance(5 * time.Second)
+	assert.True(t, breaker.Allow())
+	breaker.Record(nil)
+	assert.Equal(t, CircuitBreakerClosed, breaker.State())
+	assert.True(t, breaker.Allow())
+}
+
+func TestHostQueueFetchBatchesFailsImmediatelyWhenCircuitOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnPool := NewMockconnectionPool(ctrl)
+
+	opts := newHostQueueTestOptions().SetHostCircuitBreakerOptions(
+		NewHostCircuitBreakerOptions().SetEnabled(true))
+	queue := newTestHostQueue(opts)
+	queue.connPool = mockConnPool
+
+	mockConnPool.EXPECT().Open()
+	queue.Open()
+
+	queue.breaker.Lock()
+	queue.breaker.openWithLock()
+	queue.breaker.Unlock()
+	assert.Equal(t, CircuitBreakerOpen, queue.CircuitBreakerState())
+
+	var (
+		results []hostQueueResult
+		wg      sync.WaitGroup
+	)
+	fetchBatch := &fetchBatchOp{
+		request: rpc.FetchBatchRawRequest{
+			RangeStart: 0,
+			RangeEnd:   1,
+			NameSpace:  []byte("testNs"),
+			Ids:        [][]byte{[]byte("foo"), []byte("bar")},
+		},
+	}
+	for range fetchBatch.request.Ids {
+		fetchBatch.completionFns = append(fetchBatch.completionFns, func(r interface{}, err error) {
+			results = append(results, hostQueueResult{r, err})
+			wg.Done()
+		})
+	}
+	wg.Add(len(fetchBatch.request.Ids))
+
+	// NB: no client is requested from the connection pool.
+	assert.NoError(t, queue.Enqueue(fetchBatch))
+	wg.Wait()
+
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.Nil(t, result.result)
+		assert.Equal(t, errHostCircuitOpen(h.ID()), result.err)
+	}
+
+	var closeWg sync.WaitGroup
+	closeWg.Add(1)
+	mockConnPool.EXPECT().Close().Do(func() {
+		closeWg.Done()
+	})
+	queue.Close()
+	closeWg.Wait()
+}
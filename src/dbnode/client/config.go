@@ -31,6 +31,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/environment"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/dbnode/x/tchannel"
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/instrument"
 	"github.com/m3db/m3x/retry"
 )
@@ -69,6 +70,15 @@ type Configuration struct {
 	// FetchRetry is the fetch retry config.
 	FetchRetry retry.Configuration `yaml:"fetchRetry"`
 
+	// HedgedReads is the hedged reads config.
+	HedgedReads *HedgedReadsConfiguration `yaml:"hedgedReads"`
+
+	// CircuitBreaker is the per host circuit breaker config.
+	CircuitBreaker *CircuitBreakerConfiguration `yaml:"circuitBreaker"`
+
+	// TLS is the TLS config, if set connections to nodes are made over TLS.
+	TLS *xtls.Configuration `yaml:"tls"`
+
 	// BackgroundHealthCheckFailLimit is the amount of times a background check
 	// must fail before a connection is taken out of consideration.
 	BackgroundHealthCheckFailLimit int `yaml:"backgroundHealthCheckFailLimit" validate:"min=1,max=10"`
@@ -81,6 +91,69 @@ type Configuration struct {
 	HashingConfiguration HashingConfiguration `yaml:"hashing"`
 }
 
+// HedgedReadsConfiguration is the configuration for hedged reads, which
+// initially only send fetches to the replicas required to satisfy the read
+// consistency level and then to the remaining replicas if they are slow.
+type HedgedReadsConfiguration struct {
+	// Enabled enables hedged reads.
+	Enabled bool `yaml:"enabled"`
+
+	// Percentile is the percentile of the recent fetch latency of a host
+	// to wait for before hedging a fetch sent to it.
+	Percentile float64 `yaml:"percentile" validate:"min=0,max=1"`
+
+	// MinDelay is the minimum delay before hedging a fetch.
+	MinDelay time.Duration `yaml:"minDelay" validate:"min=0"`
+}
+
+// CircuitBreakerConfiguration is the configuration for the per host circuit
+// breakers, which fail requests to a host immediately while too many of the
+// recent requests to it have failed or timed out.
+type CircuitBreakerConfiguration struct {
+	// Enabled enables the circuit breakers.
+	Enabled bool `yaml:"enabled"`
+
+	// Window is the window over which request outcomes are counted.
+	Window time.Duration `yaml:"window" validate:"min=0"`
+
+	// MinRequests is the minimum number of requests in a window
+	// before the circuit can open.
+	MinRequests int `yaml:"minRequests" validate:"min=0"`
+
+	// ErrorRateThreshold is the rate of failed requests in a window
+	// at which the circuit opens.
+	ErrorRateThreshold float64 `yaml:"errorRateThreshold" validate:"min=0,max=1"`
+
+	// TimeoutRateThreshold is the rate of timed out requests in a window
+	// at which the circuit opens.
+	TimeoutRateThreshold float64 `yaml:"timeoutRateThreshold" validate:"min=0,max=1"`
+
+	// OpenDuration is how long the circuit stays open before
+	// a probe request is let through.
+	OpenDuration time.Duration `yaml:"openDuration" validate:"min=0"`
+}
+
+// NewOptions returns host circuit breaker options from the configuration.
+func (c CircuitBreakerConfiguration) NewOptions() HostCircuitBreakerOptions {
+	opts := NewHostCircuitBreakerOptions().SetEnabled(c.Enabled)
+	if c.Window > 0 {
+		opts = opts.SetWindow(c.Window)
+	}
+	if c.MinRequests > 0 {
+		opts = opts.SetMinRequests(c.MinRequests)
+	}
+	if c.ErrorRateThreshold > 0 {
+		opts = opts.SetErrorRateThreshold(c.ErrorRateThreshold)
+	}
+	if c.TimeoutRateThreshold > 0 {
+		opts = opts.SetTimeoutRateThreshold(c.TimeoutRateThreshold)
+	}
+	if c.OpenDuration > 0 {
+		opts = opts.SetOpenDuration(c.OpenDuration)
+	}
+	return opts
+}
+
 // HashingConfiguration is the configuration for hashing
 type HashingConfiguration struct {
 	// Murmur32 seed value
@@ -153,7 +226,7 @@ func (c Configuration) NewAdminClient(
 
 	var err error
 	if envCfg.TopologyInitializer == nil {
-		if c.EnvironmentConfig.Service != nil {
+		if c.EnvironmentConfig.Service != nil || c.EnvironmentConfig.Local != nil {
 			envCfg, err = c.EnvironmentConfig.Configure(environment.ConfigurationParameters{
 				InstrumentOpts: iopts,
 				HashingSeed:    c.HashingConfiguration.Seed,
@@ -190,6 +263,28 @@ func (c Configuration) NewAdminClient(
 		SetChannelOptions(xtchannel.NewDefaultChannelOptions()).
 		SetInstrumentOptions(iopts)
 
+	if c.TLS != nil {
+		tlsDialer, err := c.TLS.NewDialer()
+		if err != nil {
+			return nil, fmt.Errorf("unable to create tls dialer, err: %v", err)
+		}
+		v = v.SetTLSDialer(tlsDialer)
+	}
+
+	if c.HedgedReads != nil {
+		v = v.SetHedgedReadsEnabled(c.HedgedReads.Enabled)
+		if c.HedgedReads.Percentile > 0 {
+			v = v.SetHedgedReadsPercentile(c.HedgedReads.Percentile)
+		}
+		if c.HedgedReads.MinDelay > 0 {
+			v = v.SetHedgedReadsMinDelay(c.HedgedReads.MinDelay)
+		}
+	}
+
+	if c.CircuitBreaker != nil {
+		v = v.SetHostCircuitBreakerOptions(c.CircuitBreaker.NewOptions())
+	}
+
 	encodingOpts := params.EncodingOptions
 	if encodingOpts == nil {
 		encodingOpts = encoding.NewOptions()
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/m3db/m3/src/dbnode/topology"
+	xtls "github.com/m3db/m3/src/x/tls"
 	xconfig "github.com/m3db/m3x/config"
 	"github.com/m3db/m3x/retry"
 
@@ -52,6 +53,17 @@ fetchRetry:
     backoffFactor: 2
     maxRetries: 3
     jitter: true
+hedgedReads:
+    enabled: true
+    percentile: 0.99
+    minDelay: 10ms
+circuitBreaker:
+    enabled: true
+    window: 5s
+    minRequests: 10
+    errorRateThreshold: 0.4
+    timeoutRateThreshold: 0.1
+    openDuration: 2s
 backgroundHealthCheckFailLimit: 4
 backgroundHealthCheckFailThrottleFactor: 0.5
 hashing:
@@ -92,6 +104,19 @@ hashing:
 			MaxRetries:     3,
 			Jitter:         &boolTrue,
 		},
+		HedgedReads: &HedgedReadsConfiguration{
+			Enabled:    true,
+			Percentile: 0.99,
+			MinDelay:   10 * time.Millisecond,
+		},
+		CircuitBreaker: &CircuitBreakerConfiguration{
+			Enabled:              true,
+			Window:               5 * time.Second,
+			MinRequests:          10,
+			ErrorRateThreshold:   0.4,
+			TimeoutRateThreshold: 0.1,
+			OpenDuration:         2 * time.Second,
+		},
 		BackgroundHealthCheckFailLimit:          4,
 		BackgroundHealthCheckFailThrottleFactor: 0.5,
 		HashingConfiguration: HashingConfiguration{
@@ -101,3 +126,39 @@ hashing:
 
 	assert.Equal(t, expected, cfg)
 }
+
+func TestConfigurationNewAdminClientWithTLS(t *testing.T) {
+	shardSet := sessionTestShardSet()
+	topoInit := topology.NewStaticInitializer(topology.NewStaticOptions().
+		SetReplicas(sessionTestReplicas).
+		SetShardSet(shardSet).
+		SetHostShardSets(sessionTestHostAndShards(shardSet)))
+
+	noReload := time.Duration(0)
+	cfg := Configuration{
+		WriteConsistencyLevel:   topology.ConsistencyLevelMajority,
+		ReadConsistencyLevel:    topology.ReadConsistencyLevelMajority,
+		ConnectConsistencyLevel: topology.ConnectConsistencyLevelAny,
+		TLS: &xtls.Configuration{
+			InsecureSkipVerify: true,
+			ReloadInterval:     &noReload,
+		},
+	}
+
+	var opts AdminOptions
+	cli, err := cfg.NewAdminClient(ConfigurationParameters{
+		TopologyInitializer: topoInit,
+	}, func(v AdminOptions) AdminOptions {
+		opts = v
+		return v
+	})
+	require.NoError(t, err)
+	require.NotNil(t, cli)
+	assert.NotNil(t, opts.TLSDialer())
+
+	cfg.TLS = &xtls.Configuration{CertFile: "missing.crt"}
+	_, err = cfg.NewAdminClient(ConfigurationParameters{
+		TopologyInitializer: topoInit,
+	})
+	require.Error(t, err)
+}
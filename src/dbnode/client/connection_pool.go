@@ -296,13 +296,28 @@ func (p *connPool) healthCheckEvery(interval time.Duration, stutter time.Duratio
 }
 
 func newConn(channelName string, address string, opts Options) (xclose.SimpleCloser, rpc.TChanNode, error) {
+	var tunnel *tlsTunnel
+	if tlsDialer := opts.TLSDialer(); tlsDialer != nil {
+		var err error
+		tunnel, err = newTLSTunnel(address, tlsDialer, opts.HostConnectTimeout())
+		if err != nil {
+			return nil, nil, err
+		}
+		address = tunnel.Address()
+	}
 	channel, err := tchannel.NewChannel(channelName, opts.ChannelOptions())
 	if err != nil {
+		if tunnel != nil {
+			tunnel.Close()
+		}
 		return nil, nil, err
 	}
 	endpoint := &thrift.ClientOptions{HostPort: address}
 	thriftClient := thrift.NewClient(channel, nchannel.ChannelName, endpoint)
 	client := rpc.NewTChanNodeClient(thriftClient)
+	if tunnel != nil {
+		return tunneledChannel{channel: channel, tunnel: tunnel}, client, nil
+	}
 	return channel, client, nil
 }
 
@@ -31,6 +31,8 @@ import (
 	"github.com/m3db/m3/src/dbnode/x/xpool"
 	"github.com/m3db/m3/src/x/serialize"
 	"github.com/m3db/m3x/ident"
+
+	"github.com/uber-go/tally"
 )
 
 const (
@@ -52,6 +54,10 @@ type fetchState struct {
 	tagResultAccumulator fetchTaggedResultAccumulator
 	err                  error
 	done                 bool
+	hedger               *readHedger
+	hedgeQueues          []hostQueue
+	hedgeSent            bool
+	hedgesWon            tally.Counter
 
 	pool fetchStatePool
 }
@@ -77,6 +83,10 @@ func (f *fetchState) close() {
 	}
 	f.err = nil
 	f.done = false
+	f.hedger = nil
+	f.hedgeQueues = nil
+	f.hedgeSent = false
+	f.hedgesWon = nil
 	f.tagResultAccumulator.Clear()
 
 	if f.pool == nil {
@@ -123,13 +133,40 @@ func (f *fetchState) completionFn(
 
 	done, err := f.tagResultAccumulator.Add(opts, resultErr)
 	if done {
+		if err == nil && resultErr == nil && f.isHedgeHostWithLock(opts.host) {
+			f.hedgesWon.Inc(1)
+		}
 		f.markDoneWithLock(err)
+		return
+	}
+
+	if resultErr != nil && f.hedger != nil {
+		// Hedge immediately rather than waiting for the hedge delay as a host
+		// has failed, this is a no-op if the hedge has already been sent.
+		go f.hedger.hedge()
 	}
 }
 
+func (f *fetchState) isHedgeHostWithLock(host topology.Host) bool {
+	if !f.hedgeSent {
+		return false
+	}
+	for _, hq := range f.hedgeQueues {
+		if hq.Host().ID() == host.ID() {
+			return true
+		}
+	}
+	return false
+}
+
 func (f *fetchState) markDoneWithLock(err error) {
 	f.done = true
 	f.err = err
+	if f.hedger != nil {
+		// Cancel the hedge if it has not yet been sent, any responses from
+		// hosts already hedged to are discarded.
+		f.hedger.cancel()
+	}
 	f.Signal()
 }
 
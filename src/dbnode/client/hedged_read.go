@@ -0,0 +1,225 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/topology"
+)
+
+const (
+	// hostLatencyTrackerSize is the number of recent latencies tracked per host.
+	hostLatencyTrackerSize = 256
+	// hostLatencyTrackerMinSamples is the number of latencies required
+	// before percentiles are reported.
+	hostLatencyTrackerMinSamples = 16
+	// hostLatencyTrackerResortEvery is the number of latencies recorded
+	// between recomputing the sorted latencies used for percentiles.
+	hostLatencyTrackerResortEvery = 16
+)
+
+var errHedgedReadCancelled = errors.New("hedged read cancelled as consistency level already satisfied")
+
+// hostLatencyTracker tracks the recent latencies of requests to a host.
+type hostLatencyTracker struct {
+	sync.RWMutex
+	samples   [hostLatencyTrackerSize]time.Duration
+	next      int
+	count     int
+	sinceSort int
+	sorted    []time.Duration
+}
+
+func newHostLatencyTracker() *hostLatencyTracker {
+	return &hostLatencyTracker{
+		sorted: make([]time.Duration, 0, hostLatencyTrackerSize),
+	}
+}
+
+func (t *hostLatencyTracker) Record(latency time.Duration) {
+	t.Lock()
+	t.samples[t.next] = latency
+	t.next = (t.next + 1) % hostLatencyTrackerSize
+	if t.count < hostLatencyTrackerSize {
+		t.count++
+	}
+	t.sinceSort++
+	t.Unlock()
+}
+
+func (t *hostLatencyTracker) Percentile(percentile float64) (time.Duration, bool) {
+	t.RLock()
+	if t.count < hostLatencyTrackerMinSamples {
+		t.RUnlock()
+		return 0, false
+	}
+	if t.sinceSort < hostLatencyTrackerResortEvery && len(t.sorted) > 0 {
+		value := t.sorted[percentileIndex(percentile, len(t.sorted))]
+		t.RUnlock()
+		return value, true
+	}
+	t.RUnlock()
+
+	t.Lock()
+	if t.sinceSort >= hostLatencyTrackerResortEvery || len(t.sorted) == 0 {
+		t.sorted = append(t.sorted[:0], t.samples[:t.count]...)
+		sort.Slice(t.sorted, func(i, j int) bool {
+			return t.sorted[i] < t.sorted[j]
+		})
+		t.sinceSort = 0
+	}
+	value := t.sorted[percentileIndex(percentile, len(t.sorted))]
+	t.Unlock()
+	return value, true
+}
+
+func percentileIndex(percentile float64, n int) int {
+	idx := int(math.Ceil(percentile*float64(n))) - 1
+	if idx < 0 {
+		return 0
+	}
+	if idx >= n {
+		return n - 1
+	}
+	return idx
+}
+
+// hedgedReadInitialReplicas returns the number of replicas a hedged read is
+// initially sent to for the given read consistency level, returns false if
+// the read consistency level requires all replicas and cannot be hedged.
+func hedgedReadInitialReplicas(
+	level topology.ReadConsistencyLevel,
+	majority int,
+) (int, bool) {
+	switch level {
+	case topology.ReadConsistencyLevelOne:
+		return 1, true
+	case topology.ReadConsistencyLevelUnstrictMajority:
+		return majority, true
+	}
+	return 0, false
+}
+
+// sortHostIdxsByLatency sorts the host indexes in place by ascending latency,
+// preserving the order of hosts with equal latency.
+func sortHostIdxsByLatency(hostIdxs []int, latencies []time.Duration) {
+	// NB: insertion sort as this is called per ID fetched with only as
+	// many hosts as replicas, avoids allocating as sort.SliceStable would.
+	for i := 1; i < len(hostIdxs); i++ {
+		for j := i; j > 0 && latencies[hostIdxs[j]] < latencies[hostIdxs[j-1]]; j-- {
+			hostIdxs[j], hostIdxs[j-1] = hostIdxs[j-1], hostIdxs[j]
+		}
+	}
+}
+
+// selectHedgedFetchTaggedQueues splits the host queues into those a fetch
+// tagged is initially sent to, covering each shard with the given number of
+// available replicas preferring hosts with the lowest latency, and those the
+// fetch tagged is hedged to.
+func selectHedgedFetchTaggedQueues(
+	topoMap topology.Map,
+	queues []hostQueue,
+	latencies []time.Duration,
+	replicas int,
+) ([]hostQueue, []hostQueue) {
+	hostIdxs := make([]int, 0, len(queues))
+	for idx := range queues {
+		hostIdxs = append(hostIdxs, idx)
+	}
+	sort.SliceStable(hostIdxs, func(i, j int) bool {
+		return latencies[hostIdxs[i]] < latencies[hostIdxs[j]]
+	})
+
+	var (
+		initial  []hostQueue
+		hedged   []hostQueue
+		coverage = make(map[uint32]int)
+	)
+	for _, idx := range hostIdxs {
+		q := queues[idx]
+		hostShardSet, ok := topoMap.LookupHostShardSet(q.Host().ID())
+		if !ok {
+			initial = append(initial, q)
+			continue
+		}
+
+		required := false
+		for _, s := range hostShardSet.ShardSet().All() {
+			if s.State() == shard.Available && coverage[s.ID()] < replicas {
+				required = true
+				break
+			}
+		}
+		if !required {
+			hedged = append(hedged, q)
+			continue
+		}
+
+		initial = append(initial, q)
+		for _, s := range hostShardSet.ShardSet().All() {
+			if s.State() == shard.Available {
+				coverage[s.ID()]++
+			}
+		}
+	}
+	return initial, hedged
+}
+
+// readHedger sends the hedged part of a read either after a delay or once
+// triggered early, e.g. due to an error from a replica initially read from,
+// unless cancelled first. Exactly one of hedge and cancel functions is run,
+// neither hedge nor cancel block on the other running.
+type readHedger struct {
+	timer    *time.Timer
+	ran      int32
+	hedgeFn  func()
+	cancelFn func()
+}
+
+func newReadHedger(
+	delay time.Duration,
+	hedgeFn func(),
+	cancelFn func(),
+) *readHedger {
+	h := &readHedger{hedgeFn: hedgeFn, cancelFn: cancelFn}
+	h.timer = time.AfterFunc(delay, h.hedge)
+	return h
+}
+
+func (h *readHedger) hedge() {
+	if atomic.CompareAndSwapInt32(&h.ran, 0, 1) {
+		h.hedgeFn()
+	}
+}
+
+func (h *readHedger) cancel() {
+	h.timer.Stop()
+	if atomic.CompareAndSwapInt32(&h.ran, 0, 1) {
+		h.cancelFn()
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/dbnode/sharding"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostLatencyTrackerPercentile(t *testing.T) {
+	tracker := newHostLatencyTracker()
+
+	for i := 1; i < hostLatencyTrackerMinSamples; i++ {
+		tracker.Record(time.Duration(i) * time.Millisecond)
+	}
+	_, ok := tracker.Percentile(0.5)
+	assert.False(t, ok)
+
+	for i := hostLatencyTrackerMinSamples; i <= 100; i++ {
+		tracker.Record(time.Duration(i) * time.Millisecond)
+	}
+	latency, ok := tracker.Percentile(0.95)
+	require.True(t, ok)
+	assert.Equal(t, 95*time.Millisecond, latency)
+	latency, ok = tracker.Percentile(1)
+	require.True(t, ok)
+	assert.Equal(t, 100*time.Millisecond, latency)
+
+	// Only the most recent latencies are tracked.
+	for i := 0; i < hostLatencyTrackerSize; i++ {
+		tracker.Record(time.Second)
+	}
+	latency, ok = tracker.Percentile(0.5)
+	require.True(t, ok)
+	assert.Equal(t, time.Second, latency)
+}
+
+func TestHedgedReadInitialReplicas(t *testing.T) {
+	replicas, ok := hedgedReadInitialReplicas(topology.ReadConsistencyLevelOne, 2)
+	require.True(t, ok)
+	assert.Equal(t, 1, replicas)
+
+	replicas, ok = hedgedReadInitialReplicas(topology.ReadConsistencyLevelUnstrictMajority, 2)
+	require.True(t, ok)
+	assert.Equal(t, 2, replicas)
+
+	_, ok = hedgedReadInitialReplicas(topology.ReadConsistencyLevelMajority, 2)
+	assert.False(t, ok)
+	_, ok = hedgedReadInitialReplicas(topology.ReadConsistencyLevelAll, 2)
+	assert.False(t, ok)
+}
+
+func TestSortHostIdxsByLatency(t *testing.T) {
+	latencies := []time.Duration{3, 1, 2, 1}
+	hostIdxs := []int{0, 1, 2, 3}
+	sortHostIdxsByLatency(hostIdxs, latencies)
+	assert.Equal(t, []int{1, 3, 2, 0}, hostIdxs)
+}
+
+func TestSelectHedgedFetchTaggedQueues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Six hosts, each of two shards replicated three times.
+	var (
+		hostShardSets []topology.HostShardSet
+		queues        []hostQueue
+	)
+	for i := 0; i < 6; i++ {
+		host := topology.NewHost(testHostName(i), fmt.Sprintf("%s:9000", testHostName(i)))
+		shardSet, err := sharding.NewShardSet(
+			sharding.NewShards([]uint32{uint32(i % 2)}, shard.Available),
+			func(id ident.ID) uint32 { return 0 })
+		require.NoError(t, err)
+		hostShardSets = append(hostShardSets, topology.NewHostShardSet(host, shardSet))
+
+		q := NewMockhostQueue(ctrl)
+		q.EXPECT().Host().Return(host).AnyTimes()
+		queues = append(queues, q)
+	}
+	shardSet, err := sharding.NewShardSet(
+		sharding.NewShards([]uint32{0, 1}, shard.Available),
+		func(id ident.ID) uint32 { return 0 })
+	require.NoError(t, err)
+	topoMap := topology.NewStaticMap(topology.NewStaticOptions().
+		SetReplicas(3).
+		SetShardSet(shardSet).
+		SetHostShardSets(hostShardSets))
+
+	latencies := []time.Duration{6, 5, 4, 3, 2, 1}
+	initial, hedged := selectHedgedFetchTaggedQueues(topoMap, queues, latencies, 1)
+	require.Len(t, initial, 2)
+	assert.Equal(t, testHostName(5), initial[0].Host().ID())
+	assert.Equal(t, testHostName(4), initial[1].Host().ID())
+	assert.Len(t, hedged, 4)
+
+	initial, hedged = selectHedgedFetchTaggedQueues(topoMap, queues, latencies, 2)
+	assert.Len(t, initial, 4)
+	assert.Len(t, hedged, 2)
+}
+
+func TestReadHedgerHedgesAfterDelay(t *testing.T) {
+	var hedged, cancelled int32
+	done := make(chan struct{})
+	h := newReadHedger(time.Millisecond, func() {
+		atomic.AddInt32(&hedged, 1)
+		close(done)
+	}, func() {
+		atomic.AddInt32(&cancelled, 1)
+	})
+
+	<-done
+	h.cancel()
+	h.hedge()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hedged))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&cancelled))
+}
+
+func TestReadHedgerCancel(t *testing.T) {
+	var hedged, cancelled int32
+	h := newReadHedger(time.Hour, func() {
+		atomic.AddInt32(&hedged, 1)
+	}, func() {
+		atomic.AddInt32(&cancelled, 1)
+	})
+
+	h.cancel()
+	h.hedge()
+	assert.Equal(t, int32(0), atomic.LoadInt32(&hedged))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&cancelled))
+}
@@ -58,6 +58,8 @@ type queue struct {
 	opsArrayPool                               *opArrayPool
 	drainIn                                    chan []op
 	status                                     status
+	fetchLatency                               *hostLatencyTracker
+	breaker                                    *hostCircuitBreaker
 }
 
 func newHostQueue(
@@ -102,6 +104,9 @@ func newHostQueue(
 	opArrayPool := newOpArrayPool(opArrayPoolOpts, opArrayPoolCapacity)
 	opArrayPool.Init()
 
+	breaker := newHostCircuitBreaker(opts.HostCircuitBreakerOptions(),
+		opts.ClockOptions().NowFn(), scope)
+
 	return &queue{
 		opts:                                       opts,
 		nowFn:                                      opts.ClockOptions().NowFn(),
@@ -116,6 +121,8 @@ func newHostQueue(
 		ops:          opArrayPool.Get(),
 		opsArrayPool: opArrayPool,
 		drainIn:      make(chan []op, opsArraysLen),
+		fetchLatency: newHostLatencyTracker(),
+		breaker:      breaker,
 	}, nil
 }
 
@@ -322,9 +329,17 @@ func (q *queue) asyncTaggedWrite(
 		// NB(bl): host is passed to writeState to determine the state of the
 		// shard on the node we're writing to
 
+		if !q.breaker.Allow() {
+			// Circuit open, fail immediately
+			callAllCompletionFns(ops, q.host, errHostCircuitOpen(q.host.ID()))
+			cleanup()
+			return
+		}
+
 		client, err := q.connPool.NextClient()
 		if err != nil {
 			// No client available
+			q.breaker.Record(err)
 			callAllCompletionFns(ops, q.host, err)
 			cleanup()
 			return
@@ -332,6 +347,12 @@ func (q *queue) asyncTaggedWrite(
 
 		ctx, _ := thrift.NewContext(q.opts.WriteRequestTimeout())
 		err = client.WriteTaggedBatchRaw(ctx, req)
+		if batchErrs, ok := err.(*rpc.WriteBatchRawErrors); ok && batchErrs != nil {
+			// NB: per element errors are not failures of the host.
+			q.breaker.Record(nil)
+		} else {
+			q.breaker.Record(err)
+		}
 		if err == nil {
 			// All succeeded
 			callAllCompletionFns(ops, q.host, nil)
@@ -386,9 +407,17 @@ func (q *queue) asyncWrite(
 		// NB(bl): host is passed to writeState to determine the state of the
 		// shard on the node we're writing to
 
+		if !q.breaker.Allow() {
+			// Circuit open, fail immediately
+			callAllCompletionFns(ops, q.host, errHostCircuitOpen(q.host.ID()))
+			cleanup()
+			return
+		}
+
 		client, err := q.connPool.NextClient()
 		if err != nil {
 			// No client available
+			q.breaker.Record(err)
 			callAllCompletionFns(ops, q.host, err)
 			cleanup()
 			return
@@ -396,6 +425,12 @@ func (q *queue) asyncWrite(
 
 		ctx, _ := thrift.NewContext(q.opts.WriteRequestTimeout())
 		err = client.WriteBatchRaw(ctx, req)
+		if batchErrs, ok := err.(*rpc.WriteBatchRawErrors); ok && batchErrs != nil {
+			// NB: per element errors are not failures of the host.
+			q.breaker.Record(nil)
+		} else {
+			q.breaker.Record(err)
+		}
 		if err == nil {
 			// All succeeded
 			callAllCompletionFns(ops, q.host, nil)
@@ -438,21 +473,32 @@ func (q *queue) asyncFetch(op *fetchBatchOp) {
 			q.Done()
 		}
 
+		if !q.breaker.Allow() {
+			// Circuit open, fail immediately
+			op.completeAll(nil, errHostCircuitOpen(q.host.ID()))
+			cleanup()
+			return
+		}
+
 		client, err := q.connPool.NextClient()
 		if err != nil {
 			// No client available
+			q.breaker.Record(err)
 			op.completeAll(nil, err)
 			cleanup()
 			return
 		}
 
 		ctx, _ := thrift.NewContext(q.opts.FetchRequestTimeout())
+		start := q.nowFn()
 		result, err := client.FetchBatchRaw(ctx, &op.request)
+		q.breaker.Record(err)
 		if err != nil {
 			op.completeAll(nil, err)
 			cleanup()
 			return
 		}
+		q.fetchLatency.Record(q.nowFn().Sub(start))
 
 		resultLen := len(result.Elements)
 		opLen := op.Size()
@@ -481,21 +527,33 @@ func (q *queue) asyncFetchTagged(op *fetchTaggedOp) {
 			q.Done()
 		}
 
+		if !q.breaker.Allow() {
+			// Circuit open, fail immediately
+			op.CompletionFn()(fetchTaggedResultAccumulatorOpts{host: q.host},
+				errHostCircuitOpen(q.host.ID()))
+			cleanup()
+			return
+		}
+
 		client, err := q.connPool.NextClient()
 		if err != nil {
 			// No client available
+			q.breaker.Record(err)
 			op.CompletionFn()(fetchTaggedResultAccumulatorOpts{host: q.host}, err)
 			cleanup()
 			return
 		}
 
 		ctx, _ := thrift.NewContext(q.opts.FetchRequestTimeout())
+		start := q.nowFn()
 		result, err := client.FetchTagged(ctx, &op.request)
+		q.breaker.Record(err)
 		if err != nil {
 			op.CompletionFn()(fetchTaggedResultAccumulatorOpts{host: q.host}, err)
 			cleanup()
 			return
 		}
+		q.fetchLatency.Record(q.nowFn().Sub(start))
 
 		op.CompletionFn()(fetchTaggedResultAccumulatorOpts{
 			host:     q.host,
@@ -600,6 +658,14 @@ func (q *queue) BorrowConnection(fn withConnectionFn) error {
 	return nil
 }
 
+func (q *queue) FetchLatency(percentile float64) (time.Duration, bool) {
+	return q.fetchLatency.Percentile(percentile)
+}
+
+func (q *queue) CircuitBreakerState() CircuitBreakerState {
+	return q.breaker.State()
+}
+
 func (q *queue) Close() {
 	q.Lock()
 	if q.status != statusOpen {
@@ -33,6 +33,7 @@ import (
 	m3dbruntime "github.com/m3db/m3/src/dbnode/runtime"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/x/serialize"
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/context"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/instrument"
@@ -76,6 +77,15 @@ const (
 	// defaultFetchRequestTimeout is the default fetch request timeout
 	defaultFetchRequestTimeout = 15 * time.Second
 
+	// defaultHedgedReadsEnabled is the default hedged reads enabled value
+	defaultHedgedReadsEnabled = false
+
+	// defaultHedgedReadsPercentile is the default hedged reads percentile
+	defaultHedgedReadsPercentile = 0.95
+
+	// defaultHedgedReadsMinDelay is the default hedged reads min delay
+	defaultHedgedReadsMinDelay = 5 * time.Millisecond
+
 	// defaultTruncateRequestTimeout is the default truncate request timeout
 	defaultTruncateRequestTimeout = 60 * time.Second
 
@@ -178,8 +188,9 @@ var (
 			SetJitter(true),
 	)
 
-	errNoTopologyInitializerSet    = errors.New("no topology initializer set")
-	errNoReaderIteratorAllocateSet = errors.New("no reader iterator allocator set, encoding not set")
+	errNoTopologyInitializerSet     = errors.New("no topology initializer set")
+	errNoReaderIteratorAllocateSet  = errors.New("no reader iterator allocator set, encoding not set")
+	errHedgedReadsPercentileInvalid = errors.New("hedged reads percentile must be greater than 0 and at most 1")
 )
 
 type options struct {
@@ -191,6 +202,7 @@ type options struct {
 	writeConsistencyLevel                   topology.ConsistencyLevel
 	bootstrapConsistencyLevel               topology.ReadConsistencyLevel
 	channelOptions                          *tchannel.ChannelOptions
+	tlsDialer                               *xtls.Dialer
 	maxConnectionCount                      int
 	minConnectionCount                      int
 	hostConnectTimeout                      time.Duration
@@ -198,6 +210,10 @@ type options struct {
 	clusterConnectConsistencyLevel          topology.ConnectConsistencyLevel
 	writeRequestTimeout                     time.Duration
 	fetchRequestTimeout                     time.Duration
+	hedgedReadsEnabled                      bool
+	hedgedReadsPercentile                   float64
+	hedgedReadsMinDelay                     time.Duration
+	hostCircuitBreakerOpts                  HostCircuitBreakerOptions
 	truncateRequestTimeout                  time.Duration
 	backgroundConnectInterval               time.Duration
 	backgroundConnectStutter                time.Duration
@@ -278,6 +294,10 @@ func newOptions() *options {
 		clusterConnectConsistencyLevel:          defaultClusterConnectConsistencyLevel,
 		writeRequestTimeout:                     defaultWriteRequestTimeout,
 		fetchRequestTimeout:                     defaultFetchRequestTimeout,
+		hedgedReadsEnabled:                      defaultHedgedReadsEnabled,
+		hedgedReadsPercentile:                   defaultHedgedReadsPercentile,
+		hedgedReadsMinDelay:                     defaultHedgedReadsMinDelay,
+		hostCircuitBreakerOpts:                  NewHostCircuitBreakerOptions(),
 		truncateRequestTimeout:                  defaultTruncateRequestTimeout,
 		backgroundConnectInterval:               defaultBackgroundConnectInterval,
 		backgroundConnectStutter:                defaultBackgroundConnectStutter,
@@ -336,6 +356,12 @@ func (o *options) Validate() error {
 	); err != nil {
 		return err
 	}
+	if o.hedgedReadsPercentile <= 0 || o.hedgedReadsPercentile > 1 {
+		return errHedgedReadsPercentileInvalid
+	}
+	if err := o.hostCircuitBreakerOpts.Validate(); err != nil {
+		return err
+	}
 	return topology.ValidateConnectConsistencyLevel(
 		o.clusterConnectConsistencyLevel,
 	)
@@ -429,6 +455,16 @@ func (o *options) ChannelOptions() *tchannel.ChannelOptions {
 	return o.channelOptions
 }
 
+func (o *options) SetTLSDialer(value *xtls.Dialer) Options {
+	opts := *o
+	opts.tlsDialer = value
+	return &opts
+}
+
+func (o *options) TLSDialer() *xtls.Dialer {
+	return o.tlsDialer
+}
+
 func (o *options) SetMaxConnectionCount(value int) Options {
 	opts := *o
 	opts.maxConnectionCount = value
@@ -499,6 +535,46 @@ func (o *options) FetchRequestTimeout() time.Duration {
 	return o.fetchRequestTimeout
 }
 
+func (o *options) SetHedgedReadsEnabled(value bool) Options {
+	opts := *o
+	opts.hedgedReadsEnabled = value
+	return &opts
+}
+
+func (o *options) HedgedReadsEnabled() bool {
+	return o.hedgedReadsEnabled
+}
+
+func (o *options) SetHedgedReadsPercentile(value float64) Options {
+	opts := *o
+	opts.hedgedReadsPercentile = value
+	return &opts
+}
+
+func (o *options) HedgedReadsPercentile() float64 {
+	return o.hedgedReadsPercentile
+}
+
+func (o *options) SetHedgedReadsMinDelay(value time.Duration) Options {
+	opts := *o
+	opts.hedgedReadsMinDelay = value
+	return &opts
+}
+
+func (o *options) HedgedReadsMinDelay() time.Duration {
+	return o.hedgedReadsMinDelay
+}
+
+func (o *options) SetHostCircuitBreakerOptions(value HostCircuitBreakerOptions) Options {
+	opts := *o
+	opts.hostCircuitBreakerOpts = value
+	return &opts
+}
+
+func (o *options) HostCircuitBreakerOptions() HostCircuitBreakerOptions {
+	return o.hostCircuitBreakerOpts
+}
+
 func (o *options) SetTruncateRequestTimeout(value time.Duration) Options {
 	opts := *o
 	opts.truncateRequestTimeout = value
@@ -167,6 +167,8 @@ type sessionMetrics struct {
 	fetchErrors                          tally.Counter
 	fetchNodesRespondingErrors           []tally.Counter
 	fetchNodesRespondingBadRequestErrors []tally.Counter
+	fetchHedgesFired                     tally.Counter
+	fetchHedgesWon                       tally.Counter
 	topologyUpdatedSuccess               tally.Counter
 	topologyUpdatedError                 tally.Counter
 	streamFromPeersMetrics               map[shardMetricsKey]streamFromPeersMetrics
@@ -178,6 +180,8 @@ func newSessionMetrics(scope tally.Scope) sessionMetrics {
 		writeErrors:            scope.Counter("write.errors"),
 		fetchSuccess:           scope.Counter("fetch.success"),
 		fetchErrors:            scope.Counter("fetch.errors"),
+		fetchHedgesFired:       scope.Counter("fetch.hedges-fired"),
+		fetchHedgesWon:         scope.Counter("fetch.hedges-won"),
 		topologyUpdatedSuccess: scope.Counter("topology.updated-success"),
 		topologyUpdatedError:   scope.Counter("topology.updated-error"),
 		streamFromPeersMetrics: make(map[shardMetricsKey]streamFromPeersMetrics),
@@ -937,8 +941,13 @@ func (s *session) writeAttempt(
 	// returned from writeAttemptWithRLock.
 	state.Wait()
 
-	err = s.writeConsistencyResult(state.consistencyLevel, majority, enqueued,
-		enqueued-state.pending, int32(len(state.errors)), state.errors)
+	if state.consistencyLevel == topology.ConsistencyLevelZoneMajority {
+		err = s.writeZoneConsistencyResult(state.majorityZones, enqueued,
+			enqueued-state.pending, int32(len(state.successZones)), state.errors)
+	} else {
+		err = s.writeConsistencyResult(state.consistencyLevel, majority, enqueued,
+			enqueued-state.pending, int32(len(state.errors)), state.errors)
+	}
 
 	s.incWriteMetrics(err, int32(len(state.errors)))
 
@@ -963,10 +972,21 @@ func (s *session) writeAttemptWithRLock(
 	annotation []byte,
 ) (*writeState, int32, int32, error) {
 	var (
-		majority = int32(s.state.majority)
-		enqueued int32
+		majority      = int32(s.state.majority)
+		majorityZones int
+		enqueued      int32
 	)
 
+	if s.state.writeLevel == topology.ConsistencyLevelZoneMajority {
+		// The zones are counted per shard since each shard is only
+		// replicated across the zones its replicas are placed in.
+		var err error
+		shardID := s.state.topoMap.ShardSet().Lookup(id)
+		if majorityZones, err = s.state.topoMap.MajorityZones(shardID); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
 	// NB(prateek): We retain an individual copy of the namespace, ID per
 	// writeState, as each writeState tracks the lifecycle of it's resources in
 	// use in the various queues. Tracking per writeAttempt isn't sufficient as
@@ -1018,6 +1038,7 @@ func (s *session) writeAttemptWithRLock(
 	state := s.pools.writeState.Get()
 	state.consistencyLevel = s.state.writeLevel
 	state.topoMap = s.state.topoMap
+	state.majorityZones = int32(majorityZones)
 	state.incRef()
 
 	// todo@bl: Can we combine the writeOpPool and the writeStatePool?
@@ -1215,12 +1236,36 @@ func (s *session) fetchTaggedAttemptWithRLock(
 	op.update(req, fetchState.completionFn)
 
 	fetchState.Reset(opts.StartInclusive, opts.EndExclusive, op, topoMap, s.state.majority, s.state.readLevel)
+
+	queues := s.state.queues
+	if s.opts.HedgedReadsEnabled() {
+		initialReplicas, ok := hedgedReadInitialReplicas(s.state.readLevel, s.state.majority)
+		if ok && initialReplicas < s.state.replicas {
+			var hedgeQueues []hostQueue
+			queues, hedgeQueues = selectHedgedFetchTaggedQueues(topoMap, s.state.queues,
+				s.hostFetchLatencies(s.state.queues), initialReplicas)
+			if len(hedgeQueues) > 0 {
+				fetchState.incRef() // indicate the hedger has a reference to the fetchState
+				fetchState.hedgeQueues = hedgeQueues
+				fetchState.hedgesWon = s.metrics.fetchHedgesWon
+				fetchState.hedger = newReadHedger(s.hedgedReadDelay(queues), func() {
+					s.enqueueHedgedFetchTagged(fetchState, hedgeQueues)
+				}, func() {
+					fetchState.decRef() // release the ref for the hedger
+				})
+			}
+		}
+	}
+
 	fetchState.Lock()
-	for _, hq := range s.state.queues {
+	for _, hq := range queues {
 		// inc to indicate the hostQueue has a reference to `op` which has a ref to the fetchState
 		fetchState.incRef()
 		if err := hq.Enqueue(op); err != nil {
 			fetchState.Unlock()
+			if fetchState.hedger != nil {
+				fetchState.hedger.cancel()
+			}
 			op.decRef()         // release the ref for the current go-routine
 			fetchState.decRef() // release the ref for the hostQueue
 			fetchState.decRef() // release the ref for the current go-routine
@@ -1240,6 +1285,40 @@ func (s *session) fetchTaggedAttemptWithRLock(
 	return fetchState, nil
 }
 
+func (s *session) enqueueHedgedFetchTagged(
+	fetchState *fetchState,
+	queues []hostQueue,
+) {
+	var failed []hostQueue
+	s.state.RLock()
+	fetchState.Lock()
+	if !fetchState.done {
+		s.metrics.fetchHedgesFired.Inc(1)
+		fetchState.hedgeSent = true
+		for _, hq := range queues {
+			// inc to indicate the hostQueue has a reference to `op` which has a ref to the fetchState
+			fetchState.incRef()
+			if err := hq.Enqueue(fetchState.op); err != nil {
+				// The queue may have been closed by a topology update since
+				// the read began, the op was still taken ownership of.
+				fetchState.op.decRef()
+				failed = append(failed, hq)
+			}
+		}
+	}
+	fetchState.Unlock()
+	s.state.RUnlock()
+
+	for _, hq := range failed {
+		// Respond on behalf of the host so the fetch does not wait on it,
+		// which releases the ref for the hostQueue.
+		fetchState.completionFn(fetchTaggedResultAccumulatorOpts{host: hq.Host()},
+			errQueueNotOpen(hq.Host().ID()))
+	}
+
+	fetchState.decRef() // release the ref for the hedger
+}
+
 func (s *session) fetchIDsAttempt(
 	inputNamespace ident.ID,
 	inputIDs ident.Iterator,
@@ -1257,6 +1336,13 @@ func (s *session) fetchIDsAttempt(
 		consistencyLevel       topology.ReadConsistencyLevel
 		fetchBatchOpsByHostIdx [][]*fetchBatchOp
 		success                = false
+
+		hedging                     bool
+		hedgeInitialReplicas        int
+		hedgeFetchBatchOpsByHostIdx [][]*fetchBatchOp
+		hostLatencies               []time.Duration
+		replicaHostIdxs             []int
+		hedger                      *readHedger
 	)
 
 	// NB(prateek): need to make a copy of inputNamespace and inputIDs to control
@@ -1305,6 +1391,48 @@ func (s *session) fetchIDsAttempt(
 	consistencyLevel = s.state.readLevel
 	majority = int32(s.state.majority)
 
+	if s.opts.HedgedReadsEnabled() {
+		hedgeInitialReplicas, hedging = hedgedReadInitialReplicas(consistencyLevel, int(majority))
+		hedging = hedging && hedgeInitialReplicas < s.state.replicas
+	}
+	if hedging {
+		// NB: allocated rather than pooled as hedged ops may be enqueued after
+		// a topology update has changed the length of the pooled arrays.
+		hedgeFetchBatchOpsByHostIdx = make([][]*fetchBatchOp, len(s.state.queues))
+		hostLatencies = s.hostFetchLatencies(s.state.queues)
+		replicaHostIdxs = make([]int, 0, s.state.replicas)
+	}
+
+	appendFetchBatchOp := func(
+		opsByHostIdx [][]*fetchBatchOp,
+		hostIdx int,
+		tsID ident.ID,
+		completionFn completionFn,
+	) {
+		ops := opsByHostIdx[hostIdx]
+
+		var f *fetchBatchOp
+		if len(ops) > 0 {
+			// Find the last and potentially current fetch op for this host
+			f = ops[len(ops)-1]
+		}
+		if f == nil || f.Size() >= s.fetchBatchSize {
+			// If no current fetch op or existing one is at batch capacity add one
+			// NB(r): Note that we defer to the host queue to take ownership
+			// of these ops and for returning the ops to the pool when done as
+			// they know when their use is complete.
+			f = s.pools.fetchBatchOp.Get()
+			f.IncRef()
+			opsByHostIdx[hostIdx] = append(opsByHostIdx[hostIdx], f)
+			f.request.RangeStart = rangeStart
+			f.request.RangeEnd = rangeEnd
+			f.request.RangeTimeType = rpc.TimeType_UNIX_NANOSECONDS
+		}
+
+		// Append IDWithNamespace to this request
+		f.append(namespace.Bytes(), tsID.Bytes(), completionFn)
+	}
+
 	// NB(prateek): namespaceAccessors tracks the number of pending accessors for nsID.
 	// It is set to incremented by `replica` for each requested ID during fetch enqueuing,
 	// and once by initial request, and is decremented for each replica retrieved, inside
@@ -1389,9 +1517,14 @@ func (s *session) fetchIDsAttempt(
 			}
 			wg.Done()
 		}
-		completionFn := func(result interface{}, err error) {
+		complete := func(result interface{}, err error, hedged bool) {
 			var snapshotSuccess int32
 			if err != nil {
+				if !hedged && hedger != nil {
+					// Hedge immediately rather than waiting for the hedge delay
+					// as a replica initially read from has failed.
+					go hedger.hedge()
+				}
 				atomic.AddInt32(&errs, 1)
 				// NB(r): reuse the error lock here as we do not want to create
 				// a whole lot of locks for every single ID fetched due to size
@@ -1420,6 +1553,9 @@ func (s *session) fetchIDsAttempt(
 			remaining := atomic.AddInt32(&pending, -1)
 			shouldTerminate := topology.ReadConsistencyTermination(s.state.readLevel, majority, remaining, snapshotSuccess)
 			if shouldTerminate && atomic.CompareAndSwapInt32(&wgIsDone, 0, 1) {
+				if hedged && err == nil {
+					s.metrics.fetchHedgesWon.Inc(1)
+				}
 				allCompletionFn()
 			}
 
@@ -1433,6 +1569,9 @@ func (s *session) fetchIDsAttempt(
 				namespace.Finalize()
 			}
 		}
+		completionFn := func(result interface{}, err error) {
+			complete(result, err, false)
+		}
 
 		if err := s.state.topoMap.RouteForEach(tsID, func(hostIdx int, host topology.Host) {
 			// Inc safely as this for each is sequential
@@ -1443,33 +1582,32 @@ func (s *session) fetchIDsAttempt(
 			namespaceAccessors++
 			idAccessors++
 
-			ops := fetchBatchOpsByHostIdx[hostIdx]
-
-			var f *fetchBatchOp
-			if len(ops) > 0 {
-				// Find the last and potentially current fetch op for this host
-				f = ops[len(ops)-1]
-			}
-			if f == nil || f.Size() >= s.fetchBatchSize {
-				// If no current fetch op or existing one is at batch capacity add one
-				// NB(r): Note that we defer to the host queue to take ownership
-				// of these ops and for returning the ops to the pool when done as
-				// they know when their use is complete.
-				f = s.pools.fetchBatchOp.Get()
-				f.IncRef()
-				fetchBatchOpsByHostIdx[hostIdx] = append(fetchBatchOpsByHostIdx[hostIdx], f)
-				f.request.RangeStart = rangeStart
-				f.request.RangeEnd = rangeEnd
-				f.request.RangeTimeType = rpc.TimeType_UNIX_NANOSECONDS
+			if hedging {
+				// Assigned to the initial or hedged replicas once all are known
+				replicaHostIdxs = append(replicaHostIdxs, hostIdx)
+				return
 			}
-
-			// Append IDWithNamespace to this request
-			f.append(namespace.Bytes(), tsID.Bytes(), completionFn)
+			appendFetchBatchOp(fetchBatchOpsByHostIdx, hostIdx, tsID, completionFn)
 		}); err != nil {
 			routeErr = err
 			break
 		}
 
+		if hedging {
+			hedgedCompletionFn := func(result interface{}, err error) {
+				complete(result, err, true)
+			}
+			sortHostIdxsByLatency(replicaHostIdxs, hostLatencies)
+			for i, hostIdx := range replicaHostIdxs {
+				if i < hedgeInitialReplicas {
+					appendFetchBatchOp(fetchBatchOpsByHostIdx, hostIdx, tsID, completionFn)
+					continue
+				}
+				appendFetchBatchOp(hedgeFetchBatchOpsByHostIdx, hostIdx, tsID, hedgedCompletionFn)
+			}
+			replicaHostIdxs = replicaHostIdxs[:0]
+		}
+
 		// Once we've enqueued we know how many to expect so retrieve and set length
 		results = s.pools.multiReaderIteratorArray.Get(int(enqueued))
 		results = results[:enqueued]
@@ -1480,6 +1618,25 @@ func (s *session) fetchIDsAttempt(
 		return nil, routeErr
 	}
 
+	if hedging {
+		var (
+			queues        = s.state.queues
+			initialQueues []hostQueue
+		)
+		for idx := range fetchBatchOpsByHostIdx {
+			if len(fetchBatchOpsByHostIdx[idx]) > 0 {
+				initialQueues = append(initialQueues, queues[idx])
+			}
+		}
+		// NB: the hedger must be set before enqueueing so that failures of the
+		// replicas initially read from can trigger the hedge early.
+		hedger = newReadHedger(s.hedgedReadDelay(initialQueues), func() {
+			s.enqueueHedgedFetchBatchOps(queues, hedgeFetchBatchOpsByHostIdx)
+		}, func() {
+			cancelHedgedFetchBatchOps(hedgeFetchBatchOpsByHostIdx)
+		})
+	}
+
 	// Enqueue fetch ops
 	for idx := range fetchBatchOpsByHostIdx {
 		for _, f := range fetchBatchOpsByHostIdx[idx] {
@@ -1498,12 +1655,22 @@ func (s *session) fetchIDsAttempt(
 	s.state.RUnlock()
 
 	if enqueueErr != nil {
+		if hedger != nil {
+			hedger.cancel()
+		}
 		s.log.Errorf("failed to enqueue fetch: %v", enqueueErr)
 		return nil, enqueueErr
 	}
 
 	wg.Wait()
 
+	if hedger != nil {
+		// Consistency has been satisfied for all IDs, cancel the hedge if it
+		// has not yet been sent, any responses from hedged replicas already
+		// sent to are discarded.
+		hedger.cancel()
+	}
+
 	resultErrLock.RLock()
 	retErr := resultErr
 	resultErrLock.RUnlock()
@@ -1514,6 +1681,68 @@ func (s *session) fetchIDsAttempt(
 	return iters, nil
 }
 
+func (s *session) hostFetchLatencies(queues []hostQueue) []time.Duration {
+	latencies := make([]time.Duration, len(queues))
+	for i, q := range queues {
+		// NB: hosts without enough recent fetches are treated as fastest
+		// so that their latency is learned.
+		latencies[i], _ = q.FetchLatency(s.opts.HedgedReadsPercentile())
+		if q.CircuitBreakerState() == CircuitBreakerOpen {
+			// NB: hosts with an open circuit fail immediately so prefer
+			// any other host over them.
+			latencies[i] = time.Duration(math.MaxInt64)
+		}
+	}
+	return latencies
+}
+
+// hedgedReadDelay returns the delay before hedging a read initially sent
+// to the given host queues, which is the slowest of their recent fetch
+// latencies at the hedged reads percentile.
+func (s *session) hedgedReadDelay(queues []hostQueue) time.Duration {
+	delay := s.opts.HedgedReadsMinDelay()
+	for _, q := range queues {
+		latency, ok := q.FetchLatency(s.opts.HedgedReadsPercentile())
+		if ok && latency > delay {
+			delay = latency
+		}
+	}
+	return delay
+}
+
+func (s *session) enqueueHedgedFetchBatchOps(
+	queues []hostQueue,
+	fetchBatchOpsByHostIdx [][]*fetchBatchOp,
+) {
+	s.metrics.fetchHedgesFired.Inc(1)
+
+	s.state.RLock()
+	for idx := range fetchBatchOpsByHostIdx {
+		for _, f := range fetchBatchOpsByHostIdx[idx] {
+			// Passing ownership of the op itself to the host queue
+			f.DecRef()
+			if err := queues[idx].Enqueue(f); err != nil {
+				// The queue may have been closed by a topology update since
+				// the read began, the op was still taken ownership of.
+				f.completeAll(nil, err)
+				f.DecRef()
+				f.Finalize()
+			}
+		}
+	}
+	s.state.RUnlock()
+}
+
+func cancelHedgedFetchBatchOps(fetchBatchOpsByHostIdx [][]*fetchBatchOp) {
+	for idx := range fetchBatchOpsByHostIdx {
+		for _, f := range fetchBatchOpsByHostIdx[idx] {
+			f.completeAll(nil, errHedgedReadCancelled)
+			f.DecRef()
+			f.Finalize()
+		}
+	}
+}
+
 func (s *session) writeConsistencyResult(
 	level topology.ConsistencyLevel,
 	majority, enqueued, responded, resultErrs int32,
@@ -1527,6 +1756,20 @@ func (s *session) writeConsistencyResult(
 	return nil
 }
 
+// writeZoneConsistencyResult checks the zone majority consistency level which
+// counts the distinct zones of the hosts that acknowledged the write rather
+// than the number of hosts.
+func (s *session) writeZoneConsistencyResult(
+	majorityZones, enqueued, responded, successZones int32,
+	errs []error,
+) error {
+	if !topology.WriteZoneConsistencyAchieved(int(majorityZones), int(successZones)) {
+		return newConsistencyResultError(topology.ConsistencyLevelZoneMajority,
+			int(enqueued), int(responded), errs)
+	}
+	return nil
+}
+
 func (s *session) readConsistencyResult(
 	level topology.ReadConsistencyLevel,
 	majority, enqueued, responded, resultErrs int32,
@@ -1586,6 +1829,16 @@ func (s *session) Replicas() int {
 	return v
 }
 
+func (s *session) HostCircuitBreakerStates() map[string]CircuitBreakerState {
+	s.state.RLock()
+	states := make(map[string]CircuitBreakerState, len(s.state.queues))
+	for _, q := range s.state.queues {
+		states[q.Host().ID()] = q.CircuitBreakerState()
+	}
+	s.state.RUnlock()
+	return states
+}
+
 func (s *session) TopologyMap() (topology.Map, error) {
 	s.state.RLock()
 	status := s.state.status
@@ -232,6 +232,105 @@ func TestSessionFetchIDsTrimsWindowsInTimeWindow(t *testing.T) {
 	assert.NoError(t, session.Close())
 }
 
+func TestSessionFetchIDsHedgedRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reporter := xmetrics.NewTestStatsReporter(xmetrics.NewTestStatsReporterOptions())
+	scope, closer := tally.NewRootScope(tally.ScopeOptions{Reporter: reporter}, time.Millisecond)
+	defer closer.Close()
+
+	opts := newSessionTestOptions().
+		SetReadConsistencyLevel(topology.ReadConsistencyLevelOne).
+		SetHedgedReadsEnabled(true).
+		SetHedgedReadsMinDelay(time.Millisecond)
+	opts = opts.SetInstrumentOptions(opts.InstrumentOptions().
+		SetMetricsScope(scope))
+
+	s, err := newSession(opts)
+	assert.NoError(t, err)
+	session := s.(*session)
+
+	start := time.Now().Truncate(time.Hour)
+	end := start.Add(2 * time.Hour)
+
+	fetches := testFetches([]testFetch{
+		{"foo", []testValue{
+			{1.0, start.Add(1 * time.Second), xtime.Second, []byte{1, 2, 3}},
+			{2.0, start.Add(2 * time.Second), xtime.Second, nil},
+		}},
+	})
+
+	var (
+		// The fastest host is initially read from but never responds in
+		// time, the hedged read to the next fastest host succeeds.
+		latencies = map[string]time.Duration{
+			testHostName(0): 20 * time.Millisecond,
+			testHostName(1): time.Millisecond,
+			testHostName(2): 5 * time.Millisecond,
+		}
+		respondingHost = testHostName(2)
+		enqueuedLock   sync.Mutex
+		enqueued       = make(map[string][]*fetchBatchOp)
+	)
+	session.newHostQueueFn = func(
+		host topology.Host,
+		opts hostQueueOpts,
+	) (hostQueue, error) {
+		hostQueue := NewMockhostQueue(ctrl)
+		hostQueue.EXPECT().Open()
+		hostQueue.EXPECT().Host().Return(host).AnyTimes()
+		hostQueue.EXPECT().ConnectionCount().Return(opts.opts.MinConnectionCount()).AnyTimes()
+		hostQueue.EXPECT().FetchLatency(gomock.Any()).Return(latencies[host.ID()], true).AnyTimes()
+		hostQueue.EXPECT().CircuitBreakerState().Return(CircuitBreakerClosed).AnyTimes()
+		hostQueue.EXPECT().Enqueue(gomock.Any()).DoAndReturn(func(o op) error {
+			fetch, ok := o.(*fetchBatchOp)
+			assert.True(t, ok)
+			enqueuedLock.Lock()
+			enqueued[host.ID()] = append(enqueued[host.ID()], fetch)
+			enqueuedLock.Unlock()
+			if host.ID() == respondingHost {
+				go fulfillTszFetchBatchOps(t, fetches, []*fetchBatchOp{fetch}, 0)
+			}
+			return nil
+		}).AnyTimes()
+		hostQueue.EXPECT().Close()
+		return hostQueue, nil
+	}
+
+	assert.NoError(t, session.Open())
+
+	results, err := session.FetchIDs(ident.StringID(testNamespaceName),
+		fetches.IDsIter(), start, end)
+	assert.NoError(t, err)
+	assertFetchResults(t, start, end, fetches, results)
+
+	enqueuedLock.Lock()
+	for i := 0; i < sessionTestReplicas; i++ {
+		assert.Equal(t, 1, len(enqueued[testHostName(i)]))
+	}
+	// Release the ops of the hosts that never responded
+	for id, ops := range enqueued {
+		if id == respondingHost {
+			continue
+		}
+		for _, op := range ops {
+			op.completeAll(nil, fmt.Errorf("too slow"))
+		}
+	}
+	enqueuedLock.Unlock()
+
+	assert.NoError(t, session.Close())
+
+	counters := reporter.Counters()
+	for counters["fetch.success"] == 0 {
+		time.Sleep(time.Millisecond)
+		counters = reporter.Counters()
+	}
+	assert.Equal(t, 1, int(counters["fetch.hedges-fired"]))
+	assert.Equal(t, 1, int(counters["fetch.hedges-won"]))
+}
+
 func TestSessionFetchIDsBadRequestErrorIsNonRetryable(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
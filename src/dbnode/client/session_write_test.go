@@ -353,6 +353,46 @@ func TestSessionWriteConsistencyLevelOne(t *testing.T) {
 	testWriteConsistencyLevel(t, ctrl, level, 0, 3, outcomeFail)
 }
 
+func TestSessionWriteConsistencyLevelZoneMajority(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The test hosts share a single zone so one ack meets a zone majority.
+	shardSet := sessionTestShardSet()
+	opts := newSessionTestOptions().
+		SetTopologyInitializer(topology.NewStaticInitializer(
+			topology.NewStaticOptions().
+				SetReplicas(sessionTestReplicas).
+				SetShardSet(shardSet).
+				SetHostShardSets(sessionTestHostAndShards(shardSet)).
+				SetZoneReplicaFactors(map[string]int{"": sessionTestReplicas})))
+
+	level := topology.ConsistencyLevelZoneMajority
+	for i := 0; i <= 2; i++ {
+		testWriteConsistencyLevelWithOptions(t, ctrl, opts, level, 3-i, i, outcomeSuccess)
+	}
+	testWriteConsistencyLevelWithOptions(t, ctrl, opts, level, 0, 3, outcomeFail)
+}
+
+func TestSessionWriteConsistencyLevelZoneMajorityNoZoneReplicaFactors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := newSessionTestOptions().
+		SetWriteConsistencyLevel(topology.ConsistencyLevelZoneMajority)
+	session := newTestSession(t, opts).(*session)
+
+	mockHostQueues(ctrl, session, sessionTestReplicas, nil)
+
+	assert.NoError(t, session.Open())
+
+	err := session.Write(ident.StringID("testNs"), ident.StringID("foo"),
+		time.Now(), 1.0, xtime.Second, nil)
+	assert.Error(t, err)
+
+	assert.NoError(t, session.Close())
+}
+
 func testWriteConsistencyLevel(
 	t *testing.T,
 	ctrl *gomock.Controller,
@@ -360,7 +400,18 @@ func testWriteConsistencyLevel(
 	success, failures int,
 	expected outcome,
 ) {
-	opts := newSessionTestOptions()
+	testWriteConsistencyLevelWithOptions(t, ctrl, newSessionTestOptions(),
+		level, success, failures, expected)
+}
+
+func testWriteConsistencyLevelWithOptions(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	opts Options,
+	level topology.ConsistencyLevel,
+	success, failures int,
+	expected outcome,
+) {
 	opts = opts.SetWriteConsistencyLevel(level)
 
 	reporterOpts := xmetrics.NewTestStatsReporterOptions().
@@ -0,0 +1,165 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	xtls "github.com/m3db/m3/src/x/tls"
+
+	"github.com/uber/tchannel-go"
+)
+
+// tlsTunnel accepts connections on a loopback address and forwards each of
+// them to a remote address over a new TLS connection.
+// NB: The version of tchannel in use always dials plain TCP connections, so
+// channels to nodes served over TLS connect to a tunnel instead.
+type tlsTunnel struct {
+	sync.Mutex
+
+	listener net.Listener
+	address  string
+	dialer   *xtls.Dialer
+	timeout  time.Duration
+	conns    map[net.Conn]struct{}
+	closed   bool
+}
+
+func newTLSTunnel(
+	address string,
+	dialer *xtls.Dialer,
+	timeout time.Duration,
+) (*tlsTunnel, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	t := &tlsTunnel{
+		listener: listener,
+		address:  address,
+		dialer:   dialer,
+		timeout:  timeout,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	go t.serve()
+	return t, nil
+}
+
+// Address returns the loopback address to connect to.
+func (t *tlsTunnel) Address() string {
+	return t.listener.Addr().String()
+}
+
+func (t *tlsTunnel) serve() {
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			// The listener is only closed when the tunnel is closed.
+			return
+		}
+		go t.forward(conn)
+	}
+}
+
+func (t *tlsTunnel) forward(local net.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	remote, err := t.dialer.DialContext(ctx, "tcp", t.address)
+	cancel()
+	if err != nil {
+		local.Close() // nolint: errcheck
+		return
+	}
+
+	if !t.track(local, remote) {
+		local.Close()  // nolint: errcheck
+		remote.Close() // nolint: errcheck
+		return
+	}
+
+	// Closing both connections once either side is done unblocks the copy
+	// in the other direction.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		io.Copy(remote, local) // nolint: errcheck
+		local.Close()          // nolint: errcheck
+		remote.Close()         // nolint: errcheck
+		wg.Done()
+	}()
+	io.Copy(local, remote) // nolint: errcheck
+	local.Close()          // nolint: errcheck
+	remote.Close()         // nolint: errcheck
+	wg.Wait()
+
+	t.untrack(local, remote)
+}
+
+func (t *tlsTunnel) track(conns ...net.Conn) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.closed {
+		return false
+	}
+	for _, conn := range conns {
+		t.conns[conn] = struct{}{}
+	}
+	return true
+}
+
+func (t *tlsTunnel) untrack(conns ...net.Conn) {
+	t.Lock()
+	for _, conn := range conns {
+		delete(t.conns, conn)
+	}
+	t.Unlock()
+}
+
+// Close stops accepting connections and closes the forwarded connections.
+func (t *tlsTunnel) Close() {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.closed {
+		return
+	}
+	t.closed = true
+	t.listener.Close() // nolint: errcheck
+	for conn := range t.conns {
+		conn.Close() // nolint: errcheck
+	}
+}
+
+// tunneledChannel is a channel connected to a node through a TLS tunnel.
+type tunneledChannel struct {
+	channel *tchannel.Channel
+	tunnel  *tlsTunnel
+}
+
+func (c tunneledChannel) Close() {
+	c.channel.Close()
+	c.tunnel.Close()
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	xtls "github.com/m3db/m3/src/x/tls"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTLSEchoServer(t *testing.T) net.Listener {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	require.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn) // nolint: errcheck
+				conn.Close()        // nolint: errcheck
+			}()
+		}
+	}()
+	return listener
+}
+
+func TestTLSTunnelForwardsOverTLS(t *testing.T) {
+	server := newTestTLSEchoServer(t)
+	defer server.Close()
+
+	noReload := time.Duration(0)
+	dialer, err := xtls.Configuration{
+		InsecureSkipVerify: true,
+		ReloadInterval:     &noReload,
+	}.NewDialer()
+	require.NoError(t, err)
+
+	tunnel, err := newTLSTunnel(server.Addr().String(), dialer, time.Second)
+	require.NoError(t, err)
+	defer tunnel.Close()
+
+	conn, err := net.Dial("tcp", tunnel.Address())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	require.Equal(t, "ping", string(buf))
+
+	// Closing the tunnel closes the forwarded connections.
+	tunnel.Close()
+	_, err = conn.Read(buf)
+	require.Error(t, err)
+}
+
+func TestTLSTunnelClosesConnectionIfHandshakeFails(t *testing.T) {
+	// A plain TCP server never completes the TLS handshake.
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer server.Close()
+
+	noReload := time.Duration(0)
+	dialer, err := xtls.Configuration{ReloadInterval: &noReload}.NewDialer()
+	require.NoError(t, err)
+
+	tunnel, err := newTLSTunnel(server.Addr().String(), dialer, 100*time.Millisecond)
+	require.NoError(t, err)
+	defer tunnel.Close()
+
+	conn, err := net.Dial("tcp", tunnel.Address())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	require.Error(t, err)
+	if netErr, ok := err.(net.Error); ok {
+		require.False(t, netErr.Timeout())
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
 	"github.com/m3db/m3/src/dbnode/topology"
 	"github.com/m3db/m3/src/x/serialize"
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/context"
 	"github.com/m3db/m3x/ident"
 	"github.com/m3db/m3x/instrument"
@@ -252,6 +253,12 @@ type Options interface {
 	// ChannelOptions returns the channelOptions
 	ChannelOptions() *tchannel.ChannelOptions
 
+	// SetTLSDialer sets the TLS dialer, if set connections are made over TLS.
+	SetTLSDialer(value *xtls.Dialer) Options
+
+	// TLSDialer returns the TLS dialer.
+	TLSDialer() *xtls.Dialer
+
 	// SetMaxConnectionCount sets the maxConnectionCount
 	SetMaxConnectionCount(value int) Options
 
@@ -294,6 +301,36 @@ type Options interface {
 	// FetchRequestTimeout returns the fetchRequestTimeout
 	FetchRequestTimeout() time.Duration
 
+	// SetHedgedReadsEnabled sets whether fetches are hedged, i.e. initially
+	// only sent to the replicas required to satisfy the read consistency level
+	// and then sent to the remaining replicas if they are slow to respond.
+	SetHedgedReadsEnabled(value bool) Options
+
+	// HedgedReadsEnabled returns whether fetches are hedged, i.e. initially
+	// only sent to the replicas required to satisfy the read consistency level
+	// and then sent to the remaining replicas if they are slow to respond.
+	HedgedReadsEnabled() bool
+
+	// SetHedgedReadsPercentile sets the percentile of the recent fetch latency
+	// of a host to wait for before hedging a fetch sent to it.
+	SetHedgedReadsPercentile(value float64) Options
+
+	// HedgedReadsPercentile returns the percentile of the recent fetch latency
+	// of a host to wait for before hedging a fetch sent to it.
+	HedgedReadsPercentile() float64
+
+	// SetHedgedReadsMinDelay sets the minimum delay before hedging a fetch.
+	SetHedgedReadsMinDelay(value time.Duration) Options
+
+	// HedgedReadsMinDelay returns the minimum delay before hedging a fetch.
+	HedgedReadsMinDelay() time.Duration
+
+	// SetHostCircuitBreakerOptions sets the host circuit breaker options.
+	SetHostCircuitBreakerOptions(value HostCircuitBreakerOptions) Options
+
+	// HostCircuitBreakerOptions returns the host circuit breaker options.
+	HostCircuitBreakerOptions() HostCircuitBreakerOptions
+
 	// SetTruncateRequestTimeout sets the truncateRequestTimeout
 	SetTruncateRequestTimeout(value time.Duration) Options
 
@@ -476,6 +513,66 @@ type Options interface {
 }
 
 // AdminOptions is a set of administration client options
+// HostCircuitBreakerOptions is a set of options for the per host circuit
+// breakers of a session, which fail requests to a host immediately while
+// too many of the recent requests to it have failed or timed out.
+type HostCircuitBreakerOptions interface {
+	// Validate validates the options.
+	Validate() error
+
+	// SetEnabled sets whether host circuit breakers are enabled.
+	SetEnabled(value bool) HostCircuitBreakerOptions
+
+	// Enabled returns whether host circuit breakers are enabled.
+	Enabled() bool
+
+	// SetWindow sets the window over which request outcomes are counted.
+	SetWindow(value time.Duration) HostCircuitBreakerOptions
+
+	// Window returns the window over which request outcomes are counted.
+	Window() time.Duration
+
+	// SetMinRequests sets the minimum number of requests in a window
+	// before the circuit can open.
+	SetMinRequests(value int) HostCircuitBreakerOptions
+
+	// MinRequests returns the minimum number of requests in a window
+	// before the circuit can open.
+	MinRequests() int
+
+	// SetErrorRateThreshold sets the rate of failed requests in a window
+	// at which the circuit opens.
+	SetErrorRateThreshold(value float64) HostCircuitBreakerOptions
+
+	// ErrorRateThreshold returns the rate of failed requests in a window
+	// at which the circuit opens.
+	ErrorRateThreshold() float64
+
+	// SetTimeoutRateThreshold sets the rate of timed out requests in a window
+	// at which the circuit opens.
+	SetTimeoutRateThreshold(value float64) HostCircuitBreakerOptions
+
+	// TimeoutRateThreshold returns the rate of timed out requests in a window
+	// at which the circuit opens.
+	TimeoutRateThreshold() float64
+
+	// SetOpenDuration sets how long the circuit stays open before
+	// a probe request is let through.
+	SetOpenDuration(value time.Duration) HostCircuitBreakerOptions
+
+	// OpenDuration returns how long the circuit stays open before
+	// a probe request is let through.
+	OpenDuration() time.Duration
+}
+
+// HostCircuitBreakerReporter reports the state of the per host circuit
+// breakers of a session, sessions created by the client implement it.
+type HostCircuitBreakerReporter interface {
+	// HostCircuitBreakerStates returns the circuit breaker state of each
+	// host the session has a queue for, keyed by host ID.
+	HostCircuitBreakerStates() map[string]CircuitBreakerState
+}
+
 type AdminOptions interface {
 	Options
 
@@ -562,6 +659,13 @@ type hostQueue interface {
 	// BorrowConnection will borrow a connection and execute a user function
 	BorrowConnection(fn withConnectionFn) error
 
+	// FetchLatency returns the given percentile of the recent fetch latencies
+	// of the host, returns false if too few fetches have completed to tell
+	FetchLatency(percentile float64) (time.Duration, bool)
+
+	// CircuitBreakerState returns the state of the host circuit breaker
+	CircuitBreakerState() CircuitBreakerState
+
 	// Close the host queue, will flush any operations still pending
 	Close()
 }
@@ -56,6 +56,8 @@ type writeState struct {
 	tagEncoder        serialize.TagEncoder
 	majority, pending int32
 	success           int32
+	majorityZones     int32
+	successZones      []string
 	errors            []error
 
 	queues         []hostQueue
@@ -88,6 +90,7 @@ func (w *writeState) close() {
 
 	w.op, w.majority, w.pending, w.success = nil, 0, 0, 0
 	w.nsID, w.tsID, w.tagEncoder = nil, nil, nil
+	w.majorityZones, w.successZones = 0, w.successZones[:0]
 
 	for i := range w.errors {
 		w.errors[i] = nil
@@ -136,6 +139,7 @@ func (w *writeState) completionFn(result interface{}, err error) {
 		wErr = xerrors.NewRetryableError(fmt.Errorf(errStr, w.op.ShardID(), hostID))
 	} else {
 		w.success++
+		w.addSuccessZone(hostShardSet.Host().Zone())
 	}
 
 	if wErr != nil {
@@ -155,12 +159,25 @@ func (w *writeState) completionFn(result interface{}, err error) {
 		if w.pending == 0 {
 			w.Signal()
 		}
+	case topology.ConsistencyLevelZoneMajority:
+		if int32(len(w.successZones)) >= w.majorityZones || w.pending == 0 {
+			w.Signal()
+		}
 	}
 
 	w.Unlock()
 	w.decRef()
 }
 
+func (w *writeState) addSuccessZone(zone string) {
+	for _, z := range w.successZones {
+		if z == zone {
+			return
+		}
+	}
+	w.successZones = append(w.successZones, zone)
+}
+
 type writeStatePool struct {
 	pool           pool.ObjectPool
 	tagEncoderPool serialize.TagEncoderPool
@@ -154,6 +154,7 @@ type fakeHost struct{ id string }
 
 func (f fakeHost) ID() string      { return f.id }
 func (f fakeHost) Address() string { return "" }
+func (f fakeHost) Zone() string    { return "" }
 func (f fakeHost) String() string  { return "" }
 
 func writeTestSetup(t *testing.T, writeWg *sync.WaitGroup) (*writeState, *session, topology.Host) {
@@ -26,6 +26,7 @@ import (
 
 	clusterclient "github.com/m3db/m3/src/cluster/client"
 	etcdclient "github.com/m3db/m3/src/cluster/client/etcd"
+	fileclient "github.com/m3db/m3/src/cluster/client/file"
 	"github.com/m3db/m3/src/cluster/kv"
 	m3clusterkvmem "github.com/m3db/m3/src/cluster/kv/mem"
 	"github.com/m3db/m3/src/cluster/services"
@@ -38,7 +39,7 @@ import (
 )
 
 var (
-	errInvalidConfig = errors.New("must supply either service or static config")
+	errInvalidConfig = errors.New("must supply exactly one of service, local or static config")
 )
 
 // Configuration is a configuration that can be used to create namespaces, a topology, and kv store
@@ -46,6 +47,10 @@ type Configuration struct {
 	// Service is used when a topology initializer is not supplied.
 	Service *etcdclient.Configuration `yaml:"service"`
 
+	// Local is used for running M3DB with the cluster metadata persisted to
+	// local files instead of etcd, intended for single node deployments.
+	Local *fileclient.Configuration `yaml:"local"`
+
 	// StaticConfiguration is used for running M3DB with a static config
 	Static *StaticConfiguration `yaml:"static"`
 
@@ -107,7 +112,13 @@ type ConfigurationParameters struct {
 func (c Configuration) Configure(cfgParams ConfigurationParameters) (ConfigureResults, error) {
 	var emptyConfig ConfigureResults
 
-	if c.Service != nil && c.Static != nil {
+	numConfigs := 0
+	for _, set := range []bool{c.Service != nil, c.Local != nil, c.Static != nil} {
+		if set {
+			numConfigs++
+		}
+	}
+	if numConfigs > 1 {
 		return emptyConfig, errInvalidConfig
 	}
 
@@ -115,6 +126,10 @@ func (c Configuration) Configure(cfgParams ConfigurationParameters) (ConfigureRe
 		return c.configureDynamic(cfgParams)
 	}
 
+	if c.Local != nil {
+		return c.configureLocal(cfgParams)
+	}
+
 	if c.Static != nil {
 		return c.configureStatic(cfgParams)
 	}
@@ -134,17 +149,45 @@ func (c Configuration) configureDynamic(cfgParams ConfigurationParameters) (Conf
 		return ConfigureResults{}, err
 	}
 
+	serviceID := services.NewServiceID().
+		SetName(c.Service.Service).
+		SetEnvironment(c.Service.Env).
+		SetZone(c.Service.Zone)
+
+	return configureWithClient(cfgParams, configSvcClient, serviceID)
+}
+
+func (c Configuration) configureLocal(cfgParams ConfigurationParameters) (ConfigureResults, error) {
+	localClientOpts := c.Local.NewOptions().
+		SetInstrumentOptions(cfgParams.InstrumentOpts).
+		// Set timeout to zero so it will wait indefinitely for the
+		// initial value.
+		SetServicesOptions(services.NewOptions().SetInitTimeout(0))
+	localClient, err := fileclient.NewClient(localClientOpts)
+	if err != nil {
+		err = fmt.Errorf("could not create local m3cluster client: %v", err)
+		return ConfigureResults{}, err
+	}
+
+	serviceID := services.NewServiceID().
+		SetName(c.Local.Service).
+		SetEnvironment(c.Local.Env).
+		SetZone(c.Local.Zone)
+
+	return configureWithClient(cfgParams, localClient, serviceID)
+}
+
+func configureWithClient(
+	cfgParams ConfigurationParameters,
+	configSvcClient clusterclient.Client,
+	serviceID services.ServiceID,
+) (ConfigureResults, error) {
 	dynamicOpts := namespace.NewDynamicOptions().
 		SetInstrumentOptions(cfgParams.InstrumentOpts).
 		SetConfigServiceClient(configSvcClient).
 		SetNamespaceRegistryKey(kvconfig.NamespacesKey)
 	nsInit := namespace.NewDynamicInitializer(dynamicOpts)
 
-	serviceID := services.NewServiceID().
-		SetName(c.Service.Service).
-		SetEnvironment(c.Service.Env).
-		SetZone(c.Service.Zone)
-
 	topoOpts := topology.NewDynamicOptions().
 		SetConfigServiceClient(configSvcClient).
 		SetServiceID(serviceID).
@@ -109,8 +109,9 @@ func (m *RetentionOptions) GetBlockDataExpiryAfterNotAccessPeriodNanos() int64 {
 }
 
 type IndexOptions struct {
-	Enabled        bool  `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	BlockSizeNanos int64 `protobuf:"varint,2,opt,name=blockSizeNanos,proto3" json:"blockSizeNanos,omitempty"`
+	Enabled              bool  `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	BlockSizeNanos       int64 `protobuf:"varint,2,opt,name=blockSizeNanos,proto3" json:"blockSizeNanos,omitempty"`
+	RollupBlockSizeNanos int64 `protobuf:"varint,3,opt,name=rollupBlockSizeNanos,proto3" json:"rollupBlockSizeNanos,omitempty"`
 }
 
 func (m *IndexOptions) Reset()                    { *m = IndexOptions{} }
@@ -132,6 +133,13 @@ func (m *IndexOptions) GetBlockSizeNanos() int64 {
 	return 0
 }
 
+func (m *IndexOptions) GetRollupBlockSizeNanos() int64 {
+	if m != nil {
+		return m.RollupBlockSizeNanos
+	}
+	return 0
+}
+
 type NamespaceOptions struct {
 	BootstrapEnabled  bool              `protobuf:"varint,1,opt,name=bootstrapEnabled,proto3" json:"bootstrapEnabled,omitempty"`
 	FlushEnabled      bool              `protobuf:"varint,2,opt,name=flushEnabled,proto3" json:"flushEnabled,omitempty"`
@@ -309,6 +317,11 @@ func (m *IndexOptions) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintNamespace(dAtA, i, uint64(m.BlockSizeNanos))
 	}
+	if m.RollupBlockSizeNanos != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintNamespace(dAtA, i, uint64(m.RollupBlockSizeNanos))
+	}
 	return i, nil
 }
 
@@ -498,6 +511,9 @@ func (m *IndexOptions) Size() (n int) {
 	if m.BlockSizeNanos != 0 {
 		n += 1 + sovNamespace(uint64(m.BlockSizeNanos))
 	}
+	if m.RollupBlockSizeNanos != 0 {
+		n += 1 + sovNamespace(uint64(m.RollupBlockSizeNanos))
+	}
 	return n
 }
 
@@ -798,6 +814,25 @@ func (m *IndexOptions) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RollupBlockSizeNanos", wireType)
+			}
+			m.RollupBlockSizeNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNamespace
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RollupBlockSizeNanos |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNamespace(dAtA[iNdEx:])
@@ -1338,38 +1373,39 @@ func init() {
 }
 
 var fileDescriptorNamespace = []byte{
-	// 513 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xdf, 0x6e, 0xd3, 0x30,
-	0x14, 0xc6, 0x49, 0xbb, 0x3f, 0xdd, 0x59, 0x61, 0xc1, 0x42, 0xa2, 0x02, 0xa9, 0x9a, 0x0a, 0x42,
-	0x15, 0x42, 0x8d, 0x68, 0x6f, 0x10, 0x5c, 0x8d, 0x51, 0x26, 0x24, 0x54, 0x2a, 0xc3, 0xd5, 0xee,
-	0x9c, 0xe4, 0xb4, 0xb5, 0xd6, 0xd8, 0x91, 0xed, 0xc0, 0xca, 0x43, 0x20, 0xde, 0x83, 0x17, 0xe1,
-	0x82, 0x0b, 0x1e, 0x01, 0x95, 0x17, 0x41, 0x71, 0x48, 0x97, 0xb8, 0xbb, 0xd8, 0x4d, 0xe5, 0x7e,
-	0xe7, 0x67, 0x1f, 0xe7, 0x7c, 0x5f, 0x02, 0x67, 0x73, 0x6e, 0x16, 0x59, 0x38, 0x88, 0x64, 0x12,
-	0x24, 0xa3, 0x38, 0x0c, 0x92, 0x51, 0xa0, 0x55, 0x14, 0xc4, 0xa1, 0x90, 0x31, 0x06, 0x73, 0x14,
-	0xa8, 0x98, 0xc1, 0x38, 0x48, 0x95, 0x34, 0x32, 0x10, 0x2c, 0x41, 0x9d, 0xb2, 0x08, 0xaf, 0x56,
-	0x03, 0x5b, 0x21, 0x07, 0x1b, 0xa1, 0xf7, 0xab, 0x01, 0x3e, 0x45, 0x83, 0xc2, 0x70, 0x29, 0x3e,
-	0xa4, 0xf9, 0xaf, 0x26, 0x43, 0xb8, 0xa7, 0x4a, 0x6d, 0x8a, 0x8a, 0xcb, 0x78, 0xc2, 0x84, 0xd4,
-	0x1d, 0xef, 0xd8, 0xeb, 0x37, 0xe9, 0xb5, 0x35, 0xf2, 0x04, 0xee, 0x84, 0x4b, 0x19, 0x5d, 0x7c,
-	0xe4, 0x5f, 0xb1, 0xa0, 0x1b, 0x96, 0x76, 0x54, 0xf2, 0x0c, 0xee, 0x86, 0xd9, 0x6c, 0x86, 0xea,
-	0x6d, 0x66, 0x32, 0xf5, 0x1f, 0x6d, 0x5a, 0x74, 0xbb, 0x40, 0xfa, 0x70, 0x54, 0x88, 0x53, 0xa6,
-	0x4d, 0xc1, 0xee, 0x58, 0xd6, 0x95, 0x2d, 0x99, 0x77, 0x7a, 0xc3, 0x0c, 0x1b, 0x5f, 0xa6, 0x5c,
-	0xad, 0x3a, 0xbb, 0xc7, 0x5e, 0xbf, 0x45, 0x5d, 0x99, 0x9c, 0x43, 0xdf, 0x91, 0x4e, 0x66, 0x06,
-	0xd5, 0x44, 0x9a, 0x93, 0x28, 0x42, 0xad, 0xab, 0x4f, 0xbc, 0x67, 0x9b, 0xdd, 0x98, 0xef, 0x4d,
-	0xa1, 0xfd, 0x4e, 0xc4, 0x78, 0x59, 0x4e, 0xb2, 0x03, 0xfb, 0x28, 0x58, 0xb8, 0xc4, 0xd8, 0x0e,
-	0xaf, 0x45, 0xcb, 0xbf, 0x37, 0x9d, 0x57, 0xef, 0x5b, 0x13, 0xfc, 0x49, 0x69, 0x57, 0x79, 0xec,
-	0x53, 0xf0, 0x43, 0x29, 0x8d, 0x36, 0x8a, 0xa5, 0xe3, 0xda, 0xf9, 0x5b, 0x3a, 0xe9, 0x41, 0x7b,
-	0xb6, 0xcc, 0xf4, 0xa2, 0xe4, 0x1a, 0x96, 0xab, 0x69, 0xb9, 0x29, 0x5f, 0x14, 0x37, 0xa8, 0x3f,
-	0xc9, 0x53, 0x99, 0x24, 0xdc, 0xbc, 0x97, 0x73, 0x6b, 0x4a, 0x8b, 0x6e, 0x17, 0xf2, 0xab, 0x47,
-	0x4b, 0x64, 0x22, 0xdb, 0xf4, 0xde, 0xb1, 0xa8, 0xa3, 0x92, 0xc7, 0x70, 0x5b, 0x61, 0xca, 0xb8,
-	0x2a, 0xb1, 0xc2, 0x90, 0xba, 0x48, 0xce, 0xc0, 0x57, 0x4e, 0x00, 0xed, 0xd8, 0x0f, 0x87, 0x0f,
-	0x07, 0x57, 0xc1, 0x75, 0x33, 0x4a, 0xb7, 0x36, 0xe5, 0x09, 0xd0, 0x82, 0xa5, 0x7a, 0x21, 0x4d,
-	0xd9, 0x70, 0xbf, 0x48, 0x80, 0x23, 0x93, 0x57, 0xd0, 0xe6, 0x15, 0x97, 0x3a, 0x2d, 0xdb, 0xee,
-	0x7e, 0xa5, 0x5d, 0xd5, 0x44, 0x5a, 0x83, 0x7b, 0x3f, 0x3c, 0x68, 0x51, 0x9c, 0x73, 0x6d, 0xd4,
-	0x8a, 0x9c, 0x02, 0x6c, 0x36, 0xe5, 0xef, 0x47, 0xb3, 0x7f, 0x38, 0x7c, 0x54, 0xbb, 0x76, 0x01,
-	0x0e, 0x36, 0x16, 0xea, 0xb1, 0x30, 0x6a, 0x45, 0x2b, 0xdb, 0x1e, 0x9c, 0xc3, 0x91, 0x53, 0x26,
-	0x3e, 0x34, 0x2f, 0x70, 0x65, 0x3d, 0x3d, 0xa0, 0xf9, 0x92, 0x3c, 0x87, 0xdd, 0xcf, 0x6c, 0x99,
-	0xa1, 0xf5, 0xaf, 0x3e, 0x1b, 0x37, 0x1e, 0xb4, 0x20, 0x5f, 0x36, 0x5e, 0x78, 0xaf, 0xfd, 0x9f,
-	0xeb, 0xae, 0xf7, 0x7b, 0xdd, 0xf5, 0xfe, 0xac, 0xbb, 0xde, 0xf7, 0xbf, 0xdd, 0x5b, 0xe1, 0x9e,
-	0xfd, 0x06, 0x8c, 0xfe, 0x05, 0x00, 0x00, 0xff, 0xff, 0x30, 0x42, 0xc6, 0x09, 0x4e, 0x04, 0x00,
-	0x00,
+	// 535 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0x4f, 0x6f, 0xd3, 0x4c,
+	0x10, 0xc6, 0xe3, 0xb8, 0x7f, 0xd2, 0x69, 0xde, 0xb7, 0x61, 0x85, 0x84, 0x05, 0x92, 0x55, 0x05,
+	0x84, 0x22, 0x84, 0x62, 0x91, 0x5c, 0x10, 0x9c, 0xda, 0x12, 0x2a, 0x24, 0x14, 0xaa, 0x85, 0x53,
+	0x6f, 0x6b, 0x7b, 0x92, 0x58, 0xb5, 0x77, 0xad, 0xdd, 0x35, 0x34, 0x9c, 0x39, 0x23, 0xbe, 0x07,
+	0x5f, 0x84, 0x03, 0x87, 0x1e, 0x39, 0xa2, 0xe4, 0x8b, 0x20, 0xaf, 0xeb, 0x34, 0x76, 0x7a, 0xe8,
+	0xc5, 0x5a, 0x3f, 0xf3, 0x1b, 0xcd, 0xec, 0x3c, 0x63, 0xc3, 0xe9, 0x34, 0xd2, 0xb3, 0xcc, 0xef,
+	0x07, 0x22, 0xf1, 0x92, 0x61, 0xe8, 0x7b, 0xc9, 0xd0, 0x53, 0x32, 0xf0, 0x42, 0x9f, 0x8b, 0x10,
+	0xbd, 0x29, 0x72, 0x94, 0x4c, 0x63, 0xe8, 0xa5, 0x52, 0x68, 0xe1, 0x71, 0x96, 0xa0, 0x4a, 0x59,
+	0x80, 0x37, 0xa7, 0xbe, 0x89, 0x90, 0xbd, 0x95, 0xd0, 0xfd, 0xdd, 0x84, 0x0e, 0x45, 0x8d, 0x5c,
+	0x47, 0x82, 0x7f, 0x48, 0xf3, 0xa7, 0x22, 0x03, 0xb8, 0x2f, 0x4b, 0xed, 0x0c, 0x65, 0x24, 0xc2,
+	0x31, 0xe3, 0x42, 0x39, 0xd6, 0xa1, 0xd5, 0xb3, 0xe9, 0xad, 0x31, 0xf2, 0x14, 0xfe, 0xf7, 0x63,
+	0x11, 0x5c, 0x7c, 0x8c, 0xbe, 0x62, 0x41, 0x37, 0x0d, 0x5d, 0x53, 0xc9, 0x73, 0xb8, 0xe7, 0x67,
+	0x93, 0x09, 0xca, 0xb7, 0x99, 0xce, 0xe4, 0x35, 0x6a, 0x1b, 0x74, 0x33, 0x40, 0x7a, 0x70, 0x50,
+	0x88, 0x67, 0x4c, 0xe9, 0x82, 0xdd, 0x32, 0x6c, 0x5d, 0x36, 0x64, 0x5e, 0xe9, 0x0d, 0xd3, 0x6c,
+	0x74, 0x99, 0x46, 0x72, 0xee, 0x6c, 0x1f, 0x5a, 0xbd, 0x16, 0xad, 0xcb, 0xe4, 0x1c, 0x7a, 0x35,
+	0xe9, 0x68, 0xa2, 0x51, 0x8e, 0x85, 0x3e, 0x0a, 0x02, 0x54, 0x6a, 0xfd, 0xc6, 0x3b, 0xa6, 0xd8,
+	0x9d, 0xf9, 0xee, 0x37, 0x0b, 0xda, 0xef, 0x78, 0x88, 0x97, 0xe5, 0x28, 0x1d, 0xd8, 0x45, 0xce,
+	0xfc, 0x18, 0x43, 0x33, 0xbd, 0x16, 0x2d, 0x5f, 0xef, 0x3c, 0xb0, 0xdc, 0x0c, 0x11, 0xc7, 0x59,
+	0x7a, 0x5c, 0xa5, 0xed, 0x6b, 0x33, 0x6e, 0x89, 0x75, 0xbf, 0xdb, 0xd0, 0x19, 0x97, 0x1e, 0x97,
+	0xad, 0x3c, 0x83, 0x8e, 0x2f, 0x84, 0x56, 0x5a, 0xb2, 0x74, 0x54, 0xe9, 0x69, 0x43, 0x27, 0x5d,
+	0x68, 0x4f, 0xe2, 0x4c, 0xcd, 0x4a, 0xae, 0x69, 0xb8, 0x8a, 0x96, 0x3b, 0xf9, 0x45, 0x46, 0x1a,
+	0xd5, 0x27, 0x71, 0x22, 0x92, 0x24, 0xd2, 0xef, 0xc5, 0xd4, 0x74, 0xd5, 0xa2, 0x9b, 0x81, 0xfc,
+	0xba, 0x41, 0x8c, 0x8c, 0x67, 0xab, 0xda, 0x5b, 0x06, 0xad, 0xa9, 0xe4, 0x09, 0xfc, 0x27, 0x31,
+	0x65, 0x91, 0x2c, 0xb1, 0xc2, 0xc5, 0xaa, 0x48, 0x4e, 0xa1, 0x23, 0x6b, 0x5b, 0x6b, 0xbc, 0xda,
+	0x1f, 0x3c, 0xea, 0xdf, 0x6c, 0x7b, 0x7d, 0xb1, 0xe9, 0x46, 0x52, 0xbe, 0x36, 0x8a, 0xb3, 0x54,
+	0xcd, 0x84, 0x2e, 0x0b, 0xee, 0x16, 0x6b, 0x53, 0x93, 0xc9, 0x6b, 0x68, 0x47, 0x6b, 0xce, 0x3a,
+	0x2d, 0x53, 0xee, 0xc1, 0x5a, 0xb9, 0x75, 0xe3, 0x69, 0x05, 0xee, 0xfe, 0xb4, 0xa0, 0x45, 0x71,
+	0x1a, 0x29, 0x2d, 0xe7, 0xe4, 0x04, 0x60, 0x95, 0x94, 0x7f, 0x54, 0x76, 0x6f, 0x7f, 0xf0, 0xb8,
+	0xd2, 0x76, 0x01, 0xf6, 0x57, 0x16, 0xaa, 0x11, 0xd7, 0x72, 0x4e, 0xd7, 0xd2, 0x1e, 0x9e, 0xc3,
+	0x41, 0x2d, 0x4c, 0x3a, 0x60, 0x5f, 0xe0, 0xdc, 0x78, 0xba, 0x47, 0xf3, 0x23, 0x79, 0x01, 0xdb,
+	0x9f, 0x59, 0x9c, 0xa1, 0xd3, 0xdc, 0x98, 0x4d, 0x7d, 0x3d, 0x68, 0x41, 0xbe, 0x6a, 0xbe, 0xb4,
+	0x8e, 0x9d, 0x5f, 0x0b, 0xd7, 0xba, 0x5a, 0xb8, 0xd6, 0xdf, 0x85, 0x6b, 0xfd, 0x58, 0xba, 0x8d,
+	0xab, 0xa5, 0xdb, 0xf8, 0xb3, 0x74, 0x1b, 0xfe, 0x8e, 0xf9, 0x81, 0x0c, 0xff, 0x0d, 0x00, 0x45,
+	0xc9, 0xd3, 0x7a, 0x8b, 0x04, 0x00, 0x00,
 }
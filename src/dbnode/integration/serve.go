@@ -110,7 +110,7 @@ func openAndServe(
 	defer httpjsonNodeClose()
 	logger.Infof("node httpjson: listening on %v", httpNodeAddr)
 
-	nativeClusterClose, err := ttcluster.NewServer(client, tchannelClusterAddr, contextPool, nil).ListenAndServe()
+	nativeClusterClose, err := ttcluster.NewServer(client, tchannelClusterAddr, contextPool, nil, nil).ListenAndServe()
 	if err != nil {
 		return fmt.Errorf("could not open tchannelthrift interface %s: %v", tchannelClusterAddr, err)
 	}
@@ -45,3 +45,9 @@ const (
 	// configuration specifying the client write consistency level
 	ClientWriteConsistencyLevel = "m3db.client.write-consistency-level"
 )
+
+// EffectiveValueKey returns the KV config key under which a node reports the
+// value it has applied for the given runtime configuration key.
+func EffectiveValueKey(key, hostID string) string {
+	return key + ".effective." + hostID
+}
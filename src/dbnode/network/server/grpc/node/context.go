@@ -0,0 +1,70 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"github.com/m3db/m3x/context"
+
+	xnetcontext "golang.org/x/net/context"
+	"google.golang.org/grpc/stats"
+)
+
+const (
+	// contextKey is the key of the M3DB context read by the node
+	// service, see tchannelthrift.Context.
+	contextKey = "m3dbcontext"
+)
+
+// contextHandler is a gRPC stats handler that serves every RPC with an
+// M3DB context, closed once the RPC ends since the responses reference
+// data pooled by the context until they are sent.
+type contextHandler struct {
+	contextPool context.Pool
+}
+
+func newContextHandler(contextPool context.Pool) stats.Handler {
+	return &contextHandler{contextPool: contextPool}
+}
+
+func (h *contextHandler) TagRPC(
+	ctx xnetcontext.Context,
+	info *stats.RPCTagInfo,
+) xnetcontext.Context {
+	return xnetcontext.WithValue(ctx, interface{}(contextKey), h.contextPool.Get())
+}
+
+func (h *contextHandler) HandleRPC(ctx xnetcontext.Context, s stats.RPCStats) {
+	if _, ok := s.(*stats.End); !ok {
+		return
+	}
+	if inner, ok := ctx.Value(contextKey).(context.Context); ok {
+		inner.Close()
+	}
+}
+
+func (h *contextHandler) TagConn(
+	ctx xnetcontext.Context,
+	info *stats.ConnTagInfo,
+) xnetcontext.Context {
+	return ctx
+}
+
+func (h *contextHandler) HandleConn(ctx xnetcontext.Context, s stats.ConnStats) {}
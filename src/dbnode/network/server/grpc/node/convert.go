@@ -0,0 +1,188 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"errors"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/rpcpb"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+)
+
+var (
+	errNoQuery = errors.New("query is not set")
+)
+
+func fromProtoQuery(q *rpcpb.Query) (*rpc.Query, error) {
+	if q == nil {
+		return nil, errNoQuery
+	}
+	switch v := q.Query.(type) {
+	case *rpcpb.Query_Term:
+		return &rpc.Query{Term: &rpc.TermQuery{
+			Field: v.Term.Field,
+			Term:  v.Term.Term,
+		}}, nil
+	case *rpcpb.Query_Regexp:
+		return &rpc.Query{Regexp: &rpc.RegexpQuery{
+			Field:  v.Regexp.Field,
+			Regexp: v.Regexp.Regexp,
+		}}, nil
+	case *rpcpb.Query_Negation:
+		inner, err := fromProtoQuery(v.Negation.Query)
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.Query{Negation: &rpc.NegationQuery{Query: inner}}, nil
+	case *rpcpb.Query_Conjunction:
+		queries, err := fromProtoQueries(v.Conjunction.Queries)
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.Query{Conjunction: &rpc.ConjunctionQuery{Queries: queries}}, nil
+	case *rpcpb.Query_Disjunction:
+		queries, err := fromProtoQueries(v.Disjunction.Queries)
+		if err != nil {
+			return nil, err
+		}
+		return &rpc.Query{Disjunction: &rpc.DisjunctionQuery{Queries: queries}}, nil
+	default:
+		return nil, errNoQuery
+	}
+}
+
+func fromProtoQueries(queries []*rpcpb.Query) ([]*rpc.Query, error) {
+	result := make([]*rpc.Query, 0, len(queries))
+	for _, q := range queries {
+		converted, err := fromProtoQuery(q)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, converted)
+	}
+	return result, nil
+}
+
+func fromProtoDatapoint(dp *rpcpb.Datapoint) *rpc.Datapoint {
+	return &rpc.Datapoint{
+		Timestamp:         dp.Timestamp,
+		Value:             dp.Value,
+		Annotation:        dp.Annotation,
+		TimestampTimeType: rpc.TimeType(dp.TimestampTimeType),
+	}
+}
+
+func toProtoDatapoint(dp *rpc.Datapoint) *rpcpb.Datapoint {
+	return &rpcpb.Datapoint{
+		Timestamp:         dp.Timestamp,
+		Value:             dp.Value,
+		Annotation:        dp.Annotation,
+		TimestampTimeType: rpcpb.TimeType(dp.TimestampTimeType),
+	}
+}
+
+func toProtoError(err *rpc.Error) *rpcpb.Error {
+	if err == nil {
+		return nil
+	}
+	return &rpcpb.Error{
+		Type:    rpcpb.ErrorType(err.Type),
+		Message: err.Message,
+	}
+}
+
+func toProtoQueryResponse(result *rpc.QueryResult_) *rpcpb.QueryResponse {
+	resp := &rpcpb.QueryResponse{
+		Results:    make([]*rpcpb.QueryResultElement, 0, len(result.Results)),
+		Exhaustive: result.Exhaustive,
+	}
+	for _, elem := range result.Results {
+		protoElem := &rpcpb.QueryResultElement{
+			Id:         elem.ID,
+			Tags:       make([]*rpcpb.Tag, 0, len(elem.Tags)),
+			Datapoints: make([]*rpcpb.Datapoint, 0, len(elem.Datapoints)),
+		}
+		for _, tag := range elem.Tags {
+			protoElem.Tags = append(protoElem.Tags, &rpcpb.Tag{
+				Name:  tag.Name,
+				Value: tag.Value,
+			})
+		}
+		for _, dp := range elem.Datapoints {
+			protoElem.Datapoints = append(protoElem.Datapoints, toProtoDatapoint(dp))
+		}
+		resp.Results = append(resp.Results, protoElem)
+	}
+	return resp
+}
+
+func toProtoFetchTaggedResponse(result *rpc.FetchTaggedResult_) *rpcpb.FetchTaggedResponse {
+	resp := &rpcpb.FetchTaggedResponse{
+		Elements:   make([]*rpcpb.FetchTaggedIDResult, 0, len(result.Elements)),
+		Exhaustive: result.Exhaustive,
+	}
+	for _, elem := range result.Elements {
+		protoElem := &rpcpb.FetchTaggedIDResult{
+			Id:          elem.ID,
+			NameSpace:   elem.NameSpace,
+			EncodedTags: elem.EncodedTags,
+			Err:         toProtoError(elem.Err),
+		}
+		if len(elem.Segments) > 0 {
+			protoElem.Segments = make([]*rpcpb.Segments, 0, len(elem.Segments))
+			for _, segments := range elem.Segments {
+				protoElem.Segments = append(protoElem.Segments, toProtoSegments(segments))
+			}
+		}
+		resp.Elements = append(resp.Elements, protoElem)
+	}
+	return resp
+}
+
+// toProtoSegments converts the compressed segments as is, without copying
+// the segment payloads.
+func toProtoSegments(segments *rpc.Segments) *rpcpb.Segments {
+	result := &rpcpb.Segments{}
+	if segments.Merged != nil {
+		result.Merged = toProtoSegment(segments.Merged)
+	}
+	if len(segments.Unmerged) > 0 {
+		result.Unmerged = make([]*rpcpb.Segment, 0, len(segments.Unmerged))
+		for _, segment := range segments.Unmerged {
+			result.Unmerged = append(result.Unmerged, toProtoSegment(segment))
+		}
+	}
+	return result
+}
+
+func toProtoSegment(segment *rpc.Segment) *rpcpb.Segment {
+	result := &rpcpb.Segment{
+		Head: segment.Head,
+		Tail: segment.Tail,
+	}
+	if segment.StartTime != nil {
+		result.StartTime = *segment.StartTime
+	}
+	if segment.BlockSize != nil {
+		result.BlockSize = *segment.BlockSize
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"crypto/tls"
+)
+
+const (
+	defaultMaxConcurrentStreams = 16384
+	defaultMaxRecvMsgSize       = 64 * 1024 * 1024
+)
+
+// ServerOptions is a set of server options
+type ServerOptions interface {
+	// SetMaxConcurrentStreams sets the max concurrent streams per connection
+	// and returns a new ServerOptions
+	SetMaxConcurrentStreams(value uint32) ServerOptions
+
+	// MaxConcurrentStreams returns the max concurrent streams per connection
+	MaxConcurrentStreams() uint32
+
+	// SetMaxRecvMsgSize sets the max size in bytes of received messages
+	// and returns a new ServerOptions
+	SetMaxRecvMsgSize(value int) ServerOptions
+
+	// MaxRecvMsgSize returns the max size in bytes of received messages
+	MaxRecvMsgSize() int
+
+	// SetTLSConfig sets the TLS config, if set the server serves TLS
+	// connections and returns a new ServerOptions
+	SetTLSConfig(value *tls.Config) ServerOptions
+
+	// TLSConfig returns the TLS config
+	TLSConfig() *tls.Config
+}
+
+type serverOptions struct {
+	maxConcurrentStreams uint32
+	maxRecvMsgSize       int
+	tlsConfig            *tls.Config
+}
+
+// NewServerOptions creates a new set of server options with defaults
+func NewServerOptions() ServerOptions {
+	return &serverOptions{
+		maxConcurrentStreams: defaultMaxConcurrentStreams,
+		maxRecvMsgSize:       defaultMaxRecvMsgSize,
+	}
+}
+
+func (o *serverOptions) SetMaxConcurrentStreams(value uint32) ServerOptions {
+	opts := *o
+	opts.maxConcurrentStreams = value
+	return &opts
+}
+
+func (o *serverOptions) MaxConcurrentStreams() uint32 {
+	return o.maxConcurrentStreams
+}
+
+func (o *serverOptions) SetMaxRecvMsgSize(value int) ServerOptions {
+	opts := *o
+	opts.maxRecvMsgSize = value
+	return &opts
+}
+
+func (o *serverOptions) MaxRecvMsgSize() int {
+	return o.maxRecvMsgSize
+}
+
+func (o *serverOptions) SetTLSConfig(value *tls.Config) ServerOptions {
+	opts := *o
+	opts.tlsConfig = value
+	return &opts
+}
+
+func (o *serverOptions) TLSConfig() *tls.Config {
+	return o.tlsConfig
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"crypto/tls"
+	"net"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/rpcpb"
+	ns "github.com/m3db/m3/src/dbnode/network/server"
+	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
+	ttnode "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node"
+	"github.com/m3db/m3/src/dbnode/storage"
+	"github.com/m3db/m3x/context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	http2Proto = "h2"
+)
+
+type server struct {
+	db          storage.Database
+	address     string
+	contextPool context.Pool
+	opts        ServerOptions
+	ttopts      tchannelthrift.Options
+}
+
+// NewServer creates a new node gRPC network service, backed by the same
+// service implementation as the node TChannel Thrift network service
+func NewServer(
+	db storage.Database,
+	address string,
+	contextPool context.Pool,
+	opts ServerOptions,
+	ttopts tchannelthrift.Options,
+) ns.NetworkService {
+	if opts == nil {
+		opts = NewServerOptions()
+	}
+	if ttopts == nil {
+		ttopts = tchannelthrift.NewOptions()
+	}
+	return &server{
+		db:          db,
+		address:     address,
+		contextPool: contextPool,
+		opts:        opts,
+		ttopts:      ttopts,
+	}
+}
+
+func (s *server) ListenAndServe() (ns.Close, error) {
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return nil, err
+	}
+
+	serverOpts := []grpc.ServerOption{
+		grpc.StatsHandler(newContextHandler(s.contextPool)),
+		grpc.MaxConcurrentStreams(s.opts.MaxConcurrentStreams()),
+		grpc.MaxRecvMsgSize(s.opts.MaxRecvMsgSize()),
+	}
+	if tlsConfig := s.opts.TLSConfig(); tlsConfig != nil {
+		serverOpts = append(serverOpts,
+			grpc.Creds(credentials.NewTLS(withHTTP2Proto(tlsConfig))))
+	}
+
+	server := grpc.NewServer(serverOpts...)
+	rpcpb.RegisterNodeServer(server, NewService(ttnode.NewService(s.db, s.ttopts)))
+
+	go func() {
+		server.Serve(listener)
+	}()
+
+	return func() {
+		server.Stop()
+	}, nil
+}
+
+// withHTTP2Proto returns the TLS config with the configs it returns per
+// client negotiating HTTP/2, as gRPC clients require.
+func withHTTP2Proto(tlsConfig *tls.Config) *tls.Config {
+	tlsConfig = tlsConfig.Clone()
+	tlsConfig.NextProtos = []string{http2Proto}
+	if getConfigForClient := tlsConfig.GetConfigForClient; getConfigForClient != nil {
+		tlsConfig.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			clientConfig, err := getConfigForClient(hello)
+			if err != nil || clientConfig == nil {
+				return clientConfig, err
+			}
+			clientConfig = clientConfig.Clone()
+			clientConfig.NextProtos = []string{http2Proto}
+			return clientConfig, nil
+		}
+	}
+	return tlsConfig
+}
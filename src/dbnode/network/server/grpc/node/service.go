@@ -0,0 +1,193 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"github.com/m3db/m3/src/dbnode/generated/proto/rpcpb"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+
+	"github.com/uber/tchannel-go/thrift"
+	xnetcontext "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+type service struct {
+	node rpc.TChanNode
+}
+
+// NewService creates a new node gRPC service that serves requests with the
+// node TChannel Thrift service, requests must be served with an M3DB context
+func NewService(node rpc.TChanNode) rpcpb.NodeServer {
+	return &service{node: node}
+}
+
+func (s *service) Health(
+	ctx xnetcontext.Context,
+	req *rpcpb.HealthRequest,
+) (*rpcpb.HealthResponse, error) {
+	result, err := s.node.Health(thriftContext(ctx))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return &rpcpb.HealthResponse{
+		Ok:           result.Ok,
+		Status:       result.Status,
+		Bootstrapped: result.Bootstrapped,
+	}, nil
+}
+
+func (s *service) Query(
+	ctx xnetcontext.Context,
+	req *rpcpb.QueryRequest,
+) (*rpcpb.QueryResponse, error) {
+	query, err := fromProtoQuery(req.Query)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+	thriftReq := &rpc.QueryRequest{
+		Query:          query,
+		RangeStart:     req.RangeStart,
+		RangeEnd:       req.RangeEnd,
+		NameSpace:      req.NameSpace,
+		RangeType:      rpc.TimeType(req.RangeType),
+		ResultTimeType: rpc.TimeType(req.ResultTimeType),
+	}
+	if req.Limit > 0 {
+		limit := req.Limit
+		thriftReq.Limit = &limit
+	}
+	if req.NoData {
+		noData := true
+		thriftReq.NoData = &noData
+	}
+
+	result, err := s.node.Query(thriftContext(ctx), thriftReq)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return toProtoQueryResponse(result), nil
+}
+
+func (s *service) FetchTagged(
+	ctx xnetcontext.Context,
+	req *rpcpb.FetchTaggedRequest,
+) (*rpcpb.FetchTaggedResponse, error) {
+	thriftReq := &rpc.FetchTaggedRequest{
+		NameSpace:     req.NameSpace,
+		Query:         req.Query,
+		RangeStart:    req.RangeStart,
+		RangeEnd:      req.RangeEnd,
+		FetchData:     req.FetchData,
+		RangeTimeType: rpc.TimeType(req.RangeTimeType),
+	}
+	if req.Limit > 0 {
+		limit := req.Limit
+		thriftReq.Limit = &limit
+	}
+
+	result, err := s.node.FetchTagged(thriftContext(ctx), thriftReq)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return toProtoFetchTaggedResponse(result), nil
+}
+
+func (s *service) WriteBatch(
+	ctx xnetcontext.Context,
+	req *rpcpb.WriteBatchRequest,
+) (*rpcpb.WriteBatchResponse, error) {
+	thriftReq := &rpc.WriteBatchRawRequest{
+		NameSpace: req.NameSpace,
+		Elements:  make([]*rpc.WriteBatchRawRequestElement, 0, len(req.Elements)),
+	}
+	for i, elem := range req.Elements {
+		if elem.Datapoint == nil {
+			return nil, grpc.Errorf(codes.InvalidArgument,
+				"element %d has no datapoint", i)
+		}
+		thriftReq.Elements = append(thriftReq.Elements, &rpc.WriteBatchRawRequestElement{
+			ID:        elem.Id,
+			Datapoint: fromProtoDatapoint(elem.Datapoint),
+		})
+	}
+
+	return toProtoWriteBatchResponse(s.node.WriteBatchRaw(thriftContext(ctx), thriftReq))
+}
+
+func (s *service) WriteTaggedBatch(
+	ctx xnetcontext.Context,
+	req *rpcpb.WriteTaggedBatchRequest,
+) (*rpcpb.WriteBatchResponse, error) {
+	thriftReq := &rpc.WriteTaggedBatchRawRequest{
+		NameSpace: req.NameSpace,
+		Elements:  make([]*rpc.WriteTaggedBatchRawRequestElement, 0, len(req.Elements)),
+	}
+	for i, elem := range req.Elements {
+		if elem.Datapoint == nil {
+			return nil, grpc.Errorf(codes.InvalidArgument,
+				"element %d has no datapoint", i)
+		}
+		thriftReq.Elements = append(thriftReq.Elements, &rpc.WriteTaggedBatchRawRequestElement{
+			ID:          elem.Id,
+			EncodedTags: elem.EncodedTags,
+			Datapoint:   fromProtoDatapoint(elem.Datapoint),
+		})
+	}
+
+	return toProtoWriteBatchResponse(s.node.WriteTaggedBatchRaw(thriftContext(ctx), thriftReq))
+}
+
+func thriftContext(ctx xnetcontext.Context) thrift.Context {
+	return thrift.WithHeaders(ctx, nil)
+}
+
+func toProtoWriteBatchResponse(err error) (*rpcpb.WriteBatchResponse, error) {
+	if err == nil {
+		return &rpcpb.WriteBatchResponse{}, nil
+	}
+	batchErrs, ok := err.(*rpc.WriteBatchRawErrors)
+	if !ok {
+		return nil, toGRPCError(err)
+	}
+	resp := &rpcpb.WriteBatchResponse{
+		Errors: make([]*rpcpb.WriteBatchError, 0, len(batchErrs.Errors)),
+	}
+	for _, batchErr := range batchErrs.Errors {
+		resp.Errors = append(resp.Errors, &rpcpb.WriteBatchError{
+			Index: batchErr.Index,
+			Err:   toProtoError(batchErr.Err),
+		})
+	}
+	return resp, nil
+}
+
+func toGRPCError(err error) error {
+	rpcErr, ok := err.(*rpc.Error)
+	if !ok {
+		return grpc.Errorf(codes.Unknown, err.Error())
+	}
+	if tterrors.IsBadRequestError(rpcErr) {
+		return grpc.Errorf(codes.InvalidArgument, rpcErr.Message)
+	}
+	return grpc.Errorf(codes.Internal, rpcErr.Message)
+}
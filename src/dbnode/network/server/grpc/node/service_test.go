@@ -0,0 +1,294 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/m3db/m3/src/dbnode/generated/proto/rpcpb"
+	"github.com/m3db/m3/src/dbnode/generated/thrift/rpc"
+	tterrors "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/errors"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	xnetcontext "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestServiceHealth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	node := rpc.NewMockTChanNode(ctrl)
+	node.EXPECT().Health(gomock.Any()).Return(&rpc.NodeHealthResult_{
+		Ok:           true,
+		Status:       "up",
+		Bootstrapped: true,
+	}, nil)
+
+	resp, err := NewService(node).Health(xnetcontext.Background(), &rpcpb.HealthRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, &rpcpb.HealthResponse{Ok: true, Status: "up", Bootstrapped: true}, resp)
+}
+
+func TestServiceQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	node := rpc.NewMockTChanNode(ctrl)
+	node.EXPECT().Query(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ interface{}, req *rpc.QueryRequest) (*rpc.QueryResult_, error) {
+			require.NotNil(t, req.Query.Conjunction)
+			require.Equal(t, 2, len(req.Query.Conjunction.Queries))
+			assert.Equal(t, &rpc.TermQuery{Field: "city", Term: "nyc"},
+				req.Query.Conjunction.Queries[0].Term)
+			assert.Equal(t, &rpc.RegexpQuery{Field: "host", Regexp: "a.*"},
+				req.Query.Conjunction.Queries[1].Negation.Query.Regexp)
+			assert.Equal(t, "metrics", req.NameSpace)
+			require.NotNil(t, req.Limit)
+			assert.Equal(t, int64(10), *req.Limit)
+			assert.Nil(t, req.NoData)
+			assert.Equal(t, rpc.TimeType_UNIX_MILLISECONDS, req.ResultTimeType)
+			return &rpc.QueryResult_{
+				Results: []*rpc.QueryResultElement{
+					{
+						ID:   "foo",
+						Tags: []*rpc.Tag{{Name: "city", Value: "nyc"}},
+						Datapoints: []*rpc.Datapoint{
+							{Timestamp: 1000, Value: 42},
+						},
+					},
+				},
+				Exhaustive: true,
+			}, nil
+		})
+
+	resp, err := NewService(node).Query(xnetcontext.Background(), &rpcpb.QueryRequest{
+		Query: &rpcpb.Query{Query: &rpcpb.Query_Conjunction{
+			Conjunction: &rpcpb.ConjunctionQuery{Queries: []*rpcpb.Query{
+				{Query: &rpcpb.Query_Term{Term: &rpcpb.TermQuery{Field: "city", Term: "nyc"}}},
+				{Query: &rpcpb.Query_Negation{Negation: &rpcpb.NegationQuery{
+					Query: &rpcpb.Query{Query: &rpcpb.Query_Regexp{
+						Regexp: &rpcpb.RegexpQuery{Field: "host", Regexp: "a.*"},
+					}},
+				}}},
+			}},
+		}},
+		NameSpace:      "metrics",
+		Limit:          10,
+		ResultTimeType: rpcpb.TimeType_UNIX_MILLISECONDS,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &rpcpb.QueryResponse{
+		Results: []*rpcpb.QueryResultElement{
+			{
+				Id:         "foo",
+				Tags:       []*rpcpb.Tag{{Name: "city", Value: "nyc"}},
+				Datapoints: []*rpcpb.Datapoint{{Timestamp: 1000, Value: 42}},
+			},
+		},
+		Exhaustive: true,
+	}, resp)
+}
+
+func TestServiceQueryRequiresQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, err := NewService(rpc.NewMockTChanNode(ctrl)).Query(
+		xnetcontext.Background(), &rpcpb.QueryRequest{})
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+}
+
+func TestServiceFetchTagged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		startTime = int64(1000)
+		blockSize = int64(7200)
+	)
+	node := rpc.NewMockTChanNode(ctrl)
+	node.EXPECT().FetchTagged(gomock.Any(), &rpc.FetchTaggedRequest{
+		NameSpace:  []byte("metrics"),
+		Query:      []byte("query"),
+		RangeStart: 1,
+		RangeEnd:   2,
+		FetchData:  true,
+	}).Return(&rpc.FetchTaggedResult_{
+		Elements: []*rpc.FetchTaggedIDResult_{
+			{
+				ID:          []byte("foo"),
+				NameSpace:   []byte("metrics"),
+				EncodedTags: []byte("tags"),
+				Segments: []*rpc.Segments{
+					{Merged: &rpc.Segment{Head: []byte("head"), Tail: []byte("tail")}},
+					{Unmerged: []*rpc.Segment{{
+						Head:      []byte("head"),
+						Tail:      []byte("tail"),
+						StartTime: &startTime,
+						BlockSize: &blockSize,
+					}}},
+				},
+			},
+			{
+				ID:  []byte("bar"),
+				Err: tterrors.NewInternalError(errors.New("boom")),
+			},
+		},
+		Exhaustive: true,
+	}, nil)
+
+	resp, err := NewService(node).FetchTagged(xnetcontext.Background(), &rpcpb.FetchTaggedRequest{
+		NameSpace:  []byte("metrics"),
+		Query:      []byte("query"),
+		RangeStart: 1,
+		RangeEnd:   2,
+		FetchData:  true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &rpcpb.FetchTaggedResponse{
+		Elements: []*rpcpb.FetchTaggedIDResult{
+			{
+				Id:          []byte("foo"),
+				NameSpace:   []byte("metrics"),
+				EncodedTags: []byte("tags"),
+				Segments: []*rpcpb.Segments{
+					{Merged: &rpcpb.Segment{Head: []byte("head"), Tail: []byte("tail")}},
+					{Unmerged: []*rpcpb.Segment{{
+						Head:      []byte("head"),
+						Tail:      []byte("tail"),
+						StartTime: startTime,
+						BlockSize: blockSize,
+					}}},
+				},
+			},
+			{
+				Id: []byte("bar"),
+				Err: &rpcpb.Error{
+					Type:    rpcpb.ErrorType_INTERNAL_ERROR,
+					Message: "boom",
+				},
+			},
+		},
+		Exhaustive: true,
+	}, resp)
+}
+
+func TestServiceFetchTaggedError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	node := rpc.NewMockTChanNode(ctrl)
+	node.EXPECT().FetchTagged(gomock.Any(), gomock.Any()).
+		Return(nil, tterrors.NewBadRequestError(errors.New("bad query")))
+
+	_, err := NewService(node).FetchTagged(xnetcontext.Background(), &rpcpb.FetchTaggedRequest{})
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+	assert.Equal(t, "bad query", grpc.ErrorDesc(err))
+}
+
+func TestServiceWriteTaggedBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	node := rpc.NewMockTChanNode(ctrl)
+	node.EXPECT().WriteTaggedBatchRaw(gomock.Any(), &rpc.WriteTaggedBatchRawRequest{
+		NameSpace: []byte("metrics"),
+		Elements: []*rpc.WriteTaggedBatchRawRequestElement{
+			{
+				ID:          []byte("foo"),
+				EncodedTags: []byte("tags"),
+				Datapoint: &rpc.Datapoint{
+					Timestamp:         1000,
+					Value:             42,
+					TimestampTimeType: rpc.TimeType_UNIX_MILLISECONDS,
+				},
+			},
+			{
+				ID:          []byte("bar"),
+				EncodedTags: []byte("tags"),
+				Datapoint:   &rpc.Datapoint{Timestamp: 1, Value: 1},
+			},
+		},
+	}).Return(&rpc.WriteBatchRawErrors{Errors: []*rpc.WriteBatchRawError{
+		tterrors.NewBadRequestWriteBatchRawError(1, errors.New("too old")),
+	}})
+
+	resp, err := NewService(node).WriteTaggedBatch(xnetcontext.Background(), &rpcpb.WriteTaggedBatchRequest{
+		NameSpace: []byte("metrics"),
+		Elements: []*rpcpb.WriteTaggedBatchRequestElement{
+			{
+				Id:          []byte("foo"),
+				EncodedTags: []byte("tags"),
+				Datapoint: &rpcpb.Datapoint{
+					Timestamp:         1000,
+					Value:             42,
+					TimestampTimeType: rpcpb.TimeType_UNIX_MILLISECONDS,
+				},
+			},
+			{
+				Id:          []byte("bar"),
+				EncodedTags: []byte("tags"),
+				Datapoint:   &rpcpb.Datapoint{Timestamp: 1, Value: 1},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &rpcpb.WriteBatchResponse{
+		Errors: []*rpcpb.WriteBatchError{
+			{
+				Index: 1,
+				Err: &rpcpb.Error{
+					Type:    rpcpb.ErrorType_BAD_REQUEST,
+					Message: "too old",
+				},
+			},
+		},
+	}, resp)
+}
+
+func TestServiceWriteBatchError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	node := rpc.NewMockTChanNode(ctrl)
+	node.EXPECT().WriteBatchRaw(gomock.Any(), gomock.Any()).
+		Return(tterrors.NewInternalError(errors.New("overloaded")))
+
+	service := NewService(node)
+	_, err := service.WriteBatch(xnetcontext.Background(), &rpcpb.WriteBatchRequest{
+		NameSpace: []byte("metrics"),
+		Elements: []*rpcpb.WriteBatchRequestElement{
+			{Id: []byte("foo"), Datapoint: &rpcpb.Datapoint{Timestamp: 1, Value: 1}},
+		},
+	})
+	assert.Equal(t, codes.Internal, grpc.Code(err))
+
+	_, err = service.WriteBatch(xnetcontext.Background(), &rpcpb.WriteBatchRequest{
+		NameSpace: []byte("metrics"),
+		Elements:  []*rpcpb.WriteBatchRequestElement{{Id: []byte("foo")}},
+	})
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+}
@@ -28,6 +28,7 @@ import (
 	ns "github.com/m3db/m3/src/dbnode/network/server"
 	"github.com/m3db/m3/src/dbnode/network/server/httpjson"
 	ttcluster "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/cluster"
+	xtls "github.com/m3db/m3/src/x/tls"
 	xclose "github.com/m3db/m3x/close"
 	"github.com/m3db/m3x/context"
 )
@@ -70,6 +71,9 @@ func (s *server) ListenAndServe() (ns.Close, error) {
 	if err != nil {
 		return nil, err
 	}
+	if tlsConfig := s.opts.TLSConfig(); tlsConfig != nil {
+		listener = xtls.NewListener(listener, tlsConfig)
+	}
 
 	server := http.Server{
 		Handler:      mux,
@@ -29,6 +29,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
 	ttnode "github.com/m3db/m3/src/dbnode/network/server/tchannelthrift/node"
 	"github.com/m3db/m3/src/dbnode/storage"
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/context"
 )
 
@@ -74,6 +75,9 @@ func (s *server) ListenAndServe() (ns.Close, error) {
 	if err != nil {
 		return nil, err
 	}
+	if tlsConfig := s.opts.TLSConfig(); tlsConfig != nil {
+		listener = xtls.NewListener(listener, tlsConfig)
+	}
 
 	server := http.Server{
 		Handler:      mux,
@@ -21,6 +21,7 @@
 package httpjson
 
 import (
+	"crypto/tls"
 	"time"
 
 	apachethrift "github.com/apache/thrift/lib/go/thrift"
@@ -72,6 +73,13 @@ type ServerOptions interface {
 
 	// PostResponseFn returns the post response fn
 	PostResponseFn() PostResponseFn
+
+	// SetTLSConfig sets the TLS config, if set the server serves TLS
+	// connections and returns a new ServerOptions
+	SetTLSConfig(value *tls.Config) ServerOptions
+
+	// TLSConfig returns the TLS config
+	TLSConfig() *tls.Config
 }
 
 type serverOptions struct {
@@ -80,6 +88,7 @@ type serverOptions struct {
 	requestTimeout time.Duration
 	contextFn      ContextFn
 	postResponseFn PostResponseFn
+	tlsConfig      *tls.Config
 }
 
 // NewServerOptions creates a new set of server options with defaults
@@ -140,3 +149,13 @@ func (o *serverOptions) SetPostResponseFn(value PostResponseFn) ServerOptions {
 func (o *serverOptions) PostResponseFn() PostResponseFn {
 	return o.postResponseFn
 }
+
+func (o *serverOptions) SetTLSConfig(value *tls.Config) ServerOptions {
+	opts := *o
+	opts.tlsConfig = value
+	return &opts
+}
+
+func (o *serverOptions) TLSConfig() *tls.Config {
+	return o.tlsConfig
+}
@@ -41,6 +41,7 @@ type server struct {
 	address     string
 	contextPool context.Pool
 	opts        *tchannel.ChannelOptions
+	ttopts      tchannelthrift.Options
 }
 
 // NewServer creates a new cluster TChannel Thrift network service
@@ -49,17 +50,22 @@ func NewServer(
 	address string,
 	contextPool context.Pool,
 	opts *tchannel.ChannelOptions,
+	ttopts tchannelthrift.Options,
 ) ns.NetworkService {
 	// Make the opts immutable on the way in
 	if opts != nil {
 		immutableOpts := *opts
 		opts = &immutableOpts
 	}
+	if ttopts == nil {
+		ttopts = tchannelthrift.NewOptions()
+	}
 	return &server{
 		address:     address,
 		client:      client,
 		contextPool: contextPool,
 		opts:        opts,
+		ttopts:      ttopts,
 	}
 }
 
@@ -72,7 +78,11 @@ func (s *server) ListenAndServe() (ns.Close, error) {
 	service := NewService(s.client)
 	tchannelthrift.RegisterServer(channel, rpc.NewTChanClusterServer(service), s.contextPool)
 
-	channel.ListenAndServe(s.address)
+	if err := tchannelthrift.ListenAndServe(channel, s.address, s.ttopts.TLSConfig()); err != nil {
+		channel.Close()
+		xclose.TryClose(service)
+		return nil, err
+	}
 
 	return func() {
 		channel.Close()
@@ -73,7 +73,10 @@ func (s *server) ListenAndServe() (ns.Close, error) {
 	service := NewService(s.db, s.ttopts)
 	tchannelthrift.RegisterServer(channel, rpc.NewTChanNodeServer(service), s.contextPool)
 
-	channel.ListenAndServe(s.address)
+	if err := tchannelthrift.ListenAndServe(channel, s.address, s.ttopts.TLSConfig()); err != nil {
+		channel.Close()
+		return nil, err
+	}
 
 	return channel.Close, nil
 }
@@ -21,6 +21,8 @@
 package tchannelthrift
 
 import (
+	"crypto/tls"
+
 	"github.com/m3db/m3/src/x/serialize"
 	"github.com/m3db/m3x/instrument"
 	"github.com/m3db/m3x/pool"
@@ -32,6 +34,7 @@ type options struct {
 	blockMetadataV2SlicePool BlockMetadataV2SlicePool
 	tagEncoderPool           serialize.TagEncoderPool
 	tagDecoderPool           serialize.TagDecoderPool
+	tlsConfig                *tls.Config
 }
 
 // NewOptions creates new options
@@ -104,3 +107,13 @@ func (o *options) SetTagDecoderPool(value serialize.TagDecoderPool) Options {
 func (o *options) TagDecoderPool() serialize.TagDecoderPool {
 	return o.tagDecoderPool
 }
+
+func (o *options) SetTLSConfig(value *tls.Config) Options {
+	opts := *o
+	opts.tlsConfig = value
+	return &opts
+}
+
+func (o *options) TLSConfig() *tls.Config {
+	return o.tlsConfig
+}
@@ -21,6 +21,8 @@
 package tchannelthrift
 
 import (
+	"crypto/tls"
+
 	"github.com/m3db/m3/src/x/serialize"
 	"github.com/m3db/m3x/instrument"
 )
@@ -56,4 +58,10 @@ type Options interface {
 
 	// TagDecoderPool returns the tag encoder pool
 	TagDecoderPool() serialize.TagDecoderPool
+
+	// SetTLSConfig sets the TLS config, if set the server serves TLS connections
+	SetTLSConfig(value *tls.Config) Options
+
+	// TLSConfig returns the TLS config
+	TLSConfig() *tls.Config
 }
@@ -22,6 +22,7 @@ package commitlog
 
 import (
 	"bufio"
+	"io"
 	"os"
 
 	"github.com/m3db/m3/src/dbnode/digest"
@@ -37,10 +38,13 @@ const (
 )
 
 type chunkReader struct {
-	fd        *os.File
-	buffer    *bufio.Reader
-	remaining int
-	charBuff  []byte
+	fd          *os.File
+	buffer      *bufio.Reader
+	remaining   int
+	charBuff    []byte
+	compression CompressionType
+	compressed  []byte
+	chunk       []byte
 }
 
 func newChunkReader(bufferLen int) *chunkReader {
@@ -54,6 +58,14 @@ func (r *chunkReader) reset(fd *os.File) {
 	r.fd = fd
 	r.buffer.Reset(fd)
 	r.remaining = 0
+	r.compression = CompressionNone
+	r.chunk = r.chunk[:0]
+}
+
+// setCompression sets the compression of the chunks that follow the chunk
+// currently being read, it must only be called at a chunk boundary.
+func (r *chunkReader) setCompression(compression CompressionType) {
+	r.compression = compression
 }
 
 func (r *chunkReader) readHeader() error {
@@ -80,6 +92,10 @@ func (r *chunkReader) readHeader() error {
 		return err
 	}
 
+	if r.compression != CompressionNone {
+		return r.readCompressedChunk(int(size), checksumData)
+	}
+
 	// Verify data checksum
 	data, err := r.buffer.Peek(int(size))
 	if err != nil {
@@ -96,6 +112,41 @@ func (r *chunkReader) readHeader() error {
 	return nil
 }
 
+func (r *chunkReader) readCompressedChunk(size int, checksumData uint32) error {
+	// NB: Compressed chunks can be larger than the read buffer so they are
+	// read in full rather than peeked.
+	if cap(r.compressed) < size {
+		r.compressed = make([]byte, size)
+	}
+	r.compressed = r.compressed[:size]
+	if _, err := io.ReadFull(r.buffer, r.compressed); err != nil {
+		return err
+	}
+
+	if digest.Checksum(r.compressed) != checksumData {
+		return errCommitLogReaderChunkSizeChecksumMismatch
+	}
+
+	chunk, err := decompressChunk(r.chunk, r.compressed)
+	if err != nil {
+		return err
+	}
+
+	// Set decompressed data to be consumed
+	r.chunk = chunk
+	r.remaining = len(chunk)
+
+	return nil
+}
+
+// readRemaining reads from the data remaining in the current chunk.
+func (r *chunkReader) readRemaining(p []byte) (int, error) {
+	if r.compression == CompressionNone {
+		return r.buffer.Read(p)
+	}
+	return copy(p, r.chunk[len(r.chunk)-r.remaining:]), nil
+}
+
 func (r *chunkReader) Read(p []byte) (int, error) {
 	size := len(p)
 	read := 0
@@ -103,7 +154,7 @@ func (r *chunkReader) Read(p []byte) (int, error) {
 	if r.remaining < size {
 		// Copy any remaining
 		if r.remaining > 0 {
-			n, err := r.buffer.Read(p[:r.remaining])
+			n, err := r.readRemaining(p[:r.remaining])
 			r.remaining -= n
 			read += n
 			if err != nil {
@@ -125,7 +176,7 @@ func (r *chunkReader) Read(p []byte) (int, error) {
 		return read, err
 	}
 
-	n, err := r.buffer.Read(p)
+	n, err := r.readRemaining(p)
 	r.remaining -= n
 	read += n
 	return read, err
@@ -369,6 +369,44 @@ func TestCommitLogWrite(t *testing.T) {
 	assertCommitLogWritesByIterating(t, commitLog, writes)
 }
 
+func TestCommitLogWriteCompressed(t *testing.T) {
+	opts, scope := newTestOptions(t, overrides{
+		strategy: StrategyWriteWait,
+	})
+	opts = opts.SetCompression(CompressionSnappy)
+	defer cleanup(t, opts)
+
+	commitLog := newTestCommitLog(t, opts)
+
+	var writes []testWrite
+	for i := 0; i < 100; i++ {
+		series := testSeries(uint64(i%10), fmt.Sprintf("foo.bar.%d", i%10),
+			ident.NewTags(ident.StringTag("name", fmt.Sprintf("val%d", i%10))), 127)
+		writes = append(writes, testWrite{series, time.Now(), float64(i), xtime.Second, []byte{1, 2, 3}, nil})
+	}
+
+	// Call write sync
+	writeCommitLogs(t, scope, commitLog, writes).Wait()
+
+	// Close the commit log and consequently flush
+	require.NoError(t, commitLog.Close())
+
+	// Assert the compression is recorded in the log info
+	files, corruptFiles, err := Files(opts)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(corruptFiles))
+	require.Equal(t, 1, len(files))
+
+	r := newCommitLogReader(opts, ReadAllSeriesPredicate()).(*reader)
+	_, _, _, err = r.Open(files[0].FilePath)
+	require.NoError(t, err)
+	require.Equal(t, CompressionSnappy, r.chunkReader.compression)
+	require.NoError(t, r.Close())
+
+	// Assert writes occurred by reading the commit log
+	assertCommitLogWritesByIterating(t, commitLog, writes)
+}
+
 func TestReadCommitLogMissingMetadata(t *testing.T) {
 	readConc := 4
 	// Make sure we're not leaking goroutines
@@ -0,0 +1,149 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package commitlog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/golang/snappy"
+)
+
+var (
+	errCompressionTypeUnspecified = errors.New("commit log compression type unspecified")
+)
+
+// CompressionType is the compression applied to commit log chunks.
+type CompressionType uint
+
+const (
+	// CompressionNone specifies that commit log chunks are written uncompressed.
+	CompressionNone CompressionType = iota
+	// CompressionSnappy specifies that commit log chunks are compressed
+	// using the snappy block format.
+	CompressionSnappy
+
+	// DefaultCompressionType is the default commit log compression type.
+	DefaultCompressionType = CompressionNone
+)
+
+// ValidCompressionTypes returns the valid commit log compression types.
+func ValidCompressionTypes() []CompressionType {
+	return []CompressionType{CompressionNone, CompressionSnappy}
+}
+
+func (t CompressionType) String() string {
+	switch t {
+	case CompressionNone:
+		return "none"
+	case CompressionSnappy:
+		return "snappy"
+	}
+	return "unknown"
+}
+
+// ValidateCompressionType validates a commit log compression type.
+func ValidateCompressionType(v CompressionType) error {
+	for _, valid := range ValidCompressionTypes() {
+		if valid == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid commit log CompressionType '%d' valid types are: %v",
+		uint(v), ValidCompressionTypes())
+}
+
+// ParseCompressionType parses a CompressionType from a string.
+func ParseCompressionType(str string) (CompressionType, error) {
+	var r CompressionType
+	if str == "" {
+		return r, errCompressionTypeUnspecified
+	}
+	for _, valid := range ValidCompressionTypes() {
+		if str == valid.String() {
+			r = valid
+			return r, nil
+		}
+	}
+	return r, fmt.Errorf("invalid commit log CompressionType '%s' valid types are: %v",
+		str, ValidCompressionTypes())
+}
+
+// UnmarshalYAML unmarshals a CompressionType into a valid type from string.
+func (t *CompressionType) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	r, err := ParseCompressionType(str)
+	if err != nil {
+		return err
+	}
+	*t = r
+	return nil
+}
+
+// MarshalYAML marshals a CompressionType into its string form.
+func (t CompressionType) MarshalYAML() (interface{}, error) {
+	return t.String(), nil
+}
+
+// chunkCompressor compresses each write it receives into a single chunk,
+// it sits between the writer's buffer and the chunk writer so that every
+// flushed buffer is written as one compressed chunk.
+type chunkCompressor struct {
+	next io.Writer
+	buff []byte
+}
+
+func newChunkCompressor(next io.Writer) *chunkCompressor {
+	return &chunkCompressor{next: next}
+}
+
+func (c *chunkCompressor) reset(next io.Writer) {
+	c.next = next
+}
+
+func (c *chunkCompressor) Write(p []byte) (int, error) {
+	if maxLen := snappy.MaxEncodedLen(len(p)); cap(c.buff) < maxLen {
+		c.buff = make([]byte, maxLen)
+	}
+	c.buff = snappy.Encode(c.buff[:cap(c.buff)], p)
+	if _, err := c.next.Write(c.buff); err != nil {
+		return 0, err
+	}
+	// Report the uncompressed length as consumed, the chunk written is
+	// usually shorter than p which would otherwise be a short write.
+	return len(p), nil
+}
+
+// decompressChunk decompresses a chunk written by a chunkCompressor into dst,
+// growing dst if it is not large enough to hold the decompressed chunk.
+func decompressChunk(dst, src []byte) ([]byte, error) {
+	n, err := snappy.DecodedLen(src)
+	if err != nil {
+		return nil, err
+	}
+	if cap(dst) < n {
+		dst = make([]byte, n)
+	}
+	return snappy.Decode(dst[:n], src)
+}
@@ -82,6 +82,8 @@ type options struct {
 	bytesPool               pool.CheckedBytesPool
 	identPool               ident.Pool
 	readConcurrency         int
+	compression             CompressionType
+	archiveDirectory        string
 }
 
 // NewOptions creates new commit log options
@@ -100,6 +102,7 @@ func NewOptions() Options {
 			return pool.NewBytesPool(s, nil)
 		}),
 		readConcurrency: defaultReadConcurrency,
+		compression:     DefaultCompressionType,
 	}
 	o.bytesPool.Init()
 	o.identPool = ident.NewPool(o.bytesPool, ident.PoolOptions{})
@@ -125,6 +128,10 @@ func (o *options) Validate() error {
 			MaximumQueueSizeQueueChannelSizeRatio, float64(o.BacklogQueueSize())/float64(o.BacklogQueueChannelSize()))
 	}
 
+	if err := ValidateCompressionType(o.Compression()); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -247,3 +254,23 @@ func (o *options) SetIdentifierPool(value ident.Pool) Options {
 func (o *options) IdentifierPool() ident.Pool {
 	return o.identPool
 }
+
+func (o *options) SetCompression(value CompressionType) Options {
+	opts := *o
+	opts.compression = value
+	return &opts
+}
+
+func (o *options) Compression() CompressionType {
+	return o.compression
+}
+
+func (o *options) SetArchiveDirectory(value string) Options {
+	opts := *o
+	opts.archiveDirectory = value
+	return &opts
+}
+
+func (o *options) ArchiveDirectory() string {
+	return o.archiveDirectory
+}
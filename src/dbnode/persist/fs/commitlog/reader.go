@@ -496,7 +496,18 @@ func (r *reader) readInfo() (schema.LogInfo, error) {
 	r.infoDecoderStream.Reset(data)
 	r.infoDecoder.Reset(r.infoDecoderStream)
 	logInfo, err := r.infoDecoder.DecodeLogInfo()
-	return logInfo, err
+	if err != nil {
+		return emptyLogInfo, err
+	}
+
+	// The chunks following the log info are compressed using the
+	// compression recorded in the log info.
+	compression := CompressionType(logInfo.Compression)
+	if err := ValidateCompressionType(compression); err != nil {
+		return emptyLogInfo, err
+	}
+	r.chunkReader.setCompression(compression)
+	return logInfo, nil
 }
 
 func (r *reader) Close() error {
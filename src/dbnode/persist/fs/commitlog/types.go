@@ -179,6 +179,23 @@ type Options interface {
 
 	// IdentifierPool returns the IdentifierPool to use for pooling identifiers.
 	IdentifierPool() ident.Pool
+
+	// SetCompression sets the compression applied to the chunks of newly
+	// written commit log files.
+	SetCompression(value CompressionType) Options
+
+	// Compression returns the compression applied to the chunks of newly
+	// written commit log files.
+	Compression() CompressionType
+
+	// SetArchiveDirectory sets the directory that commit log files no longer
+	// required for bootstrap are archived to rather than deleted, commit log
+	// files are deleted if it is empty.
+	SetArchiveDirectory(value string) Options
+
+	// ArchiveDirectory returns the directory that commit log files no longer
+	// required for bootstrap are archived to rather than deleted.
+	ArchiveDirectory() string
 }
 
 // FileFilterPredicate is a predicate that allows the caller to determine
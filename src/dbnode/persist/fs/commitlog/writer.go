@@ -104,6 +104,8 @@ type writer struct {
 	start               time.Time
 	duration            time.Duration
 	chunkWriter         chunkWriter
+	chunkCompressor     *chunkCompressor
+	compression         CompressionType
 	chunkReserveHeader  []byte
 	buffer              *bufio.Writer
 	sizeBuffer          []byte
@@ -127,6 +129,8 @@ func newCommitLogWriter(
 		newDirectoryMode:    opts.FilesystemOptions().NewDirectoryMode(),
 		nowFn:               opts.ClockOptions().NowFn(),
 		chunkWriter:         newChunkWriter(flushFn, shouldFsync),
+		chunkCompressor:     newChunkCompressor(nil),
+		compression:         opts.Compression(),
 		chunkReserveHeader:  make([]byte, chunkHeaderLen),
 		buffer:              bufio.NewWriterSize(nil, opts.FlushSize()),
 		sizeBuffer:          make([]byte, binary.MaxVarintLen64),
@@ -163,9 +167,10 @@ func (w *writer) Open(start time.Time, duration time.Duration) (File, error) {
 		return File{}, err
 	}
 	logInfo := schema.LogInfo{
-		Start:    start.UnixNano(),
-		Duration: int64(duration),
-		Index:    int64(index),
+		Start:       start.UnixNano(),
+		Duration:    int64(duration),
+		Index:       int64(index),
+		Compression: int64(w.compression),
 	}
 	w.logEncoder.Reset()
 	if err := w.logEncoder.EncodeLogInfo(logInfo); err != nil {
@@ -183,6 +188,17 @@ func (w *writer) Open(start time.Time, duration time.Duration) (File, error) {
 		return File{}, err
 	}
 
+	if w.compression != CompressionNone {
+		// The log info is always written as an uncompressed chunk so that
+		// readers can determine the compression of the chunks that follow.
+		if err := w.buffer.Flush(); err != nil {
+			w.Close()
+			return File{}, err
+		}
+		w.chunkCompressor.reset(w.chunkWriter)
+		w.buffer.Reset(w.chunkCompressor)
+	}
+
 	w.start = start
 	w.duration = duration
 	return File{
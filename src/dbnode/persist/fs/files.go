@@ -23,6 +23,7 @@ package fs
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -266,6 +267,91 @@ func DeleteFiles(filePaths []string) error {
 	return multiErr.FinalError()
 }
 
+// MoveFiles moves a set of files into a directory, creating the directory if it
+// does not exist, returning all the errors encountered during the move process.
+// Existing files in the directory are never overwritten, a file whose name is
+// already taken is moved with an index appended to its name before the file
+// suffix. Files that cannot be linked into the directory, i.e. if it resides on
+// a different device, are copied and then removed.
+func MoveFiles(filePaths []string, dirPath string, newDirectoryMode os.FileMode) error {
+	if err := os.MkdirAll(dirPath, newDirectoryMode); err != nil {
+		return err
+	}
+
+	multiErr := xerrors.NewMultiError()
+	for _, file := range filePaths {
+		target, err := moveFileToDir(file, dirPath)
+		if err != nil {
+			detailedErr := fmt.Errorf("failed to move file %s to %s: %v", file, target, err)
+			multiErr = multiErr.Add(detailedErr)
+		}
+	}
+	return multiErr.FinalError()
+}
+
+func moveFileToDir(file, dirPath string) (string, error) {
+	var (
+		fileName = filepath.Base(file)
+		ext      = filepath.Ext(fileName)
+		name     = strings.TrimSuffix(fileName, ext)
+		target   = filepath.Join(dirPath, fileName)
+	)
+	for i := 1; ; i++ {
+		err := moveFile(file, target)
+		if !os.IsExist(err) {
+			return target, err
+		}
+		target = filepath.Join(dirPath, fmt.Sprintf("%s%s%d%s", name, separator, i, ext))
+	}
+}
+
+// moveFile moves the file to the destination, it returns an error satisfying
+// os.IsExist if the destination already exists rather than overwriting it.
+func moveFile(src, dst string) error {
+	// NB: Unlike rename, linking fails rather than replacing the destination
+	// if it already exists.
+	err := os.Link(src, dst)
+	if err == nil {
+		return os.Remove(src)
+	}
+	if os.IsExist(err) {
+		return err
+	}
+	if err := copyFile(src, dst); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
 // DeleteDirectories delets a set of directories and its contents, returning all
 // of the errors encountered during the deletion process.
 func DeleteDirectories(dirPaths []string) error {
@@ -108,6 +108,73 @@ func TestDeleteFiles(t *testing.T) {
 	}
 }
 
+func TestMoveFiles(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var files []string
+	iter := 3
+
+	for i := 0; i < iter; i++ {
+		fd := createTempFile(t)
+		_, err := fd.Write([]byte{byte(i)})
+		require.NoError(t, err)
+		fd.Close()
+		files = append(files, fd.Name())
+	}
+
+	// Add a non-existent file path
+	files = append(files, "/not/a/real/path")
+
+	archiveDir := path.Join(dir, "archive")
+	require.Error(t, MoveFiles(files, archiveDir, defaultNewDirectoryMode))
+	for i := 0; i < iter; i++ {
+		require.True(t, !mustFileExists(t, files[i]))
+
+		moved := path.Join(archiveDir, path.Base(files[i]))
+		require.True(t, mustFileExists(t, moved))
+		data, err := ioutil.ReadFile(moved)
+		require.NoError(t, err)
+		require.Equal(t, []byte{byte(i)}, data)
+	}
+}
+
+func TestMoveFilesDoesNotOverwrite(t *testing.T) {
+	dir := createTempDir(t)
+	defer os.RemoveAll(dir)
+
+	var (
+		archiveDir = path.Join(dir, "archive")
+		fileName   = "commitlog-0-0.db"
+	)
+	for i := 0; i < 3; i++ {
+		srcDir := path.Join(dir, fmt.Sprintf("src%d", i))
+		require.NoError(t, os.MkdirAll(srcDir, defaultNewDirectoryMode))
+		src := path.Join(srcDir, fileName)
+		require.NoError(t, ioutil.WriteFile(src, []byte{byte(i)}, defaultNewFileMode))
+
+		require.NoError(t, MoveFiles([]string{src}, archiveDir, defaultNewDirectoryMode))
+		require.False(t, mustFileExists(t, src))
+	}
+
+	expected := []string{"commitlog-0-0.db", "commitlog-0-0-1.db", "commitlog-0-0-2.db"}
+	for i, name := range expected {
+		data, err := ioutil.ReadFile(path.Join(archiveDir, name))
+		require.NoError(t, err)
+		require.Equal(t, []byte{byte(i)}, data)
+	}
+
+	// Archived commit log files remain discoverable by their file name.
+	files, err := filepath.Glob(path.Join(archiveDir, commitLogFilePattern))
+	require.NoError(t, err)
+	require.Equal(t, 3, len(files))
+	for _, f := range files {
+		_, index, err := TimeAndIndexFromCommitlogFilename(f)
+		require.NoError(t, err)
+		require.Equal(t, 0, index)
+	}
+}
+
 func TestDeleteInactiveDirectories(t *testing.T) {
 	tempPrefix, err := ioutil.TempDir("", "filespath")
 	require.NoError(t, err)
@@ -363,7 +363,7 @@ func (dec *Decoder) decodeIndexSummary() (schema.IndexSummary, IndexSummaryToken
 }
 
 func (dec *Decoder) decodeLogInfo() schema.LogInfo {
-	numFieldsToSkip, _, ok := dec.checkNumFieldsFor(logInfoType, checkNumFieldsOptions{})
+	numFieldsToSkip, actual, ok := dec.checkNumFieldsFor(logInfoType, checkNumFieldsOptions{})
 	if !ok {
 		return emptyLogInfo
 	}
@@ -371,6 +371,12 @@ func (dec *Decoder) decodeLogInfo() schema.LogInfo {
 	logInfo.Start = dec.decodeVarint()
 	logInfo.Duration = dec.decodeVarint()
 	logInfo.Index = dec.decodeVarint()
+
+	// Commit logs written before compression was added only have 3 fields
+	// and are always uncompressed.
+	if actual >= 4 {
+		logInfo.Compression = dec.decodeVarint()
+	}
 	dec.skip(numFieldsToSkip)
 	if dec.err != nil {
 		return emptyLogInfo
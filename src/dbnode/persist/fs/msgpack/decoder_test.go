@@ -155,6 +155,30 @@ func TestDecodeLogInfoMoreFieldsThanExpected(t *testing.T) {
 	require.Equal(t, testLogInfo, res)
 }
 
+func TestDecodeLogInfoWithoutCompression(t *testing.T) {
+	var (
+		enc = NewEncoder()
+		dec = NewDecoder(nil)
+	)
+
+	// Encode the log info as it was before the compression field was added
+	enc.encodeNumObjectFieldsForFn = testGenEncodeNumObjectFieldsForFn(enc, logInfoType, -1)
+	enc.encodeRootObject(logInfoVersion, logInfoType)
+	enc.encodeNumObjectFieldsForFn(logInfoType)
+	enc.encodeVarintFn(testLogInfo.Start)
+	enc.encodeVarintFn(testLogInfo.Duration)
+	enc.encodeVarintFn(testLogInfo.Index)
+	require.NoError(t, enc.err)
+
+	// Verify the log info decodes as uncompressed
+	dec.Reset(NewDecoderStream(enc.Bytes()))
+	res, err := dec.DecodeLogInfo()
+	require.NoError(t, err)
+	expected := testLogInfo
+	expected.Compression = 0
+	require.Equal(t, expected, res)
+}
+
 func TestDecodeLogEntryMoreFieldsThanExpected(t *testing.T) {
 	var (
 		enc = NewEncoder()
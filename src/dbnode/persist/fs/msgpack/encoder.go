@@ -269,6 +269,7 @@ func (enc *Encoder) encodeLogInfo(info schema.LogInfo) {
 	enc.encodeVarintFn(info.Start)
 	enc.encodeVarintFn(info.Duration)
 	enc.encodeVarintFn(info.Index)
+	enc.encodeVarintFn(info.Compression)
 }
 
 func (enc *Encoder) encodeLogEntry(entry schema.LogEntry) {
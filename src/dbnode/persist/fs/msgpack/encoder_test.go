@@ -112,6 +112,7 @@ func testExpectedResultForLogInfo(t *testing.T, logInfo schema.LogInfo) []interf
 		logInfo.Start,
 		logInfo.Duration,
 		logInfo.Index,
+		logInfo.Compression,
 	}
 }
 
@@ -64,9 +64,10 @@ var (
 	}
 
 	testLogInfo = schema.LogInfo{
-		Start:    time.Now().UnixNano(),
-		Duration: int64(2 * time.Hour),
-		Index:    234,
+		Start:       time.Now().UnixNano(),
+		Duration:    int64(2 * time.Hour),
+		Index:       234,
+		Compression: 1,
 	}
 
 	testLogEntry = schema.LogEntry{
@@ -249,6 +249,9 @@ func (pm *persistManager) PrepareIndex(opts persist.IndexPrepareOptions) (persis
 		VolumeIndex:        volumeIndex,
 	}
 	blockSize := nsMetadata.Options().IndexOptions().BlockSize()
+	if opts.BlockSize > 0 {
+		blockSize = opts.BlockSize
+	}
 	idxWriterOpts := IndexWriterOpenOptions{
 		BlockSize:   blockSize,
 		FileSetType: opts.FileSetType,
@@ -75,6 +75,9 @@ type LogInfo struct {
 	Start    int64
 	Duration int64
 	Index    int64
+	// Compression is the compression applied to the chunks that follow the
+	// log info, zero denotes uncompressed chunks.
+	Compression int64
 }
 
 // LogEntry stores per-entry data in a commit log
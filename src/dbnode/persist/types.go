@@ -116,6 +116,9 @@ type IndexPrepareOptions struct {
 	BlockStart        time.Time
 	FileSetType       FileSetType
 	Shards            map[uint32]struct{}
+	// BlockSize overrides the namespace index block size, it is set when
+	// persisting index blocks rolled up to a coarser block size.
+	BlockSize time.Duration
 }
 
 // DataPrepareSnapshotOptions is the options struct for the Prepare method that contains
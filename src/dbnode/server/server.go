@@ -30,6 +30,8 @@ import (
 	"os/signal"
 	"runtime"
 	"runtime/debug"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -44,6 +46,8 @@ import (
 	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
 	"github.com/m3db/m3/src/dbnode/environment"
 	"github.com/m3db/m3/src/dbnode/kvconfig"
+	grpcnode "github.com/m3db/m3/src/dbnode/network/server/grpc/node"
+	"github.com/m3db/m3/src/dbnode/network/server/httpjson"
 	hjcluster "github.com/m3db/m3/src/dbnode/network/server/httpjson/cluster"
 	hjnode "github.com/m3db/m3/src/dbnode/network/server/httpjson/node"
 	"github.com/m3db/m3/src/dbnode/network/server/tchannelthrift"
@@ -344,6 +348,11 @@ func Run(runOpts RunOptions) {
 		commitLogQueueChannelSize = int(float64(commitLogQueueSize) / commitlog.MaximumQueueSizeQueueChannelSizeRatio)
 	}
 
+	commitLogCompression := commitlog.DefaultCompressionType
+	if cfg.CommitLog.Compression != nil {
+		commitLogCompression = *cfg.CommitLog.Compression
+	}
+
 	opts = opts.SetCommitLogOptions(opts.CommitLogOptions().
 		SetInstrumentOptions(opts.InstrumentOptions()).
 		SetFilesystemOptions(fsopts).
@@ -352,7 +361,9 @@ func Run(runOpts RunOptions) {
 		SetFlushInterval(cfg.CommitLog.FlushEvery).
 		SetBacklogQueueSize(commitLogQueueSize).
 		SetBacklogQueueChannelSize(commitLogQueueChannelSize).
-		SetBlockSize(cfg.CommitLog.BlockSize))
+		SetBlockSize(cfg.CommitLog.BlockSize).
+		SetCompression(commitLogCompression).
+		SetArchiveDirectory(cfg.CommitLog.ArchiveDirectory))
 
 	// Set the series cache policy
 	seriesCachePolicy := cfg.Cache.SeriesConfiguration().Policy
@@ -455,8 +466,9 @@ func Run(runOpts RunOptions) {
 	}
 
 	// Kick off runtime options manager KV watches
+	effectiveValues := newKVEffectiveValueReporter(envCfg.KVStore, hostID, logger)
 	clientAdminOpts := m3dbClient.Options().(client.AdminOptions)
-	kvWatchClientConsistencyLevels(envCfg.KVStore, logger,
+	kvWatchClientConsistencyLevels(envCfg.KVStore, logger, effectiveValues,
 		clientAdminOpts, runtimeOptsMgr)
 
 	// Set repair options
@@ -513,6 +525,7 @@ func Run(runOpts RunOptions) {
 			}
 
 			bs.SetBootstrapperProvider(updated.BootstrapperProvider())
+			effectiveValues.report(kvconfig.BootstrapperKey, strings.Join(bootstrappers, ","))
 		})
 
 	// Initialize clustered database
@@ -531,6 +544,18 @@ func Run(runOpts RunOptions) {
 
 	contextPool := opts.ContextPool()
 
+	httpjsonOpts := httpjson.NewServerOptions()
+	grpcOpts := grpcnode.NewServerOptions()
+	if cfg.TLS != nil {
+		tlsConfig, err := cfg.TLS.NewServerConfig()
+		if err != nil {
+			logger.Fatalf("could not create tls config: %v", err)
+		}
+		ttopts = ttopts.SetTLSConfig(tlsConfig)
+		httpjsonOpts = httpjsonOpts.SetTLSConfig(tlsConfig)
+		grpcOpts = grpcOpts.SetTLSConfig(tlsConfig)
+	}
+
 	tchannelOpts := xtchannel.NewDefaultChannelOptions()
 	tchannelthriftNodeClose, err := ttnode.NewServer(db,
 		cfg.ListenAddress, contextPool, tchannelOpts, ttopts).ListenAndServe()
@@ -542,7 +567,7 @@ func Run(runOpts RunOptions) {
 	logger.Infof("node tchannelthrift: listening on %v", cfg.ListenAddress)
 
 	tchannelthriftClusterClose, err := ttcluster.NewServer(m3dbClient,
-		cfg.ClusterListenAddress, contextPool, tchannelOpts).ListenAndServe()
+		cfg.ClusterListenAddress, contextPool, tchannelOpts, ttopts).ListenAndServe()
 	if err != nil {
 		logger.Fatalf("could not open tchannelthrift interface on %s: %v",
 			cfg.ClusterListenAddress, err)
@@ -551,7 +576,7 @@ func Run(runOpts RunOptions) {
 	logger.Infof("cluster tchannelthrift: listening on %v", cfg.ClusterListenAddress)
 
 	httpjsonNodeClose, err := hjnode.NewServer(db,
-		cfg.HTTPNodeListenAddress, contextPool, nil, ttopts).ListenAndServe()
+		cfg.HTTPNodeListenAddress, contextPool, httpjsonOpts, ttopts).ListenAndServe()
 	if err != nil {
 		logger.Fatalf("could not open httpjson interface on %s: %v",
 			cfg.HTTPNodeListenAddress, err)
@@ -560,7 +585,7 @@ func Run(runOpts RunOptions) {
 	logger.Infof("node httpjson: listening on %v", cfg.HTTPNodeListenAddress)
 
 	httpjsonClusterClose, err := hjcluster.NewServer(m3dbClient,
-		cfg.HTTPClusterListenAddress, contextPool, nil).ListenAndServe()
+		cfg.HTTPClusterListenAddress, contextPool, httpjsonOpts).ListenAndServe()
 	if err != nil {
 		logger.Fatalf("could not open httpjson interface on %s: %v",
 			cfg.HTTPClusterListenAddress, err)
@@ -568,6 +593,17 @@ func Run(runOpts RunOptions) {
 	defer httpjsonClusterClose()
 	logger.Infof("cluster httpjson: listening on %v", cfg.HTTPClusterListenAddress)
 
+	if cfg.GRPCNodeListenAddress != "" {
+		grpcNodeClose, err := grpcnode.NewServer(db,
+			cfg.GRPCNodeListenAddress, contextPool, grpcOpts, ttopts).ListenAndServe()
+		if err != nil {
+			logger.Fatalf("could not open grpc interface on %s: %v",
+				cfg.GRPCNodeListenAddress, err)
+		}
+		defer grpcNodeClose()
+		logger.Infof("node grpc: listening on %v", cfg.GRPCNodeListenAddress)
+	}
+
 	if cfg.DebugListenAddress != "" {
 		go func() {
 			if err := http.ListenAndServe(cfg.DebugListenAddress, nil); err != nil {
@@ -591,7 +627,7 @@ func Run(runOpts RunOptions) {
 		logger.Infof("bootstrapped")
 
 		// Only set the write new series limit after bootstrapping
-		kvWatchNewSeriesLimitPerShard(envCfg.KVStore, logger, topo,
+		kvWatchNewSeriesLimitPerShard(envCfg.KVStore, logger, effectiveValues, topo,
 			runtimeOptsMgr, cfg.WriteNewSeriesLimitPerSecond)
 	}()
 
@@ -668,9 +704,33 @@ func bgValidateProcessLimits(logger xlog.Logger) {
 	}
 }
 
+// kvEffectiveValueReporter reports the runtime configuration values applied by
+// this node back to KV so they can be compared with the configured values.
+type kvEffectiveValueReporter struct {
+	store  kv.Store
+	hostID string
+	logger xlog.Logger
+}
+
+func newKVEffectiveValueReporter(
+	store kv.Store,
+	hostID string,
+	logger xlog.Logger,
+) kvEffectiveValueReporter {
+	return kvEffectiveValueReporter{store: store, hostID: hostID, logger: logger}
+}
+
+func (r kvEffectiveValueReporter) report(key, value string) {
+	effectiveKey := kvconfig.EffectiveValueKey(key, r.hostID)
+	if _, err := r.store.Set(effectiveKey, &commonpb.StringProto{Value: value}); err != nil {
+		r.logger.Warnf("could not report effective value of KV key %s: %v", key, err)
+	}
+}
+
 func kvWatchNewSeriesLimitPerShard(
 	store kv.Store,
 	logger xlog.Logger,
+	effectiveValues kvEffectiveValueReporter,
 	topo topology.Topology,
 	runtimeOptsMgr m3dbruntime.OptionsManager,
 	defaultClusterNewSeriesLimit int,
@@ -696,6 +756,9 @@ func kvWatchNewSeriesLimitPerShard(
 	err = setNewSeriesLimitPerShardOnChange(topo, runtimeOptsMgr, initClusterLimit)
 	if err != nil {
 		logger.Warnf("unable to set cluster new series insert limit: %v", err)
+	} else {
+		effectiveValues.report(kvconfig.ClusterNewSeriesInsertLimitKey,
+			strconv.Itoa(initClusterLimit))
 	}
 
 	watch, err := store.Watch(kvconfig.ClusterNewSeriesInsertLimitKey)
@@ -721,6 +784,8 @@ func kvWatchNewSeriesLimitPerShard(
 				logger.Warnf("unable to set cluster new series insert limit: %v", err)
 				continue
 			}
+			effectiveValues.report(kvconfig.ClusterNewSeriesInsertLimitKey,
+				strconv.Itoa(value))
 		}
 	}()
 }
@@ -728,6 +793,7 @@ func kvWatchNewSeriesLimitPerShard(
 func kvWatchClientConsistencyLevels(
 	store kv.Store,
 	logger xlog.Logger,
+	effectiveValues kvEffectiveValueReporter,
 	clientOpts client.AdminOptions,
 	runtimeOptsMgr m3dbruntime.OptionsManager,
 ) {
@@ -757,7 +823,7 @@ func kvWatchClientConsistencyLevels(
 		return fmt.Errorf("invalid consistency level set: %s", v)
 	}
 
-	kvWatchStringValue(store, logger,
+	kvWatchStringValue(store, logger, effectiveValues,
 		kvconfig.ClientBootstrapConsistencyLevel,
 		func(value string) error {
 			return setReadConsistencyLevel(value,
@@ -765,12 +831,13 @@ func kvWatchClientConsistencyLevels(
 					return opts.SetClientBootstrapConsistencyLevel(level)
 				})
 		},
-		func() error {
-			return runtimeOptsMgr.Update(runtimeOptsMgr.Get().
-				SetClientBootstrapConsistencyLevel(clientOpts.BootstrapConsistencyLevel()))
+		func() (string, error) {
+			level := clientOpts.BootstrapConsistencyLevel()
+			return level.String(), runtimeOptsMgr.Update(runtimeOptsMgr.Get().
+				SetClientBootstrapConsistencyLevel(level))
 		})
 
-	kvWatchStringValue(store, logger,
+	kvWatchStringValue(store, logger, effectiveValues,
 		kvconfig.ClientReadConsistencyLevel,
 		func(value string) error {
 			return setReadConsistencyLevel(value,
@@ -778,12 +845,13 @@ func kvWatchClientConsistencyLevels(
 					return opts.SetClientReadConsistencyLevel(level)
 				})
 		},
-		func() error {
-			return runtimeOptsMgr.Update(runtimeOptsMgr.Get().
-				SetClientReadConsistencyLevel(clientOpts.ReadConsistencyLevel()))
+		func() (string, error) {
+			level := clientOpts.ReadConsistencyLevel()
+			return level.String(), runtimeOptsMgr.Update(runtimeOptsMgr.Get().
+				SetClientReadConsistencyLevel(level))
 		})
 
-	kvWatchStringValue(store, logger,
+	kvWatchStringValue(store, logger, effectiveValues,
 		kvconfig.ClientWriteConsistencyLevel,
 		func(value string) error {
 			return setConsistencyLevel(value,
@@ -791,18 +859,20 @@ func kvWatchClientConsistencyLevels(
 					return opts.SetClientWriteConsistencyLevel(level)
 				})
 		},
-		func() error {
-			return runtimeOptsMgr.Update(runtimeOptsMgr.Get().
-				SetClientWriteConsistencyLevel(clientOpts.WriteConsistencyLevel()))
+		func() (string, error) {
+			level := clientOpts.WriteConsistencyLevel()
+			return level.String(), runtimeOptsMgr.Update(runtimeOptsMgr.Get().
+				SetClientWriteConsistencyLevel(level))
 		})
 }
 
 func kvWatchStringValue(
 	store kv.Store,
 	logger xlog.Logger,
+	effectiveValues kvEffectiveValueReporter,
 	key string,
 	onValue func(value string) error,
-	onDelete func() error,
+	onDelete func() (string, error),
 ) {
 	protoValue := &commonpb.StringProto{}
 
@@ -819,6 +889,7 @@ func kvWatchStringValue(
 			logger.Errorf("could not process value of KV key %s: %v", key, err)
 		} else {
 			logger.Infof("set KV key %s: %v", key, protoValue.Value)
+			effectiveValues.report(key, protoValue.Value)
 		}
 	}
 
@@ -832,9 +903,12 @@ func kvWatchStringValue(
 		for range watch.C() {
 			newValue := watch.Get()
 			if newValue == nil {
-				if err := onDelete(); err != nil {
+				defaultValue, err := onDelete()
+				if err != nil {
 					logger.Warnf("could not set default for KV key %s: %v", key, err)
+					continue
 				}
+				effectiveValues.report(key, defaultValue)
 				continue
 			}
 
@@ -848,6 +922,7 @@ func kvWatchStringValue(
 				continue
 			}
 			logger.Infof("set KV key %s: %v", key, protoValue.Value)
+			effectiveValues.report(key, protoValue.Value)
 		}
 	}()
 }
@@ -1063,9 +1063,15 @@ func (s *fileSystemSource) bootstrapFromIndexPersistedBlocks(
 
 		info := infoFile.Info
 		indexBlockStart := xtime.UnixNano(info.BlockStart).ToTime()
+		volumeBlockSize := indexBlockSize
+		if info.BlockSize > 0 {
+			// Volumes of index blocks rolled up to a coarser block size
+			// cover the rollup block size recorded in their info file.
+			volumeBlockSize = time.Duration(info.BlockSize)
+		}
 		indexBlockRange := xtime.Range{
 			Start: indexBlockStart,
-			End:   indexBlockStart.Add(indexBlockSize),
+			End:   indexBlockStart.Add(volumeBlockSize),
 		}
 		willFulfill := result.ShardTimeRanges{}
 		for _, shard := range info.Shards {
@@ -22,6 +22,7 @@ package storage
 
 import (
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
@@ -39,6 +40,8 @@ type commitLogFilesFn func(commitlog.Options) ([]commitlog.File, []commitlog.Err
 
 type deleteFilesFn func(files []string) error
 
+type archiveFilesFn func(files []string, dir string, newDirectoryMode os.FileMode) error
+
 type deleteInactiveDirectoriesFn func(parentDirPath string, activeDirNames []string) error
 
 // Narrow interface so as not to expose all the functionality of the commitlog
@@ -59,23 +62,26 @@ type cleanupManager struct {
 	commitLogsDir               string
 	commitLogFilesFn            commitLogFilesFn
 	deleteFilesFn               deleteFilesFn
+	archiveFilesFn              archiveFilesFn
 	deleteInactiveDirectoriesFn deleteInactiveDirectoriesFn
 	cleanupInProgress           bool
 	metrics                     cleanupManagerMetrics
 }
 
 type cleanupManagerMetrics struct {
-	status               tally.Gauge
-	corruptCommitlogFile tally.Counter
-	deletedCommitlogFile tally.Counter
+	status                tally.Gauge
+	corruptCommitlogFile  tally.Counter
+	deletedCommitlogFile  tally.Counter
+	archivedCommitlogFile tally.Counter
 }
 
 func newCleanupManagerMetrics(scope tally.Scope) cleanupManagerMetrics {
 	clScope := scope.SubScope("commitlog")
 	return cleanupManagerMetrics{
-		status:               scope.Gauge("cleanup"),
-		corruptCommitlogFile: clScope.Counter("corrupt"),
-		deletedCommitlogFile: clScope.Counter("deleted"),
+		status:                scope.Gauge("cleanup"),
+		corruptCommitlogFile:  clScope.Counter("corrupt"),
+		deletedCommitlogFile:  clScope.Counter("deleted"),
+		archivedCommitlogFile: clScope.Counter("archived"),
 	}
 }
 
@@ -95,6 +101,7 @@ func newCleanupManager(
 		commitLogsDir:               commitLogsDir,
 		commitLogFilesFn:            commitlog.Files,
 		deleteFilesFn:               fs.DeleteFiles,
+		archiveFilesFn:              fs.MoveFiles,
 		deleteInactiveDirectoriesFn: fs.DeleteInactiveDirectories,
 		metrics:                     newCleanupManagerMetrics(scope),
 	}
@@ -154,7 +161,6 @@ func (m *cleanupManager) Cleanup(t time.Time) error {
 			"encountered errors when cleaning up commit logs for commitLogFiles %v: %v",
 			filesToCleanup, err))
 	}
-	m.metrics.deletedCommitlogFile.Inc(int64(len(filesToCleanup)))
 
 	return multiErr.FinalError()
 }
@@ -448,6 +454,18 @@ func (m *cleanupManager) cleanupCommitLogs(filesToCleanup []commitLogFileWithErr
 	for _, f := range filesToCleanup {
 		filesToDelete = append(filesToDelete, f.path)
 	}
+
+	// Commit log files that are no longer required for bootstrap are moved
+	// to the archive directory when one is configured so that they remain
+	// available for forensic replay.
+	commitLogOpts := m.opts.CommitLogOptions()
+	if archiveDir := commitLogOpts.ArchiveDirectory(); archiveDir != "" {
+		m.metrics.archivedCommitlogFile.Inc(int64(len(filesToDelete)))
+		return m.archiveFilesFn(filesToDelete, fs.CommitLogsDirPath(archiveDir),
+			commitLogOpts.FilesystemOptions().NewDirectoryMode())
+	}
+
+	m.metrics.deletedCommitlogFile.Inc(int64(len(filesToDelete)))
 	return m.deleteFilesFn(filesToDelete)
 }
 
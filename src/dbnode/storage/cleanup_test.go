@@ -23,10 +23,12 @@ package storage
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/persist/fs/commitlog"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
@@ -90,6 +92,56 @@ func TestCleanupManagerCleanup(t *testing.T) {
 	require.Equal(t, []string{"foo"}, deletedFiles)
 }
 
+func TestCleanupManagerCleanupArchivesCommitLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ts := timeFor(36000)
+	rOpts := retentionOptions.
+		SetRetentionPeriod(21600 * time.Second).
+		SetBlockSize(7200 * time.Second)
+	nsOpts := namespaceOptions.SetRetentionOptions(rOpts)
+
+	ns := NewMockdatabaseNamespace(ctrl)
+	ns.EXPECT().ID().Return(ident.StringID("ns")).AnyTimes()
+	ns.EXPECT().Options().Return(nsOpts).AnyTimes()
+	ns.EXPECT().NeedsFlush(gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+	ns.EXPECT().GetOwnedShards().Return(nil).AnyTimes()
+	namespaces := []databaseNamespace{ns}
+	db := newMockdatabase(ctrl, namespaces...)
+	db.EXPECT().GetOwnedNamespaces().Return(namespaces, nil).AnyTimes()
+	mgr := newCleanupManager(db, newNoopFakeActiveLogs(), tally.NoopScope).(*cleanupManager)
+	mgr.opts = mgr.opts.SetCommitLogOptions(
+		mgr.opts.CommitLogOptions().
+			SetBlockSize(rOpts.BlockSize()).
+			SetArchiveDirectory("/var/lib/m3db-archive"))
+
+	mgr.commitLogFilesFn = func(_ commitlog.Options) ([]commitlog.File, []commitlog.ErrorWithPath, error) {
+		return []commitlog.File{
+			{FilePath: "foo", Start: timeFor(14400)},
+		}, nil, nil
+	}
+	var deletedFiles []string
+	mgr.deleteFilesFn = func(files []string) error {
+		deletedFiles = append(deletedFiles, files...)
+		return nil
+	}
+	var (
+		archivedFiles []string
+		archiveDir    string
+	)
+	mgr.archiveFilesFn = func(files []string, dir string, _ os.FileMode) error {
+		archivedFiles = append(archivedFiles, files...)
+		archiveDir = dir
+		return nil
+	}
+
+	require.NoError(t, mgr.Cleanup(ts))
+	require.Empty(t, deletedFiles)
+	require.Equal(t, []string{"foo"}, archivedFiles)
+	require.Equal(t, fs.CommitLogsDirPath("/var/lib/m3db-archive"), archiveDir)
+}
+
 func TestCleanupManagerNamespaceCleanup(t *testing.T) {
 	ctrl := gomock.NewController(xtest.Reporter{t})
 	defer ctrl.Finish()
@@ -77,14 +77,18 @@ type nsIndex struct {
 	// and don't require a lock when being accessed.
 	nowFn           clock.NowFn
 	blockSize       time.Duration
+	rollupBlockSize time.Duration
 	retentionPeriod time.Duration
 	bufferPast      time.Duration
 	bufferFuture    time.Duration
 
 	indexFilesetsBeforeFn indexFilesetsBeforeFn
+	indexFilesetsAtFn     indexFilesetsAtFn
+	readIndexInfoFilesFn  readIndexInfoFilesFn
 	deleteFilesFn         deleteFilesFn
 
 	newBlockFn          newBlockFn
+	newRollupBlockFn    newRollupBlockFn
 	logger              xlog.Logger
 	opts                Options
 	nsMetadata          namespace.Metadata
@@ -100,6 +104,16 @@ type nsIndex struct {
 	// blocks and other cleanup tasks on index close
 	queriesWg sync.WaitGroup
 
+	// compactLock serializes the background compaction of flushed blocks with
+	// flushes as both write index volumes and compaction replaces the volumes
+	// that back the segments of a block.
+	compactLock            sync.Mutex
+	compactWorkers         xsync.WorkerPool
+	compactPersistManagers chan persist.Manager
+	newPersistManagerFn    newPersistManagerFn
+	compactCloseCh         chan struct{}
+	compactDoneCh          chan struct{}
+
 	metrics nsIndexMetrics
 }
 
@@ -123,6 +137,17 @@ type nsIndexState struct {
 	// chronological order. This is used at query time to enforce determinism about results
 	// returned.
 	blockStartsDescOrder []xtime.UnixNano
+
+	// NB: `rollupBlocksByTime` contains the blocks that flushed blocks have been rolled up
+	// into when a rollup block size is configured for the namespace, keyed by the start of
+	// the rollup block. `rollupBlockStartsDescOrder` contains its keys in reverse
+	// chronological order.
+	rollupBlocksByTime         map[xtime.UnixNano]index.Block
+	rollupBlockStartsDescOrder []xtime.UnixNano
+
+	// NB: `rolledUpBlocks` contains the blocks that have been replaced by a rollup block,
+	// they are closed on the following tick so that any inflight queries can complete.
+	rolledUpBlocks []index.Block
 }
 
 // NB: nsIndexRuntimeOptions does not contain its own mutex as some of the variables
@@ -138,6 +163,10 @@ type nsIndexRuntimeOptions struct {
 
 type newBlockFn func(time.Time, namespace.Metadata, index.Options) (index.Block, error)
 
+type newRollupBlockFn func(time.Time, time.Duration, namespace.Metadata, index.Options) (index.Block, error)
+
+type newPersistManagerFn func() (persist.Manager, error)
+
 // NB(prateek): the returned filesets are strictly before the given time, i.e. they
 // live in the period (-infinity, exclusiveTime).
 type indexFilesetsBeforeFn func(dir string,
@@ -145,6 +174,16 @@ type indexFilesetsBeforeFn func(dir string,
 	exclusiveTime time.Time,
 ) ([]string, error)
 
+type indexFilesetsAtFn func(dir string,
+	nsID ident.ID,
+	blockStart time.Time,
+) (fs.FileSetFilesSlice, error)
+
+type readIndexInfoFilesFn func(dir string,
+	nsID ident.ID,
+	readerBufferSize int,
+) []fs.ReadIndexInfoFileResult
+
 type newNamespaceIndexOpts struct {
 	md              namespace.Metadata
 	opts            Options
@@ -217,32 +256,49 @@ func newNamespaceIndexWithOptions(
 	instrumentOpts = instrumentOpts.SetMetricsScope(scope)
 	indexOpts = indexOpts.SetInstrumentOptions(instrumentOpts)
 
-	nowFn := indexOpts.ClockOptions().NowFn()
+	var (
+		nowFn              = indexOpts.ClockOptions().NowFn()
+		fsOpts             = newIndexOpts.opts.CommitLogOptions().FilesystemOptions()
+		compactConcurrency = indexOpts.CompactFlushedSegmentsConcurrency()
+		compactWorkers     = xsync.NewWorkerPool(compactConcurrency)
+	)
+	compactWorkers.Init()
 	idx := &nsIndex{
 		state: nsIndexState{
 			runtimeOpts: nsIndexRuntimeOptions{
 				insertMode:            indexOpts.InsertMode(), // FOLLOWUP(prateek): wire to allow this to be tweaked at runtime
 				flushBlockNumSegments: runtime.DefaultFlushIndexBlockNumSegments,
 			},
-			blocksByTime: make(map[xtime.UnixNano]index.Block),
+			blocksByTime:       make(map[xtime.UnixNano]index.Block),
+			rollupBlocksByTime: make(map[xtime.UnixNano]index.Block),
 		},
 
 		nowFn:           nowFn,
 		blockSize:       nsMD.Options().IndexOptions().BlockSize(),
+		rollupBlockSize: nsMD.Options().IndexOptions().RollupBlockSize(),
 		retentionPeriod: nsMD.Options().RetentionOptions().RetentionPeriod(),
 		bufferPast:      nsMD.Options().RetentionOptions().BufferPast(),
 		bufferFuture:    nsMD.Options().RetentionOptions().BufferFuture(),
 
 		indexFilesetsBeforeFn: fs.IndexFileSetsBefore,
+		indexFilesetsAtFn:     fs.IndexFileSetsAt,
+		readIndexInfoFilesFn:  fs.ReadIndexInfoFiles,
 		deleteFilesFn:         fs.DeleteFiles,
 
 		newBlockFn:       newBlockFn,
+		newRollupBlockFn: index.NewBlockWithSize,
 		opts:             newIndexOpts.opts,
 		logger:           indexOpts.InstrumentOptions().Logger(),
 		nsMetadata:       nsMD,
 		resultsPool:      indexOpts.ResultsPool(),
 		queryWorkersPool: newIndexOpts.opts.QueryIDsWorkerPool(),
 
+		compactWorkers:         compactWorkers,
+		compactPersistManagers: make(chan persist.Manager, compactConcurrency),
+		newPersistManagerFn: func() (persist.Manager, error) {
+			return fs.NewPersistManager(fsOpts)
+		},
+
 		metrics: newNamespaceIndexMetrics(instrumentOpts),
 	}
 	if runtimeOptsMgr != nil {
@@ -259,11 +315,19 @@ func newNamespaceIndexWithOptions(
 	// allocate the current block to ensure we're able to index as soon as we return
 	currentBlock := nowFn().Truncate(idx.blockSize)
 	idx.state.RLock()
-	defer idx.state.RUnlock()
-	if _, err := idx.ensureBlockPresentWithRLock(currentBlock); err != nil {
+	_, err := idx.ensureBlockPresentWithRLock(currentBlock)
+	idx.state.RUnlock()
+	if err != nil {
 		return nil, err
 	}
 
+	// start compacting the segments of flushed blocks in the background.
+	if indexOpts.CompactFlushedSegments() {
+		idx.compactCloseCh = make(chan struct{})
+		idx.compactDoneCh = make(chan struct{})
+		go idx.compactLoop(indexOpts.CompactFlushedSegmentsInterval())
+	}
+
 	return idx, nil
 }
 
@@ -453,7 +517,15 @@ func (i *nsIndex) Bootstrap(
 
 	var multiErr xerrors.MultiError
 	for blockStart, blockResults := range bootstrapResults {
-		block, err := i.ensureBlockPresentWithRLock(blockStart.ToTime())
+		var (
+			block index.Block
+			err   error
+		)
+		if rollupBlockSize, ok := i.rollupBlockSizeForResults(blockResults); ok {
+			block, err = i.ensureRollupBlockPresentWithRLock(blockStart.ToTime(), rollupBlockSize)
+		} else {
+			block, err = i.ensureBlockPresentWithRLock(blockStart.ToTime())
+		}
 		if err != nil { // should never happen
 			multiErr = multiErr.Add(i.unableToAllocBlockInvariantError(err))
 			continue
@@ -479,9 +551,38 @@ func (i *nsIndex) Tick(c context.Cancellable, tickStart time.Time) (namespaceInd
 		i.state.Unlock()
 	}()
 
-	result.NumBlocks = int64(len(i.state.blocksByTime))
+	result.NumBlocks = int64(len(i.state.blocksByTime) + len(i.state.rollupBlocksByTime))
 
 	var multiErr xerrors.MultiError
+
+	// close any blocks replaced by a rollup block during the last flush, any
+	// queries that were using them have had a tick interval to complete.
+	for _, block := range i.state.rolledUpBlocks {
+		multiErr = multiErr.Add(block.Close())
+	}
+	i.state.rolledUpBlocks = nil
+
+	for blockStart, block := range i.state.rollupBlocksByTime {
+		if c.IsCancelled() {
+			multiErr = multiErr.Add(errDbIndexTerminatingTickCancellation)
+			return result, multiErr.FinalError()
+		}
+
+		// drop any rollup blocks wholly past the retention period
+		if !block.EndTime().After(earliestBlockStartToRetain) {
+			multiErr = multiErr.Add(block.Close())
+			delete(i.state.rollupBlocksByTime, blockStart)
+			result.NumBlocksEvicted++
+			result.NumBlocks--
+			continue
+		}
+
+		blockTickResult, tickErr := block.Tick(c, tickStart)
+		multiErr = multiErr.Add(tickErr)
+		result.NumSegments += blockTickResult.NumSegments
+		result.NumTotalDocs += blockTickResult.NumDocs
+	}
+
 	for blockStart, block := range i.state.blocksByTime {
 		if c.IsCancelled() {
 			multiErr = multiErr.Add(errDbIndexTerminatingTickCancellation)
@@ -517,6 +618,12 @@ func (i *nsIndex) Flush(
 	flush persist.IndexFlush,
 	shards []databaseShard,
 ) error {
+	// Hold the compaction lock for the duration of the flush so that the
+	// volumes on disk for a block always match the segments it holds while
+	// the block is compacted.
+	i.compactLock.Lock()
+	defer i.compactLock.Unlock()
+
 	flushable, err := i.flushableBlocks(shards)
 	if err != nil {
 		return err
@@ -552,9 +659,509 @@ func (i *nsIndex) Flush(
 		}
 	}
 	i.metrics.FlushEvictedMutableSegments.Inc(evictResults.NumMutableSegments)
+
+	if i.rollupBlockSize > 0 {
+		i.rollupFlushedBlocks(flush, shards)
+	}
 	return nil
 }
 
+// compactLoop compacts the segments of flushed blocks each interval until
+// the index is closed.
+func (i *nsIndex) compactLoop(interval time.Duration) {
+	defer close(i.compactDoneCh)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-i.compactCloseCh:
+			return
+		case <-ticker.C:
+			i.compactFlushedBlocks()
+		}
+	}
+}
+
+// compactFlushedBlocks merges the segments of each flushed block that holds
+// more than a single segment into a single segment persisted as a new index
+// volume, the volumes it replaces are then removed from disk.
+func (i *nsIndex) compactFlushedBlocks() {
+	i.compactLock.Lock()
+	defer i.compactLock.Unlock()
+
+	i.state.RLock()
+	if !i.isOpenWithRLock() {
+		i.state.RUnlock()
+		return
+	}
+	// Blocks that share a block start are compacted by the same worker as
+	// the volume index of a new volume is resolved from the volumes on disk.
+	compactable := make(map[xtime.UnixNano][]index.Block)
+	for blockStart, block := range i.state.blocksByTime {
+		if len(block.FlushedSegments()) > 1 {
+			compactable[blockStart] = append(compactable[blockStart], block)
+		}
+	}
+	for blockStart, block := range i.state.rollupBlocksByTime {
+		if len(block.FlushedSegments()) > 1 {
+			compactable[blockStart] = append(compactable[blockStart], block)
+		}
+	}
+	i.state.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, blocks := range compactable {
+		blocks := blocks
+		wg.Add(1)
+		i.compactWorkers.Go(func() {
+			defer wg.Done()
+			for _, block := range blocks {
+				i.state.RLock()
+				open := i.isOpenWithRLock()
+				i.state.RUnlock()
+				if !open {
+					return
+				}
+				i.compactFlushedBlockAndEmitMetrics(block)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func (i *nsIndex) compactFlushedBlockAndEmitMetrics(indexBlock index.Block) {
+	numSegments, err := i.compactFlushedBlockWithPersistManager(indexBlock)
+	if err != nil {
+		// deliberately choosing to not mark this as an error as the block
+		// remains queryable using the segments it already holds.
+		i.metrics.CompactionErrors.Inc(1)
+		i.logger.WithFields(
+			xlog.NewField("err", err.Error()),
+			xlog.NewField("blockStart", indexBlock.StartTime()),
+		).Warnf("encountered error while compacting flushed index block segments")
+		return
+	}
+	if numSegments == 0 {
+		return
+	}
+	i.metrics.CompactedBlocks.Inc(1)
+	i.metrics.CompactedSegments.Inc(int64(numSegments))
+}
+
+// compactFlushedBlockWithPersistManager compacts the block using a persist
+// manager owned by the compactor as the flush persist manager only supports
+// a single persist at a time.
+func (i *nsIndex) compactFlushedBlockWithPersistManager(
+	indexBlock index.Block,
+) (int, error) {
+	pm, err := i.acquireCompactPersistManager()
+	if err != nil {
+		return 0, err
+	}
+	defer i.releaseCompactPersistManager(pm)
+
+	flush, err := pm.StartIndexPersist()
+	if err != nil {
+		return 0, err
+	}
+
+	var multiErr xerrors.MultiError
+	numSegments, err := i.compactFlushedBlock(flush, indexBlock)
+	multiErr = multiErr.Add(err)
+	multiErr = multiErr.Add(flush.DoneIndex())
+	if err := multiErr.FinalError(); err != nil {
+		return 0, err
+	}
+	return numSegments, nil
+}
+
+// acquireCompactPersistManager returns an idle persist manager, at most one
+// persist manager is created per compaction worker.
+func (i *nsIndex) acquireCompactPersistManager() (persist.Manager, error) {
+	select {
+	case pm := <-i.compactPersistManagers:
+		return pm, nil
+	default:
+		return i.newPersistManagerFn()
+	}
+}
+
+func (i *nsIndex) releaseCompactPersistManager(pm persist.Manager) {
+	select {
+	case i.compactPersistManagers <- pm:
+	default:
+	}
+}
+
+func (i *nsIndex) compactFlushedBlock(
+	flush persist.IndexFlush,
+	indexBlock index.Block,
+) (int, error) {
+	segments := indexBlock.FlushedSegments()
+	if len(segments) <= 1 {
+		return 0, nil
+	}
+
+	// Resolve the volumes backing the segments before persisting the compacted
+	// volume so that only the volumes being replaced are removed afterwards,
+	// the compacted volume covers the same shards as the volumes it replaces.
+	filesets, shards, err := i.indexVolumesAt(indexBlock.StartTime())
+	if err != nil {
+		return 0, err
+	}
+	if len(filesets) == 0 {
+		return 0, nil
+	}
+
+	compacted, err := i.persistCompactedSegment(flush, persist.IndexPrepareOptions{
+		NamespaceMetadata: i.nsMetadata,
+		BlockStart:        indexBlock.StartTime(),
+		FileSetType:       persist.FileSetFlushType,
+		Shards:            shards,
+		BlockSize:         indexBlock.EndTime().Sub(indexBlock.StartTime()),
+	}, segments)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := indexBlock.ReplaceFlushedSegments(segments, compacted); err != nil {
+		// The compacted volume is left on disk as it is a complete volume for
+		// the block, it will be compacted again with any other segments the
+		// block now holds.
+		compacted.Close()
+		return 0, err
+	}
+
+	// The block now only references the compacted volume so it is safe to
+	// remove the volumes that were replaced.
+	if err := i.deleteFilesFn(filesets.Filepaths()); err != nil {
+		return 0, err
+	}
+
+	return len(segments), nil
+}
+
+// indexVolumesAt returns the index volumes on disk for the provided block
+// starts and the shards they cover as recorded in their info files.
+func (i *nsIndex) indexVolumesAt(
+	blockStarts ...time.Time,
+) (fs.FileSetFilesSlice, map[uint32]struct{}, error) {
+	var (
+		fsOpts     = i.opts.CommitLogOptions().FilesystemOptions()
+		pathPrefix = fsOpts.FilePathPrefix()
+		nsID       = i.nsMetadata.ID()
+		filesets   fs.FileSetFilesSlice
+		volumes    = make(map[indexVolumeKey]bool)
+	)
+	for _, blockStart := range blockStarts {
+		blockFilesets, err := i.indexFilesetsAtFn(pathPrefix, nsID, blockStart)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, fileset := range blockFilesets {
+			volumes[newIndexVolumeKey(fileset.ID)] = false
+		}
+		filesets = append(filesets, blockFilesets...)
+	}
+	if len(filesets) == 0 {
+		return nil, nil, nil
+	}
+
+	shards := make(map[uint32]struct{})
+	infoFiles := i.readIndexInfoFilesFn(pathPrefix, nsID, fsOpts.InfoReaderBufferSize())
+	for _, infoFile := range infoFiles {
+		key := newIndexVolumeKey(infoFile.ID)
+		if _, ok := volumes[key]; !ok {
+			continue
+		}
+		if err := infoFile.Err.Error(); err != nil {
+			return nil, nil, fmt.Errorf("unable to read index info file %s: %v",
+				infoFile.Err.Filepath(), err)
+		}
+		volumes[key] = true
+		for _, shard := range infoFile.Info.Shards {
+			shards[shard] = struct{}{}
+		}
+	}
+	for key, read := range volumes {
+		if !read {
+			return nil, nil, fmt.Errorf(
+				"unable to find index info file for block start %v volume %d",
+				key.blockStart.ToTime(), key.volumeIndex)
+		}
+	}
+
+	return filesets, shards, nil
+}
+
+// indexVolumeKey identifies an index volume of the namespace.
+type indexVolumeKey struct {
+	blockStart  xtime.UnixNano
+	volumeIndex int
+}
+
+func newIndexVolumeKey(id fs.FileSetFileIdentifier) indexVolumeKey {
+	return indexVolumeKey{
+		blockStart:  xtime.ToUnixNano(id.BlockStart),
+		volumeIndex: id.VolumeIndex,
+	}
+}
+
+// persistCompactedSegment merges the provided segments into a single segment
+// persisted as a new index volume and returns the persisted segment.
+func (i *nsIndex) persistCompactedSegment(
+	flush persist.IndexFlush,
+	opts persist.IndexPrepareOptions,
+	segments []segment.Segment,
+) (segment.Segment, error) {
+	preparedPersist, err := flush.PrepareIndex(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.compactSegments(preparedPersist, segments); err != nil {
+		compacted, _ := preparedPersist.Close()
+		// Safe to for over a nil array so disregard error here.
+		for _, segment := range compacted {
+			segment.Close()
+		}
+		return nil, err
+	}
+
+	compacted, err := preparedPersist.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(compacted) != 1 {
+		for _, segment := range compacted {
+			segment.Close()
+		}
+		return nil, fmt.Errorf("expected a single compacted segment, found: %d", len(compacted))
+	}
+	return compacted[0], nil
+}
+
+// rollupFlushedBlocks merges the flushed blocks of each rollup block sized
+// window that can no longer receive writes into a single rollup block, the
+// rollup block is persisted as a new index volume at the start of the window
+// and the volumes of the blocks it replaces are then removed from disk.
+func (i *nsIndex) rollupFlushedBlocks(
+	flush persist.IndexFlush,
+	shards []databaseShard,
+) {
+	// Only windows whose blocks are all sealable can be rolled up
+	// so that no further writes can arrive for the window.
+	lastSealableBlockStart := retention.FlushTimeEndForBlockSize(i.blockSize,
+		i.nowFn().Add(-i.bufferPast))
+
+	i.state.RLock()
+	if !i.isOpenWithRLock() {
+		i.state.RUnlock()
+		return
+	}
+	windows := make(map[xtime.UnixNano][]index.Block)
+	for blockStart, block := range i.state.blocksByTime {
+		windowStart := blockStart.ToTime().Truncate(i.rollupBlockSize)
+		windowEnd := windowStart.Add(i.rollupBlockSize)
+		if windowEnd.After(lastSealableBlockStart.Add(i.blockSize)) {
+			continue
+		}
+		key := xtime.ToUnixNano(windowStart)
+		windows[key] = append(windows[key], block)
+	}
+	i.state.RUnlock()
+
+	for windowStart, blocks := range windows {
+		rolledUp, err := i.rollupBlocks(flush, windowStart.ToTime(), blocks, shards)
+		if err != nil {
+			// deliberately choosing to not mark this as an error as the blocks
+			// remain queryable at their existing granularity.
+			i.metrics.RollupErrors.Inc(1)
+			i.logger.WithFields(
+				xlog.NewField("err", err.Error()),
+				xlog.NewField("rollupBlockStart", windowStart.ToTime()),
+			).Warnf("encountered error while rolling up flushed index blocks")
+			continue
+		}
+		i.metrics.RolledUpBlocks.Inc(int64(rolledUp))
+	}
+}
+
+func (i *nsIndex) rollupBlocks(
+	flush persist.IndexFlush,
+	windowStart time.Time,
+	blocks []index.Block,
+	shards []databaseShard,
+) (int, error) {
+	// Every block in the window must have been flushed before it can be
+	// rolled up, otherwise wait for a subsequent flush.
+	var segments []segment.Segment
+	for _, block := range blocks {
+		if !block.IsSealed() || block.NeedsMutableSegmentsEvicted() {
+			return 0, nil
+		}
+		segments = append(segments, block.FlushedSegments()...)
+	}
+
+	// Include the segments of any existing rollup block for the window, this
+	// can exist if the window was bootstrapped both from a rollup volume and
+	// from volumes of the blocks that it replaced.
+	windowStartNanos := xtime.ToUnixNano(windowStart)
+	i.state.RLock()
+	rollupBlock, hasRollupBlock := i.state.rollupBlocksByTime[windowStartNanos]
+	i.state.RUnlock()
+	var rollupSegments []segment.Segment
+	if hasRollupBlock {
+		rollupSegments = rollupBlock.FlushedSegments()
+		segments = append(segments, rollupSegments...)
+	}
+
+	// Resolve the volumes on disk before persisting the rollup volume so
+	// that only the volumes being replaced are removed afterwards, the
+	// rollup volume covers the same shards as the volumes it replaces.
+	var (
+		windowEnd   = windowStart.Add(i.rollupBlockSize)
+		blockStarts []time.Time
+	)
+	for t := windowStart; t.Before(windowEnd); t = t.Add(i.blockSize) {
+		blockStarts = append(blockStarts, t)
+	}
+	filesets, volumeShards, err := i.indexVolumesAt(blockStarts...)
+	if err != nil {
+		return 0, err
+	}
+	if len(filesets) == 0 {
+		return 0, nil
+	}
+
+	rolledUp, err := i.persistCompactedSegment(flush, persist.IndexPrepareOptions{
+		NamespaceMetadata: i.nsMetadata,
+		BlockStart:        windowStart,
+		FileSetType:       persist.FileSetFlushType,
+		Shards:            volumeShards,
+		BlockSize:         i.rollupBlockSize,
+	}, segments)
+	if err != nil {
+		return 0, err
+	}
+
+	if hasRollupBlock {
+		err = rollupBlock.ReplaceFlushedSegments(rollupSegments, rolledUp)
+	} else {
+		rollupBlock, err = i.newRollupBlockWithSegment(windowStart, rolledUp, shards)
+	}
+	if err != nil {
+		// The rollup volume is left on disk as it is a complete volume
+		// for the window, it will be rolled up again on the next flush.
+		rolledUp.Close()
+		return 0, err
+	}
+
+	i.state.Lock()
+	if !i.isOpenWithRLock() {
+		i.state.Unlock()
+		if !hasRollupBlock {
+			rollupBlock.Close()
+		}
+		return 0, errDbIndexUnableToFlushClosed
+	}
+	i.state.rollupBlocksByTime[windowStartNanos] = rollupBlock
+	for _, block := range blocks {
+		blockStart := xtime.ToUnixNano(block.StartTime())
+		if existing, ok := i.state.blocksByTime[blockStart]; ok && existing == block {
+			delete(i.state.blocksByTime, blockStart)
+		}
+		i.state.rolledUpBlocks = append(i.state.rolledUpBlocks, block)
+	}
+	i.updateBlockStartsWithLock()
+	i.state.Unlock()
+
+	// The rollup block now holds all the data for the window so it is safe
+	// to remove the volumes that were replaced.
+	if err := i.deleteFilesFn(filesets.Filepaths()); err != nil {
+		return 0, err
+	}
+
+	return len(blocks), nil
+}
+
+func (i *nsIndex) compactSegments(
+	preparedPersist persist.PreparedIndexPersist,
+	segments []segment.Segment,
+) error {
+	seg, err := mem.NewSegment(postings.ID(0), i.opts.IndexOptions().MemSegmentOptions())
+	if err != nil {
+		return err
+	}
+	defer seg.Close()
+
+	for _, s := range segments {
+		if err := compactSegmentInto(seg, s); err != nil {
+			return err
+		}
+	}
+
+	if _, err := seg.Seal(); err != nil {
+		return err
+	}
+
+	return preparedPersist.Persist(seg)
+}
+
+func compactSegmentInto(dst segment.MutableSegment, src segment.Segment) error {
+	reader, err := src.Reader()
+	if err != nil {
+		return err
+	}
+
+	iter, err := reader.AllDocs()
+	if err != nil {
+		reader.Close()
+		return err
+	}
+
+	var multiErr xerrors.MultiError
+	for iter.Next() {
+		d := iter.Current()
+		exists, err := dst.ContainsID(d.ID)
+		if err != nil {
+			multiErr = multiErr.Add(err)
+			break
+		}
+		if exists {
+			continue
+		}
+
+		// NB: documents returned by the iterator are only valid until the
+		// following call to Next so they must be copied before inserting.
+		if _, err := dst.Insert(copyDocument(d)); err != nil {
+			multiErr = multiErr.Add(err)
+			break
+		}
+	}
+
+	multiErr = multiErr.Add(iter.Err())
+	multiErr = multiErr.Add(iter.Close())
+	multiErr = multiErr.Add(reader.Close())
+	return multiErr.FinalError()
+}
+
+func copyDocument(d doc.Document) doc.Document {
+	fields := make([]doc.Field, 0, len(d.Fields))
+	for _, f := range d.Fields {
+		fields = append(fields, doc.Field{
+			Name:  append([]byte(nil), f.Name...),
+			Value: append([]byte(nil), f.Value...),
+		})
+	}
+	return doc.Document{
+		ID:     append([]byte(nil), d.ID...),
+		Fields: fields,
+	}
+}
+
 func (i *nsIndex) flushableBlocks(
 	shards []databaseShard,
 ) ([]index.Block, error) {
@@ -991,7 +1598,32 @@ func (i *nsIndex) overriddenOptsForQueryWithRLock(
 func (i *nsIndex) blocksForQueryWithRLock(queryRange xtime.Ranges) ([]index.Block, error) {
 	// Chunk the query request into bounds based on applicable blocks and
 	// execute the requests to each of them; and merge results.
-	blocks := make([]index.Block, 0, len(i.state.blockStartsDescOrder))
+	blocks := make([]index.Block, 0,
+		len(i.state.rollupBlockStartsDescOrder)+len(i.state.blockStartsDescOrder))
+
+	// Prefer rollup blocks as they cover the coarsest ranges of time, the
+	// blocks at the namespace index block size are only used for the ranges
+	// of time that are not covered by a rollup block.
+	for _, start := range i.state.rollupBlockStartsDescOrder {
+		if queryRange.IsEmpty() {
+			break
+		}
+
+		block, ok := i.state.rollupBlocksByTime[start]
+		if !ok {
+			// This is an invariant, should never occur if state tracking is correct.
+			return nil, i.missingBlockInvariantError(start)
+		}
+
+		blockRange := xtime.Range{Start: block.StartTime(), End: block.EndTime()}
+		if !queryRange.Overlaps(blockRange) {
+			continue
+		}
+
+		queryRange = queryRange.RemoveRange(blockRange)
+		blocks = append(blocks, block)
+	}
+	numRollupBlocks := len(blocks)
 
 	// Iterate known blocks in a defined order of time (newest first) to enforce
 	// some determinism about the results returned.
@@ -1019,6 +1651,13 @@ func (i *nsIndex) blocksForQueryWithRLock(queryRange xtime.Ranges) ([]index.Bloc
 		blocks = append(blocks, block)
 	}
 
+	if numRollupBlocks > 0 {
+		// Maintain the newest first ordering across both sets of blocks.
+		sort.SliceStable(blocks, func(i, j int) bool {
+			return blocks[i].StartTime().After(blocks[j].StartTime())
+		})
+	}
+
 	return blocks, nil
 }
 
@@ -1072,6 +1711,104 @@ func (i *nsIndex) ensureBlockPresentWithRLock(blockStart time.Time) (index.Block
 	return block, nil
 }
 
+// rollupBlockSizeForResults returns the size of the rollup block that the
+// bootstrapped results must be added to if they were read from a rollup
+// volume, i.e. they fulfill ranges past the end of the index block.
+func (i *nsIndex) rollupBlockSizeForResults(results result.IndexBlock) (time.Duration, bool) {
+	min, max := results.Fulfilled().MinMax()
+	blockStart := results.BlockStart()
+	if results.Fulfilled().IsEmpty() || !max.After(blockStart.Add(i.blockSize)) {
+		return 0, false
+	}
+
+	// Bootstrapped rollup volumes may have been written using a
+	// different rollup block size to the one currently configured.
+	rollupBlockSize := i.rollupBlockSize
+	if rollupBlockSize <= 0 || min.Before(blockStart) ||
+		max.After(blockStart.Add(rollupBlockSize)) {
+		rollupBlockSize = max.Sub(blockStart)
+		if rem := rollupBlockSize % i.blockSize; rem != 0 {
+			rollupBlockSize += i.blockSize - rem
+		}
+	}
+	return rollupBlockSize, true
+}
+
+// ensureRollupBlockPresentWithRLock guarantees a rollup index.Block exists
+// for the specified blockStart, allocating one if it does not.
+func (i *nsIndex) ensureRollupBlockPresentWithRLock(
+	blockStart time.Time,
+	blockSize time.Duration,
+) (index.Block, error) {
+	blockStartNanos := xtime.ToUnixNano(blockStart)
+	if block, ok := i.state.rollupBlocksByTime[blockStartNanos]; ok {
+		return block, nil
+	}
+
+	i.state.RUnlock()
+	i.state.Lock()
+	defer func() {
+		i.state.Unlock()
+		i.state.RLock()
+	}()
+
+	if block, ok := i.state.rollupBlocksByTime[blockStartNanos]; ok {
+		return block, nil
+	}
+
+	block, err := i.newRollupBlock(blockStart, blockSize)
+	if err != nil { // unable to allocate the block, should never happen.
+		return nil, i.unableToAllocBlockInvariantError(err)
+	}
+
+	i.state.rollupBlocksByTime[blockStartNanos] = block
+	i.updateBlockStartsWithLock()
+	return block, nil
+}
+
+// newRollupBlockWithSegment returns a new rollup block for the window that
+// holds the provided rolled up segment.
+func (i *nsIndex) newRollupBlockWithSegment(
+	windowStart time.Time,
+	rolledUp segment.Segment,
+	shards []databaseShard,
+) (index.Block, error) {
+	block, err := i.newRollupBlock(windowStart, i.rollupBlockSize)
+	if err != nil {
+		return nil, err
+	}
+
+	fulfilled := result.NewShardTimeRanges(windowStart, windowStart.Add(i.rollupBlockSize),
+		dbShards(shards).IDs()...)
+	results := result.NewIndexBlock(windowStart, []segment.Segment{rolledUp}, fulfilled)
+	if err := block.AddResults(results); err != nil {
+		block.Close()
+		return nil, err
+	}
+	return block, nil
+}
+
+// newRollupBlock returns a new sealed rollup block, rollup blocks never take
+// writes and only hold the segments of the blocks they replace.
+func (i *nsIndex) newRollupBlock(
+	blockStart time.Time,
+	blockSize time.Duration,
+) (index.Block, error) {
+	block, err := i.newRollupBlockFn(blockStart, blockSize, i.nsMetadata, i.opts.IndexOptions())
+	if err != nil {
+		return nil, err
+	}
+	if err := block.Seal(); err != nil {
+		block.Close()
+		return nil, err
+	}
+	if _, err := block.EvictMutableSegments(); err != nil {
+		block.Close()
+		return nil, err
+	}
+	return block, nil
+}
+
 func (i *nsIndex) updateBlockStartsWithLock() {
 	// update ordered blockStarts slice
 	var (
@@ -1095,6 +1832,16 @@ func (i *nsIndex) updateBlockStartsWithLock() {
 
 	// rotate latestBlock
 	i.state.latestBlock = latestBlock
+
+	// update ordered rollup blockStarts slice
+	rollupBlockStarts := make([]xtime.UnixNano, 0, len(i.state.rollupBlocksByTime))
+	for ts := range i.state.rollupBlocksByTime {
+		rollupBlockStarts = append(rollupBlockStarts, ts)
+	}
+	sort.Slice(rollupBlockStarts, func(i, j int) bool {
+		return rollupBlockStarts[i] > rollupBlockStarts[j]
+	})
+	i.state.rollupBlockStartsDescOrder = rollupBlockStarts
 }
 
 func (i *nsIndex) isOpenWithRLock() bool {
@@ -1121,6 +1868,11 @@ func (i *nsIndex) CleanupExpiredFileSets(t time.Time) error {
 			earliestBlockStartToRetain = t.ToTime()
 		}
 	}
+	for t := range i.state.rollupBlocksByTime {
+		if t.ToTime().Before(earliestBlockStartToRetain) {
+			earliestBlockStartToRetain = t.ToTime()
+		}
+	}
 
 	// know the earliest block to retain, find all blocks earlier than it
 	var (
@@ -1144,18 +1896,29 @@ func (i *nsIndex) Close() error {
 	}
 
 	i.state.closed = true
+	if i.compactCloseCh != nil {
+		close(i.compactCloseCh)
+	}
 
 	var multiErr xerrors.MultiError
 	multiErr = multiErr.Add(i.state.insertQueue.Stop())
 
-	blocks := make([]index.Block, 0, len(i.state.blocksByTime))
+	blocks := make([]index.Block, 0, len(i.state.blocksByTime)+
+		len(i.state.rollupBlocksByTime)+len(i.state.rolledUpBlocks))
 	for _, block := range i.state.blocksByTime {
 		blocks = append(blocks, block)
 	}
+	for _, block := range i.state.rollupBlocksByTime {
+		blocks = append(blocks, block)
+	}
+	blocks = append(blocks, i.state.rolledUpBlocks...)
 
 	i.state.latestBlock = nil
 	i.state.blocksByTime = nil
 	i.state.blockStartsDescOrder = nil
+	i.state.rollupBlocksByTime = nil
+	i.state.rollupBlockStartsDescOrder = nil
+	i.state.rolledUpBlocks = nil
 
 	if i.runtimeOptsListener != nil {
 		i.runtimeOptsListener.Close()
@@ -1165,6 +1928,11 @@ func (i *nsIndex) Close() error {
 	// Can now unlock after collecting blocks to close and setting closed state.
 	i.state.Unlock()
 
+	// Wait for the compactor to stop before closing blocks it may be compacting.
+	if i.compactDoneCh != nil {
+		<-i.compactDoneCh
+	}
+
 	// Wait for inflight queries to finish before closing blocks, do this
 	// outside of lock in case an inflight query needs to acquire a read lock
 	// to finish but can't acquire it because close was holding the lock waiting
@@ -1200,6 +1968,11 @@ type nsIndexMetrics struct {
 	QueryAfterClose             tally.Counter
 	InsertEndToEndLatency       tally.Timer
 	FlushEvictedMutableSegments tally.Counter
+	CompactedBlocks             tally.Counter
+	CompactedSegments           tally.Counter
+	CompactionErrors            tally.Counter
+	RolledUpBlocks              tally.Counter
+	RollupErrors                tally.Counter
 }
 
 func newNamespaceIndexMetrics(
@@ -1220,6 +1993,15 @@ func newNamespaceIndexMetrics(
 			scope.Timer("insert-end-to-end-latency"),
 			iopts.MetricsSamplingRate()),
 		FlushEvictedMutableSegments: scope.Counter("mutable-segment-evicted"),
+		CompactedBlocks:             scope.Counter("flushed-block-compacted"),
+		CompactedSegments:           scope.Counter("flushed-segment-compacted"),
+		CompactionErrors: scope.Tagged(map[string]string{
+			"error_type": "compaction",
+		}).Counter("index-error"),
+		RolledUpBlocks: scope.Counter("block-rolled-up"),
+		RollupErrors: scope.Tagged(map[string]string{
+			"error_type": "rollup",
+		}).Counter("index-error"),
 	}
 }
 
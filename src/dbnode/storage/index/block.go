@@ -45,11 +45,13 @@ var (
 	// ErrUnableToQueryBlockClosed is returned when querying closed block.
 	ErrUnableToQueryBlockClosed = errors.New("unable to query, index block is closed")
 
-	errUnableToWriteBlockClosed     = errors.New("unable to write, index block is closed")
-	errUnableToWriteBlockSealed     = errors.New("unable to write, index block is sealed")
-	errUnableToBootstrapBlockClosed = errors.New("unable to bootstrap, block is closed")
-	errUnableToTickBlockClosed      = errors.New("unable to tick, block is closed")
-	errBlockAlreadyClosed           = errors.New("unable to close, block already closed")
+	errUnableToWriteBlockClosed       = errors.New("unable to write, index block is closed")
+	errUnableToWriteBlockSealed       = errors.New("unable to write, index block is sealed")
+	errUnableToBootstrapBlockClosed   = errors.New("unable to bootstrap, block is closed")
+	errUnableToTickBlockClosed        = errors.New("unable to tick, block is closed")
+	errBlockAlreadyClosed             = errors.New("unable to close, block already closed")
+	errUnableToReplaceBlockNotSealed  = errors.New("unable to replace flushed segments, block is not sealed")
+	errUnableToReplaceSegmentsChanged = errors.New("unable to replace flushed segments, block segments have changed")
 
 	errUnableToSealBlockIllegalStateFmtString  = "unable to seal, index block state: %v"
 	errUnableToWriteBlockUnknownStateFmtString = "unable to write, unknown index block state: %v"
@@ -94,10 +96,18 @@ func NewBlock(
 	md namespace.Metadata,
 	opts Options,
 ) (Block, error) {
-	var (
-		blockSize = md.Options().IndexOptions().BlockSize()
-	)
+	return NewBlockWithSize(startTime, md.Options().IndexOptions().BlockSize(), md, opts)
+}
 
+// NewBlockWithSize returns a new Block covering the provided block size rather
+// than the namespace index block size, this is used for index blocks that have
+// been rolled up to a coarser granularity.
+func NewBlockWithSize(
+	startTime time.Time,
+	blockSize time.Duration,
+	md namespace.Metadata,
+	opts Options,
+) (Block, error) {
 	// FOLLOWUP(prateek): use this to track segments when we have multiple segments in a Block.
 	postingsOffset := postings.ID(0)
 	seg, err := mem.NewSegment(postingsOffset, opts.MemSegmentOptions())
@@ -494,6 +504,86 @@ func (b *block) EvictMutableSegments() (EvictMutableSegmentResults, error) {
 	return results, multiErr.FinalError()
 }
 
+func (b *block) FlushedSegments() []segment.Segment {
+	b.RLock()
+	defer b.RUnlock()
+	segments, ok := b.flushedSegmentsWithRLock()
+	if !ok {
+		return nil
+	}
+	return segments
+}
+
+// flushedSegmentsWithRLock returns the immutable segments held by the block
+// and whether the block is sealed and holds no mutable segments with data.
+func (b *block) flushedSegmentsWithRLock() ([]segment.Segment, bool) {
+	if b.state != blockStateSealed {
+		return nil, false
+	}
+	if b.activeSegment != nil && b.activeSegment.Size() > 0 {
+		return nil, false
+	}
+
+	var segments []segment.Segment
+	for _, group := range b.shardRangesSegments {
+		for _, seg := range group.segments {
+			if mutableSeg, ok := seg.(segment.MutableSegment); ok {
+				if mutableSeg.Size() > 0 {
+					// NB: only blocks whose data is wholly flushed can be compacted.
+					return nil, false
+				}
+				continue
+			}
+			segments = append(segments, seg)
+		}
+	}
+	return segments, true
+}
+
+func (b *block) ReplaceFlushedSegments(
+	replaced []segment.Segment,
+	replacement segment.Segment,
+) error {
+	b.Lock()
+	defer b.Unlock()
+	if b.state != blockStateSealed {
+		return errUnableToReplaceBlockNotSealed
+	}
+
+	// Ensure the segments were not swapped from underneath us (i.e. by a
+	// bootstrap adding results) while the replacement was being built.
+	current, ok := b.flushedSegmentsWithRLock()
+	if !ok || len(current) != len(replaced) {
+		return errUnableToReplaceSegmentsChanged
+	}
+	for idx := range current {
+		if current[idx] != replaced[idx] {
+			return errUnableToReplaceSegmentsChanged
+		}
+	}
+
+	fulfilled := make(result.ShardTimeRanges)
+	for _, group := range b.shardRangesSegments {
+		fulfilled.AddRanges(group.shardTimeRanges)
+	}
+
+	// Queries hold the read lock for their entire duration so it is
+	// safe to close the replaced segments while holding the write lock.
+	var multiErr xerrors.MultiError
+	for i := range b.shardRangesSegments {
+		for _, seg := range b.shardRangesSegments[i].segments {
+			multiErr = multiErr.Add(seg.Close())
+		}
+		b.shardRangesSegments[i] = blockShardRangesSegments{}
+	}
+	b.shardRangesSegments = append(b.shardRangesSegments[:0], blockShardRangesSegments{
+		shardTimeRanges: fulfilled,
+		segments:        []segment.Segment{replacement},
+	})
+
+	return multiErr.FinalError()
+}
+
 func (b *block) Close() error {
 	b.Lock()
 	defer b.Unlock()
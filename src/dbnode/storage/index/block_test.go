@@ -1184,6 +1184,107 @@ func TestBlockEvictMutableSegmentsAddResults(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestBlockFlushedSegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(time.Hour)
+	blk, err := NewBlock(start, testMD, testOpts)
+	require.NoError(t, err)
+
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	seg1 := segment.NewMockSegment(ctrl)
+	seg2 := segment.NewMockSegment(ctrl)
+	require.NoError(t, b.AddResults(
+		result.NewIndexBlock(start, []segment.Segment{seg1},
+			result.NewShardTimeRanges(start, start.Add(time.Hour), 1, 2))))
+	require.NoError(t, b.AddResults(
+		result.NewIndexBlock(start, []segment.Segment{seg2},
+			result.NewShardTimeRanges(start, start.Add(time.Hour), 3))))
+
+	// not sealed so no flushed segments
+	require.Nil(t, b.FlushedSegments())
+
+	require.NoError(t, b.Seal())
+	require.Equal(t, []segment.Segment{seg1, seg2}, b.FlushedSegments())
+
+	// any mutable segments prevent the segments being considered flushed
+	seg3 := segment.NewMockMutableSegment(ctrl)
+	seg3.EXPECT().Seal().Return(seg3, nil)
+	seg3.EXPECT().Size().Return(int64(1)).AnyTimes()
+	require.NoError(t, b.AddResults(
+		result.NewIndexBlock(start, []segment.Segment{seg3},
+			result.NewShardTimeRanges(start, start.Add(time.Hour), 4))))
+	require.Nil(t, b.FlushedSegments())
+}
+
+func TestBlockReplaceFlushedSegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(time.Hour)
+	blk, err := NewBlock(start, testMD, testOpts)
+	require.NoError(t, err)
+
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	seg1 := segment.NewMockSegment(ctrl)
+	seg2 := segment.NewMockSegment(ctrl)
+	seg3 := segment.NewMockSegment(ctrl)
+	require.Error(t, b.ReplaceFlushedSegments(nil, seg3))
+
+	require.NoError(t, b.AddResults(
+		result.NewIndexBlock(start, []segment.Segment{seg1},
+			result.NewShardTimeRanges(start, start.Add(time.Hour), 1, 2))))
+	require.NoError(t, b.AddResults(
+		result.NewIndexBlock(start, []segment.Segment{seg2},
+			result.NewShardTimeRanges(start, start.Add(time.Hour), 3))))
+	require.NoError(t, b.Seal())
+
+	seg1.EXPECT().Close().Return(nil)
+	seg2.EXPECT().Close().Return(nil)
+	require.NoError(t, b.ReplaceFlushedSegments([]segment.Segment{seg1, seg2}, seg3))
+
+	require.Equal(t, 1, len(b.shardRangesSegments))
+	require.Equal(t, []segment.Segment{seg3}, b.shardRangesSegments[0].segments)
+	require.Equal(t,
+		result.NewShardTimeRanges(start, start.Add(time.Hour), 1, 2, 3).SummaryString(),
+		b.shardRangesSegments[0].shardTimeRanges.SummaryString())
+	require.Equal(t, []segment.Segment{seg3}, b.FlushedSegments())
+}
+
+func TestBlockReplaceFlushedSegmentsChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testMD := newTestNSMetadata(t)
+	start := time.Now().Truncate(time.Hour)
+	blk, err := NewBlock(start, testMD, testOpts)
+	require.NoError(t, err)
+
+	b, ok := blk.(*block)
+	require.True(t, ok)
+
+	seg1 := segment.NewMockSegment(ctrl)
+	seg2 := segment.NewMockSegment(ctrl)
+	seg3 := segment.NewMockSegment(ctrl)
+	require.NoError(t, b.AddResults(
+		result.NewIndexBlock(start, []segment.Segment{seg1, seg2},
+			result.NewShardTimeRanges(start, start.Add(time.Hour), 1, 2))))
+	require.NoError(t, b.Seal())
+
+	require.Equal(t, errUnableToReplaceSegmentsChanged,
+		b.ReplaceFlushedSegments([]segment.Segment{seg1}, seg3))
+	require.Equal(t, errUnableToReplaceSegmentsChanged,
+		b.ReplaceFlushedSegments([]segment.Segment{seg2, seg1}, seg3))
+	require.Equal(t, []segment.Segment{seg1, seg2}, b.FlushedSegments())
+}
+
 func TestBlockE2EInsertQuery(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
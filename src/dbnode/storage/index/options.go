@@ -22,6 +22,7 @@ package index
 
 import (
 	"errors"
+	"time"
 
 	"github.com/m3db/m3/src/dbnode/clock"
 	"github.com/m3db/m3/src/m3ninx/doc"
@@ -35,6 +36,18 @@ const (
 	// defaultIndexInsertMode sets the default indexing mode to synchronous.
 	defaultIndexInsertMode = InsertSync
 
+	// defaultCompactFlushedSegments sets the default for whether flushed index
+	// block segments are compacted into a single segment.
+	defaultCompactFlushedSegments = true
+
+	// defaultCompactFlushedSegmentsInterval sets the default interval at which
+	// the segments of flushed index blocks are checked for compaction.
+	defaultCompactFlushedSegmentsInterval = time.Minute
+
+	// defaultCompactFlushedSegmentsConcurrency sets the default number of
+	// flushed index blocks that are compacted concurrently.
+	defaultCompactFlushedSegmentsConcurrency = 1
+
 	// documentArrayPool size in general: 256*256*sizeof(doc.Document)
 	// = 256 * 256 * 16
 	// = 1mb (but with Go's heap probably 2mb)
@@ -49,17 +62,22 @@ var (
 	errOptionsBytesPoolUnspecified      = errors.New("checkedbytes pool is unset")
 	errOptionsResultsPoolUnspecified    = errors.New("results pool is unset")
 	errIDGenerationDisabled             = errors.New("id generation is disabled")
+	errOptionsCompactIntervalInvalid    = errors.New("compact flushed segments interval must be positive")
+	errOptionsCompactConcurrencyInvalid = errors.New("compact flushed segments concurrency must be positive")
 )
 
 type opts struct {
-	insertMode     InsertMode
-	clockOpts      clock.Options
-	instrumentOpts instrument.Options
-	memOpts        mem.Options
-	idPool         ident.Pool
-	bytesPool      pool.CheckedBytesPool
-	resultsPool    ResultsPool
-	docArrayPool   doc.DocumentArrayPool
+	insertMode                        InsertMode
+	compactFlushedSegments            bool
+	compactFlushedSegmentsInterval    time.Duration
+	compactFlushedSegmentsConcurrency int
+	clockOpts                         clock.Options
+	instrumentOpts                    instrument.Options
+	memOpts                           mem.Options
+	idPool                            ident.Pool
+	bytesPool                         pool.CheckedBytesPool
+	resultsPool                       ResultsPool
+	docArrayPool                      doc.DocumentArrayPool
 }
 
 var undefinedUUIDFn = func() ([]byte, error) { return nil, errIDGenerationDisabled }
@@ -84,14 +102,17 @@ func NewOptions() Options {
 	docArrayPool.Init()
 
 	opts := &opts{
-		insertMode:     defaultIndexInsertMode,
-		clockOpts:      clock.NewOptions(),
-		instrumentOpts: instrument.NewOptions(),
-		memOpts:        mem.NewOptions().SetNewUUIDFn(undefinedUUIDFn),
-		bytesPool:      bytesPool,
-		idPool:         idPool,
-		resultsPool:    resultsPool,
-		docArrayPool:   docArrayPool,
+		insertMode:                        defaultIndexInsertMode,
+		compactFlushedSegments:            defaultCompactFlushedSegments,
+		compactFlushedSegmentsInterval:    defaultCompactFlushedSegmentsInterval,
+		compactFlushedSegmentsConcurrency: defaultCompactFlushedSegmentsConcurrency,
+		clockOpts:                         clock.NewOptions(),
+		instrumentOpts:                    instrument.NewOptions(),
+		memOpts:                           mem.NewOptions().SetNewUUIDFn(undefinedUUIDFn),
+		bytesPool:                         bytesPool,
+		idPool:                            idPool,
+		resultsPool:                       resultsPool,
+		docArrayPool:                      docArrayPool,
 	}
 	resultsPool.Init(func() Results { return NewResults(opts) })
 	return opts
@@ -107,6 +128,12 @@ func (o *opts) Validate() error {
 	if o.resultsPool == nil {
 		return errOptionsResultsPoolUnspecified
 	}
+	if o.compactFlushedSegmentsInterval <= 0 {
+		return errOptionsCompactIntervalInvalid
+	}
+	if o.compactFlushedSegmentsConcurrency <= 0 {
+		return errOptionsCompactConcurrencyInvalid
+	}
 	return nil
 }
 
@@ -120,6 +147,36 @@ func (o *opts) InsertMode() InsertMode {
 	return o.insertMode
 }
 
+func (o *opts) SetCompactFlushedSegments(value bool) Options {
+	opts := *o
+	opts.compactFlushedSegments = value
+	return &opts
+}
+
+func (o *opts) CompactFlushedSegments() bool {
+	return o.compactFlushedSegments
+}
+
+func (o *opts) SetCompactFlushedSegmentsInterval(value time.Duration) Options {
+	opts := *o
+	opts.compactFlushedSegmentsInterval = value
+	return &opts
+}
+
+func (o *opts) CompactFlushedSegmentsInterval() time.Duration {
+	return o.compactFlushedSegmentsInterval
+}
+
+func (o *opts) SetCompactFlushedSegmentsConcurrency(value int) Options {
+	opts := *o
+	opts.compactFlushedSegmentsConcurrency = value
+	return &opts
+}
+
+func (o *opts) CompactFlushedSegmentsConcurrency() int {
+	return o.compactFlushedSegmentsConcurrency
+}
+
 func (o *opts) SetClockOptions(value clock.Options) Options {
 	opts := *o
 	opts.clockOpts = value
@@ -29,6 +29,7 @@ import (
 	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/idx"
+	"github.com/m3db/m3/src/m3ninx/index/segment"
 	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
 	"github.com/m3db/m3x/context"
 	"github.com/m3db/m3x/ident"
@@ -186,6 +187,17 @@ type Block interface {
 	// data the mutable segments should have held at this time.
 	EvictMutableSegments() (EvictMutableSegmentResults, error)
 
+	// FlushedSegments returns the immutable segments held by the block, it
+	// returns nil unless the block is sealed and holds no mutable segments
+	// that are not empty.
+	FlushedSegments() []segment.Segment
+
+	// ReplaceFlushedSegments swaps the provided flushed segments for a single
+	// replacement segment that covers the same data, the replaced segments are
+	// closed. It returns an error if the block's segments no longer match the
+	// segments to replace, in which case the replacement is not taken ownership of.
+	ReplaceFlushedSegments(replaced []segment.Segment, replacement segment.Segment) error
+
 	// Close will release any held resources and close the Block.
 	Close() error
 }
@@ -533,6 +545,30 @@ type Options interface {
 	// IndexInsertMode returns the index's insert mode (sync/async).
 	InsertMode() InsertMode
 
+	// SetCompactFlushedSegments sets whether the segments of flushed index
+	// blocks are compacted into a single segment in the background.
+	SetCompactFlushedSegments(value bool) Options
+
+	// CompactFlushedSegments returns whether the segments of flushed index
+	// blocks are compacted into a single segment in the background.
+	CompactFlushedSegments() bool
+
+	// SetCompactFlushedSegmentsInterval sets the interval at which the segments
+	// of flushed index blocks are checked for compaction.
+	SetCompactFlushedSegmentsInterval(value time.Duration) Options
+
+	// CompactFlushedSegmentsInterval returns the interval at which the segments
+	// of flushed index blocks are checked for compaction.
+	CompactFlushedSegmentsInterval() time.Duration
+
+	// SetCompactFlushedSegmentsConcurrency sets the maximum number of flushed
+	// index blocks that are compacted concurrently.
+	SetCompactFlushedSegmentsConcurrency(value int) Options
+
+	// CompactFlushedSegmentsConcurrency returns the maximum number of flushed
+	// index blocks that are compacted concurrently.
+	CompactFlushedSegmentsConcurrency() int
+
 	// SetClockOptions sets the clock options.
 	SetClockOptions(value clock.Options) Options
 
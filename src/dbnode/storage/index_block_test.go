@@ -511,3 +511,89 @@ func TestNamespaceIndexBlockQuery(t *testing.T) {
 	_, err = idx.Query(ctx, q, qOpts)
 	require.NoError(t, err)
 }
+
+func TestNamespaceIndexBlockQueryRollupBlock(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{t})
+	defer ctrl.Finish()
+
+	retention := 4 * time.Hour
+	blockSize := time.Hour
+	rollupBlockSize := 2 * blockSize
+	now := time.Now().Truncate(blockSize).Add(10 * time.Minute)
+	t0 := now.Truncate(blockSize)
+	tRollup := t0.Add(-rollupBlockSize)
+	tRollupNanos := xtime.ToUnixNano(tRollup)
+	var nowLock sync.Mutex
+	nowFn := func() time.Time {
+		nowLock.Lock()
+		defer nowLock.Unlock()
+		return now
+	}
+	opts := testDatabaseOptions()
+	opts = opts.SetClockOptions(opts.ClockOptions().SetNowFn(nowFn))
+
+	b0 := index.NewMockBlock(ctrl)
+	b0.EXPECT().StartTime().Return(t0).AnyTimes()
+	b0.EXPECT().EndTime().Return(t0.Add(blockSize)).AnyTimes()
+	newBlockFn := func(ts time.Time, md namespace.Metadata, io index.Options) (index.Block, error) {
+		if ts.Equal(t0) {
+			return b0, nil
+		}
+		panic("should never get here")
+	}
+	md := testNamespaceMetadata(blockSize, retention)
+	nsIdx, err := newNamespaceIndexWithNewBlockFn(md, newBlockFn, opts)
+	require.NoError(t, err)
+
+	rb := index.NewMockBlock(ctrl)
+	rb.EXPECT().StartTime().Return(tRollup).AnyTimes()
+	rb.EXPECT().EndTime().Return(t0).AnyTimes()
+	idx := nsIdx.(*nsIndex)
+	idx.rollupBlockSize = rollupBlockSize
+	idx.newRollupBlockFn = func(
+		ts time.Time,
+		size time.Duration,
+		md namespace.Metadata,
+		io index.Options,
+	) (index.Block, error) {
+		require.True(t, ts.Equal(tRollup))
+		require.Equal(t, rollupBlockSize, size)
+		return rb, nil
+	}
+
+	// results read from a rollup volume fulfill the whole rollup block
+	seg1 := segment.NewMockSegment(ctrl)
+	bootstrapResults := result.IndexResults{
+		tRollupNanos: result.NewIndexBlock(tRollup, []segment.Segment{seg1},
+			result.NewShardTimeRanges(tRollup, t0, 1, 2, 3)),
+	}
+
+	rb.EXPECT().Seal().Return(nil)
+	rb.EXPECT().EvictMutableSegments().Return(index.EvictMutableSegmentResults{}, nil)
+	rb.EXPECT().AddResults(bootstrapResults[tRollupNanos]).Return(nil)
+	require.NoError(t, idx.Bootstrap(bootstrapResults))
+	require.Equal(t, rb, idx.state.rollupBlocksByTime[tRollupNanos])
+	_, ok := idx.state.blocksByTime[tRollupNanos]
+	require.False(t, ok)
+
+	// only queries the rollup block when the range is covered by it
+	ctx := context.NewContext()
+	q := index.Query{}
+	qOpts := index.QueryOptions{
+		StartInclusive: tRollup,
+		EndExclusive:   tRollup.Add(blockSize + time.Minute),
+	}
+	rb.EXPECT().Query(q, qOpts, gomock.Any()).Return(true, nil)
+	_, err = idx.Query(ctx, q, qOpts)
+	require.NoError(t, err)
+
+	// queries both the rollup block and the latest block if needed
+	qOpts = index.QueryOptions{
+		StartInclusive: tRollup,
+		EndExclusive:   now.Add(time.Minute),
+	}
+	b0.EXPECT().Query(q, qOpts, gomock.Any()).Return(true, nil)
+	rb.EXPECT().Query(q, qOpts, gomock.Any()).Return(true, nil)
+	_, err = idx.Query(ctx, q, qOpts)
+	require.NoError(t, err)
+}
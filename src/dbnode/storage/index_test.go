@@ -21,22 +21,30 @@
 package storage
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
+	indexproto "github.com/m3db/m3/src/dbnode/generated/proto/index"
 	"github.com/m3db/m3/src/dbnode/persist"
+	"github.com/m3db/m3/src/dbnode/persist/fs"
 	"github.com/m3db/m3/src/dbnode/retention"
 	"github.com/m3db/m3/src/dbnode/storage/block"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
 	"github.com/m3db/m3/src/dbnode/storage/index"
 	"github.com/m3db/m3/src/dbnode/storage/namespace"
+	"github.com/m3db/m3/src/m3ninx/doc"
 	"github.com/m3db/m3/src/m3ninx/idx"
 	"github.com/m3db/m3/src/m3ninx/index/segment"
+	"github.com/m3db/m3/src/m3ninx/index/segment/mem"
+	"github.com/m3db/m3/src/m3ninx/postings"
 	"github.com/m3db/m3x/context"
 	"github.com/m3db/m3x/ident"
 	xtest "github.com/m3db/m3x/test"
 	xtime "github.com/m3db/m3x/time"
 
+	"github.com/fortytw2/leaktest"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -249,6 +257,288 @@ func TestNamespaceIndexFlushSuccessMultipleShards(t *testing.T) {
 	require.True(t, persistClosed)
 }
 
+func TestNamespaceIndexCompactFlushedBlocks(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{t})
+	defer ctrl.Finish()
+
+	test := newTestIndex(t, ctrl)
+
+	now := time.Now().Truncate(test.indexBlockSize)
+	idx := test.index.(*nsIndex)
+
+	mockBlock := index.NewMockBlock(ctrl)
+	blockTime := now.Add(-2 * test.indexBlockSize)
+	mockBlock.EXPECT().StartTime().Return(blockTime).AnyTimes()
+	mockBlock.EXPECT().EndTime().Return(blockTime.Add(test.indexBlockSize)).AnyTimes()
+	idx.state.blocksByTime[xtime.ToUnixNano(blockTime)] = mockBlock
+
+	seg1 := newTestIndexSegment(t, test.opts.IndexOptions(), "foo", "bar")
+	seg2 := newTestIndexSegment(t, test.opts.IndexOptions(), "bar", "baz")
+	segments := []segment.Segment{seg1, seg2}
+	mockBlock.EXPECT().FlushedSegments().Return(segments).Times(2)
+
+	volume0 := fs.FileSetFileIdentifier{BlockStart: blockTime, VolumeIndex: 0}
+	volume1 := fs.FileSetFileIdentifier{BlockStart: blockTime, VolumeIndex: 1}
+	idx.indexFilesetsAtFn = func(dir string, nsID ident.ID, blockStart time.Time) (fs.FileSetFilesSlice, error) {
+		require.True(t, blockStart.Equal(blockTime))
+		return fs.FileSetFilesSlice{
+			{ID: volume0, AbsoluteFilepaths: []string{"abc"}},
+			{ID: volume1, AbsoluteFilepaths: []string{"def"}},
+		}, nil
+	}
+	// The compacted volume covers the shards of the volumes it replaces, the
+	// volume of another block is neither read nor replaced.
+	idx.readIndexInfoFilesFn = func(dir string, nsID ident.ID, bufferSize int) []fs.ReadIndexInfoFileResult {
+		return []fs.ReadIndexInfoFileResult{
+			newTestIndexInfoFileResult(volume0, []uint32{0}, nil),
+			newTestIndexInfoFileResult(volume1, []uint32{0, 2}, nil),
+			newTestIndexInfoFileResult(fs.FileSetFileIdentifier{
+				BlockStart: blockTime.Add(test.indexBlockSize),
+			}, []uint32{3}, nil),
+		}
+	}
+	var deleted []string
+	idx.deleteFilesFn = func(s []string) error {
+		deleted = s
+		return nil
+	}
+
+	var persistedIDs []string
+	compacted := segment.NewMockSegment(ctrl)
+	preparedPersist := persist.PreparedIndexPersist{
+		Close: func() ([]segment.Segment, error) {
+			return []segment.Segment{compacted}, nil
+		},
+		Persist: func(seg segment.MutableSegment) error {
+			for _, id := range []string{"foo", "bar", "baz"} {
+				exists, err := seg.ContainsID([]byte(id))
+				require.NoError(t, err)
+				if exists {
+					persistedIDs = append(persistedIDs, id)
+				}
+			}
+			require.Equal(t, int64(3), seg.Size())
+			return nil
+		},
+	}
+	mockFlush := persist.NewMockIndexFlush(ctrl)
+	mockFlush.EXPECT().PrepareIndex(xtest.CmpMatcher(persist.IndexPrepareOptions{
+		NamespaceMetadata: test.metadata,
+		BlockStart:        blockTime,
+		FileSetType:       persist.FileSetFlushType,
+		Shards:            map[uint32]struct{}{0: struct{}{}, 2: struct{}{}},
+		BlockSize:         test.indexBlockSize,
+	})).Return(preparedPersist, nil)
+	mockFlush.EXPECT().DoneIndex().Return(nil)
+	mockBlock.EXPECT().ReplaceFlushedSegments(segments, compacted).Return(nil)
+
+	mockPersistManager := persist.NewMockManager(ctrl)
+	mockPersistManager.EXPECT().StartIndexPersist().Return(mockFlush, nil)
+	idx.newPersistManagerFn = func() (persist.Manager, error) {
+		return mockPersistManager, nil
+	}
+
+	idx.compactFlushedBlocks()
+	require.Equal(t, []string{"foo", "bar", "baz"}, persistedIDs)
+	require.Equal(t, []string{"abc", "def"}, deleted)
+
+	// The persist manager is reused by subsequent compactions.
+	require.Equal(t, 1, len(idx.compactPersistManagers))
+}
+
+func TestNamespaceIndexCompactFlushedBlocksRequiresVolumeShards(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{t})
+	defer ctrl.Finish()
+
+	test := newTestIndex(t, ctrl)
+
+	now := time.Now().Truncate(test.indexBlockSize)
+	idx := test.index.(*nsIndex)
+
+	mockBlock := index.NewMockBlock(ctrl)
+	blockTime := now.Add(-2 * test.indexBlockSize)
+	mockBlock.EXPECT().StartTime().Return(blockTime).AnyTimes()
+	idx.state.blocksByTime[xtime.ToUnixNano(blockTime)] = mockBlock
+
+	seg1 := newTestIndexSegment(t, test.opts.IndexOptions(), "foo")
+	seg2 := newTestIndexSegment(t, test.opts.IndexOptions(), "bar")
+	mockBlock.EXPECT().FlushedSegments().Return([]segment.Segment{seg1, seg2}).Times(2)
+
+	volume := fs.FileSetFileIdentifier{BlockStart: blockTime}
+	idx.indexFilesetsAtFn = func(dir string, nsID ident.ID, blockStart time.Time) (fs.FileSetFilesSlice, error) {
+		return fs.FileSetFilesSlice{{ID: volume, AbsoluteFilepaths: []string{"abc"}}}, nil
+	}
+	// The block is not compacted if the shards of a volume it replaces are unknown.
+	idx.readIndexInfoFilesFn = func(dir string, nsID ident.ID, bufferSize int) []fs.ReadIndexInfoFileResult {
+		return []fs.ReadIndexInfoFileResult{
+			newTestIndexInfoFileResult(volume, nil, errors.New("corrupt info file")),
+		}
+	}
+	idx.deleteFilesFn = func(s []string) error {
+		require.FailNow(t, "unexpected files deletion")
+		return nil
+	}
+
+	mockFlush := persist.NewMockIndexFlush(ctrl)
+	mockFlush.EXPECT().DoneIndex().Return(nil)
+	mockPersistManager := persist.NewMockManager(ctrl)
+	mockPersistManager.EXPECT().StartIndexPersist().Return(mockFlush, nil)
+	idx.newPersistManagerFn = func() (persist.Manager, error) {
+		return mockPersistManager, nil
+	}
+
+	idx.compactFlushedBlocks()
+}
+
+func TestNamespaceIndexCompactorStopsOnClose(t *testing.T) {
+	defer leaktest.CheckTimeout(t, time.Second)()
+
+	md := testNamespaceMetadata(time.Hour, time.Hour*8)
+	opts := testDatabaseOptions()
+	opts = opts.SetIndexOptions(opts.IndexOptions().
+		SetCompactFlushedSegments(true).
+		SetCompactFlushedSegmentsInterval(time.Millisecond))
+	idx, err := newNamespaceIndex(md, opts)
+	require.NoError(t, err)
+
+	// Allow the compactor to tick before closing the index.
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, idx.Close())
+}
+
+func TestNamespaceIndexFlushRollsUpBlocks(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{t})
+	defer ctrl.Finish()
+
+	test := newTestIndex(t, ctrl)
+
+	rollupBlockSize := 2 * test.indexBlockSize
+	now := time.Now().Truncate(test.indexBlockSize)
+	windowStart := now.Truncate(rollupBlockSize).Add(-2 * rollupBlockSize)
+	windowStartNanos := xtime.ToUnixNano(windowStart)
+	idx := test.index.(*nsIndex)
+	idx.rollupBlockSize = rollupBlockSize
+
+	var (
+		blocks    []*index.MockBlock
+		segments  []segment.Segment
+		filesets  = make(map[xtime.UnixNano]fs.FileSetFile)
+		infoFiles []fs.ReadIndexInfoFileResult
+	)
+	for i, id := range []string{"foo", "bar"} {
+		blockTime := windowStart.Add(time.Duration(i) * test.indexBlockSize)
+		seg := newTestIndexSegment(t, test.opts.IndexOptions(), id)
+		mockBlock := index.NewMockBlock(ctrl)
+		mockBlock.EXPECT().StartTime().Return(blockTime).AnyTimes()
+		mockBlock.EXPECT().EndTime().Return(blockTime.Add(test.indexBlockSize)).AnyTimes()
+		mockBlock.EXPECT().IsSealed().Return(true).AnyTimes()
+		mockBlock.EXPECT().NeedsMutableSegmentsEvicted().Return(false).AnyTimes()
+		mockBlock.EXPECT().FlushedSegments().Return([]segment.Segment{seg}).AnyTimes()
+		idx.state.blocksByTime[xtime.ToUnixNano(blockTime)] = mockBlock
+		blocks = append(blocks, mockBlock)
+		segments = append(segments, seg)
+		volume := fs.FileSetFileIdentifier{BlockStart: blockTime}
+		filesets[xtime.ToUnixNano(blockTime)] = fs.FileSetFile{
+			ID:                volume,
+			AbsoluteFilepaths: []string{id + "-info", id + "-data"},
+		}
+		// The rollup volume covers the shards of the volumes it replaces,
+		// including shards that are no longer owned.
+		infoFiles = append(infoFiles, newTestIndexInfoFileResult(volume, []uint32{uint32(i)}, nil))
+	}
+
+	mockShard := NewMockdatabaseShard(ctrl)
+	mockShard.EXPECT().ID().Return(uint32(0)).AnyTimes()
+	shards := []databaseShard{mockShard}
+
+	idx.indexFilesetsAtFn = func(dir string, nsID ident.ID, blockStart time.Time) (fs.FileSetFilesSlice, error) {
+		fileset, ok := filesets[xtime.ToUnixNano(blockStart)]
+		if !ok {
+			return nil, nil
+		}
+		return fs.FileSetFilesSlice{fileset}, nil
+	}
+	idx.readIndexInfoFilesFn = func(dir string, nsID ident.ID, bufferSize int) []fs.ReadIndexInfoFileResult {
+		return infoFiles
+	}
+	var deleted []string
+	idx.deleteFilesFn = func(s []string) error {
+		deleted = append(deleted, s...)
+		return nil
+	}
+
+	var persistedIDs []string
+	rolledUp := segment.NewMockSegment(ctrl)
+	preparedPersist := persist.PreparedIndexPersist{
+		Close: func() ([]segment.Segment, error) {
+			return []segment.Segment{rolledUp}, nil
+		},
+		Persist: func(seg segment.MutableSegment) error {
+			for _, id := range []string{"foo", "bar"} {
+				exists, err := seg.ContainsID([]byte(id))
+				require.NoError(t, err)
+				if exists {
+					persistedIDs = append(persistedIDs, id)
+				}
+			}
+			return nil
+		},
+	}
+	mockFlush := persist.NewMockIndexFlush(ctrl)
+	mockFlush.EXPECT().PrepareIndex(xtest.CmpMatcher(persist.IndexPrepareOptions{
+		NamespaceMetadata: test.metadata,
+		BlockStart:        windowStart,
+		FileSetType:       persist.FileSetFlushType,
+		Shards:            map[uint32]struct{}{0: struct{}{}, 1: struct{}{}},
+		BlockSize:         rollupBlockSize,
+	})).Return(preparedPersist, nil)
+
+	rollupBlock := index.NewMockBlock(ctrl)
+	rollupBlock.EXPECT().StartTime().Return(windowStart).AnyTimes()
+	rollupBlock.EXPECT().EndTime().Return(windowStart.Add(rollupBlockSize)).AnyTimes()
+	rollupBlock.EXPECT().FlushedSegments().Return([]segment.Segment{rolledUp}).AnyTimes()
+	rollupBlock.EXPECT().Seal().Return(nil)
+	rollupBlock.EXPECT().EvictMutableSegments().Return(index.EvictMutableSegmentResults{}, nil)
+	rollupBlock.EXPECT().AddResults(gomock.Any()).DoAndReturn(func(r result.IndexBlock) error {
+		require.True(t, r.BlockStart().Equal(windowStart))
+		require.Equal(t, []segment.Segment{rolledUp}, r.Segments())
+		return nil
+	})
+	idx.newRollupBlockFn = func(
+		blockStart time.Time,
+		blockSize time.Duration,
+		md namespace.Metadata,
+		opts index.Options,
+	) (index.Block, error) {
+		require.True(t, blockStart.Equal(windowStart))
+		require.Equal(t, rollupBlockSize, blockSize)
+		return rollupBlock, nil
+	}
+
+	require.NoError(t, idx.Flush(mockFlush, shards))
+	require.Equal(t, []string{"foo", "bar"}, persistedIDs)
+	require.Equal(t, []string{"foo-info", "foo-data", "bar-info", "bar-data"}, deleted)
+
+	idx.state.RLock()
+	require.Equal(t, rollupBlock, idx.state.rollupBlocksByTime[windowStartNanos])
+	for _, block := range blocks {
+		_, ok := idx.state.blocksByTime[xtime.ToUnixNano(block.StartTime())]
+		require.False(t, ok)
+	}
+	require.Equal(t, 2, len(idx.state.rolledUpBlocks))
+	idx.state.RUnlock()
+
+	// The blocks that were rolled up are closed on the next tick.
+	c := context.NewCancellable()
+	for _, block := range blocks {
+		block.EXPECT().Close().Return(nil)
+	}
+	rollupBlock.EXPECT().Tick(c, gomock.Any()).Return(index.BlockTickResult{}, nil)
+	_, err := idx.Tick(c, now)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(idx.state.rolledUpBlocks))
+}
+
 func TestNamespaceIndexQueryNoMatchingBlocks(t *testing.T) {
 	ctrl := gomock.NewController(xtest.Reporter{t})
 	defer ctrl.Finish()
@@ -278,6 +568,40 @@ func TestNamespaceIndexQueryNoMatchingBlocks(t *testing.T) {
 	assert.Equal(t, 0, result.Results.Size())
 }
 
+func newTestIndexSegment(t *testing.T, opts index.Options, ids ...string) segment.Segment {
+	seg, err := mem.NewSegment(postings.ID(0), opts.MemSegmentOptions())
+	require.NoError(t, err)
+	for _, id := range ids {
+		_, err := seg.Insert(doc.Document{
+			ID:     []byte(id),
+			Fields: []doc.Field{{Name: []byte("name"), Value: []byte(id)}},
+		})
+		require.NoError(t, err)
+	}
+	_, err = seg.Seal()
+	require.NoError(t, err)
+	return seg
+}
+
+type testInfoFileResultError struct {
+	err error
+}
+
+func (e testInfoFileResultError) Error() error     { return e.err }
+func (e testInfoFileResultError) Filepath() string { return "info" }
+
+func newTestIndexInfoFileResult(
+	id fs.FileSetFileIdentifier,
+	shards []uint32,
+	err error,
+) fs.ReadIndexInfoFileResult {
+	return fs.ReadIndexInfoFileResult{
+		ID:   id,
+		Info: indexproto.IndexInfo{Shards: shards},
+		Err:  testInfoFileResultError{err: err},
+	}
+}
+
 type testIndex struct {
 	index          namespaceIndex
 	metadata       namespace.Metadata
@@ -302,6 +626,8 @@ func newTestIndex(t *testing.T, ctrl *gomock.Controller) testIndex {
 	md, err := namespace.NewMetadata(ident.StringID("testns"), nopts)
 	require.NoError(t, err)
 	opts := testDatabaseOptions()
+	// Compaction is driven explicitly by tests rather than in the background.
+	opts = opts.SetIndexOptions(opts.IndexOptions().SetCompactFlushedSegments(false))
 	index, err := newNamespaceIndex(md, opts)
 	require.NoError(t, err)
 
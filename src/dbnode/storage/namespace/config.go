@@ -85,13 +85,15 @@ func (mc *MetadataConfiguration) Metadata() (Metadata, error) {
 
 // IndexConfiguration controls the knobs to tweak indexing configuration.
 type IndexConfiguration struct {
-	Enabled   bool          `yaml:"enabled" validate:"nonzero"`
-	BlockSize time.Duration `yaml:"blockSize" validate:"nonzero"`
+	Enabled         bool          `yaml:"enabled" validate:"nonzero"`
+	BlockSize       time.Duration `yaml:"blockSize" validate:"nonzero"`
+	RollupBlockSize time.Duration `yaml:"rollupBlockSize"`
 }
 
 // Options returns the IndexOptions corresponding to the receiver struct.
 func (ic *IndexConfiguration) Options() IndexOptions {
 	return NewIndexOptions().
 		SetEnabled(ic.Enabled).
-		SetBlockSize(ic.BlockSize)
+		SetBlockSize(ic.BlockSize).
+		SetRollupBlockSize(ic.RollupBlockSize)
 }
@@ -73,7 +73,8 @@ func ToIndexOptions(
 	}
 
 	iopts = iopts.SetEnabled(io.Enabled).
-		SetBlockSize(fromNanos(io.BlockSizeNanos))
+		SetBlockSize(fromNanos(io.BlockSizeNanos)).
+		SetRollupBlockSize(fromNanos(io.RollupBlockSizeNanos))
 
 	return iopts, nil
 }
@@ -157,8 +158,9 @@ func OptionsToProto(opts Options) *nsproto.NamespaceOptions {
 			BlockDataExpiryAfterNotAccessPeriodNanos: ropts.BlockDataExpiryAfterNotAccessedPeriod().Nanoseconds(),
 		},
 		IndexOptions: &nsproto.IndexOptions{
-			Enabled:        iopts.Enabled(),
-			BlockSizeNanos: iopts.BlockSize().Nanoseconds(),
+			Enabled:              iopts.Enabled(),
+			BlockSizeNanos:       iopts.BlockSize().Nanoseconds(),
+			RollupBlockSizeNanos: iopts.RollupBlockSize().Nanoseconds(),
 		},
 	}
 }
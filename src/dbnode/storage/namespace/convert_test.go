@@ -39,8 +39,9 @@ var (
 	}
 
 	validIndexOpts = nsproto.IndexOptions{
-		Enabled:        true,
-		BlockSizeNanos: toNanos(600), // 10h
+		Enabled:              true,
+		BlockSizeNanos:       toNanos(600),  // 10h
+		RollupBlockSizeNanos: toNanos(1200), // 20h
 	}
 
 	validRetentionOpts = nsproto.RetentionOptions{
@@ -228,6 +229,15 @@ func assertEqualMetadata(t *testing.T, name string, expected nsproto.NamespaceOp
 	require.Equal(t, expected.RepairEnabled, opts.RepairEnabled())
 
 	assertEqualRetentions(t, *expected.RetentionOptions, opts.RetentionOptions())
+	if expected.IndexOptions != nil {
+		assertEqualIndexOptions(t, *expected.IndexOptions, opts.IndexOptions())
+	}
+}
+
+func assertEqualIndexOptions(t *testing.T, expected nsproto.IndexOptions, observed namespace.IndexOptions) {
+	require.Equal(t, expected.Enabled, observed.Enabled())
+	require.Equal(t, expected.BlockSizeNanos, observed.BlockSize().Nanoseconds())
+	require.Equal(t, expected.RollupBlockSizeNanos, observed.RollupBlockSize().Nanoseconds())
 }
 
 func assertEqualRetentions(t *testing.T, expected nsproto.RetentionOptions, observed retention.Options) {
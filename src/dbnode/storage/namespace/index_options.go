@@ -30,24 +30,30 @@ var (
 
 	// defaultIndexBlockSize is the default block size for index blocks.
 	defaultIndexBlockSize = 2 * time.Hour
+
+	// defaultIndexRollupBlockSize disables rolling up index blocks by default.
+	defaultIndexRollupBlockSize = time.Duration(0)
 )
 
 type indexOpts struct {
-	enabled   bool
-	blockSize time.Duration
+	enabled         bool
+	blockSize       time.Duration
+	rollupBlockSize time.Duration
 }
 
 // NewIndexOptions returns a new IndexOptions.
 func NewIndexOptions() IndexOptions {
 	return &indexOpts{
-		enabled:   defaultIndexEnabled,
-		blockSize: defaultIndexBlockSize,
+		enabled:         defaultIndexEnabled,
+		blockSize:       defaultIndexBlockSize,
+		rollupBlockSize: defaultIndexRollupBlockSize,
 	}
 }
 
 func (i *indexOpts) Equal(value IndexOptions) bool {
 	return i.Enabled() == value.Enabled() &&
-		i.BlockSize() == value.BlockSize()
+		i.BlockSize() == value.BlockSize() &&
+		i.RollupBlockSize() == value.RollupBlockSize()
 }
 
 func (i *indexOpts) SetEnabled(value bool) IndexOptions {
@@ -69,3 +75,13 @@ func (i *indexOpts) SetBlockSize(value time.Duration) IndexOptions {
 func (i *indexOpts) BlockSize() time.Duration {
 	return i.blockSize
 }
+
+func (i *indexOpts) SetRollupBlockSize(value time.Duration) IndexOptions {
+	io := *i
+	io.rollupBlockSize = value
+	return &io
+}
+
+func (i *indexOpts) RollupBlockSize() time.Duration {
+	return i.rollupBlockSize
+}
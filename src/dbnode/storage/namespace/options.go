@@ -47,9 +47,12 @@ const (
 )
 
 var (
-	errIndexBlockSizePositive                       = errors.New("index block size must positive")
-	errIndexBlockSizeTooLarge                       = errors.New("index block size needs to be <= namespace retention period")
-	errIndexBlockSizeMustBeAMultipleOfDataBlockSize = errors.New("index block size must be a multiple of data block size")
+	errIndexBlockSizePositive                         = errors.New("index block size must positive")
+	errIndexBlockSizeTooLarge                         = errors.New("index block size needs to be <= namespace retention period")
+	errIndexBlockSizeMustBeAMultipleOfDataBlockSize   = errors.New("index block size must be a multiple of data block size")
+	errIndexRollupBlockSizeNegative                   = errors.New("index rollup block size must not be negative")
+	errIndexRollupBlockSizeTooLarge                   = errors.New("index rollup block size needs to be <= namespace retention period")
+	errIndexRollupBlockSizeMustBeAMultipleOfBlockSize = errors.New("index rollup block size must be a multiple of index block size")
 )
 
 type options struct {
@@ -85,9 +88,10 @@ func (o *options) Validate() error {
 		return nil
 	}
 	var (
-		retention      = o.retentionOpts.RetentionPeriod()
-		dataBlockSize  = o.retentionOpts.BlockSize()
-		indexBlockSize = o.indexOpts.BlockSize()
+		retention       = o.retentionOpts.RetentionPeriod()
+		dataBlockSize   = o.retentionOpts.BlockSize()
+		indexBlockSize  = o.indexOpts.BlockSize()
+		rollupBlockSize = o.indexOpts.RollupBlockSize()
 	)
 	if indexBlockSize <= 0 {
 		return errIndexBlockSizePositive
@@ -98,6 +102,15 @@ func (o *options) Validate() error {
 	if indexBlockSize%dataBlockSize != 0 {
 		return errIndexBlockSizeMustBeAMultipleOfDataBlockSize
 	}
+	if rollupBlockSize < 0 {
+		return errIndexRollupBlockSizeNegative
+	}
+	if retention < rollupBlockSize {
+		return errIndexRollupBlockSizeTooLarge
+	}
+	if rollupBlockSize%indexBlockSize != 0 {
+		return errIndexRollupBlockSizeMustBeAMultipleOfBlockSize
+	}
 	return nil
 }
 
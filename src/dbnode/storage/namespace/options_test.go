@@ -88,6 +88,7 @@ func TestOptionsValidate(t *testing.T) {
 		SetIndexOptions(iOpts)
 
 	iOpts.EXPECT().Enabled().Return(true).AnyTimes()
+	iOpts.EXPECT().RollupBlockSize().Return(time.Duration(0)).AnyTimes()
 
 	rOpts.EXPECT().Validate().Return(nil)
 	rOpts.EXPECT().RetentionPeriod().Return(time.Hour)
@@ -116,6 +117,7 @@ func TestOptionsValidateBlockSizeMustBeMultiple(t *testing.T) {
 		SetIndexOptions(iOpts)
 
 	iOpts.EXPECT().Enabled().Return(true).AnyTimes()
+	iOpts.EXPECT().RollupBlockSize().Return(time.Duration(0)).AnyTimes()
 
 	rOpts.EXPECT().Validate().Return(nil)
 	rOpts.EXPECT().RetentionPeriod().Return(4 * time.Hour).AnyTimes()
@@ -135,6 +137,7 @@ func TestOptionsValidateBlockSizePositive(t *testing.T) {
 		SetIndexOptions(iOpts)
 
 	iOpts.EXPECT().Enabled().Return(true).AnyTimes()
+	iOpts.EXPECT().RollupBlockSize().Return(time.Duration(0)).AnyTimes()
 
 	rOpts.EXPECT().Validate().Return(nil)
 	rOpts.EXPECT().RetentionPeriod().Return(4 * time.Hour).AnyTimes()
@@ -149,6 +152,21 @@ func TestOptionsValidateBlockSizePositive(t *testing.T) {
 	require.Error(t, o1.Validate())
 }
 
+func TestOptionsValidateRollupBlockSize(t *testing.T) {
+	ropts := retention.NewOptions().
+		SetRetentionPeriod(30 * 24 * time.Hour).
+		SetBlockSize(2 * time.Hour)
+	iopts := NewIndexOptions().
+		SetEnabled(true).
+		SetBlockSize(24 * time.Hour)
+	o1 := NewOptions().SetRetentionOptions(ropts)
+
+	require.NoError(t, o1.SetIndexOptions(iopts.SetRollupBlockSize(7*24*time.Hour)).Validate())
+	require.Error(t, o1.SetIndexOptions(iopts.SetRollupBlockSize(36*time.Hour)).Validate())
+	require.Error(t, o1.SetIndexOptions(iopts.SetRollupBlockSize(-24*time.Hour)).Validate())
+	require.Error(t, o1.SetIndexOptions(iopts.SetRollupBlockSize(31*24*time.Hour)).Validate())
+}
+
 func TestOptionsValidateNoIndexing(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
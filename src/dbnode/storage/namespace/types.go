@@ -102,6 +102,14 @@ type IndexOptions interface {
 
 	// BlockSize returns the block size.
 	BlockSize() time.Duration
+
+	// SetRollupBlockSize sets the block size that flushed index blocks are
+	// rolled up into, zero disables rolling up index blocks.
+	SetRollupBlockSize(value time.Duration) IndexOptions
+
+	// RollupBlockSize returns the block size that flushed index blocks are
+	// rolled up into, zero disables rolling up index blocks.
+	RollupBlockSize() time.Duration
 }
 
 // Metadata represents namespace metadata information
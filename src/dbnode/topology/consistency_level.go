@@ -44,6 +44,13 @@ const (
 	// ConsistencyLevelAll corresponds to all nodes participating
 	// for an operation to succeed
 	ConsistencyLevelAll
+
+	// ConsistencyLevelZoneMajority corresponds to nodes in the majority of
+	// zones participating for an operation to succeed, it only applies to
+	// writes. There is no read equivalent since a read from one node in each
+	// of a majority of zones can miss a write acknowledged at this level when
+	// a zone holds more than one replica
+	ConsistencyLevelZoneMajority
 )
 
 // String returns the consistency level as a string
@@ -57,6 +64,8 @@ func (l ConsistencyLevel) String() string {
 		return majority
 	case ConsistencyLevelAll:
 		return all
+	case ConsistencyLevelZoneMajority:
+		return zoneMajority
 	}
 	return unknown
 }
@@ -66,6 +75,7 @@ var validConsistencyLevels = []ConsistencyLevel{
 	ConsistencyLevelOne,
 	ConsistencyLevelMajority,
 	ConsistencyLevelAll,
+	ConsistencyLevelZoneMajority,
 }
 
 var (
@@ -306,6 +316,7 @@ const (
 	none             = "none"
 	majority         = "majority"
 	unstrictMajority = "unstrict_majority"
+	zoneMajority     = "zone_majority"
 )
 
 // WriteConsistencyAchieved returns a bool indicating whether or not we've received enough
@@ -334,6 +345,13 @@ func WriteConsistencyAchieved(
 	panic(fmt.Errorf("unrecognized consistency level: %s", level.String()))
 }
 
+// WriteZoneConsistencyAchieved returns a bool indicating whether or not we've
+// received successful acks from enough distinct zones to consider a write
+// successful at the zone majority consistency level.
+func WriteZoneConsistencyAchieved(majorityZones, numSuccessZones int) bool {
+	return numSuccessZones >= majorityZones
+}
+
 // ReadConsistencyTermination returns a bool to indicate whether sufficient
 // responses (error/success) have been received, so that we're able to decide
 // whether we will be able to satisfy the reuquest or not.
@@ -234,7 +234,8 @@ func getStaticOptions(service services.Service, hashGen sharding.HashGen) (Stati
 	return NewStaticOptions().
 		SetReplicas(replicas).
 		SetShardSet(allShardSet).
-		SetHostShardSets(hostShardSets), nil
+		SetHostShardSets(hostShardSets).
+		SetZoneReplicaFactors(service.Replication().ZoneReplicaFactors()), nil
 }
 
 func validateInstances(
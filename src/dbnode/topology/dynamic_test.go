@@ -219,6 +219,7 @@ func getMockService(ctrl *gomock.Controller) services.Service {
 
 	mockReplication := services.NewMockServiceReplication(ctrl)
 	mockReplication.EXPECT().Replicas().Return(2).AnyTimes()
+	mockReplication.EXPECT().ZoneReplicaFactors().Return(nil).AnyTimes()
 	mockService.EXPECT().Replication().Return(mockReplication).AnyTimes()
 
 	mockSharding := services.NewMockServiceSharding(ctrl)
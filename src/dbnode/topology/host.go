@@ -41,6 +41,7 @@ func Majority(replicas int) int {
 type host struct {
 	id      string
 	address string
+	zone    string
 }
 
 func (h *host) ID() string {
@@ -51,6 +52,10 @@ func (h *host) Address() string {
 	return h.address
 }
 
+func (h *host) Zone() string {
+	return h.zone
+}
+
 func (h *host) String() string {
 	return fmt.Sprintf("Host<ID=%s, Address=%s>", h.id, h.address)
 }
@@ -60,6 +65,11 @@ func NewHost(id, address string) Host {
 	return &host{id: id, address: address}
 }
 
+// NewHostWithZone creates a new host that resides in the given zone
+func NewHostWithZone(id, address, zone string) Host {
+	return &host{id: id, address: address, zone: zone}
+}
+
 type hostShardSet struct {
 	host     Host
 	shardSet sharding.ShardSet
@@ -86,7 +96,8 @@ func NewHostShardSetFromServiceInstance(
 	if err != nil {
 		return nil, err
 	}
-	return NewHostShardSet(NewHost(si.InstanceID(), si.Endpoint()), shardSet), nil
+	host := NewHostWithZone(si.InstanceID(), si.Endpoint(), si.Zone())
+	return NewHostShardSet(host, shardSet), nil
 }
 
 func (h *hostShardSet) Host() Host {
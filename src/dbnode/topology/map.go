@@ -35,6 +35,8 @@ type staticMap struct {
 	orderedHostsByShard [][]orderedHost
 	replicas            int
 	majority            int
+	// majorityZonesByShard is nil if the topology has no zone replica factors.
+	majorityZonesByShard []int
 }
 
 // NewStaticMap creates a new static topology map
@@ -65,6 +67,20 @@ func NewStaticMap(opts StaticOptions) Map {
 		}
 	}
 
+	if len(opts.ZoneReplicaFactors()) > 0 {
+		// NB: Shards only move between hosts of the same zone when the
+		// placement has zone replica factors, so the zones of the hosts of a
+		// shard are the zones its replicas are placed in.
+		topoMap.majorityZonesByShard = make([]int, totalShards)
+		for shard, hosts := range topoMap.hostsByShard {
+			zones := make(map[string]struct{}, len(hosts))
+			for _, host := range hosts {
+				zones[host.Zone()] = struct{}{}
+			}
+			topoMap.majorityZonesByShard[shard] = Majority(len(zones))
+		}
+	}
+
 	return &topoMap
 }
 
@@ -132,6 +148,16 @@ func (t *staticMap) MajorityReplicas() int {
 	return t.majority
 }
 
+func (t *staticMap) MajorityZones(shard uint32) (int, error) {
+	if t.majorityZonesByShard == nil {
+		return 0, errNoZoneReplicaFactors
+	}
+	if int(shard) >= len(t.majorityZonesByShard) {
+		return 0, errUnownedShard
+	}
+	return t.majorityZonesByShard[shard], nil
+}
+
 type mapWatch struct {
 	xwatch.Watch
 }
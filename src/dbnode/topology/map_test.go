@@ -21,6 +21,7 @@
 package topology
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/m3db/m3/src/cluster/shard"
@@ -135,4 +136,46 @@ func TestStaticMap(t *testing.T) {
 
 	assert.Equal(t, 2, m.Replicas())
 	assert.Equal(t, 2, m.MajorityReplicas())
+	_, err = m.MajorityZones(0)
+	assert.Equal(t, errNoZoneReplicaFactors, err)
+}
+
+func TestStaticMapMajorityZones(t *testing.T) {
+	hashFn := sharding.DefaultHashFn(1)
+	var hostShardSets []HostShardSet
+	for i, host := range []struct {
+		zone   string
+		shards []uint32
+	}{
+		{zone: "z1", shards: []uint32{0, 1}},
+		{zone: "z1", shards: []uint32{0, 1}},
+		{zone: "z2", shards: []uint32{0, 1}},
+		{zone: "z3", shards: []uint32{0}},
+		{zone: "z4", shards: []uint32{1}},
+	} {
+		id := fmt.Sprintf("h%d", i)
+		hostShardSets = append(hostShardSets,
+			NewHostShardSet(
+				NewHostWithZone(id, id+":9000", host.zone),
+				newTestShardSet(t, host.shards, hashFn)))
+	}
+
+	opts := NewStaticOptions().
+		SetShardSet(newTestShardSet(t, []uint32{0, 1}, hashFn)).
+		SetReplicas(4).
+		SetHostShardSets(hostShardSets).
+		SetZoneReplicaFactors(map[string]int{"z1": 2, "z2": 1, "z3": 1, "z4": 1})
+
+	m := NewStaticMap(opts)
+	assert.Equal(t, 3, m.MajorityReplicas())
+	assert.Equal(t, "z2", m.Hosts()[2].Zone())
+
+	// Each shard is replicated across three of the zones.
+	for _, shardID := range []uint32{0, 1} {
+		majorityZones, err := m.MajorityZones(shardID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, majorityZones)
+	}
+	_, err := m.MajorityZones(2)
+	assert.Equal(t, errUnownedShard, err)
 }
@@ -44,9 +44,10 @@ var (
 )
 
 type staticOptions struct {
-	shardSet      sharding.ShardSet
-	replicas      int
-	hostShardSets []HostShardSet
+	shardSet           sharding.ShardSet
+	replicas           int
+	hostShardSets      []HostShardSet
+	zoneReplicaFactors map[string]int
 }
 
 // NewStaticOptions creates a new set of static topology options
@@ -116,6 +117,16 @@ func (o *staticOptions) HostShardSets() []HostShardSet {
 	return o.hostShardSets
 }
 
+func (o *staticOptions) SetZoneReplicaFactors(value map[string]int) StaticOptions {
+	opts := *o
+	opts.zoneReplicaFactors = value
+	return &opts
+}
+
+func (o *staticOptions) ZoneReplicaFactors() map[string]int {
+	return o.zoneReplicaFactors
+}
+
 type dynamicOptions struct {
 	configServiceClient     client.Client
 	serviceID               services.ServiceID
@@ -28,6 +28,9 @@ import (
 
 var (
 	errUnownedShard = errors.New("unowned shard")
+
+	errNoZoneReplicaFactors = errors.New(
+		"zone majority consistency requires a placement with zone replica factors")
 )
 
 type staticInitializer struct {
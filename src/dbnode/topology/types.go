@@ -37,6 +37,10 @@ type Host interface {
 	// Address returns the address of the host
 	Address() string
 
+	// Zone returns the zone of the host, empty if the topology
+	// does not span multiple zones
+	Zone() string
+
 	// String returns a string representation of the host
 	String() string
 }
@@ -126,6 +130,11 @@ type Map interface {
 
 	// MajorityReplicas returns the number of replicas to establish majority in the topology
 	MajorityReplicas() int
+
+	// MajorityZones returns the number of distinct zones of the replicas of
+	// the given shard required to establish majority, it returns an error if
+	// the topology has no zone replica factors
+	MajorityZones(shard uint32) (int, error)
 }
 
 // RouteForEachFn is a function to execute for each routed to host
@@ -166,6 +175,12 @@ type StaticOptions interface {
 
 	// HostShardSets returns the hostShardSets
 	HostShardSets() []HostShardSet
+
+	// SetZoneReplicaFactors sets the replicas placed in each zone
+	SetZoneReplicaFactors(value map[string]int) StaticOptions
+
+	// ZoneReplicaFactors returns the replicas placed in each zone
+	ZoneReplicaFactors() map[string]int
 }
 
 // DynamicOptions is a set of options for dynamic topology
@@ -52,17 +52,26 @@ const (
 	TransformationType_UNKNOWN   TransformationType = 0
 	TransformationType_ABSOLUTE  TransformationType = 1
 	TransformationType_PERSECOND TransformationType = 2
+	TransformationType_INCREASE  TransformationType = 3
+	TransformationType_ADD       TransformationType = 4
+	TransformationType_RESET     TransformationType = 5
 )
 
 var TransformationType_name = map[int32]string{
 	0: "UNKNOWN",
 	1: "ABSOLUTE",
 	2: "PERSECOND",
+	3: "INCREASE",
+	4: "ADD",
+	5: "RESET",
 }
 var TransformationType_value = map[string]int32{
 	"UNKNOWN":   0,
 	"ABSOLUTE":  1,
 	"PERSECOND": 2,
+	"INCREASE":  3,
+	"ADD":       4,
+	"RESET":     5,
 }
 
 func (x TransformationType) String() string {
@@ -81,17 +90,18 @@ func init() {
 }
 
 var fileDescriptorTransformation = []byte{
-	// 180 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x0a, 0x49, 0xcf, 0x2c, 0xc9,
-	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0xcf, 0x35, 0x4e, 0x49, 0xd2, 0xcf, 0x35, 0xd6, 0x2f,
-	0x2e, 0x4a, 0xd6, 0xcf, 0x4d, 0x2d, 0x29, 0xca, 0x4c, 0x2e, 0xd6, 0x4f, 0x4f, 0xcd, 0x4b, 0x2d,
-	0x4a, 0x2c, 0x49, 0x4d, 0xd1, 0x2f, 0x28, 0xca, 0x2f, 0xc9, 0xd7, 0x2f, 0x29, 0x4a, 0xcc, 0x2b,
-	0x4e, 0xcb, 0x2f, 0xca, 0x4d, 0x2c, 0xc9, 0xcc, 0xcf, 0x2b, 0x48, 0x42, 0x13, 0xd0, 0x03, 0xab,
-	0x12, 0x12, 0x40, 0x57, 0xa6, 0x65, 0xc7, 0x25, 0x14, 0x82, 0x22, 0x16, 0x52, 0x59, 0x90, 0x2a,
-	0xc4, 0xcd, 0xc5, 0x1e, 0xea, 0xe7, 0xed, 0xe7, 0x1f, 0xee, 0x27, 0xc0, 0x20, 0xc4, 0xc3, 0xc5,
-	0xe1, 0xe8, 0x14, 0xec, 0xef, 0x13, 0x1a, 0xe2, 0x2a, 0xc0, 0x28, 0xc4, 0xcb, 0xc5, 0x19, 0xe0,
-	0x1a, 0x14, 0xec, 0xea, 0xec, 0xef, 0xe7, 0x22, 0xc0, 0xe4, 0x14, 0x78, 0xe2, 0x91, 0x1c, 0xe3,
-	0x85, 0x47, 0x72, 0x8c, 0x0f, 0x1e, 0xc9, 0x31, 0x4e, 0x78, 0x2c, 0xc7, 0x10, 0x65, 0x4f, 0xa1,
-	0xcb, 0x93, 0xd8, 0xc0, 0xe2, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0x1e, 0xe7, 0xc2, 0x13,
-	0x03, 0x01, 0x00, 0x00,
+	// 203 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe3, 0x0a, 0x49, 0xcf, 0x2c, 0xc9,
+	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0xcf, 0x35, 0x4e, 0x49, 0x02, 0x12, 0xfa, 0xc5, 0x45,
+	0xc9, 0xfa, 0xb9, 0xa9, 0x25, 0x45, 0x99, 0xc9, 0xc5, 0xfa, 0xe9, 0xa9, 0x79, 0xa9, 0x45, 0x89,
+	0x25, 0xa9, 0x29, 0xfa, 0x05, 0x45, 0xf9, 0x25, 0xf9, 0xfa, 0x25, 0x45, 0x89, 0x79, 0xc5, 0x69,
+	0xf9, 0x45, 0xb9, 0x89, 0x25, 0x99, 0xf9, 0x79, 0x05, 0x49, 0x68, 0x02, 0x7a, 0x60, 0x55, 0x42,
+	0x02, 0xe8, 0xca, 0xb4, 0x12, 0xb8, 0x84, 0x42, 0x50, 0xc4, 0x42, 0x2a, 0x0b, 0x52, 0x85, 0xb8,
+	0xb9, 0xd8, 0x43, 0xfd, 0xbc, 0xfd, 0xfc, 0xc3, 0xfd, 0x04, 0x18, 0x84, 0x78, 0xb8, 0x38, 0x1c,
+	0x9d, 0x82, 0xfd, 0x7d, 0x42, 0x43, 0x5c, 0x05, 0x18, 0x85, 0x78, 0xb9, 0x38, 0x03, 0x5c, 0x83,
+	0x82, 0x5d, 0x9d, 0xfd, 0xfd, 0x5c, 0x04, 0x98, 0x40, 0x92, 0x9e, 0x7e, 0xce, 0x41, 0xae, 0x8e,
+	0xc1, 0xae, 0x02, 0xcc, 0x42, 0xec, 0x5c, 0xcc, 0x8e, 0x2e, 0x2e, 0x02, 0x2c, 0x42, 0x9c, 0x5c,
+	0xac, 0x41, 0xae, 0xc1, 0xae, 0x21, 0x02, 0xac, 0x4e, 0x81, 0x27, 0x1e, 0xc9, 0x31, 0x5e, 0x00,
+	0xe2, 0x07, 0x40, 0x3c, 0xe1, 0xb1, 0x1c, 0x43, 0x94, 0x3d, 0x85, 0x7e, 0x4b, 0x62, 0x03, 0x8b,
+	0x1b, 0x03, 0x00, 0x71, 0x38, 0xb4, 0xa1, 0x25, 0x01, 0x00, 0x00,
 }
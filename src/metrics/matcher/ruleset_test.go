@@ -280,6 +280,10 @@ func (r *mockRuleSet) MappingRules() (view.MappingRules, error) { return nil, ni
 func (r *mockRuleSet) RollupRules() (view.RollupRules, error)   { return nil, nil }
 func (r *mockRuleSet) Latest() (view.RuleSet, error)            { return view.RuleSet{}, nil }
 
+func (r *mockRuleSet) MatchingRulesAt(id []byte, timeNanos int64) (view.RuleSet, error) {
+	return view.RuleSet{}, nil
+}
+
 func testRuleSet() (kv.Store, cache.Cache, *ruleSet) {
 	store := mem.NewStore()
 	cache := newMemCache()
@@ -437,5 +437,56 @@ func TestPipelineUnmarshalYAML(t *testing.T) {
 	require.Equal(t, expected, pipeline)
 }
 
+func TestPipelineUnmarshalYAMLCounterTransformations(t *testing.T) {
+	input := `
+- aggregation: Last
+- transformation: Increase
+- transformation: Add
+- transformation: Reset
+- rollup:
+    newName: testRollup
+    tags:
+      - tag1
+`
+
+	var pipeline Pipeline
+	require.NoError(t, yaml.Unmarshal([]byte(input), &pipeline))
+
+	expected := NewPipeline([]OpUnion{
+		{
+			Type:        AggregationOpType,
+			Aggregation: AggregationOp{Type: aggregation.Last},
+		},
+		{
+			Type:           TransformationOpType,
+			Transformation: TransformationOp{Type: transformation.Increase},
+		},
+		{
+			Type:           TransformationOpType,
+			Transformation: TransformationOp{Type: transformation.Add},
+		},
+		{
+			Type:           TransformationOpType,
+			Transformation: TransformationOp{Type: transformation.Reset},
+		},
+		{
+			Type: RollupOpType,
+			Rollup: RollupOp{
+				NewName:       b("testRollup"),
+				Tags:          bs("tag1"),
+				AggregationID: aggregation.DefaultID,
+			},
+		},
+	})
+	require.Equal(t, expected, pipeline)
+
+	// The pipeline survives a protobuf roundtrip.
+	pb, err := pipeline.Proto()
+	require.NoError(t, err)
+	res, err := NewPipelineFromProto(pb)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+}
+
 func b(v string) []byte       { return []byte(v) }
 func bs(v ...string) [][]byte { return bytes.ArraysFromStringArray(v) }
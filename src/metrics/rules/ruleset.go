@@ -48,6 +48,7 @@ var (
 	errRuleSetNotTombstoned = errors.New("ruleset is not tombstoned")
 	errRuleNotFound         = errors.New("rule not found")
 	errNoRuleSnapshots      = errors.New("rule has no snapshots")
+	errNoRuleFilter         = errors.New("rule filter has not been compiled")
 	ruleIDNotFoundErrorFmt  = "no rule with id %v"
 	ruleActionErrorFmt      = "cannot %s rule %s"
 	ruleSetActionErrorFmt   = "cannot %s ruleset %s"
@@ -90,6 +91,11 @@ type RuleSet interface {
 	// ActiveSet returns the active ruleset at a given time.
 	ActiveSet(timeNanos int64) Matcher
 
+	// MatchingRulesAt returns a snapshot of the ruleset containing the snapshots
+	// of the rules in effect at a given time whose filters match the given id.
+	// Rule filters are only compiled for rulesets created from protobuf messages.
+	MatchingRulesAt(id []byte, timeNanos int64) (view.RuleSet, error)
+
 	// ToMutableRuleSet returns a mutable version of this ruleset.
 	ToMutableRuleSet() MutableRuleSet
 }
@@ -229,6 +235,64 @@ func (rs *ruleSet) ActiveSet(timeNanos int64) Matcher {
 	)
 }
 
+func (rs *ruleSet) MatchingRulesAt(id []byte, timeNanos int64) (view.RuleSet, error) {
+	var mappingRules []view.MappingRule
+	for _, m := range rs.mappingRules {
+		idx := m.activeIndex(timeNanos)
+		if idx < 0 {
+			continue
+		}
+		snapshot := m.snapshots[idx]
+		if snapshot.tombstoned {
+			continue
+		}
+		if snapshot.filter == nil {
+			return view.RuleSet{}, errNoRuleFilter
+		}
+		if !snapshot.filter.Matches(id) {
+			continue
+		}
+		mrv, err := m.mappingRuleView(idx)
+		if err != nil {
+			return view.RuleSet{}, err
+		}
+		mappingRules = append(mappingRules, mrv)
+	}
+	sort.Sort(view.MappingRulesByNameAsc(mappingRules))
+
+	var rollupRules []view.RollupRule
+	for _, r := range rs.rollupRules {
+		idx := r.activeIndex(timeNanos)
+		if idx < 0 {
+			continue
+		}
+		snapshot := r.snapshots[idx]
+		if snapshot.tombstoned {
+			continue
+		}
+		if snapshot.filter == nil {
+			return view.RuleSet{}, errNoRuleFilter
+		}
+		if !snapshot.filter.Matches(id) {
+			continue
+		}
+		rrv, err := r.rollupRuleView(idx)
+		if err != nil {
+			return view.RuleSet{}, err
+		}
+		rollupRules = append(rollupRules, rrv)
+	}
+	sort.Sort(view.RollupRulesByNameAsc(rollupRules))
+
+	return view.RuleSet{
+		Namespace:     string(rs.Namespace()),
+		Version:       rs.Version(),
+		CutoverMillis: rs.CutoverNanos() / nanosPerMilli,
+		MappingRules:  mappingRules,
+		RollupRules:   rollupRules,
+	}, nil
+}
+
 // Proto returns the protobuf representation of a ruleset.
 func (rs *ruleSet) Proto() (*rulepb.RuleSet, error) {
 	res := &rulepb.RuleSet{
@@ -437,6 +437,80 @@ func TestRuleSetLatest(t *testing.T) {
 	require.Equal(t, expected, latest)
 }
 
+func TestRuleSetMatchingRulesAt(t *testing.T) {
+	helper := NewRuleSetUpdateHelper(0)
+	meta := helper.NewUpdateMetadata(1000, testUser)
+	mutable := NewEmptyRuleSet("testNamespace", meta)
+
+	_, err := mutable.AddMappingRule(view.MappingRule{
+		Name:   "web",
+		Filter: "app:web",
+		StoragePolicies: policy.StoragePolicies{
+			policy.NewStoragePolicy(10*time.Second, xtime.Second, 2*24*time.Hour),
+		},
+	}, meta)
+	require.NoError(t, err)
+	_, err = mutable.AddMappingRule(view.MappingRule{
+		Name:       "db",
+		Filter:     "app:db",
+		DropPolicy: policy.DropMust,
+	}, meta)
+	require.NoError(t, err)
+	_, err = mutable.AddRollupRule(view.RollupRule{
+		Name:   "webByHost",
+		Filter: "app:web",
+		Targets: []view.RollupTarget{
+			{
+				Pipeline: pipeline.NewPipeline([]pipeline.OpUnion{
+					{
+						Type: pipeline.RollupOpType,
+						Rollup: pipeline.RollupOp{
+							NewName:       b("webByHost"),
+							Tags:          bs("host"),
+							AggregationID: aggregation.DefaultID,
+						},
+					},
+				}),
+				StoragePolicies: policy.StoragePolicies{
+					policy.NewStoragePolicy(time.Minute, xtime.Minute, 40*24*time.Hour),
+				},
+			},
+		},
+	}, meta)
+	require.NoError(t, err)
+
+	// Rule filters of a ruleset created in place are not compiled.
+	_, err = mutable.MatchingRulesAt(b("m|app=web"), 2000)
+	require.Equal(t, errNoRuleFilter, err)
+
+	proto, err := mutable.Proto()
+	require.NoError(t, err)
+	rs, err := NewRuleSetFromProto(1, proto, testRuleSetOptions())
+	require.NoError(t, err)
+
+	// No rules are in effect before the cutover time.
+	matched, err := rs.MatchingRulesAt(b("m|app=web"), 500)
+	require.NoError(t, err)
+	require.Empty(t, matched.MappingRules)
+	require.Empty(t, matched.RollupRules)
+
+	matched, err = rs.MatchingRulesAt(b("m|app=web"), 2000)
+	require.NoError(t, err)
+	require.Equal(t, "testNamespace", matched.Namespace)
+	require.Equal(t, 1, matched.Version)
+	require.Len(t, matched.MappingRules, 1)
+	require.Equal(t, "web", matched.MappingRules[0].Name)
+	require.Len(t, matched.RollupRules, 1)
+	require.Equal(t, "webByHost", matched.RollupRules[0].Name)
+
+	matched, err = rs.MatchingRulesAt(b("m|app=db"), 2000)
+	require.NoError(t, err)
+	require.Len(t, matched.MappingRules, 1)
+	require.Equal(t, "db", matched.MappingRules[0].Name)
+	require.Equal(t, policy.DropMust, matched.MappingRules[0].DropPolicy)
+	require.Empty(t, matched.RollupRules)
+}
+
 func TestRuleSetClone(t *testing.T) {
 	var (
 		version = 1
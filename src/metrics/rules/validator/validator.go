@@ -42,6 +42,7 @@ var (
 	errMoreThanOneAggregationOpInPipeline = errors.New("more than one aggregation operation in pipeline")
 	errAggregationOpNotFirstInPipeline    = errors.New("aggregation operation is not the first operation in pipeline")
 	errNoRollupOpInPipeline               = errors.New("no rollup operation in pipeline")
+	errMoreThanOneCumulativeTransformOp   = errors.New("more than one cumulative transformation operation before a rollup operation")
 )
 
 type validator struct {
@@ -280,6 +281,9 @@ func (v *validator) validateStoragePolicies(
 // * The pipeline can contain arbitrary number of transformation operations. However,
 //   the transformation derivative order computed from the list of transformations must
 //   be no more than the maximum transformation derivative order that is supported.
+// * There can be at most one cumulative transformation operation in between two consecutive
+//   rollup operations, and a transformation producing multiple outputs must be immediately
+//   followed by a rollup operation.
 // * The pipeline must contain at least one rollup operation and at most `n` rollup operations,
 //   where `n` is the maximum supported number of rollup levels.
 func (v *validator) validatePipeline(pipeline mpipeline.Pipeline, types []metric.Type) error {
@@ -289,6 +293,7 @@ func (v *validator) validatePipeline(pipeline mpipeline.Pipeline, types []metric
 	var (
 		numAggregationOps             int
 		transformationDerivativeOrder int
+		numCumulativeTransformOps     int
 		numRollupOps                  int
 		previousRollupTags            map[string]struct{}
 		numPipelineOps                = pipeline.Len()
@@ -315,6 +320,16 @@ func (v *validator) validatePipeline(pipeline mpipeline.Pipeline, types []metric
 					return fmt.Errorf("transformation derivative order is %d higher than supported %d", transformationDerivativeOrder, v.opts.MaxTransformationDerivativeOrder())
 				}
 			}
+			if transformOp.Type.IsCumulativeTransform() {
+				numCumulativeTransformOps++
+				if numCumulativeTransformOps > 1 {
+					return errMoreThanOneCumulativeTransformOp
+				}
+			}
+			if transformOp.Type.IsUnaryMultiOutputTransform() &&
+				(i+1 >= numPipelineOps || pipeline.At(i+1).Type != mpipeline.RollupOpType) {
+				return fmt.Errorf("transformation operation at index %d is not followed by a rollup operation", i)
+			}
 			if err := validateTransformationOp(transformOp); err != nil {
 				return fmt.Errorf("invalid transformation operation at index %d: %v", i, err)
 			}
@@ -323,6 +338,7 @@ func (v *validator) validatePipeline(pipeline mpipeline.Pipeline, types []metric
 			// two consecutive rollup operations and as such we reset the derivative order when
 			// encountering a rollup operation.
 			transformationDerivativeOrder = 0
+			numCumulativeTransformOps = 0
 			numRollupOps++
 			if numRollupOps > v.opts.MaxRollupLevels() {
 				return fmt.Errorf("number of rollup levels is %d higher than supported %d", numRollupOps, v.opts.MaxRollupLevels())
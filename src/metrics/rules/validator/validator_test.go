@@ -695,6 +695,82 @@ func TestValidatorValidateRollupRulePipelineTransformationDerivativeOrderNotSupp
 	require.True(t, strings.Contains(err.Error(), "transformation derivative order is 2 higher than supported 1"))
 }
 
+func TestValidatorValidateRollupRulePipelineMoreThanOneCumulativeTransformation(t *testing.T) {
+	view := view.RuleSet{
+		RollupRules: []view.RollupRule{
+			{
+				Name:   "snapshot1",
+				Filter: testTypeTag + ":" + testCounterType,
+				Targets: []view.RollupTarget{
+					{
+						Pipeline: pipeline.NewPipeline([]pipeline.OpUnion{
+							{
+								Type:           pipeline.TransformationOpType,
+								Transformation: pipeline.TransformationOp{Type: transformation.Add},
+							},
+							{
+								Type:           pipeline.TransformationOpType,
+								Transformation: pipeline.TransformationOp{Type: transformation.Add},
+							},
+							{
+								Type: pipeline.RollupOpType,
+								Rollup: pipeline.RollupOp{
+									NewName:       []byte("rName1"),
+									Tags:          [][]byte{[]byte("rtagName1"), []byte("rtagName2")},
+									AggregationID: aggregation.DefaultID,
+								},
+							},
+						}),
+						StoragePolicies: testStoragePolicies(),
+					},
+				},
+			},
+		},
+	}
+	validator := NewValidator(testValidatorOptions())
+	err := validator.ValidateSnapshot(view)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), errMoreThanOneCumulativeTransformOp.Error()))
+}
+
+func TestValidatorValidateRollupRulePipelineMultiOutputTransformationNotFollowedByRollup(t *testing.T) {
+	view := view.RuleSet{
+		RollupRules: []view.RollupRule{
+			{
+				Name:   "snapshot1",
+				Filter: testTypeTag + ":" + testCounterType,
+				Targets: []view.RollupTarget{
+					{
+						Pipeline: pipeline.NewPipeline([]pipeline.OpUnion{
+							{
+								Type:           pipeline.TransformationOpType,
+								Transformation: pipeline.TransformationOp{Type: transformation.Reset},
+							},
+							{
+								Type:           pipeline.TransformationOpType,
+								Transformation: pipeline.TransformationOp{Type: transformation.Absolute},
+							},
+							{
+								Type: pipeline.RollupOpType,
+								Rollup: pipeline.RollupOp{
+									NewName:       []byte("rName1"),
+									Tags:          [][]byte{[]byte("rtagName1"), []byte("rtagName2")},
+									AggregationID: aggregation.DefaultID,
+								},
+							},
+						}),
+						StoragePolicies: testStoragePolicies(),
+					},
+				},
+			},
+		},
+	}
+	validator := NewValidator(testValidatorOptions())
+	err := validator.ValidateSnapshot(view)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "transformation operation at index 0 is not followed by a rollup operation"))
+}
+
 func TestValidatorValidateRollupRulePipelineInvalidTransformationType(t *testing.T) {
 	view := view.RuleSet{
 		RollupRules: []view.RollupRule{
@@ -45,3 +45,21 @@ func perSecond(prev, curr Datapoint) Datapoint {
 	rate := diff * float64(nanosPerSecond) / float64(curr.TimeNanos-prev.TimeNanos)
 	return Datapoint{TimeNanos: curr.TimeNanos, Value: rate}
 }
+
+// increase computes the difference between consecutive datapoints, unlike
+// perSecond it does not take into account the time interval between the values.
+// * It skips NaN values.
+// * It assumes the timestamps are monotonically increasing, and an empty datapoint
+//   is returned otherwise.
+// * A value smaller than the previous value is treated as a counter reset, in
+//   which case the current value is the increase since the reset.
+func increase(prev, curr Datapoint) Datapoint {
+	if prev.TimeNanos >= curr.TimeNanos || math.IsNaN(prev.Value) || math.IsNaN(curr.Value) {
+		return emptyDatapoint
+	}
+	diff := curr.Value - prev.Value
+	if diff < 0 {
+		diff = curr.Value
+	}
+	return Datapoint{TimeNanos: curr.TimeNanos, Value: diff}
+}
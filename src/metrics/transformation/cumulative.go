@@ -0,0 +1,37 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package transformation
+
+import "math"
+
+// add computes the running sum of datapoints.
+// * It skips NaN values, in which case the previous sum is returned.
+// * An empty previous result is treated as a zero sum.
+func add(prevResult, curr Datapoint) Datapoint {
+	if math.IsNaN(curr.Value) {
+		return Datapoint{TimeNanos: curr.TimeNanos, Value: prevResult.Value}
+	}
+	sum := curr.Value
+	if !math.IsNaN(prevResult.Value) {
+		sum += prevResult.Value
+	}
+	return Datapoint{TimeNanos: curr.TimeNanos, Value: sum}
+}
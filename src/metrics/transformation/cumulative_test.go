@@ -0,0 +1,66 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package transformation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdd(t *testing.T) {
+	inputs := []struct {
+		prevResult  Datapoint
+		curr        Datapoint
+		expectedNaN bool
+		expected    Datapoint
+	}{
+		{
+			prevResult: Datapoint{TimeNanos: 1000, Value: 25},
+			curr:       Datapoint{TimeNanos: 2000, Value: 5},
+			expected:   Datapoint{TimeNanos: 2000, Value: 30},
+		},
+		{
+			prevResult: emptyDatapoint,
+			curr:       Datapoint{TimeNanos: 2000, Value: 5},
+			expected:   Datapoint{TimeNanos: 2000, Value: 5},
+		},
+		{
+			prevResult: Datapoint{TimeNanos: 1000, Value: 25},
+			curr:       Datapoint{TimeNanos: 2000, Value: math.NaN()},
+			expected:   Datapoint{TimeNanos: 2000, Value: 25},
+		},
+		{
+			prevResult:  emptyDatapoint,
+			curr:        Datapoint{TimeNanos: 2000, Value: math.NaN()},
+			expectedNaN: true,
+		},
+	}
+
+	for _, input := range inputs {
+		if input.expectedNaN {
+			require.True(t, add(input.prevResult, input.curr).IsEmpty())
+		} else {
+			require.Equal(t, input.expected, add(input.prevResult, input.curr))
+		}
+	}
+}
@@ -20,7 +20,10 @@
 
 package transformation
 
-import "math"
+import (
+	"math"
+	"time"
+)
 
 var (
 	emptyDatapoint = Datapoint{Value: math.NaN()}
@@ -44,3 +47,13 @@ type UnaryTransform func(dp Datapoint) Datapoint
 // previous and the current datapoint as input and produces
 // a single datapoint as the transformation result.
 type BinaryTransform func(prev, curr Datapoint) Datapoint
+
+// CumulativeTransform is a transformation that takes the previous
+// transformation result and the current datapoint as input and
+// produces a single datapoint as the transformation result.
+type CumulativeTransform func(prevResult, curr Datapoint) Datapoint
+
+// UnaryMultiOutputTransform is a unary transformation that takes a
+// single datapoint and the resolution of the datapoint as input and
+// transforms it into two datapoints as output.
+type UnaryMultiOutputTransform func(dp Datapoint, resolution time.Duration) (Datapoint, Datapoint)
@@ -35,11 +35,17 @@ const (
 	UnknownType Type = iota
 	Absolute
 	PerSecond
+	Increase
+	Add
+	Reset
 )
 
 // IsValid checks if the transformation type is valid.
 func (t Type) IsValid() bool {
-	return t.IsUnaryTransform() || t.IsBinaryTransform()
+	return t.IsUnaryTransform() ||
+		t.IsBinaryTransform() ||
+		t.IsCumulativeTransform() ||
+		t.IsUnaryMultiOutputTransform()
 }
 
 // IsUnaryTransform returns whether this is a unary transformation.
@@ -54,6 +60,19 @@ func (t Type) IsBinaryTransform() bool {
 	return exists
 }
 
+// IsCumulativeTransform returns whether this is a cumulative transformation.
+func (t Type) IsCumulativeTransform() bool {
+	_, exists := cumulativeTransforms[t]
+	return exists
+}
+
+// IsUnaryMultiOutputTransform returns whether this is a unary transformation
+// producing multiple outputs.
+func (t Type) IsUnaryMultiOutputTransform() bool {
+	_, exists := unaryMultiOutputTransforms[t]
+	return exists
+}
+
 // UnaryTransform returns the unary transformation function associated with
 // the transformation type if applicable, or an error otherwise.
 func (t Type) UnaryTransform() (UnaryTransform, error) {
@@ -94,6 +113,46 @@ func (t Type) MustBinaryTransform() BinaryTransform {
 	return tf
 }
 
+// CumulativeTransform returns the cumulative transformation function associated
+// with the transformation type if applicable, or an error otherwise.
+func (t Type) CumulativeTransform() (CumulativeTransform, error) {
+	tf, exists := cumulativeTransforms[t]
+	if !exists {
+		return nil, fmt.Errorf("%v is not a cumulative transfomration", t)
+	}
+	return tf, nil
+}
+
+// MustCumulativeTransform returns the cumulative transformation function associated
+// with the transformation type if applicable, or panics otherwise.
+func (t Type) MustCumulativeTransform() CumulativeTransform {
+	tf, err := t.CumulativeTransform()
+	if err != nil {
+		panic(err)
+	}
+	return tf
+}
+
+// UnaryMultiOutputTransform returns the unary multi-output transformation function
+// associated with the transformation type if applicable, or an error otherwise.
+func (t Type) UnaryMultiOutputTransform() (UnaryMultiOutputTransform, error) {
+	tf, exists := unaryMultiOutputTransforms[t]
+	if !exists {
+		return nil, fmt.Errorf("%v is not a unary multi-output transfomration", t)
+	}
+	return tf, nil
+}
+
+// MustUnaryMultiOutputTransform returns the unary multi-output transformation function
+// associated with the transformation type if applicable, or panics otherwise.
+func (t Type) MustUnaryMultiOutputTransform() UnaryMultiOutputTransform {
+	tf, err := t.UnaryMultiOutputTransform()
+	if err != nil {
+		panic(err)
+	}
+	return tf
+}
+
 // ToProto converts the transformation type to a protobuf message in place.
 func (t Type) ToProto(pb *transformationpb.TransformationType) error {
 	switch t {
@@ -101,6 +160,12 @@ func (t Type) ToProto(pb *transformationpb.TransformationType) error {
 		*pb = transformationpb.TransformationType_ABSOLUTE
 	case PerSecond:
 		*pb = transformationpb.TransformationType_PERSECOND
+	case Increase:
+		*pb = transformationpb.TransformationType_INCREASE
+	case Add:
+		*pb = transformationpb.TransformationType_ADD
+	case Reset:
+		*pb = transformationpb.TransformationType_RESET
 	default:
 		return fmt.Errorf("unknown transformation type: %v", t)
 	}
@@ -114,6 +179,12 @@ func (t *Type) FromProto(pb transformationpb.TransformationType) error {
 		*t = Absolute
 	case transformationpb.TransformationType_PERSECOND:
 		*t = PerSecond
+	case transformationpb.TransformationType_INCREASE:
+		*t = Increase
+	case transformationpb.TransformationType_ADD:
+		*t = Add
+	case transformationpb.TransformationType_RESET:
+		*t = Reset
 	default:
 		return fmt.Errorf("unknown transformation type in proto: %v", pb)
 	}
@@ -174,6 +245,13 @@ var (
 	}
 	binaryTransforms = map[Type]BinaryTransform{
 		PerSecond: perSecond,
+		Increase:  increase,
+	}
+	cumulativeTransforms = map[Type]CumulativeTransform{
+		Add: add,
+	}
+	unaryMultiOutputTransforms = map[Type]UnaryMultiOutputTransform{
+		Reset: reset,
 	}
 	typeStringMap map[string]Type
 )
@@ -186,4 +264,10 @@ func init() {
 	for t := range binaryTransforms {
 		typeStringMap[t.String()] = t
 	}
+	for t := range cumulativeTransforms {
+		typeStringMap[t.String()] = t
+	}
+	for t := range unaryMultiOutputTransforms {
+		typeStringMap[t.String()] = t
+	}
 }
@@ -43,6 +43,9 @@ func TestIsUnaryTransform(t *testing.T) {
 		{typ: Absolute, expected: true},
 		{typ: UnknownType, expected: false},
 		{typ: PerSecond, expected: false},
+		{typ: Increase, expected: false},
+		{typ: Add, expected: false},
+		{typ: Reset, expected: false},
 		{typ: Type(10000), expected: false},
 	}
 
@@ -57,8 +60,11 @@ func TestIsBinaryTransform(t *testing.T) {
 		expected bool
 	}{
 		{typ: PerSecond, expected: true},
+		{typ: Increase, expected: true},
 		{typ: UnknownType, expected: false},
 		{typ: Absolute, expected: false},
+		{typ: Add, expected: false},
+		{typ: Reset, expected: false},
 		{typ: Type(10000), expected: false},
 	}
 
@@ -67,6 +73,53 @@ func TestIsBinaryTransform(t *testing.T) {
 	}
 }
 
+func TestIsCumulativeTransform(t *testing.T) {
+	inputs := []struct {
+		typ      Type
+		expected bool
+	}{
+		{typ: Add, expected: true},
+		{typ: UnknownType, expected: false},
+		{typ: Absolute, expected: false},
+		{typ: PerSecond, expected: false},
+		{typ: Increase, expected: false},
+		{typ: Reset, expected: false},
+		{typ: Type(10000), expected: false},
+	}
+
+	for _, input := range inputs {
+		require.Equal(t, input.expected, input.typ.IsCumulativeTransform())
+	}
+}
+
+func TestIsUnaryMultiOutputTransform(t *testing.T) {
+	inputs := []struct {
+		typ      Type
+		expected bool
+	}{
+		{typ: Reset, expected: true},
+		{typ: UnknownType, expected: false},
+		{typ: Absolute, expected: false},
+		{typ: PerSecond, expected: false},
+		{typ: Increase, expected: false},
+		{typ: Add, expected: false},
+		{typ: Type(10000), expected: false},
+	}
+
+	for _, input := range inputs {
+		require.Equal(t, input.expected, input.typ.IsUnaryMultiOutputTransform())
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	for _, typ := range []Type{Absolute, PerSecond, Increase, Add, Reset} {
+		require.True(t, typ.IsValid())
+	}
+	for _, typ := range []Type{UnknownType, Type(10000)} {
+		require.False(t, typ.IsValid())
+	}
+}
+
 func TestUnaryTransform(t *testing.T) {
 	inputs := []Type{
 		Absolute,
@@ -122,6 +175,7 @@ func TestMustUnaryTransformPanics(t *testing.T) {
 func TestBinaryTransform(t *testing.T) {
 	inputs := []Type{
 		PerSecond,
+		Increase,
 	}
 
 	for _, input := range inputs {
@@ -148,6 +202,7 @@ func TestBinaryTransformErrors(t *testing.T) {
 func TestMustBinaryTransform(t *testing.T) {
 	inputs := []Type{
 		PerSecond,
+		Increase,
 	}
 
 	for _, input := range inputs {
@@ -171,6 +226,36 @@ func TestMustBinaryTransformPanics(t *testing.T) {
 	}
 }
 
+func TestCumulativeTransform(t *testing.T) {
+	tf, err := Add.CumulativeTransform()
+	require.NoError(t, err)
+	require.NotNil(t, tf)
+	require.NotPanics(t, func() { tf = Add.MustCumulativeTransform() })
+	require.NotNil(t, tf)
+
+	for _, input := range []Type{UnknownType, Absolute, PerSecond, Reset, Type(10000)} {
+		tf, err := input.CumulativeTransform()
+		require.Error(t, err)
+		require.Nil(t, tf)
+		require.Panics(t, func() { input.MustCumulativeTransform() })
+	}
+}
+
+func TestUnaryMultiOutputTransform(t *testing.T) {
+	tf, err := Reset.UnaryMultiOutputTransform()
+	require.NoError(t, err)
+	require.NotNil(t, tf)
+	require.NotPanics(t, func() { tf = Reset.MustUnaryMultiOutputTransform() })
+	require.NotNil(t, tf)
+
+	for _, input := range []Type{UnknownType, Absolute, PerSecond, Add, Type(10000)} {
+		tf, err := input.UnaryMultiOutputTransform()
+		require.Error(t, err)
+		require.Nil(t, tf)
+		require.Panics(t, func() { input.MustUnaryMultiOutputTransform() })
+	}
+}
+
 func TestTypeString(t *testing.T) {
 	inputs := []struct {
 		typ      Type
@@ -179,6 +264,9 @@ func TestTypeString(t *testing.T) {
 		{typ: UnknownType, expected: "UnknownType"},
 		{typ: Absolute, expected: "Absolute"},
 		{typ: PerSecond, expected: "PerSecond"},
+		{typ: Increase, expected: "Increase"},
+		{typ: Add, expected: "Add"},
+		{typ: Reset, expected: "Reset"},
 		{typ: Type(1000), expected: "Type(1000)"},
 	}
 
@@ -218,3 +306,25 @@ func TestTypeRoundTrip(t *testing.T) {
 	require.NoError(t, res.FromProto(pb))
 	require.Equal(t, testType, res)
 }
+
+func TestTypeProtoRoundTripAllTypes(t *testing.T) {
+	for _, typ := range []Type{Absolute, PerSecond, Increase, Add, Reset} {
+		var (
+			pb  transformationpb.TransformationType
+			res Type
+		)
+		require.NoError(t, typ.ToProto(&pb))
+		require.NoError(t, res.FromProto(pb))
+		require.Equal(t, typ, res)
+	}
+}
+
+func TestParseType(t *testing.T) {
+	for _, typ := range []Type{Absolute, PerSecond, Increase, Add, Reset} {
+		parsed, err := ParseType(typ.String())
+		require.NoError(t, err)
+		require.Equal(t, typ, parsed)
+	}
+	_, err := ParseType("foo")
+	require.Error(t, err)
+}
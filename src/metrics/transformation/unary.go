@@ -20,7 +20,10 @@
 
 package transformation
 
-import "math"
+import (
+	"math"
+	"time"
+)
 
 func absolute(dp Datapoint) Datapoint {
 	var res Datapoint
@@ -28,3 +31,11 @@ func absolute(dp Datapoint) Datapoint {
 	res.Value = math.Abs(dp.Value)
 	return res
 }
+
+// reset returns the datapoint followed by a zero datapoint half a resolution
+// later, so the value is reset before the next datapoint is produced. When the
+// result is forwarded to a rollup the aggregator defers the zero datapoint to
+// the next window, since each source can only write once per window downstream.
+func reset(dp Datapoint, resolution time.Duration) (Datapoint, Datapoint) {
+	return dp, Datapoint{TimeNanos: dp.TimeNanos + int64(resolution/2), Value: 0}
+}
@@ -22,6 +22,7 @@ package transformation
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -49,3 +50,10 @@ func TestAbsolute(t *testing.T) {
 		require.Equal(t, input.expected, absolute(input.dp))
 	}
 }
+
+func TestReset(t *testing.T) {
+	dp := Datapoint{TimeNanos: time.Unix(1240, 0).UnixNano(), Value: 30}
+	res, zero := reset(dp, 10*time.Second)
+	require.Equal(t, dp, res)
+	require.Equal(t, Datapoint{TimeNanos: time.Unix(1245, 0).UnixNano(), Value: 0}, zero)
+}
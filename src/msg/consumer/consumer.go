@@ -28,8 +28,10 @@ import (
 
 	"github.com/m3db/m3/src/msg/generated/proto/msgpb"
 	"github.com/m3db/m3/src/msg/protocol/proto"
+	xtls "github.com/m3db/m3/src/x/tls"
 
 	"github.com/uber-go/tally"
+	"go.uber.org/atomic"
 )
 
 type listener struct {
@@ -49,6 +51,9 @@ func NewListener(addr string, opts Options) (Listener, error) {
 	if err != nil {
 		return nil, err
 	}
+	if tlsConfig := opts.TLSConfig(); tlsConfig != nil {
+		lis = xtls.NewListener(lis, tlsConfig)
+	}
 	mPool := newMessagePool(opts.MessagePoolOptions())
 	mPool.Init()
 	return &listener{
@@ -88,12 +93,17 @@ func newConsumerMetrics(scope tally.Scope) metrics {
 type consumer struct {
 	sync.Mutex
 
-	opts    Options
-	mPool   *messagePool
-	encoder proto.Encoder
-	decoder proto.Decoder
-	w       *bufio.Writer
-	conn    net.Conn
+	opts              Options
+	mPool             *messagePool
+	encoder           proto.Encoder
+	compressedEncoder proto.Encoder
+	decoder           proto.Decoder
+	w                 *bufio.Writer
+	conn              net.Conn
+
+	// NB: acks are only compressed once the producer has sent compressed
+	// messages, which indicates it is able to decode compressed acks.
+	producerCompresses *atomic.Bool
 
 	ackPb  msgpb.Ack
 	closed bool
@@ -108,19 +118,28 @@ func newConsumer(
 	opts Options,
 	m metrics,
 ) *consumer {
+	var (
+		encoderOpts       = opts.EncoderOptions()
+		compressedEncoder proto.Encoder
+	)
+	if encoderOpts.CompressionType() != proto.NoCompression {
+		compressedEncoder = proto.NewEncoder(encoderOpts)
+	}
 	return &consumer{
-		opts:    opts,
-		mPool:   mPool,
-		encoder: proto.NewEncoder(opts.EncoderOptions()),
+		opts:              opts,
+		mPool:             mPool,
+		encoder:           proto.NewEncoder(encoderOpts.SetCompressionType(proto.NoCompression)),
+		compressedEncoder: compressedEncoder,
 		decoder: proto.NewDecoder(
 			bufio.NewReaderSize(conn, opts.ConnectionReadBufferSize()),
 			opts.DecoderOptions(),
 		),
-		w:      bufio.NewWriterSize(conn, opts.ConnectionWriteBufferSize()),
-		conn:   conn,
-		closed: false,
-		doneCh: make(chan struct{}),
-		m:      m,
+		w:                  bufio.NewWriterSize(conn, opts.ConnectionWriteBufferSize()),
+		conn:               conn,
+		producerCompresses: atomic.NewBool(false),
+		closed:             false,
+		doneCh:             make(chan struct{}),
+		m:                  m,
 	}
 }
 
@@ -140,6 +159,9 @@ func (c *consumer) Message() (Message, error) {
 		c.m.messageDecodeError.Inc(1)
 		return nil, err
 	}
+	if c.compressedEncoder != nil && !c.producerCompresses.Load() && c.decoder.ReceivedCompressed() {
+		c.producerCompresses.Store(true)
+	}
 	c.m.messageReceived.Inc(1)
 	return m, nil
 }
@@ -189,13 +211,20 @@ func (c *consumer) tryAckAndFlush() {
 }
 
 func (c *consumer) encodeAckWithLock(ackLen int) error {
-	err := c.encoder.Encode(&c.ackPb)
+	// The decoder accepts compressed messages, which is advertised to the
+	// producer in every ack so it can start compressing the messages.
+	c.ackPb.AcceptsCompressed = true
+	encoder := c.encoder
+	if c.compressedEncoder != nil && c.producerCompresses.Load() {
+		encoder = c.compressedEncoder
+	}
+	err := encoder.Encode(&c.ackPb)
 	c.ackPb.Metadata = c.ackPb.Metadata[:0]
 	if err != nil {
 		c.m.ackEncodeError.Inc(1)
 		return err
 	}
-	_, err = c.w.Write(c.encoder.Bytes())
+	_, err = c.w.Write(encoder.Bytes())
 	if err != nil {
 		c.m.ackWriteError.Inc(1)
 		return err
@@ -21,6 +21,7 @@
 package consumer
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"net"
@@ -250,6 +251,60 @@ func TestConsumerAckBuffer(t *testing.T) {
 	m2.Ack()
 }
 
+func TestConsumerCompressesAcksAfterProducerCompresses(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	encOpts := proto.NewOptions().SetCompressionType(proto.SnappyCompression)
+	opts := testOptions().SetEncoderOptions(encOpts)
+	l, err := NewListener("127.0.0.1:0", opts)
+	require.NoError(t, err)
+	defer l.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+
+	c, err := l.Accept()
+	require.NoError(t, err)
+
+	mockEncoder := proto.NewMockEncoder(ctrl)
+	mockCompressedEncoder := proto.NewMockEncoder(ctrl)
+	cc := c.(*consumer)
+	require.NotNil(t, cc.compressedEncoder)
+	cc.encoder = mockEncoder
+	cc.compressedEncoder = mockCompressedEncoder
+
+	acceptsCompressedFn := func(m proto.Marshaler) {
+		require.True(t, m.(*msgpb.Ack).AcceptsCompressed)
+	}
+
+	// Acks are not compressed before the producer sends compressed messages.
+	require.NoError(t, produce(conn, &testMsg1))
+	m, err := cc.Message()
+	require.NoError(t, err)
+	mockEncoder.EXPECT().Encode(gomock.Any()).Do(acceptsCompressedFn)
+	mockEncoder.EXPECT().Bytes()
+	m.Ack()
+
+	// Acks are compressed after the producer sends compressed messages.
+	compressible := msgpb.Message{
+		Metadata: testMsg2.Metadata,
+		Value:    bytes.Repeat([]byte("bar"), 100),
+	}
+	encoder := proto.NewEncoder(encOpts)
+	require.NoError(t, encoder.Encode(&compressible))
+	_, err = conn.Write(encoder.Bytes())
+	require.NoError(t, err)
+	m, err = cc.Message()
+	require.NoError(t, err)
+	require.Equal(t, compressible.Value, m.Bytes())
+	mockCompressedEncoder.EXPECT().Encode(gomock.Any()).Do(acceptsCompressedFn)
+	mockCompressedEncoder.EXPECT().Bytes()
+	m.Ack()
+}
+
 func TestConsumerAckAfterClosed(t *testing.T) {
 	defer leaktest.Check(t)()
 
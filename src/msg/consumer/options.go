@@ -21,6 +21,7 @@
 package consumer
 
 import (
+	"crypto/tls"
 	"time"
 
 	"github.com/m3db/m3/src/msg/protocol/proto"
@@ -43,6 +44,7 @@ type options struct {
 	writeBufferSize  int
 	readBufferSize   int
 	iOpts            instrument.Options
+	tlsConfig        *tls.Config
 }
 
 // NewOptions creates a new options.
@@ -138,3 +140,13 @@ func (opts *options) SetInstrumentOptions(value instrument.Options) Options {
 	o.iOpts = value
 	return &o
 }
+
+func (opts *options) TLSConfig() *tls.Config {
+	return opts.tlsConfig
+}
+
+func (opts *options) SetTLSConfig(value *tls.Config) Options {
+	o := *opts
+	o.tlsConfig = value
+	return &o
+}
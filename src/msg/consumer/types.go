@@ -21,6 +21,7 @@
 package consumer
 
 import (
+	"crypto/tls"
 	"net"
 	"time"
 
@@ -111,6 +112,12 @@ type Options interface {
 
 	// SetInstrumentOptions sets the instrument options.
 	SetInstrumentOptions(value instrument.Options) Options
+
+	// TLSConfig returns the TLS config, if set connections are served over TLS.
+	TLSConfig() *tls.Config
+
+	// SetTLSConfig sets the TLS config, if set connections are served over TLS.
+	SetTLSConfig(value *tls.Config) Options
 }
 
 // MessageProcessor processes the message. When a MessageProcessor was set in the
@@ -101,7 +101,8 @@ func (m *Message) GetValue() []byte {
 }
 
 type Ack struct {
-	Metadata []Metadata `protobuf:"bytes,1,rep,name=metadata" json:"metadata"`
+	Metadata          []Metadata `protobuf:"bytes,1,rep,name=metadata" json:"metadata"`
+	AcceptsCompressed bool       `protobuf:"varint,2,opt,name=accepts_compressed,json=acceptsCompressed,proto3" json:"accepts_compressed,omitempty"`
 }
 
 func (m *Ack) Reset()                    { *m = Ack{} }
@@ -116,6 +117,13 @@ func (m *Ack) GetMetadata() []Metadata {
 	return nil
 }
 
+func (m *Ack) GetAcceptsCompressed() bool {
+	if m != nil {
+		return m.AcceptsCompressed
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*Metadata)(nil), "msgpb.Metadata")
 	proto.RegisterType((*Message)(nil), "msgpb.Message")
@@ -208,6 +216,16 @@ func (m *Ack) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if m.AcceptsCompressed {
+		dAtA[i] = 0x10
+		i++
+		if m.AcceptsCompressed {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	return i, nil
 }
 
@@ -253,6 +271,9 @@ func (m *Ack) Size() (n int) {
 			n += 1 + l + sovMsg(uint64(l))
 		}
 	}
+	if m.AcceptsCompressed {
+		n += 2
+	}
 	return n
 }
 
@@ -528,6 +549,26 @@ func (m *Ack) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AcceptsCompressed", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMsg
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AcceptsCompressed = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMsg(dAtA[iNdEx:])
@@ -659,20 +700,21 @@ func init() {
 }
 
 var fileDescriptorMsg = []byte{
-	// 232 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xb2, 0x4a, 0xcf, 0x2c, 0xc9,
-	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0xcf, 0x35, 0x4e, 0x49, 0xd2, 0xcf, 0x35, 0xd6, 0x2f,
-	0x2e, 0x4a, 0xd6, 0xcf, 0x2d, 0x4e, 0xd7, 0x4f, 0x4f, 0xcd, 0x4b, 0x2d, 0x4a, 0x2c, 0x49, 0x4d,
-	0xd1, 0x2f, 0x28, 0xca, 0x2f, 0xc9, 0x07, 0x89, 0x15, 0x24, 0x81, 0x48, 0x3d, 0x30, 0x5f, 0x88,
-	0x15, 0x2c, 0x20, 0xa5, 0x8b, 0x64, 0x44, 0x7a, 0x7e, 0x7a, 0x3e, 0x44, 0x75, 0x52, 0x69, 0x1a,
-	0x98, 0x07, 0xd1, 0x0a, 0x62, 0x41, 0x74, 0x29, 0x19, 0x70, 0x71, 0xf8, 0xa6, 0x96, 0x24, 0xa6,
-	0x24, 0x96, 0x24, 0x0a, 0x89, 0x70, 0xb1, 0x16, 0x67, 0x24, 0x16, 0xa5, 0x48, 0x30, 0x2a, 0x30,
-	0x6a, 0xb0, 0x04, 0x41, 0x38, 0x42, 0x7c, 0x5c, 0x4c, 0x99, 0x29, 0x12, 0x4c, 0x60, 0x21, 0xa6,
-	0xcc, 0x14, 0xa5, 0x20, 0x2e, 0x76, 0xdf, 0xd4, 0xe2, 0xe2, 0xc4, 0xf4, 0x54, 0x21, 0x43, 0x2e,
-	0x8e, 0x5c, 0xa8, 0x66, 0xb0, 0x1e, 0x6e, 0x23, 0x7e, 0x3d, 0xb0, 0x2b, 0xf4, 0x60, 0x66, 0x3a,
-	0xb1, 0x9c, 0xb8, 0x27, 0xcf, 0x10, 0x04, 0x57, 0x06, 0xb2, 0xa3, 0x2c, 0x31, 0xa7, 0x34, 0x15,
-	0x6c, 0x20, 0x4f, 0x10, 0x84, 0xa3, 0x64, 0xc1, 0xc5, 0xec, 0x98, 0x9c, 0x8d, 0x66, 0x1e, 0x33,
-	0x11, 0xe6, 0x39, 0x09, 0x9c, 0x78, 0x24, 0xc7, 0x78, 0xe1, 0x91, 0x1c, 0xe3, 0x83, 0x47, 0x72,
-	0x8c, 0x13, 0x1e, 0xcb, 0x31, 0x24, 0xb1, 0x81, 0x3d, 0x66, 0x0c, 0x08, 0x00, 0x00, 0xff, 0xff,
-	0x31, 0xd7, 0x86, 0x7e, 0x4c, 0x01, 0x00, 0x00,
+	// 251 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe3, 0xb2, 0x4a, 0xcf, 0x2c, 0xc9,
+	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0xcf, 0x35, 0x4e, 0x49, 0x02, 0x12, 0xfa, 0xc5, 0x45,
+	0xc9, 0xfa, 0xb9, 0xc5, 0xe9, 0xfa, 0xe9, 0xa9, 0x79, 0xa9, 0x45, 0x89, 0x25, 0xa9, 0x29, 0xfa,
+	0x05, 0x45, 0xf9, 0x25, 0xf9, 0x20, 0xb1, 0x82, 0x24, 0x10, 0xa9, 0x07, 0xe6, 0x0b, 0xb1, 0x82,
+	0x05, 0xa4, 0x74, 0x91, 0x8c, 0x48, 0xcf, 0x4f, 0xcf, 0x87, 0xa8, 0x4e, 0x2a, 0x4d, 0x03, 0xf3,
+	0x20, 0x5a, 0x41, 0x2c, 0x88, 0x2e, 0x25, 0x03, 0x2e, 0x0e, 0xdf, 0xd4, 0x92, 0xc4, 0x94, 0xc4,
+	0x92, 0x44, 0x21, 0x11, 0x2e, 0xd6, 0xe2, 0x8c, 0xc4, 0xa2, 0x14, 0x09, 0x46, 0x05, 0x46, 0x0d,
+	0x96, 0x20, 0x08, 0x47, 0x88, 0x8f, 0x8b, 0x29, 0x33, 0x45, 0x82, 0x09, 0x2c, 0x04, 0x64, 0x29,
+	0x05, 0x71, 0xb1, 0xfb, 0xa6, 0x16, 0x17, 0x27, 0xa6, 0xa7, 0x0a, 0x19, 0x72, 0x71, 0xe4, 0x42,
+	0x35, 0x83, 0xf5, 0x70, 0x1b, 0xf1, 0xeb, 0x81, 0x5d, 0xa1, 0x07, 0x33, 0xd3, 0x89, 0xe5, 0xc4,
+	0x3d, 0x79, 0x86, 0x20, 0xb8, 0x32, 0x90, 0x1d, 0x65, 0x89, 0x39, 0xa5, 0xa9, 0x60, 0x03, 0x79,
+	0x82, 0x20, 0x1c, 0xa5, 0x74, 0x2e, 0x66, 0xc7, 0xe4, 0x6c, 0x34, 0xf3, 0x98, 0x89, 0x31, 0x4f,
+	0x97, 0x4b, 0x28, 0x31, 0x39, 0x39, 0xb5, 0xa0, 0xa4, 0x38, 0x1e, 0xe8, 0xe3, 0x82, 0x22, 0xa0,
+	0xcb, 0x52, 0x21, 0xae, 0xe5, 0x08, 0x12, 0x84, 0xca, 0x38, 0xc3, 0x25, 0x9c, 0x04, 0x4e, 0x3c,
+	0x92, 0x63, 0xbc, 0x00, 0xc4, 0x0f, 0x80, 0x78, 0xc2, 0x63, 0x39, 0x86, 0x24, 0x36, 0x70, 0x38,
+	0x18, 0x03, 0x00, 0x9d, 0xca, 0x6f, 0xc5, 0x7b, 0x01, 0x00, 0x00,
 }
@@ -0,0 +1,616 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package buffer
+
+import (
+	"container/list"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/msg/producer"
+	"github.com/m3db/m3x/log"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/atomic"
+)
+
+var (
+	errNoDiskOptions = errors.New("no disk options")
+)
+
+type diskBufferMetrics struct {
+	messageTooLarge tally.Counter
+	bufferFull      tally.Counter
+	messageSpilled  tally.Counter
+	byteSpilled     tally.Counter
+	messageDrained  tally.Counter
+	messageDropped  tally.Counter
+	byteDropped     tally.Counter
+	drainErrors     tally.Counter
+	writeErrors     tally.Counter
+	syncErrors      tally.Counter
+	readErrors      tally.Counter
+	removeErrors    tally.Counter
+	segmentCreated  tally.Counter
+	segmentRemoved  tally.Counter
+	segmentExpired  tally.Counter
+	messageReplayed tally.Counter
+	messageBuffered tally.Gauge
+	byteBuffered    tally.Gauge
+	messagePending  tally.Gauge
+	byteOnDisk      tally.Gauge
+	segmentsOnDisk  tally.Gauge
+}
+
+func newDiskBufferMetrics(scope tally.Scope) diskBufferMetrics {
+	return diskBufferMetrics{
+		messageTooLarge: scope.Counter("message-too-large"),
+		bufferFull:      scope.Counter("buffer-full"),
+		messageSpilled:  scope.Counter("message-spilled"),
+		byteSpilled:     scope.Counter("byte-spilled"),
+		messageDrained:  scope.Counter("message-drained"),
+		messageDropped:  scope.Counter("buffer-message-dropped"),
+		byteDropped:     scope.Counter("buffer-byte-dropped"),
+		drainErrors:     scope.Counter("drain-errors"),
+		writeErrors:     scope.Counter("disk-write-errors"),
+		syncErrors:      scope.Counter("disk-sync-errors"),
+		readErrors:      scope.Counter("disk-read-errors"),
+		removeErrors:    scope.Counter("segment-remove-errors"),
+		segmentCreated:  scope.Counter("segment-created"),
+		segmentRemoved:  scope.Counter("segment-removed"),
+		segmentExpired:  scope.Counter("segment-expired"),
+		messageReplayed: scope.Counter("message-replayed"),
+		messageBuffered: scope.Gauge("message-buffered"),
+		byteBuffered:    scope.Gauge("byte-buffered"),
+		messagePending:  scope.Gauge("message-pending"),
+		byteOnDisk:      scope.Gauge("byte-on-disk"),
+		segmentsOnDisk:  scope.Gauge("segments-on-disk"),
+	}
+}
+
+// diskBuffer is a buffer that persists every message to segment files on
+// local disk before it is written out. Messages are held in memory until the
+// memory budget is exceeded, after which messages are spilled to disk and
+// written out once the messages held in memory have been consumed. Segment
+// files are removed once all their messages have been consumed by every
+// consumer service, and the messages in the segment files left behind by
+// a previous run are replayed on startup, so a message is written out at
+// least once. Messages dropped by the writer are not considered consumed,
+// they are kept on disk until their segment is dropped for exceeding the
+// max segment age or to make room for new messages.
+// nolint: maligned
+type diskBuffer struct {
+	sync.Mutex
+
+	opts           Options
+	dOpts          DiskOptions
+	maxMemorySize  uint64
+	maxMessageSize int
+	maxDiskSize    int
+	logger         log.Logger
+	nowFn          func() time.Time
+	m              diskBufferMetrics
+
+	// segments are ordered from the oldest to the newest, the newest
+	// segment is the active segment if it is not sealed.
+	segments   []*segment
+	nextIndex  uint64
+	diskSize   int
+	numPending *atomic.Int64
+	memSize    *atomic.Uint64
+	numInMem   *atomic.Int64
+	writeFn    producer.WriteFn
+	isClosed   bool
+	doneCh     chan struct{}
+	wg         sync.WaitGroup
+}
+
+// NewDiskBuffer returns a new disk buffer, messages persisted in the
+// segment files left behind by a previous run will be replayed once the
+// buffer is initialized.
+func NewDiskBuffer(opts Options) (producer.PersistentBuffer, error) {
+	if opts == nil {
+		opts = NewOptions()
+	}
+	if opts.DiskOptions() == nil {
+		return nil, errNoDiskOptions
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	dOpts := opts.DiskOptions()
+	if err := os.MkdirAll(dOpts.Path(), newDirectoryMode); err != nil {
+		return nil, err
+	}
+	b := &diskBuffer{
+		opts:           opts,
+		dOpts:          dOpts,
+		maxMemorySize:  uint64(opts.MaxBufferSize()),
+		maxMessageSize: opts.MaxMessageSize(),
+		maxDiskSize:    dOpts.MaxDiskSize(),
+		logger:         opts.InstrumentOptions().Logger(),
+		nowFn:          time.Now,
+		m:              newDiskBufferMetrics(opts.InstrumentOptions().MetricsScope()),
+		numPending:     atomic.NewInt64(0),
+		memSize:        atomic.NewUint64(0),
+		numInMem:       atomic.NewInt64(0),
+		doneCh:         make(chan struct{}),
+	}
+	if err := b.openSegments(); err != nil {
+		b.closeSegments(b.segments)
+		return nil, err
+	}
+	return b, nil
+}
+
+// openSegments opens the segment files left behind by a previous run.
+func (b *diskBuffer) openSegments() error {
+	indexes, err := segmentFileIndexes(b.dOpts.Path())
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		s, err := openSegment(segmentFilePath(b.dOpts.Path(), index), index)
+		if err != nil {
+			return err
+		}
+		b.nextIndex = index + 1
+		if s.numRecords == 0 {
+			if err := s.remove(); err != nil {
+				return err
+			}
+			continue
+		}
+		b.segments = append(b.segments, s)
+		b.diskSize += s.size
+		b.numPending.Add(int64(s.numRecords))
+		b.m.messageReplayed.Inc(int64(s.numRecords))
+	}
+	return nil
+}
+
+func (b *diskBuffer) SetWriteFn(fn producer.WriteFn) {
+	b.Lock()
+	b.writeFn = fn
+	b.Unlock()
+}
+
+func (b *diskBuffer) Add(m producer.Message) (*producer.RefCountedMessage, error) {
+	s := m.Size()
+	if s > b.maxMessageSize {
+		b.m.messageTooLarge.Inc(1)
+		return nil, errMessageTooLarge
+	}
+	b.Lock()
+	if b.isClosed {
+		b.Unlock()
+		return nil, errBufferClosed
+	}
+	if err := b.ensureDiskSpaceWithLock(s + recordHeaderLen); err != nil {
+		b.Unlock()
+		return nil, err
+	}
+	seg, err := b.activeSegmentWithLock(s + recordHeaderLen)
+	if err != nil {
+		b.m.writeErrors.Inc(1)
+		b.Unlock()
+		return nil, err
+	}
+	rec, err := seg.append(m.Shard(), m.Bytes())
+	if err != nil {
+		b.m.writeErrors.Inc(1)
+		b.Unlock()
+		return nil, err
+	}
+	b.diskSize += recordHeaderLen + s
+	if b.dOpts.SyncInterval() == 0 {
+		if err := seg.sync(); err != nil {
+			// NB: The message is not added to the buffer, so it should
+			// not block the segment from being removed.
+			seg.finalize(nil)
+			b.m.syncErrors.Inc(1)
+			b.Unlock()
+			return nil, err
+		}
+	}
+
+	// NB: Once there are messages spilled to disk, new messages are spilled
+	// as well so messages are written out roughly in the order they are added.
+	messageSize := uint64(s)
+	if b.numPending.Load() > 0 || b.memSize.Load()+messageSize > b.maxMemorySize {
+		seg.addPending(rec)
+		b.numPending.Inc()
+		b.Unlock()
+		// The message has been persisted and will be written out from disk,
+		// so the original message is no longer needed.
+		m.Finalize(producer.Persisted)
+		b.m.messageSpilled.Inc(1)
+		b.m.byteSpilled.Inc(int64(s))
+		return nil, nil
+	}
+	rm := b.newRefCountedMessageWithLock(seg, m)
+	b.Unlock()
+	return rm, nil
+}
+
+// ensureDiskSpaceWithLock makes sure there is enough disk space for the
+// new message, by dropping the oldest segments if the buffer is configured
+// to drop the oldest messages when full.
+func (b *diskBuffer) ensureDiskSpaceWithLock(recordSize int) error {
+	if b.diskSize+recordSize <= b.maxDiskSize {
+		return nil
+	}
+	if b.opts.OnFullStrategy() == ReturnError {
+		b.m.bufferFull.Inc(1)
+		return errBufferFull
+	}
+	for len(b.segments) > 0 && b.diskSize+recordSize > b.maxDiskSize {
+		b.dropSegmentWithLock(0)
+	}
+	return nil
+}
+
+// activeSegmentWithLock returns the segment the new message should be
+// appended to, a new segment is created if the active segment is full.
+func (b *diskBuffer) activeSegmentWithLock(recordSize int) (*segment, error) {
+	if n := len(b.segments); n > 0 {
+		active := b.segments[n-1]
+		if !active.sealed && active.size+recordSize <= b.dOpts.MaxSegmentSize() {
+			return active, nil
+		}
+		active.sealed = true
+	}
+	s, err := newSegment(b.dOpts.Path(), b.nextIndex, b.nowFn())
+	if err != nil {
+		return nil, err
+	}
+	b.nextIndex++
+	b.segments = append(b.segments, s)
+	b.m.segmentCreated.Inc(1)
+	return s, nil
+}
+
+func (b *diskBuffer) newRefCountedMessageWithLock(
+	seg *segment,
+	m producer.Message,
+) *producer.RefCountedMessage {
+	dm := &diskBufferMessage{Message: m, b: b, seg: seg}
+	rm := producer.NewRefCountedMessage(dm, nil)
+	b.memSize.Add(uint64(m.Size()))
+	b.numInMem.Inc()
+	dm.e = seg.addInMemory(rm)
+	return rm
+}
+
+// onFinalize is called when a message held in memory is finalized. Only the
+// consumed messages are finalized in the segment, the messages finalized for
+// any other reason are no longer held in memory but are kept on disk.
+func (b *diskBuffer) onFinalize(
+	seg *segment,
+	e *list.Element,
+	size int,
+	r producer.FinalizeReason,
+) {
+	if r == producer.Consumed {
+		seg.finalize(e)
+	} else {
+		seg.release(e)
+	}
+	b.memSize.Sub(uint64(size))
+	b.numInMem.Dec()
+}
+
+// dropSegmentWithLock drops all the messages not yet finalized in the
+// segment and removes the segment file.
+func (b *diskBuffer) dropSegmentWithLock(idx int) {
+	s := b.segments[idx]
+	s.sealed = true
+	numPending := s.numPending()
+	numDropped, bytesDropped := s.drop()
+	b.numPending.Sub(int64(numPending))
+	b.m.messageDropped.Inc(int64(numDropped))
+	b.m.byteDropped.Inc(int64(bytesDropped))
+	b.removeSegmentWithLock(idx)
+}
+
+func (b *diskBuffer) removeSegmentWithLock(idx int) {
+	s := b.segments[idx]
+	if err := s.remove(); err != nil {
+		b.m.removeErrors.Inc(1)
+		b.logger.Errorf("could not remove segment file %s: %v", s.path, err)
+	}
+	b.diskSize -= s.size
+	b.segments = append(b.segments[:idx], b.segments[idx+1:]...)
+	b.m.segmentRemoved.Inc(1)
+}
+
+func (b *diskBuffer) Init() {
+	b.wg.Add(2)
+	go func() {
+		b.drainUntilClose()
+		b.wg.Done()
+	}()
+	go func() {
+		b.cleanupUntilClose()
+		b.wg.Done()
+	}()
+	if b.dOpts.SyncInterval() > 0 {
+		b.wg.Add(1)
+		go func() {
+			b.syncUntilClose()
+			b.wg.Done()
+		}()
+	}
+}
+
+func (b *diskBuffer) syncUntilClose() {
+	ticker := time.NewTicker(b.dOpts.SyncInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.sync()
+		case <-b.doneCh:
+			return
+		}
+	}
+}
+
+// sync commits the messages appended to the segment files since the last
+// sync to stable storage.
+func (b *diskBuffer) sync() {
+	b.Lock()
+	defer b.Unlock()
+
+	for _, s := range b.segments {
+		if err := s.sync(); err != nil {
+			b.m.syncErrors.Inc(1)
+			b.logger.Errorf("could not sync segment file %s: %v", s.path, err)
+		}
+	}
+}
+
+func (b *diskBuffer) drainUntilClose() {
+	ticker := time.NewTicker(b.dOpts.DrainInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.drain()
+		case <-b.doneCh:
+			return
+		}
+	}
+}
+
+// drain writes out the messages spilled to disk as long as there is
+// room in the memory budget.
+func (b *diskBuffer) drain() {
+	b.Lock()
+	writeFn := b.writeFn
+	b.Unlock()
+	if writeFn == nil {
+		return
+	}
+	for b.memSize.Load() < b.maxMemorySize {
+		rm, ok := b.nextPending()
+		if !ok {
+			return
+		}
+		if rm == nil {
+			continue
+		}
+		b.m.messageDrained.Inc(1)
+		if err := writeFn(rm); err != nil {
+			// NB: The writer drops the message on errors.
+			b.m.drainErrors.Inc(1)
+		}
+	}
+}
+
+// nextPending reads the oldest pending message from disk if it fits in the
+// memory budget, it returns a nil message if the pending message can not
+// be read.
+func (b *diskBuffer) nextPending() (*producer.RefCountedMessage, bool) {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.numPending.Load() == 0 {
+		return nil, false
+	}
+	for _, seg := range b.segments {
+		rec, ok := seg.peekPending()
+		if !ok {
+			continue
+		}
+		// NB: A message larger than the memory budget is still written
+		// out once nothing else is held in memory.
+		if memSize := b.memSize.Load(); memSize > 0 && memSize+uint64(rec.size) > b.maxMemorySize {
+			return nil, false
+		}
+		seg.popPending()
+		data, err := seg.read(rec)
+		if err != nil {
+			b.numPending.Dec()
+			b.m.readErrors.Inc(1)
+			b.m.messageDropped.Inc(1)
+			b.m.byteDropped.Inc(int64(rec.size))
+			seg.finalize(nil)
+			b.logger.Errorf("could not read message from segment file %s: %v", seg.path, err)
+			return nil, true
+		}
+		// NB: The message is tracked in memory before it is no longer pending
+		// so it is always accounted for while waiting for consumption on close.
+		rm := b.newRefCountedMessageWithLock(seg, newPersistedMessage(rec.shard, data))
+		b.numPending.Dec()
+		return rm, true
+	}
+	return nil, false
+}
+
+func (b *diskBuffer) cleanupUntilClose() {
+	ticker := time.NewTicker(b.opts.CleanupRetryOptions().InitialBackoff())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.cleanup()
+		case <-b.doneCh:
+			return
+		}
+	}
+}
+
+// cleanup removes the segments whose messages have all been finalized,
+// and drops the segments that are older than the max segment age.
+func (b *diskBuffer) cleanup() {
+	b.Lock()
+	b.cleanupWithLock()
+	b.Unlock()
+}
+
+func (b *diskBuffer) cleanupWithLock() {
+	var (
+		now    = b.nowFn()
+		maxAge = b.dOpts.MaxSegmentAge()
+	)
+	for i := 0; i < len(b.segments); {
+		s := b.segments[i]
+		if maxAge > 0 && now.Sub(s.createdAt) > maxAge {
+			b.dropSegmentWithLock(i)
+			b.m.segmentExpired.Inc(1)
+			continue
+		}
+		if s.isFinalized() {
+			b.removeSegmentWithLock(i)
+			continue
+		}
+		i++
+	}
+	b.m.messageBuffered.Update(float64(b.numInMem.Load()))
+	b.m.byteBuffered.Update(float64(b.memSize.Load()))
+	b.m.messagePending.Update(float64(b.numPending.Load()))
+	b.m.byteOnDisk.Update(float64(b.diskSize))
+	b.m.segmentsOnDisk.Update(float64(len(b.segments)))
+}
+
+// Close stops the buffer from accepting new messages. If the CloseType is
+// WaitForConsumption, it blocks until all the messages have been consumed.
+// If the CloseType is DropEverything, it drops all the messages held in memory
+// but keeps the segment files on disk so the messages not yet consumed will be
+// replayed the next time the buffer is created.
+func (b *diskBuffer) Close(ct producer.CloseType) {
+	// Stop taking writes right away.
+	b.Lock()
+	if b.isClosed {
+		b.Unlock()
+		return
+	}
+	b.isClosed = true
+	canDrain := b.writeFn != nil
+	b.Unlock()
+	if ct == producer.WaitForConsumption {
+		b.waitUntilAllDataConsumed(canDrain)
+	}
+	close(b.doneCh)
+	b.wg.Wait()
+
+	// Remove the segments that have been fully consumed before dropping
+	// the rest of the messages, so only the segments with messages not yet
+	// consumed are left on disk.
+	b.Lock()
+	if n := len(b.segments); n > 0 {
+		b.segments[n-1].sealed = true
+	}
+	b.cleanupWithLock()
+	segments := b.segments
+	b.segments = nil
+	b.Unlock()
+	for _, s := range segments {
+		s.drop()
+	}
+	b.closeSegments(segments)
+}
+
+func (b *diskBuffer) closeSegments(segments []*segment) {
+	for _, s := range segments {
+		if err := s.close(); err != nil {
+			b.logger.Errorf("could not close segment file %s: %v", s.path, err)
+		}
+	}
+}
+
+func (b *diskBuffer) waitUntilAllDataConsumed(canDrain bool) {
+	isConsumed := func() bool {
+		if canDrain && b.numPending.Load() > 0 {
+			return false
+		}
+		return b.numInMem.Load() == 0
+	}
+	if isConsumed() {
+		return
+	}
+	ticker := time.NewTicker(b.opts.CloseCheckInterval())
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if isConsumed() {
+			return
+		}
+	}
+}
+
+// diskBufferMessage is a message held in memory by the disk buffer.
+type diskBufferMessage struct {
+	producer.Message
+
+	b   *diskBuffer
+	seg *segment
+	e   *list.Element
+}
+
+func (m *diskBufferMessage) Finalize(r producer.FinalizeReason) {
+	m.b.onFinalize(m.seg, m.e, m.Message.Size(), r)
+	m.Message.Finalize(r)
+}
+
+// persistedMessage is a message read from a segment file.
+type persistedMessage struct {
+	shard uint32
+	data  []byte
+}
+
+func newPersistedMessage(shard uint32, data []byte) producer.Message {
+	return &persistedMessage{shard: shard, data: data}
+}
+
+func (m *persistedMessage) Shard() uint32 { return m.shard }
+
+func (m *persistedMessage) Bytes() []byte { return m.data }
+
+func (m *persistedMessage) Size() int { return len(m.data) }
+
+func (m *persistedMessage) Finalize(producer.FinalizeReason) { m.data = nil }
@@ -0,0 +1,518 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package buffer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/msg/producer"
+	"github.com/m3db/m3x/retry"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskOptionsValidation(t *testing.T) {
+	opts := NewDiskOptions()
+	require.Equal(t, errEmptyDiskPath, opts.Validate())
+
+	opts = opts.SetPath("/tmp")
+	require.NoError(t, opts.Validate())
+
+	opts = opts.SetSyncInterval(-1)
+	require.Equal(t, errNegativeSyncInterval, opts.Validate())
+
+	opts = opts.SetDrainInterval(0)
+	require.Equal(t, errInvalidDrainInterval, opts.Validate())
+
+	opts = opts.SetMaxSegmentAge(-1)
+	require.Equal(t, errNegativeMaxSegmentAge, opts.Validate())
+
+	opts = opts.SetMaxDiskSize(100).SetMaxSegmentSize(200)
+	require.Equal(t, errInvalidMaxDiskSize, opts.Validate())
+
+	opts = opts.SetMaxSegmentSize(0)
+	require.Equal(t, errInvalidMaxSegmentSize, opts.Validate())
+
+	bOpts := NewOptions().
+		SetMaxMessageSize(100).
+		SetDiskOptions(NewDiskOptions().SetPath("/tmp").SetMaxSegmentSize(100))
+	require.Equal(t, errInvalidMaxSegmentSize, bOpts.Validate())
+}
+
+func TestNewDiskBufferNoDiskOptions(t *testing.T) {
+	_, err := NewDiskBuffer(NewOptions())
+	require.Equal(t, errNoDiskOptions, err)
+}
+
+func TestDiskBufferHoldsMessageInMemory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testDiskBufferDir(t)
+	defer os.RemoveAll(dir)
+
+	b := mustNewDiskBuffer(t, testDiskBufferOptions(dir))
+	defer b.Close(producer.DropEverything)
+
+	mm := testDiskBufferMessage(ctrl, 1, []byte("foo"))
+	rm, err := b.Add(mm)
+	require.NoError(t, err)
+	require.NotNil(t, rm)
+	require.Equal(t, 3, int(b.memSize.Load()))
+	require.Equal(t, 0, int(b.numPending.Load()))
+	require.Equal(t, 1, len(b.segments))
+	require.Equal(t, recordHeaderLen+3, b.diskSize)
+	require.Equal(t, []byte("foo"), rm.Bytes())
+	require.Equal(t, uint32(1), rm.Shard())
+
+	mm.EXPECT().Finalize(producer.Consumed)
+	rm.IncRef()
+	rm.DecRef()
+	require.Equal(t, 0, int(b.memSize.Load()))
+	require.Equal(t, 0, b.segments[0].numInMemory())
+	require.Equal(t, int64(1), b.segments[0].numFinalized.Load())
+}
+
+func TestDiskBufferKeepsDroppedMessageOnDisk(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testDiskBufferDir(t)
+	defer os.RemoveAll(dir)
+
+	b := mustNewDiskBuffer(t, testDiskBufferOptions(dir))
+
+	mm := testDiskBufferMessage(ctrl, 1, []byte("foo"))
+	rm, err := b.Add(mm)
+	require.NoError(t, err)
+
+	// A dropped message is no longer held in memory but is not finalized
+	// in the segment, so it is replayed after restart.
+	mm.EXPECT().Finalize(producer.Dropped)
+	require.True(t, rm.Drop())
+	require.Equal(t, 0, int(b.memSize.Load()))
+	require.Equal(t, 0, int(b.numInMem.Load()))
+	require.Equal(t, 0, b.segments[0].numInMemory())
+	require.Equal(t, int64(0), b.segments[0].numFinalized.Load())
+
+	b.Close(producer.WaitForConsumption)
+	require.Equal(t, 1, len(testSegmentFiles(t, dir)))
+
+	b = mustNewDiskBuffer(t, testDiskBufferOptions(dir))
+	defer b.Close(producer.DropEverything)
+	require.Equal(t, 1, int(b.numPending.Load()))
+}
+
+func TestDiskBufferSyncEveryMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testDiskBufferDir(t)
+	defer os.RemoveAll(dir)
+
+	opts := testDiskBufferOptions(dir)
+	b := mustNewDiskBuffer(t, opts)
+	defer b.Close(producer.DropEverything)
+
+	mm := testDiskBufferMessage(ctrl, 1, []byte("foo"))
+	mm.EXPECT().Finalize(producer.Dropped)
+	_, err := b.Add(mm)
+	require.NoError(t, err)
+	require.True(t, b.segments[0].dirty)
+	b.sync()
+	require.False(t, b.segments[0].dirty)
+
+	opts = opts.SetDiskOptions(opts.DiskOptions().SetPath(dir + "-sync").SetSyncInterval(0))
+	defer os.RemoveAll(dir + "-sync")
+	b2 := mustNewDiskBuffer(t, opts)
+	defer b2.Close(producer.DropEverything)
+
+	mm = testDiskBufferMessage(ctrl, 1, []byte("foo"))
+	mm.EXPECT().Finalize(producer.Dropped)
+	_, err = b2.Add(mm)
+	require.NoError(t, err)
+	require.False(t, b2.segments[0].dirty)
+}
+
+func TestDiskBufferAddMessageTooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testDiskBufferDir(t)
+	defer os.RemoveAll(dir)
+
+	b := mustNewDiskBuffer(t, testDiskBufferOptions(dir).SetMaxMessageSize(2))
+	defer b.Close(producer.DropEverything)
+
+	_, err := b.Add(testDiskBufferMessage(ctrl, 1, []byte("foo")))
+	require.Equal(t, errMessageTooLarge, err)
+}
+
+func TestDiskBufferSpillAndDrain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testDiskBufferDir(t)
+	defer os.RemoveAll(dir)
+
+	b := mustNewDiskBuffer(t, testDiskBufferOptions(dir).SetMaxBufferSize(4).SetMaxMessageSize(4))
+	defer b.Close(producer.DropEverything)
+
+	var written []*producer.RefCountedMessage
+	b.SetWriteFn(func(rm *producer.RefCountedMessage) error {
+		written = append(written, rm)
+		return nil
+	})
+
+	mm1 := testDiskBufferMessage(ctrl, 1, []byte("foo"))
+	rm1, err := b.Add(mm1)
+	require.NoError(t, err)
+	require.NotNil(t, rm1)
+
+	// The memory budget is exceeded so the message is spilled to disk.
+	mm2 := testDiskBufferMessage(ctrl, 2, []byte("bar"))
+	mm2.EXPECT().Finalize(producer.Persisted)
+	rm2, err := b.Add(mm2)
+	require.NoError(t, err)
+	require.Nil(t, rm2)
+	require.Equal(t, 1, int(b.numPending.Load()))
+
+	// New messages are spilled once there are pending messages on disk.
+	mm3 := testDiskBufferMessage(ctrl, 3, []byte("b"))
+	mm3.EXPECT().Finalize(producer.Persisted)
+	rm3, err := b.Add(mm3)
+	require.NoError(t, err)
+	require.Nil(t, rm3)
+	require.Equal(t, 2, int(b.numPending.Load()))
+
+	// Nothing is drained until there is room in the memory budget.
+	b.drain()
+	require.Equal(t, 0, len(written))
+
+	mm1.EXPECT().Finalize(producer.Consumed)
+	rm1.IncRef()
+	rm1.DecRef()
+	b.drain()
+	require.Equal(t, 2, len(written))
+	require.Equal(t, 0, int(b.numPending.Load()))
+	require.Equal(t, []byte("bar"), written[0].Bytes())
+	require.Equal(t, uint32(2), written[0].Shard())
+	require.Equal(t, []byte("b"), written[1].Bytes())
+	require.Equal(t, uint32(3), written[1].Shard())
+	require.Equal(t, 4, int(b.memSize.Load()))
+
+	for _, rm := range written {
+		rm.IncRef()
+		rm.DecRef()
+	}
+	require.Equal(t, 0, int(b.memSize.Load()))
+	require.Equal(t, int64(3), b.segments[0].numFinalized.Load())
+}
+
+func TestDiskBufferRemoveConsumedSegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testDiskBufferDir(t)
+	defer os.RemoveAll(dir)
+
+	opts := testDiskBufferOptions(dir).SetMaxMessageSize(3)
+	opts = opts.SetDiskOptions(opts.DiskOptions().SetMaxSegmentSize(recordHeaderLen + 3))
+	b := mustNewDiskBuffer(t, opts)
+	defer b.Close(producer.DropEverything)
+
+	var rms []*producer.RefCountedMessage
+	for i := 0; i < 3; i++ {
+		mm := testDiskBufferMessage(ctrl, 1, []byte("foo"))
+		mm.EXPECT().Finalize(producer.Consumed)
+		rm, err := b.Add(mm)
+		require.NoError(t, err)
+		rms = append(rms, rm)
+	}
+	require.Equal(t, 3, len(b.segments))
+	require.Equal(t, 3, len(testSegmentFiles(t, dir)))
+
+	rms[0].IncRef()
+	rms[0].DecRef()
+	rms[2].IncRef()
+	rms[2].DecRef()
+	b.cleanup()
+
+	// The active segment is not removed even if all its messages are consumed.
+	require.Equal(t, 2, len(b.segments))
+	require.Equal(t, uint64(1), b.segments[0].index)
+	require.Equal(t, uint64(2), b.segments[1].index)
+	require.Equal(t, 2*(recordHeaderLen+3), b.diskSize)
+	require.Equal(t, 2, len(testSegmentFiles(t, dir)))
+
+	rms[1].IncRef()
+	rms[1].DecRef()
+	b.cleanup()
+	require.Equal(t, 1, len(b.segments))
+	require.Equal(t, uint64(2), b.segments[0].index)
+}
+
+func TestDiskBufferReturnErrorOnFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testDiskBufferDir(t)
+	defer os.RemoveAll(dir)
+
+	opts := testDiskBufferOptions(dir).SetMaxMessageSize(3).SetOnFullStrategy(ReturnError)
+	opts = opts.SetDiskOptions(opts.DiskOptions().
+		SetMaxSegmentSize(recordHeaderLen + 3).
+		SetMaxDiskSize(2 * (recordHeaderLen + 3)))
+	b := mustNewDiskBuffer(t, opts)
+	defer b.Close(producer.DropEverything)
+
+	for i := 0; i < 2; i++ {
+		mm := testDiskBufferMessage(ctrl, 1, []byte("foo"))
+		mm.EXPECT().Finalize(producer.Dropped)
+		_, err := b.Add(mm)
+		require.NoError(t, err)
+	}
+	_, err := b.Add(testDiskBufferMessage(ctrl, 1, []byte("foo")))
+	require.Equal(t, errBufferFull, err)
+	require.Equal(t, 2, len(b.segments))
+}
+
+func TestDiskBufferDropOldestOnFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testDiskBufferDir(t)
+	defer os.RemoveAll(dir)
+
+	opts := testDiskBufferOptions(dir).SetMaxMessageSize(3).SetOnFullStrategy(DropOldest)
+	opts = opts.SetDiskOptions(opts.DiskOptions().
+		SetMaxSegmentSize(recordHeaderLen + 3).
+		SetMaxDiskSize(2 * (recordHeaderLen + 3)))
+	b := mustNewDiskBuffer(t, opts)
+	defer b.Close(producer.DropEverything)
+
+	var rms []*producer.RefCountedMessage
+	for i := 0; i < 2; i++ {
+		mm := testDiskBufferMessage(ctrl, 1, []byte("foo"))
+		mm.EXPECT().Finalize(producer.Dropped)
+		rm, err := b.Add(mm)
+		require.NoError(t, err)
+		rms = append(rms, rm)
+	}
+
+	mm := testDiskBufferMessage(ctrl, 1, []byte("foo"))
+	mm.EXPECT().Finalize(producer.Dropped)
+	rm, err := b.Add(mm)
+	require.NoError(t, err)
+	require.NotNil(t, rm)
+	require.True(t, rms[0].IsDroppedOrConsumed())
+	require.False(t, rms[1].IsDroppedOrConsumed())
+	require.Equal(t, 2, len(b.segments))
+	require.Equal(t, uint64(1), b.segments[0].index)
+	require.Equal(t, uint64(2), b.segments[1].index)
+	require.Equal(t, 2, len(testSegmentFiles(t, dir)))
+}
+
+func TestDiskBufferDropExpiredSegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testDiskBufferDir(t)
+	defer os.RemoveAll(dir)
+
+	opts := testDiskBufferOptions(dir)
+	opts = opts.SetDiskOptions(opts.DiskOptions().SetMaxSegmentAge(time.Minute))
+	b := mustNewDiskBuffer(t, opts)
+	defer b.Close(producer.DropEverything)
+
+	now := time.Now()
+	b.nowFn = func() time.Time { return now }
+
+	mm := testDiskBufferMessage(ctrl, 1, []byte("foo"))
+	mm.EXPECT().Finalize(producer.Dropped)
+	rm, err := b.Add(mm)
+	require.NoError(t, err)
+
+	b.cleanup()
+	require.Equal(t, 1, len(b.segments))
+	require.False(t, rm.IsDroppedOrConsumed())
+
+	now = now.Add(2 * time.Minute)
+	b.cleanup()
+	require.Equal(t, 0, len(b.segments))
+	require.Equal(t, 0, b.diskSize)
+	require.True(t, rm.IsDroppedOrConsumed())
+	require.Equal(t, 0, len(testSegmentFiles(t, dir)))
+}
+
+func TestDiskBufferReplayAfterRestart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testDiskBufferDir(t)
+	defer os.RemoveAll(dir)
+
+	opts := testDiskBufferOptions(dir)
+	opts = opts.SetDiskOptions(opts.DiskOptions().SetMaxSegmentSize(2 * (recordHeaderLen + 3)))
+	b := mustNewDiskBuffer(t, opts)
+
+	var rms []*producer.RefCountedMessage
+	for i, v := range []string{"foo", "bar", "baz"} {
+		mm := testDiskBufferMessage(ctrl, 1, []byte(v))
+		if i < 2 {
+			mm.EXPECT().Finalize(producer.Consumed)
+		} else {
+			mm.EXPECT().Finalize(producer.Dropped)
+		}
+		rm, err := b.Add(mm)
+		require.NoError(t, err)
+		rms = append(rms, rm)
+	}
+	require.Equal(t, 2, len(b.segments))
+
+	// Consume the messages in the first segment, which should be removed
+	// on close while the rest of the messages are persisted for replay.
+	for _, rm := range rms[:2] {
+		rm.IncRef()
+		rm.DecRef()
+	}
+	b.Close(producer.DropEverything)
+	require.True(t, rms[2].IsDroppedOrConsumed())
+	require.Equal(t, 1, len(testSegmentFiles(t, dir)))
+
+	// Append a partially written message to the segment file which should
+	// be ignored on replay.
+	f, err := os.OpenFile(testSegmentFiles(t, dir)[0], os.O_APPEND|os.O_WRONLY, newFileMode)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 0, 10, 0, 0})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	b = mustNewDiskBuffer(t, opts)
+	require.Equal(t, 1, len(b.segments))
+	require.Equal(t, 1, int(b.numPending.Load()))
+	require.Equal(t, uint64(2), b.nextIndex)
+
+	var written []*producer.RefCountedMessage
+	b.SetWriteFn(func(rm *producer.RefCountedMessage) error {
+		written = append(written, rm)
+		return nil
+	})
+	b.drain()
+	require.Equal(t, 1, len(written))
+	require.Equal(t, []byte("baz"), written[0].Bytes())
+
+	// New messages are appended to a new segment.
+	mm := testDiskBufferMessage(ctrl, 1, []byte("qux"))
+	mm.EXPECT().Finalize(producer.Consumed)
+	rm, err := b.Add(mm)
+	require.NoError(t, err)
+	require.NotNil(t, rm)
+	require.Equal(t, 2, len(b.segments))
+	require.Equal(t, uint64(2), b.segments[1].index)
+
+	written[0].IncRef()
+	written[0].DecRef()
+	rm.IncRef()
+	rm.DecRef()
+	b.Close(producer.WaitForConsumption)
+	require.Equal(t, 0, len(testSegmentFiles(t, dir)))
+}
+
+func TestDiskBufferCloseWaitForConsumption(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := testDiskBufferDir(t)
+	defer os.RemoveAll(dir)
+
+	b := mustNewDiskBuffer(t, testDiskBufferOptions(dir).SetMaxBufferSize(3).SetMaxMessageSize(3))
+	b.SetWriteFn(func(rm *producer.RefCountedMessage) error {
+		rm.IncRef()
+		rm.DecRef()
+		return nil
+	})
+	b.Init()
+
+	mm1 := testDiskBufferMessage(ctrl, 1, []byte("foo"))
+	rm, err := b.Add(mm1)
+	require.NoError(t, err)
+	mm2 := testDiskBufferMessage(ctrl, 1, []byte("bar"))
+	mm2.EXPECT().Finalize(producer.Persisted)
+	_, err = b.Add(mm2)
+	require.NoError(t, err)
+
+	mm1.EXPECT().Finalize(producer.Consumed)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		rm.IncRef()
+		rm.DecRef()
+	}()
+	b.Close(producer.WaitForConsumption)
+	require.Equal(t, 0, int(b.numPending.Load()))
+	require.Equal(t, 0, len(testSegmentFiles(t, dir)))
+
+	_, err = b.Add(testDiskBufferMessage(ctrl, 1, []byte("baz")))
+	require.Equal(t, errBufferClosed, err)
+}
+
+func mustNewDiskBuffer(t *testing.T, opts Options) *diskBuffer {
+	b, err := NewDiskBuffer(opts)
+	require.NoError(t, err)
+	return b.(*diskBuffer)
+}
+
+func testDiskBufferOptions(dir string) Options {
+	return NewOptions().
+		SetMaxMessageSize(16).
+		SetCloseCheckInterval(10 * time.Millisecond).
+		SetCleanupRetryOptions(retry.NewOptions().SetInitialBackoff(10 * time.Millisecond)).
+		SetDiskOptions(NewDiskOptions().
+			SetPath(dir).
+			SetMaxSegmentSize(1024).
+			SetMaxDiskSize(4096).
+			SetDrainInterval(10 * time.Millisecond))
+}
+
+func testDiskBufferDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "disk-buffer")
+	require.NoError(t, err)
+	return dir
+}
+
+func testDiskBufferMessage(ctrl *gomock.Controller, shard uint32, data []byte) *producer.MockMessage {
+	mm := producer.NewMockMessage(ctrl)
+	mm.EXPECT().Shard().Return(shard).AnyTimes()
+	mm.EXPECT().Bytes().Return(data).AnyTimes()
+	mm.EXPECT().Size().Return(len(data)).AnyTimes()
+	return mm
+}
+
+func testSegmentFiles(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, segmentFilePrefix+"*"+segmentFileSuffix))
+	require.NoError(t, err)
+	return files
+}
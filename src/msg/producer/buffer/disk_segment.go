@@ -0,0 +1,359 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package buffer
+
+import (
+	"bufio"
+	"container/list"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/msg/producer"
+
+	"go.uber.org/atomic"
+)
+
+const (
+	// recordHeaderLen is the length of the header of each record in a segment
+	// file, which contains the message size, the message shard and the checksum
+	// of the message bytes.
+	recordHeaderLen = 12
+
+	segmentFilePrefix = "segment-"
+	segmentFileSuffix = ".log"
+	newFileMode       = 0644
+	newDirectoryMode  = 0755
+)
+
+var (
+	errRecordChecksumMismatch = errors.New("record checksum mismatch")
+)
+
+// record is the location of a message in a segment file.
+type record struct {
+	offset int64
+	size   int
+	shard  uint32
+}
+
+// segment is an append-only file containing messages added to the disk buffer.
+// A segment can be removed once all its messages have been consumed.
+type segment struct {
+	sync.Mutex
+
+	index     uint64
+	path      string
+	fd        *os.File
+	size      int
+	createdAt time.Time
+
+	// numRecords is the number of messages in the segment, it is only
+	// updated before the segment is sealed.
+	numRecords int
+	sealed     bool
+	// dirty is true if messages have been appended since the last sync.
+	dirty bool
+
+	// pending contains the messages that are persisted but not yet written out.
+	pending []record
+	// inMemory contains the messages that are written out but not yet finalized.
+	inMemory *list.List
+
+	numFinalized *atomic.Int64
+}
+
+func newSegment(dir string, index uint64, createdAt time.Time) (*segment, error) {
+	path := segmentFilePath(dir, index)
+	fd, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, newFileMode)
+	if err != nil {
+		return nil, err
+	}
+	return &segment{
+		index:        index,
+		path:         path,
+		fd:           fd,
+		createdAt:    createdAt,
+		inMemory:     list.New(),
+		numFinalized: atomic.NewInt64(0),
+	}, nil
+}
+
+// openSegment opens an existing segment file and marks all the valid
+// messages in it as pending. A corrupted or partially written message
+// ends the segment, since it can only be caused by an unclean shutdown
+// while the segment was being appended to.
+func openSegment(path string, index uint64) (*segment, error) {
+	fd, err := os.OpenFile(path, os.O_RDWR, newFileMode)
+	if err != nil {
+		return nil, err
+	}
+	info, err := fd.Stat()
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+	var (
+		pending []record
+		offset  int64
+		r       = bufio.NewReader(fd)
+	)
+	for {
+		rec, err := readRecord(r, offset)
+		if err != nil {
+			break
+		}
+		pending = append(pending, rec)
+		offset = rec.offset + int64(rec.size)
+	}
+	return &segment{
+		index:        index,
+		path:         path,
+		fd:           fd,
+		size:         int(offset),
+		createdAt:    info.ModTime(),
+		numRecords:   len(pending),
+		sealed:       true,
+		pending:      pending,
+		inMemory:     list.New(),
+		numFinalized: atomic.NewInt64(0),
+	}, nil
+}
+
+// append appends the message to the end of the segment file.
+func (s *segment) append(shard uint32, data []byte) (record, error) {
+	buf := make([]byte, recordHeaderLen+len(data))
+	binary.BigEndian.PutUint32(buf[0:], uint32(len(data)))
+	binary.BigEndian.PutUint32(buf[4:], shard)
+	binary.BigEndian.PutUint32(buf[8:], crc32.ChecksumIEEE(data))
+	copy(buf[recordHeaderLen:], data)
+	if _, err := s.fd.WriteAt(buf, int64(s.size)); err != nil {
+		return record{}, err
+	}
+	rec := record{
+		offset: int64(s.size + recordHeaderLen),
+		size:   len(data),
+		shard:  shard,
+	}
+	s.size += len(buf)
+	s.numRecords++
+	s.dirty = true
+	return rec, nil
+}
+
+// sync commits the messages appended since the last sync to stable storage.
+func (s *segment) sync() error {
+	if !s.dirty {
+		return nil
+	}
+	if err := s.fd.Sync(); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
+
+// read reads the bytes of the message from the segment file.
+func (s *segment) read(rec record) ([]byte, error) {
+	data := make([]byte, rec.size)
+	if _, err := s.fd.ReadAt(data, rec.offset); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *segment) addPending(rec record) {
+	s.Lock()
+	s.pending = append(s.pending, rec)
+	s.Unlock()
+}
+
+// peekPending returns the oldest pending message without removing it.
+func (s *segment) peekPending() (record, bool) {
+	s.Lock()
+	defer s.Unlock()
+	if len(s.pending) == 0 {
+		return record{}, false
+	}
+	return s.pending[0], true
+}
+
+// popPending removes and returns the oldest pending message.
+func (s *segment) popPending() (record, bool) {
+	s.Lock()
+	defer s.Unlock()
+	if len(s.pending) == 0 {
+		return record{}, false
+	}
+	rec := s.pending[0]
+	s.pending = s.pending[1:]
+	return rec, true
+}
+
+func (s *segment) numPending() int {
+	s.Lock()
+	n := len(s.pending)
+	s.Unlock()
+	return n
+}
+
+func (s *segment) addInMemory(rm *producer.RefCountedMessage) *list.Element {
+	s.Lock()
+	e := s.inMemory.PushBack(rm)
+	s.Unlock()
+	return e
+}
+
+func (s *segment) numInMemory() int {
+	s.Lock()
+	n := s.inMemory.Len()
+	s.Unlock()
+	return n
+}
+
+// finalize marks a message in the segment as finalized, the element is
+// nil for messages that were never written out.
+func (s *segment) finalize(e *list.Element) {
+	s.release(e)
+	s.numFinalized.Inc()
+}
+
+// release stops tracking a message that is no longer held in memory without
+// finalizing it, so the message is kept in the segment file and replayed the
+// next time the buffer is created unless the segment is dropped.
+func (s *segment) release(e *list.Element) {
+	if e == nil {
+		return
+	}
+	s.Lock()
+	s.inMemory.Remove(e)
+	s.Unlock()
+}
+
+// isFinalized returns true if the segment is sealed and all its messages
+// have been finalized.
+func (s *segment) isFinalized() bool {
+	return s.sealed && s.numFinalized.Load() >= int64(s.numRecords)
+}
+
+// drop drops all the messages in the segment that have not been finalized
+// and returns the number of messages and bytes dropped.
+func (s *segment) drop() (int, int) {
+	s.Lock()
+	var (
+		numDropped   = len(s.pending)
+		bytesDropped int
+		toDrop       = make([]*producer.RefCountedMessage, 0, s.inMemory.Len())
+	)
+	for _, rec := range s.pending {
+		bytesDropped += rec.size
+	}
+	s.pending = nil
+	for e := s.inMemory.Front(); e != nil; e = e.Next() {
+		toDrop = append(toDrop, e.Value.(*producer.RefCountedMessage))
+	}
+	s.Unlock()
+
+	// NB: Messages must be dropped without holding the segment lock
+	// since finalizing the messages acquires the lock.
+	for _, rm := range toDrop {
+		// There is a chance that the message is consumed right before
+		// the drop call which will lead drop to return false.
+		if rm.Drop() {
+			numDropped++
+			bytesDropped += int(rm.Size())
+		}
+	}
+	return numDropped, bytesDropped
+}
+
+func (s *segment) close() error {
+	if err := s.sync(); err != nil {
+		s.fd.Close()
+		return err
+	}
+	return s.fd.Close()
+}
+
+func (s *segment) remove() error {
+	if err := s.fd.Close(); err != nil {
+		return err
+	}
+	return os.Remove(s.path)
+}
+
+func readRecord(r io.Reader, offset int64) (record, error) {
+	var header [recordHeaderLen]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return record{}, err
+	}
+	var (
+		size     = binary.BigEndian.Uint32(header[0:])
+		shard    = binary.BigEndian.Uint32(header[4:])
+		checksum = binary.BigEndian.Uint32(header[8:])
+		data     = make([]byte, size)
+	)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return record{}, err
+	}
+	if crc32.ChecksumIEEE(data) != checksum {
+		return record{}, errRecordChecksumMismatch
+	}
+	return record{
+		offset: offset + recordHeaderLen,
+		size:   int(size),
+		shard:  shard,
+	}, nil
+}
+
+func segmentFilePath(dir string, index uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%020d%s", segmentFilePrefix, index, segmentFileSuffix))
+}
+
+// segmentFileIndexes returns the indexes of the segment files in the
+// directory in ascending order.
+func segmentFileIndexes(dir string) ([]uint64, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, segmentFilePrefix+"*"+segmentFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]uint64, 0, len(matches))
+	for _, match := range matches {
+		name := filepath.Base(match)
+		name = strings.TrimSuffix(strings.TrimPrefix(name, segmentFilePrefix), segmentFileSuffix)
+		index, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	return indexes, nil
+}
@@ -37,6 +37,10 @@ const (
 	defaultCleanupInitialBackoff = 10 * time.Second
 	defaultAllowedSpilloverRatio = 0.2
 	defaultCleanupMaxBackoff     = time.Minute
+	defaultMaxSegmentSize        = 64 * 1024 * 1024   // 64MB.
+	defaultMaxDiskSize           = 1024 * 1024 * 1024 // 1GB.
+	defaultDrainInterval         = 100 * time.Millisecond
+	defaultSyncInterval          = 100 * time.Millisecond
 )
 
 var (
@@ -44,6 +48,12 @@ var (
 	errInvalidMaxMessageSize  = errors.New("invalid max message size")
 	errNegativeMaxBufferSize  = errors.New("negative max buffer size")
 	errNegativeMaxMessageSize = errors.New("negative max message size")
+	errEmptyDiskPath          = errors.New("empty disk path")
+	errInvalidMaxSegmentSize  = errors.New("invalid max segment size")
+	errInvalidMaxDiskSize     = errors.New("invalid max disk size")
+	errNegativeMaxSegmentAge  = errors.New("negative max segment age")
+	errInvalidDrainInterval   = errors.New("invalid drain interval")
+	errNegativeSyncInterval   = errors.New("negative sync interval")
 )
 
 type bufferOptions struct {
@@ -55,6 +65,7 @@ type bufferOptions struct {
 	scanBatchSize         int
 	allowedSpilloverRatio float64
 	rOpts                 retry.Options
+	dOpts                 DiskOptions
 	iOpts                 instrument.Options
 }
 
@@ -156,6 +167,16 @@ func (opts *bufferOptions) SetCleanupRetryOptions(value retry.Options) Options {
 	return &o
 }
 
+func (opts *bufferOptions) DiskOptions() DiskOptions {
+	return opts.dOpts
+}
+
+func (opts *bufferOptions) SetDiskOptions(value DiskOptions) Options {
+	o := *opts
+	o.dOpts = value
+	return &o
+}
+
 func (opts *bufferOptions) InstrumentOptions() instrument.Options {
 	return opts.iOpts
 }
@@ -180,5 +201,117 @@ func (opts *bufferOptions) Validate() error {
 		// Max message size can only be as large as max buffer size.
 		return errInvalidMaxMessageSize
 	}
+	if opts.DiskOptions() == nil {
+		return nil
+	}
+	if err := opts.DiskOptions().Validate(); err != nil {
+		return err
+	}
+	if opts.MaxMessageSize()+recordHeaderLen > opts.DiskOptions().MaxSegmentSize() {
+		// A segment file must be large enough to hold the largest message.
+		return errInvalidMaxSegmentSize
+	}
+	return nil
+}
+
+type diskOptions struct {
+	path           string
+	maxSegmentSize int
+	maxDiskSize    int
+	maxSegmentAge  time.Duration
+	drainInterval  time.Duration
+	syncInterval   time.Duration
+}
+
+// NewDiskOptions creates DiskOptions.
+func NewDiskOptions() DiskOptions {
+	return &diskOptions{
+		maxSegmentSize: defaultMaxSegmentSize,
+		maxDiskSize:    defaultMaxDiskSize,
+		drainInterval:  defaultDrainInterval,
+		syncInterval:   defaultSyncInterval,
+	}
+}
+
+func (opts *diskOptions) Path() string {
+	return opts.path
+}
+
+func (opts *diskOptions) SetPath(value string) DiskOptions {
+	o := *opts
+	o.path = value
+	return &o
+}
+
+func (opts *diskOptions) MaxSegmentSize() int {
+	return opts.maxSegmentSize
+}
+
+func (opts *diskOptions) SetMaxSegmentSize(value int) DiskOptions {
+	o := *opts
+	o.maxSegmentSize = value
+	return &o
+}
+
+func (opts *diskOptions) MaxDiskSize() int {
+	return opts.maxDiskSize
+}
+
+func (opts *diskOptions) SetMaxDiskSize(value int) DiskOptions {
+	o := *opts
+	o.maxDiskSize = value
+	return &o
+}
+
+func (opts *diskOptions) MaxSegmentAge() time.Duration {
+	return opts.maxSegmentAge
+}
+
+func (opts *diskOptions) SetMaxSegmentAge(value time.Duration) DiskOptions {
+	o := *opts
+	o.maxSegmentAge = value
+	return &o
+}
+
+func (opts *diskOptions) DrainInterval() time.Duration {
+	return opts.drainInterval
+}
+
+func (opts *diskOptions) SetDrainInterval(value time.Duration) DiskOptions {
+	o := *opts
+	o.drainInterval = value
+	return &o
+}
+
+func (opts *diskOptions) SyncInterval() time.Duration {
+	return opts.syncInterval
+}
+
+func (opts *diskOptions) SetSyncInterval(value time.Duration) DiskOptions {
+	o := *opts
+	o.syncInterval = value
+	return &o
+}
+
+func (opts *diskOptions) Validate() error {
+	if opts.Path() == "" {
+		return errEmptyDiskPath
+	}
+	if opts.MaxSegmentSize() <= 0 {
+		return errInvalidMaxSegmentSize
+	}
+	if opts.MaxDiskSize() < opts.MaxSegmentSize() {
+		// Max disk size must be able to hold at least one segment file.
+		return errInvalidMaxDiskSize
+	}
+	if opts.MaxSegmentAge() < 0 {
+		return errNegativeMaxSegmentAge
+	}
+	if opts.DrainInterval() <= 0 {
+		return errInvalidDrainInterval
+	}
+	if opts.SyncInterval() < 0 {
+		return errNegativeSyncInterval
+	}
 	return nil
 }
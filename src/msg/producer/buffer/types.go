@@ -96,6 +96,12 @@ type Options interface {
 	// SetCleanupRetryOptions sets the cleanup retry options.
 	SetCleanupRetryOptions(value retry.Options) Options
 
+	// DiskOptions returns the disk options, which are only used by disk buffers.
+	DiskOptions() DiskOptions
+
+	// SetDiskOptions sets the disk options, which are only used by disk buffers.
+	SetDiskOptions(value DiskOptions) Options
+
 	// InstrumentOptions returns the instrument options.
 	InstrumentOptions() instrument.Options
 
@@ -105,3 +111,55 @@ type Options interface {
 	// Validate validates the options.
 	Validate() error
 }
+
+// DiskOptions configs the disk buffer. The max buffer size of the buffer
+// options is used as the memory budget of the disk buffer, and messages
+// beyond the memory budget are spilled to disk and written out once the
+// messages held in memory have been consumed. The OnFullStrategy of the
+// buffer options applies when the max disk size is reached.
+type DiskOptions interface {
+	// Path returns the directory of the segment files.
+	Path() string
+
+	// SetPath sets the directory of the segment files.
+	SetPath(value string) DiskOptions
+
+	// MaxSegmentSize returns the max size of a segment file.
+	MaxSegmentSize() int
+
+	// SetMaxSegmentSize sets the max size of a segment file.
+	SetMaxSegmentSize(value int) DiskOptions
+
+	// MaxDiskSize returns the max total size of all the segment files.
+	MaxDiskSize() int
+
+	// SetMaxDiskSize sets the max total size of all the segment files.
+	SetMaxDiskSize(value int) DiskOptions
+
+	// MaxSegmentAge returns the max age of a segment file, after which the
+	// segment file is removed and messages not yet consumed are dropped.
+	// A zero value means segment files never expire.
+	MaxSegmentAge() time.Duration
+
+	// SetMaxSegmentAge sets the max age of a segment file.
+	SetMaxSegmentAge(value time.Duration) DiskOptions
+
+	// DrainInterval returns the interval to write out messages spilled to disk.
+	DrainInterval() time.Duration
+
+	// SetDrainInterval sets the interval to write out messages spilled to disk.
+	SetDrainInterval(value time.Duration) DiskOptions
+
+	// SyncInterval returns the interval to sync the messages appended to the
+	// segment files to stable storage. A zero value means every message is
+	// synced before it is added to the buffer, otherwise the messages added
+	// within the last interval may be lost if the host crashes.
+	SyncInterval() time.Duration
+
+	// SetSyncInterval sets the interval to sync the messages appended to the
+	// segment files to stable storage.
+	SetSyncInterval(value time.Duration) DiskOptions
+
+	// Validate validates the options.
+	Validate() error
+}
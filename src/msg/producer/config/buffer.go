@@ -23,6 +23,7 @@ package config
 import (
 	"time"
 
+	"github.com/m3db/m3/src/msg/producer"
 	"github.com/m3db/m3/src/msg/producer/buffer"
 	"github.com/m3db/m3x/instrument"
 	"github.com/m3db/m3x/retry"
@@ -38,6 +39,17 @@ type BufferConfiguration struct {
 	ScanBatchSize         *int                   `yaml:"scanBatchSize"`
 	AllowedSpilloverRatio *float64               `yaml:"allowedSpilloverRatio"`
 	CleanupRetry          *retry.Configuration   `yaml:"cleanupRetry"`
+	Disk                  *DiskConfiguration     `yaml:"disk"`
+}
+
+// NewBuffer creates a new buffer, messages are persisted on local disk
+// if the disk configuration is set.
+func (c *BufferConfiguration) NewBuffer(iOpts instrument.Options) (producer.Buffer, error) {
+	opts := c.NewOptions(iOpts)
+	if c.Disk == nil {
+		return buffer.NewBuffer(opts)
+	}
+	return buffer.NewDiskBuffer(opts)
 }
 
 // NewOptions creates new buffer options.
@@ -67,5 +79,39 @@ func (c *BufferConfiguration) NewOptions(iOpts instrument.Options) buffer.Option
 	if c.CleanupRetry != nil {
 		opts = opts.SetCleanupRetryOptions(c.CleanupRetry.NewOptions(iOpts.MetricsScope()))
 	}
+	if c.Disk != nil {
+		opts = opts.SetDiskOptions(c.Disk.NewOptions())
+	}
 	return opts.SetInstrumentOptions(iOpts)
 }
+
+// DiskConfiguration configs the disk buffer.
+type DiskConfiguration struct {
+	Path           string         `yaml:"path" validate:"nonzero"`
+	MaxSegmentSize *int           `yaml:"maxSegmentSize"`
+	MaxDiskSize    *int           `yaml:"maxDiskSize"`
+	MaxSegmentAge  *time.Duration `yaml:"maxSegmentAge"`
+	DrainInterval  *time.Duration `yaml:"drainInterval"`
+	SyncInterval   *time.Duration `yaml:"syncInterval"`
+}
+
+// NewOptions creates new disk options.
+func (c *DiskConfiguration) NewOptions() buffer.DiskOptions {
+	opts := buffer.NewDiskOptions().SetPath(c.Path)
+	if c.MaxSegmentSize != nil {
+		opts = opts.SetMaxSegmentSize(*c.MaxSegmentSize)
+	}
+	if c.MaxDiskSize != nil {
+		opts = opts.SetMaxDiskSize(*c.MaxDiskSize)
+	}
+	if c.MaxSegmentAge != nil {
+		opts = opts.SetMaxSegmentAge(*c.MaxSegmentAge)
+	}
+	if c.DrainInterval != nil {
+		opts = opts.SetDrainInterval(*c.DrainInterval)
+	}
+	if c.SyncInterval != nil {
+		opts = opts.SetSyncInterval(*c.SyncInterval)
+	}
+	return opts
+}
@@ -69,3 +69,32 @@ func TestEmptyBufferConfiguration(t *testing.T) {
 		cfg.NewOptions(instrument.NewOptions()).SetCleanupRetryOptions(rOpts),
 	)
 }
+
+func TestBufferConfigurationWithDisk(t *testing.T) {
+	str := `
+maxBufferSize: 100
+maxMessageSize: 16
+disk:
+  path: /var/lib/m3msg/buffer
+  maxSegmentSize: 1024
+  maxDiskSize: 4096
+  maxSegmentAge: 1h
+  drainInterval: 50ms
+  syncInterval: 0s
+`
+
+	var cfg BufferConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+
+	bOpts := cfg.NewOptions(instrument.NewOptions())
+	require.Equal(t, 100, bOpts.MaxBufferSize())
+	dOpts := bOpts.DiskOptions()
+	require.NotNil(t, dOpts)
+	require.Equal(t, "/var/lib/m3msg/buffer", dOpts.Path())
+	require.Equal(t, 1024, dOpts.MaxSegmentSize())
+	require.Equal(t, 4096, dOpts.MaxDiskSize())
+	require.Equal(t, time.Hour, dOpts.MaxSegmentAge())
+	require.Equal(t, 50*time.Millisecond, dOpts.DrainInterval())
+	require.Equal(t, time.Duration(0), dOpts.SyncInterval())
+	require.NoError(t, bOpts.Validate())
+}
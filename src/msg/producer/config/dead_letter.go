@@ -0,0 +1,124 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/msg/producer"
+	"github.com/m3db/m3/src/msg/producer/deadletter"
+	"github.com/m3db/m3/src/msg/producer/writer"
+	"github.com/m3db/m3x/instrument"
+)
+
+var (
+	errNoDeadLetterSink       = errors.New("no dead letter sink configured")
+	errMultipleDeadLetterSink = errors.New("only one of file and producer dead letter sink can be configured")
+)
+
+// DeadLetterPolicyConfiguration configs a dead letter policy.
+type DeadLetterPolicyConfiguration struct {
+	MaxRetries int           `yaml:"maxRetries"`
+	MaxAge     time.Duration `yaml:"maxAge"`
+}
+
+// NewPolicy creates a dead letter policy.
+func (c DeadLetterPolicyConfiguration) NewPolicy() writer.DeadLetterPolicy {
+	return writer.DeadLetterPolicy{
+		MaxRetries: c.MaxRetries,
+		MaxAge:     c.MaxAge,
+	}
+}
+
+// DeadLetterFileConfiguration configs the dead letter file sink.
+type DeadLetterFileConfiguration struct {
+	Path        string `yaml:"path" validate:"nonzero"`
+	MaxFileSize *int   `yaml:"maxFileSize"`
+}
+
+// NewOptions creates dead letter file sink options.
+func (c *DeadLetterFileConfiguration) NewOptions() deadletter.FileSinkOptions {
+	opts := deadletter.NewFileSinkOptions().SetPath(c.Path)
+	if c.MaxFileSize != nil {
+		opts = opts.SetMaxFileSize(*c.MaxFileSize)
+	}
+	return opts
+}
+
+// DeadLetterConfiguration configs the dead letter options.
+type DeadLetterConfiguration struct {
+	// Default is the policy of the consumer services without an override.
+	Default DeadLetterPolicyConfiguration `yaml:"default"`
+
+	// Services are the policy overrides keyed by consumer service name.
+	Services map[string]DeadLetterPolicyConfiguration `yaml:"services"`
+
+	// File configs a sink writing dead letters to local files.
+	File *DeadLetterFileConfiguration `yaml:"file"`
+
+	// Producer configs a sink producing dead letters to a separate topic.
+	Producer *ProducerConfiguration `yaml:"producer"`
+}
+
+// NewOptions creates dead letter options.
+func (c *DeadLetterConfiguration) NewOptions(
+	cs client.Client,
+	iOpts instrument.Options,
+) (writer.DeadLetterOptions, error) {
+	sink, err := c.newSink(cs, iOpts)
+	if err != nil {
+		return nil, err
+	}
+	policies := make(map[string]writer.DeadLetterPolicy, len(c.Services))
+	for name, policy := range c.Services {
+		policies[name] = policy.NewPolicy()
+	}
+	return writer.NewDeadLetterOptions().
+		SetSink(sink).
+		SetDefaultPolicy(c.Default.NewPolicy()).
+		SetServicePolicies(policies), nil
+}
+
+func (c *DeadLetterConfiguration) newSink(
+	cs client.Client,
+	iOpts instrument.Options,
+) (writer.DeadLetterSink, error) {
+	if c.File != nil && c.Producer != nil {
+		return nil, errMultipleDeadLetterSink
+	}
+	if c.File != nil {
+		return deadletter.NewFileSink(c.File.NewOptions())
+	}
+	if c.Producer == nil {
+		return nil, errNoDeadLetterSink
+	}
+	scope := iOpts.MetricsScope().SubScope("dead-letter")
+	p, err := c.Producer.NewProducer(cs, iOpts.SetMetricsScope(scope))
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Init(); err != nil {
+		return nil, err
+	}
+	return deadletter.NewProducerSink(p, producer.WaitForConsumption), nil
+}
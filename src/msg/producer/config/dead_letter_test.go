@@ -0,0 +1,75 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/msg/producer/writer"
+	"github.com/m3db/m3x/instrument"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestDeadLetterConfiguration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deadletter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	str := `
+default:
+  maxRetries: 10
+services:
+  m3aggregator:
+    maxAge: 1h
+file:
+  path: ` + dir + `
+  maxFileSize: 1024
+`
+
+	var cfg DeadLetterConfiguration
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+	require.Equal(t, 1024, *cfg.File.MaxFileSize)
+
+	opts, err := cfg.NewOptions(nil, instrument.NewOptions())
+	require.NoError(t, err)
+	require.NotNil(t, opts.Sink())
+	require.Equal(t, writer.DeadLetterPolicy{MaxRetries: 10}, opts.DefaultPolicy())
+	require.Equal(t, map[string]writer.DeadLetterPolicy{
+		"m3aggregator": {MaxAge: time.Hour},
+	}, opts.ServicePolicies())
+	require.NoError(t, opts.Sink().Close())
+}
+
+func TestDeadLetterConfigurationInvalidSink(t *testing.T) {
+	var cfg DeadLetterConfiguration
+	_, err := cfg.NewOptions(nil, instrument.NewOptions())
+	require.Equal(t, errNoDeadLetterSink, err)
+
+	cfg.File = &DeadLetterFileConfiguration{Path: "foo"}
+	cfg.Producer = &ProducerConfiguration{}
+	_, err = cfg.NewOptions(nil, instrument.NewOptions())
+	require.Equal(t, errMultipleDeadLetterSink, err)
+}
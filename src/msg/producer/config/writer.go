@@ -29,6 +29,7 @@ import (
 	"github.com/m3db/m3/src/msg/producer/writer"
 	"github.com/m3db/m3/src/msg/protocol/proto"
 	"github.com/m3db/m3/src/msg/topic"
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/instrument"
 	"github.com/m3db/m3x/pool"
 	"github.com/m3db/m3x/retry"
@@ -44,10 +45,11 @@ type ConnectionConfiguration struct {
 	FlushInterval   *time.Duration       `yaml:"flushInterval"`
 	WriteBufferSize *int                 `yaml:"writeBufferSize"`
 	ReadBufferSize  *int                 `yaml:"readBufferSize"`
+	TLS             *xtls.Configuration  `yaml:"tls"`
 }
 
 // NewOptions creates connection options.
-func (c *ConnectionConfiguration) NewOptions(iOpts instrument.Options) writer.ConnectionOptions {
+func (c *ConnectionConfiguration) NewOptions(iOpts instrument.Options) (writer.ConnectionOptions, error) {
 	opts := writer.NewConnectionOptions()
 	if c.DialTimeout != nil {
 		opts = opts.SetDialTimeout(*c.DialTimeout)
@@ -73,7 +75,14 @@ func (c *ConnectionConfiguration) NewOptions(iOpts instrument.Options) writer.Co
 	if c.ReadBufferSize != nil {
 		opts = opts.SetReadBufferSize(*c.ReadBufferSize)
 	}
-	return opts.SetInstrumentOptions(iOpts)
+	if c.TLS != nil {
+		tlsDialer, err := c.TLS.NewDialer()
+		if err != nil {
+			return nil, err
+		}
+		opts = opts.SetTLSDialer(tlsDialer)
+	}
+	return opts.SetInstrumentOptions(iOpts), nil
 }
 
 // WriterConfiguration configs the writer options.
@@ -94,6 +103,7 @@ type WriterConfiguration struct {
 	Encoder                           *proto.Configuration           `yaml:"encoder"`
 	Decoder                           *proto.Configuration           `yaml:"decoder"`
 	Connection                        *ConnectionConfiguration       `yaml:"connection"`
+	DeadLetter                        *DeadLetterConfiguration       `yaml:"deadLetter"`
 }
 
 // NewOptions creates writer options.
@@ -157,7 +167,18 @@ func (c *WriterConfiguration) NewOptions(
 		opts = opts.SetDecoderOptions(c.Decoder.NewOptions(iOpts))
 	}
 	if c.Connection != nil {
-		opts = opts.SetConnectionOptions(c.Connection.NewOptions(iOpts))
+		connOpts, err := c.Connection.NewOptions(iOpts)
+		if err != nil {
+			return nil, err
+		}
+		opts = opts.SetConnectionOptions(connOpts)
+	}
+	if c.DeadLetter != nil {
+		dlOpts, err := c.DeadLetter.NewOptions(cs, iOpts)
+		if err != nil {
+			return nil, err
+		}
+		opts = opts.SetDeadLetterOptions(dlOpts)
 	}
 	return opts.SetInstrumentOptions(iOpts), nil
 }
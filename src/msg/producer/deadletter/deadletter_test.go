@@ -0,0 +1,182 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package deadletter
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/m3db/m3/src/msg/producer"
+	"github.com/m3db/m3/src/msg/producer/writer"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func testDeadLetter(cs string, value string) writer.DeadLetter {
+	return writer.DeadLetter{
+		ConsumerService: cs,
+		Shard:           7,
+		Value:           []byte(value),
+		Reason:          writer.MaxRetriesExceeded,
+		WriteTimes:      11,
+		InitNanos:       100,
+		DeadNanos:       200,
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	dl := testDeadLetter("m3aggregator", "foo")
+	b, err := Encode(dl)
+	require.NoError(t, err)
+
+	decoded, err := Decode(b)
+	require.NoError(t, err)
+	require.Equal(t, dl, decoded)
+
+	_, err = Decode(b[:headerLen-1])
+	require.Equal(t, errDeadLetterTooShort, err)
+
+	_, err = Decode(b[:len(b)-1])
+	require.Equal(t, errChecksumMismatch, err)
+
+	b[len(b)-1]++
+	_, err = Decode(b)
+	require.Equal(t, errChecksumMismatch, err)
+}
+
+func TestFileSinkWriteAndReadDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deadletter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	b, err := Encode(testDeadLetter("cs", "foo"))
+	require.NoError(t, err)
+	opts := NewFileSinkOptions().
+		SetPath(dir).
+		SetMaxFileSize(2 * (recordHeaderLen + len(b)))
+
+	s, err := NewFileSink(opts)
+	require.NoError(t, err)
+	require.NoError(t, s.Write(testDeadLetter("cs", "foo")))
+	require.NoError(t, s.Write(testDeadLetter("cs", "bar")))
+	require.NoError(t, s.Write(testDeadLetter("cs", "baz")))
+	require.NoError(t, s.Close())
+	require.Equal(t, errFileSinkClosed, s.Write(testDeadLetter("cs", "foo")))
+
+	indexes, err := fileIndexes(dir)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0, 1}, indexes)
+
+	// Reopening the sink starts a new file.
+	s, err = NewFileSink(opts)
+	require.NoError(t, err)
+	require.NoError(t, s.Write(testDeadLetter("cs", "qux")))
+	require.NoError(t, s.Close())
+
+	// Truncated records at the end of a file are ignored.
+	f, err := os.OpenFile(filePath(dir, 2), os.O_APPEND|os.O_WRONLY, newFileMode)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 1})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	var values []string
+	require.NoError(t, ReadDir(dir, func(dl writer.DeadLetter) error {
+		require.Equal(t, "cs", dl.ConsumerService)
+		values = append(values, string(dl.Value))
+		return nil
+	}))
+	require.Equal(t, []string{"foo", "bar", "baz", "qux"}, values)
+}
+
+func TestRewriteDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deadletter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	b, err := Encode(testDeadLetter("cs1", "foo"))
+	require.NoError(t, err)
+	s, err := NewFileSink(NewFileSinkOptions().
+		SetPath(dir).
+		SetMaxFileSize(2 * (recordHeaderLen + len(b))))
+	require.NoError(t, err)
+	for _, dl := range []writer.DeadLetter{
+		testDeadLetter("cs1", "foo"),
+		testDeadLetter("cs2", "bar"),
+		testDeadLetter("cs1", "baz"),
+		testDeadLetter("cs1", "qux"),
+		testDeadLetter("cs2", "quz"),
+		testDeadLetter("cs2", "qix"),
+	} {
+		require.NoError(t, s.Write(dl))
+	}
+	require.NoError(t, s.Close())
+
+	// Remove the dead letters of cs1.
+	require.NoError(t, RewriteDir(dir, func(dl writer.DeadLetter) bool {
+		return dl.ConsumerService != "cs1"
+	}))
+	indexes, err := fileIndexes(dir)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0, 2}, indexes)
+
+	var values []string
+	require.NoError(t, ReadDir(dir, func(dl writer.DeadLetter) error {
+		require.Equal(t, "cs2", dl.ConsumerService)
+		values = append(values, string(dl.Value))
+		return nil
+	}))
+	require.Equal(t, []string{"bar", "quz", "qix"}, values)
+}
+
+func TestFileSinkInvalidOptions(t *testing.T) {
+	_, err := NewFileSink(NewFileSinkOptions())
+	require.Equal(t, errEmptyFileSinkPath, err)
+
+	_, err = NewFileSink(NewFileSinkOptions().SetPath("foo").SetMaxFileSize(0))
+	require.Equal(t, errInvalidFileSinkMaxSize, err)
+}
+
+func TestProducerSink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dl := testDeadLetter("cs", "foo")
+	p := producer.NewMockProducer(ctrl)
+	p.EXPECT().NumShards().Return(uint32(4))
+	p.EXPECT().Produce(gomock.Any()).Do(func(m producer.Message) {
+		require.Equal(t, uint32(3), m.Shard())
+		decoded, err := Decode(m.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, dl, decoded)
+	}).Return(nil)
+
+	s := NewProducerSink(p, producer.DropEverything)
+	require.NoError(t, s.Write(dl))
+
+	p.EXPECT().NumShards().Return(uint32(0))
+	require.Equal(t, errNoShards, s.Write(dl))
+
+	p.EXPECT().Close(producer.DropEverything)
+	require.NoError(t, s.Close())
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package deadletter
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math"
+
+	"github.com/m3db/m3/src/msg/producer/writer"
+)
+
+const (
+	// encodingVersion is the version of the dead letter encoding.
+	encodingVersion = 1
+
+	// headerLen is the length of the fixed size fields of an encoded dead
+	// letter: version, checksum, shard, reason, write times, init nanos,
+	// dead nanos and the lengths of the consumer service and the value.
+	headerLen = 1 + 4 + 4 + 1 + 4 + 8 + 8 + 2 + 4
+)
+
+var (
+	errDeadLetterTooShort      = errors.New("dead letter is too short")
+	errUnknownEncodingVersion  = errors.New("unknown dead letter encoding version")
+	errChecksumMismatch        = errors.New("dead letter checksum mismatch")
+	errConsumerServiceTooLong  = errors.New("dead letter consumer service is too long")
+	errInvalidDeadLetterLength = errors.New("invalid dead letter length")
+)
+
+// Encode encodes a dead letter into bytes.
+func Encode(dl writer.DeadLetter) ([]byte, error) {
+	if len(dl.ConsumerService) > math.MaxUint16 {
+		return nil, errConsumerServiceTooLong
+	}
+	b := make([]byte, headerLen+len(dl.ConsumerService)+len(dl.Value))
+	b[0] = encodingVersion
+	// NB: The checksum at b[1:5] is filled in once the rest is encoded.
+	binary.BigEndian.PutUint32(b[5:], dl.Shard)
+	b[9] = byte(dl.Reason)
+	binary.BigEndian.PutUint32(b[10:], uint32(dl.WriteTimes))
+	binary.BigEndian.PutUint64(b[14:], uint64(dl.InitNanos))
+	binary.BigEndian.PutUint64(b[22:], uint64(dl.DeadNanos))
+	binary.BigEndian.PutUint16(b[30:], uint16(len(dl.ConsumerService)))
+	binary.BigEndian.PutUint32(b[32:], uint32(len(dl.Value)))
+	n := copy(b[headerLen:], dl.ConsumerService)
+	copy(b[headerLen+n:], dl.Value)
+	binary.BigEndian.PutUint32(b[1:], crc32.ChecksumIEEE(b[5:]))
+	return b, nil
+}
+
+// Decode decodes a dead letter from bytes, the value of the dead letter
+// references the given bytes.
+func Decode(b []byte) (writer.DeadLetter, error) {
+	if len(b) < headerLen {
+		return writer.DeadLetter{}, errDeadLetterTooShort
+	}
+	if b[0] != encodingVersion {
+		return writer.DeadLetter{}, errUnknownEncodingVersion
+	}
+	if binary.BigEndian.Uint32(b[1:]) != crc32.ChecksumIEEE(b[5:]) {
+		return writer.DeadLetter{}, errChecksumMismatch
+	}
+	var (
+		csLen    = int(binary.BigEndian.Uint16(b[30:]))
+		valueLen = int(binary.BigEndian.Uint32(b[32:]))
+	)
+	if headerLen+csLen+valueLen != len(b) {
+		return writer.DeadLetter{}, errInvalidDeadLetterLength
+	}
+	return writer.DeadLetter{
+		ConsumerService: string(b[headerLen : headerLen+csLen]),
+		Shard:           binary.BigEndian.Uint32(b[5:]),
+		Value:           b[headerLen+csLen:],
+		Reason:          writer.DeadLetterReason(b[9]),
+		WriteTimes:      int(binary.BigEndian.Uint32(b[10:])),
+		InitNanos:       int64(binary.BigEndian.Uint64(b[14:])),
+		DeadNanos:       int64(binary.BigEndian.Uint64(b[22:])),
+	}, nil
+}
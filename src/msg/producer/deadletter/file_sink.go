@@ -0,0 +1,301 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package deadletter
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/m3db/m3/src/msg/producer/writer"
+)
+
+const (
+	// recordHeaderLen is the length of the size prefix of each record.
+	recordHeaderLen = 4
+
+	fileNamePrefix   = "deadletter-"
+	fileNameSuffix   = ".log"
+	tempFileSuffix   = ".tmp"
+	newFileMode      = 0644
+	newDirectoryMode = 0755
+)
+
+var (
+	errFileSinkClosed = errors.New("dead letter file sink is closed")
+)
+
+type fileSink struct {
+	sync.Mutex
+
+	dir         string
+	maxFileSize int
+
+	index  uint64
+	fd     *os.File
+	size   int
+	closed bool
+}
+
+// NewFileSink creates a dead letter sink that appends dead letters to size
+// rotated files in a local directory. Files written by previous processes are
+// never appended to, a new file is always started.
+func NewFileSink(opts FileSinkOptions) (writer.DeadLetterSink, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(opts.Path(), newDirectoryMode); err != nil {
+		return nil, err
+	}
+	indexes, err := fileIndexes(opts.Path())
+	if err != nil {
+		return nil, err
+	}
+	s := &fileSink{
+		dir:         opts.Path(),
+		maxFileSize: opts.MaxFileSize(),
+	}
+	if len(indexes) > 0 {
+		s.index = indexes[len(indexes)-1] + 1
+	}
+	if err := s.openWithLock(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) Write(dl writer.DeadLetter) error {
+	record, err := encodeRecord(dl)
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return errFileSinkClosed
+	}
+	if s.size > 0 && s.size+len(record) > s.maxFileSize {
+		if err := s.fd.Close(); err != nil {
+			return err
+		}
+		s.index++
+		if err := s.openWithLock(); err != nil {
+			return err
+		}
+	}
+	n, err := s.fd.Write(record)
+	s.size += n
+	return err
+}
+
+func (s *fileSink) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.fd.Close()
+}
+
+func (s *fileSink) openWithLock() error {
+	fd, err := os.OpenFile(
+		filePath(s.dir, s.index),
+		os.O_CREATE|os.O_WRONLY|os.O_EXCL,
+		newFileMode,
+	)
+	if err != nil {
+		return err
+	}
+	s.fd = fd
+	s.size = 0
+	return nil
+}
+
+// ReadDir reads the dead letters in a directory written by the file sink in
+// the order they were written, fn is called for each dead letter and stops
+// the iteration if it returns an error. A truncated record at the end of a file,
+// which could be left by a process crashing mid write, is ignored.
+func ReadDir(dir string, fn func(dl writer.DeadLetter) error) error {
+	indexes, err := fileIndexes(dir)
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if err := readFile(filePath(dir, index), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RewriteDir rewrites the dead letters in a directory written by the file sink,
+// keeping only the dead letters for which keep returns true, keep is called
+// for each dead letter in the order they were written. Files with no dead
+// letters left are removed. The directory must not be written to by a file
+// sink while it is being rewritten.
+func RewriteDir(dir string, keep func(dl writer.DeadLetter) bool) error {
+	indexes, err := fileIndexes(dir)
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if err := rewriteFile(filePath(dir, index), keep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rewriteFile(path string, keep func(dl writer.DeadLetter) bool) error {
+	var (
+		kept      []writer.DeadLetter
+		numLetter int
+	)
+	if err := readFile(path, func(dl writer.DeadLetter) error {
+		numLetter++
+		if keep(dl) {
+			kept = append(kept, dl)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(kept) == numLetter {
+		return nil
+	}
+	if len(kept) == 0 {
+		return os.Remove(path)
+	}
+	// NB: The kept dead letters are written to a temporary file which then
+	// replaces the original file, so the original file is left intact if
+	// the rewrite fails midway.
+	tempPath := path + tempFileSuffix
+	fd, err := os.OpenFile(tempPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, newFileMode)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(fd)
+	for _, dl := range kept {
+		record, err := encodeRecord(dl)
+		if err == nil {
+			_, err = w.Write(record)
+		}
+		if err != nil {
+			fd.Close()
+			os.Remove(tempPath)
+			return err
+		}
+	}
+	err = w.Flush()
+	if err == nil {
+		err = fd.Sync()
+	}
+	if err != nil {
+		fd.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := fd.Close(); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return os.Rename(tempPath, path)
+}
+
+func encodeRecord(dl writer.DeadLetter) ([]byte, error) {
+	b, err := Encode(dl)
+	if err != nil {
+		return nil, err
+	}
+	record := make([]byte, recordHeaderLen+len(b))
+	binary.BigEndian.PutUint32(record, uint32(len(b)))
+	copy(record[recordHeaderLen:], b)
+	return record, nil
+}
+
+func readFile(path string, fn func(dl writer.DeadLetter) error) error {
+	fd, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	var (
+		r      = bufio.NewReader(fd)
+		header = make([]byte, recordHeaderLen)
+	)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return err
+		}
+		b := make([]byte, binary.BigEndian.Uint32(header))
+		if _, err := io.ReadFull(r, b); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return err
+		}
+		dl, err := Decode(b)
+		if err != nil {
+			return fmt.Errorf("could not decode dead letter in %s: %v", path, err)
+		}
+		if err := fn(dl); err != nil {
+			return err
+		}
+	}
+}
+
+func filePath(dir string, index uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%020d%s", fileNamePrefix, index, fileNameSuffix))
+}
+
+// fileIndexes returns the sorted indexes of the dead letter files in a directory.
+func fileIndexes(dir string) ([]uint64, error) {
+	files, err := filepath.Glob(filepath.Join(dir, fileNamePrefix+"*"+fileNameSuffix))
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]uint64, 0, len(files))
+	for _, f := range files {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(f), fileNamePrefix), fileNameSuffix)
+		index, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	return indexes, nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package deadletter
+
+import (
+	"errors"
+)
+
+const (
+	defaultMaxFileSize = 64 * 1024 * 1024
+)
+
+var (
+	errEmptyFileSinkPath      = errors.New("empty dead letter file sink path")
+	errInvalidFileSinkMaxSize = errors.New("invalid dead letter file sink max file size")
+)
+
+// FileSinkOptions configs the file sink.
+type FileSinkOptions interface {
+	// Path returns the directory of the dead letter files.
+	Path() string
+
+	// SetPath sets the directory of the dead letter files.
+	SetPath(value string) FileSinkOptions
+
+	// MaxFileSize returns the max size of a dead letter file, after which
+	// a new file is created.
+	MaxFileSize() int
+
+	// SetMaxFileSize sets the max size of a dead letter file.
+	SetMaxFileSize(value int) FileSinkOptions
+
+	// Validate validates the options.
+	Validate() error
+}
+
+type fileSinkOptions struct {
+	path        string
+	maxFileSize int
+}
+
+// NewFileSinkOptions creates FileSinkOptions.
+func NewFileSinkOptions() FileSinkOptions {
+	return &fileSinkOptions{
+		maxFileSize: defaultMaxFileSize,
+	}
+}
+
+func (opts *fileSinkOptions) Path() string {
+	return opts.path
+}
+
+func (opts *fileSinkOptions) SetPath(value string) FileSinkOptions {
+	o := *opts
+	o.path = value
+	return &o
+}
+
+func (opts *fileSinkOptions) MaxFileSize() int {
+	return opts.maxFileSize
+}
+
+func (opts *fileSinkOptions) SetMaxFileSize(value int) FileSinkOptions {
+	o := *opts
+	o.maxFileSize = value
+	return &o
+}
+
+func (opts *fileSinkOptions) Validate() error {
+	if opts.path == "" {
+		return errEmptyFileSinkPath
+	}
+	if opts.maxFileSize <= 0 {
+		return errInvalidFileSinkMaxSize
+	}
+	return nil
+}
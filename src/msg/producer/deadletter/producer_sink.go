@@ -0,0 +1,85 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package deadletter
+
+import (
+	"errors"
+
+	"github.com/m3db/m3/src/msg/producer"
+	"github.com/m3db/m3/src/msg/producer/writer"
+)
+
+var (
+	errNoShards = errors.New("dead letter topic has no shards")
+)
+
+type producerSink struct {
+	p         producer.Producer
+	closeType producer.CloseType
+}
+
+// NewProducerSink creates a dead letter sink that produces the encoded dead
+// letters to the topic of the given producer, the producer is owned by the
+// sink and is closed with the given close type when the sink is closed.
+func NewProducerSink(
+	p producer.Producer,
+	closeType producer.CloseType,
+) writer.DeadLetterSink {
+	return &producerSink{
+		p:         p,
+		closeType: closeType,
+	}
+}
+
+func (s *producerSink) Write(dl writer.DeadLetter) error {
+	b, err := Encode(dl)
+	if err != nil {
+		return err
+	}
+	numShards := s.p.NumShards()
+	if numShards == 0 {
+		return errNoShards
+	}
+	return s.p.Produce(newMessage(dl.Shard%numShards, b))
+}
+
+func (s *producerSink) Close() error {
+	s.p.Close(s.closeType)
+	return nil
+}
+
+// message is a producer message holding an encoded dead letter.
+type message struct {
+	shard uint32
+	bytes []byte
+}
+
+func newMessage(shard uint32, bytes []byte) producer.Message {
+	return message{
+		shard: shard,
+		bytes: bytes,
+	}
+}
+
+func (m message) Shard() uint32                    { return m.shard }
+func (m message) Bytes() []byte                    { return m.bytes }
+func (m message) Size() int                        { return len(m.bytes) }
+func (m message) Finalize(producer.FinalizeReason) {}
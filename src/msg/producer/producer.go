@@ -34,8 +34,19 @@ func NewProducer(opts Options) Producer {
 }
 
 func (p *producer) Init() error {
+	pb, ok := p.Buffer.(PersistentBuffer)
+	if !ok {
+		p.Buffer.Init()
+		return p.Writer.Init()
+	}
+	// NB: A persistent buffer starts writing out the persisted messages
+	// once initialized, so the writer must be initialized first.
+	if err := p.Writer.Init(); err != nil {
+		return err
+	}
+	pb.SetWriteFn(p.Writer.Write)
 	p.Buffer.Init()
-	return p.Writer.Init()
+	return nil
 }
 
 func (p *producer) Produce(m Message) error {
@@ -43,6 +54,11 @@ func (p *producer) Produce(m Message) error {
 	if err != nil {
 		return err
 	}
+	if rm == nil {
+		// The message has been persisted by the buffer and
+		// will be written out asynchronously.
+		return nil
+	}
 	return p.Writer.Write(rm)
 }
 
@@ -33,6 +33,10 @@ const (
 
 	// Dropped means the message has been dropped.
 	Dropped
+
+	// Persisted means the message has been persisted by a persistent buffer,
+	// which produces the message from its persisted copy from then on.
+	Persisted
 )
 
 // Message contains the data that will be produced by the producer.
@@ -76,6 +80,9 @@ type Producer interface {
 	// producing to.
 	NumShards() uint32
 
+	// Stats returns the stats of the messages in flight to the consumer services.
+	Stats() TopicStats
+
 	// Init initializes a producer.
 	Init() error
 
@@ -117,6 +124,21 @@ type Buffer interface {
 	Close(ct CloseType)
 }
 
+// WriteFn writes a reference counted message out.
+type WriteFn func(rm *RefCountedMessage) error
+
+// PersistentBuffer is a buffer that persists messages so they survive producer
+// restarts, and writes out the persisted messages asynchronously.
+type PersistentBuffer interface {
+	Buffer
+
+	// SetWriteFn sets the function used to write out the persisted messages,
+	// it must be called before the buffer is initialized. Add may return a nil
+	// message without an error, which means the message has been persisted and
+	// will be written out later through the write function.
+	SetWriteFn(fn WriteFn)
+}
+
 // Writer writes all the messages out to the consumer services.
 type Writer interface {
 	// Write writes a reference counted message out.
@@ -132,9 +154,52 @@ type Writer interface {
 	// writing to.
 	NumShards() uint32
 
+	// Stats returns the stats of the messages in flight to the consumer services.
+	Stats() TopicStats
+
 	// Init initializes a writer.
 	Init() error
 
 	// Close closes the writer.
 	Close()
 }
+
+// TopicStats contains the stats of the messages in flight to the consumer
+// services of a topic.
+type TopicStats struct {
+	Topic            string                 `json:"topic"`
+	ConsumerServices []ConsumerServiceStats `json:"consumerServices"`
+}
+
+// ConsumerServiceStats contains the stats of the messages in flight to a
+// consumer service.
+type ConsumerServiceStats struct {
+	ConsumerService string       `json:"consumerService"`
+	ConsumptionType string       `json:"consumptionType"`
+	Shards          []ShardStats `json:"shards"`
+}
+
+// ShardStats contains the stats of the messages in flight for a shard to the
+// consumer instances owning the shard, the stats are collected when the queue
+// of messages is fully scanned so they could be stale by up to the full scan
+// interval of the writer.
+type ShardStats struct {
+	Shard             uint32   `json:"shard"`
+	ConsumerInstances []string `json:"consumerInstances"`
+
+	// NumInFlight is the number of messages not yet acknowledged.
+	NumInFlight int `json:"numInFlight"`
+
+	// OldestInFlightNanos is when the oldest message not yet acknowledged was
+	// first written in Unix nanoseconds, zero if there is no message in flight.
+	OldestInFlightNanos int64 `json:"oldestInFlightNanos"`
+
+	// NumRetries is the total number of retries of the messages in flight.
+	NumRetries int `json:"numRetries"`
+
+	// MaxRetries is the max number of retries of a message in flight.
+	MaxRetries int `json:"maxRetries"`
+
+	// UpdatedNanos is when the stats were collected in Unix nanoseconds.
+	UpdatedNanos int64 `json:"updatedNanos"`
+}
@@ -77,6 +77,9 @@ type consumerServiceWriter interface {
 
 	// UnregisterFilter unregisters the filter for the consumer service.
 	UnregisterFilter()
+
+	// Stats returns the stats of the messages in flight to the consumer service.
+	Stats() producer.ConsumerServiceStats
 }
 
 type consumerServiceWriterMetrics struct {
@@ -85,6 +88,8 @@ type consumerServiceWriterMetrics struct {
 	filterAccepted    tally.Counter
 	filterNotAccepted tally.Counter
 	queueSize         tally.Gauge
+	oldestInFlightAge tally.Gauge
+	maxRetries        tally.Gauge
 }
 
 func newConsumerServiceWriterMetrics(scope tally.Scope) consumerServiceWriterMetrics {
@@ -94,6 +99,8 @@ func newConsumerServiceWriterMetrics(scope tally.Scope) consumerServiceWriterMet
 		filterAccepted:    scope.Counter("filter-accepted"),
 		filterNotAccepted: scope.Counter("filter-not-accepted"),
 		queueSize:         scope.Gauge("queue-size"),
+		oldestInFlightAge: scope.Gauge("oldest-in-flight-age"),
+		maxRetries:        scope.Gauge("max-in-flight-retries"),
 	}
 }
 
@@ -148,6 +155,10 @@ func newConsumerServiceWriter(
 		cm:              newConsumerWriterMetrics(opts.InstrumentOptions().MetricsScope()),
 	}
 	w.processFn = w.process
+	deadLetter := resolveDeadLetterPolicy(opts.DeadLetterOptions(), cs.ServiceID().String(), cs.ServiceID().Name())
+	for _, sw := range w.shardWriters {
+		sw.SetDeadLetterPolicy(deadLetter)
+	}
 	return w, nil
 }
 
@@ -338,6 +349,18 @@ func (w *consumerServiceWriterImpl) UnregisterFilter() {
 	w.Unlock()
 }
 
+func (w *consumerServiceWriterImpl) Stats() producer.ConsumerServiceStats {
+	stats := producer.ConsumerServiceStats{
+		ConsumerService: w.cs.ServiceID().String(),
+		ConsumptionType: w.cs.ConsumptionType().String(),
+		Shards:          make([]producer.ShardStats, 0, len(w.shardWriters)),
+	}
+	for _, sw := range w.shardWriters {
+		stats.Shards = append(stats.Shards, sw.Stats()...)
+	}
+	return stats
+}
+
 func (w *consumerServiceWriterImpl) reportMetrics() {
 	t := time.NewTicker(w.opts.InstrumentOptions().ReportInterval())
 	defer t.Stop()
@@ -347,11 +370,32 @@ func (w *consumerServiceWriterImpl) reportMetrics() {
 		case <-w.doneCh:
 			return
 		case <-t.C:
-			var l int
+			var (
+				l                   int
+				oldestInFlightNanos int64
+				maxRetries          int
+			)
 			for _, sw := range w.shardWriters {
 				l += sw.QueueSize()
+				for _, s := range sw.Stats() {
+					if s.NumInFlight == 0 {
+						continue
+					}
+					if oldestInFlightNanos == 0 || s.OldestInFlightNanos < oldestInFlightNanos {
+						oldestInFlightNanos = s.OldestInFlightNanos
+					}
+					if s.MaxRetries > maxRetries {
+						maxRetries = s.MaxRetries
+					}
+				}
+			}
+			var oldestInFlightAge time.Duration
+			if oldestInFlightNanos > 0 {
+				oldestInFlightAge = time.Since(time.Unix(0, oldestInFlightNanos))
 			}
 			w.m.queueSize.Update(float64(l))
+			w.m.oldestInFlightAge.Update(oldestInFlightAge.Seconds())
+			w.m.maxRetries.Update(float64(maxRetries))
 		}
 	}
 }
@@ -669,3 +669,30 @@ func TestConsumerServiceCloseShardWritersConcurrently(t *testing.T) {
 func testPlacementService(store kv.Store, sid services.ServiceID) placement.Service {
 	return service.NewPlacementService(storage.NewPlacementStorage(store, sid.String(), placement.NewOptions()), placement.NewOptions())
 }
+
+func TestConsumerServiceWriterStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sw1 := NewMockshardWriter(ctrl)
+	sw1.EXPECT().Stats().Return([]producer.ShardStats{{Shard: 0, NumInFlight: 1}})
+	sw2 := NewMockshardWriter(ctrl)
+	sw2.EXPECT().Stats().Return([]producer.ShardStats{{Shard: 1, NumInFlight: 2}, {Shard: 1, NumInFlight: 3}})
+
+	cs := topic.NewConsumerService().
+		SetServiceID(services.NewServiceID().SetName("foo")).
+		SetConsumptionType(topic.Replicated)
+	w := &consumerServiceWriterImpl{
+		cs:           cs,
+		shardWriters: []shardWriter{sw1, sw2},
+	}
+	require.Equal(t, producer.ConsumerServiceStats{
+		ConsumerService: cs.ServiceID().String(),
+		ConsumptionType: "replicated",
+		Shards: []producer.ShardStats{
+			{Shard: 0, NumInFlight: 1},
+			{Shard: 1, NumInFlight: 2},
+			{Shard: 1, NumInFlight: 3},
+		},
+	}, w.Stats())
+}
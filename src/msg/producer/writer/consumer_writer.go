@@ -55,6 +55,10 @@ type consumerWriter interface {
 	// Write writes the bytes, it is thread safe.
 	Write(b []byte) error
 
+	// AcceptsCompressed returns whether the consumer has indicated it is able
+	// to decode compressed messages on the current connection.
+	AcceptsCompressed() bool
+
 	// Init initializes the consumer writer.
 	Init()
 
@@ -74,6 +78,7 @@ type consumerWriterMetrics struct {
 	connectError            tally.Counter
 	setKeepAliveError       tally.Counter
 	setKeepAlivePeriodError tally.Counter
+	tlsHandshakeError       tally.Counter
 }
 
 func newConsumerWriterMetrics(scope tally.Scope) consumerWriterMetrics {
@@ -89,6 +94,7 @@ func newConsumerWriterMetrics(scope tally.Scope) consumerWriterMetrics {
 		connectError:            scope.Counter("connect-error"),
 		setKeepAliveError:       scope.Counter("set-keep-alive-error"),
 		setKeepAlivePeriodError: scope.Counter("set-keep-alive-period-error"),
+		tlsHandshakeError:       scope.Counter("tls-handshake-error"),
 	}
 }
 
@@ -106,19 +112,27 @@ type consumerWriterImpl struct {
 	connRetrier retry.Retrier
 	logger      log.Logger
 
-	validConn      *atomic.Bool
-	conn           io.ReadWriteCloser
-	rw             *bufio.ReadWriter
-	lastResetNanos int64
-	resetCh        chan struct{}
-	ack            msgpb.Ack
-	closed         *atomic.Bool
-	doneCh         chan struct{}
-	wg             sync.WaitGroup
-	m              consumerWriterMetrics
+	validConn         *atomic.Bool
+	acceptsCompressed *atomic.Bool
+	conn              io.ReadWriteCloser
+	rw                *bufio.ReadWriter
+	lastResetNanos    int64
+	resetCh           chan struct{}
+	ack               msgpb.Ack
+	closed            *atomic.Bool
+	doneCh            chan struct{}
+	wg                sync.WaitGroup
+	m                 consumerWriterMetrics
 
 	nowFn     clock.NowFn
 	connectFn connectFn
+
+	// compressor is nil if compression is disabled, otherwise the messages
+	// written to a consumer that accepts compressed messages are batched and
+	// each batch is compressed as a whole.
+	compressor   proto.FrameCompressor
+	batch        []byte
+	maxBatchSize int
 }
 
 func newConsumerWriter(
@@ -132,30 +146,44 @@ func newConsumerWriter(
 	}
 
 	var (
-		connOpts = opts.ConnectionOptions()
-		rw       = bufio.NewReadWriter(
+		connOpts     = opts.ConnectionOptions()
+		encoderOpts  = opts.EncoderOptions()
+		compressor   proto.FrameCompressor
+		maxBatchSize = connOpts.WriteBufferSize()
+		rw           = bufio.NewReadWriter(
 			bufio.NewReaderSize(u, connOpts.ReadBufferSize()),
 			bufio.NewWriterSize(u, connOpts.WriteBufferSize()),
 		)
 	)
+	if encoderOpts.CompressionType() != proto.NoCompression {
+		compressor = proto.NewFrameCompressor(encoderOpts)
+	}
+	// NB: A batch is decompressed as a whole by the consumer, so it can not
+	// be larger than the max message size.
+	if maxMessageSize := encoderOpts.MaxMessageSize(); maxBatchSize > maxMessageSize {
+		maxBatchSize = maxMessageSize
+	}
 	w := &consumerWriterImpl{
-		decoder:        proto.NewDecoder(rw, opts.DecoderOptions()),
-		addr:           addr,
-		router:         router,
-		opts:           opts,
-		connOpts:       connOpts,
-		ackRetrier:     retry.NewRetrier(opts.AckErrorRetryOptions()),
-		connRetrier:    retry.NewRetrier(connOpts.RetryOptions().SetForever(defaultRetryForever)),
-		logger:         opts.InstrumentOptions().Logger(),
-		validConn:      atomic.NewBool(false),
-		conn:           u,
-		rw:             rw,
-		lastResetNanos: 0,
-		resetCh:        make(chan struct{}, 1),
-		closed:         atomic.NewBool(false),
-		doneCh:         make(chan struct{}),
-		m:              m,
-		nowFn:          time.Now,
+		decoder:           proto.NewDecoder(rw, opts.DecoderOptions()),
+		addr:              addr,
+		router:            router,
+		opts:              opts,
+		connOpts:          connOpts,
+		ackRetrier:        retry.NewRetrier(opts.AckErrorRetryOptions()),
+		connRetrier:       retry.NewRetrier(connOpts.RetryOptions().SetForever(defaultRetryForever)),
+		logger:            opts.InstrumentOptions().Logger(),
+		validConn:         atomic.NewBool(false),
+		acceptsCompressed: atomic.NewBool(false),
+		conn:              u,
+		rw:                rw,
+		compressor:        compressor,
+		maxBatchSize:      maxBatchSize,
+		lastResetNanos:    0,
+		resetCh:           make(chan struct{}, 1),
+		closed:            atomic.NewBool(false),
+		doneCh:            make(chan struct{}),
+		m:                 m,
+		nowFn:             time.Now,
 	}
 
 	w.connectFn = w.connectOnce
@@ -177,7 +205,7 @@ func (w *consumerWriterImpl) Write(b []byte) error {
 		return errInvalidConnection
 	}
 	w.writeLock.Lock()
-	_, err := w.rw.Write(b)
+	err := w.writeWithLock(b)
 	w.writeLock.Unlock()
 	if err != nil {
 		w.notifyReset()
@@ -186,6 +214,39 @@ func (w *consumerWriterImpl) Write(b []byte) error {
 	return err
 }
 
+func (w *consumerWriterImpl) writeWithLock(b []byte) error {
+	if w.compressor == nil || !w.acceptsCompressed.Load() {
+		_, err := w.rw.Write(b)
+		return err
+	}
+	if len(w.batch) > 0 && len(w.batch)+len(b) > w.maxBatchSize {
+		if err := w.flushBatchWithLock(); err != nil {
+			return err
+		}
+	}
+	if len(b) > w.maxBatchSize {
+		_, err := w.rw.Write(b)
+		return err
+	}
+	w.batch = append(w.batch, b...)
+	return nil
+}
+
+// flushBatchWithLock compresses the batched messages and writes them out
+// as a single compressed frame.
+func (w *consumerWriterImpl) flushBatchWithLock() error {
+	if len(w.batch) == 0 {
+		return nil
+	}
+	_, err := w.rw.Write(w.compressor.Compress(w.batch))
+	w.batch = w.batch[:0]
+	return err
+}
+
+func (w *consumerWriterImpl) AcceptsCompressed() bool {
+	return w.acceptsCompressed.Load()
+}
+
 func (w *consumerWriterImpl) Init() {
 	w.wg.Add(1)
 	go func() {
@@ -214,6 +275,10 @@ func (w *consumerWriterImpl) flushUntilClose() {
 		select {
 		case <-flushTicker.C:
 			w.writeLock.Lock()
+			if err := w.flushBatchWithLock(); err != nil {
+				w.notifyReset()
+				w.m.encodeError.Inc(1)
+			}
 			w.rw.Flush()
 			w.writeLock.Unlock()
 		case <-w.doneCh:
@@ -286,6 +351,7 @@ func (w *consumerWriterImpl) readAcks() error {
 	// NB(cw) The proto needs to be cleaned up because the gogo protobuf
 	// unmarshalling will append to the underlying slice.
 	w.ack.Metadata = w.ack.Metadata[:0]
+	w.ack.AcceptsCompressed = false
 	w.decodeLock.Lock()
 	err := w.decoder.Decode(&w.ack)
 	w.decodeLock.Unlock()
@@ -294,6 +360,9 @@ func (w *consumerWriterImpl) readAcks() error {
 		w.m.decodeError.Inc(1)
 		return err
 	}
+	if w.ack.AcceptsCompressed && !w.acceptsCompressed.Load() {
+		w.acceptsCompressed.Store(true)
+	}
 	for _, m := range w.ack.Metadata {
 		if err := w.router.Ack(newMetadataFromProto(m)); err != nil {
 			w.m.ackError.Inc(1)
@@ -337,7 +406,13 @@ func (w *consumerWriterImpl) reset(conn io.ReadWriteCloser) {
 	w.conn = conn
 	w.rw.Reader.Reset(conn)
 	w.rw.Writer.Reset(conn)
+	// The batched messages are dropped like the buffered ones, they will
+	// be retried as they are not acked.
+	w.batch = w.batch[:0]
 	w.lastResetNanos = w.nowFn().UnixNano()
+	// The consumer on the new connection needs to indicate again whether
+	// it is able to decode compressed messages.
+	w.acceptsCompressed.Store(false)
 }
 
 func (w *consumerWriterImpl) connectOnce(addr string) (io.ReadWriteCloser, error) {
@@ -350,6 +425,13 @@ func (w *consumerWriterImpl) connectOnce(addr string) (io.ReadWriteCloser, error
 	if err = tcpConn.SetKeepAlive(true); err != nil {
 		w.m.setKeepAliveError.Inc(1)
 	}
+	if tlsDialer := w.connOpts.TLSDialer(); tlsDialer != nil {
+		conn, err = tlsDialer.Client(tcpConn, addr, w.connOpts.DialTimeout())
+		if err != nil {
+			w.m.tlsHandshakeError.Inc(1)
+			return nil, err
+		}
+	}
 	keepAlivePeriod := w.connOpts.KeepAlivePeriod()
 	if keepAlivePeriod <= 0 {
 		return conn, nil
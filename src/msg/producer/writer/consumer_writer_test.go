@@ -21,6 +21,7 @@
 package writer
 
 import (
+	"bytes"
 	"io"
 	"net"
 	"sync"
@@ -92,6 +93,114 @@ func TestNewConsumerWriter(t *testing.T) {
 	require.Contains(t, err.Error(), "closed network connection")
 }
 
+func TestConsumerWriterAcceptsCompressed(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRouter := NewMockackRouter(ctrl)
+	opts := testOptions()
+	w := newConsumerWriter(lis.Addr().String(), mockRouter, opts, testConsumerWriterMetrics()).(*consumerWriterImpl)
+	require.False(t, w.AcceptsCompressed())
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		conn, err := lis.Accept()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		serverEncoder := proto.NewEncoder(opts.EncoderOptions())
+		serverDecoder := proto.NewDecoder(conn, opts.DecoderOptions())
+		var msg msgpb.Message
+		assert.NoError(t, serverDecoder.Decode(&msg))
+		assert.NoError(t, serverEncoder.Encode(&msgpb.Ack{
+			Metadata:          []msgpb.Metadata{msg.Metadata},
+			AcceptsCompressed: true,
+		}))
+		_, err = conn.Write(serverEncoder.Bytes())
+		assert.NoError(t, err)
+	}()
+
+	require.NoError(t, write(w, &testMsg))
+
+	wg.Add(1)
+	mockRouter.EXPECT().
+		Ack(newMetadataFromProto(testMsg.Metadata)).
+		Do(func(interface{}) { wg.Done() }).
+		Return(nil)
+
+	w.Init()
+	wg.Wait()
+	require.True(t, w.AcceptsCompressed())
+
+	// The consumer needs to indicate it accepts compressed messages again
+	// after the connection is reset.
+	w.reset(uninitializedReadWriter{})
+	require.False(t, w.AcceptsCompressed())
+	w.Close()
+}
+
+func TestConsumerWriterBatchesCompressedMessages(t *testing.T) {
+	defer leaktest.Check(t)()
+
+	opts := testOptions()
+	opts = opts.
+		SetEncoderOptions(opts.EncoderOptions().SetCompressionType(proto.SnappyCompression)).
+		SetConnectionOptions(opts.ConnectionOptions().SetWriteBufferSize(200))
+	w := newConsumerWriter("badAddress", nil, opts, testConsumerWriterMetrics()).(*consumerWriterImpl)
+	<-w.resetCh
+	var buf bytes.Buffer
+	w.rw.Writer.Reset(&buf)
+	w.validConn.Store(true)
+
+	require.NoError(t, testEncoder.Encode(&testMsg))
+	frameLen := len(testEncoder.Bytes())
+
+	// Messages are written as is before the consumer accepts compressed messages.
+	require.NoError(t, write(w, &testMsg))
+	require.Equal(t, 0, len(w.batch))
+	require.NoError(t, w.rw.Flush())
+	require.Equal(t, frameLen, buf.Len())
+
+	// Messages are batched until the batch is full.
+	w.acceptsCompressed.Store(true)
+	numMsgs := 1
+	for len(w.batch)+frameLen <= w.maxBatchSize {
+		require.NoError(t, write(w, &testMsg))
+		numMsgs++
+	}
+	require.NoError(t, w.rw.Flush())
+	require.Equal(t, frameLen, buf.Len())
+	require.NoError(t, write(w, &testMsg))
+	numMsgs++
+	require.Equal(t, frameLen, len(w.batch))
+
+	w.writeLock.Lock()
+	require.NoError(t, w.flushBatchWithLock())
+	w.writeLock.Unlock()
+	require.NoError(t, w.rw.Flush())
+	require.Equal(t, 0, len(w.batch))
+	require.True(t, buf.Len() < numMsgs*frameLen)
+
+	dec := proto.NewDecoder(&buf, nil)
+	for i := 0; i < numMsgs; i++ {
+		var msg msgpb.Message
+		require.NoError(t, dec.Decode(&msg))
+		require.Equal(t, testMsg, msg)
+	}
+	require.True(t, dec.ReceivedCompressed())
+	require.Equal(t, 0, buf.Len())
+}
+
 func TestConsumerWriterSignalResetConnection(t *testing.T) {
 	lis, err := net.Listen("tcp", "127.0.0.1:0")
 	require.NoError(t, err)
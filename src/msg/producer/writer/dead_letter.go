@@ -0,0 +1,135 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package writer
+
+import (
+	"fmt"
+	"time"
+)
+
+// DeadLetterReason is the reason why a message is moved to the dead letter sink.
+type DeadLetterReason int
+
+const (
+	// UnknownDeadLetterReason is an unknown reason.
+	UnknownDeadLetterReason DeadLetterReason = iota
+
+	// MaxRetriesExceeded means the message has been retried more than
+	// the max number of retries allowed by the consumer service.
+	MaxRetriesExceeded
+
+	// MaxAgeExceeded means the message has not been consumed within
+	// the max age allowed by the consumer service.
+	MaxAgeExceeded
+)
+
+func (r DeadLetterReason) String() string {
+	switch r {
+	case MaxRetriesExceeded:
+		return "maxRetriesExceeded"
+	case MaxAgeExceeded:
+		return "maxAgeExceeded"
+	}
+	return fmt.Sprintf("unknown(%d)", int(r))
+}
+
+// DeadLetter is a message that could not be consumed by a consumer service.
+type DeadLetter struct {
+	// ConsumerService is the consumer service that failed to consume the message.
+	ConsumerService string
+	// Shard is the shard of the message.
+	Shard uint32
+	// Value is the bytes of the message.
+	Value []byte
+	// Reason is the reason why the message is a dead letter.
+	Reason DeadLetterReason
+	// WriteTimes is the number of times the message has been written.
+	WriteTimes int
+	// InitNanos is when the message was first written in Unix nanoseconds.
+	InitNanos int64
+	// DeadNanos is when the message was moved to the sink in Unix nanoseconds.
+	DeadNanos int64
+}
+
+// DeadLetterSink receives the messages that could not be consumed.
+type DeadLetterSink interface {
+	// Write writes a dead letter.
+	Write(dl DeadLetter) error
+
+	// Close closes the sink.
+	Close() error
+}
+
+// DeadLetterPolicy decides when a message is moved to the dead letter sink
+// instead of being retried.
+type DeadLetterPolicy struct {
+	// MaxRetries is the max number of retries of a message, zero means unlimited.
+	MaxRetries int
+	// MaxAge is the max age of a message, zero means unlimited.
+	MaxAge time.Duration
+}
+
+// IsEmpty returns true if the policy never moves messages to the dead letter sink.
+func (p DeadLetterPolicy) IsEmpty() bool {
+	return p.MaxRetries <= 0 && p.MaxAge <= 0
+}
+
+// deadLetterPolicy is the dead letter policy resolved for a consumer service.
+type deadLetterPolicy struct {
+	DeadLetterPolicy
+
+	consumerService string
+	sink            DeadLetterSink
+}
+
+// isEnabled returns true if messages could be moved to the dead letter sink.
+func (p deadLetterPolicy) isEnabled() bool {
+	return p.sink != nil && !p.IsEmpty()
+}
+
+// reason returns the reason why the message should be moved to the dead
+// letter sink, or false if the message should continue to be retried.
+func (p deadLetterPolicy) reason(m *message, nowNanos int64) (DeadLetterReason, bool) {
+	if p.MaxAge > 0 && m.InitNanos()+int64(p.MaxAge) <= nowNanos {
+		return MaxAgeExceeded, true
+	}
+	// NB: The first write is not a retry.
+	if p.MaxRetries > 0 && m.WriteTimes() > p.MaxRetries {
+		return MaxRetriesExceeded, true
+	}
+	return UnknownDeadLetterReason, false
+}
+
+// resolveDeadLetterPolicy returns the dead letter policy of the consumer service.
+func resolveDeadLetterPolicy(opts DeadLetterOptions, consumerService string, serviceName string) deadLetterPolicy {
+	if opts == nil {
+		return deadLetterPolicy{}
+	}
+	policy, ok := opts.ServicePolicies()[serviceName]
+	if !ok {
+		policy = opts.DefaultPolicy()
+	}
+	return deadLetterPolicy{
+		DeadLetterPolicy: policy,
+		consumerService:  consumerService,
+		sink:             opts.Sink(),
+	}
+}
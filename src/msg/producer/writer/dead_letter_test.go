@@ -0,0 +1,86 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package writer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetterReasonString(t *testing.T) {
+	require.Equal(t, "maxRetriesExceeded", MaxRetriesExceeded.String())
+	require.Equal(t, "maxAgeExceeded", MaxAgeExceeded.String())
+	require.Equal(t, "unknown(0)", UnknownDeadLetterReason.String())
+}
+
+func TestResolveDeadLetterPolicy(t *testing.T) {
+	require.False(t, resolveDeadLetterPolicy(nil, "foo", "foo").isEnabled())
+
+	sink := &testDeadLetterSink{}
+	opts := NewDeadLetterOptions()
+	require.False(t, resolveDeadLetterPolicy(opts, "foo", "foo").isEnabled())
+
+	opts = opts.
+		SetSink(sink).
+		SetDefaultPolicy(DeadLetterPolicy{MaxRetries: 10}).
+		SetServicePolicies(map[string]DeadLetterPolicy{
+			"bar": {MaxAge: time.Hour},
+			"baz": {},
+		})
+
+	p := resolveDeadLetterPolicy(opts, "foo-id", "foo")
+	require.True(t, p.isEnabled())
+	require.Equal(t, DeadLetterPolicy{MaxRetries: 10}, p.DeadLetterPolicy)
+	require.Equal(t, "foo-id", p.consumerService)
+
+	p = resolveDeadLetterPolicy(opts, "bar-id", "bar")
+	require.True(t, p.isEnabled())
+	require.Equal(t, DeadLetterPolicy{MaxAge: time.Hour}, p.DeadLetterPolicy)
+
+	// An empty policy disables dead letters for the consumer service.
+	p = resolveDeadLetterPolicy(opts, "baz-id", "baz")
+	require.False(t, p.isEnabled())
+}
+
+func TestDeadLetterPolicyReason(t *testing.T) {
+	m := newMessage()
+	m.initNanos = 100
+
+	p := deadLetterPolicy{DeadLetterPolicy: DeadLetterPolicy{MaxRetries: 2, MaxAge: 50}}
+	_, ok := p.reason(m, 120)
+	require.False(t, ok)
+
+	m.IncWriteTimes()
+	m.IncWriteTimes()
+	_, ok = p.reason(m, 120)
+	require.False(t, ok)
+
+	m.IncWriteTimes()
+	reason, ok := p.reason(m, 120)
+	require.True(t, ok)
+	require.Equal(t, MaxRetriesExceeded, reason)
+
+	reason, ok = p.reason(m, 150)
+	require.True(t, ok)
+	require.Equal(t, MaxAgeExceeded, reason)
+}
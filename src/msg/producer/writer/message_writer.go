@@ -83,8 +83,15 @@ type messageWriter interface {
 	// SetMessageTTLNanos sets the message ttl nanoseconds.
 	SetMessageTTLNanos(nanos int64)
 
+	// SetDeadLetterPolicy sets the dead letter policy.
+	SetDeadLetterPolicy(value deadLetterPolicy)
+
 	// QueueSize returns the number of messages queued in the writer.
 	QueueSize() int
+
+	// Stats returns the stats of the messages in flight as of the last full
+	// scan of the message queue, the shard of the stats is not set.
+	Stats() producer.ShardStats
 }
 
 type messageWriterMetrics struct {
@@ -98,6 +105,9 @@ type messageWriterMetrics struct {
 	messageClosed            tally.Counter
 	messageDroppedBufferFull tally.Counter
 	messageDroppedTTLExpire  tally.Counter
+	messageDeadMaxRetries    tally.Counter
+	messageDeadMaxAge        tally.Counter
+	deadLetterError          tally.Counter
 	messageRetry             tally.Counter
 	messageConsumeLatency    tally.Timer
 	messageWriteDelay        tally.Timer
@@ -132,6 +142,13 @@ func newMessageWriterMetrics(
 		messageDroppedTTLExpire: scope.Tagged(
 			map[string]string{"reason": "ttl-expire"},
 		).Counter("message-dropped"),
+		messageDeadMaxRetries: scope.Tagged(
+			map[string]string{"reason": "max-retries"},
+		).Counter("message-dead-letter"),
+		messageDeadMaxAge: scope.Tagged(
+			map[string]string{"reason": "max-age"},
+		).Counter("message-dead-letter"),
+		deadLetterError:       scope.Counter("dead-letter-error"),
 		messageRetry:          scope.Counter("message-retry"),
 		messageConsumeLatency: instrument.MustCreateSampledTimer(scope.Timer("message-consume-latency"), samplingRate),
 		messageWriteDelay:     instrument.MustCreateSampledTimer(scope.Timer("message-write-delay"), samplingRate),
@@ -158,13 +175,17 @@ type messageWriterImpl struct {
 	cutOffNanos      int64
 	cutOverNanos     int64
 	messageTTLNanos  int64
+	deadLetter       deadLetterPolicy
 	msgsToWrite      []*message
+	deadLetters      []DeadLetter
 	isClosed         bool
 	doneCh           chan struct{}
 	wg               sync.WaitGroup
 	m                messageWriterMetrics
 	nextFullScan     time.Time
 	lastNewWrite     *list.Element
+	scanStats        producer.ShardStats
+	stats            producer.ShardStats
 
 	nowFn clock.NowFn
 }
@@ -179,13 +200,15 @@ func newMessageWriter(
 		opts = NewOptions()
 	}
 	nowFn := time.Now
+	// NB: Messages are compressed in batches by the consumer writers.
+	encoderOpts := opts.EncoderOptions().SetCompressionType(proto.NoCompression)
 	return &messageWriterImpl{
 		replicatedShardID: replicatedShardID,
 		mPool:             mPool,
 		opts:              opts,
 		retryOpts:         opts.MessageRetryOptions(),
 		r:                 rand.New(rand.NewSource(nowFn().UnixNano())),
-		encoder:           proto.NewEncoder(opts.EncoderOptions()),
+		encoder:           proto.NewEncoder(encoderOpts),
 		msgID:             0,
 		queue:             list.New(),
 		acks:              newAckHelper(opts.InitialAckMapSize()),
@@ -252,16 +275,14 @@ func (w *messageWriterImpl) write(
 	}
 	// The write function is accessed through only one thread,
 	// so no lock is required for encoding.
-	err := w.encoder.Encode(msg)
-	m.DecReads()
-	if err != nil {
+	if err := w.encoder.Encode(msg); err != nil {
+		m.DecReads()
 		return err
 	}
-	var (
-		written = false
-	)
+	written := false
 	for i := len(iterationIndexes) - 1; i >= 0; i-- {
-		if err := consumerWriters[randIndex(iterationIndexes, i)].Write(w.encoder.Bytes()); err != nil {
+		cw := consumerWriters[randIndex(iterationIndexes, i)]
+		if err := cw.Write(w.encoder.Bytes()); err != nil {
 			w.m.oneConsumerWriteError.Inc(1)
 			continue
 		}
@@ -269,6 +290,7 @@ func (w *messageWriterImpl) write(
 		w.m.writeSuccess.Inc(1)
 		break
 	}
+	m.DecReads()
 	if written {
 		return nil
 	}
@@ -344,6 +366,8 @@ func (w *messageWriterImpl) scanMessageQueue() {
 	w.RUnlock()
 	var (
 		msgsToWrite      []*message
+		deadLetters      []DeadLetter
+		deadLetterSink   DeadLetterSink
 		beforeScan       = w.nowFn()
 		batchSize        = w.opts.MessageQueueScanBatchSize()
 		consumerWriters  []consumerWriter
@@ -358,7 +382,10 @@ func (w *messageWriterImpl) scanMessageQueue() {
 		e, msgsToWrite = w.scanBatchWithLock(e, beforeBatchNanos, batchSize, fullScan)
 		consumerWriters = w.consumerWriters
 		iterationIndexes = w.iterationIndexes
+		deadLetters = w.deadLetters
+		deadLetterSink = w.deadLetter.sink
 		w.Unlock()
+		w.writeDeadLetters(deadLetterSink, deadLetters)
 		if !fullScan && len(msgsToWrite) == 0 {
 			w.m.scanBatchLatency.Record(w.nowFn().Sub(beforeBatch))
 			// If this is not a full scan, abort after the iteration batch
@@ -380,6 +407,11 @@ func (w *messageWriterImpl) scanMessageQueue() {
 	w.m.scanTotalLatency.Record(afterScan.Sub(beforeScan))
 	if fullScan {
 		w.nextFullScan = afterScan.Add(w.opts.MessageQueueFullScanInterval())
+		w.Lock()
+		w.stats = w.scanStats
+		w.stats.UpdatedNanos = afterScan.UnixNano()
+		w.scanStats = producer.ShardStats{}
+		w.Unlock()
 	}
 }
 
@@ -416,6 +448,7 @@ func (w *messageWriterImpl) scanBatchWithLock(
 		next     *list.Element
 	)
 	w.msgsToWrite = w.msgsToWrite[:0]
+	w.deadLetters = w.deadLetters[:0]
 	for e := start; e != nil; e = next {
 		iterated++
 		if iterated > batchSize {
@@ -441,6 +474,9 @@ func (w *messageWriterImpl) scanBatchWithLock(
 				// is not a new write.
 				break
 			}
+			if !m.IsAcked() {
+				w.addScanStatsWithLock(m)
+			}
 			continue
 		}
 		// If the message exceeded its allowed ttl of the consumer service,
@@ -471,17 +507,94 @@ func (w *messageWriterImpl) scanBatchWithLock(
 			w.m.messageDroppedBufferFull.Inc(1)
 			continue
 		}
+		if w.deadLetter.isEnabled() {
+			if reason, ok := w.deadLetter.reason(m, nowNanos); ok {
+				w.moveToDeadLetterWithLock(m, reason, nowNanos)
+				w.removeFromQueueWithLock(e, m)
+				continue
+			}
+		}
 		m.IncWriteTimes()
 		writeTimes := m.WriteTimes()
 		m.SetRetryAtNanos(w.nextRetryNanos(writeTimes, nowNanos))
 		if writeTimes > 1 {
 			w.m.messageRetry.Inc(1)
 		}
+		if fullScan {
+			w.addScanStatsWithLock(m)
+		}
 		w.msgsToWrite = append(w.msgsToWrite, m)
 	}
 	return next, w.msgsToWrite
 }
 
+// addScanStatsWithLock adds a message in flight to the stats of the current
+// full scan.
+func (w *messageWriterImpl) addScanStatsWithLock(m *message) {
+	w.scanStats.NumInFlight++
+	if initNanos := m.InitNanos(); w.scanStats.OldestInFlightNanos == 0 || initNanos < w.scanStats.OldestInFlightNanos {
+		w.scanStats.OldestInFlightNanos = initNanos
+	}
+	// NB: The first write is not a retry.
+	if retries := m.WriteTimes() - 1; retries > 0 {
+		w.scanStats.NumRetries += retries
+		if retries > w.scanStats.MaxRetries {
+			w.scanStats.MaxRetries = retries
+		}
+	}
+}
+
+// moveToDeadLetterWithLock queues the message to be written to the dead
+// letter sink and acks the message so it is no longer retried.
+func (w *messageWriterImpl) moveToDeadLetterWithLock(
+	m *message,
+	reason DeadLetterReason,
+	nowNanos int64,
+) {
+	// NB: The bytes must be copied before the message is acked as acking
+	// the message could finalize it.
+	m.IncReads()
+	if m.IsDroppedOrConsumed() {
+		m.DecReads()
+		return
+	}
+	dl := DeadLetter{
+		ConsumerService: w.deadLetter.consumerService,
+		Shard:           m.Shard(),
+		Value:           append([]byte(nil), m.Bytes()...),
+		Reason:          reason,
+		WriteTimes:      m.WriteTimes(),
+		InitNanos:       m.InitNanos(),
+		DeadNanos:       nowNanos,
+	}
+	m.DecReads()
+	// There is a chance the message was acked right before the ack is
+	// called, in which case it does not need to be moved to the sink.
+	if acked, _ := w.acks.ack(m.Metadata()); !acked {
+		return
+	}
+	switch reason {
+	case MaxRetriesExceeded:
+		w.m.messageDeadMaxRetries.Inc(1)
+	case MaxAgeExceeded:
+		w.m.messageDeadMaxAge.Inc(1)
+	}
+	w.deadLetters = append(w.deadLetters, dl)
+}
+
+// writeDeadLetters writes the dead letters queued by the last scan batch to
+// the sink, it is called without holding the lock so a slow sink does not
+// block new writes.
+func (w *messageWriterImpl) writeDeadLetters(sink DeadLetterSink, deadLetters []DeadLetter) {
+	for i, dl := range deadLetters {
+		if err := sink.Write(dl); err != nil {
+			w.m.deadLetterError.Inc(1)
+		}
+		// Release the message bytes.
+		deadLetters[i] = DeadLetter{}
+	}
+}
+
 func (w *messageWriterImpl) Close() {
 	w.Lock()
 	if w.isClosed {
@@ -562,6 +675,12 @@ func (w *messageWriterImpl) SetMessageTTLNanos(nanos int64) {
 	w.Unlock()
 }
 
+func (w *messageWriterImpl) SetDeadLetterPolicy(value deadLetterPolicy) {
+	w.Lock()
+	w.deadLetter = value
+	w.Unlock()
+}
+
 func (w *messageWriterImpl) AddConsumerWriter(cw consumerWriter) {
 	w.Lock()
 	newConsumerWriters := make([]consumerWriter, 0, len(w.consumerWriters)+1)
@@ -598,6 +717,17 @@ func (w *messageWriterImpl) QueueSize() int {
 	return w.acks.size()
 }
 
+func (w *messageWriterImpl) Stats() producer.ShardStats {
+	w.RLock()
+	stats := w.stats
+	stats.ConsumerInstances = make([]string, 0, len(w.consumerWriters))
+	for _, cw := range w.consumerWriters {
+		stats.ConsumerInstances = append(stats.ConsumerInstances, cw.Address())
+	}
+	w.RUnlock()
+	return stats
+}
+
 func (w *messageWriterImpl) newMessage() *message {
 	if w.mPool != nil {
 		return w.mPool.Get()
@@ -820,6 +820,176 @@ func TestMessageWriterQueueFullScanOnWriteErrors(t *testing.T) {
 	require.Equal(t, 1, w.queue.Len())
 }
 
+func TestMessageWriterStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := testOptions().SetMessageRetryOptions(
+		retry.NewOptions().SetInitialBackoff(2 * time.Nanosecond).SetMaxBackoff(5 * time.Nanosecond),
+	)
+	w := newMessageWriter(200, nil, opts, testMessageWriterMetrics()).(*messageWriterImpl)
+	require.Equal(t, producer.ShardStats{ConsumerInstances: []string{}}, w.Stats())
+
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		nowNanos := now.Add(time.Duration(i) * time.Second)
+		w.nowFn = func() time.Time { return nowNanos }
+		mm := producer.NewMockMessage(ctrl)
+		mm.EXPECT().Size().Return(3)
+		w.Write(producer.NewRefCountedMessage(mm, nil))
+	}
+
+	// NB: There is no consumer writer so the messages stay in flight.
+	w.nowFn = func() time.Time { return now.Add(time.Hour) }
+	w.scanMessageQueue()
+	require.Equal(t, producer.ShardStats{
+		ConsumerInstances:   []string{},
+		NumInFlight:         2,
+		OldestInFlightNanos: now.UnixNano(),
+		UpdatedNanos:        now.Add(time.Hour).UnixNano(),
+	}, w.Stats())
+
+	// Stats are only updated by full scans, but the messages are still retried.
+	w.nowFn = func() time.Time { return now.Add(2 * time.Hour) }
+	w.nextFullScan = now.Add(3 * time.Hour)
+	w.scanMessageQueue()
+	require.Equal(t, now.Add(time.Hour).UnixNano(), w.Stats().UpdatedNanos)
+
+	w.nextFullScan = time.Time{}
+	w.scanMessageQueue()
+	require.Equal(t, producer.ShardStats{
+		ConsumerInstances:   []string{},
+		NumInFlight:         2,
+		OldestInFlightNanos: now.UnixNano(),
+		NumRetries:          4,
+		MaxRetries:          2,
+		UpdatedNanos:        now.Add(2 * time.Hour).UnixNano(),
+	}, w.Stats())
+}
+
+func TestMessageWriterDeadLetterMaxRetries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := testOptions().SetMessageRetryOptions(
+		retry.NewOptions().SetInitialBackoff(2 * time.Nanosecond).SetMaxBackoff(5 * time.Nanosecond),
+	)
+	w := newMessageWriter(200, testMessagePool(opts), opts, testMessageWriterMetrics()).(*messageWriterImpl)
+	sink := &testDeadLetterSink{}
+	w.SetDeadLetterPolicy(deadLetterPolicy{
+		DeadLetterPolicy: DeadLetterPolicy{MaxRetries: 1},
+		consumerService:  "foo",
+		sink:             sink,
+	})
+
+	now := time.Now()
+	w.nowFn = func() time.Time { return now }
+
+	mm := producer.NewMockMessage(ctrl)
+	mm.EXPECT().Size().Return(3)
+	mm.EXPECT().Shard().Return(uint32(5)).AnyTimes()
+	mm.EXPECT().Bytes().Return([]byte("bar")).AnyTimes()
+	rm := producer.NewRefCountedMessage(mm, nil)
+	w.Write(rm)
+
+	// The first write and the first retry.
+	for i := 1; i <= 2; i++ {
+		_, toBeRetried := w.scanBatchWithLock(w.queue.Front(), now.UnixNano()+int64(i)*int64(time.Hour), 16, true)
+		require.Equal(t, 1, len(toBeRetried))
+		require.Equal(t, 0, len(sink.deadLetters))
+	}
+
+	mm.EXPECT().Finalize(producer.Consumed)
+	nowNanos := now.UnixNano() + 3*int64(time.Hour)
+	_, toBeRetried := w.scanBatchWithLock(w.queue.Front(), nowNanos, 16, true)
+	require.Equal(t, 0, len(toBeRetried))
+	require.Equal(t, 0, w.queue.Len())
+	require.True(t, isEmptyWithLock(w.acks))
+	require.True(t, rm.IsDroppedOrConsumed())
+
+	// The dead letters are written to the sink after the scan batch.
+	require.Equal(t, 0, len(sink.deadLetters))
+	require.Equal(t, 1, len(w.deadLetters))
+	w.writeDeadLetters(sink, w.deadLetters)
+	require.Equal(t, DeadLetter{}, w.deadLetters[0])
+	require.Equal(t, []DeadLetter{
+		{
+			ConsumerService: "foo",
+			Shard:           5,
+			Value:           []byte("bar"),
+			Reason:          MaxRetriesExceeded,
+			WriteTimes:      2,
+			InitNanos:       now.UnixNano(),
+			DeadNanos:       nowNanos,
+		},
+	}, sink.deadLetters)
+}
+
+func TestMessageWriterDeadLetterMaxAge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := testOptions().SetMessageRetryOptions(
+		retry.NewOptions().SetInitialBackoff(2 * time.Nanosecond).SetMaxBackoff(5 * time.Nanosecond),
+	)
+	w := newMessageWriter(200, nil, opts, testMessageWriterMetrics()).(*messageWriterImpl)
+	sink := &testDeadLetterSink{}
+	w.SetDeadLetterPolicy(deadLetterPolicy{
+		DeadLetterPolicy: DeadLetterPolicy{MaxAge: time.Minute},
+		consumerService:  "foo",
+		sink:             sink,
+	})
+
+	now := time.Now()
+	w.nowFn = func() time.Time { return now }
+
+	mm := producer.NewMockMessage(ctrl)
+	mm.EXPECT().Size().Return(3)
+	mm.EXPECT().Shard().Return(uint32(5)).AnyTimes()
+	mm.EXPECT().Bytes().Return([]byte("bar")).AnyTimes()
+	rm := producer.NewRefCountedMessage(mm, nil)
+	w.Write(rm)
+
+	_, toBeRetried := w.scanBatchWithLock(w.queue.Front(), now.UnixNano()+int64(time.Second), 16, true)
+	require.Equal(t, 1, len(toBeRetried))
+	require.Equal(t, 0, len(sink.deadLetters))
+
+	mm.EXPECT().Finalize(producer.Consumed)
+	_, toBeRetried = w.scanBatchWithLock(w.queue.Front(), now.UnixNano()+int64(time.Hour), 16, true)
+	require.Equal(t, 0, len(toBeRetried))
+	require.Equal(t, 0, w.queue.Len())
+	w.writeDeadLetters(sink, w.deadLetters)
+	require.Equal(t, 1, len(sink.deadLetters))
+	require.Equal(t, MaxAgeExceeded, sink.deadLetters[0].Reason)
+	require.Equal(t, 1, sink.deadLetters[0].WriteTimes)
+}
+
+func TestMessageWriterDeadLetterDisabledWithoutSink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	opts := testOptions().SetMessageRetryOptions(
+		retry.NewOptions().SetInitialBackoff(2 * time.Nanosecond).SetMaxBackoff(5 * time.Nanosecond),
+	)
+	w := newMessageWriter(200, nil, opts, testMessageWriterMetrics()).(*messageWriterImpl)
+	w.SetDeadLetterPolicy(deadLetterPolicy{
+		DeadLetterPolicy: DeadLetterPolicy{MaxRetries: 1},
+		consumerService:  "foo",
+	})
+
+	mm := producer.NewMockMessage(ctrl)
+	mm.EXPECT().Size().Return(3)
+	mm.EXPECT().Bytes().Return([]byte("bar")).AnyTimes()
+	w.Write(producer.NewRefCountedMessage(mm, nil))
+
+	now := time.Now()
+	for i := 1; i <= 3; i++ {
+		_, toBeRetried := w.scanBatchWithLock(w.queue.Front(), now.UnixNano()+int64(i)*int64(time.Hour), 16, true)
+		require.Equal(t, 1, len(toBeRetried))
+	}
+	require.Equal(t, 1, w.queue.Len())
+}
+
 func isEmptyWithLock(h *acks) bool {
 	h.Lock()
 	defer h.Unlock()
@@ -846,3 +1016,18 @@ func validateMessages(t *testing.T, msgs []*producer.RefCountedMessage, w *messa
 	w.RUnlock()
 	require.Equal(t, idx, len(msgs))
 }
+
+type testDeadLetterSink struct {
+	deadLetters []DeadLetter
+	closed      bool
+}
+
+func (s *testDeadLetterSink) Write(dl DeadLetter) error {
+	s.deadLetters = append(s.deadLetters, dl)
+	return nil
+}
+
+func (s *testDeadLetterSink) Close() error {
+	s.closed = true
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/m3db/m3/src/cluster/services"
 	"github.com/m3db/m3/src/msg/protocol/proto"
 	"github.com/m3db/m3/src/msg/topic"
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/instrument"
 	"github.com/m3db/m3x/pool"
 	"github.com/m3db/m3x/retry"
@@ -105,6 +106,12 @@ type ConnectionOptions interface {
 
 	// SetInstrumentOptions sets the instrument options.
 	SetInstrumentOptions(value instrument.Options) ConnectionOptions
+
+	// TLSDialer returns the TLS dialer, if set connections are made over TLS.
+	TLSDialer() *xtls.Dialer
+
+	// SetTLSDialer sets the TLS dialer, if set connections are made over TLS.
+	SetTLSDialer(value *xtls.Dialer) ConnectionOptions
 }
 
 type connectionOptions struct {
@@ -117,6 +124,7 @@ type connectionOptions struct {
 	writeBufferSize int
 	readBufferSize  int
 	iOpts           instrument.Options
+	tlsDialer       *xtls.Dialer
 }
 
 // NewConnectionOptions creates ConnectionOptions.
@@ -224,6 +232,79 @@ func (opts *connectionOptions) SetInstrumentOptions(value instrument.Options) Co
 	return &o
 }
 
+func (opts *connectionOptions) TLSDialer() *xtls.Dialer {
+	return opts.tlsDialer
+}
+
+func (opts *connectionOptions) SetTLSDialer(value *xtls.Dialer) ConnectionOptions {
+	o := *opts
+	o.tlsDialer = value
+	return &o
+}
+
+// DeadLetterOptions configs how messages that could not be consumed are
+// moved to the dead letter sink.
+type DeadLetterOptions interface {
+	// Sink returns the dead letter sink, the sink is closed when the writer is closed.
+	Sink() DeadLetterSink
+
+	// SetSink sets the dead letter sink, the sink is closed when the writer is closed.
+	SetSink(value DeadLetterSink) DeadLetterOptions
+
+	// DefaultPolicy returns the policy for consumer services without a policy.
+	DefaultPolicy() DeadLetterPolicy
+
+	// SetDefaultPolicy sets the policy for consumer services without a policy.
+	SetDefaultPolicy(value DeadLetterPolicy) DeadLetterOptions
+
+	// ServicePolicies returns the policies keyed by consumer service name.
+	ServicePolicies() map[string]DeadLetterPolicy
+
+	// SetServicePolicies sets the policies keyed by consumer service name.
+	SetServicePolicies(value map[string]DeadLetterPolicy) DeadLetterOptions
+}
+
+type deadLetterOptions struct {
+	sink            DeadLetterSink
+	defaultPolicy   DeadLetterPolicy
+	servicePolicies map[string]DeadLetterPolicy
+}
+
+// NewDeadLetterOptions creates DeadLetterOptions.
+func NewDeadLetterOptions() DeadLetterOptions {
+	return &deadLetterOptions{}
+}
+
+func (opts *deadLetterOptions) Sink() DeadLetterSink {
+	return opts.sink
+}
+
+func (opts *deadLetterOptions) SetSink(value DeadLetterSink) DeadLetterOptions {
+	o := *opts
+	o.sink = value
+	return &o
+}
+
+func (opts *deadLetterOptions) DefaultPolicy() DeadLetterPolicy {
+	return opts.defaultPolicy
+}
+
+func (opts *deadLetterOptions) SetDefaultPolicy(value DeadLetterPolicy) DeadLetterOptions {
+	o := *opts
+	o.defaultPolicy = value
+	return &o
+}
+
+func (opts *deadLetterOptions) ServicePolicies() map[string]DeadLetterPolicy {
+	return opts.servicePolicies
+}
+
+func (opts *deadLetterOptions) SetServicePolicies(value map[string]DeadLetterPolicy) DeadLetterOptions {
+	o := *opts
+	o.servicePolicies = value
+	return &o
+}
+
 // Options configs the writer.
 type Options interface {
 	// TopicName returns the topic name.
@@ -326,6 +407,12 @@ type Options interface {
 	// SetConnectionOptions sets the options for connections.
 	SetConnectionOptions(value ConnectionOptions) Options
 
+	// DeadLetterOptions returns the dead letter options.
+	DeadLetterOptions() DeadLetterOptions
+
+	// SetDeadLetterOptions sets the dead letter options.
+	SetDeadLetterOptions(value DeadLetterOptions) Options
+
 	// InstrumentOptions returns the instrument options.
 	InstrumentOptions() instrument.Options
 
@@ -350,6 +437,7 @@ type writerOptions struct {
 	encOpts                           proto.Options
 	decOpts                           proto.Options
 	cOpts                             ConnectionOptions
+	dlOpts                            DeadLetterOptions
 	iOpts                             instrument.Options
 }
 
@@ -368,6 +456,7 @@ func NewOptions() Options {
 		encOpts:                           proto.NewOptions(),
 		decOpts:                           proto.NewOptions(),
 		cOpts:                             NewConnectionOptions(),
+		dlOpts:                            NewDeadLetterOptions(),
 		iOpts:                             instrument.NewOptions(),
 	}
 }
@@ -532,6 +621,16 @@ func (opts *writerOptions) SetConnectionOptions(value ConnectionOptions) Options
 	return &o
 }
 
+func (opts *writerOptions) DeadLetterOptions() DeadLetterOptions {
+	return opts.dlOpts
+}
+
+func (opts *writerOptions) SetDeadLetterOptions(value DeadLetterOptions) Options {
+	o := *opts
+	o.dlOpts = value
+	return &o
+}
+
 func (opts *writerOptions) InstrumentOptions() instrument.Options {
 	return opts.iOpts
 }
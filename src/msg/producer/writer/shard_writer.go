@@ -43,14 +43,21 @@ type shardWriter interface {
 	// SetMessageTTLNanos sets the message ttl nanoseconds.
 	SetMessageTTLNanos(value int64)
 
+	// SetDeadLetterPolicy sets the dead letter policy.
+	SetDeadLetterPolicy(value deadLetterPolicy)
+
 	// Close closes the shard writer.
 	Close()
 
 	// QueueSize returns the number of messages queued for the shard.
 	QueueSize() int
+
+	// Stats returns the stats of the messages in flight for the shard.
+	Stats() []producer.ShardStats
 }
 
 type sharedShardWriter struct {
+	shard     uint32
 	instances map[string]struct{}
 	mw        messageWriter
 	isClosed  *atomic.Bool
@@ -68,6 +75,7 @@ func newSharedShardWriter(
 	mw.Init()
 	router.Register(replicatedShardID, mw)
 	return &sharedShardWriter{
+		shard:     shard,
 		instances: make(map[string]struct{}),
 		mw:        mw,
 		isClosed:  atomic.NewBool(false),
@@ -115,10 +123,20 @@ func (w *sharedShardWriter) QueueSize() int {
 	return w.mw.QueueSize()
 }
 
+func (w *sharedShardWriter) Stats() []producer.ShardStats {
+	stats := w.mw.Stats()
+	stats.Shard = w.shard
+	return []producer.ShardStats{stats}
+}
+
 func (w *sharedShardWriter) SetMessageTTLNanos(value int64) {
 	w.mw.SetMessageTTLNanos(value)
 }
 
+func (w *sharedShardWriter) SetDeadLetterPolicy(value deadLetterPolicy) {
+	w.mw.SetDeadLetterPolicy(value)
+}
+
 // nolint: maligned
 type replicatedShardWriter struct {
 	sync.RWMutex
@@ -133,6 +151,7 @@ type replicatedShardWriter struct {
 
 	messageWriters  map[string]messageWriter
 	messageTTLNanos int64
+	deadLetter      deadLetterPolicy
 	replicaID       uint32
 	isClosed        bool
 }
@@ -227,6 +246,7 @@ func (w *replicatedShardWriter) UpdateInstances(
 	w.Lock()
 	w.messageWriters = newMessageWriters
 	w.setMessageTTLNanosWithLock(w.messageTTLNanos)
+	w.setDeadLetterPolicyWithLock(w.deadLetter)
 	w.Unlock()
 
 	// If there are less instances for this shard, this happens when user
@@ -280,6 +300,18 @@ func (w *replicatedShardWriter) QueueSize() int {
 	return l
 }
 
+func (w *replicatedShardWriter) Stats() []producer.ShardStats {
+	w.RLock()
+	stats := make([]producer.ShardStats, 0, len(w.messageWriters))
+	for _, mw := range w.messageWriters {
+		s := mw.Stats()
+		s.Shard = w.shard
+		stats = append(stats, s)
+	}
+	w.RUnlock()
+	return stats
+}
+
 func (w *replicatedShardWriter) SetMessageTTLNanos(value int64) {
 	w.Lock()
 	w.messageTTLNanos = value
@@ -293,6 +325,19 @@ func (w *replicatedShardWriter) setMessageTTLNanosWithLock(value int64) {
 	}
 }
 
+func (w *replicatedShardWriter) SetDeadLetterPolicy(value deadLetterPolicy) {
+	w.Lock()
+	w.deadLetter = value
+	w.setDeadLetterPolicyWithLock(value)
+	w.Unlock()
+}
+
+func (w *replicatedShardWriter) setDeadLetterPolicyWithLock(value deadLetterPolicy) {
+	for _, mw := range w.messageWriters {
+		mw.SetDeadLetterPolicy(value)
+	}
+}
+
 func anyKeyValueInMap(
 	m map[placement.Instance]consumerWriter,
 ) (placement.Instance, consumerWriter, bool) {
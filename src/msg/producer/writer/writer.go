@@ -23,6 +23,7 @@ package writer
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/m3db/m3/src/cluster/services"
@@ -254,6 +255,27 @@ func (w *writer) Close() {
 	for _, csw := range w.consumerServiceWriters {
 		csw.Close()
 	}
+	if dlOpts := w.opts.DeadLetterOptions(); dlOpts != nil && dlOpts.Sink() != nil {
+		if err := dlOpts.Sink().Close(); err != nil {
+			w.logger.Errorf("could not close dead letter sink: %v", err)
+		}
+	}
+}
+
+func (w *writer) Stats() producer.TopicStats {
+	w.RLock()
+	stats := producer.TopicStats{
+		Topic:            w.topic,
+		ConsumerServices: make([]producer.ConsumerServiceStats, 0, len(w.consumerServiceWriters)),
+	}
+	for _, csw := range w.consumerServiceWriters {
+		stats.ConsumerServices = append(stats.ConsumerServices, csw.Stats())
+	}
+	w.RUnlock()
+	sort.Slice(stats.ConsumerServices, func(i, j int) bool {
+		return stats.ConsumerServices[i].ConsumerService < stats.ConsumerServices[j].ConsumerService
+	})
+	return stats
 }
 
 func (w *writer) RegisterFilter(sid services.ServiceID, filter producer.FilterFunc) {
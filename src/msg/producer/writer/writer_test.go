@@ -838,3 +838,26 @@ func TestWriterNumShards(t *testing.T) {
 	require.NoError(t, w.Init())
 	require.Equal(t, 2, int(w.NumShards()))
 }
+
+func TestWriterStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	csw1 := NewMockconsumerServiceWriter(ctrl)
+	csw1.EXPECT().Stats().Return(producer.ConsumerServiceStats{ConsumerService: "b"})
+	csw2 := NewMockconsumerServiceWriter(ctrl)
+	csw2.EXPECT().Stats().Return(producer.ConsumerServiceStats{ConsumerService: "a"})
+
+	w := NewWriter(testOptions()).(*writer)
+	w.consumerServiceWriters = map[string]consumerServiceWriter{
+		"b": csw1,
+		"a": csw2,
+	}
+	require.Equal(t, producer.TopicStats{
+		Topic: testOptions().TopicName(),
+		ConsumerServices: []producer.ConsumerServiceStats{
+			{ConsumerService: "a"},
+			{ConsumerService: "b"},
+		},
+	}, w.Stats())
+}
@@ -53,3 +53,30 @@ func BenchmarkBaseEncodeDecodeRoundTrip(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkSnappyEncodeDecodeRoundTrip(b *testing.B) {
+	r := bytes.NewReader(nil)
+	encoder := NewEncoder(NewOptions().SetCompressionType(SnappyCompression))
+	decoder := NewDecoder(r, NewOptions())
+	encodeMsg := msgpb.Message{
+		Metadata: msgpb.Metadata{},
+		Value:    make([]byte, 200),
+	}
+	decodeMsg := msgpb.Message{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		encodeMsg.Metadata.Id = uint64(n)
+		err := encoder.Encode(&encodeMsg)
+		if err != nil {
+			b.FailNow()
+		}
+		r.Reset(encoder.Bytes())
+		if err := decoder.Decode(&decodeMsg); err != nil {
+			b.FailNow()
+		}
+		if decodeMsg.Metadata.Id != uint64(n) {
+			b.FailNow()
+		}
+	}
+}
@@ -36,7 +36,7 @@ func growDataBufferIfNeeded(buffer []byte, targetSize int, pool pool.BytesPool)
 	if len(buffer) >= targetSize {
 		return buffer
 	}
-	if pool != nil {
+	if pool != nil && buffer != nil {
 		pool.Put(buffer)
 	}
 	return getByteSliceWithLength(targetSize, pool)
@@ -0,0 +1,124 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package proto
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3x/pool"
+
+	"github.com/golang/snappy"
+)
+
+// CompressionType is the type of compression applied to encoded payloads.
+type CompressionType string
+
+// List of supported compression types.
+const (
+	// NoCompression writes payloads as is.
+	NoCompression CompressionType = "none"
+
+	// SnappyCompression compresses payloads with snappy block encoding.
+	SnappyCompression CompressionType = "snappy"
+)
+
+const (
+	// compressedFlag is set in the size prefix of a frame whose payload is
+	// a batch of one or more uncompressed frames compressed together, so
+	// small messages written together are compressed as a whole. Frames
+	// without the flag are decoded as is, which keeps decoders compatible
+	// with peers that do not compress. Decoders that predate compression do
+	// not understand the flag, so peers only send compressed frames on a
+	// connection once the other end has indicated it is able to decode them.
+	compressedFlag uint32 = 1 << 31
+	sizeMask              = compressedFlag - 1
+)
+
+var (
+	validCompressionTypes = []CompressionType{
+		NoCompression,
+		SnappyCompression,
+	}
+)
+
+// UnmarshalYAML unmarshals CompressionType from yaml.
+func (t *CompressionType) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	if str == "" {
+		*t = NoCompression
+		return nil
+	}
+	var validStrings []string
+	for _, validType := range validCompressionTypes {
+		validString := string(validType)
+		if validString == str {
+			*t = validType
+			return nil
+		}
+		validStrings = append(validStrings, validString)
+	}
+
+	return fmt.Errorf("invalid compression type %s, valid types are: %v", str, validStrings)
+}
+
+type frameCompressor struct {
+	buffer    []byte
+	bytesPool pool.BytesPool
+}
+
+// NewFrameCompressor creates a new frame compressor, the implementation is
+// not thread safe.
+func NewFrameCompressor(opts Options) FrameCompressor {
+	if opts == nil {
+		opts = NewOptions()
+	}
+	return &frameCompressor{bytesPool: opts.BytesPool()}
+}
+
+func (c *frameCompressor) Compress(frames []byte) []byte {
+	c.buffer = growDataBufferIfNeeded(
+		c.buffer,
+		sizeEncodingLength+snappy.MaxEncodedLen(len(frames)),
+		c.bytesPool,
+	)
+	n, ok := compressFrames(c.buffer, frames)
+	if !ok {
+		return frames
+	}
+	return c.buffer[:n]
+}
+
+// compressFrames compresses the uncompressed frames into a single compressed
+// frame in the buffer, which must be large enough for the compressed frame,
+// and returns its length. It returns false if compression does not reduce
+// the size of the frames.
+func compressFrames(buffer []byte, frames []byte) (int, bool) {
+	compressed := snappy.Encode(buffer[sizeEncodingLength:], frames)
+	n := sizeEncodingLength + len(compressed)
+	if n >= len(frames) {
+		return 0, false
+	}
+	sizeEncodeDecoder.PutUint32(buffer, uint32(len(compressed))|compressedFlag)
+	return n, true
+}
@@ -27,8 +27,9 @@ import (
 
 // Configuration configures an Encoder or a Decoder.
 type Configuration struct {
-	MaxMessageSize *int                              `yaml:"maxMessageSize"`
-	BytesPool      *pool.BucketizedPoolConfiguration `yaml:"bytesPool"`
+	MaxMessageSize  *int                              `yaml:"maxMessageSize"`
+	BytesPool       *pool.BucketizedPoolConfiguration `yaml:"bytesPool"`
+	CompressionType *CompressionType                  `yaml:"compression"`
 }
 
 // NewOptions creates a new Options.
@@ -41,6 +42,9 @@ func (c *Configuration) NewOptions(
 	if c.MaxMessageSize != nil {
 		opts = opts.SetMaxMessageSize(*c.MaxMessageSize)
 	}
+	if c.CompressionType != nil {
+		opts = opts.SetCompressionType(*c.CompressionType)
+	}
 	if c.BytesPool != nil {
 		scope := iOpts.MetricsScope()
 		p := pool.NewBytesPool(
@@ -47,6 +47,7 @@ bytesPool:
 	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
 	opts := cfg.NewOptions(instrument.NewOptions())
 	require.Equal(t, 1024, opts.MaxMessageSize())
+	require.Equal(t, NoCompression, opts.CompressionType())
 	require.NotNil(t, opts.BytesPool())
 	b := opts.BytesPool().Get(2)
 	require.Equal(t, 0, len(b))
@@ -55,3 +56,19 @@ bytesPool:
 	require.Equal(t, 0, len(b))
 	require.Equal(t, 1024, cap(b))
 }
+
+func TestConfigurationCompression(t *testing.T) {
+	str := `
+compression: snappy
+`
+
+	var cfg Configuration
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+	opts := cfg.NewOptions(instrument.NewOptions())
+	require.Equal(t, SnappyCompression, opts.CompressionType())
+
+	str = `
+compression: foo
+`
+	require.Error(t, yaml.Unmarshal([]byte(str), &cfg))
+}
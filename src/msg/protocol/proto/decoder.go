@@ -21,20 +21,32 @@
 package proto
 
 import (
+	"errors"
 	"fmt"
 	"io"
 
 	"github.com/m3db/m3x/pool"
+
+	"github.com/golang/snappy"
+)
+
+var (
+	errInvalidCompressedFrame = errors.New("invalid frame in compressed frame")
 )
 
 type decoder struct {
-	r              io.Reader
-	buffer         []byte
-	bytesPool      pool.BytesPool
-	maxMessageSize int
+	r                  io.Reader
+	buffer             []byte
+	decompressed       []byte
+	frames             []byte
+	bytesPool          pool.BytesPool
+	maxMessageSize     int
+	receivedCompressed bool
 }
 
 // NewDecoder decodes a new decoder, the implementation is not thread safe.
+// The decoder accepts both compressed and uncompressed payloads regardless
+// of the configured compression type.
 func NewDecoder(r io.Reader, opts Options) Decoder {
 	if opts == nil {
 		opts = NewOptions()
@@ -49,32 +61,85 @@ func NewDecoder(r io.Reader, opts Options) Decoder {
 }
 
 func (d *decoder) Decode(m Unmarshaler) error {
-	size, err := d.decodeSize()
+	// NB: The frames decompressed from a compressed frame are decoded
+	// before the next frame is read.
+	for len(d.frames) == 0 {
+		buffer, compressed, err := d.readFrame()
+		if err != nil {
+			return err
+		}
+		if !compressed {
+			return m.Unmarshal(buffer)
+		}
+		if d.frames, err = d.decompress(buffer); err != nil {
+			return err
+		}
+		d.receivedCompressed = true
+	}
+	return d.decodeDecompressed(m)
+}
+
+// readFrame reads the payload of the next frame from the reader.
+func (d *decoder) readFrame() ([]byte, bool, error) {
+	size, compressed, err := d.decodeSize()
 	if err != nil {
-		return err
+		return nil, false, err
 	}
-	d.buffer = growDataBufferIfNeeded(d.buffer, sizeEncodingLength+size, d.bytesPool)
 	if size > d.maxMessageSize {
-		return fmt.Errorf("decoded message size %d is larger than maximum supported size %d", size, d.maxMessageSize)
+		return nil, false, fmt.Errorf("decoded message size %d is larger than maximum supported size %d", size, d.maxMessageSize)
+	}
+	d.buffer = growDataBufferIfNeeded(d.buffer, sizeEncodingLength+size, d.bytesPool)
+	buffer := d.buffer[sizeEncodingLength : sizeEncodingLength+size]
+	if _, err := io.ReadFull(d.r, buffer); err != nil {
+		return nil, false, err
+	}
+	return buffer, compressed, nil
+}
+
+// decodeDecompressed decodes the next frame decompressed from a compressed
+// frame, the decompressed frames must not be compressed themselves.
+func (d *decoder) decodeDecompressed(m Unmarshaler) error {
+	if len(d.frames) < sizeEncodingLength {
+		d.frames = nil
+		return errInvalidCompressedFrame
+	}
+	size := sizeEncodeDecoder.Uint32(d.frames)
+	end := sizeEncodingLength + int(size&sizeMask)
+	if size&compressedFlag != 0 || end > len(d.frames) {
+		d.frames = nil
+		return errInvalidCompressedFrame
 	}
-	return d.decodeData(d.buffer[sizeEncodingLength:sizeEncodingLength+size], m)
+	buffer := d.frames[sizeEncodingLength:end]
+	d.frames = d.frames[end:]
+	return m.Unmarshal(buffer)
 }
 
-func (d *decoder) decodeSize() (int, error) {
+func (d *decoder) decodeSize() (int, bool, error) {
 	if _, err := io.ReadFull(d.r, d.buffer[:sizeEncodingLength]); err != nil {
-		return 0, err
+		return 0, false, err
 	}
 	size := sizeEncodeDecoder.Uint32(d.buffer[:sizeEncodingLength])
-	return int(size), nil
+	return int(size & sizeMask), size&compressedFlag != 0, nil
 }
 
-func (d *decoder) decodeData(buffer []byte, m Unmarshaler) error {
-	if _, err := io.ReadFull(d.r, buffer); err != nil {
-		return err
+func (d *decoder) decompress(buffer []byte) ([]byte, error) {
+	size, err := snappy.DecodedLen(buffer)
+	if err != nil {
+		return nil, err
 	}
-	return m.Unmarshal(buffer)
+	if size > d.maxMessageSize {
+		return nil, fmt.Errorf("decompressed message size %d is larger than maximum supported size %d", size, d.maxMessageSize)
+	}
+	d.decompressed = growDataBufferIfNeeded(d.decompressed, size, d.bytesPool)
+	return snappy.Decode(d.decompressed[:size], buffer)
+}
+
+func (d *decoder) ReceivedCompressed() bool {
+	return d.receivedCompressed
 }
 
 func (d *decoder) ResetReader(r io.Reader) {
 	d.r = r
+	d.frames = nil
+	d.receivedCompressed = false
 }
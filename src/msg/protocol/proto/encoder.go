@@ -24,13 +24,17 @@ import (
 	"fmt"
 
 	"github.com/m3db/m3x/pool"
+
+	"github.com/golang/snappy"
 )
 
 type encoder struct {
-	buffer         []byte
-	bytesPool      pool.BytesPool
-	maxMessageSize int
-	encoded        int
+	buffer          []byte
+	scratch         []byte
+	bytesPool       pool.BytesPool
+	maxMessageSize  int
+	compressionType CompressionType
+	encoded         int
 }
 
 // NewEncoder creates a new encoder, the implementation is not thread safe.
@@ -40,9 +44,10 @@ func NewEncoder(opts Options) Encoder {
 	}
 	pool := opts.BytesPool()
 	return &encoder{
-		buffer:         getByteSliceWithLength(sizeEncodingLength, pool),
-		bytesPool:      pool,
-		maxMessageSize: opts.MaxMessageSize(),
+		buffer:          getByteSliceWithLength(sizeEncodingLength, pool),
+		bytesPool:       pool,
+		maxMessageSize:  opts.MaxMessageSize(),
+		compressionType: opts.CompressionType(),
 	}
 }
 
@@ -51,6 +56,9 @@ func (e *encoder) Encode(m Marshaler) error {
 	if size > e.maxMessageSize {
 		return fmt.Errorf("message size %d is larger than maximum supported size %d", size, e.maxMessageSize)
 	}
+	if e.compressionType == SnappyCompression {
+		return e.encodeSnappy(m, size)
+	}
 	e.buffer = growDataBufferIfNeeded(e.buffer, sizeEncodingLength+size, e.bytesPool)
 	e.encodeSize(size)
 	if err := e.encodeData(e.buffer[sizeEncodingLength:], m); err != nil {
@@ -60,6 +68,31 @@ func (e *encoder) Encode(m Marshaler) error {
 	return nil
 }
 
+// encodeSnappy encodes the message as an uncompressed frame in the scratch
+// buffer and compresses it into a compressed frame in the output buffer, the
+// uncompressed frame is written as is when compression does not reduce its
+// size.
+func (e *encoder) encodeSnappy(m Marshaler, size int) error {
+	frameLen := sizeEncodingLength + size
+	e.scratch = growDataBufferIfNeeded(e.scratch, frameLen, e.bytesPool)
+	sizeEncodeDecoder.PutUint32(e.scratch, uint32(size))
+	if err := e.encodeData(e.scratch[sizeEncodingLength:], m); err != nil {
+		return err
+	}
+	e.buffer = growDataBufferIfNeeded(
+		e.buffer,
+		sizeEncodingLength+snappy.MaxEncodedLen(frameLen),
+		e.bytesPool,
+	)
+	n, ok := compressFrames(e.buffer, e.scratch[:frameLen])
+	if !ok {
+		copy(e.buffer, e.scratch[:frameLen])
+		n = frameLen
+	}
+	e.encoded = n
+	return nil
+}
+
 func (e *encoder) Bytes() []byte {
 	return e.buffer[:e.encoded]
 }
@@ -25,19 +25,22 @@ import (
 )
 
 var (
-	defaultMaxMessageSize = 4 * 1024 * 1024 // 4MB.
+	defaultMaxMessageSize  = 4 * 1024 * 1024 // 4MB.
+	defaultCompressionType = NoCompression
 )
 
 // NewOptions creates a new Options.
 func NewOptions() Options {
 	return &options{
-		maxMessageSize: defaultMaxMessageSize,
+		maxMessageSize:  defaultMaxMessageSize,
+		compressionType: defaultCompressionType,
 	}
 }
 
 type options struct {
-	maxMessageSize int
-	bytesPool      pool.BytesPool
+	maxMessageSize  int
+	bytesPool       pool.BytesPool
+	compressionType CompressionType
 }
 
 func (opts *options) MaxMessageSize() int {
@@ -59,3 +62,13 @@ func (opts *options) SetBytesPool(value pool.BytesPool) Options {
 	o.bytesPool = value
 	return &o
 }
+
+func (opts *options) CompressionType() CompressionType {
+	return opts.compressionType
+}
+
+func (opts *options) SetCompressionType(value CompressionType) Options {
+	o := *opts
+	o.compressionType = value
+	return &o
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/m3db/m3/src/msg/generated/proto/msgpb"
 	"github.com/m3db/m3x/pool"
 
+	"github.com/golang/snappy"
 	"github.com/stretchr/testify/require"
 )
 
@@ -174,6 +175,172 @@ func TestEncodeDecodeRoundTrip(t *testing.T) {
 	require.Equal(t, testMsg, msg)
 }
 
+func TestEncodeDecodeRoundTripWithCompression(t *testing.T) {
+	enc := NewEncoder(NewOptions().SetCompressionType(SnappyCompression))
+	r := bytes.NewReader(nil)
+	dec := NewDecoder(r, NewOptions())
+	encodeMsg := msgpb.Message{
+		Metadata: msgpb.Metadata{
+			Shard: 1,
+			Id:    2,
+		},
+		Value: make([]byte, 1000),
+	}
+
+	require.NoError(t, enc.Encode(&encodeMsg))
+	size := sizeEncodeDecoder.Uint32(enc.Bytes())
+	require.True(t, size&compressedFlag != 0)
+	require.Equal(t, len(enc.Bytes())-sizeEncodingLength, int(size&sizeMask))
+	require.True(t, len(enc.Bytes()) < sizeEncodingLength+encodeMsg.Size())
+
+	var decodeMsg msgpb.Message
+	r.Reset(enc.Bytes())
+	require.NoError(t, dec.Decode(&decodeMsg))
+	require.Equal(t, encodeMsg, decodeMsg)
+}
+
+func TestEncodeWithCompressionIncompressiblePayload(t *testing.T) {
+	enc := NewEncoder(NewOptions().SetCompressionType(SnappyCompression))
+	encodeMsg := msgpb.Message{
+		Metadata: msgpb.Metadata{
+			Shard: 1,
+			Id:    2,
+		},
+		Value: []byte{1, 2, 3},
+	}
+
+	require.NoError(t, enc.Encode(&encodeMsg))
+	size := sizeEncodeDecoder.Uint32(enc.Bytes())
+	require.True(t, size&compressedFlag == 0)
+	require.Equal(t, encodeMsg.Size(), int(size))
+
+	var decodeMsg msgpb.Message
+	dec := NewDecoder(bytes.NewReader(enc.Bytes()), nil)
+	require.NoError(t, dec.Decode(&decodeMsg))
+	require.Equal(t, encodeMsg, decodeMsg)
+}
+
+func TestDecodeMixedCompressedAndUncompressed(t *testing.T) {
+	p := getBytesPool(2, []int{4, 16, 2048})
+	p.Init()
+
+	var (
+		compressedEnc = NewEncoder(NewOptions().SetCompressionType(SnappyCompression).SetBytesPool(p))
+		plainEnc      = NewEncoder(NewOptions().SetBytesPool(p))
+		buf           bytes.Buffer
+		encodeMsgs    []msgpb.Message
+	)
+	for i := 0; i < 4; i++ {
+		encodeMsg := msgpb.Message{
+			Metadata: msgpb.Metadata{
+				Shard: uint64(i),
+				Id:    uint64(i),
+			},
+			Value: bytes.Repeat([]byte{byte(i)}, 500),
+		}
+		enc := plainEnc
+		if i%2 == 1 {
+			enc = compressedEnc
+		}
+		require.NoError(t, enc.Encode(&encodeMsg))
+		buf.Write(enc.Bytes())
+		encodeMsgs = append(encodeMsgs, encodeMsg)
+	}
+
+	dec := NewDecoder(&buf, NewOptions().SetBytesPool(p))
+	for i, encodeMsg := range encodeMsgs {
+		var decodeMsg msgpb.Message
+		require.NoError(t, dec.Decode(&decodeMsg))
+		require.Equal(t, encodeMsg, decodeMsg)
+		require.Equal(t, i > 0, dec.ReceivedCompressed())
+	}
+
+	dec.ResetReader(&buf)
+	require.False(t, dec.ReceivedCompressed())
+}
+
+func TestDecodeDecompressedMessageLargerThanMaxSize(t *testing.T) {
+	enc := NewEncoder(NewOptions().SetCompressionType(SnappyCompression))
+	encodeMsg := msgpb.Message{
+		Metadata: msgpb.Metadata{
+			Shard: 1,
+			Id:    2,
+		},
+		Value: make([]byte, 1000),
+	}
+	require.NoError(t, enc.Encode(&encodeMsg))
+
+	var decodeMsg msgpb.Message
+	opts := NewOptions().SetMaxMessageSize(500)
+	dec := NewDecoder(bytes.NewReader(enc.Bytes()), opts)
+	err := dec.Decode(&decodeMsg)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "larger than maximum supported size")
+}
+
+func TestFrameCompressorRoundTrip(t *testing.T) {
+	var (
+		enc        = NewEncoder(nil)
+		compressor = NewFrameCompressor(nil)
+		frames     []byte
+		encodeMsgs []msgpb.Message
+	)
+	for i := 0; i < 10; i++ {
+		encodeMsg := msgpb.Message{
+			Metadata: msgpb.Metadata{
+				Shard: uint64(i),
+				Id:    uint64(i),
+			},
+			Value: []byte("foo.bar.baz"),
+		}
+		require.NoError(t, enc.Encode(&encodeMsg))
+		frames = append(frames, enc.Bytes()...)
+		encodeMsgs = append(encodeMsgs, encodeMsg)
+	}
+
+	// The messages are too small to be compressed on their own, but are
+	// compressed as a batch.
+	compressed := compressor.Compress(frames)
+	size := sizeEncodeDecoder.Uint32(compressed)
+	require.True(t, size&compressedFlag != 0)
+	require.True(t, len(compressed) < len(frames))
+
+	var buf bytes.Buffer
+	buf.Write(compressed)
+	require.NoError(t, enc.Encode(&encodeMsgs[0]))
+	buf.Write(enc.Bytes())
+	encodeMsgs = append(encodeMsgs, encodeMsgs[0])
+
+	dec := NewDecoder(&buf, nil)
+	for _, encodeMsg := range encodeMsgs {
+		var decodeMsg msgpb.Message
+		require.NoError(t, dec.Decode(&decodeMsg))
+		require.Equal(t, encodeMsg, decodeMsg)
+	}
+	require.True(t, dec.ReceivedCompressed())
+}
+
+func TestFrameCompressorIncompressibleFrames(t *testing.T) {
+	enc := NewEncoder(nil)
+	require.NoError(t, enc.Encode(&msgpb.Message{Value: []byte{1, 2, 3}}))
+	frames := append([]byte(nil), enc.Bytes()...)
+	require.Equal(t, frames, NewFrameCompressor(nil).Compress(frames))
+}
+
+func TestDecodeInvalidCompressedFrame(t *testing.T) {
+	var (
+		// The size of the first frame is larger than the decompressed frames.
+		frames = bytes.Repeat([]byte{0, 0, 1, 0}, 50)
+		buffer = make([]byte, sizeEncodingLength+snappy.MaxEncodedLen(len(frames)))
+	)
+	n, ok := compressFrames(buffer, frames)
+	require.True(t, ok)
+
+	var decodeMsg msgpb.Message
+	dec := NewDecoder(bytes.NewReader(buffer[:n]), nil)
+	require.Equal(t, errInvalidCompressedFrame, dec.Decode(&decodeMsg))
+}
+
 // nolint: unparam
 func getBytesPool(bucketSizes int, bucketCaps []int) pool.BytesPool {
 	buckets := make([]pool.Bucket, len(bucketCaps))
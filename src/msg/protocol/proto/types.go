@@ -56,10 +56,23 @@ type Decoder interface {
 	// Decode decodes the unmarshaler.
 	Decode(m Unmarshaler) error
 
+	// ReceivedCompressed returns whether a compressed payload has been
+	// decoded since the reader was last reset.
+	ReceivedCompressed() bool
+
 	// ResetReader resets the reader.
 	ResetReader(r io.Reader)
 }
 
+// FrameCompressor compresses batches of encoded frames.
+type FrameCompressor interface {
+	// Compress compresses the encoded uncompressed frames into a single
+	// compressed frame, the frames are returned as is if compression does
+	// not reduce their size. The returned bytes could be reused by the next
+	// compress call.
+	Compress(frames []byte) []byte
+}
+
 // Options configures a encoder or decoder.
 type Options interface {
 	// MaxMessageSize returns the maximum message size.
@@ -73,4 +86,18 @@ type Options interface {
 
 	// SetBytesPool sets the bytes pool.
 	SetBytesPool(value pool.BytesPool) Options
+
+	// CompressionType returns the compression type used by the encoder and
+	// the frame compressor, decoders accept both compressed and uncompressed
+	// payloads. Producers
+	// and consumers only use compression on a connection once the peer has
+	// indicated it is able to decode compressed payloads.
+	CompressionType() CompressionType
+
+	// SetCompressionType sets the compression type used by the encoder and
+	// the frame compressor, decoders accept both compressed and uncompressed
+	// payloads. Producers
+	// and consumers only use compression on a connection once the peer has
+	// indicated it is able to decode compressed payloads.
+	SetCompressionType(value CompressionType) Options
 }
@@ -0,0 +1,79 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/net/http"
+)
+
+const (
+	// CircuitBreakersURL is the url to view the state of the M3DB client
+	// per host circuit breakers.
+	CircuitBreakersURL = "/api/v1/debug/circuit-breakers"
+
+	// CircuitBreakersHTTPMethod is the HTTP method used with this resource.
+	CircuitBreakersHTTPMethod = http.MethodGet
+)
+
+// CircuitBreakersHandler represents a handler for the circuit breakers
+// debug endpoint.
+type CircuitBreakersHandler struct {
+	clusters m3.Clusters
+}
+
+// CircuitBreakersResponse is the circuit breaker state of each host
+// keyed by host ID, for each cluster namespace keyed by namespace ID.
+type CircuitBreakersResponse struct {
+	Namespaces map[string]map[string]string `json:"namespaces"`
+}
+
+// NewCircuitBreakersHandler returns a new instance of handler.
+func NewCircuitBreakersHandler(clusters m3.Clusters) http.Handler {
+	return &CircuitBreakersHandler{clusters: clusters}
+}
+
+func (h *CircuitBreakersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	resp := CircuitBreakersResponse{
+		Namespaces: make(map[string]map[string]string),
+	}
+	if h.clusters != nil {
+		for _, ns := range h.clusters.ClusterNamespaces() {
+			reporter, ok := ns.Session().(client.HostCircuitBreakerReporter)
+			if !ok {
+				continue
+			}
+			hosts := make(map[string]string)
+			for hostID, state := range reporter.HostCircuitBreakerStates() {
+				hosts[hostID] = state.String()
+			}
+			resp.Namespaces[ns.NamespaceID().String()] = hosts
+		}
+	}
+
+	xhttp.WriteJSONResponse(w, resp, logger)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCircuitBreakerSession struct {
+	*client.MockSession
+	states map[string]client.CircuitBreakerState
+}
+
+func (s testCircuitBreakerSession) HostCircuitBreakerStates() map[string]client.CircuitBreakerState {
+	return s.states
+}
+
+func TestCircuitBreakersHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	session := testCircuitBreakerSession{
+		MockSession: client.NewMockSession(ctrl),
+		states: map[string]client.CircuitBreakerState{
+			"host0": client.CircuitBreakerClosed,
+			"host1": client.CircuitBreakerOpen,
+			"host2": client.CircuitBreakerHalfOpen,
+		},
+	}
+	clusters, err := m3.NewClusters(m3.UnaggregatedClusterNamespaceDefinition{
+		NamespaceID: ident.StringID("metrics"),
+		Session:     session,
+		Retention:   48 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(CircuitBreakersHTTPMethod, CircuitBreakersURL, nil)
+	w := httptest.NewRecorder()
+	NewCircuitBreakersHandler(clusters).ServeHTTP(w, req)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var parsed CircuitBreakersResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&parsed))
+	assert.Equal(t, map[string]map[string]string{
+		"metrics": {
+			"host0": "closed",
+			"host1": "open",
+			"host2": "half_open",
+		},
+	}, parsed.Namespaces)
+}
@@ -50,4 +50,8 @@ func RegisterRoutes(
 	r.HandleFunc(ConfigGetBootstrappersURL, logged(NewConfigGetBootstrappersHandler(client)).ServeHTTP).Methods(ConfigGetBootstrappersHTTPMethod)
 	r.HandleFunc(ConfigSetBootstrappersURL, logged(NewConfigSetBootstrappersHandler(client)).ServeHTTP).Methods(ConfigSetBootstrappersHTTPMethod)
 
+	r.HandleFunc(ConfigListKVURL, logged(NewConfigListKVHandler(client)).ServeHTTP).Methods(ConfigListKVHTTPMethod)
+	r.HandleFunc(ConfigKVURL, logged(NewConfigGetKVHandler(client)).ServeHTTP).Methods(ConfigGetKVHTTPMethod)
+	r.HandleFunc(ConfigKVURL, logged(NewConfigSetKVHandler(client)).ServeHTTP).Methods(ConfigSetKVHTTPMethod)
+
 }
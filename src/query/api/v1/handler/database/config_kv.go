@@ -0,0 +1,303 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	dbconfig "github.com/m3db/m3/src/cmd/services/m3dbnode/config"
+	"github.com/m3db/m3/src/dbnode/kvconfig"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/util/logging"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+)
+
+const (
+	configKVKeyVar = "key"
+
+	// ConfigListKVURL is the url for the runtime kv config list handler.
+	ConfigListKVURL = handler.RoutePrefixV1 + "/database/config/kv"
+
+	// ConfigListKVHTTPMethod is the HTTP method used with this resource.
+	ConfigListKVHTTPMethod = http.MethodGet
+
+	// ConfigKVURL is the url for the runtime kv config get and set handlers.
+	ConfigKVURL = ConfigListKVURL + "/{" + configKVKeyVar + "}"
+)
+
+// KVConfigValueType is the type of the value of a runtime kv config key.
+type KVConfigValueType string
+
+const (
+	// KVConfigInt64Type is a value stored as a commonpb.Int64Proto.
+	KVConfigInt64Type KVConfigValueType = "int64"
+	// KVConfigStringType is a value stored as a commonpb.StringProto.
+	KVConfigStringType KVConfigValueType = "string"
+	// KVConfigStringArrayType is a value stored as a commonpb.StringArrayProto.
+	KVConfigStringArrayType KVConfigValueType = "stringArray"
+)
+
+var (
+	errUnknownKVConfigKey = errors.New("unknown kv config key")
+	errNoKVConfigValue    = errors.New("no value")
+)
+
+// kvConfigKey describes a runtime kv config key known to the coordinator.
+type kvConfigKey struct {
+	key         string
+	valueType   KVConfigValueType
+	description string
+	// validateFn receives an int64, string or []string depending on the
+	// value type.
+	validateFn func(value interface{}) error
+}
+
+// knownKVConfigKeys are the runtime kv config keys the database nodes watch,
+// the namespaces key is managed by the namespace endpoints instead.
+var knownKVConfigKeys = []kvConfigKey{
+	{
+		key:         kvconfig.BootstrapperKey,
+		valueType:   KVConfigStringArrayType,
+		description: "ordered list of bootstrappers used when a node bootstraps",
+		validateFn: func(value interface{}) error {
+			bootstrappers := value.([]string)
+			if len(bootstrappers) == 0 {
+				return errNoKVConfigValue
+			}
+			return dbconfig.ValidateBootstrappersOrder(bootstrappers)
+		},
+	},
+	{
+		key:         kvconfig.ClusterNewSeriesInsertLimitKey,
+		valueType:   KVConfigInt64Type,
+		description: "new series inserted per second across the cluster, zero disables the limit",
+		validateFn: func(value interface{}) error {
+			if value.(int64) < 0 {
+				return fmt.Errorf("limit must not be negative: %d", value.(int64))
+			}
+			return nil
+		},
+	},
+	{
+		key:         kvconfig.ClientBootstrapConsistencyLevel,
+		valueType:   KVConfigStringType,
+		description: "read consistency level used by node clients when bootstrapping from peers",
+		validateFn:  validateReadConsistencyLevel,
+	},
+	{
+		key:         kvconfig.ClientReadConsistencyLevel,
+		valueType:   KVConfigStringType,
+		description: "read consistency level used by node clients",
+		validateFn:  validateReadConsistencyLevel,
+	},
+	{
+		key:         kvconfig.ClientWriteConsistencyLevel,
+		valueType:   KVConfigStringType,
+		description: "write consistency level used by node clients",
+		validateFn:  validateConsistencyLevel,
+	},
+}
+
+func lookupKVConfigKey(key string) (kvConfigKey, bool) {
+	for _, k := range knownKVConfigKeys {
+		if k.key == key {
+			return k, true
+		}
+	}
+	return kvConfigKey{}, false
+}
+
+// parse converts a JSON value into the proto stored for the key, validating
+// it along the way.
+func (k kvConfigKey) parse(data json.RawMessage) (proto.Message, error) {
+	if len(data) == 0 {
+		return nil, errNoKVConfigValue
+	}
+
+	var (
+		value interface{}
+		msg   proto.Message
+	)
+	switch k.valueType {
+	case KVConfigInt64Type:
+		var v int64
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		value, msg = v, &commonpb.Int64Proto{Value: v}
+	case KVConfigStringType:
+		var v string
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		value, msg = v, &commonpb.StringProto{Value: v}
+	case KVConfigStringArrayType:
+		var v []string
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		value, msg = v, &commonpb.StringArrayProto{Values: v}
+	default:
+		return nil, fmt.Errorf("unknown value type: %s", k.valueType)
+	}
+
+	if err := k.validateFn(value); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// decode returns the JSON value of a stored kv value along with the string
+// form nodes use when reporting the value they have applied.
+func (k kvConfigKey) decode(v kv.Value) (interface{}, string, error) {
+	switch k.valueType {
+	case KVConfigInt64Type:
+		var msg commonpb.Int64Proto
+		if err := v.Unmarshal(&msg); err != nil {
+			return nil, "", err
+		}
+		return msg.Value, strconv.FormatInt(msg.Value, 10), nil
+	case KVConfigStringType:
+		var msg commonpb.StringProto
+		if err := v.Unmarshal(&msg); err != nil {
+			return nil, "", err
+		}
+		return msg.Value, msg.Value, nil
+	case KVConfigStringArrayType:
+		var msg commonpb.StringArrayProto
+		if err := v.Unmarshal(&msg); err != nil {
+			return nil, "", err
+		}
+		return msg.Values, strings.Join(msg.Values, ","), nil
+	default:
+		return nil, "", fmt.Errorf("unknown value type: %s", k.valueType)
+	}
+}
+
+func validateReadConsistencyLevel(value interface{}) error {
+	for _, level := range topology.ValidReadConsistencyLevels() {
+		if level.String() == value.(string) {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid read consistency level: %s", value.(string))
+}
+
+func validateConsistencyLevel(value interface{}) error {
+	for _, level := range topology.ValidConsistencyLevels() {
+		if level.String() == value.(string) {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid consistency level: %s", value.(string))
+}
+
+// KVConfigKey is a runtime kv config key along with its current value.
+type KVConfigKey struct {
+	Key         string            `json:"key"`
+	Type        KVConfigValueType `json:"type"`
+	Description string            `json:"description"`
+	// Value is omitted when the key is not set and nodes use their defaults.
+	Value   interface{} `json:"value,omitempty"`
+	Version int         `json:"version"`
+}
+
+// ConfigListKVResponse is the response for the runtime kv config list handler.
+type ConfigListKVResponse struct {
+	Keys []KVConfigKey `json:"keys"`
+}
+
+type configListKVHandler struct {
+	client clusterclient.Client
+}
+
+// NewConfigListKVHandler returns a new instance of a runtime kv config list
+// handler.
+func NewConfigListKVHandler(
+	client clusterclient.Client,
+) http.Handler {
+	return &configListKVHandler{
+		client: client,
+	}
+}
+
+func (h *configListKVHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx)
+
+	store, err := h.client.KV()
+	if err != nil {
+		logger.Error("unable to get kv store", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	resp := ConfigListKVResponse{Keys: make([]KVConfigKey, 0, len(knownKVConfigKeys))}
+	for _, k := range knownKVConfigKeys {
+		result, _, err := currentKVConfigKey(store, k)
+		if err != nil {
+			logger.Error("unable to get kv key", zap.String("key", k.key), zap.Any("error", err))
+			xhttp.Error(w, err, http.StatusInternalServerError)
+			return
+		}
+		resp.Keys = append(resp.Keys, result)
+	}
+
+	xhttp.WriteJSONResponse(w, resp, logger)
+}
+
+// currentKVConfigKey returns the key with its current value if set, along
+// with the string form of the value nodes report once applied.
+func currentKVConfigKey(store kv.Store, k kvConfigKey) (KVConfigKey, string, error) {
+	result := KVConfigKey{
+		Key:         k.key,
+		Type:        k.valueType,
+		Description: k.description,
+	}
+
+	v, err := store.Get(k.key)
+	if err == kv.ErrNotFound {
+		return result, "", nil
+	}
+	if err != nil {
+		return result, "", err
+	}
+
+	value, reported, err := k.decode(v)
+	if err != nil {
+		return result, "", err
+	}
+	result.Value = value
+	result.Version = v.Version()
+	return result, reported, nil
+}
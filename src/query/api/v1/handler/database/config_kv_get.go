@@ -0,0 +1,206 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package database
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/dbnode/kvconfig"
+	"github.com/m3db/m3/src/query/api/v1/handler/placement"
+	"github.com/m3db/m3/src/query/util/logging"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	// ConfigGetKVHTTPMethod is the HTTP method used with this resource.
+	ConfigGetKVHTTPMethod = http.MethodGet
+
+	historyParam = "history"
+)
+
+// KVConfigVersion is a previous value of a runtime kv config key.
+type KVConfigVersion struct {
+	Version int         `json:"version"`
+	Value   interface{} `json:"value"`
+}
+
+// KVConfigEffectiveValue is the value of a runtime kv config key a node
+// reported as applied.
+type KVConfigEffectiveValue struct {
+	HostID string `json:"hostID"`
+	Value  string `json:"value"`
+}
+
+// ConfigGetKVResponse is the response for the runtime kv config get handler.
+type ConfigGetKVResponse struct {
+	KVConfigKey
+
+	// History holds every version of the value up to the current one when
+	// requested with the history query parameter.
+	History []KVConfigVersion `json:"history,omitempty"`
+
+	// Effective holds the values reported by the database nodes in the
+	// placement, nodes that have not reported a value are omitted.
+	Effective []KVConfigEffectiveValue `json:"effective"`
+
+	// Converged is true when every node in the placement reported the current
+	// value as applied.
+	Converged bool `json:"converged"`
+}
+
+type configGetKVHandler struct {
+	client clusterclient.Client
+	nowFn  func() time.Time
+}
+
+// NewConfigGetKVHandler returns a new instance of a runtime kv config get
+// handler.
+func NewConfigGetKVHandler(
+	client clusterclient.Client,
+) http.Handler {
+	return &configGetKVHandler{
+		client: client,
+		nowFn:  time.Now,
+	}
+}
+
+func (h *configGetKVHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx)
+
+	key := strings.TrimSpace(mux.Vars(r)[configKVKeyVar])
+	k, ok := lookupKVConfigKey(key)
+	if !ok {
+		xhttp.Error(w, errUnknownKVConfigKey, http.StatusNotFound)
+		return
+	}
+
+	store, err := h.client.KV()
+	if err != nil {
+		logger.Error("unable to get kv store", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	current, reported, err := currentKVConfigKey(store, k)
+	if err != nil {
+		logger.Error("unable to get kv key", zap.String("key", key), zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	resp := ConfigGetKVResponse{KVConfigKey: current}
+	if r.URL.Query().Get(historyParam) == "true" && current.Version > 0 {
+		if resp.History, err = kvConfigHistory(store, k, current.Version); err != nil {
+			logger.Error("unable to get kv key history", zap.String("key", key), zap.Any("error", err))
+			xhttp.Error(w, err, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	hostIDs, err := h.placementHostIDs(r)
+	if err != nil {
+		logger.Error("unable to get placement", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	resp.Effective = make([]KVConfigEffectiveValue, 0, len(hostIDs))
+	resp.Converged = current.Version > 0 && len(hostIDs) > 0
+	for _, hostID := range hostIDs {
+		v, err := store.Get(kvconfig.EffectiveValueKey(key, hostID))
+		if err == kv.ErrNotFound {
+			resp.Converged = false
+			continue
+		}
+		if err != nil {
+			logger.Error("unable to get effective kv value", zap.String("hostID", hostID), zap.Any("error", err))
+			xhttp.Error(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		var effective commonpb.StringProto
+		if err := v.Unmarshal(&effective); err != nil {
+			logger.Error("unable to unmarshal effective kv value", zap.String("hostID", hostID), zap.Any("error", err))
+			xhttp.Error(w, err, http.StatusInternalServerError)
+			return
+		}
+		if effective.Value != reported {
+			resp.Converged = false
+		}
+		resp.Effective = append(resp.Effective, KVConfigEffectiveValue{
+			HostID: hostID,
+			Value:  effective.Value,
+		})
+	}
+
+	xhttp.WriteJSONResponse(w, resp, logger)
+}
+
+// placementHostIDs returns the IDs of the instances in the M3DB placement,
+// or none if there is no placement yet.
+func (h *configGetKVHandler) placementHostIDs(r *http.Request) ([]string, error) {
+	opts := placement.NewServiceOptions(placement.M3DBServiceName, r.Header, nil)
+	service, err := placement.Service(h.client, opts, h.nowFn(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := service.Placement()
+	if err == kv.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	instances := p.Instances()
+	hostIDs := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		hostIDs = append(hostIDs, instance.ID())
+	}
+	return hostIDs, nil
+}
+
+func kvConfigHistory(store kv.Store, k kvConfigKey, version int) ([]KVConfigVersion, error) {
+	values, err := store.History(k.key, 1, version+1)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]KVConfigVersion, 0, len(values))
+	for _, v := range values {
+		value, _, err := k.decode(v)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, KVConfigVersion{Version: v.Version(), Value: value})
+	}
+	return history, nil
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package database
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/query/util/logging"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	// ConfigSetKVHTTPMethod is the HTTP method used with this resource.
+	ConfigSetKVHTTPMethod = http.MethodPost
+)
+
+// ConfigSetKVRequest is the request for the runtime kv config set handler.
+type ConfigSetKVRequest struct {
+	Value json.RawMessage `json:"value"`
+
+	// Version, when set, makes the update a check and set against the
+	// current version of the key, zero meaning the key must not be set.
+	Version *int `json:"version"`
+}
+
+type configSetKVHandler struct {
+	client clusterclient.Client
+}
+
+// NewConfigSetKVHandler returns a new instance of a runtime kv config set
+// handler.
+func NewConfigSetKVHandler(
+	client clusterclient.Client,
+) http.Handler {
+	return &configSetKVHandler{
+		client: client,
+	}
+}
+
+func (h *configSetKVHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx)
+
+	key := strings.TrimSpace(mux.Vars(r)[configKVKeyVar])
+	k, ok := lookupKVConfigKey(key)
+	if !ok {
+		xhttp.Error(w, errUnknownKVConfigKey, http.StatusNotFound)
+		return
+	}
+
+	req, value, rErr := h.parseRequest(r, k)
+	if rErr != nil {
+		logger.Error("unable to parse request", zap.Any("error", rErr))
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	store, err := h.client.KV()
+	if err != nil {
+		logger.Error("unable to get kv store", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	if req.Version != nil {
+		_, err = store.CheckAndSet(key, *req.Version, value)
+	} else {
+		_, err = store.Set(key, value)
+	}
+	if err == kv.ErrVersionMismatch {
+		xhttp.Error(w, err, http.StatusConflict)
+		return
+	}
+	if err != nil {
+		logger.Error("unable to set kv key", zap.String("key", key), zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	result, _, err := currentKVConfigKey(store, k)
+	if err != nil {
+		logger.Error("unable to get kv key", zap.String("key", key), zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, result, logger)
+}
+
+func (h *configSetKVHandler) parseRequest(
+	r *http.Request,
+	k kvConfigKey,
+) (ConfigSetKVRequest, proto.Message, *xhttp.ParseError) {
+	var req ConfigSetKVRequest
+
+	defer r.Body.Close()
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return req, nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+
+	value, err := k.parse(req.Value)
+	if err != nil {
+		return req, nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+
+	return req, value, nil
+}
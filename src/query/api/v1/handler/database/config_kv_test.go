@@ -0,0 +1,232 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package database
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/commonpb"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/dbnode/kvconfig"
+	xtest "github.com/m3db/m3/src/x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigListKVHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient, mockStore, _ := SetupDatabaseTest(t, ctrl)
+	handler := NewConfigListKVHandler(mockClient)
+
+	mockStore.EXPECT().
+		Get(kvconfig.ClusterNewSeriesInsertLimitKey).
+		Return(mem.NewValue(3, &commonpb.Int64Proto{Value: 10000}), nil)
+	mockStore.EXPECT().
+		Get(gomock.Any()).
+		Return(nil, kv.ErrNotFound).
+		Times(len(knownKVConfigKeys) - 1)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", ConfigListKVURL, nil)
+	handler.ServeHTTP(w, req)
+
+	resp := w.Result()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body),
+		`{"key":"m3db.node.cluster-new-series-insert-limit","type":"int64",`+
+			`"description":"new series inserted per second across the cluster, zero disables the limit",`+
+			`"value":10000,"version":3}`)
+	assert.Contains(t, string(body), `{"key":"m3db.node.bootstrapper","type":"stringArray",`)
+}
+
+func TestConfigGetKVHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient, mockStore, mockPlacementService := SetupDatabaseTest(t, ctrl)
+	router := mux.NewRouter()
+	router.HandleFunc(ConfigKVURL, NewConfigGetKVHandler(mockClient).ServeHTTP)
+
+	key := kvconfig.ClientWriteConsistencyLevel
+	mockStore.EXPECT().
+		Get(key).
+		Return(mem.NewValue(2, &commonpb.StringProto{Value: "all"}), nil)
+	mockStore.EXPECT().
+		History(key, 1, 3).
+		Return([]kv.Value{
+			mem.NewValue(1, &commonpb.StringProto{Value: "majority"}),
+			mem.NewValue(2, &commonpb.StringProto{Value: "all"}),
+		}, nil)
+
+	mockPlacementService.EXPECT().
+		Placement().
+		Return(placement.NewPlacement().SetInstances([]placement.Instance{
+			placement.NewInstance().SetID("host1"),
+			placement.NewInstance().SetID("host2"),
+		}), nil)
+	mockStore.EXPECT().
+		Get(kvconfig.EffectiveValueKey(key, "host1")).
+		Return(mem.NewValue(4, &commonpb.StringProto{Value: "all"}), nil)
+	mockStore.EXPECT().
+		Get(kvconfig.EffectiveValueKey(key, "host2")).
+		Return(mem.NewValue(1, &commonpb.StringProto{Value: "majority"}), nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", ConfigListKVURL+"/"+key+"?history=true", nil)
+	router.ServeHTTP(w, req)
+
+	resp := w.Result()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	expectedResponse := `
+	{
+		"key": "m3db.client.write-consistency-level",
+		"type": "string",
+		"description": "write consistency level used by node clients",
+		"value": "all",
+		"version": 2,
+		"history": [
+			{"version": 1, "value": "majority"},
+			{"version": 2, "value": "all"}
+		],
+		"effective": [
+			{"hostID": "host1", "value": "all"},
+			{"hostID": "host2", "value": "majority"}
+		],
+		"converged": false
+	}
+	`
+	assert.Equal(t, mustPrettyJSON(t, expectedResponse), mustPrettyJSON(t, string(body)),
+		xtest.Diff(mustPrettyJSON(t, expectedResponse), mustPrettyJSON(t, string(body))))
+}
+
+func TestConfigGetKVHandlerUnknownKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient, _, _ := SetupDatabaseTest(t, ctrl)
+	router := mux.NewRouter()
+	router.HandleFunc(ConfigKVURL, NewConfigGetKVHandler(mockClient).ServeHTTP)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", ConfigListKVURL+"/"+kvconfig.NamespacesKey, nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+}
+
+func TestConfigSetKVHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient, mockStore, _ := SetupDatabaseTest(t, ctrl)
+	router := mux.NewRouter()
+	router.HandleFunc(ConfigKVURL, NewConfigSetKVHandler(mockClient).ServeHTTP)
+
+	key := kvconfig.BootstrapperKey
+	value := &commonpb.StringArrayProto{
+		Values: []string{"filesystem", "commitlog", "peers", "uninitialized_topology"},
+	}
+	mockStore.EXPECT().
+		CheckAndSet(key, 1, value).
+		Return(2, nil)
+	mockStore.EXPECT().
+		Get(key).
+		Return(mem.NewValue(2, value), nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", ConfigListKVURL+"/"+key, strings.NewReader(`
+	{
+		"value": ["filesystem", "commitlog", "peers", "uninitialized_topology"],
+		"version": 1
+	}
+	`))
+	router.ServeHTTP(w, req)
+
+	resp := w.Result()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `"value":["filesystem","commitlog","peers","uninitialized_topology"],"version":2`)
+}
+
+func TestConfigSetKVHandlerVersionMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient, mockStore, _ := SetupDatabaseTest(t, ctrl)
+	router := mux.NewRouter()
+	router.HandleFunc(ConfigKVURL, NewConfigSetKVHandler(mockClient).ServeHTTP)
+
+	key := kvconfig.ClusterNewSeriesInsertLimitKey
+	mockStore.EXPECT().
+		CheckAndSet(key, 3, &commonpb.Int64Proto{Value: 500}).
+		Return(0, kv.ErrVersionMismatch)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", ConfigListKVURL+"/"+key,
+		strings.NewReader(`{"value": 500, "version": 3}`))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+}
+
+func TestConfigSetKVHandlerInvalidValue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient, _, _ := SetupDatabaseTest(t, ctrl)
+	router := mux.NewRouter()
+	router.HandleFunc(ConfigKVURL, NewConfigSetKVHandler(mockClient).ServeHTTP)
+
+	for _, test := range []struct {
+		key  string
+		body string
+	}{
+		{key: kvconfig.ClientReadConsistencyLevel, body: `{"value": "three"}`},
+		{key: kvconfig.ClientWriteConsistencyLevel, body: `{"value": 2}`},
+		{key: kvconfig.ClusterNewSeriesInsertLimitKey, body: `{"value": -1}`},
+		{key: kvconfig.BootstrapperKey, body: `{"value": []}`},
+		{key: kvconfig.BootstrapperKey, body: `{}`},
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", ConfigListKVURL+"/"+test.key,
+			strings.NewReader(test.body))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, test.body)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -74,6 +75,10 @@ const (
 	HeaderClusterZoneName = "Cluster-Zone-Name"
 	// HeaderDryRun is the header used to specify whether this should be a dry run.
 	HeaderDryRun = "Dry-Run"
+	// HeaderClusterZoneReplicaFactors is the header used to specify the number
+	// of replicas each zone holds for placements spanning multiple zones,
+	// e.g. "zone-a=2,zone-b=1".
+	HeaderClusterZoneReplicaFactors = "Cluster-Zone-Replica-Factors"
 
 	defaultM3AggMaxAggregationWindowSize = time.Minute
 	// defaultM3AggWarmupDuration configures the buffer to account for the delay
@@ -113,6 +118,10 @@ type HandlerOptions struct {
 	Config        config.Configuration
 
 	M3AggServiceOptions *M3AggServiceOptions
+
+	// ShardSizeFetcher is used to estimate the bytes streamed by simulated
+	// M3DB placement changes, no estimate is made if it is nil.
+	ShardSizeFetcher ShardSizeFetcher
 }
 
 // NewHandlerOptions is the constructor function for HandlerOptions.
@@ -145,6 +154,12 @@ type ServiceOptions struct {
 	M3Agg *M3AggServiceOptions
 
 	DryRun bool
+
+	// ZoneReplicaFactors is the number of replicas each zone holds, only
+	// set for placements spanning multiple zones.
+	ZoneReplicaFactors map[string]int
+
+	zoneReplicaFactorsErr error
 }
 
 // M3AggServiceOptions contains the service options that are
@@ -180,6 +195,9 @@ func NewServiceOptions(
 	if v := strings.TrimSpace(headers.Get(HeaderDryRun)); v == "true" {
 		opts.DryRun = true
 	}
+	if v := strings.TrimSpace(headers.Get(HeaderClusterZoneReplicaFactors)); v != "" {
+		opts.ZoneReplicaFactors, opts.zoneReplicaFactorsErr = parseZoneReplicaFactors(v)
+	}
 
 	if m3AggOpts != nil {
 		if m3AggOpts.MaxAggregationWindowSize > 0 {
@@ -194,6 +212,26 @@ func NewServiceOptions(
 	return opts
 }
 
+func parseZoneReplicaFactors(value string) (map[string]int, error) {
+	zoneRFs := make(map[string]int)
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid zone replica factor %q, expected zone=rf", entry)
+		}
+		zone := strings.TrimSpace(parts[0])
+		rf, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if zone == "" || err != nil || rf <= 0 {
+			return nil, fmt.Errorf("invalid zone replica factor %q, expected zone=rf", entry)
+		}
+		if _, ok := zoneRFs[zone]; ok {
+			return nil, fmt.Errorf("duplicate zone replica factor for zone %s", zone)
+		}
+		zoneRFs[zone] = rf
+	}
+	return zoneRFs, nil
+}
+
 // Service gets a placement service from m3cluster client
 func Service(
 	clusterClient clusterclient.Client,
@@ -246,6 +284,9 @@ func ServiceWithAlgo(
 	if opts.ServiceName == M3AggregatorServiceName && opts.M3Agg == nil {
 		return nil, nil, errM3AggServiceOptionsRequired
 	}
+	if opts.zoneReplicaFactorsErr != nil {
+		return nil, nil, opts.zoneReplicaFactorsErr
+	}
 
 	sid := services.NewServiceID().
 		SetName(opts.ServiceName).
@@ -256,6 +297,9 @@ func ServiceWithAlgo(
 		SetValidZone(opts.ServiceZone).
 		SetIsSharded(true).
 		SetDryrun(opts.DryRun)
+	if len(opts.ZoneReplicaFactors) > 0 {
+		pOpts = pOpts.SetZoneReplicaFactors(opts.ZoneReplicaFactors)
+	}
 
 	switch opts.ServiceName {
 	case M3CoordinatorServiceName:
@@ -395,6 +439,31 @@ func RegisterRoutes(r *mux.Router, opts HandlerOptions) {
 	r.HandleFunc(M3DBReplaceURL, replaceFn).Methods(ReplaceHTTPMethod)
 	r.HandleFunc(M3AggReplaceURL, replaceFn).Methods(ReplaceHTTPMethod)
 	r.HandleFunc(M3CoordinatorReplaceURL, replaceFn).Methods(ReplaceHTTPMethod)
+
+	// Rebalance
+	var (
+		rebalanceHandler = NewRebalanceHandler(opts)
+		rebalanceFn      = applyMiddleware(rebalanceHandler.ServeHTTP)
+	)
+	r.HandleFunc(M3DBRebalanceURL, rebalanceFn).Methods(RebalanceHTTPMethod)
+	r.HandleFunc(M3AggRebalanceURL, rebalanceFn).Methods(RebalanceHTTPMethod)
+
+	// Update weights
+	var (
+		updateWeightsHandler = NewUpdateWeightsHandler(opts)
+		updateWeightsFn      = applyMiddleware(updateWeightsHandler.ServeHTTP)
+	)
+	r.HandleFunc(M3DBUpdateWeightsURL, updateWeightsFn).Methods(UpdateWeightsHTTPMethod)
+	r.HandleFunc(M3AggUpdateWeightsURL, updateWeightsFn).Methods(UpdateWeightsHTTPMethod)
+
+	// Simulate
+	var (
+		simulateHandler = NewSimulateHandler(opts)
+		simulateFn      = applyMiddleware(simulateHandler.ServeHTTP)
+	)
+	r.HandleFunc(M3DBSimulateURL, simulateFn).Methods(SimulateHTTPMethod)
+	r.HandleFunc(M3AggSimulateURL, simulateFn).Methods(SimulateHTTPMethod)
+	r.HandleFunc(M3CoordinatorSimulateURL, simulateFn).Methods(SimulateHTTPMethod)
 }
 
 func newPlacementCutoverNanosFn(
@@ -22,6 +22,7 @@ package placement
 
 import (
 	"errors"
+	"net/http"
 	"testing"
 	"time"
 
@@ -118,6 +119,20 @@ func TestPlacementServiceWithClusterHeaders(t *testing.T) {
 	})
 }
 
+func TestNewServiceOptionsZoneReplicaFactors(t *testing.T) {
+	headers := http.Header{}
+	headers.Set(HeaderClusterZoneReplicaFactors, "zone-a=2, zone-b=1")
+	opts := NewServiceOptions(M3DBServiceName, headers, nil)
+	require.NoError(t, opts.zoneReplicaFactorsErr)
+	require.Equal(t, map[string]int{"zone-a": 2, "zone-b": 1}, opts.ZoneReplicaFactors)
+
+	for _, v := range []string{"zone-a", "zone-a=0", "zone-a=x", "=1", "zone-a=1,zone-a=2"} {
+		headers.Set(HeaderClusterZoneReplicaFactors, v)
+		opts = NewServiceOptions(M3DBServiceName, headers, nil)
+		require.Error(t, opts.zoneReplicaFactorsErr, v)
+	}
+}
+
 func TestConvertInstancesProto(t *testing.T) {
 	runForAllAllowedServices(func(serviceName string) {
 		instances, err := ConvertInstancesProto([]*placementpb.Instance{})
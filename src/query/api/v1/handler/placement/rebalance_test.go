@@ -0,0 +1,233 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package placement
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var shardedServices = []string{M3DBServiceName, M3AggregatorServiceName}
+
+func newRebalanceRequest(body string) *http.Request {
+	rb := strings.NewReader(body)
+	return httptest.NewRequest(RebalanceHTTPMethod, M3DBRebalanceURL, rb)
+}
+
+func TestPlacementRebalanceHandler_Force(t *testing.T) {
+	for _, s := range shardedServices {
+		t.Run(s, func(t *testing.T) {
+			testPlacementRebalanceHandlerForce(t, s)
+		})
+	}
+}
+
+func TestPlacementRebalanceHandler_Safe_Err(t *testing.T) {
+	for _, s := range shardedServices {
+		t.Run(s, func(t *testing.T) {
+			testPlacementRebalanceHandlerSafeErr(t, s)
+		})
+	}
+}
+
+func TestPlacementRebalanceHandler_Safe_Ok(t *testing.T) {
+	for _, s := range shardedServices {
+		t.Run(s, func(t *testing.T) {
+			testPlacementRebalanceHandlerSafeOk(t, s)
+		})
+	}
+}
+
+func testPlacementRebalanceHandlerForce(t *testing.T, serviceName string) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		mockClient, mockPlacementService = SetupPlacementTest(t, ctrl)
+		handlerOpts                      = NewHandlerOptions(mockClient, config.Configuration{}, nil)
+		handler                          = NewRebalanceHandler(handlerOpts)
+	)
+	handler.nowFn = func() time.Time { return time.Unix(0, 0) }
+
+	w := httptest.NewRecorder()
+	req := newRebalanceRequest(`{"force": true}`)
+	mockPlacementService.EXPECT().Rebalance().Return(nil, errors.New("test"))
+	handler.ServeHTTP(serviceName, w, req)
+
+	resp := w.Result()
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, `{"error":"test"}`+"\n", string(body))
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	w = httptest.NewRecorder()
+	req = newRebalanceRequest(`{"force": true}`)
+	mockPlacementService.EXPECT().Rebalance().Return(placement.NewPlacement(), nil)
+	handler.ServeHTTP(serviceName, w, req)
+
+	resp = w.Result()
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(t, `{"placement":{"instances":{},"replicaFactor":0,"numShards":0,"isSharded":false,"cutoverTime":"0","isMirrored":false,"maxShardSetId":0},"version":0}`, string(body))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func testPlacementRebalanceHandlerSafeErr(t *testing.T, serviceName string) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		mockClient, mockPlacementService = SetupPlacementTest(t, ctrl)
+		handlerOpts                      = NewHandlerOptions(mockClient, config.Configuration{}, nil)
+		handler                          = NewRebalanceHandler(handlerOpts)
+	)
+	handler.nowFn = func() time.Time { return time.Unix(0, 0) }
+
+	// The request body is optional.
+	w := httptest.NewRecorder()
+	req := newRebalanceRequest("")
+	mockPlacementService.EXPECT().Placement().Return(newInitPlacement(), nil)
+	handler.ServeHTTP(serviceName, w, req)
+
+	resp := w.Result()
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"instances [A,B] do not have all shards available"}`+"\n", string(body))
+
+	w = httptest.NewRecorder()
+	req = newRebalanceRequest("{")
+	handler.ServeHTTP(serviceName, w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
+
+type placementMoveMatcher struct {
+	from, to string
+	numMoved int
+}
+
+func (m placementMoveMatcher) Matches(x interface{}) bool {
+	pl := x.(placement.Placement)
+
+	from, ok := pl.Instance(m.from)
+	if !ok {
+		return false
+	}
+
+	to, ok := pl.Instance(m.to)
+	if !ok {
+		return false
+	}
+
+	initShards := to.Shards().ShardsForState(shard.Initializing)
+	for _, s := range initShards {
+		if s.SourceID() != m.from {
+			return false
+		}
+	}
+	return from.Shards().NumShardsForState(shard.Leaving) == m.numMoved &&
+		len(initShards) == m.numMoved
+}
+
+func (m placementMoveMatcher) String() string {
+	return "matches if the expected number of shards moved between the instances"
+}
+
+func newPlacementMoveMatcher(from, to string, numMoved int) gomock.Matcher {
+	return placementMoveMatcher{from: from, to: to, numMoved: numMoved}
+}
+
+// newShardedAvailPlacement returns a placement with instance A owning the
+// given number of shards and instance B owning the rest of the shards.
+func newShardedAvailPlacement(serviceName string, numShardsOnA int) placement.Placement {
+	var (
+		ids     = []uint32{0, 1, 2, 3}
+		shardsA []shard.Shard
+		shardsB []shard.Shard
+	)
+	for i, id := range ids {
+		s := shard.NewShard(id).SetState(shard.Available)
+		if i < numShardsOnA {
+			shardsA = append(shardsA, s)
+		} else {
+			shardsB = append(shardsB, s)
+		}
+	}
+
+	instA := placement.NewInstance().
+		SetID("A").
+		SetIsolationGroup("r1").
+		SetZone("z1").
+		SetWeight(1).
+		SetShardSetID(1).
+		SetShards(shard.NewShards(shardsA))
+	instB := placement.NewInstance().
+		SetID("B").
+		SetIsolationGroup("r2").
+		SetZone("z1").
+		SetWeight(1).
+		SetShardSetID(2).
+		SetShards(shard.NewShards(shardsB))
+	return placement.NewPlacement().
+		SetInstances([]placement.Instance{instA, instB}).
+		SetShards(ids).
+		SetReplicaFactor(1).
+		SetIsSharded(true).
+		SetIsMirrored(serviceName == M3AggregatorServiceName).
+		SetMaxShardSetID(2).
+		SetVersion(1)
+}
+
+func testPlacementRebalanceHandlerSafeOk(t *testing.T, serviceName string) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		mockClient, mockPlacementService = SetupPlacementTest(t, ctrl)
+		handlerOpts                      = NewHandlerOptions(mockClient, config.Configuration{}, nil)
+		handler                          = NewRebalanceHandler(handlerOpts)
+	)
+	handler.nowFn = func() time.Time { return time.Unix(0, 0) }
+
+	pl := newShardedAvailPlacement(serviceName, 3)
+	mockPlacementService.EXPECT().Placement().Return(pl, nil)
+	mockPlacementService.EXPECT().
+		CheckAndSet(newPlacementMoveMatcher("A", "B", 1), 1).
+		Return(pl.Clone().SetVersion(2), nil)
+
+	w := httptest.NewRecorder()
+	req := newRebalanceRequest(`{}`)
+	handler.ServeHTTP(serviceName, w, req)
+
+	resp := w.Result()
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `"version":2`)
+}
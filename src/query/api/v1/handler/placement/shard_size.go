@@ -0,0 +1,81 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package placement
+
+import (
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/client"
+	"github.com/m3db/m3/src/dbnode/storage/bootstrap/result"
+	"github.com/m3db/m3/src/dbnode/topology"
+	"github.com/m3db/m3x/ident"
+)
+
+// ShardSizeFetcher fetches the size of M3DB shards as reported by the nodes.
+type ShardSizeFetcher interface {
+	// FetchShardSize returns the bytes of the blocks of a shard of a
+	// namespace that start between start and end.
+	FetchShardSize(namespace ident.ID, shard uint32, start, end time.Time) (uint64, error)
+}
+
+type shardSizeFetcher struct {
+	session client.AdminSession
+}
+
+// NewShardSizeFetcher returns a ShardSizeFetcher that sums the sizes of the
+// blocks metadata streamed from the nodes owning a shard.
+func NewShardSizeFetcher(session client.AdminSession) ShardSizeFetcher {
+	return shardSizeFetcher{session: session}
+}
+
+func (f shardSizeFetcher) FetchShardSize(
+	namespace ident.ID,
+	shard uint32,
+	start, end time.Time,
+) (uint64, error) {
+	iter, err := f.session.FetchBlocksMetadataFromPeers(namespace, shard,
+		start, end, topology.ReadConsistencyLevelOne, result.NewOptions())
+	if err != nil {
+		return 0, err
+	}
+
+	// NB: Every replica reports the blocks it holds, so the size of the shard
+	// is the largest total reported by a single node.
+	var (
+		hostSizes = make(map[string]uint64)
+		size      uint64
+	)
+	for iter.Next() {
+		host, metadata := iter.Current()
+		if metadata.Size <= 0 {
+			continue
+		}
+		hostSize := hostSizes[host.ID()] + uint64(metadata.Size)
+		hostSizes[host.ID()] = hostSize
+		if hostSize > size {
+			size = hostSize
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
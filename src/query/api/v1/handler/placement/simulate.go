@@ -0,0 +1,469 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package placement
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/generated/proto/placementpb"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	nshandler "github.com/m3db/m3/src/query/api/v1/handler/namespace"
+	"github.com/m3db/m3/src/query/util/logging"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"go.uber.org/zap"
+)
+
+const (
+	// SimulateHTTPMethod is the HTTP method for the simulate endpoint.
+	SimulateHTTPMethod = http.MethodPost
+
+	simulatePathName = "simulate"
+
+	// defaultSampledShards is the default number of moved shards whose size
+	// is fetched from the nodes to estimate the bytes per shard.
+	defaultSampledShards = 4
+
+	// SimulateAddOperation simulates adding instances.
+	SimulateAddOperation = "add"
+	// SimulateRemoveOperation simulates removing instances.
+	SimulateRemoveOperation = "remove"
+	// SimulateReplaceOperation simulates replacing instances.
+	SimulateReplaceOperation = "replace"
+	// SimulateRebalanceOperation simulates rebalancing the placement.
+	SimulateRebalanceOperation = "rebalance"
+	// SimulateWeightsOperation simulates updating instance weights.
+	SimulateWeightsOperation = "weights"
+)
+
+var (
+	// M3DBSimulateURL is the url for the m3db simulate handler (method POST).
+	M3DBSimulateURL = path.Join(handler.RoutePrefixV1, M3DBServicePlacementPathName, simulatePathName)
+
+	// M3AggSimulateURL is the url for the m3aggregator simulate handler
+	// (method POST).
+	M3AggSimulateURL = path.Join(handler.RoutePrefixV1, M3AggServicePlacementPathName, simulatePathName)
+
+	// M3CoordinatorSimulateURL is the url for the m3coordinator simulate
+	// handler (method POST).
+	M3CoordinatorSimulateURL = path.Join(handler.RoutePrefixV1, M3CoordinatorServicePlacementPathName, simulatePathName)
+
+	errNoInstancesToAdd          = errors.New("no instances specified")
+	errNoLeavingInstanceIDs      = errors.New("no leaving instance ids specified")
+	errInvalidSampledShards      = errors.New("sampled shards must not be negative")
+	errInvalidSimulatedOperation = fmt.Errorf(
+		"invalid operation, must be one of: %s, %s, %s, %s, %s",
+		SimulateAddOperation, SimulateRemoveOperation, SimulateReplaceOperation,
+		SimulateRebalanceOperation, SimulateWeightsOperation)
+)
+
+// SimulateRequest is the request to simulate a placement change.
+type SimulateRequest struct {
+	// Operation is the placement change to simulate.
+	Operation string `json:"operation"`
+
+	// Instances are the instances to add, or the replacement candidates.
+	Instances []json.RawMessage `json:"instances"`
+
+	// LeavingInstanceIDs are the instances to remove or replace.
+	LeavingInstanceIDs []string `json:"leavingInstanceIDs"`
+
+	// Weights maps instance IDs to their new weights.
+	Weights map[string]uint32 `json:"weights"`
+
+	// SampledShards is the number of moved M3DB shards whose size is fetched
+	// from the nodes to estimate the bytes each instance needs to stream for
+	// the shards it takes, defaults to defaultSampledShards.
+	SampledShards int `json:"sampledShards"`
+}
+
+// SimulateResponse is the result of a simulated placement change.
+type SimulateResponse struct {
+	// AllAvailable is false when the current placement has shards that are
+	// not available, in which case the change would only apply when forced.
+	AllAvailable    bool                    `json:"allAvailable"`
+	ShardsMoved     int                     `json:"shardsMoved"`
+	ByteEstimate    *ByteEstimate           `json:"byteEstimate,omitempty"`
+	Instances       []InstanceDiff          `json:"instances"`
+	IsolationGroups []IsolationGroupBalance `json:"isolationGroups"`
+}
+
+// ByteEstimate describes the estimate of the bytes streamed by a placement
+// change. BytesPerShard is the average size of the sampled shards reported
+// by the nodes, summed across the blocks within the retention period of
+// every namespace.
+type ByteEstimate struct {
+	BytesPerShard uint64 `json:"bytesPerShard"`
+	TotalBytes    uint64 `json:"totalBytes"`
+
+	// SampledShards are the moved shards whose size was fetched.
+	SampledShards []uint32 `json:"sampledShards"`
+
+	// Namespaces are the namespaces included in the estimate.
+	Namespaces []string `json:"namespaces"`
+}
+
+// InstanceDiff describes how a placement change affects an instance.
+type InstanceDiff struct {
+	ID             string   `json:"id"`
+	IsolationGroup string   `json:"isolationGroup"`
+	WeightBefore   uint32   `json:"weightBefore"`
+	WeightAfter    uint32   `json:"weightAfter"`
+	ShardsBefore   int      `json:"shardsBefore"`
+	ShardsAfter    int      `json:"shardsAfter"`
+	ShardsAdded    []uint32 `json:"shardsAdded"`
+	ShardsRemoved  []uint32 `json:"shardsRemoved"`
+
+	// EstimatedBytes is the estimated bytes the instance streams for the
+	// shards it takes, see ByteEstimate for how it is derived.
+	EstimatedBytes uint64 `json:"estimatedBytes,omitempty"`
+}
+
+// IsolationGroupBalance describes the load of an isolation group before
+// and after a placement change.
+type IsolationGroupBalance struct {
+	IsolationGroup string `json:"isolationGroup"`
+	WeightBefore   uint32 `json:"weightBefore"`
+	WeightAfter    uint32 `json:"weightAfter"`
+	ShardsBefore   int    `json:"shardsBefore"`
+	ShardsAfter    int    `json:"shardsAfter"`
+}
+
+// SimulateHandler is the handler for simulating placement changes.
+type SimulateHandler Handler
+
+// NewSimulateHandler returns a new SimulateHandler.
+func NewSimulateHandler(opts HandlerOptions) *SimulateHandler {
+	return &SimulateHandler{HandlerOptions: opts, nowFn: time.Now}
+}
+
+func (h *SimulateHandler) ServeHTTP(serviceName string, w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx)
+
+	req, pErr := h.parseRequest(r)
+	if pErr != nil {
+		xhttp.Error(w, pErr.Inner(), pErr.Code())
+		return
+	}
+
+	resp, err := h.Simulate(serviceName, r, req)
+	if err != nil {
+		logger.Error("unable to simulate placement change", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, resp, logger)
+}
+
+func (h *SimulateHandler) parseRequest(r *http.Request) (*SimulateRequest, *xhttp.ParseError) {
+	defer r.Body.Close()
+
+	req := &SimulateRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+
+	var err error
+	switch req.Operation {
+	case SimulateAddOperation:
+		if len(req.Instances) == 0 {
+			err = errNoInstancesToAdd
+		}
+	case SimulateRemoveOperation, SimulateReplaceOperation:
+		if len(req.LeavingInstanceIDs) == 0 {
+			err = errNoLeavingInstanceIDs
+		}
+	case SimulateRebalanceOperation:
+	case SimulateWeightsOperation:
+		err = validateInstanceWeights(req.Weights)
+	default:
+		err = errInvalidSimulatedOperation
+	}
+	if err == nil && req.SampledShards < 0 {
+		err = errInvalidSampledShards
+	}
+	if err != nil {
+		return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+
+	return req, nil
+}
+
+// Simulate runs the requested placement change in dry run mode and returns
+// the difference between the current and the resulting placement.
+func (h *SimulateHandler) Simulate(
+	serviceName string,
+	httpReq *http.Request,
+	req *SimulateRequest,
+) (*SimulateResponse, error) {
+	instances, err := convertInstancesJSON(req.Instances)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceOpts := NewServiceOptions(serviceName, httpReq.Header, h.M3AggServiceOptions)
+	serviceOpts.DryRun = true
+	service, _, err := ServiceWithAlgo(h.ClusterClient, serviceOpts, h.nowFn(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	curPlacement, err := service.Placement()
+	if err != nil {
+		return nil, err
+	}
+
+	var newPlacement placement.Placement
+	switch req.Operation {
+	case SimulateAddOperation:
+		newPlacement, _, err = service.AddInstances(instances)
+	case SimulateRemoveOperation:
+		newPlacement, err = service.RemoveInstances(req.LeavingInstanceIDs)
+	case SimulateReplaceOperation:
+		newPlacement, _, err = service.ReplaceInstances(req.LeavingInstanceIDs, instances)
+	case SimulateRebalanceOperation:
+		newPlacement, err = service.Rebalance()
+	case SimulateWeightsOperation:
+		newPlacement, err = service.UpdateInstanceWeights(req.Weights)
+	default:
+		err = errInvalidSimulatedOperation
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := diffPlacements(curPlacement, newPlacement)
+	resp.AllAvailable = validateAllAvailable(curPlacement) == nil
+	if serviceName == M3DBServiceName && h.ShardSizeFetcher != nil && resp.ShardsMoved > 0 {
+		sampledShards := req.SampledShards
+		if sampledShards == 0 {
+			sampledShards = defaultSampledShards
+		}
+		estimate, err := h.estimateBytesPerShard(sampleShards(movedShards(resp), sampledShards))
+		if err != nil {
+			return nil, err
+		}
+		applyByteEstimate(resp, estimate)
+	}
+	return resp, nil
+}
+
+// estimateBytesPerShard estimates the bytes of a single shard across all
+// namespaces from the size of the sampled shards reported by the nodes for
+// the blocks within the namespace retention.
+func (h *SimulateHandler) estimateBytesPerShard(
+	sampledShards []uint32,
+) (*ByteEstimate, error) {
+	store, err := h.ClusterClient.KV()
+	if err != nil {
+		return nil, err
+	}
+
+	nsMetadatas, _, err := nshandler.Metadata(store)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		estimate = &ByteEstimate{
+			SampledShards: sampledShards,
+			Namespaces:    make([]string, 0, len(nsMetadatas)),
+		}
+		now        = h.nowFn()
+		totalBytes uint64
+	)
+	for _, md := range nsMetadatas {
+		var (
+			retentionOpts = md.Options().RetentionOptions()
+			start         = now.Add(-retentionOpts.RetentionPeriod()).Truncate(retentionOpts.BlockSize())
+		)
+		for _, shardID := range sampledShards {
+			size, err := h.ShardSizeFetcher.FetchShardSize(md.ID(), shardID, start, now)
+			if err != nil {
+				return nil, fmt.Errorf("unable to fetch size of shard %d of namespace %s: %v",
+					shardID, md.ID().String(), err)
+			}
+			totalBytes += size
+		}
+		estimate.Namespaces = append(estimate.Namespaces, md.ID().String())
+	}
+	if len(sampledShards) > 0 {
+		estimate.BytesPerShard = totalBytes / uint64(len(sampledShards))
+	}
+	sort.Strings(estimate.Namespaces)
+	return estimate, nil
+}
+
+// movedShards returns the sorted shards added to any instance.
+func movedShards(resp *SimulateResponse) []uint32 {
+	moved := make(map[uint32]struct{}, resp.ShardsMoved)
+	for _, diff := range resp.Instances {
+		for _, id := range diff.ShardsAdded {
+			moved[id] = struct{}{}
+		}
+	}
+	return shardsDifference(moved, nil)
+}
+
+// sampleShards returns at most n shards spread evenly across the sorted
+// shards.
+func sampleShards(shards []uint32, n int) []uint32 {
+	if len(shards) <= n {
+		return shards
+	}
+	sampled := make([]uint32, 0, n)
+	for i := 0; i < n; i++ {
+		sampled = append(sampled, shards[i*len(shards)/n])
+	}
+	return sampled
+}
+
+// applyByteEstimate sets the estimated bytes each instance streams for the
+// shards it takes.
+func applyByteEstimate(resp *SimulateResponse, estimate *ByteEstimate) {
+	for i := range resp.Instances {
+		diff := &resp.Instances[i]
+		diff.EstimatedBytes = uint64(len(diff.ShardsAdded)) * estimate.BytesPerShard
+		estimate.TotalBytes += diff.EstimatedBytes
+	}
+	resp.ByteEstimate = estimate
+}
+
+func convertInstancesJSON(raw []json.RawMessage) ([]placement.Instance, error) {
+	instancesProto := make([]*placementpb.Instance, 0, len(raw))
+	for _, b := range raw {
+		instanceProto := &placementpb.Instance{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(b), instanceProto); err != nil {
+			return nil, err
+		}
+		instancesProto = append(instancesProto, instanceProto)
+	}
+	return ConvertInstancesProto(instancesProto)
+}
+
+// diffPlacements compares the shards owned by each instance and isolation
+// group before and after a placement change, shards in the Leaving state
+// are not considered owned.
+func diffPlacements(
+	before placement.Placement,
+	after placement.Placement,
+) *SimulateResponse {
+	var (
+		resp          = &SimulateResponse{}
+		instanceIDs   = make(map[string]struct{})
+		groupBalances = make(map[string]*IsolationGroupBalance)
+	)
+	for _, instance := range before.Instances() {
+		instanceIDs[instance.ID()] = struct{}{}
+	}
+	for _, instance := range after.Instances() {
+		instanceIDs[instance.ID()] = struct{}{}
+	}
+
+	groupBalance := func(group string) *IsolationGroupBalance {
+		b, ok := groupBalances[group]
+		if !ok {
+			b = &IsolationGroupBalance{IsolationGroup: group}
+			groupBalances[group] = b
+		}
+		return b
+	}
+
+	for id := range instanceIDs {
+		diff := InstanceDiff{ID: id}
+		beforeShards := make(map[uint32]struct{})
+		if instance, ok := before.Instance(id); ok {
+			diff.IsolationGroup = instance.IsolationGroup()
+			beforeShards = ownedShards(instance)
+			diff.ShardsBefore = len(beforeShards)
+			if !instance.IsLeaving() {
+				diff.WeightBefore = instance.Weight()
+			}
+			b := groupBalance(instance.IsolationGroup())
+			b.WeightBefore += diff.WeightBefore
+			b.ShardsBefore += diff.ShardsBefore
+		}
+		afterShards := make(map[uint32]struct{})
+		if instance, ok := after.Instance(id); ok {
+			diff.IsolationGroup = instance.IsolationGroup()
+			afterShards = ownedShards(instance)
+			diff.ShardsAfter = len(afterShards)
+			if !instance.IsLeaving() {
+				diff.WeightAfter = instance.Weight()
+			}
+			b := groupBalance(instance.IsolationGroup())
+			b.WeightAfter += diff.WeightAfter
+			b.ShardsAfter += diff.ShardsAfter
+		}
+
+		diff.ShardsAdded = shardsDifference(afterShards, beforeShards)
+		diff.ShardsRemoved = shardsDifference(beforeShards, afterShards)
+		resp.ShardsMoved += len(diff.ShardsAdded)
+		resp.Instances = append(resp.Instances, diff)
+	}
+	sort.Slice(resp.Instances, func(i, j int) bool {
+		return resp.Instances[i].ID < resp.Instances[j].ID
+	})
+
+	for _, b := range groupBalances {
+		resp.IsolationGroups = append(resp.IsolationGroups, *b)
+	}
+	sort.Slice(resp.IsolationGroups, func(i, j int) bool {
+		return resp.IsolationGroups[i].IsolationGroup < resp.IsolationGroups[j].IsolationGroup
+	})
+
+	return resp
+}
+
+func ownedShards(instance placement.Instance) map[uint32]struct{} {
+	shards := make(map[uint32]struct{}, instance.Shards().NumShards())
+	for _, s := range instance.Shards().All() {
+		if s.State() == shard.Leaving {
+			continue
+		}
+		shards[s.ID()] = struct{}{}
+	}
+	return shards
+}
+
+// shardsDifference returns the sorted shards in a but not in b.
+func shardsDifference(a, b map[uint32]struct{}) []uint32 {
+	res := make([]uint32, 0, len(a))
+	for id := range a {
+		if _, ok := b[id]; !ok {
+			res = append(res, id)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
@@ -0,0 +1,306 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package placement
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cluster/placement/service"
+	"github.com/m3db/m3/src/cluster/placement/storage"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cluster/shard"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	nsproto "github.com/m3db/m3/src/dbnode/generated/proto/namespace"
+	nshandler "github.com/m3db/m3/src/query/api/v1/handler/namespace"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3x/ident"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSimulateRequest(body string) *http.Request {
+	rb := strings.NewReader(body)
+	return httptest.NewRequest(SimulateHTTPMethod, M3DBSimulateURL, rb)
+}
+
+// setupSimulateTest returns a client whose placement services share the
+// given kv store, so placements persisted by the test are visible to the
+// dry run placement services created by the handler.
+func setupSimulateTest(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	store kv.Store,
+) *client.MockClient {
+	logging.InitWithCores(nil)
+
+	mockClient := client.NewMockClient(ctrl)
+	mockServices := services.NewMockServices(ctrl)
+	mockClient.EXPECT().Services(gomock.Any()).Return(mockServices, nil).AnyTimes()
+	mockClient.EXPECT().KV().Return(store, nil).AnyTimes()
+	mockServices.EXPECT().PlacementService(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ interface{}, opts placement.Options) (placement.Service, error) {
+			return service.NewPlacementService(storage.NewPlacementStorage(store, "", opts), opts), nil
+		},
+	).AnyTimes()
+
+	return mockClient
+}
+
+type testShardSizeFetcher struct {
+	t     *testing.T
+	now   time.Time
+	sizes map[uint32]uint64
+}
+
+func (f testShardSizeFetcher) FetchShardSize(
+	namespace ident.ID,
+	shard uint32,
+	start, end time.Time,
+) (uint64, error) {
+	assert.Equal(f.t, "test", namespace.String())
+	assert.Equal(f.t, f.now.Add(-48*time.Hour).Truncate(2*time.Hour), start)
+	assert.Equal(f.t, f.now, end)
+	return f.sizes[shard], nil
+}
+
+func newSimulatePlacement() placement.Placement {
+	instA := placement.NewInstance().
+		SetID("A").
+		SetIsolationGroup("r1").
+		SetZone(DefaultServiceZone).
+		SetWeight(1).
+		SetEndpoint("a:9000").
+		SetShards(shard.NewShards([]shard.Shard{
+			shard.NewShard(0).SetState(shard.Available),
+			shard.NewShard(1).SetState(shard.Available),
+		}))
+	instB := placement.NewInstance().
+		SetID("B").
+		SetIsolationGroup("r2").
+		SetZone(DefaultServiceZone).
+		SetWeight(1).
+		SetEndpoint("b:9000").
+		SetShards(shard.NewShards([]shard.Shard{
+			shard.NewShard(2).SetState(shard.Available),
+			shard.NewShard(3).SetState(shard.Available),
+		}))
+	return placement.NewPlacement().
+		SetInstances([]placement.Instance{instA, instB}).
+		SetShards([]uint32{0, 1, 2, 3}).
+		SetReplicaFactor(1).
+		SetIsSharded(true)
+}
+
+func TestPlacementSimulateHandlerAdd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mem.NewStore()
+	ps := service.NewPlacementService(storage.NewPlacementStorage(store, "", nil), nil)
+	_, err := ps.Set(newSimulatePlacement())
+	require.NoError(t, err)
+
+	registry := nsproto.Registry{
+		Namespaces: map[string]*nsproto.NamespaceOptions{
+			"test": &nsproto.NamespaceOptions{
+				BootstrapEnabled: true,
+				RetentionOptions: &nsproto.RetentionOptions{
+					RetentionPeriodNanos:                     int64(48 * time.Hour),
+					BlockSizeNanos:                           int64(2 * time.Hour),
+					BufferFutureNanos:                        int64(10 * time.Minute),
+					BufferPastNanos:                          int64(10 * time.Minute),
+					BlockDataExpiry:                          true,
+					BlockDataExpiryAfterNotAccessPeriodNanos: int64(time.Hour),
+				},
+			},
+		},
+	}
+	_, err = store.Set(nshandler.M3DBNodeNamespacesKey, &registry)
+	require.NoError(t, err)
+
+	var (
+		now         = time.Unix(1000000, 0)
+		mockClient  = setupSimulateTest(t, ctrl, store)
+		handlerOpts = NewHandlerOptions(mockClient, config.Configuration{}, nil)
+	)
+	handlerOpts.ShardSizeFetcher = testShardSizeFetcher{
+		t:     t,
+		now:   now,
+		sizes: map[uint32]uint64{0: 2400, 1: 2400, 2: 2400, 3: 2400},
+	}
+	handler := NewSimulateHandler(handlerOpts)
+	handler.nowFn = func() time.Time { return now }
+
+	w := httptest.NewRecorder()
+	req := newSimulateRequest(`{
+		"operation": "add",
+		"instances": [
+			{"id": "C", "isolationGroup": "r3", "zone": "embedded", "weight": 1, "endpoint": "c:9000"}
+		]
+	}`)
+	handler.ServeHTTP(M3DBServiceName, w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp SimulateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.AllAvailable)
+	assert.Equal(t, 1, resp.ShardsMoved)
+	require.Equal(t, 3, len(resp.Instances))
+	assert.Equal(t, &ByteEstimate{
+		BytesPerShard: 2400,
+		TotalBytes:    2400,
+		SampledShards: resp.Instances[2].ShardsAdded,
+		Namespaces:    []string{"test"},
+	}, resp.ByteEstimate)
+	assert.Equal(t, "A", resp.Instances[0].ID)
+	assert.Equal(t, "B", resp.Instances[1].ID)
+	assert.Equal(t, 1, len(resp.Instances[0].ShardsRemoved)+len(resp.Instances[1].ShardsRemoved))
+
+	diffC := resp.Instances[2]
+	assert.Equal(t, "C", diffC.ID)
+	assert.Equal(t, "r3", diffC.IsolationGroup)
+	assert.Equal(t, 0, diffC.ShardsBefore)
+	assert.Equal(t, 1, diffC.ShardsAfter)
+	assert.Equal(t, 1, len(diffC.ShardsAdded))
+	assert.Empty(t, diffC.ShardsRemoved)
+	assert.Equal(t, uint64(2400), diffC.EstimatedBytes)
+
+	require.Equal(t, 3, len(resp.IsolationGroups))
+	assert.Equal(t, IsolationGroupBalance{
+		IsolationGroup: "r3",
+		WeightBefore:   0,
+		WeightAfter:    1,
+		ShardsBefore:   0,
+		ShardsAfter:    1,
+	}, resp.IsolationGroups[2])
+
+	// The simulated change is not persisted.
+	p, err := ps.Placement()
+	require.NoError(t, err)
+	assert.Equal(t, 2, p.NumInstances())
+}
+
+func TestPlacementSimulateHandlerBadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		mockClient  = setupSimulateTest(t, ctrl, mem.NewStore())
+		handlerOpts = NewHandlerOptions(mockClient, config.Configuration{}, nil)
+		handler     = NewSimulateHandler(handlerOpts)
+	)
+
+	for _, body := range []string{
+		`{`,
+		`{"operation": "foo"}`,
+		`{"operation": "add"}`,
+		`{"operation": "remove"}`,
+		`{"operation": "replace", "instances": [{"id": "C"}]}`,
+		`{"operation": "weights"}`,
+		`{"operation": "weights", "weights": {"A": 0}}`,
+		`{"operation": "rebalance", "sampledShards": -1}`,
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(M3DBServiceName, w, newSimulateRequest(body))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestDiffPlacements(t *testing.T) {
+	before := newSimulatePlacement()
+
+	instA := placement.NewInstance().
+		SetID("A").
+		SetIsolationGroup("r1").
+		SetWeight(1).
+		SetShards(shard.NewShards([]shard.Shard{
+			shard.NewShard(0).SetState(shard.Available),
+			shard.NewShard(1).SetState(shard.Leaving),
+		}))
+	instB := placement.NewInstance().
+		SetID("B").
+		SetIsolationGroup("r2").
+		SetWeight(3).
+		SetShards(shard.NewShards([]shard.Shard{
+			shard.NewShard(1).SetState(shard.Initializing).SetSourceID("A"),
+			shard.NewShard(2).SetState(shard.Available),
+			shard.NewShard(3).SetState(shard.Available),
+		}))
+	after := placement.NewPlacement().
+		SetInstances([]placement.Instance{instA, instB}).
+		SetShards([]uint32{0, 1, 2, 3}).
+		SetReplicaFactor(1).
+		SetIsSharded(true)
+
+	resp := diffPlacements(before, after)
+	assert.Nil(t, resp.ByteEstimate)
+	assert.Equal(t, 1, resp.ShardsMoved)
+	assert.Equal(t, []uint32{1}, movedShards(resp))
+
+	applyByteEstimate(resp, &ByteEstimate{BytesPerShard: 10})
+	assert.Equal(t, uint64(10), resp.ByteEstimate.TotalBytes)
+	assert.Equal(t, []InstanceDiff{
+		{
+			ID:             "A",
+			IsolationGroup: "r1",
+			WeightBefore:   1,
+			WeightAfter:    1,
+			ShardsBefore:   2,
+			ShardsAfter:    1,
+			ShardsAdded:    []uint32{},
+			ShardsRemoved:  []uint32{1},
+		},
+		{
+			ID:             "B",
+			IsolationGroup: "r2",
+			WeightBefore:   1,
+			WeightAfter:    3,
+			ShardsBefore:   2,
+			ShardsAfter:    3,
+			ShardsAdded:    []uint32{1},
+			ShardsRemoved:  []uint32{},
+			EstimatedBytes: 10,
+		},
+	}, resp.Instances)
+	assert.Equal(t, []IsolationGroupBalance{
+		{IsolationGroup: "r1", WeightBefore: 1, WeightAfter: 1, ShardsBefore: 2, ShardsAfter: 1},
+		{IsolationGroup: "r2", WeightBefore: 1, WeightAfter: 3, ShardsBefore: 2, ShardsAfter: 3},
+	}, resp.IsolationGroups)
+}
+
+func TestSampleShards(t *testing.T) {
+	shards := []uint32{1, 2, 3, 4, 5, 6, 7, 8}
+	assert.Equal(t, shards, sampleShards(shards, 8))
+	assert.Equal(t, shards, sampleShards(shards, 10))
+	assert.Equal(t, []uint32{1, 3, 5, 7}, sampleShards(shards, 4))
+	assert.Equal(t, []uint32{1, 3, 6}, sampleShards(shards, 3))
+}
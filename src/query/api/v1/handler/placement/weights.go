@@ -0,0 +1,205 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package placement
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/generated/proto/admin"
+	"github.com/m3db/m3/src/query/util/logging"
+	xhttp "github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// UpdateWeightsHTTPMethod is the HTTP method for the update weights endpoint.
+	UpdateWeightsHTTPMethod = http.MethodPost
+
+	weightsPathName = "weights"
+)
+
+var (
+	// M3DBUpdateWeightsURL is the url for the m3db update weights handler
+	// (method POST).
+	M3DBUpdateWeightsURL = path.Join(handler.RoutePrefixV1, M3DBServicePlacementPathName, weightsPathName)
+
+	// M3AggUpdateWeightsURL is the url for the m3aggregator update weights
+	// handler (method POST).
+	M3AggUpdateWeightsURL = path.Join(handler.RoutePrefixV1, M3AggServicePlacementPathName, weightsPathName)
+
+	errNoInstanceWeights = errors.New("no instance weights specified")
+)
+
+// UpdateWeightsRequest is the request to update the weights of instances
+// in a placement.
+type UpdateWeightsRequest struct {
+	// Weights maps instance IDs to their new weights.
+	Weights map[string]uint32 `json:"weights"`
+
+	// Force updates the weights even if not all shards are available.
+	Force bool `json:"force"`
+}
+
+// UpdateWeightsHandler is the type for placement weight updates.
+type UpdateWeightsHandler Handler
+
+// NewUpdateWeightsHandler returns a new UpdateWeightsHandler.
+func NewUpdateWeightsHandler(opts HandlerOptions) *UpdateWeightsHandler {
+	return &UpdateWeightsHandler{HandlerOptions: opts, nowFn: time.Now}
+}
+
+func (h *UpdateWeightsHandler) ServeHTTP(serviceName string, w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.WithContext(ctx)
+
+	req, pErr := h.parseRequest(r)
+	if pErr != nil {
+		xhttp.Error(w, pErr.Inner(), pErr.Code())
+		return
+	}
+
+	placement, err := h.UpdateWeights(serviceName, r, req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err.(type) {
+		case unsafeAddError, unknownInstancesError:
+			status = http.StatusBadRequest
+		}
+		logger.Error("unable to update instance weights", zap.Error(err))
+		xhttp.Error(w, err, status)
+		return
+	}
+
+	placementProto, err := placement.Proto()
+	if err != nil {
+		logger.Error("unable to get placement protobuf", zap.Error(err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	resp := &admin.PlacementGetResponse{
+		Placement: placementProto,
+		Version:   int32(placement.Version()),
+	}
+
+	xhttp.WriteProtoMsgJSONResponse(w, resp, logger)
+}
+
+func (h *UpdateWeightsHandler) parseRequest(r *http.Request) (*UpdateWeightsRequest, *xhttp.ParseError) {
+	defer r.Body.Close()
+
+	req := &UpdateWeightsRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+	if err := validateInstanceWeights(req.Weights); err != nil {
+		return nil, xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+
+	return req, nil
+}
+
+// UpdateWeights updates the weights of instances and rebalances the placement.
+func (h *UpdateWeightsHandler) UpdateWeights(
+	serviceName string,
+	httpReq *http.Request,
+	req *UpdateWeightsRequest,
+) (placement.Placement, error) {
+	serviceOpts := NewServiceOptions(serviceName, httpReq.Header, h.M3AggServiceOptions)
+	service, algo, err := ServiceWithAlgo(h.ClusterClient, serviceOpts, h.nowFn(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	curPlacement, err := service.Placement()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateInstancesExist(curPlacement, req.Weights); err != nil {
+		return nil, err
+	}
+
+	if req.Force {
+		return service.UpdateInstanceWeights(req.Weights)
+	}
+
+	if err := validateAllAvailable(curPlacement); err != nil {
+		return nil, err
+	}
+
+	// We use the algorithm directly so that we can CheckAndSet on the placement
+	// to make "atomic" forward progress.
+	newPlacement, err := algo.UpdateInstanceWeights(curPlacement, req.Weights)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure the placement we're updating is still the one on which we validated
+	// all shards are available.
+	return service.CheckAndSet(newPlacement, curPlacement.Version())
+}
+
+type unknownInstancesError struct {
+	ids string
+}
+
+func (e unknownInstancesError) Error() string {
+	return fmt.Sprintf("instances [%s] do not exist in the placement", e.ids)
+}
+
+func validateInstanceWeights(weights map[string]uint32) error {
+	if len(weights) == 0 {
+		return errNoInstanceWeights
+	}
+	for id, weight := range weights {
+		if weight == 0 {
+			return fmt.Errorf("invalid weight 0 for instance %s", id)
+		}
+	}
+	return nil
+}
+
+func validateInstancesExist(p placement.Placement, weights map[string]uint32) error {
+	unknownIDs := []string{}
+	for id := range weights {
+		if _, ok := p.Instance(id); !ok {
+			unknownIDs = append(unknownIDs, id)
+		}
+	}
+	if len(unknownIDs) > 0 {
+		sort.Strings(unknownIDs)
+		return unknownInstancesError{
+			ids: strings.Join(unknownIDs, ","),
+		}
+	}
+	return nil
+}
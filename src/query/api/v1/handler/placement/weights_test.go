@@ -0,0 +1,181 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package placement
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/placement"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUpdateWeightsRequest(body string) *http.Request {
+	rb := strings.NewReader(body)
+	return httptest.NewRequest(UpdateWeightsHTTPMethod, M3DBUpdateWeightsURL, rb)
+}
+
+func TestPlacementUpdateWeightsHandler_Force(t *testing.T) {
+	for _, s := range shardedServices {
+		t.Run(s, func(t *testing.T) {
+			testPlacementUpdateWeightsHandlerForce(t, s)
+		})
+	}
+}
+
+func TestPlacementUpdateWeightsHandler_Safe_Err(t *testing.T) {
+	for _, s := range shardedServices {
+		t.Run(s, func(t *testing.T) {
+			testPlacementUpdateWeightsHandlerSafeErr(t, s)
+		})
+	}
+}
+
+func TestPlacementUpdateWeightsHandler_Safe_Ok(t *testing.T) {
+	for _, s := range shardedServices {
+		t.Run(s, func(t *testing.T) {
+			testPlacementUpdateWeightsHandlerSafeOk(t, s)
+		})
+	}
+}
+
+func testPlacementUpdateWeightsHandlerForce(t *testing.T, serviceName string) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		mockClient, mockPlacementService = SetupPlacementTest(t, ctrl)
+		handlerOpts                      = NewHandlerOptions(mockClient, config.Configuration{}, nil)
+		handler                          = NewUpdateWeightsHandler(handlerOpts)
+	)
+	handler.nowFn = func() time.Time { return time.Unix(0, 0) }
+
+	w := httptest.NewRecorder()
+	req := newUpdateWeightsRequest(`{"force": true, "weights": {"A": 2}}`)
+	mockPlacementService.EXPECT().Placement().Return(newInitPlacement(), nil)
+	mockPlacementService.EXPECT().
+		UpdateInstanceWeights(map[string]uint32{"A": 2}).
+		Return(nil, errors.New("test"))
+	handler.ServeHTTP(serviceName, w, req)
+
+	resp := w.Result()
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, `{"error":"test"}`+"\n", string(body))
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	w = httptest.NewRecorder()
+	req = newUpdateWeightsRequest(`{"force": true, "weights": {"A": 2}}`)
+	mockPlacementService.EXPECT().Placement().Return(newInitPlacement(), nil)
+	mockPlacementService.EXPECT().
+		UpdateInstanceWeights(map[string]uint32{"A": 2}).
+		Return(placement.NewPlacement(), nil)
+	handler.ServeHTTP(serviceName, w, req)
+
+	resp = w.Result()
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(t, `{"placement":{"instances":{},"replicaFactor":0,"numShards":0,"isSharded":false,"cutoverTime":"0","isMirrored":false,"maxShardSetId":0},"version":0}`, string(body))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func testPlacementUpdateWeightsHandlerSafeErr(t *testing.T, serviceName string) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		mockClient, mockPlacementService = SetupPlacementTest(t, ctrl)
+		handlerOpts                      = NewHandlerOptions(mockClient, config.Configuration{}, nil)
+		handler                          = NewUpdateWeightsHandler(handlerOpts)
+	)
+	handler.nowFn = func() time.Time { return time.Unix(0, 0) }
+
+	w := httptest.NewRecorder()
+	req := newUpdateWeightsRequest(`{}`)
+	handler.ServeHTTP(serviceName, w, req)
+
+	resp := w.Result()
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"no instance weights specified"}`+"\n", string(body))
+
+	w = httptest.NewRecorder()
+	req = newUpdateWeightsRequest(`{"weights": {"A": 0}}`)
+	handler.ServeHTTP(serviceName, w, req)
+
+	resp = w.Result()
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid weight 0 for instance A"}`+"\n", string(body))
+
+	w = httptest.NewRecorder()
+	req = newUpdateWeightsRequest(`{"force": true, "weights": {"A": 2, "D": 2, "C": 2}}`)
+	mockPlacementService.EXPECT().Placement().Return(newInitPlacement(), nil)
+	handler.ServeHTTP(serviceName, w, req)
+
+	resp = w.Result()
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"instances [C,D] do not exist in the placement"}`+"\n", string(body))
+
+	w = httptest.NewRecorder()
+	req = newUpdateWeightsRequest(`{"weights": {"A": 2}}`)
+	mockPlacementService.EXPECT().Placement().Return(newInitPlacement(), nil)
+	handler.ServeHTTP(serviceName, w, req)
+
+	resp = w.Result()
+	body, _ = ioutil.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"instances [A,B] do not have all shards available"}`+"\n", string(body))
+}
+
+func testPlacementUpdateWeightsHandlerSafeOk(t *testing.T, serviceName string) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		mockClient, mockPlacementService = SetupPlacementTest(t, ctrl)
+		handlerOpts                      = NewHandlerOptions(mockClient, config.Configuration{}, nil)
+		handler                          = NewUpdateWeightsHandler(handlerOpts)
+	)
+	handler.nowFn = func() time.Time { return time.Unix(0, 0) }
+
+	pl := newShardedAvailPlacement(serviceName, 2)
+	mockPlacementService.EXPECT().Placement().Return(pl, nil)
+	mockPlacementService.EXPECT().
+		CheckAndSet(newPlacementMoveMatcher("A", "B", 1), 1).
+		Return(pl.Clone().SetVersion(2), nil)
+
+	w := httptest.NewRecorder()
+	req := newUpdateWeightsRequest(`{"weights": {"B": 3}}`)
+	handler.ServeHTTP(serviceName, w, req)
+
+	resp := w.Result()
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `"version":2`)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/m3db/m3/src/query/block"
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/tenant"
 	"github.com/m3db/m3/src/query/ts"
 	"github.com/m3db/m3/src/query/util/logging"
 	"github.com/m3db/m3/src/x/net/http"
@@ -121,7 +122,7 @@ func (h *PromReadHandler) ServeHTTPWithEngine(w http.ResponseWriter, r *http.Req
 		logger.Info("Request params", zap.Any("params", params))
 	}
 
-	if err := h.validateRequest(&params); err != nil {
+	if err := h.validateRequest(ctx, &params); err != nil {
 		return nil, emptyReqParams, &RespError{Err: err, Code: http.StatusBadRequest}
 	}
 
@@ -134,17 +135,22 @@ func (h *PromReadHandler) ServeHTTPWithEngine(w http.ResponseWriter, r *http.Req
 	return result, params, nil
 }
 
-func (h *PromReadHandler) validateRequest(params *models.RequestParams) error {
+func (h *PromReadHandler) validateRequest(ctx context.Context, params *models.RequestParams) error {
+	limitsCfg := h.limitsCfg
+	if t, ok := tenant.FromContext(ctx); ok && t.Limits != nil {
+		limitsCfg = t.Limits
+	}
+
 	// Impose a rough limit on the number of returned time series. This is intended to prevent things like
 	// querying from the beginning of time with a 1s step size.
 	// Approach taken directly from prom.
 	numSteps := int64(params.End.Sub(params.Start) / params.Step)
-	if h.limitsCfg.MaxComputedDatapoints > 0 && numSteps > h.limitsCfg.MaxComputedDatapoints {
+	if limitsCfg.MaxComputedDatapoints > 0 && numSteps > limitsCfg.MaxComputedDatapoints {
 		return fmt.Errorf(
 			"querying from %v to %v with step size %v would result in too many datapoints "+
 				"(end - start / step > %d). Either decrease the query resolution (?step=XX), decrease the time window, "+
 				"or increase the limit (`limits.maxComputedDatapoints`)",
-			params.Start, params.End, params.Step, h.limitsCfg.MaxComputedDatapoints,
+			params.Start, params.End, params.Step, limitsCfg.MaxComputedDatapoints,
 		)
 	}
 
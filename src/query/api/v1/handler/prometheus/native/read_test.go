@@ -35,6 +35,7 @@ import (
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage/mock"
+	"github.com/m3db/m3/src/query/tenant"
 	"github.com/m3db/m3/src/query/test"
 	"github.com/m3db/m3/src/query/util/logging"
 
@@ -248,7 +249,7 @@ func TestPromReadHandler_validateRequest(t *testing.T) {
 				MaxComputedDatapoints: tc.Max,
 			}
 
-			err := setup.Handler.validateRequest(tc.Params)
+			err := setup.Handler.validateRequest(context.Background(), tc.Params)
 
 			if tc.ErrorExpected {
 				require.Error(t, err)
@@ -258,3 +259,25 @@ func TestPromReadHandler_validateRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestPromReadHandler_validateRequestTenantLimits(t *testing.T) {
+	setup := newTestSetup()
+	setup.Handler.limitsCfg = &config.LimitsConfiguration{
+		MaxComputedDatapoints: 3601,
+	}
+
+	params := &models.RequestParams{
+		Step:  time.Second,
+		Start: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2018, 1, 1, 1, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, setup.Handler.validateRequest(context.Background(), params))
+
+	ctx := tenant.NewContext(context.Background(), &tenant.Tenant{
+		ID: "foo",
+		Limits: &config.LimitsConfiguration{
+			MaxComputedDatapoints: 3599,
+		},
+	})
+	require.Error(t, setup.Handler.validateRequest(ctx, params))
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/m3db/m3/src/query/generated/proto/prompb"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/tenant"
 	"github.com/m3db/m3/src/query/util/logging"
 	"github.com/m3db/m3/src/x/net/http"
 	xerrors "github.com/m3db/m3x/errors"
@@ -130,12 +131,18 @@ func (h *PromWriteHandler) write(ctx context.Context, r *prompb.WriteRequest) er
 		wg            sync.WaitGroup
 		writeUnaggErr error
 		writeAggErr   error
+		downsampler   = h.downsampler
 	)
-	if h.downsampler != nil {
+	if t, ok := tenant.FromContext(ctx); ok {
+		// Writes made for a tenant are downsampled to the aggregated
+		// cluster namespaces of the tenant.
+		downsampler = t.Downsampler
+	}
+	if downsampler != nil {
 		// If writing downsampled aggregations, write them async
 		wg.Add(1)
 		go func() {
-			writeAggErr = h.writeAggregated(ctx, r)
+			writeAggErr = h.writeAggregated(ctx, downsampler, r)
 			wg.Done()
 		}()
 	}
@@ -146,7 +153,7 @@ func (h *PromWriteHandler) write(ctx context.Context, r *prompb.WriteRequest) er
 		writeUnaggErr = h.writeUnaggregated(ctx, r)
 	}
 
-	if h.downsampler != nil {
+	if downsampler != nil {
 		// Wait for downsampling to finish if we wrote datapoints
 		// for aggregations
 		wg.Wait()
@@ -196,9 +203,10 @@ func (h *PromWriteHandler) writeUnaggregated(
 
 func (h *PromWriteHandler) writeAggregated(
 	_ context.Context,
+	downsampler downsample.Downsampler,
 	r *prompb.WriteRequest,
 ) error {
-	metricsAppender, err := h.downsampler.NewMetricsAppender()
+	metricsAppender, err := downsampler.NewMetricsAppender()
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,259 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	merrors "github.com/m3db/m3/src/metrics/errors"
+	"github.com/m3db/m3/src/metrics/generated/proto/rulepb"
+	"github.com/m3db/m3/src/metrics/rules"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/net/http"
+	"github.com/m3db/m3x/clock"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	namespaceVar = "namespace"
+	ruleIDVar    = "ruleID"
+
+	// ruleSetVersionParam is the query parameter of the ruleset version a rule
+	// change is based on. The change is rejected if the ruleset has been updated
+	// since, and applied to the latest ruleset if the parameter is not set.
+	ruleSetVersionParam = "rulesetVersion"
+
+	// HeaderUpdatedBy is the header used to specify who is making a rule change.
+	HeaderUpdatedBy = "Rules-Updated-By"
+
+	defaultUpdatedBy = "m3coordinator"
+)
+
+var (
+	// NamespacesURL is the url for listing and adding rule namespaces.
+	NamespacesURL = handler.RoutePrefixV1 + "/rules/namespaces"
+
+	// RuleSetURL is the url for the ruleset of a namespace.
+	RuleSetURL = fmt.Sprintf("%s/{%s}", NamespacesURL, namespaceVar)
+
+	// RuleSetChangesURL is the url for applying a batch of changes to the ruleset of a namespace.
+	RuleSetChangesURL = RuleSetURL + "/changes"
+
+	// MappingRulesURL is the url for adding mapping rules to a namespace.
+	MappingRulesURL = RuleSetURL + "/mapping-rules"
+
+	// MappingRuleURL is the url for a mapping rule of a namespace.
+	MappingRuleURL = fmt.Sprintf("%s/{%s}", MappingRulesURL, ruleIDVar)
+
+	// RollupRulesURL is the url for adding rollup rules to a namespace.
+	RollupRulesURL = RuleSetURL + "/rollup-rules"
+
+	// RollupRuleURL is the url for a rollup rule of a namespace.
+	RollupRuleURL = fmt.Sprintf("%s/{%s}", RollupRulesURL, ruleIDVar)
+
+	// MatchURL is the url for previewing the result of matching a metric
+	// against the rules of a namespace.
+	MatchURL = RuleSetURL + "/match"
+)
+
+// HandlerOptions is the set of options for the rule handlers.
+type HandlerOptions struct {
+	// Store is the store rules are read from and written to. Rulesets are
+	// validated by the store before they are written.
+	Store rules.Store
+
+	// Validator validates the proposed rulesets metrics are matched against
+	// when previewing rule matches, proposed rulesets are not validated if nil.
+	Validator rules.Validator
+
+	// PropagationDelay is the delay between a rule change and when it takes effect.
+	PropagationDelay time.Duration
+
+	// NameTagKey is the tag key of the metric name used when matching
+	// metrics against rules, defaults to "name".
+	NameTagKey string
+}
+
+// Handler represents a generic handler for rule endpoints.
+type Handler struct {
+	store        rules.Store
+	validator    rules.Validator
+	updateHelper rules.RuleSetUpdateHelper
+	ruleSetOpts  rules.Options
+	nowFn        clock.NowFn
+}
+
+func newHandler(opts HandlerOptions) Handler {
+	return Handler{
+		store:        opts.Store,
+		validator:    opts.Validator,
+		updateHelper: rules.NewRuleSetUpdateHelper(opts.PropagationDelay),
+		ruleSetOpts:  newMatchRuleSetOptions(opts.NameTagKey),
+		nowFn:        time.Now,
+	}
+}
+
+// RegisterRoutes registers the rule management routes.
+func RegisterRoutes(r *mux.Router, opts HandlerOptions) {
+	logged := logging.WithResponseTimeLogging
+
+	// Namespaces.
+	r.HandleFunc(NamespacesURL, logged(NewListNamespacesHandler(opts)).ServeHTTP).Methods(http.MethodGet)
+	r.HandleFunc(NamespacesURL, logged(NewAddNamespaceHandler(opts)).ServeHTTP).Methods(http.MethodPost)
+
+	// Rulesets.
+	r.HandleFunc(RuleSetURL, logged(NewGetRuleSetHandler(opts)).ServeHTTP).Methods(http.MethodGet)
+	r.HandleFunc(RuleSetChangesURL, logged(NewApplyChangesHandler(opts)).ServeHTTP).Methods(http.MethodPost)
+
+	// Mapping rules.
+	r.HandleFunc(MappingRulesURL, logged(NewAddMappingRuleHandler(opts)).ServeHTTP).Methods(http.MethodPost)
+	r.HandleFunc(MappingRuleURL, logged(NewGetMappingRuleHandler(opts)).ServeHTTP).Methods(http.MethodGet)
+	r.HandleFunc(MappingRuleURL, logged(NewUpdateMappingRuleHandler(opts)).ServeHTTP).Methods(http.MethodPut)
+	r.HandleFunc(MappingRuleURL, logged(NewDeleteMappingRuleHandler(opts)).ServeHTTP).Methods(http.MethodDelete)
+
+	// Rollup rules.
+	r.HandleFunc(RollupRulesURL, logged(NewAddRollupRuleHandler(opts)).ServeHTTP).Methods(http.MethodPost)
+	r.HandleFunc(RollupRuleURL, logged(NewGetRollupRuleHandler(opts)).ServeHTTP).Methods(http.MethodGet)
+	r.HandleFunc(RollupRuleURL, logged(NewUpdateRollupRuleHandler(opts)).ServeHTTP).Methods(http.MethodPut)
+	r.HandleFunc(RollupRuleURL, logged(NewDeleteRollupRuleHandler(opts)).ServeHTTP).Methods(http.MethodDelete)
+}
+
+// RegisterMatchRoute registers the route previewing the result of matching a
+// metric against rules. Matching metrics does not mutate the rules, so unlike
+// the rule management routes the route only requires read access.
+func RegisterMatchRoute(r *mux.Router, opts HandlerOptions, wrap func(http.Handler) http.Handler) {
+	r.Handle(MatchURL, wrap(NewMatchHandler(opts))).Methods(http.MethodPost)
+}
+
+// updateRuleSetFn applies an update to a ruleset.
+type updateRuleSetFn func(rs rules.MutableRuleSet, meta rules.UpdateMetadata) error
+
+// readNamespaces reads the rule namespaces, returning an empty set of
+// namespaces if none has been created yet.
+func (h Handler) readNamespaces() (*rules.Namespaces, error) {
+	nss, err := h.store.ReadNamespaces()
+	if _, ok := err.(merrors.NotFoundError); ok {
+		empty, err := rules.NewNamespaces(0, &rulepb.Namespaces{})
+		if err != nil {
+			return nil, err
+		}
+		return &empty, nil
+	}
+	return nss, err
+}
+
+// updateRuleSet reads the ruleset of the namespace, applies the update and
+// writes the ruleset back to the store, returning the updated ruleset as read
+// back from the store. If an expected version is given, the update is rejected
+// unless the ruleset is at the expected version. The write itself is conditioned
+// on the version read so concurrent updates are detected.
+func (h Handler) updateRuleSet(
+	r *http.Request,
+	nsName string,
+	expectedVersion *int,
+	updateFn updateRuleSetFn,
+) (rules.RuleSet, error) {
+	rs, err := h.store.ReadRuleSet(nsName)
+	if err != nil {
+		return nil, err
+	}
+	if expectedVersion != nil && rs.Version() != *expectedVersion {
+		return nil, merrors.NewStaleDataError(fmt.Sprintf(
+			"ruleset version mismatch: current version is %d, expected version is %d",
+			rs.Version(), *expectedVersion,
+		))
+	}
+	mutable := rs.ToMutableRuleSet().Clone()
+	if err := updateFn(mutable, h.updateMetadata(r)); err != nil {
+		return nil, err
+	}
+	if err := h.store.WriteRuleSet(mutable); err != nil {
+		return nil, err
+	}
+	return h.store.ReadRuleSet(nsName)
+}
+
+func (h Handler) updateMetadata(r *http.Request) rules.UpdateMetadata {
+	updatedBy := strings.TrimSpace(r.Header.Get(HeaderUpdatedBy))
+	if updatedBy == "" {
+		updatedBy = defaultUpdatedBy
+	}
+	return h.updateHelper.NewUpdateMetadata(h.nowFn().UnixNano(), updatedBy)
+}
+
+// parseRulePath returns the namespace and the rule ID in the request path.
+func parseRulePath(r *http.Request, errEmptyRuleID error) (string, string, error) {
+	vars := mux.Vars(r)
+	nsName := strings.TrimSpace(vars[namespaceVar])
+	if nsName == "" {
+		return "", "", errEmptyNamespace
+	}
+	ruleID := strings.TrimSpace(vars[ruleIDVar])
+	if ruleID == "" {
+		return "", "", errEmptyRuleID
+	}
+	return nsName, ruleID, nil
+}
+
+// parseRuleSetVersion returns the ruleset version in the request query, or nil
+// if the request does not specify one.
+func parseRuleSetVersion(r *http.Request) (*int, error) {
+	str := strings.TrimSpace(r.URL.Query().Get(ruleSetVersionParam))
+	if str == "" {
+		return nil, nil
+	}
+	version, err := strconv.Atoi(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ruleset version: %s", str)
+	}
+	return &version, nil
+}
+
+func parseRequest(r *http.Request, v interface{}) *xhttp.ParseError {
+	defer r.Body.Close()
+
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return xhttp.NewParseError(err, http.StatusBadRequest)
+	}
+	return nil
+}
+
+// errorStatusCode returns the http status code for an error returned by
+// the rules store or a ruleset update.
+func errorStatusCode(err error) int {
+	switch err.(type) {
+	case merrors.InvalidInputError, merrors.ValidationError:
+		return http.StatusBadRequest
+	case merrors.NotFoundError:
+		return http.StatusNotFound
+	case merrors.StaleDataError:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	merrors "github.com/m3db/m3/src/metrics/errors"
+	"github.com/m3db/m3/src/metrics/rules"
+	"github.com/m3db/m3/src/metrics/rules/view"
+)
+
+var (
+	errEmptyMappingRuleID = errors.New("must specify mapping rule ID")
+
+	mappingRuleType = ruleType{
+		name:           "mapping rule",
+		errEmptyRuleID: errEmptyMappingRuleID,
+		newRule:        func() interface{} { return &view.MappingRule{} },
+		setID:          func(rule interface{}, id string) { rule.(*view.MappingRule).ID = id },
+		latest: func(rs rules.RuleSet, id string) (interface{}, error) {
+			return latestMappingRule(rs, id)
+		},
+		add: func(rs rules.MutableRuleSet, rule interface{}, meta rules.UpdateMetadata) (string, error) {
+			return rs.AddMappingRule(*rule.(*view.MappingRule), meta)
+		},
+		update: func(rs rules.MutableRuleSet, rule interface{}, meta rules.UpdateMetadata) error {
+			return rs.UpdateMappingRule(*rule.(*view.MappingRule), meta)
+		},
+		delete: func(rs rules.MutableRuleSet, id string, meta rules.UpdateMetadata) error {
+			return rs.DeleteMappingRule(id, meta)
+		},
+	}
+)
+
+// latestMappingRule returns the latest snapshot of a mapping rule in a ruleset.
+func latestMappingRule(rs rules.RuleSet, id string) (view.MappingRule, error) {
+	latest, err := rs.Latest()
+	if err != nil {
+		return view.MappingRule{}, err
+	}
+	for _, rule := range latest.MappingRules {
+		if rule.ID == id {
+			return rule, nil
+		}
+	}
+	return view.MappingRule{}, merrors.NewNotFoundError(fmt.Sprintf("mapping rule %s not found", id))
+}
+
+// GetMappingRuleHandler is the handler for getting a mapping rule.
+type GetMappingRuleHandler struct {
+	ruleHandler
+}
+
+// NewGetMappingRuleHandler returns a new instance of GetMappingRuleHandler.
+func NewGetMappingRuleHandler(opts HandlerOptions) *GetMappingRuleHandler {
+	return &GetMappingRuleHandler{ruleHandler: newRuleHandler(opts, mappingRuleType)}
+}
+
+func (h *GetMappingRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serveGet(w, r)
+}
+
+// AddMappingRuleHandler is the handler for adding a mapping rule.
+type AddMappingRuleHandler struct {
+	ruleHandler
+}
+
+// NewAddMappingRuleHandler returns a new instance of AddMappingRuleHandler.
+func NewAddMappingRuleHandler(opts HandlerOptions) *AddMappingRuleHandler {
+	return &AddMappingRuleHandler{ruleHandler: newRuleHandler(opts, mappingRuleType)}
+}
+
+func (h *AddMappingRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serveAdd(w, r)
+}
+
+// UpdateMappingRuleHandler is the handler for updating a mapping rule.
+type UpdateMappingRuleHandler struct {
+	ruleHandler
+}
+
+// NewUpdateMappingRuleHandler returns a new instance of UpdateMappingRuleHandler.
+func NewUpdateMappingRuleHandler(opts HandlerOptions) *UpdateMappingRuleHandler {
+	return &UpdateMappingRuleHandler{ruleHandler: newRuleHandler(opts, mappingRuleType)}
+}
+
+func (h *UpdateMappingRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serveUpdate(w, r)
+}
+
+// DeleteMappingRuleHandler is the handler for deleting a mapping rule.
+type DeleteMappingRuleHandler struct {
+	ruleHandler
+}
+
+// NewDeleteMappingRuleHandler returns a new instance of DeleteMappingRuleHandler.
+func NewDeleteMappingRuleHandler(opts HandlerOptions) *DeleteMappingRuleHandler {
+	return &DeleteMappingRuleHandler{ruleHandler: newRuleHandler(opts, mappingRuleType)}
+}
+
+func (h *DeleteMappingRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serveDelete(w, r)
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/metrics/rules/view"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMappingRuleLifecycle(t *testing.T) {
+	router, _ := testRouter()
+
+	w := serveTestRequest(router, http.MethodPost, NamespacesURL, `{"id": "foo"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Add a mapping rule.
+	mappingRulesURL := testRuleSetURL("foo") + "/mapping-rules"
+	w = serveTestRequest(router, http.MethodPost, mappingRulesURL, `{
+		"name": "servers",
+		"filter": "name:servers.* service:servers",
+		"storagePolicies": ["10s:2d"]
+	}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var added view.MappingRule
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &added))
+	require.NotEmpty(t, added.ID)
+	require.Equal(t, "servers", added.Name)
+	require.Equal(t, "name:servers.* service:servers", added.Filter)
+	require.Equal(t, policy.StoragePolicies{
+		policy.MustParseStoragePolicy("10s:2d"),
+	}, added.StoragePolicies)
+	require.Equal(t, "tester", added.LastUpdatedBy)
+
+	// Get the mapping rule.
+	mappingRuleURL := mappingRulesURL + "/" + added.ID
+	w = serveTestRequest(router, http.MethodGet, mappingRuleURL, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var fetched view.MappingRule
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+	require.Equal(t, added, fetched)
+
+	// Update the mapping rule.
+	w = serveTestRequest(router, http.MethodPut, mappingRuleURL, `{
+		"name": "servers",
+		"filter": "name:servers.* service:servers",
+		"storagePolicies": ["10s:2d", "1m:40d"]
+	}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var updated view.MappingRule
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	require.Equal(t, added.ID, updated.ID)
+	require.Equal(t, policy.StoragePolicies{
+		policy.MustParseStoragePolicy("10s:2d"),
+		policy.MustParseStoragePolicy("1m:40d"),
+	}, updated.StoragePolicies)
+
+	// Delete the mapping rule.
+	w = serveTestRequest(router, http.MethodDelete, mappingRuleURL, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var deleted DeleteRuleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &deleted))
+	require.True(t, deleted.Deleted)
+
+	w = serveTestRequest(router, http.MethodGet, mappingRuleURL, "")
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMappingRuleNotFound(t *testing.T) {
+	router, _ := testRouter()
+
+	w := serveTestRequest(router, http.MethodPost, NamespacesURL, `{"id": "foo"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	mappingRuleURL := testRuleSetURL("foo") + "/mapping-rules/bar"
+	w = serveTestRequest(router, http.MethodGet, mappingRuleURL, "")
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveTestRequest(router, http.MethodPut, mappingRuleURL, `{
+		"name": "servers",
+		"filter": "name:servers.*"
+	}`)
+	require.NotEqual(t, http.StatusOK, w.Code)
+
+	// Rules cannot be added to a namespace that does not exist.
+	w = serveTestRequest(router, http.MethodPost, testRuleSetURL("baz")+"/mapping-rules", `{
+		"name": "servers",
+		"filter": "name:servers.*"
+	}`)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMappingRuleRuleSetVersion(t *testing.T) {
+	router, _ := testRouter()
+
+	w := serveTestRequest(router, http.MethodPost, NamespacesURL, `{"id": "foo"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = serveTestRequest(router, http.MethodGet, testRuleSetURL("foo"), "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var rs view.RuleSet
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rs))
+
+	// Changes based on the current ruleset version are applied.
+	mappingRulesURL := testRuleSetURL("foo") + "/mapping-rules"
+	w = serveTestRequest(router, http.MethodPost,
+		fmt.Sprintf("%s?rulesetVersion=%d", mappingRulesURL, rs.Version), `{
+		"name": "servers",
+		"filter": "name:servers.*",
+		"storagePolicies": ["10s:2d"]
+	}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var added view.MappingRule
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &added))
+
+	// Changes based on a stale ruleset version are rejected.
+	mappingRuleURL := mappingRulesURL + "/" + added.ID
+	w = serveTestRequest(router, http.MethodPut,
+		fmt.Sprintf("%s?rulesetVersion=%d", mappingRuleURL, rs.Version), `{
+		"name": "servers",
+		"filter": "name:servers.*",
+		"storagePolicies": ["1m:40d"]
+	}`)
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+
+	w = serveTestRequest(router, http.MethodDelete,
+		fmt.Sprintf("%s?rulesetVersion=%d", mappingRuleURL, rs.Version), "")
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+
+	// Invalid ruleset versions are rejected.
+	w = serveTestRequest(router, http.MethodDelete, mappingRuleURL+"?rulesetVersion=foo", "")
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = serveTestRequest(router, http.MethodGet, mappingRuleURL, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var fetched view.MappingRule
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+	require.Equal(t, added, fetched)
+}
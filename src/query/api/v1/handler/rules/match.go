@@ -0,0 +1,363 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/m3db/m3/src/metrics/aggregation"
+	merrors "github.com/m3db/m3/src/metrics/errors"
+	"github.com/m3db/m3/src/metrics/filters"
+	"github.com/m3db/m3/src/metrics/metadata"
+	"github.com/m3db/m3/src/metrics/metric/id/m3"
+	"github.com/m3db/m3/src/metrics/pipeline"
+	"github.com/m3db/m3/src/metrics/pipeline/applied"
+	"github.com/m3db/m3/src/metrics/policy"
+	"github.com/m3db/m3/src/metrics/rules"
+	"github.com/m3db/m3/src/metrics/rules/view"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultNameTagKey = "name"
+	m3MetricPrefix    = "m3+"
+	proposedUpdatedBy = "match-preview"
+)
+
+var (
+	errEmptyMetric       = errors.New("must specify either metric id or metric name")
+	errAmbiguousMetric   = errors.New("must specify only one of metric id and metric name")
+	errInvalidMetricTags = errors.New("metric tag names and values must not contain '+', '=' or ','")
+)
+
+// MatchRequest is a request to preview the result of matching a metric
+// against the rules of a namespace.
+type MatchRequest struct {
+	// The m3 formatted id of the metric, e.g. m3+requests+host=foo,service=bar.
+	// Either the metric id or the metric name must be specified.
+	ID string `json:"id"`
+
+	// The name and tags of the metric, used to construct the metric id if the
+	// id is not specified.
+	Name string            `json:"name"`
+	Tags map[string]string `json:"tags"`
+
+	// The time to match the metric at, defaults to now.
+	TimeMillis int64 `json:"timeMillis"`
+
+	// An optional proposed ruleset to match the metric against instead of the
+	// ruleset of the namespace. All proposed rules are in effect immediately.
+	RuleSet *view.RuleSet `json:"ruleset"`
+}
+
+// MatchResponse is the result of matching a metric against a ruleset.
+type MatchResponse struct {
+	ID             string `json:"id"`
+	TimeMillis     int64  `json:"timeMillis"`
+	RuleSetVersion int    `json:"rulesetVersion"`
+	Proposed       bool   `json:"proposed"`
+
+	// The mapping and rollup rules whose filters match the metric.
+	MappingRules []view.MappingRule `json:"mappingRules"`
+	RollupRules  []view.RollupRule  `json:"rollupRules"`
+
+	// Whether the metric is dropped as a result of the drop policies of the
+	// matched mapping rules.
+	Dropped bool `json:"dropped"`
+
+	// The staged metadatas the metric itself is aggregated with.
+	Metadatas []MatchStagedMetadata `json:"metadatas"`
+
+	// The new metrics produced by the rollup targets of the matched rollup rules.
+	Rollups []MatchRollup `json:"rollups"`
+}
+
+// MatchRollup is a new rollup metric produced by matching a metric.
+type MatchRollup struct {
+	ID        string                `json:"id"`
+	Metadatas []MatchStagedMetadata `json:"metadatas"`
+}
+
+// MatchStagedMetadata is a staged metadata produced by matching a metric.
+type MatchStagedMetadata struct {
+	CutoverNanos int64           `json:"cutoverNanos"`
+	Tombstoned   bool            `json:"tombstoned"`
+	Pipelines    []MatchPipeline `json:"pipelines"`
+}
+
+// MatchPipeline is a pipeline a matched metric is aggregated with.
+type MatchPipeline struct {
+	AggregationID   aggregation.ID         `json:"aggregation"`
+	StoragePolicies policy.StoragePolicies `json:"storagePolicies"`
+	DropPolicy      policy.DropPolicy      `json:"dropPolicy,omitempty"`
+	Operations      []MatchPipelineOp      `json:"operations,omitempty"`
+}
+
+// MatchPipelineOp is an operation applied to a matched metric after aggregation.
+type MatchPipelineOp struct {
+	Transformation *pipeline.TransformationOp `json:"transformation,omitempty"`
+	Rollup         *MatchRollupOp             `json:"rollup,omitempty"`
+}
+
+// MatchRollupOp is a rollup operation applied to a matched metric after aggregation.
+type MatchRollupOp struct {
+	ID            string         `json:"id"`
+	AggregationID aggregation.ID `json:"aggregation"`
+}
+
+// MatchHandler is the handler for previewing the result of matching a metric
+// against the rules of a namespace.
+type MatchHandler struct {
+	Handler
+}
+
+// NewMatchHandler returns a new instance of MatchHandler.
+func NewMatchHandler(opts HandlerOptions) *MatchHandler {
+	return &MatchHandler{Handler: newHandler(opts)}
+}
+
+func (h *MatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+	nsName := strings.TrimSpace(mux.Vars(r)[namespaceVar])
+	if nsName == "" {
+		logger.Error("no namespace to match against", zap.Any("error", errEmptyNamespace))
+		xhttp.Error(w, errEmptyNamespace, http.StatusBadRequest)
+		return
+	}
+
+	var req MatchRequest
+	if rErr := parseRequest(r, &req); rErr != nil {
+		logger.Error("unable to parse request", zap.Any("error", rErr))
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+	id, err := matchMetricID(req)
+	if err != nil {
+		logger.Error("invalid metric", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+	timeNanos := h.nowFn().UnixNano()
+	if req.TimeMillis != 0 {
+		timeNanos = req.TimeMillis * int64(time.Millisecond)
+	}
+
+	rs, err := h.matchRuleSet(nsName, req.RuleSet)
+	if err != nil {
+		logger.Error("unable to get ruleset to match against", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+	resp, err := h.match(rs, id, timeNanos)
+	if err != nil {
+		logger.Error("unable to match metric", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+	resp.Proposed = req.RuleSet != nil
+	xhttp.WriteJSONResponse(w, resp, logger)
+}
+
+// matchRuleSet returns the ruleset to match against with its rule filters
+// compiled, either built from the proposed ruleset or read from the store.
+func (h *MatchHandler) matchRuleSet(nsName string, proposed *view.RuleSet) (rules.RuleSet, error) {
+	var (
+		rs  rules.RuleSet
+		err error
+	)
+	if proposed == nil {
+		rs, err = h.store.ReadRuleSet(nsName)
+	} else {
+		rs, err = h.newProposedRuleSet(nsName, *proposed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	proto, err := rs.Proto()
+	if err != nil {
+		return nil, err
+	}
+	return rules.NewRuleSetFromProto(rs.Version(), proto, h.ruleSetOpts)
+}
+
+func (h *MatchHandler) match(rs rules.RuleSet, id []byte, timeNanos int64) (MatchResponse, error) {
+	matched, err := rs.MatchingRulesAt(id, timeNanos)
+	if err != nil {
+		return MatchResponse{}, err
+	}
+
+	// Only match at the given time so the result reflects the rules in effect
+	// at that time.
+	res := rs.ActiveSet(timeNanos).ForwardMatch(id, timeNanos, timeNanos+1)
+	forExistingID := res.ForExistingIDAt(timeNanos)
+	resp := MatchResponse{
+		ID:             string(id),
+		TimeMillis:     timeNanos / int64(time.Millisecond),
+		RuleSetVersion: rs.Version(),
+		MappingRules:   matched.MappingRules,
+		RollupRules:    matched.RollupRules,
+		Dropped:        len(forExistingID) > 0 && forExistingID[len(forExistingID)-1].IsDropPolicyApplied(),
+		Metadatas:      newMatchStagedMetadatas(forExistingID),
+	}
+	for i := 0; i < res.NumNewRollupIDs(); i++ {
+		rollup := res.ForNewRollupIDsAt(i, timeNanos)
+		resp.Rollups = append(resp.Rollups, MatchRollup{
+			ID:        string(rollup.ID),
+			Metadatas: newMatchStagedMetadatas(rollup.Metadatas),
+		})
+	}
+	return resp, nil
+}
+
+// newProposedRuleSet creates a ruleset containing the rules of a proposed
+// ruleset, all of which are in effect immediately. The ruleset is validated
+// the same way rulesets are validated before they are written to the store.
+func (h *MatchHandler) newProposedRuleSet(nsName string, proposed view.RuleSet) (rules.RuleSet, error) {
+	meta := rules.NewRuleSetUpdateHelper(0).NewUpdateMetadata(0, proposedUpdatedBy)
+	rs := rules.NewEmptyRuleSet(nsName, meta)
+	for _, rule := range proposed.MappingRules {
+		if _, err := rs.AddMappingRule(rule, meta); err != nil {
+			return nil, merrors.NewInvalidInputError(err.Error())
+		}
+	}
+	for _, rule := range proposed.RollupRules {
+		if _, err := rs.AddRollupRule(rule, meta); err != nil {
+			return nil, merrors.NewInvalidInputError(err.Error())
+		}
+	}
+	if h.validator != nil {
+		if err := h.validator.Validate(rs); err != nil {
+			return nil, err
+		}
+	}
+	return rs, nil
+}
+
+// matchMetricID returns the m3 formatted id of the metric in the request.
+func matchMetricID(req MatchRequest) ([]byte, error) {
+	var (
+		metricID = strings.TrimSpace(req.ID)
+		name     = strings.TrimSpace(req.Name)
+	)
+	switch {
+	case metricID == "" && name == "":
+		return nil, errEmptyMetric
+	case metricID != "" && name != "":
+		return nil, errAmbiguousMetric
+	case metricID != "":
+		if _, _, err := m3.NameAndTags([]byte(metricID)); err != nil {
+			return nil, fmt.Errorf("invalid metric id %s: %v", metricID, err)
+		}
+		return []byte(metricID), nil
+	}
+
+	tagNames := make([]string, 0, len(req.Tags))
+	for tagName, tagValue := range req.Tags {
+		if strings.ContainsAny(tagName, "+=,") || strings.ContainsAny(tagValue, "+=,") {
+			return nil, errInvalidMetricTags
+		}
+		tagNames = append(tagNames, tagName)
+	}
+	sort.Strings(tagNames)
+
+	var buf bytes.Buffer
+	buf.WriteString(m3MetricPrefix)
+	buf.WriteString(name)
+	buf.WriteByte('+')
+	for i, tagName := range tagNames {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(tagName)
+		buf.WriteByte('=')
+		buf.WriteString(req.Tags[tagName])
+	}
+	return buf.Bytes(), nil
+}
+
+func newMatchStagedMetadatas(metadatas metadata.StagedMetadatas) []MatchStagedMetadata {
+	res := make([]MatchStagedMetadata, 0, len(metadatas))
+	for _, sm := range metadatas {
+		pipelines := make([]MatchPipeline, 0, len(sm.Pipelines))
+		for _, p := range sm.Pipelines {
+			pipelines = append(pipelines, MatchPipeline{
+				AggregationID:   p.AggregationID,
+				StoragePolicies: p.StoragePolicies,
+				DropPolicy:      p.DropPolicy,
+				Operations:      newMatchPipelineOps(p.Pipeline),
+			})
+		}
+		res = append(res, MatchStagedMetadata{
+			CutoverNanos: sm.CutoverNanos,
+			Tombstoned:   sm.Tombstoned,
+			Pipelines:    pipelines,
+		})
+	}
+	return res
+}
+
+func newMatchPipelineOps(p applied.Pipeline) []MatchPipelineOp {
+	ops := make([]MatchPipelineOp, 0, p.Len())
+	for i := 0; i < p.Len(); i++ {
+		op := p.At(i)
+		switch op.Type {
+		case pipeline.TransformationOpType:
+			transformation := op.Transformation
+			ops = append(ops, MatchPipelineOp{Transformation: &transformation})
+		case pipeline.RollupOpType:
+			ops = append(ops, MatchPipelineOp{Rollup: &MatchRollupOp{
+				ID:            string(op.Rollup.ID),
+				AggregationID: op.Rollup.AggregationID,
+			}})
+		}
+	}
+	return ops
+}
+
+// newMatchRuleSetOptions returns the ruleset options used to match m3
+// formatted metric ids against rules.
+func newMatchRuleSetOptions(nameTagKey string) rules.Options {
+	if nameTagKey == "" {
+		nameTagKey = defaultNameTagKey
+	}
+	tagsFilterOpts := filters.TagsFilterOptions{
+		NameTagKey:          []byte(nameTagKey),
+		NameAndTagsFn:       m3.NameAndTags,
+		SortedTagIteratorFn: m3.NewSortedTagIterator,
+	}
+	isRollupIDFn := func(name []byte, tags []byte) bool {
+		return m3.IsRollupID(name, tags, nil)
+	}
+	return rules.NewOptions().
+		SetTagsFilterOptions(tagsFilterOpts).
+		SetNewRollupIDFn(m3.NewRollupID).
+		SetIsRollupIDFn(isRollupIDFn)
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/metrics/policy"
+	rulekv "github.com/m3db/m3/src/metrics/rules/store/kv"
+	"github.com/m3db/m3/src/metrics/rules/validator"
+	"github.com/m3db/m3/src/metrics/rules/validator/namespace/static"
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchStoredRuleSet(t *testing.T) {
+	router, _ := testRouter()
+
+	w := serveTestRequest(router, http.MethodPost, NamespacesURL, `{"id": "foo"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = serveTestRequest(router, http.MethodPost, testRuleSetURL("foo")+"/mapping-rules", `{
+		"name": "servers",
+		"filter": "service:servers",
+		"storagePolicies": ["10s:2d"]
+	}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = serveTestRequest(router, http.MethodPost, testRuleSetURL("foo")+"/rollup-rules", `{
+		"name": "serversByHost",
+		"filter": "service:servers",
+		"targets": [{
+			"pipeline": [{"rollup": {"newName": "requests_by_host", "tags": ["host"], "aggregation": ["Sum"]}}],
+			"storagePolicies": ["1m:40d"]
+		}]
+	}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	matchURL := testRuleSetURL("foo") + "/match"
+	w = serveTestRequest(router, http.MethodPost, matchURL, `{
+		"name": "requests",
+		"tags": {"service": "servers", "host": "h1"}
+	}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp MatchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "m3+requests+host=h1,service=servers", resp.ID)
+	require.False(t, resp.Proposed)
+	require.False(t, resp.Dropped)
+	require.Len(t, resp.MappingRules, 1)
+	require.Equal(t, "servers", resp.MappingRules[0].Name)
+	require.Len(t, resp.RollupRules, 1)
+	require.Equal(t, "serversByHost", resp.RollupRules[0].Name)
+
+	require.Len(t, resp.Metadatas, 1)
+	require.Len(t, resp.Metadatas[0].Pipelines, 1)
+	require.Equal(t, policy.StoragePolicies{
+		policy.MustParseStoragePolicy("10s:2d"),
+	}, resp.Metadatas[0].Pipelines[0].StoragePolicies)
+
+	require.Len(t, resp.Rollups, 1)
+	require.Equal(t, "m3+requests_by_host+host=h1,m3_rollup=true", resp.Rollups[0].ID)
+	require.Len(t, resp.Rollups[0].Metadatas, 1)
+	require.Len(t, resp.Rollups[0].Metadatas[0].Pipelines, 1)
+	require.Equal(t, policy.StoragePolicies{
+		policy.MustParseStoragePolicy("1m:40d"),
+	}, resp.Rollups[0].Metadatas[0].Pipelines[0].StoragePolicies)
+
+	// Metrics not matching any rules are aggregated with the default pipeline.
+	w = serveTestRequest(router, http.MethodPost, matchURL, `{
+		"id": "m3+requests+service=databases"
+	}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	resp = MatchResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Empty(t, resp.MappingRules)
+	require.Empty(t, resp.RollupRules)
+	require.Empty(t, resp.Rollups)
+	require.False(t, resp.Dropped)
+}
+
+func TestMatchProposedRuleSet(t *testing.T) {
+	router, _ := testRouter()
+
+	// The proposed ruleset is used even if the namespace does not exist.
+	w := serveTestRequest(router, http.MethodPost, testRuleSetURL("foo")+"/match", `{
+		"id": "m3+requests+host=h1,service=servers",
+		"timeMillis": 1000,
+		"ruleset": {
+			"mappingRules": [{
+				"name": "dropServers",
+				"filter": "service:servers",
+				"dropPolicy": 1
+			}]
+		}
+	}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp MatchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.True(t, resp.Proposed)
+	require.True(t, resp.Dropped)
+	require.Equal(t, int64(1000), resp.TimeMillis)
+	require.Len(t, resp.MappingRules, 1)
+	require.Equal(t, "dropServers", resp.MappingRules[0].Name)
+	require.Equal(t, policy.DropMust, resp.MappingRules[0].DropPolicy)
+}
+
+func TestMatchProposedRuleSetValidated(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	storeOpts := rulekv.NewStoreOptions(testNamespacesKey, testRuleSetKeyFmt, nil)
+	validatorOpts := validator.NewOptions().
+		SetNamespaceValidator(static.NewNamespaceValidator(static.Invalid))
+	router := mux.NewRouter()
+	RegisterMatchRoute(router, HandlerOptions{
+		Store:     rulekv.NewStore(mem.NewStore(), storeOpts),
+		Validator: validator.NewValidator(validatorOpts),
+	}, logging.WithResponseTimeLogging)
+
+	// Proposed rulesets failing validation are rejected.
+	w := serveTestRequest(router, http.MethodPost, testRuleSetURL("foo")+"/match", `{
+		"id": "m3+requests+host=h1,service=servers",
+		"ruleset": {
+			"mappingRules": [{
+				"name": "dropServers",
+				"filter": "service:servers",
+				"dropPolicy": 1
+			}]
+		}
+	}`)
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
+
+func TestMatchInvalidMetric(t *testing.T) {
+	router, _ := testRouter()
+
+	matchURL := testRuleSetURL("foo") + "/match"
+	for _, body := range []string{
+		`{}`,
+		`{"id": "m3+requests+host=h1", "name": "requests"}`,
+		`{"id": "requests"}`,
+		`{"name": "requests", "tags": {"host": "h1,h2"}}`,
+	} {
+		w := serveTestRequest(router, http.MethodPost, matchURL, body)
+		require.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	merrors "github.com/m3db/m3/src/metrics/errors"
+	"github.com/m3db/m3/src/metrics/rules"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+var (
+	errEmptyNamespace = errors.New("must specify namespace")
+)
+
+// ListNamespacesHandler is the handler for listing rule namespaces.
+type ListNamespacesHandler struct {
+	Handler
+}
+
+// NewListNamespacesHandler returns a new instance of ListNamespacesHandler.
+func NewListNamespacesHandler(opts HandlerOptions) *ListNamespacesHandler {
+	return &ListNamespacesHandler{Handler: newHandler(opts)}
+}
+
+func (h *ListNamespacesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	nss, err := h.readNamespaces()
+	if err != nil {
+		logger.Error("unable to read namespaces", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+	view, err := nss.NamespacesView()
+	if err != nil {
+		logger.Error("unable to get namespaces view", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	xhttp.WriteJSONResponse(w, view, logger)
+}
+
+// AddNamespaceRequest is a request to add a rule namespace.
+type AddNamespaceRequest struct {
+	ID string `json:"id"`
+}
+
+// AddNamespaceHandler is the handler for adding rule namespaces.
+type AddNamespaceHandler struct {
+	Handler
+}
+
+// NewAddNamespaceHandler returns a new instance of AddNamespaceHandler.
+func NewAddNamespaceHandler(opts HandlerOptions) *AddNamespaceHandler {
+	return &AddNamespaceHandler{Handler: newHandler(opts)}
+}
+
+func (h *AddNamespaceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+
+	var req AddNamespaceRequest
+	if rErr := parseRequest(r, &req); rErr != nil {
+		logger.Error("unable to parse request", zap.Any("error", rErr))
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+	nsName := strings.TrimSpace(req.ID)
+	if nsName == "" {
+		logger.Error("no namespace to add", zap.Any("error", errEmptyNamespace))
+		xhttp.Error(w, errEmptyNamespace, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.addNamespace(r, nsName); err != nil {
+		logger.Error("unable to add namespace", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+	nss, err := h.readNamespaces()
+	if err != nil {
+		logger.Error("unable to read namespaces", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+	view, err := nss.NamespacesView()
+	if err != nil {
+		logger.Error("unable to get namespaces view", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	xhttp.WriteJSONResponse(w, view, logger)
+}
+
+// addNamespace adds the namespace alongside an empty ruleset, or revives the
+// namespace and its ruleset if the namespace has been deleted.
+func (h *AddNamespaceHandler) addNamespace(r *http.Request, nsName string) error {
+	nss, err := h.readNamespaces()
+	if err != nil {
+		return err
+	}
+	if ns, err := nss.Namespace(nsName); err == nil && !ns.Tombstoned() {
+		return merrors.NewInvalidInputError(fmt.Sprintf("namespace %s already exists", nsName))
+	}
+	meta := h.updateMetadata(r)
+	revived, err := nss.AddNamespace(nsName, meta)
+	if err != nil {
+		return err
+	}
+
+	var rs rules.MutableRuleSet
+	if !revived {
+		rs = rules.NewEmptyRuleSet(nsName, meta)
+	} else {
+		existing, err := h.store.ReadRuleSet(nsName)
+		if err != nil {
+			return err
+		}
+		rs = existing.ToMutableRuleSet().Clone()
+		if err := rs.Revive(meta); err != nil {
+			return err
+		}
+	}
+	return h.store.WriteAll(nss, rs)
+}
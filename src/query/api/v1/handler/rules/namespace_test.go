@@ -0,0 +1,121 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/m3db/m3/src/cluster/kv/mem"
+	"github.com/m3db/m3/src/metrics/rules"
+	rulekv "github.com/m3db/m3/src/metrics/rules/store/kv"
+	"github.com/m3db/m3/src/metrics/rules/view"
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testNamespacesKey = "namespaces"
+	testRuleSetKeyFmt = "rulesets/%s"
+)
+
+func TestListNamespacesEmpty(t *testing.T) {
+	router, _ := testRouter()
+
+	w := serveTestRequest(router, http.MethodGet, NamespacesURL, "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var nss view.Namespaces
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &nss))
+	require.Empty(t, nss.Namespaces)
+}
+
+func TestAddNamespace(t *testing.T) {
+	router, store := testRouter()
+
+	w := serveTestRequest(router, http.MethodPost, NamespacesURL, `{"id": "foo"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var nss view.Namespaces
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &nss))
+	require.Len(t, nss.Namespaces, 1)
+	require.Equal(t, "foo", nss.Namespaces[0].ID)
+	require.False(t, nss.Namespaces[0].Tombstoned)
+
+	rs, err := store.ReadRuleSet("foo")
+	require.NoError(t, err)
+	require.Equal(t, "foo", string(rs.Namespace()))
+	require.False(t, rs.Tombstoned())
+
+	// Adding the same namespace again is rejected.
+	w = serveTestRequest(router, http.MethodPost, NamespacesURL, `{"id": "foo"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAddNamespaceInvalidRequest(t *testing.T) {
+	router, _ := testRouter()
+
+	w := serveTestRequest(router, http.MethodPost, NamespacesURL, `{"id": " "}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveTestRequest(router, http.MethodPost, NamespacesURL, `{`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func testRouter() (*mux.Router, rules.Store) {
+	logging.InitWithCores(nil)
+
+	opts := rulekv.NewStoreOptions(testNamespacesKey, testRuleSetKeyFmt, nil)
+	store := rulekv.NewStore(mem.NewStore(), opts)
+	router := mux.NewRouter()
+	handlerOpts := HandlerOptions{Store: store}
+	RegisterRoutes(router, handlerOpts)
+	RegisterMatchRoute(router, handlerOpts, logging.WithResponseTimeLogging)
+	return router, store
+}
+
+func serveTestRequest(
+	router *mux.Router,
+	method string,
+	url string,
+	body string,
+) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, url, nil)
+	} else {
+		req = httptest.NewRequest(method, url, bytes.NewBufferString(body))
+	}
+	req.Header.Set(HeaderUpdatedBy, "tester")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func testRuleSetURL(nsName string) string {
+	return strings.Replace(RuleSetURL, "{"+namespaceVar+"}", nsName, 1)
+}
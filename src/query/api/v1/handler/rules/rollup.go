@@ -0,0 +1,124 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	merrors "github.com/m3db/m3/src/metrics/errors"
+	"github.com/m3db/m3/src/metrics/rules"
+	"github.com/m3db/m3/src/metrics/rules/view"
+)
+
+var (
+	errEmptyRollupRuleID = errors.New("must specify rollup rule ID")
+
+	rollupRuleType = ruleType{
+		name:           "rollup rule",
+		errEmptyRuleID: errEmptyRollupRuleID,
+		newRule:        func() interface{} { return &view.RollupRule{} },
+		setID:          func(rule interface{}, id string) { rule.(*view.RollupRule).ID = id },
+		latest: func(rs rules.RuleSet, id string) (interface{}, error) {
+			return latestRollupRule(rs, id)
+		},
+		add: func(rs rules.MutableRuleSet, rule interface{}, meta rules.UpdateMetadata) (string, error) {
+			return rs.AddRollupRule(*rule.(*view.RollupRule), meta)
+		},
+		update: func(rs rules.MutableRuleSet, rule interface{}, meta rules.UpdateMetadata) error {
+			return rs.UpdateRollupRule(*rule.(*view.RollupRule), meta)
+		},
+		delete: func(rs rules.MutableRuleSet, id string, meta rules.UpdateMetadata) error {
+			return rs.DeleteRollupRule(id, meta)
+		},
+	}
+)
+
+// latestRollupRule returns the latest snapshot of a rollup rule in a ruleset.
+func latestRollupRule(rs rules.RuleSet, id string) (view.RollupRule, error) {
+	latest, err := rs.Latest()
+	if err != nil {
+		return view.RollupRule{}, err
+	}
+	for _, rule := range latest.RollupRules {
+		if rule.ID == id {
+			return rule, nil
+		}
+	}
+	return view.RollupRule{}, merrors.NewNotFoundError(fmt.Sprintf("rollup rule %s not found", id))
+}
+
+// GetRollupRuleHandler is the handler for getting a rollup rule.
+type GetRollupRuleHandler struct {
+	ruleHandler
+}
+
+// NewGetRollupRuleHandler returns a new instance of GetRollupRuleHandler.
+func NewGetRollupRuleHandler(opts HandlerOptions) *GetRollupRuleHandler {
+	return &GetRollupRuleHandler{ruleHandler: newRuleHandler(opts, rollupRuleType)}
+}
+
+func (h *GetRollupRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serveGet(w, r)
+}
+
+// AddRollupRuleHandler is the handler for adding a rollup rule.
+type AddRollupRuleHandler struct {
+	ruleHandler
+}
+
+// NewAddRollupRuleHandler returns a new instance of AddRollupRuleHandler.
+func NewAddRollupRuleHandler(opts HandlerOptions) *AddRollupRuleHandler {
+	return &AddRollupRuleHandler{ruleHandler: newRuleHandler(opts, rollupRuleType)}
+}
+
+func (h *AddRollupRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serveAdd(w, r)
+}
+
+// UpdateRollupRuleHandler is the handler for updating a rollup rule.
+type UpdateRollupRuleHandler struct {
+	ruleHandler
+}
+
+// NewUpdateRollupRuleHandler returns a new instance of UpdateRollupRuleHandler.
+func NewUpdateRollupRuleHandler(opts HandlerOptions) *UpdateRollupRuleHandler {
+	return &UpdateRollupRuleHandler{ruleHandler: newRuleHandler(opts, rollupRuleType)}
+}
+
+func (h *UpdateRollupRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serveUpdate(w, r)
+}
+
+// DeleteRollupRuleHandler is the handler for deleting a rollup rule.
+type DeleteRollupRuleHandler struct {
+	ruleHandler
+}
+
+// NewDeleteRollupRuleHandler returns a new instance of DeleteRollupRuleHandler.
+func NewDeleteRollupRuleHandler(opts HandlerOptions) *DeleteRollupRuleHandler {
+	return &DeleteRollupRuleHandler{ruleHandler: newRuleHandler(opts, rollupRuleType)}
+}
+
+func (h *DeleteRollupRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.serveDelete(w, r)
+}
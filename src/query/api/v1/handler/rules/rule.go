@@ -0,0 +1,215 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/m3db/m3/src/metrics/rules"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// ruleType describes a type of rule so that the handlers of the different
+// types of rules share the same implementation.
+type ruleType struct {
+	// name of the rule type used in log messages.
+	name string
+
+	// errEmptyRuleID is returned when the request path has no rule ID.
+	errEmptyRuleID error
+
+	// newRule returns a pointer to a new empty rule view to parse requests into.
+	newRule func() interface{}
+
+	// setID sets the ID of the rule view.
+	setID func(rule interface{}, id string)
+
+	// latest returns the latest snapshot of the rule with the given ID.
+	latest func(rs rules.RuleSet, id string) (interface{}, error)
+
+	// add adds the rule to the ruleset, returning the ID of the added rule.
+	add func(rs rules.MutableRuleSet, rule interface{}, meta rules.UpdateMetadata) (string, error)
+
+	// update updates the rule in the ruleset.
+	update func(rs rules.MutableRuleSet, rule interface{}, meta rules.UpdateMetadata) error
+
+	// delete deletes the rule with the given ID from the ruleset.
+	delete func(rs rules.MutableRuleSet, id string, meta rules.UpdateMetadata) error
+}
+
+// DeleteRuleResponse is the response of a rule deletion.
+type DeleteRuleResponse struct {
+	Deleted bool `json:"deleted"`
+}
+
+// ruleHandler implements the handlers of a type of rule.
+type ruleHandler struct {
+	Handler
+
+	ruleType ruleType
+}
+
+func newRuleHandler(opts HandlerOptions, ruleType ruleType) ruleHandler {
+	return ruleHandler{
+		Handler:  newHandler(opts),
+		ruleType: ruleType,
+	}
+}
+
+func (h ruleHandler) serveGet(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+	nsName, ruleID, err := parseRulePath(r, h.ruleType.errEmptyRuleID)
+	if err != nil {
+		logger.Error("invalid request path", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	rs, err := h.store.ReadRuleSet(nsName)
+	if err != nil {
+		logger.Error("unable to read ruleset", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+	rule, err := h.ruleType.latest(rs, ruleID)
+	if err != nil {
+		logger.Error("unable to get "+h.ruleType.name, zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+	xhttp.WriteJSONResponse(w, rule, logger)
+}
+
+func (h ruleHandler) serveAdd(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+	nsName := strings.TrimSpace(mux.Vars(r)[namespaceVar])
+	if nsName == "" {
+		logger.Error("no namespace to add "+h.ruleType.name+" to", zap.Any("error", errEmptyNamespace))
+		xhttp.Error(w, errEmptyNamespace, http.StatusBadRequest)
+		return
+	}
+	version, err := parseRuleSetVersion(r)
+	if err != nil {
+		logger.Error("invalid ruleset version", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	rule := h.ruleType.newRule()
+	if rErr := parseRequest(r, rule); rErr != nil {
+		logger.Error("unable to parse request", zap.Any("error", rErr))
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	var ruleID string
+	rs, err := h.updateRuleSet(r, nsName, version,
+		func(rs rules.MutableRuleSet, meta rules.UpdateMetadata) error {
+			var err error
+			ruleID, err = h.ruleType.add(rs, rule, meta)
+			return err
+		})
+	if err != nil {
+		logger.Error("unable to add "+h.ruleType.name, zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+	added, err := h.ruleType.latest(rs, ruleID)
+	if err != nil {
+		logger.Error("unable to get added "+h.ruleType.name, zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	xhttp.WriteJSONResponse(w, added, logger)
+}
+
+func (h ruleHandler) serveUpdate(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+	nsName, ruleID, err := parseRulePath(r, h.ruleType.errEmptyRuleID)
+	if err != nil {
+		logger.Error("invalid request path", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+	version, err := parseRuleSetVersion(r)
+	if err != nil {
+		logger.Error("invalid ruleset version", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	rule := h.ruleType.newRule()
+	if rErr := parseRequest(r, rule); rErr != nil {
+		logger.Error("unable to parse request", zap.Any("error", rErr))
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+	h.ruleType.setID(rule, ruleID)
+
+	rs, err := h.updateRuleSet(r, nsName, version,
+		func(rs rules.MutableRuleSet, meta rules.UpdateMetadata) error {
+			return h.ruleType.update(rs, rule, meta)
+		})
+	if err != nil {
+		logger.Error("unable to update "+h.ruleType.name, zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+	updated, err := h.ruleType.latest(rs, ruleID)
+	if err != nil {
+		logger.Error("unable to get updated "+h.ruleType.name, zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	xhttp.WriteJSONResponse(w, updated, logger)
+}
+
+func (h ruleHandler) serveDelete(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+	nsName, ruleID, err := parseRulePath(r, h.ruleType.errEmptyRuleID)
+	if err != nil {
+		logger.Error("invalid request path", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+	version, err := parseRuleSetVersion(r)
+	if err != nil {
+		logger.Error("invalid ruleset version", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.updateRuleSet(r, nsName, version,
+		func(rs rules.MutableRuleSet, meta rules.UpdateMetadata) error {
+			return h.ruleType.delete(rs, ruleID, meta)
+		})
+	if err != nil {
+		logger.Error("unable to delete "+h.ruleType.name, zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+	xhttp.WriteJSONResponse(w, DeleteRuleResponse{Deleted: true}, logger)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/m3db/m3/src/metrics/rules"
+	"github.com/m3db/m3/src/metrics/rules/view/changes"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+var (
+	errNamespaceMismatch = errors.New("ruleset changes namespace does not match request namespace")
+)
+
+// GetRuleSetHandler is the handler for getting the latest ruleset of a namespace.
+type GetRuleSetHandler struct {
+	Handler
+}
+
+// NewGetRuleSetHandler returns a new instance of GetRuleSetHandler.
+func NewGetRuleSetHandler(opts HandlerOptions) *GetRuleSetHandler {
+	return &GetRuleSetHandler{Handler: newHandler(opts)}
+}
+
+func (h *GetRuleSetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+	nsName := strings.TrimSpace(mux.Vars(r)[namespaceVar])
+	if nsName == "" {
+		logger.Error("no namespace to get ruleset for", zap.Any("error", errEmptyNamespace))
+		xhttp.Error(w, errEmptyNamespace, http.StatusBadRequest)
+		return
+	}
+
+	rs, err := h.store.ReadRuleSet(nsName)
+	if err != nil {
+		logger.Error("unable to read ruleset", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+	latest, err := rs.Latest()
+	if err != nil {
+		logger.Error("unable to get latest ruleset", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	xhttp.WriteJSONResponse(w, latest, logger)
+}
+
+// ApplyChangesRequest is a request to apply a batch of changes to a ruleset.
+type ApplyChangesRequest struct {
+	// The ruleset version the changes are based on. The changes are rejected
+	// if the ruleset has been updated since.
+	RuleSetVersion int `json:"rulesetVersion"`
+
+	// The changes to apply.
+	RuleSetChanges changes.RuleSetChanges `json:"rulesetChanges"`
+}
+
+// ApplyChangesHandler is the handler for applying a batch of changes to a ruleset.
+type ApplyChangesHandler struct {
+	Handler
+}
+
+// NewApplyChangesHandler returns a new instance of ApplyChangesHandler.
+func NewApplyChangesHandler(opts HandlerOptions) *ApplyChangesHandler {
+	return &ApplyChangesHandler{Handler: newHandler(opts)}
+}
+
+func (h *ApplyChangesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := logging.WithContext(r.Context())
+	nsName := strings.TrimSpace(mux.Vars(r)[namespaceVar])
+	if nsName == "" {
+		logger.Error("no namespace to apply changes to", zap.Any("error", errEmptyNamespace))
+		xhttp.Error(w, errEmptyNamespace, http.StatusBadRequest)
+		return
+	}
+
+	var req ApplyChangesRequest
+	if rErr := parseRequest(r, &req); rErr != nil {
+		logger.Error("unable to parse request", zap.Any("error", rErr))
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+	if req.RuleSetChanges.Namespace == "" {
+		req.RuleSetChanges.Namespace = nsName
+	} else if req.RuleSetChanges.Namespace != nsName {
+		logger.Error("invalid ruleset changes", zap.Any("error", errNamespaceMismatch))
+		xhttp.Error(w, errNamespaceMismatch, http.StatusBadRequest)
+		return
+	}
+
+	rs, err := h.updateRuleSet(r, nsName, &req.RuleSetVersion,
+		func(rs rules.MutableRuleSet, meta rules.UpdateMetadata) error {
+			return rs.ApplyRuleSetChanges(req.RuleSetChanges, meta)
+		})
+	if err != nil {
+		logger.Error("unable to apply ruleset changes", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+	latest, err := rs.Latest()
+	if err != nil {
+		logger.Error("unable to get latest ruleset", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	xhttp.WriteJSONResponse(w, latest, logger)
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/m3db/m3/src/metrics/rules/view"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRuleSetChanges(t *testing.T) {
+	router, _ := testRouter()
+
+	w := serveTestRequest(router, http.MethodPost, NamespacesURL, `{"id": "foo"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = serveTestRequest(router, http.MethodGet, testRuleSetURL("foo"), "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var rs view.RuleSet
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rs))
+	require.Empty(t, rs.MappingRules)
+	require.Empty(t, rs.RollupRules)
+
+	changesURL := testRuleSetURL("foo") + "/changes"
+	body := `{
+		"rulesetVersion": %d,
+		"rulesetChanges": {
+			"mappingRuleChanges": [{
+				"op": "add",
+				"ruleData": {
+					"name": "servers",
+					"filter": "name:servers.*",
+					"storagePolicies": ["10s:2d"]
+				}
+			}]
+		}
+	}`
+	w = serveTestRequest(router, http.MethodPost, changesURL, fmt.Sprintf(body, rs.Version))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var updated view.RuleSet
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	require.Equal(t, rs.Version+1, updated.Version)
+	require.Len(t, updated.MappingRules, 1)
+	require.Equal(t, "servers", updated.MappingRules[0].Name)
+
+	// Changes based on a stale ruleset version are rejected.
+	w = serveTestRequest(router, http.MethodPost, changesURL, fmt.Sprintf(body, rs.Version))
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestApplyRuleSetChangesNamespaceMismatch(t *testing.T) {
+	router, _ := testRouter()
+
+	w := serveTestRequest(router, http.MethodPost, NamespacesURL, `{"id": "foo"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = serveTestRequest(router, http.MethodPost, testRuleSetURL("foo")+"/changes", `{
+		"rulesetVersion": 1,
+		"rulesetChanges": {"namespace": "bar"}
+	}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -21,18 +21,23 @@
 package topic
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
 	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/kv"
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
 	"github.com/m3db/m3/src/msg/topic"
+	"github.com/m3db/m3/src/query/generated/proto/admin"
 	"github.com/m3db/m3/src/query/util/logging"
 	"github.com/m3db/m3/src/x/net/http"
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
 	"github.com/gorilla/mux"
+	"go.uber.org/zap"
 )
 
 const (
@@ -40,6 +45,18 @@ const (
 	DefaultTopicName = "aggregated_metrics"
 	// HeaderTopicName is the header used to specify the topic name.
 	HeaderTopicName = "topic-name"
+
+	// versionQueryParam is the query parameter used to specify the version of
+	// the topic a change is based on, the change is refused if the topic has
+	// been updated since.
+	versionQueryParam = "version"
+	// forceQueryParam is the query parameter used to force unsafe changes.
+	forceQueryParam = "force"
+)
+
+var (
+	errInvalidTopicVersion  = errors.New("invalid topic version")
+	errTopicVersionMismatch = errors.New("topic is not at the specified version")
 )
 
 type serviceFn func(clusterClient clusterclient.Client) (topic.Service, error)
@@ -69,6 +86,10 @@ func RegisterRoutes(r *mux.Router, client clusterclient.Client, cfg config.Confi
 	r.HandleFunc(InitURL, logged(NewInitHandler(client, cfg)).ServeHTTP).Methods(InitHTTPMethod)
 	r.HandleFunc(GetURL, logged(NewGetHandler(client, cfg)).ServeHTTP).Methods(GetHTTPMethod)
 	r.HandleFunc(AddURL, logged(NewAddHandler(client, cfg)).ServeHTTP).Methods(AddHTTPMethod)
+	r.HandleFunc(UpdateURL, logged(NewUpdateHandler(client, cfg)).ServeHTTP).Methods(UpdateHTTPMethod)
+	r.HandleFunc(RemoveURL, logged(NewRemoveHandler(client, cfg)).ServeHTTP).Methods(RemoveHTTPMethod)
+	r.HandleFunc(ShardsURL, logged(NewShardsHandler(client, cfg)).ServeHTTP).Methods(ShardsHTTPMethod)
+	r.HandleFunc(DeleteURL, logged(NewDeleteHandler(client, cfg)).ServeHTTP).Methods(DeleteHTTPMethod)
 }
 
 func topicName(headers http.Header) string {
@@ -86,3 +107,55 @@ func parseRequest(r *http.Request, m proto.Message) *xhttp.ParseError {
 	}
 	return nil
 }
+
+// checkVersion returns an error if the request specifies a topic version
+// that does not match the version of the topic.
+func checkVersion(r *http.Request, t topic.Topic) error {
+	str := strings.TrimSpace(r.FormValue(versionQueryParam))
+	if str == "" {
+		return nil
+	}
+	version, err := strconv.Atoi(str)
+	if err != nil {
+		return errInvalidTopicVersion
+	}
+	if version != t.Version() {
+		return errTopicVersionMismatch
+	}
+	return nil
+}
+
+func isForced(r *http.Request) bool {
+	return r.FormValue(forceQueryParam) == "true"
+}
+
+// errorStatusCode returns the http status code for an error returned by the
+// topic service.
+func errorStatusCode(err error) int {
+	switch err {
+	case errInvalidTopicVersion:
+		return http.StatusBadRequest
+	case kv.ErrNotFound:
+		return http.StatusNotFound
+	case kv.ErrVersionMismatch, kv.ErrAlreadyExists, errTopicVersionMismatch:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeTopicResponse(w http.ResponseWriter, t topic.Topic, logger *zap.Logger) {
+	topicProto, err := topic.ToProto(t)
+	if err != nil {
+		logger.Error("unable to get topic protobuf", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	resp := &admin.TopicGetResponse{
+		Topic:   topicProto,
+		Version: uint32(t.Version()),
+	}
+
+	xhttp.WriteProtoMsgJSONResponse(w, resp, logger)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package topic
+
+import (
+	"errors"
+	"net/http"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DeleteURL is the url for the topic delete handler (with the DELETE method).
+	DeleteURL = handler.RoutePrefixV1 + "/topic"
+
+	// DeleteHTTPMethod is the HTTP method used with this resource.
+	DeleteHTTPMethod = http.MethodDelete
+)
+
+var (
+	errDeleteTopicWithConsumerServices = errors.New("could not delete a topic with consumer services unless forced")
+)
+
+// DeleteHandler is the handler for topic deletes.
+type DeleteHandler Handler
+
+// NewDeleteHandler returns a new instance of DeleteHandler.
+func NewDeleteHandler(client clusterclient.Client, cfg config.Configuration) *DeleteHandler {
+	return &DeleteHandler{client: client, cfg: cfg, serviceFn: Service}
+}
+
+func (h *DeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		logger = logging.WithContext(ctx)
+		name   = topicName(r.Header)
+	)
+
+	service, err := h.serviceFn(h.client)
+	if err != nil {
+		logger.Error("unable to get service", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	t, err := service.Get(name)
+	if err != nil {
+		logger.Error("unable to get topic", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+
+	if err := checkVersion(r, t); err != nil {
+		logger.Error("unable to delete topic", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+
+	// NB: Producers of a topic with consumer services would stop delivering
+	// messages, so the consumer services need to be removed first.
+	if len(t.ConsumerServices()) > 0 && !isForced(r) {
+		logger.Error("unable to delete topic", zap.Any("error", errDeleteTopicWithConsumerServices))
+		xhttp.Error(w, errDeleteTopicWithConsumerServices, http.StatusBadRequest)
+		return
+	}
+
+	if err := service.Delete(name); err != nil {
+		logger.Error("unable to delete topic", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+
+	xhttp.WriteJSONResponse(w, struct {
+		Deleted bool `json:"deleted"`
+	}{
+		Deleted: true,
+	}, logger)
+}
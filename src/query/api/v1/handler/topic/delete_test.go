@@ -0,0 +1,75 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package topic
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/msg/topic"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicDeleteHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := setupTest(t, ctrl)
+	handler := NewDeleteHandler(nil, config.Configuration{})
+	handler.serviceFn = testServiceFn(mockService)
+
+	t1 := topic.NewTopic().SetName(DefaultTopicName).SetNumberOfShards(256).SetVersion(1)
+	t2 := t1.SetConsumerServices([]topic.ConsumerService{testConsumerService(topic.Shared, time.Minute)})
+
+	// Unknown topic.
+	mockService.EXPECT().Get(DefaultTopicName).Return(nil, kv.ErrNotFound)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(DeleteHTTPMethod, "/topic", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	// Topic with consumer services without force.
+	mockService.EXPECT().Get(DefaultTopicName).Return(t2, nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(DeleteHTTPMethod, "/topic", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Topic with consumer services with force.
+	mockService.EXPECT().Get(DefaultTopicName).Return(t2, nil)
+	mockService.EXPECT().Delete(DefaultTopicName).Return(nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(DeleteHTTPMethod, "/topic?force=true&version=1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "{\"deleted\":true}", w.Body.String())
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	// Topic without consumer services.
+	mockService.EXPECT().Get(DefaultTopicName).Return(t1, nil)
+	mockService.EXPECT().Delete(DefaultTopicName).Return(nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(DeleteHTTPMethod, "/topic", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package topic
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	consumerServiceNameVar = "name"
+	environmentQueryParam  = "environment"
+	zoneQueryParam         = "zone"
+
+	// RemoveHTTPMethod is the HTTP method used with this resource.
+	RemoveHTTPMethod = http.MethodDelete
+)
+
+var (
+	// RemoveURL is the url for the topic consumer service remove handler (with the DELETE method).
+	RemoveURL = fmt.Sprintf("%s/topic/consumer_service/{%s}", handler.RoutePrefixV1, consumerServiceNameVar)
+
+	errEmptyConsumerServiceName = errors.New("must specify consumer service name to remove")
+)
+
+// RemoveHandler is the handler for topic consumer service removals.
+type RemoveHandler Handler
+
+// NewRemoveHandler returns a new instance of RemoveHandler.
+func NewRemoveHandler(client clusterclient.Client, cfg config.Configuration) *RemoveHandler {
+	return &RemoveHandler{client: client, cfg: cfg, serviceFn: Service}
+}
+
+func (h *RemoveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		logger = logging.WithContext(ctx)
+		name   = strings.TrimSpace(mux.Vars(r)[consumerServiceNameVar])
+	)
+	if name == "" {
+		logger.Error("no consumer service to remove", zap.Any("error", errEmptyConsumerServiceName))
+		xhttp.Error(w, errEmptyConsumerServiceName, http.StatusBadRequest)
+		return
+	}
+	sid := services.NewServiceID().
+		SetName(name).
+		SetEnvironment(r.FormValue(environmentQueryParam)).
+		SetZone(r.FormValue(zoneQueryParam))
+
+	service, err := h.serviceFn(h.client)
+	if err != nil {
+		logger.Error("unable to get service", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	t, err := service.Get(topicName(r.Header))
+	if err != nil {
+		logger.Error("unable to get topic", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+
+	if err := checkVersion(r, t); err != nil {
+		logger.Error("unable to remove consumer service", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+
+	t, err = t.RemoveConsumerService(sid)
+	if err != nil {
+		logger.Error("unable to remove consumer service", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusNotFound)
+		return
+	}
+
+	t, err = service.CheckAndSet(t, t.Version())
+	if err != nil {
+		logger.Error("unable to persist consumer service removal", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+
+	writeTopicResponse(w, t, logger)
+}
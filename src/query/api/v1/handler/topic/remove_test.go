@@ -0,0 +1,91 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package topic
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/kv"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/msg/topic"
+	"github.com/m3db/m3/src/query/generated/proto/admin"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicRemoveHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := setupTest(t, ctrl)
+	handler := NewRemoveHandler(nil, config.Configuration{})
+	handler.serviceFn = testServiceFn(mockService)
+	router := mux.NewRouter()
+	router.Handle(RemoveURL, handler).Methods(RemoveHTTPMethod)
+
+	newTopic := func() topic.Topic {
+		return topic.NewTopic().
+			SetName(DefaultTopicName).
+			SetNumberOfShards(256).
+			SetConsumerServices([]topic.ConsumerService{testConsumerService(topic.Shared, time.Minute)}).
+			SetVersion(2)
+	}
+
+	// Unknown consumer service.
+	mockService.EXPECT().Get(DefaultTopicName).Return(newTopic(), nil)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(RemoveHTTPMethod, "/api/v1/topic/consumer_service/name1?environment=env2&zone=zone1", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	// CheckAndSet conflict.
+	mockService.EXPECT().Get(DefaultTopicName).Return(newTopic(), nil)
+	mockService.EXPECT().CheckAndSet(gomock.Any(), 2).Return(nil, kv.ErrVersionMismatch)
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(RemoveHTTPMethod, "/api/v1/topic/consumer_service/name1?environment=env1&zone=zone1", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	// Successful removal.
+	mockService.EXPECT().Get(DefaultTopicName).Return(newTopic(), nil)
+	mockService.EXPECT().CheckAndSet(gomock.Any(), 2).DoAndReturn(func(t topic.Topic, version int) (topic.Topic, error) {
+		return t.SetVersion(3), nil
+	})
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(RemoveHTTPMethod, "/api/v1/topic/consumer_service/name1?environment=env1&zone=zone1&version=2", nil)
+	router.ServeHTTP(w, req)
+	resp := w.Result()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var respProto admin.TopicGetResponse
+	require.NoError(t, jsonUnmarshaler.Unmarshal(bytes.NewBuffer(body), &respProto))
+	require.Equal(t, uint32(3), respProto.Version)
+	require.Empty(t, respProto.Topic.ConsumerServices)
+}
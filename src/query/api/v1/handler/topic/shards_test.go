@@ -0,0 +1,93 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package topic
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/msg/topic"
+	"github.com/m3db/m3/src/query/generated/proto/admin"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newShardsRequest(t *testing.T, numShards uint32) *http.Request {
+	b := bytes.NewBuffer(nil)
+	require.NoError(t, jsonMarshaler.Marshal(b, &admin.TopicInitRequest{NumberOfShards: numShards}))
+	return httptest.NewRequest(ShardsHTTPMethod, "/topic/shards", b)
+}
+
+func TestTopicShardsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := setupTest(t, ctrl)
+	handler := NewShardsHandler(nil, config.Configuration{})
+	handler.serviceFn = testServiceFn(mockService)
+
+	t1 := topic.NewTopic().SetName(DefaultTopicName).SetNumberOfShards(256).SetVersion(1)
+	mockService.EXPECT().Get(DefaultTopicName).Return(t1, nil)
+	mockService.EXPECT().CheckAndSet(gomock.Any(), 1).DoAndReturn(func(t topic.Topic, version int) (topic.Topic, error) {
+		require.Equal(t, uint32(1024), t.NumberOfShards())
+		return t.SetVersion(2), nil
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newShardsRequest(t, 1024))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var respProto admin.TopicGetResponse
+	require.NoError(t, jsonUnmarshaler.Unmarshal(w.Body, &respProto))
+	require.Equal(t, uint32(1024), respProto.Topic.NumberOfShards)
+	require.Equal(t, uint32(2), respProto.Version)
+
+	// Zero shards is refused.
+	mockService.EXPECT().Get(DefaultTopicName).Return(t1, nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newShardsRequest(t, 0))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTopicShardsHandlerWithConsumerServices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := setupTest(t, ctrl)
+	handler := NewShardsHandler(nil, config.Configuration{})
+	handler.serviceFn = testServiceFn(mockService)
+
+	t1 := topic.NewTopic().
+		SetName(DefaultTopicName).
+		SetNumberOfShards(256).
+		SetConsumerServices([]topic.ConsumerService{testConsumerService(topic.Shared, time.Minute)}).
+		SetVersion(1)
+	mockService.EXPECT().Get(DefaultTopicName).Return(t1, nil)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newShardsRequest(t, 1024))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package topic
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	clusterclient "github.com/m3db/m3/src/cluster/client"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/msg/topic"
+	"github.com/m3db/m3/src/query/api/v1/handler"
+	"github.com/m3db/m3/src/query/generated/proto/admin"
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// UpdateURL is the url for the topic consumer service update handler (with the PUT method).
+	UpdateURL = handler.RoutePrefixV1 + "/topic"
+
+	// UpdateHTTPMethod is the HTTP method used with this resource.
+	UpdateHTTPMethod = http.MethodPut
+)
+
+var (
+	errConsumptionTypeChange = errors.New("could not change the consumption type of a consumer service unless forced")
+)
+
+// UpdateHandler is the handler for topic consumer service updates.
+type UpdateHandler Handler
+
+// NewUpdateHandler returns a new instance of UpdateHandler.
+func NewUpdateHandler(client clusterclient.Client, cfg config.Configuration) *UpdateHandler {
+	return &UpdateHandler{client: client, cfg: cfg, serviceFn: Service}
+}
+
+func (h *UpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		logger = logging.WithContext(ctx)
+		req    admin.TopicAddRequest
+	)
+	rErr := parseRequest(r, &req)
+	if rErr != nil {
+		logger.Error("unable to parse request", zap.Any("error", rErr))
+		xhttp.Error(w, rErr.Inner(), rErr.Code())
+		return
+	}
+
+	service, err := h.serviceFn(h.client)
+	if err != nil {
+		logger.Error("unable to get service", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	t, err := service.Get(topicName(r.Header))
+	if err != nil {
+		logger.Error("unable to get topic", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+
+	if err := checkVersion(r, t); err != nil {
+		logger.Error("unable to update consumer service", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+
+	cs, err := topic.NewConsumerServiceFromProto(req.ConsumerService)
+	if err != nil {
+		logger.Error("unable to parse consumer service", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	idx := consumerServiceIndex(t, cs)
+	if idx < 0 {
+		err := fmt.Errorf("could not find consumer service %s in the topic", cs.ServiceID().String())
+		logger.Error("unable to update consumer service", zap.Any("error", err))
+		xhttp.Error(w, err, http.StatusNotFound)
+		return
+	}
+
+	// NB: Changing the consumption type of a consumer service is refused
+	// unless forced as messages in flight would be delivered with the wrong
+	// semantics.
+	if t.ConsumerServices()[idx].ConsumptionType() != cs.ConsumptionType() {
+		if !isForced(r) {
+			logger.Error("unable to update consumer service", zap.Any("error", errConsumptionTypeChange))
+			xhttp.Error(w, errConsumptionTypeChange, http.StatusBadRequest)
+			return
+		}
+		css := append([]topic.ConsumerService(nil), t.ConsumerServices()...)
+		css[idx] = cs
+		t = t.SetConsumerServices(css)
+	} else {
+		t, err = t.UpdateConsumerService(cs)
+		if err != nil {
+			logger.Error("unable to update consumer service", zap.Any("error", err))
+			xhttp.Error(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	t, err = service.CheckAndSet(t, t.Version())
+	if err != nil {
+		logger.Error("unable to persist consumer service", zap.Any("error", err))
+		xhttp.Error(w, err, errorStatusCode(err))
+		return
+	}
+
+	writeTopicResponse(w, t, logger)
+}
+
+// consumerServiceIndex returns the index of the consumer service in the
+// topic, or -1 if the topic does not have the consumer service.
+func consumerServiceIndex(t topic.Topic, cs topic.ConsumerService) int {
+	for i, c := range t.ConsumerServices() {
+		if c.ServiceID().Equal(cs.ServiceID()) {
+			return i
+		}
+	}
+	return -1
+}
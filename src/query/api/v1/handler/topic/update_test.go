@@ -0,0 +1,169 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package topic
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/cluster/services"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/msg/generated/proto/topicpb"
+	"github.com/m3db/m3/src/msg/topic"
+	"github.com/m3db/m3/src/query/generated/proto/admin"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func testConsumerService(ct topic.ConsumptionType, ttl time.Duration) topic.ConsumerService {
+	return topic.NewConsumerService().
+		SetServiceID(services.NewServiceID().SetName("name1").SetEnvironment("env1").SetZone("zone1")).
+		SetConsumptionType(ct).
+		SetMessageTTLNanos(int64(ttl))
+}
+
+func newUpdateRequest(t *testing.T, ct topicpb.ConsumptionType, ttl time.Duration, url string) *http.Request {
+	updateProto := admin.TopicAddRequest{
+		ConsumerService: &topicpb.ConsumerService{
+			ConsumptionType: ct,
+			ServiceId: &topicpb.ServiceID{
+				Environment: "env1",
+				Zone:        "zone1",
+				Name:        "name1",
+			},
+			MessageTtlNanos: int64(ttl),
+		},
+	}
+	b := bytes.NewBuffer(nil)
+	require.NoError(t, jsonMarshaler.Marshal(b, &updateProto))
+	return httptest.NewRequest(UpdateHTTPMethod, url, b)
+}
+
+func TestTopicUpdateHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := setupTest(t, ctrl)
+	handler := NewUpdateHandler(nil, config.Configuration{})
+	handler.serviceFn = testServiceFn(mockService)
+
+	t1 := topic.NewTopic().
+		SetName(DefaultTopicName).
+		SetNumberOfShards(256).
+		SetConsumerServices([]topic.ConsumerService{testConsumerService(topic.Shared, time.Minute)}).
+		SetVersion(2)
+
+	mockService.EXPECT().Get(DefaultTopicName).Return(t1, nil)
+	mockService.EXPECT().CheckAndSet(gomock.Any(), 2).DoAndReturn(func(t topic.Topic, version int) (topic.Topic, error) {
+		return t.SetVersion(3), nil
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newUpdateRequest(t, topicpb.ConsumptionType_SHARED, 5*time.Minute, "/topic?version=2"))
+	resp := w.Result()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var respProto admin.TopicGetResponse
+	require.NoError(t, jsonUnmarshaler.Unmarshal(bytes.NewBuffer(body), &respProto))
+	require.Equal(t, uint32(3), respProto.Version)
+	require.Equal(t, int64(5*time.Minute), respProto.Topic.ConsumerServices[0].MessageTtlNanos)
+}
+
+func TestTopicUpdateHandlerRefusesUnsafeChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := setupTest(t, ctrl)
+	handler := NewUpdateHandler(nil, config.Configuration{})
+	handler.serviceFn = testServiceFn(mockService)
+
+	newTopic := func() topic.Topic {
+		return topic.NewTopic().
+			SetName(DefaultTopicName).
+			SetNumberOfShards(256).
+			SetConsumerServices([]topic.ConsumerService{testConsumerService(topic.Shared, time.Minute)}).
+			SetVersion(2)
+	}
+
+	// Stale version.
+	mockService.EXPECT().Get(DefaultTopicName).Return(newTopic(), nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newUpdateRequest(t, topicpb.ConsumptionType_SHARED, time.Minute, "/topic?version=1"))
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	// Invalid version.
+	mockService.EXPECT().Get(DefaultTopicName).Return(newTopic(), nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newUpdateRequest(t, topicpb.ConsumptionType_SHARED, time.Minute, "/topic?version=foo"))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Consumption type change.
+	mockService.EXPECT().Get(DefaultTopicName).Return(newTopic(), nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newUpdateRequest(t, topicpb.ConsumptionType_REPLICATED, time.Minute, "/topic"))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Unknown consumer service.
+	mockService.EXPECT().Get(DefaultTopicName).Return(newTopic().SetConsumerServices(nil), nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, newUpdateRequest(t, topicpb.ConsumptionType_SHARED, time.Minute, "/topic"))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTopicUpdateHandlerForcedConsumptionTypeChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := setupTest(t, ctrl)
+	handler := NewUpdateHandler(nil, config.Configuration{})
+	handler.serviceFn = testServiceFn(mockService)
+
+	t1 := topic.NewTopic().
+		SetName(DefaultTopicName).
+		SetNumberOfShards(256).
+		SetConsumerServices([]topic.ConsumerService{testConsumerService(topic.Shared, time.Minute)}).
+		SetVersion(2)
+
+	mockService.EXPECT().Get(DefaultTopicName).Return(t1, nil)
+	mockService.EXPECT().CheckAndSet(gomock.Any(), 2).DoAndReturn(func(t topic.Topic, version int) (topic.Topic, error) {
+		return t.SetVersion(3), nil
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newUpdateRequest(t, topicpb.ConsumptionType_REPLICATED, time.Minute, "/topic?force=true"))
+	resp := w.Result()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var respProto admin.TopicGetResponse
+	require.NoError(t, jsonUnmarshaler.Unmarshal(bytes.NewBuffer(body), &respProto))
+	require.Equal(t, uint32(3), respProto.Version)
+	require.Equal(t, 1, len(respProto.Topic.ConsumerServices))
+	require.Equal(t, topicpb.ConsumptionType_REPLICATED, respProto.Topic.ConsumerServices[0].ConsumptionType)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
 	dbconfig "github.com/m3db/m3/src/cmd/services/m3dbnode/config"
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/dbnode/client"
 	"github.com/m3db/m3/src/query/api/v1/handler"
 	"github.com/m3db/m3/src/query/api/v1/handler/database"
 	m3json "github.com/m3db/m3/src/query/api/v1/handler/json"
@@ -39,11 +40,14 @@ import (
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/native"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/validator"
+	"github.com/m3db/m3/src/query/api/v1/handler/rules"
 	"github.com/m3db/m3/src/query/api/v1/handler/topic"
+	"github.com/m3db/m3/src/query/auth"
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
 	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/query/tenant"
 	"github.com/m3db/m3/src/query/util/logging"
 	"github.com/m3db/m3/src/x/net/http"
 
@@ -69,6 +73,7 @@ type Handler struct {
 	downsampler   downsample.Downsampler
 	engine        *executor.Engine
 	clusters      m3.Clusters
+	tenants       *tenant.Registry
 	clusterClient clusterclient.Client
 	config        config.Configuration
 	embeddedDbCfg *dbconfig.DBConfiguration
@@ -89,6 +94,7 @@ func NewHandler(
 	downsampler downsample.Downsampler,
 	engine *executor.Engine,
 	m3dbClusters m3.Clusters,
+	tenants *tenant.Registry,
 	clusterClient clusterclient.Client,
 	cfg config.Configuration,
 	embeddedDbCfg *dbconfig.DBConfiguration,
@@ -111,6 +117,7 @@ func NewHandler(
 		downsampler:   downsampler,
 		engine:        engine,
 		clusters:      m3dbClusters,
+		tenants:       tenants,
 		clusterClient: clusterClient,
 		config:        cfg,
 		embeddedDbCfg: embeddedDbCfg,
@@ -125,6 +132,21 @@ func NewHandler(
 func (h *Handler) RegisterRoutes() error {
 	logged := logging.WithResponseTimeLogging
 
+	var authorizer *auth.Authorizer
+	if h.config.Auth != nil {
+		var err error
+		authorizer, err = h.config.Auth.NewAuthorizer()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Data endpoints are served to callers granted the role with the tenant
+	// requests are made for, if any.
+	tenanted := func(role auth.Role, next http.Handler) http.Handler {
+		return logged(authorizer.Middleware(role, h.tenants.Middleware(next)))
+	}
+
 	h.router.HandleFunc(openapi.URL,
 		logged(&openapi.DocHandler{}).ServeHTTP,
 	).Methods(openapi.HTTPMethod)
@@ -145,62 +167,153 @@ func (h *Handler) RegisterRoutes() error {
 	nativePromReadHandler := native.NewPromReadHandler(h.engine, h.tagOptions, &h.config.Limits)
 
 	h.router.HandleFunc(remote.PromReadURL,
-		logged(promRemoteReadHandler).ServeHTTP,
+		tenanted(auth.RoleRead, promRemoteReadHandler).ServeHTTP,
 	).Methods(remote.PromReadHTTPMethod)
 	h.router.HandleFunc(remote.PromWriteURL,
-		promRemoteWriteHandler.ServeHTTP,
+		authorizer.Middleware(auth.RoleWrite,
+			h.tenants.Middleware(promRemoteWriteHandler)).ServeHTTP,
 	).Methods(remote.PromWriteHTTPMethod)
 	h.router.HandleFunc(native.PromReadURL,
-		logged(nativePromReadHandler).ServeHTTP,
+		tenanted(auth.RoleRead, nativePromReadHandler).ServeHTTP,
 	).Methods(native.PromReadHTTPMethod)
 	h.router.HandleFunc(native.PromReadInstantURL,
-		logged(native.NewPromReadInstantHandler(h.engine, h.tagOptions)).ServeHTTP,
+		tenanted(auth.RoleRead, native.NewPromReadInstantHandler(h.engine, h.tagOptions)).ServeHTTP,
 	).Methods(native.PromReadInstantHTTPMethod)
 
 	// Native M3 search and write endpoints
 	h.router.HandleFunc(handler.SearchURL,
-		logged(handler.NewSearchHandler(h.storage)).ServeHTTP,
+		tenanted(auth.RoleRead, handler.NewSearchHandler(h.storage)).ServeHTTP,
 	).Methods(handler.SearchHTTPMethod)
 	h.router.HandleFunc(m3json.WriteJSONURL,
-		logged(m3json.NewWriteJSONHandler(h.storage)).ServeHTTP,
+		tenanted(auth.RoleWrite, m3json.NewWriteJSONHandler(h.storage)).ServeHTTP,
 	).Methods(m3json.JSONWriteHTTPMethod)
 
 	// Tag completion endpoints
 	h.router.HandleFunc(native.CompleteTagsURL,
-		logged(native.NewCompleteTagsHandler(h.storage)).ServeHTTP,
+		tenanted(auth.RoleRead, native.NewCompleteTagsHandler(h.storage)).ServeHTTP,
 	).Methods(native.CompleteTagsHTTPMethod)
 	h.router.HandleFunc(remote.TagValuesURL,
-		logged(remote.NewTagValuesHandler(h.storage)).ServeHTTP,
+		tenanted(auth.RoleRead, remote.NewTagValuesHandler(h.storage)).ServeHTTP,
 	).Methods(remote.TagValuesHTTPMethod)
 
 	// Series match endpoints
 	h.router.HandleFunc(remote.PromSeriesMatchURL,
-		logged(remote.NewPromSeriesMatchHandler(h.storage, h.tagOptions)).ServeHTTP,
+		tenanted(auth.RoleRead, remote.NewPromSeriesMatchHandler(h.storage, h.tagOptions)).ServeHTTP,
 	).Methods(remote.PromSeriesMatchHTTPMethod)
 
 	// Debug endpoints
 	h.router.HandleFunc(validator.PromDebugURL,
-		logged(validator.NewPromDebugHandler(nativePromReadHandler, h.scope)).ServeHTTP,
+		logged(authorizer.Middleware(auth.RoleRead,
+			validator.NewPromDebugHandler(nativePromReadHandler, h.scope))).ServeHTTP,
 	).Methods(validator.PromDebugHTTPMethod)
+	h.router.HandleFunc(handler.CircuitBreakersURL,
+		logged(authorizer.Middleware(auth.RoleAdmin,
+			handler.NewCircuitBreakersHandler(h.clusters))).ServeHTTP,
+	).Methods(handler.CircuitBreakersHTTPMethod)
+
+	rulesOpts, err := h.rulesHandlerOptions()
+	if err != nil {
+		return err
+	}
+
+	// Admin endpoints are served to callers granted the admin role, with
+	// the mutating requests audited.
+	if err := h.registerAdminRoutes(authorizer, func() error {
+		h.registerClusterRoutes(rulesOpts)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// Previewing rule matches does not mutate the rules, so it is served to
+	// callers granted the read role without being audited.
+	if rulesOpts != nil {
+		rules.RegisterMatchRoute(h.router, *rulesOpts, func(next http.Handler) http.Handler {
+			return logged(authorizer.Middleware(auth.RoleRead, next))
+		})
+	}
+
+	h.registerHealthEndpoints()
+	return h.registerAdminRoutes(authorizer, func() error {
+		h.registerProfileEndpoints()
+		h.registerRoutesEndpoint()
+		return nil
+	})
+}
+
+// registerAdminRoutes registers the routes registered by the function to be
+// served to callers granted the admin role, auditing mutating requests.
+func (h *Handler) registerAdminRoutes(
+	authorizer *auth.Authorizer,
+	register func() error,
+) error {
+	existing := make(map[*mux.Route]struct{})
+	if err := h.router.Walk(
+		func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+			existing[route] = struct{}{}
+			return nil
+		}); err != nil {
+		return err
+	}
+
+	if err := register(); err != nil {
+		return err
+	}
 
+	return h.router.Walk(
+		func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+			if _, ok := existing[route]; ok {
+				return nil
+			}
+			if handler := route.GetHandler(); handler != nil {
+				route.Handler(authorizer.Middleware(auth.RoleAdmin, auth.Audit(handler)))
+			}
+			return nil
+		})
+}
+
+// registerClusterRoutes registers the placement, namespace, database, topic
+// and rules management routes.
+func (h *Handler) registerClusterRoutes(rulesOpts *rules.HandlerOptions) {
 	if h.clusterClient != nil {
 		placementOpts := placement.HandlerOptions{
 			ClusterClient:       h.clusterClient,
 			Config:              h.config,
 			M3AggServiceOptions: h.m3AggServiceOptions(),
+			ShardSizeFetcher:    h.shardSizeFetcher(),
 		}
 
 		placement.RegisterRoutes(h.router, placementOpts)
 		namespace.RegisterRoutes(h.router, h.clusterClient)
 		database.RegisterRoutes(h.router, h.clusterClient, h.config, h.embeddedDbCfg)
 		topic.RegisterRoutes(h.router, h.clusterClient, h.config)
-	}
 
-	h.registerHealthEndpoints()
-	h.registerProfileEndpoints()
-	h.registerRoutesEndpoint()
+		if rulesOpts != nil {
+			rules.RegisterRoutes(h.router, *rulesOpts)
+		}
+	}
+}
 
-	return nil
+// rulesHandlerOptions returns the options of the rules handlers, or nil if
+// rules are not managed by the coordinator.
+func (h *Handler) rulesHandlerOptions() (*rules.HandlerOptions, error) {
+	if h.clusterClient == nil || h.config.Rules == nil {
+		return nil, nil
+	}
+	rulesValidator, err := h.config.Rules.NewValidator(h.clusterClient)
+	if err != nil {
+		return nil, err
+	}
+	rulesStore, err := h.config.Rules.NewStore(h.clusterClient, rulesValidator)
+	if err != nil {
+		return nil, err
+	}
+	return &rules.HandlerOptions{
+		Store:            rulesStore,
+		Validator:        rulesValidator,
+		PropagationDelay: h.config.Rules.PropagationDelay,
+		NameTagKey:       h.config.Rules.NameTagKey,
+	}, nil
 }
 
 func (h *Handler) m3AggServiceOptions() *placement.M3AggServiceOptions {
@@ -225,6 +338,26 @@ func (h *Handler) m3AggServiceOptions() *placement.M3AggServiceOptions {
 	}
 }
 
+// shardSizeFetcher returns the fetcher of the M3DB shard sizes reported by
+// the nodes, or nil if there is no local M3DB cluster to fetch them from.
+func (h *Handler) shardSizeFetcher() placement.ShardSizeFetcher {
+	if h.clusters == nil {
+		return nil
+	}
+
+	ns := h.clusters.UnaggregatedClusterNamespace()
+	if ns == nil {
+		return nil
+	}
+
+	session, ok := ns.Session().(client.AdminSession)
+	if !ok {
+		return nil
+	}
+
+	return placement.NewShardSizeFetcher(session)
+}
+
 // Endpoints useful for profiling the service
 func (h *Handler) registerHealthEndpoints() {
 	h.router.HandleFunc(healthURL, func(w http.ResponseWriter, r *http.Request) {
@@ -31,6 +31,7 @@ import (
 	m3json "github.com/m3db/m3/src/query/api/v1/handler/json"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/native"
 	"github.com/m3db/m3/src/query/api/v1/handler/prometheus/remote"
+	"github.com/m3db/m3/src/query/auth"
 	"github.com/m3db/m3/src/query/executor"
 	"github.com/m3db/m3/src/query/models"
 	"github.com/m3db/m3/src/query/storage"
@@ -49,7 +50,7 @@ func makeTagOptions() models.TagOptions {
 
 func setupHandler(store storage.Storage) (*Handler, error) {
 	return NewHandler(store, makeTagOptions(), nil, executor.NewEngine(store, tally.NewTestScope("test", nil)), nil, nil,
-		config.Configuration{}, nil, tally.NewTestScope("", nil))
+		nil, config.Configuration{}, nil, tally.NewTestScope("", nil))
 }
 
 func TestPromRemoteReadGet(t *testing.T) {
@@ -190,6 +191,55 @@ func TestHealthGet(t *testing.T) {
 	assert.True(t, result > 0)
 }
 
+func TestAuthRoutes(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	ctrl := gomock.NewController(t)
+	store, _ := m3.NewStorageAndSession(t, ctrl)
+	cfg := config.Configuration{
+		Auth: &auth.Configuration{
+			Tokens: []auth.TokenConfiguration{
+				{
+					Name:                  "reader",
+					Token:                 "reader-token",
+					IdentityConfiguration: auth.IdentityConfiguration{Roles: []auth.Role{auth.RoleRead}},
+				},
+				{
+					Name:                  "admin",
+					Token:                 "admin-token",
+					IdentityConfiguration: auth.IdentityConfiguration{Roles: []auth.Role{auth.RoleAdmin}},
+				},
+			},
+		},
+	}
+	h, err := NewHandler(store, makeTagOptions(), nil,
+		executor.NewEngine(store, tally.NewTestScope("test", nil)), nil, nil,
+		nil, cfg, nil, tally.NewTestScope("", nil))
+	require.NoError(t, err, "unable to setup handler")
+	require.NoError(t, h.RegisterRoutes())
+
+	tests := []struct {
+		url          string
+		token        string
+		expectedCode int
+	}{
+		{url: healthURL, expectedCode: http.StatusOK},
+		{url: routesURL, expectedCode: http.StatusUnauthorized},
+		{url: routesURL, token: "reader-token", expectedCode: http.StatusForbidden},
+		{url: routesURL, token: "admin-token", expectedCode: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		req, _ := http.NewRequest("GET", test.url, nil)
+		if test.token != "" {
+			req.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		res := httptest.NewRecorder()
+		h.Router().ServeHTTP(res, req)
+		assert.Equal(t, test.expectedCode, res.Code, "url=%s token=%s", test.url, test.token)
+	}
+}
+
 func TestCORSMiddleware(t *testing.T) {
 	logging.InitWithCores(nil)
 
@@ -0,0 +1,157 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package auth
+
+import (
+	"crypto/sha256"
+	"errors"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	bearerPrefix = "Bearer "
+)
+
+var (
+	errInvalidToken       = errors.New("invalid bearer token")
+	errInvalidCredentials = errors.New("invalid username or password")
+)
+
+// Authenticator authenticates the caller of requests.
+type Authenticator interface {
+	// Authenticate returns the identity of the caller of the request, nil if
+	// the request carries no credentials of the authenticator and an error
+	// if the credentials are invalid.
+	Authenticate(r *http.Request) (*Identity, error)
+}
+
+type tokenAuthenticator struct {
+	identities map[[sha256.Size]byte]*Identity
+}
+
+// NewTokenAuthenticator returns an authenticator of static bearer tokens,
+// keyed by token.
+func NewTokenAuthenticator(identities map[string]*Identity) Authenticator {
+	a := &tokenAuthenticator{
+		identities: make(map[[sha256.Size]byte]*Identity, len(identities)),
+	}
+	for token, identity := range identities {
+		// Tokens are looked up by their digest so the lookup does not
+		// depend on how much of a token matches a configured token.
+		a.identities[sha256.Sum256([]byte(token))] = identity
+	}
+	return a
+}
+
+func (a *tokenAuthenticator) Authenticate(r *http.Request) (*Identity, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return nil, nil
+	}
+	token := strings.TrimPrefix(header, bearerPrefix)
+	identity, ok := a.identities[sha256.Sum256([]byte(token))]
+	if !ok {
+		return nil, errInvalidToken
+	}
+	return identity, nil
+}
+
+// BasicUser is a user authenticated with basic auth.
+type BasicUser struct {
+	// Identity is the identity of the user.
+	Identity *Identity
+
+	// PasswordHash is the bcrypt hash of the password of the user.
+	PasswordHash []byte
+}
+
+type basicAuthenticator struct {
+	users map[string]BasicUser
+}
+
+// NewBasicAuthenticator returns an authenticator of basic auth users,
+// keyed by username.
+func NewBasicAuthenticator(users map[string]BasicUser) Authenticator {
+	return &basicAuthenticator{users: users}
+}
+
+func (a *basicAuthenticator) Authenticate(r *http.Request) (*Identity, error) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return nil, nil
+	}
+	user, ok := a.users[username]
+	if !ok {
+		return nil, errInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(password)); err != nil {
+		return nil, errInvalidCredentials
+	}
+	return user.Identity, nil
+}
+
+type certificateAuthenticator struct {
+	identities map[string]*Identity
+}
+
+// NewCertificateAuthenticator returns an authenticator of verified TLS
+// client certificates, keyed by the common name of their subject.
+func NewCertificateAuthenticator(identities map[string]*Identity) Authenticator {
+	return &certificateAuthenticator{identities: identities}
+}
+
+func (a *certificateAuthenticator) Authenticate(r *http.Request) (*Identity, error) {
+	// Only the certificates verified against the client CAs of the server
+	// identify the caller, unverified certificates are ignored.
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 ||
+		len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+	identity, ok := a.identities[r.TLS.VerifiedChains[0][0].Subject.CommonName]
+	if !ok {
+		return nil, nil
+	}
+	return identity, nil
+}
+
+type multiAuthenticator []Authenticator
+
+// NewMultiAuthenticator returns an authenticator that authenticates requests
+// with the first of the authenticators the request carries credentials of.
+func NewMultiAuthenticator(authenticators ...Authenticator) Authenticator {
+	return multiAuthenticator(authenticators)
+}
+
+func (a multiAuthenticator) Authenticate(r *http.Request) (*Identity, error) {
+	for _, authenticator := range a {
+		identity, err := authenticator.Authenticate(r)
+		if err != nil {
+			return nil, err
+		}
+		if identity != nil {
+			return identity, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestRequest() *http.Request {
+	return httptest.NewRequest(http.MethodGet, "/", nil)
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	foo := &Identity{Name: "foo", Roles: []Role{RoleRead}}
+	a := NewTokenAuthenticator(map[string]*Identity{"secret": foo})
+
+	req := newTestRequest()
+	identity, err := a.Authenticate(req)
+	require.NoError(t, err)
+	assert.Nil(t, identity)
+
+	req.Header.Set("Authorization", "Bearer secret")
+	identity, err = a.Authenticate(req)
+	require.NoError(t, err)
+	assert.Equal(t, foo, identity)
+
+	req.Header.Set("Authorization", "Bearer other")
+	_, err = a.Authenticate(req)
+	assert.Error(t, err)
+}
+
+func TestBasicAuthenticator(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	foo := &Identity{Name: "foo", Roles: []Role{RoleRead}}
+	a := NewBasicAuthenticator(map[string]BasicUser{
+		"foo": {Identity: foo, PasswordHash: hash},
+	})
+
+	req := newTestRequest()
+	identity, err := a.Authenticate(req)
+	require.NoError(t, err)
+	assert.Nil(t, identity)
+
+	req.SetBasicAuth("foo", "secret")
+	identity, err = a.Authenticate(req)
+	require.NoError(t, err)
+	assert.Equal(t, foo, identity)
+
+	req.SetBasicAuth("foo", "other")
+	_, err = a.Authenticate(req)
+	assert.Error(t, err)
+
+	req.SetBasicAuth("bar", "secret")
+	_, err = a.Authenticate(req)
+	assert.Error(t, err)
+}
+
+func TestCertificateAuthenticator(t *testing.T) {
+	foo := &Identity{Name: "foo", Roles: []Role{RoleRead}}
+	a := NewCertificateAuthenticator(map[string]*Identity{"foo": foo})
+
+	newCert := func(commonName string) *x509.Certificate {
+		return &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	}
+
+	req := newTestRequest()
+	identity, err := a.Authenticate(req)
+	require.NoError(t, err)
+	assert.Nil(t, identity)
+
+	// Unverified certificates are ignored.
+	req.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{newCert("foo")},
+	}
+	identity, err = a.Authenticate(req)
+	require.NoError(t, err)
+	assert.Nil(t, identity)
+
+	req.TLS.VerifiedChains = [][]*x509.Certificate{{newCert("foo")}}
+	identity, err = a.Authenticate(req)
+	require.NoError(t, err)
+	assert.Equal(t, foo, identity)
+
+	req.TLS.VerifiedChains = [][]*x509.Certificate{{newCert("bar")}}
+	identity, err = a.Authenticate(req)
+	require.NoError(t, err)
+	assert.Nil(t, identity)
+}
+
+func TestMultiAuthenticator(t *testing.T) {
+	foo := &Identity{Name: "foo", Roles: []Role{RoleRead}}
+	bar := &Identity{Name: "bar", Roles: []Role{RoleWrite}}
+	a := NewMultiAuthenticator(
+		NewTokenAuthenticator(map[string]*Identity{"secret": foo}),
+		NewCertificateAuthenticator(map[string]*Identity{"bar": bar}),
+	)
+
+	req := newTestRequest()
+	identity, err := a.Authenticate(req)
+	require.NoError(t, err)
+	assert.Nil(t, identity)
+
+	req.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{
+			{{Subject: pkix.Name{CommonName: "bar"}}},
+		},
+	}
+	identity, err = a.Authenticate(req)
+	require.NoError(t, err)
+	assert.Equal(t, bar, identity)
+
+	req.Header.Set("Authorization", "Bearer secret")
+	identity, err = a.Authenticate(req)
+	require.NoError(t, err)
+	assert.Equal(t, foo, identity)
+
+	req.Header.Set("Authorization", "Bearer other")
+	_, err = a.Authenticate(req)
+	assert.Error(t, err)
+}
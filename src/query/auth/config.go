@@ -0,0 +1,139 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	errNoAuthenticators = errors.New("auth requires at least one of tokens, users or certificates")
+)
+
+// Configuration is the configuration of the authentication and authorization
+// of requests.
+type Configuration struct {
+	// Tokens are the static bearer tokens callers authenticate with.
+	Tokens []TokenConfiguration `yaml:"tokens"`
+
+	// Users are the basic auth users callers authenticate as.
+	Users []UserConfiguration `yaml:"users"`
+
+	// Certificates are the verified TLS client certificates callers
+	// authenticate with, requires the server to verify client certificates.
+	Certificates []CertificateConfiguration `yaml:"certificates"`
+
+	// AnonymousRoles are the roles granted to requests carrying no
+	// credentials, which are rejected if not set.
+	AnonymousRoles []Role `yaml:"anonymousRoles"`
+}
+
+// IdentityConfiguration is the configuration of the roles and tenant of an
+// identity.
+type IdentityConfiguration struct {
+	// Roles are the roles granted to the identity.
+	Roles []Role `yaml:"roles" validate:"nonzero"`
+
+	// Tenant is the tenant the identity is restricted to, if any.
+	Tenant string `yaml:"tenant"`
+}
+
+func (c IdentityConfiguration) newIdentity(name string) *Identity {
+	return &Identity{Name: name, Roles: c.Roles, Tenant: c.Tenant}
+}
+
+// TokenConfiguration is the configuration of a static bearer token.
+type TokenConfiguration struct {
+	// Name is the name of the identity of the token.
+	Name string `yaml:"name" validate:"nonzero"`
+
+	// Token is the bearer token.
+	Token string `yaml:"token" validate:"nonzero"`
+
+	IdentityConfiguration `yaml:",inline"`
+}
+
+// UserConfiguration is the configuration of a basic auth user.
+type UserConfiguration struct {
+	// Username is the username of the user.
+	Username string `yaml:"username" validate:"nonzero"`
+
+	// PasswordHash is the bcrypt hash of the password of the user.
+	PasswordHash string `yaml:"passwordHash" validate:"nonzero"`
+
+	IdentityConfiguration `yaml:",inline"`
+}
+
+// CertificateConfiguration is the configuration of a TLS client certificate.
+type CertificateConfiguration struct {
+	// CommonName is the common name of the subject of the certificate.
+	CommonName string `yaml:"commonName" validate:"nonzero"`
+
+	IdentityConfiguration `yaml:",inline"`
+}
+
+// NewAuthorizer returns a new authorizer from the configuration.
+func (c Configuration) NewAuthorizer() (*Authorizer, error) {
+	var authenticators []Authenticator
+	if len(c.Tokens) > 0 {
+		identities := make(map[string]*Identity, len(c.Tokens))
+		for _, t := range c.Tokens {
+			if _, ok := identities[t.Token]; ok {
+				return nil, fmt.Errorf("duplicate token of: %s", t.Name)
+			}
+			identities[t.Token] = t.newIdentity(t.Name)
+		}
+		authenticators = append(authenticators, NewTokenAuthenticator(identities))
+	}
+	if len(c.Users) > 0 {
+		users := make(map[string]BasicUser, len(c.Users))
+		for _, u := range c.Users {
+			if _, ok := users[u.Username]; ok {
+				return nil, fmt.Errorf("duplicate user: %s", u.Username)
+			}
+			if _, err := bcrypt.Cost([]byte(u.PasswordHash)); err != nil {
+				return nil, fmt.Errorf("invalid password hash of user %s: %v", u.Username, err)
+			}
+			users[u.Username] = BasicUser{
+				Identity:     u.newIdentity(u.Username),
+				PasswordHash: []byte(u.PasswordHash),
+			}
+		}
+		authenticators = append(authenticators, NewBasicAuthenticator(users))
+	}
+	if len(c.Certificates) > 0 {
+		identities := make(map[string]*Identity, len(c.Certificates))
+		for _, cert := range c.Certificates {
+			if _, ok := identities[cert.CommonName]; ok {
+				return nil, fmt.Errorf("duplicate certificate: %s", cert.CommonName)
+			}
+			identities[cert.CommonName] = cert.newIdentity(cert.CommonName)
+		}
+		authenticators = append(authenticators, NewCertificateAuthenticator(identities))
+	}
+	if len(authenticators) == 0 && len(c.AnonymousRoles) == 0 {
+		return nil, errNoAuthenticators
+	}
+	return NewAuthorizer(NewMultiAuthenticator(authenticators...), c.AnonymousRoles), nil
+}
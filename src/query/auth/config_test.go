@@ -0,0 +1,86 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestConfigurationNewAuthorizer(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	str := `
+tokens:
+  - name: prometheus
+    token: secret
+    roles: [read, write]
+    tenant: foo
+users:
+  - username: operator
+    passwordHash: ` + string(hash) + `
+    roles: [admin]
+certificates:
+  - commonName: agent
+    roles: [write]
+anonymousRoles: [read]
+`
+	var cfg Configuration
+	require.NoError(t, yaml.Unmarshal([]byte(str), &cfg))
+
+	require.Equal(t, 1, len(cfg.Tokens))
+	assert.Equal(t, []Role{RoleRead, RoleWrite}, cfg.Tokens[0].Roles)
+	assert.Equal(t, "foo", cfg.Tokens[0].Tenant)
+	require.Equal(t, 1, len(cfg.Users))
+	assert.Equal(t, []Role{RoleAdmin}, cfg.Users[0].Roles)
+	assert.Equal(t, []Role{RoleRead}, cfg.AnonymousRoles)
+
+	a, err := cfg.NewAuthorizer()
+	require.NoError(t, err)
+	require.NotNil(t, a.anonymous)
+	assert.Equal(t, []Role{RoleRead}, a.anonymous.Roles)
+}
+
+func TestConfigurationInvalid(t *testing.T) {
+	var cfg Configuration
+	assert.Error(t, yaml.Unmarshal([]byte("anonymousRoles: [owner]"), &cfg))
+
+	_, err := Configuration{}.NewAuthorizer()
+	assert.Error(t, err)
+
+	_, err = Configuration{
+		Users: []UserConfiguration{{Username: "foo", PasswordHash: "secret"}},
+	}.NewAuthorizer()
+	assert.Error(t, err)
+
+	_, err = Configuration{
+		Tokens: []TokenConfiguration{
+			{Name: "foo", Token: "secret"},
+			{Name: "bar", Token: "secret"},
+		},
+	}.NewAuthorizer()
+	assert.Error(t, err)
+}
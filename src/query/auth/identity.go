@@ -0,0 +1,101 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package auth provides the authentication and role based authorization of
+// requests to the coordinator HTTP API.
+package auth
+
+import (
+	"context"
+	"fmt"
+)
+
+// Role is a role granted to an identity.
+type Role string
+
+const (
+	// RoleRead grants reading and querying series.
+	RoleRead Role = "read"
+	// RoleWrite grants writing series.
+	RoleWrite Role = "write"
+	// RoleAdmin grants managing placements, namespaces, topics, rules and
+	// runtime configuration, and implies every other role.
+	RoleAdmin Role = "admin"
+)
+
+var validRoles = []Role{
+	RoleRead,
+	RoleWrite,
+	RoleAdmin,
+}
+
+func (r Role) String() string {
+	return string(r)
+}
+
+// UnmarshalYAML unmarshals a role.
+func (r *Role) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	for _, valid := range validRoles {
+		if str == valid.String() {
+			*r = valid
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid Role '%s' valid roles are: %v", str, validRoles)
+}
+
+type contextKey struct{}
+
+// Identity is the authenticated identity of a caller.
+type Identity struct {
+	// Name is the name of the identity.
+	Name string
+
+	// Roles are the roles granted to the identity.
+	Roles []Role
+
+	// Tenant is the tenant the identity is restricted to, if any.
+	Tenant string
+}
+
+// HasRole returns whether the identity is granted the role.
+func (i *Identity) HasRole(role Role) bool {
+	for _, r := range i.Roles {
+		if r == role || r == RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// NewContext returns a context carrying the identity.
+func NewContext(ctx context.Context, i *Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, i)
+}
+
+// FromContext returns the identity carried by the context, if any.
+func FromContext(ctx context.Context) (*Identity, bool) {
+	i, ok := ctx.Value(contextKey{}).(*Identity)
+	return i, ok && i != nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/m3db/m3/src/query/util/logging"
+	"github.com/m3db/m3/src/x/net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	anonymousName = "anonymous"
+)
+
+var (
+	errUnauthenticated = errors.New("request must be authenticated")
+)
+
+// Authorizer authenticates the caller of requests and authorizes requests by
+// the roles granted to the caller.
+type Authorizer struct {
+	authenticator Authenticator
+	anonymous     *Identity
+}
+
+// NewAuthorizer returns a new authorizer of the callers authenticated by the
+// authenticator, requests carrying no credentials are granted the anonymous
+// roles.
+func NewAuthorizer(authenticator Authenticator, anonymousRoles []Role) *Authorizer {
+	a := &Authorizer{authenticator: authenticator}
+	if len(anonymousRoles) > 0 {
+		a.anonymous = &Identity{Name: anonymousName, Roles: anonymousRoles}
+	}
+	return a
+}
+
+// Middleware returns a handler that serves requests with the identity of
+// their caller in the request context, rejecting requests of unauthenticated
+// callers or callers not granted the role. Requests are served as is by a
+// nil authorizer.
+func (a *Authorizer) Middleware(role Role, next http.Handler) http.Handler {
+	if a == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := logging.WithContext(r.Context())
+		identity, err := a.authenticator.Authenticate(r)
+		if err == nil && identity == nil {
+			if identity = a.anonymous; identity == nil {
+				err = errUnauthenticated
+			}
+		}
+		if err != nil {
+			logger.Warn("unauthenticated request",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("remoteAddr", r.RemoteAddr),
+				zap.Error(err))
+			w.Header().Set("WWW-Authenticate", `Bearer realm="m3coordinator"`)
+			xhttp.Error(w, err, http.StatusUnauthorized)
+			return
+		}
+		if !identity.HasRole(role) {
+			logger.Warn("unauthorized request",
+				zap.String("identity", identity.Name),
+				zap.String("role", role.String()),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("remoteAddr", r.RemoteAddr))
+			xhttp.Error(w, fmt.Errorf("%s is not granted the %s role",
+				identity.Name, role), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), identity)))
+	})
+}
+
+// Audit returns a handler that logs the mutating requests it serves with the
+// identity of their caller and the status of the response.
+func Audit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		sw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+
+		name := anonymousName
+		if identity, ok := FromContext(r.Context()); ok {
+			name = identity.Name
+		}
+		logging.WithContext(r.Context()).Info("audit",
+			zap.String("identity", name),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.String("remoteAddr", r.RemoteAddr),
+			zap.Int("status", sw.status))
+	})
+}
+
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3/src/query/util/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityHasRole(t *testing.T) {
+	reader := &Identity{Name: "reader", Roles: []Role{RoleRead}}
+	assert.True(t, reader.HasRole(RoleRead))
+	assert.False(t, reader.HasRole(RoleWrite))
+	assert.False(t, reader.HasRole(RoleAdmin))
+
+	admin := &Identity{Name: "admin", Roles: []Role{RoleAdmin}}
+	assert.True(t, admin.HasRole(RoleRead))
+	assert.True(t, admin.HasRole(RoleWrite))
+	assert.True(t, admin.HasRole(RoleAdmin))
+}
+
+func TestAuthorizerMiddleware(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	authenticator := NewTokenAuthenticator(map[string]*Identity{
+		"reader": {Name: "reader", Roles: []Role{RoleRead}},
+		"writer": {Name: "writer", Roles: []Role{RoleRead, RoleWrite}},
+	})
+
+	tests := []struct {
+		name             string
+		anonymousRoles   []Role
+		token            string
+		role             Role
+		expectedCode     int
+		expectedIdentity string
+	}{
+		{
+			name:             "granted",
+			token:            "reader",
+			role:             RoleRead,
+			expectedCode:     http.StatusOK,
+			expectedIdentity: "reader",
+		},
+		{
+			name:         "not granted",
+			token:        "reader",
+			role:         RoleWrite,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "invalid credentials",
+			token:        "other",
+			role:         RoleRead,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "no credentials",
+			role:         RoleRead,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:             "anonymous granted",
+			anonymousRoles:   []Role{RoleRead},
+			role:             RoleRead,
+			expectedCode:     http.StatusOK,
+			expectedIdentity: anonymousName,
+		},
+		{
+			name:           "anonymous not granted",
+			anonymousRoles: []Role{RoleRead},
+			role:           RoleWrite,
+			expectedCode:   http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				served   bool
+				identity string
+			)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				served = true
+				if i, ok := FromContext(r.Context()); ok {
+					identity = i.Name
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			recorder := httptest.NewRecorder()
+			a := NewAuthorizer(authenticator, test.anonymousRoles)
+			a.Middleware(test.role, next).ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedCode == http.StatusOK, served)
+			assert.Equal(t, test.expectedIdentity, identity)
+		})
+	}
+}
+
+func TestNilAuthorizerMiddleware(t *testing.T) {
+	var (
+		a      *Authorizer
+		served bool
+	)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+	})
+
+	a.Middleware(RoleAdmin, next).ServeHTTP(httptest.NewRecorder(),
+		httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.True(t, served)
+}
+
+func TestAuditRecordsStatus(t *testing.T) {
+	logging.InitWithCores(nil)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req = req.WithContext(NewContext(req.Context(), &Identity{Name: "admin"}))
+	recorder := httptest.NewRecorder()
+
+	sw := &statusResponseWriter{ResponseWriter: recorder, status: http.StatusOK}
+	Audit(next).ServeHTTP(sw, req)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, http.StatusCreated, sw.status)
+}
@@ -32,6 +32,7 @@ import (
 
 	clusterclient "github.com/m3db/m3/src/cluster/client"
 	etcdclient "github.com/m3db/m3/src/cluster/client/etcd"
+	fileclient "github.com/m3db/m3/src/cluster/client/file"
 	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
 	dbconfig "github.com/m3db/m3/src/cmd/services/m3dbnode/config"
 	"github.com/m3db/m3/src/cmd/services/m3query/config"
@@ -49,9 +50,11 @@ import (
 	"github.com/m3db/m3/src/query/storage/m3"
 	"github.com/m3db/m3/src/query/storage/remote"
 	"github.com/m3db/m3/src/query/stores/m3db"
+	"github.com/m3db/m3/src/query/tenant"
 	tsdbRemote "github.com/m3db/m3/src/query/tsdb/remote"
 	"github.com/m3db/m3/src/query/util/logging"
 	"github.com/m3db/m3/src/x/serialize"
+	xtls "github.com/m3db/m3/src/x/tls"
 	"github.com/m3db/m3x/clock"
 	xconfig "github.com/m3db/m3x/config"
 	"github.com/m3db/m3x/ident"
@@ -138,6 +141,7 @@ func Run(runOpts RunOptions) {
 		backendStorage storage.Storage
 		clusterClient  clusterclient.Client
 		downsampler    downsample.Downsampler
+		tenants        *tenant.Registry
 		enabled        bool
 	)
 
@@ -176,6 +180,9 @@ func Run(runOpts RunOptions) {
 		if !enabled {
 			logger.Fatal("need remote clients for grpc backend")
 		}
+		if cfg.Tenants != nil {
+			logger.Fatal("tenants are not supported with the grpc backend")
+		}
 
 		logger.Info("setup grpc backend")
 	} else {
@@ -185,7 +192,7 @@ func Run(runOpts RunOptions) {
 		}
 
 		var cleanup cleanupFn
-		backendStorage, clusterClient, downsampler, cleanup, err = newM3DBStorage(
+		backendStorage, clusterClient, downsampler, tenants, cleanup, err = newM3DBStorage(
 			runOpts,
 			cfg,
 			tagOptions,
@@ -200,12 +207,17 @@ func Run(runOpts RunOptions) {
 			logger.Fatal("unable to setup m3db backend", zap.Error(err))
 		}
 		defer cleanup()
+
+		if tenants != nil {
+			// Route the requests made for tenants to their cluster namespaces.
+			backendStorage = tenant.NewStorage(backendStorage)
+		}
 	}
 
 	engine := executor.NewEngine(backendStorage, scope.SubScope("engine"))
 
 	handler, err := httpd.NewHandler(backendStorage, tagOptions, downsampler, engine,
-		m3dbClusters, clusterClient, cfg, runOpts.DBConfig, scope)
+		m3dbClusters, tenants, clusterClient, cfg, runOpts.DBConfig, scope)
 	if err != nil {
 		logger.Fatal("unable to set up handlers", zap.Error(err))
 	}
@@ -227,9 +239,24 @@ func Run(runOpts RunOptions) {
 		}
 	}()
 
+	serveFn := srv.ListenAndServe
+	if cfg.TLS != nil {
+		tlsConfig, err := cfg.TLS.NewServerConfig()
+		if err != nil {
+			logger.Fatal("unable to create tls config", zap.Error(err))
+		}
+		listener, err := xtls.Listen(listenAddress, tlsConfig)
+		if err != nil {
+			logger.Fatal("unable to listen", zap.String("address", listenAddress), zap.Error(err))
+		}
+		serveFn = func() error {
+			return srv.Serve(listener)
+		}
+	}
+
 	go func() {
 		logger.Info("starting server", zap.String("address", listenAddress))
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := serveFn(); err != nil && err != http.ErrServerClosed {
 			logger.Fatal("server error while listening",
 				zap.String("address", listenAddress), zap.Error(err))
 		}
@@ -295,7 +322,7 @@ func newM3DBStorage(
 	instrumentOptions instrument.Options,
 	readWorkerPool xsync.PooledWorkerPool,
 	writeWorkerPool xsync.PooledWorkerPool,
-) (storage.Storage, clusterclient.Client, downsample.Downsampler, cleanupFn, error) {
+) (storage.Storage, clusterclient.Client, downsample.Downsampler, *tenant.Registry, cleanupFn, error) {
 	var (
 		clusterClient       clusterclient.Client
 		clusterClientWaitCh <-chan struct{}
@@ -304,19 +331,46 @@ func newM3DBStorage(
 		// Only use a cluster client if we are going to receive one, that
 		// way passing nil to httpd NewHandler disables the endpoints entirely
 		clusterClientDoneCh := make(chan struct{}, 1)
-		clusterClientWaitCh = clusterClientDoneCh
 		clusterClient = m3dbcluster.NewAsyncClient(func() (clusterclient.Client, error) {
 			return <-clusterClientCh, nil
 		}, clusterClientDoneCh)
+
+		// Close the wait channel once done since the downsamplers of tenants
+		// also wait on the cluster client.
+		clusterClientReadyCh := make(chan struct{})
+		clusterClientWaitCh = clusterClientReadyCh
+		go func() {
+			<-clusterClientDoneCh
+			close(clusterClientReadyCh)
+		}()
 	} else {
-		var etcdCfg *etcdclient.Configuration
+		var (
+			etcdCfg  *etcdclient.Configuration
+			localCfg *fileclient.Configuration
+		)
 		switch {
+		case cfg.ClusterManagement != nil && cfg.ClusterManagement.Local != nil:
+			localCfg = cfg.ClusterManagement.Local
+
 		case cfg.ClusterManagement != nil:
 			etcdCfg = &cfg.ClusterManagement.Etcd
 
 		case len(cfg.Clusters) == 1 &&
 			cfg.Clusters[0].Client.EnvironmentConfig.Service != nil:
 			etcdCfg = cfg.Clusters[0].Client.EnvironmentConfig.Service
+
+		case len(cfg.Clusters) == 1 &&
+			cfg.Clusters[0].Client.EnvironmentConfig.Local != nil:
+			localCfg = cfg.Clusters[0].Client.EnvironmentConfig.Local
+		}
+
+		if localCfg != nil {
+			// We resolved a local configuration for cluster management endpoints
+			var err error
+			clusterClient, err = localCfg.NewClient(instrumentOptions)
+			if err != nil {
+				return nil, nil, nil, nil, nil, errors.Wrap(err, "unable to create cluster management local client")
+			}
 		}
 
 		if etcdCfg != nil {
@@ -327,7 +381,7 @@ func newM3DBStorage(
 			)
 			clusterClient, err = etcdclient.NewConfigServiceClient(clusterSvcClientOpts)
 			if err != nil {
-				return nil, nil, nil, nil, errors.Wrap(err, "unable to create cluster management etcd client")
+				return nil, nil, nil, nil, nil, errors.Wrap(err, "unable to create cluster management etcd client")
 			}
 		}
 	}
@@ -342,40 +396,61 @@ func newM3DBStorage(
 		writeWorkerPool,
 	)
 	if err != nil {
-		return nil, nil, nil, nil, errors.Wrap(err, "unable to set up storages")
+		return nil, nil, nil, nil, nil, errors.Wrap(err, "unable to set up storages")
 	}
 
-	var (
-		namespaces  = clusters.ClusterNamespaces()
-		downsampler downsample.Downsampler
-	)
-	if n := namespaces.NumAggregatedClusterNamespaces(); n > 0 {
+	newClustersDownsampler := func(
+		clusters m3.Clusters,
+		store storage.Storage,
+		rulesNamespace string,
+	) (downsample.Downsampler, error) {
+		namespaces := clusters.ClusterNamespaces()
+		n := namespaces.NumAggregatedClusterNamespaces()
+		if n == 0 {
+			return nil, nil
+		}
+
 		logger.Info("configuring downsampler to use with aggregated cluster namespaces",
-			zap.Int("numAggregatedClusterNamespaces", n))
+			zap.Int("numAggregatedClusterNamespaces", n),
+			zap.String("rulesNamespace", rulesNamespace))
 		autoMappingRules, err := newDownsamplerAutoMappingRules(namespaces)
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, err
 		}
 
 		newDownsamplerFn := func() (downsample.Downsampler, error) {
 			return newDownsampler(cfg.Downsample, clusterClient,
-				fanoutStorage, autoMappingRules, tagOptions, instrumentOptions)
+				store, autoMappingRules, rulesNamespace, tagOptions, instrumentOptions)
 		}
 
 		if clusterClientWaitCh != nil {
 			// Need to wait before constructing and instead return an async downsampler
 			// since the cluster client will return errors until it's initialized itself
 			// and will fail constructing the downsampler consequently
-			downsampler = downsample.NewAsyncDownsampler(func() (downsample.Downsampler, error) {
+			return downsample.NewAsyncDownsampler(func() (downsample.Downsampler, error) {
 				<-clusterClientWaitCh
 				return newDownsamplerFn()
-			}, nil)
-		} else {
-			// Otherwise we already have a client and can immediately construct the downsampler
-			downsampler, err = newDownsamplerFn()
-			if err != nil {
-				return nil, nil, nil, nil, err
-			}
+			}, nil), nil
+		}
+
+		// Otherwise we already have a client and can immediately construct the downsampler
+		return newDownsamplerFn()
+	}
+
+	downsampler, err := newClustersDownsampler(clusters, fanoutStorage, "")
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	var (
+		tenants        *tenant.Registry
+		tenantClusters []m3.Clusters
+	)
+	if cfg.Tenants != nil {
+		tenants, tenantClusters, err = newTenants(cfg, tagOptions, logger,
+			readWorkerPool, writeWorkerPool, newClustersDownsampler)
+		if err != nil {
+			return nil, nil, nil, nil, nil, errors.Wrap(err, "unable to set up tenants")
 		}
 	}
 
@@ -386,16 +461,97 @@ func newM3DBStorage(
 			logger.Error("error during storage cleanup", zap.Error(lastErr))
 		}
 
-		if err := clusters.Close(); err != nil {
-			lastErr = errors.Wrap(err, "unable to close M3DB cluster sessions")
-			// Make sure the previous error is at least logged
-			logger.Error("error during cluster cleanup", zap.Error(err))
+		for _, c := range append([]m3.Clusters{clusters}, tenantClusters...) {
+			if err := c.Close(); err != nil {
+				lastErr = errors.Wrap(err, "unable to close M3DB cluster sessions")
+				// Make sure the previous error is at least logged
+				logger.Error("error during cluster cleanup", zap.Error(err))
+			}
 		}
 
 		return lastErr
 	}
 
-	return fanoutStorage, clusterClient, downsampler, cleanup, nil
+	return fanoutStorage, clusterClient, downsampler, tenants, cleanup, nil
+}
+
+type newClustersDownsamplerFn func(
+	clusters m3.Clusters,
+	store storage.Storage,
+	rulesNamespace string,
+) (downsample.Downsampler, error)
+
+func newTenants(
+	cfg config.Configuration,
+	tagOptions models.TagOptions,
+	logger *zap.Logger,
+	readWorkerPool xsync.PooledWorkerPool,
+	writeWorkerPool xsync.PooledWorkerPool,
+	newClustersDownsampler newClustersDownsamplerFn,
+) (*tenant.Registry, []m3.Clusters, error) {
+	var (
+		tenants        = make([]*tenant.Tenant, 0, len(cfg.Tenants.Tenants))
+		tenantClusters = make([]m3.Clusters, 0, len(cfg.Tenants.Tenants))
+		closeAll       = func() {
+			for _, clusters := range tenantClusters {
+				if err := clusters.Close(); err != nil {
+					logger.Error("error closing tenant clusters", zap.Error(err))
+				}
+			}
+		}
+	)
+	for _, tenantCfg := range cfg.Tenants.Tenants {
+		clusters, err := tenantCfg.Clusters.NewClusters(m3.ClustersStaticConfigurationOptions{
+			AsyncSessions: true,
+		})
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("unable to connect to clusters of tenant %s: %v",
+				tenantCfg.ID, err)
+		}
+		tenantClusters = append(tenantClusters, clusters)
+
+		for _, namespace := range clusters.ClusterNamespaces() {
+			logger.Info("resolved tenant cluster namespace",
+				zap.String("tenant", tenantCfg.ID),
+				zap.String("namespace", namespace.NamespaceID().String()))
+		}
+
+		store := m3.NewStorage(clusters, readWorkerPool, writeWorkerPool,
+			tagOptions, cfg.TimeStitchingPolicy)
+
+		rulesNamespace := tenantCfg.RulesNamespace
+		if rulesNamespace == "" {
+			rulesNamespace = tenantCfg.ID
+		}
+		downsampler, err := newClustersDownsampler(clusters, store, rulesNamespace)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("unable to create downsampler of tenant %s: %v",
+				tenantCfg.ID, err)
+		}
+
+		limits := tenantCfg.Limits
+		if limits == nil {
+			limits = &cfg.Limits
+		}
+
+		tenants = append(tenants, &tenant.Tenant{
+			ID:          tenantCfg.ID,
+			Clusters:    clusters,
+			Storage:     store,
+			Downsampler: downsampler,
+			Limits:      limits,
+		})
+	}
+
+	registry, err := tenant.NewRegistry(cfg.Tenants.Header, cfg.Tenants.Required, tenants)
+	if err != nil {
+		closeAll()
+		return nil, nil, err
+	}
+
+	return registry, tenantClusters, nil
 }
 
 func newDownsampler(
@@ -403,6 +559,7 @@ func newDownsampler(
 	clusterManagementClient clusterclient.Client,
 	storage storage.Storage,
 	autoMappingRules []downsample.MappingRule,
+	rulesNamespace string,
 	tagOptions models.TagOptions,
 	instrumentOpts instrument.Options,
 ) (downsample.Downsampler, error) {
@@ -431,6 +588,7 @@ func newDownsampler(
 	downsampler, err := cfg.NewDownsampler(downsample.DownsamplerOptions{
 		Storage:          storage,
 		RulesKVStore:     kvStore,
+		RulesNamespace:   rulesNamespace,
 		AutoMappingRules: autoMappingRules,
 		ClockOptions:     clock.NewOptions(),
 		// TODO: remove after https://github.com/m3db/m3/issues/992 is fixed
@@ -554,6 +712,7 @@ func newStorages(
 		readWorkerPool,
 		writeWorkerPool,
 		tagOptions,
+		cfg.TimeStitchingPolicy,
 	)
 	stores := []storage.Storage{localStorage}
 	remoteEnabled := false
@@ -113,6 +113,7 @@ func handleFetchResponses(requests []execution.Request) (*storage.FetchResult, e
 		}
 
 		result.SeriesList = append(result.SeriesList, fetchreq.result.SeriesList...)
+		result.ResolutionRanges = append(result.ResolutionRanges, fetchreq.result.ResolutionRanges...)
 	}
 
 	return result, nil
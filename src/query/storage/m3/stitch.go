@@ -0,0 +1,272 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/query/storage"
+	xerrors "github.com/m3db/m3x/errors"
+)
+
+// TimeStitchingPolicy determines how a query time range is split across
+// the namespaces that retain it.
+type TimeStitchingPolicy uint
+
+const (
+	// TimeStitchingDisabled serves the whole query range from the namespaces
+	// that can completely fulfill it, preferring the finest resolution.
+	TimeStitchingDisabled TimeStitchingPolicy = iota
+	// TimeStitchingFinestResolution splits the query range into segments
+	// each served by the finest resolution namespace that retains it, e.g.
+	// unaggregated for recent data and aggregated for older data.
+	TimeStitchingFinestResolution
+
+	// DefaultTimeStitchingPolicy is the default time stitching policy.
+	DefaultTimeStitchingPolicy = TimeStitchingDisabled
+)
+
+var validTimeStitchingPolicies = []TimeStitchingPolicy{
+	TimeStitchingDisabled,
+	TimeStitchingFinestResolution,
+}
+
+func (p TimeStitchingPolicy) String() string {
+	switch p {
+	case TimeStitchingDisabled:
+		return "disabled"
+	case TimeStitchingFinestResolution:
+		return "finest_resolution"
+	default:
+		return "unknown"
+	}
+}
+
+// UnmarshalYAML unmarshals a time stitching policy.
+func (p *TimeStitchingPolicy) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	if str == "" {
+		*p = DefaultTimeStitchingPolicy
+		return nil
+	}
+	for _, valid := range validTimeStitchingPolicies {
+		if str == valid.String() {
+			*p = valid
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid TimeStitchingPolicy '%s' valid types are: %v",
+		str, validTimeStitchingPolicies)
+}
+
+// queryRangeSegment is a part of the query time range served by
+// a single namespace.
+type queryRangeSegment struct {
+	start     time.Time
+	end       time.Time
+	namespace ClusterNamespace
+}
+
+// resolveStitchedSegmentsForQuery splits the query range into segments
+// ordered by start time, each served by the finest resolution namespace
+// that retains it. Only the unaggregated namespace and aggregated namespaces
+// that downsample all metrics are considered. Returns no segments if the
+// range can be served by a single namespace.
+func (s *m3storage) resolveStitchedSegmentsForQuery(
+	start time.Time,
+	end time.Time,
+) []queryRangeSegment {
+	now := s.nowFn()
+
+	var r reusedAggregatedNamespaceSlices
+	r = s.aggregatedNamespaces(r, nil)
+	candidates := make([]ClusterNamespace, 0, len(r.completeAggregated)+1)
+	candidates = append(candidates, s.clusters.UnaggregatedClusterNamespace())
+	candidates = append(candidates, r.completeAggregated...)
+	sort.Stable(ClusterNamespacesByResolutionAsc(candidates))
+
+	var (
+		segments []queryRangeSegment
+		cursor   = end
+	)
+	for _, namespace := range candidates {
+		if !cursor.After(start) {
+			break
+		}
+		retainedStart := now.Add(-1 * namespace.Options().Attributes().Retention)
+		if !retainedStart.Before(cursor) {
+			// Retains nothing older than what finer namespaces already serve.
+			continue
+		}
+		segmentStart := retainedStart
+		if segmentStart.Before(start) {
+			segmentStart = start
+		}
+		segments = append(segments, queryRangeSegment{
+			start:     segmentStart,
+			end:       cursor,
+			namespace: namespace,
+		})
+		cursor = segmentStart
+	}
+
+	if len(segments) < 2 {
+		return nil
+	}
+
+	// Any range older than all retentions is served by the longest
+	// retained namespace, then order segments by start time.
+	segments[len(segments)-1].start = start
+	for i, j := 0, len(segments)-1; i < j; i, j = i+1, j-1 {
+		segments[i], segments[j] = segments[j], segments[i]
+	}
+	return segments
+}
+
+func resolutionRangesForSegments(segments []queryRangeSegment) []storage.ResolutionRange {
+	ranges := make([]storage.ResolutionRange, 0, len(segments))
+	for _, segment := range segments {
+		ranges = append(ranges, storage.ResolutionRange{
+			Start:      segment.start,
+			End:        segment.end,
+			Namespace:  segment.namespace.NamespaceID().String(),
+			Attributes: segment.namespace.Options().Attributes(),
+		})
+	}
+	return ranges
+}
+
+// stitchedResult accumulates the series iterators fetched for each segment
+// of a query and stitches the iterators of each series ID in time order.
+type stitchedResult struct {
+	sync.Mutex
+	segmentIters []encoding.SeriesIterators
+	stitched     []encoding.SeriesIterator
+	finalResult  encoding.MutableSeriesIterators
+	err          xerrors.MultiError
+
+	pools encoding.IteratorPools
+}
+
+func newStitchedResult(numSegments int, pools encoding.IteratorPools) *stitchedResult {
+	return &stitchedResult{
+		segmentIters: make([]encoding.SeriesIterators, numSegments),
+		pools:        pools,
+	}
+}
+
+func (r *stitchedResult) Add(
+	segmentIdx int,
+	iters encoding.SeriesIterators,
+	err error,
+) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err != nil {
+		r.err = r.err.Add(err)
+		return
+	}
+	r.segmentIters[segmentIdx] = iters
+}
+
+func (r *stitchedResult) FinalResult() (encoding.SeriesIterators, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err := r.err.LastError(); err != nil {
+		return nil, err
+	}
+	if r.finalResult != nil {
+		return r.finalResult, nil
+	}
+
+	var (
+		order    []string
+		bySeries = make(map[string][]encoding.SeriesIterator)
+	)
+	for _, iters := range r.segmentIters {
+		if iters == nil {
+			continue
+		}
+		for _, iter := range iters.Iters() {
+			id := iter.ID().String()
+			existing, ok := bySeries[id]
+			if !ok {
+				order = append(order, id)
+			}
+			bySeries[id] = append(existing, iter)
+		}
+	}
+
+	r.finalResult = r.pools.MutableSeriesIterators().Get(len(order))
+	r.finalResult.Reset(len(order))
+	for i, id := range order {
+		iters := bySeries[id]
+		if len(iters) == 1 {
+			r.finalResult.SetAt(i, iters[0])
+			continue
+		}
+		stitched := newStitchedSeriesIterator(iters)
+		r.stitched = append(r.stitched, stitched)
+		r.finalResult.SetAt(i, stitched)
+	}
+
+	return r.finalResult, nil
+}
+
+func (r *stitchedResult) Close() error {
+	r.Lock()
+	defer r.Unlock()
+
+	for i, iters := range r.segmentIters {
+		if iters != nil {
+			iters.Close()
+		}
+		r.segmentIters[i] = nil
+	}
+
+	// NB: the stitched iterators only own the replicas trimmed to the
+	// range of each segment, not the segment iterators they wrap.
+	for _, iter := range r.stitched {
+		iter.Close()
+	}
+	r.stitched = nil
+
+	if r.finalResult != nil {
+		// NB: the segment iterators own the series iterators referenced by
+		// the final result, so reset it before returning it to the pool to
+		// avoid closing them twice.
+		r.finalResult.Reset(0)
+		r.finalResult.Close()
+		r.finalResult = nil
+	}
+
+	r.err = xerrors.NewMultiError()
+	return nil
+}
@@ -0,0 +1,355 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/storage/index"
+	m3ts "github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3x/ident"
+	"github.com/m3db/m3x/sync"
+	xtime "github.com/m3db/m3x/time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func newTestStitchingStorage(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	now time.Time,
+) (*m3storage, testSessions) {
+	store, sessions := setup(t, ctrl)
+	s := store.(*m3storage)
+	s.stitchingPolicy = TimeStitchingFinestResolution
+	s.nowFn = func() time.Time { return now }
+	return s, sessions
+}
+
+func newTestStitchSeriesIter(
+	ctrl *gomock.Controller,
+	id string,
+	datapoints []m3ts.Datapoint,
+) encoding.SeriesIterator {
+	iter := encoding.NewMockSeriesIterator(ctrl)
+	iter.EXPECT().ID().Return(ident.StringID(id)).AnyTimes()
+	iter.EXPECT().Tags().
+		Return(ident.NewTagsIterator(ident.NewTags(ident.StringTag("foo", "bar")))).
+		AnyTimes()
+	iter.EXPECT().Err().Return(nil).AnyTimes()
+	iter.EXPECT().Close().AnyTimes()
+
+	calls := make([]*gomock.Call, 0, 2*len(datapoints)+1)
+	for _, dp := range datapoints {
+		calls = append(calls,
+			iter.EXPECT().Next().Return(true),
+			iter.EXPECT().Current().Return(dp, xtime.Second, nil))
+	}
+	calls = append(calls, iter.EXPECT().Next().Return(false).AnyTimes())
+	gomock.InOrder(calls...)
+	return iter
+}
+
+func TestTimeStitchingPolicyUnmarshalYAML(t *testing.T) {
+	for _, policy := range validTimeStitchingPolicies {
+		var parsed TimeStitchingPolicy
+		require.NoError(t, yaml.Unmarshal([]byte(policy.String()), &parsed))
+		assert.Equal(t, policy, parsed)
+	}
+
+	var parsed TimeStitchingPolicy
+	require.Error(t, yaml.Unmarshal([]byte("coarsest"), &parsed))
+}
+
+func TestResolveStitchedSegmentsForQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	s, _ := newTestStitchingStorage(t, ctrl, now)
+
+	// Served entirely by the unaggregated namespace.
+	assert.Nil(t, s.resolveStitchedSegmentsForQuery(now.Add(-time.Hour), now))
+
+	segments := s.resolveStitchedSegmentsForQuery(now.Add(-2*test1MonthRetention), now)
+	require.Len(t, segments, 2)
+	assert.Equal(t, "metrics_aggregated_5m:90d", segments[0].namespace.NamespaceID().String())
+	assert.Equal(t, now.Add(-2*test1MonthRetention), segments[0].start)
+	assert.Equal(t, now.Add(-test1MonthRetention), segments[0].end)
+	assert.Equal(t, "metrics_unaggregated", segments[1].namespace.NamespaceID().String())
+	assert.Equal(t, now.Add(-test1MonthRetention), segments[1].start)
+	assert.Equal(t, now, segments[1].end)
+
+	// Ranges older than all retentions are served by the longest retention.
+	segments = s.resolveStitchedSegmentsForQuery(now.Add(-2*testLongestRetention), now)
+	require.Len(t, segments, 3)
+	assert.Equal(t, "metrics_aggregated_10m:365d", segments[0].namespace.NamespaceID().String())
+	assert.Equal(t, now.Add(-2*testLongestRetention), segments[0].start)
+	assert.Equal(t, now.Add(-test3MonthRetention), segments[0].end)
+	assert.Equal(t, "metrics_aggregated_5m:90d", segments[1].namespace.NamespaceID().String())
+	assert.Equal(t, "metrics_unaggregated", segments[2].namespace.NamespaceID().String())
+}
+
+func TestStitchedSeriesIterator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	start := time.Now().Truncate(time.Hour)
+	older := []m3ts.Datapoint{
+		{Timestamp: start, Value: 1},
+		{Timestamp: start.Add(time.Minute), Value: 2},
+	}
+	newer := []m3ts.Datapoint{
+		{Timestamp: start.Add(2 * time.Minute), Value: 3},
+	}
+
+	iter := newStitchedSeriesIterator([]encoding.SeriesIterator{
+		newTestStitchSeriesIter(ctrl, "foo", older),
+		newTestStitchSeriesIter(ctrl, "foo", nil),
+		newTestStitchSeriesIter(ctrl, "foo", newer),
+	})
+	assert.Equal(t, "foo", iter.ID().String())
+
+	var values []float64
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		values = append(values, dp.Value)
+	}
+	require.NoError(t, iter.Err())
+	assert.Equal(t, []float64{1, 2, 3}, values)
+}
+
+func TestStitchedSeriesIteratorStopsOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	failing := encoding.NewMockSeriesIterator(ctrl)
+	failing.EXPECT().Next().Return(false)
+	failing.EXPECT().Err().Return(errors.New("decode error"))
+
+	iter := newStitchedSeriesIterator([]encoding.SeriesIterator{
+		failing,
+		encoding.NewMockSeriesIterator(ctrl),
+	})
+	assert.False(t, iter.Next())
+	assert.Error(t, iter.Err())
+}
+
+func TestLocalReadStitchesUnaggregatedAndAggregated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	s, sessions := newTestStitchingStorage(t, ctrl, now)
+	readPool, err := sync.NewPooledWorkerPool(10, sync.NewPooledWorkerPoolOptions())
+	require.NoError(t, err)
+	readPool.Init()
+	s.readWorkerPool = readPool
+
+	var (
+		olderTime = now.Add(-45 * 24 * time.Hour)
+		newerTime = now.Add(-time.Hour)
+	)
+
+	session := sessions.aggregated3MonthRetention5MinuteResolution
+	session.EXPECT().IteratorPools().Return(newTestIteratorPools(ctrl), nil).AnyTimes()
+	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ ident.ID,
+			_ index.Query,
+			opts index.QueryOptions,
+		) (encoding.SeriesIterators, bool, error) {
+			assert.Equal(t, now.Add(-2*test1MonthRetention), opts.StartInclusive)
+			assert.Equal(t, now.Add(-test1MonthRetention), opts.EndExclusive)
+			return encoding.NewSeriesIterators([]encoding.SeriesIterator{
+				newTestStitchSeriesIter(ctrl, "foo", []m3ts.Datapoint{{Timestamp: olderTime, Value: 1}}),
+			}, nil), true, nil
+		})
+
+	session = sessions.unaggregated1MonthRetention
+	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ ident.ID,
+			_ index.Query,
+			opts index.QueryOptions,
+		) (encoding.SeriesIterators, bool, error) {
+			assert.Equal(t, now.Add(-test1MonthRetention), opts.StartInclusive)
+			assert.Equal(t, now, opts.EndExclusive)
+			return encoding.NewSeriesIterators([]encoding.SeriesIterator{
+				newTestStitchSeriesIter(ctrl, "foo", []m3ts.Datapoint{{Timestamp: newerTime, Value: 2}}),
+				newTestStitchSeriesIter(ctrl, "bar", []m3ts.Datapoint{{Timestamp: newerTime, Value: 3}}),
+			}, nil), true, nil
+		})
+
+	query := newFetchReq()
+	query.Start = now.Add(-2 * test1MonthRetention)
+	query.End = now
+	result, err := s.Fetch(context.TODO(), query, &storage.FetchOptions{Limit: 100})
+	require.NoError(t, err)
+
+	require.Len(t, result.SeriesList, 2)
+	values := make(map[string][]float64)
+	for _, series := range result.SeriesList {
+		for _, dp := range series.Values().Datapoints() {
+			values[series.Name()] = append(values[series.Name()], dp.Value)
+		}
+	}
+	assert.Equal(t, map[string][]float64{"foo": {1, 2}, "bar": {3}}, values)
+
+	require.Len(t, result.ResolutionRanges, 2)
+	assert.Equal(t, "metrics_aggregated_5m:90d", result.ResolutionRanges[0].Namespace)
+	assert.Equal(t, 5*time.Minute, result.ResolutionRanges[0].Attributes.Resolution)
+	assert.Equal(t, "metrics_unaggregated", result.ResolutionRanges[1].Namespace)
+	assert.Equal(t, storage.UnaggregatedMetricsType, result.ResolutionRanges[1].Attributes.MetricsType)
+}
+
+func newTestStitchReplica(
+	t *testing.T,
+	blockStart time.Time,
+	blockSize time.Duration,
+	datapoints []m3ts.Datapoint,
+) encoding.MultiReaderIterator {
+	encoder := m3tsz.NewEncoder(blockStart, nil,
+		m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
+	for _, dp := range datapoints {
+		require.NoError(t, encoder.Encode(dp, xtime.Second, nil))
+	}
+
+	replica := encoding.NewMultiReaderIterator(stitchedIterAlloc, nil)
+	replica.Reset([]xio.SegmentReader{xio.NewSegmentReader(encoder.Discard())},
+		blockStart, blockSize)
+	return replica
+}
+
+func replicaValues(t *testing.T, replica encoding.MultiReaderIterator) []float64 {
+	var values []float64
+	for replica.Next() {
+		dp, _, _ := replica.Current()
+		values = append(values, dp.Value)
+	}
+	require.NoError(t, replica.Err())
+	return values
+}
+
+func TestStitchedSeriesIteratorReplicasTrimmedAtBoundary(t *testing.T) {
+	var (
+		blockSize = 2 * time.Hour
+		start     = time.Now().Truncate(blockSize).Add(-2 * blockSize)
+		boundary  = start.Add(blockSize + time.Hour)
+		end       = start.Add(2 * blockSize)
+	)
+
+	// Both namespaces return the block that holds the boundary, with
+	// datapoints on either side of it.
+	older := encoding.NewSeriesIterator(encoding.SeriesIteratorOptions{
+		ID: ident.StringID("foo"),
+		Replicas: []encoding.MultiReaderIterator{
+			newTestStitchReplica(t, start, blockSize, []m3ts.Datapoint{
+				{Timestamp: start.Add(time.Minute), Value: 1},
+			}),
+			newTestStitchReplica(t, start.Add(blockSize), blockSize, []m3ts.Datapoint{
+				{Timestamp: boundary.Add(-time.Minute), Value: 2},
+				{Timestamp: boundary.Add(time.Minute), Value: 20},
+			}),
+		},
+		StartInclusive: start,
+		EndExclusive:   boundary,
+	}, nil)
+	newer := encoding.NewSeriesIterator(encoding.SeriesIteratorOptions{
+		ID: ident.StringID("foo"),
+		Replicas: []encoding.MultiReaderIterator{
+			newTestStitchReplica(t, start.Add(blockSize), blockSize, []m3ts.Datapoint{
+				{Timestamp: boundary.Add(-time.Minute), Value: 30},
+				{Timestamp: boundary.Add(time.Minute), Value: 3},
+			}),
+		},
+		StartInclusive: boundary,
+		EndExclusive:   end,
+	}, nil)
+	defer older.Close()
+	defer newer.Close()
+
+	iter := newStitchedSeriesIterator([]encoding.SeriesIterator{older, newer})
+	defer iter.Close()
+
+	replicas := iter.Replicas()
+	require.NoError(t, iter.Err())
+	require.Len(t, replicas, 3)
+	assert.Equal(t, []float64{1}, replicaValues(t, replicas[0]))
+	assert.Equal(t, []float64{2}, replicaValues(t, replicas[1]))
+	assert.Equal(t, []float64{3}, replicaValues(t, replicas[2]))
+}
+
+func TestFetchCompressedStitchesUnaggregatedAndAggregated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	s, sessions := newTestStitchingStorage(t, ctrl, now)
+
+	var (
+		olderTime = now.Add(-45 * 24 * time.Hour)
+		newerTime = now.Add(-time.Hour)
+	)
+
+	session := sessions.aggregated3MonthRetention5MinuteResolution
+	session.EXPECT().IteratorPools().Return(newTestIteratorPools(ctrl), nil).AnyTimes()
+	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(encoding.NewSeriesIterators([]encoding.SeriesIterator{
+			newTestStitchSeriesIter(ctrl, "foo", []m3ts.Datapoint{{Timestamp: olderTime, Value: 1}}),
+		}, nil), true, nil)
+
+	session = sessions.unaggregated1MonthRetention
+	session.EXPECT().FetchTagged(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(encoding.NewSeriesIterators([]encoding.SeriesIterator{
+			newTestStitchSeriesIter(ctrl, "foo", []m3ts.Datapoint{{Timestamp: newerTime, Value: 2}}),
+		}, nil), true, nil)
+
+	query := newFetchReq()
+	query.Start = now.Add(-2 * test1MonthRetention)
+	query.End = now
+	iters, cleanup, err := s.FetchCompressed(context.TODO(), query, &storage.FetchOptions{Limit: 100})
+	require.NoError(t, err)
+	defer cleanup()
+
+	require.Equal(t, 1, iters.Len())
+	iter := iters.Iters()[0]
+	_, ok := iter.(*stitchedSeriesIterator)
+	require.True(t, ok)
+
+	var values []float64
+	for iter.Next() {
+		dp, _, _ := iter.Current()
+		values = append(values, dp.Value)
+	}
+	require.NoError(t, iter.Err())
+	assert.Equal(t, []float64{1, 2}, values)
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package m3
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/m3db/m3/src/dbnode/encoding"
+	"github.com/m3db/m3/src/dbnode/encoding/m3tsz"
+	"github.com/m3db/m3/src/dbnode/ts"
+	"github.com/m3db/m3/src/dbnode/x/xio"
+	"github.com/m3db/m3x/ident"
+	xtime "github.com/m3db/m3x/time"
+)
+
+var (
+	errStitchedSeriesIteratorReset = errors.New("stitched series iterator cannot be reset")
+
+	stitchedIterAlloc = func(r io.Reader) encoding.ReaderIterator {
+		return m3tsz.NewReaderIterator(r, m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
+	}
+)
+
+// stitchedSeriesIterator iterates over the series iterators of a single
+// series fetched from consecutive time ranges, in time order. Each underlying
+// iterator is expected to be filtered to its own time range. The underlying
+// iterators are owned by the caller and are not closed by this iterator, only
+// the replicas trimmed to the range of each underlying iterator are.
+type stitchedSeriesIterator struct {
+	iters []encoding.SeriesIterator
+	idx   int
+	err   error
+
+	replicas        []encoding.MultiReaderIterator
+	replicasTrimmed bool
+}
+
+func newStitchedSeriesIterator(iters []encoding.SeriesIterator) encoding.SeriesIterator {
+	return &stitchedSeriesIterator{iters: iters}
+}
+
+func (it *stitchedSeriesIterator) Next() bool {
+	for it.err == nil && it.idx < len(it.iters) {
+		curr := it.iters[it.idx]
+		if curr.Next() {
+			return true
+		}
+		if err := curr.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		it.idx++
+	}
+	return false
+}
+
+func (it *stitchedSeriesIterator) Current() (ts.Datapoint, xtime.Unit, ts.Annotation) {
+	return it.iters[it.idx].Current()
+}
+
+func (it *stitchedSeriesIterator) Err() error {
+	return it.err
+}
+
+func (it *stitchedSeriesIterator) Close() {
+	it.closeReplicas()
+	it.iters = nil
+	it.idx = 0
+}
+
+func (it *stitchedSeriesIterator) ID() ident.ID {
+	return it.iters[0].ID()
+}
+
+func (it *stitchedSeriesIterator) Namespace() ident.ID {
+	return it.iters[len(it.iters)-1].Namespace()
+}
+
+func (it *stitchedSeriesIterator) Tags() ident.TagIterator {
+	return it.iters[0].Tags()
+}
+
+func (it *stitchedSeriesIterator) Start() time.Time {
+	return it.iters[0].Start()
+}
+
+func (it *stitchedSeriesIterator) End() time.Time {
+	return it.iters[len(it.iters)-1].End()
+}
+
+func (it *stitchedSeriesIterator) Reset(_ encoding.SeriesIteratorOptions) {
+	// Stitched iterators are not pooled, they only wrap iterators
+	// fetched for a single query.
+	it.err = errStitchedSeriesIteratorReset
+}
+
+func (it *stitchedSeriesIterator) SetIterateEqualTimestampStrategy(
+	strategy encoding.IterateEqualTimestampStrategy,
+) {
+	for _, iter := range it.iters {
+		iter.SetIterateEqualTimestampStrategy(strategy)
+	}
+}
+
+// Replicas returns the replicas of every stitched range. Blocks at the
+// boundary of two ranges may hold datapoints from both namespaces, so they
+// are re-encoded with only the datapoints of their own range. Reading the
+// replicas consumes the readers of the underlying iterators, any error
+// trimming them is returned by Err.
+func (it *stitchedSeriesIterator) Replicas() []encoding.MultiReaderIterator {
+	if it.replicasTrimmed || it.err != nil {
+		return it.replicas
+	}
+	it.replicasTrimmed = true
+
+	for i, iter := range it.iters {
+		// NB: Only trim at the boundaries between ranges, the start and end of
+		// the query range are filtered by callers with Start and End.
+		var start, end time.Time
+		if i > 0 {
+			start = iter.Start()
+		}
+		if i < len(it.iters)-1 {
+			end = iter.End()
+		}
+		for _, replica := range iter.Replicas() {
+			if replica == nil {
+				continue
+			}
+			trimmed, err := trimReplica(replica, start, end)
+			if err != nil {
+				it.err = err
+				it.closeReplicas()
+				return nil
+			}
+			it.replicas = append(it.replicas, trimmed)
+		}
+	}
+	return it.replicas
+}
+
+func (it *stitchedSeriesIterator) closeReplicas() {
+	for _, replica := range it.replicas {
+		replica.Close()
+	}
+	it.replicas = nil
+}
+
+// trimReplica returns a replica with the blocks of the given replica that
+// overlap [start, end), a zero start or end leaves that side unbounded.
+func trimReplica(
+	replica encoding.MultiReaderIterator,
+	start time.Time,
+	end time.Time,
+) (encoding.MultiReaderIterator, error) {
+	var blocks [][]xio.BlockReader
+	if readers := replica.Readers(); readers != nil {
+		for next := true; next; next = readers.Next() {
+			l, blockStart, blockSize := readers.CurrentReaders()
+			blockEnd := blockStart.Add(blockSize)
+			if l == 0 || !blockEnd.After(start) || (!end.IsZero() && !blockStart.Before(end)) {
+				continue
+			}
+
+			block := make([]xio.BlockReader, 0, l)
+			for i := 0; i < l; i++ {
+				// NB: Clone the readers to read them from the start, the replica
+				// has already read from the readers of its current block.
+				reader, err := readers.CurrentReaderAt(i).Clone()
+				if err != nil {
+					return nil, err
+				}
+				block = append(block, xio.BlockReader{
+					SegmentReader: reader,
+					Start:         blockStart,
+					BlockSize:     blockSize,
+				})
+			}
+
+			if blockStart.Before(start) || (!end.IsZero() && blockEnd.After(end)) {
+				trimmed, ok, err := trimBlock(block, start, end)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+				block = []xio.BlockReader{trimmed}
+			}
+			blocks = append(blocks, block)
+		}
+	}
+
+	trimmed := encoding.NewMultiReaderIterator(stitchedIterAlloc, nil)
+	trimmed.ResetSliceOfSlices(xio.NewReaderSliceOfSlicesFromBlockReadersIterator(blocks))
+	return trimmed, nil
+}
+
+// trimBlock re-encodes the datapoints of a block that are within
+// [start, end), returning false if there are none.
+func trimBlock(
+	block []xio.BlockReader,
+	start time.Time,
+	end time.Time,
+) (xio.BlockReader, bool, error) {
+	var (
+		blockStart = block[0].Start
+		blockSize  = block[0].BlockSize
+		readers    = make([]xio.SegmentReader, 0, len(block))
+	)
+	for _, reader := range block {
+		readers = append(readers, reader.SegmentReader)
+	}
+
+	iter := encoding.NewMultiReaderIterator(stitchedIterAlloc, nil)
+	iter.Reset(readers, blockStart, blockSize)
+	defer iter.Close()
+
+	encoder := m3tsz.NewEncoder(blockStart, nil,
+		m3tsz.DefaultIntOptimizationEnabled, encoding.NewOptions())
+	for iter.Next() {
+		dp, unit, annotation := iter.Current()
+		if dp.Timestamp.Before(start) || (!end.IsZero() && !dp.Timestamp.Before(end)) {
+			continue
+		}
+		if err := encoder.Encode(dp, unit, annotation); err != nil {
+			return xio.BlockReader{}, false, err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return xio.BlockReader{}, false, err
+	}
+	if encoder.NumEncoded() == 0 {
+		return xio.BlockReader{}, false, nil
+	}
+
+	return xio.BlockReader{
+		SegmentReader: xio.NewSegmentReader(encoder.Discard()),
+		Start:         blockStart,
+		BlockSize:     blockSize,
+	}, true, nil
+}
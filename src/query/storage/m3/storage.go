@@ -57,6 +57,7 @@ type m3storage struct {
 	readWorkerPool  xsync.PooledWorkerPool
 	writeWorkerPool xsync.PooledWorkerPool
 	opts            m3db.Options
+	stitchingPolicy TimeStitchingPolicy
 	nowFn           func() time.Time
 }
 
@@ -67,6 +68,7 @@ func NewStorage(
 	readWorkerPool xsync.PooledWorkerPool,
 	writeWorkerPool xsync.PooledWorkerPool,
 	tagOptions models.TagOptions,
+	stitchingPolicy TimeStitchingPolicy,
 ) Storage {
 	opts := m3db.NewOptions().
 		SetTagOptions(tagOptions).
@@ -78,6 +80,7 @@ func NewStorage(
 		readWorkerPool:  readWorkerPool,
 		writeWorkerPool: writeWorkerPool,
 		opts:            opts,
+		stitchingPolicy: stitchingPolicy,
 		nowFn:           time.Now,
 	}
 }
@@ -87,18 +90,24 @@ func (s *m3storage) Fetch(
 	query *storage.FetchQuery,
 	options *storage.FetchOptions,
 ) (*storage.FetchResult, error) {
-	raw, cleanup, err := s.FetchCompressed(ctx, query, options)
+	raw, ranges, cleanup, err := s.fetchCompressed(ctx, query, options)
 	defer cleanup()
 	if err != nil {
 		return nil, err
 	}
 
-	return storage.SeriesIteratorsToFetchResult(
+	result, err := storage.SeriesIteratorsToFetchResult(
 		raw,
 		s.readWorkerPool,
 		false,
 		s.opts.TagOptions(),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	result.ResolutionRanges = ranges
+	return result, nil
 }
 
 func (s *m3storage) FetchBlocks(
@@ -150,16 +159,35 @@ func (s *m3storage) FetchCompressed(
 	query *storage.FetchQuery,
 	options *storage.FetchOptions,
 ) (encoding.SeriesIterators, Cleanup, error) {
+	iters, _, cleanup, err := s.fetchCompressed(ctx, query, options)
+	return iters, cleanup, err
+}
+
+func (s *m3storage) fetchCompressed(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (encoding.SeriesIterators, []storage.ResolutionRange, Cleanup, error) {
 	// Check if the query was interrupted.
 	select {
 	case <-ctx.Done():
-		return nil, noop, ctx.Err()
+		return nil, nil, noop, ctx.Err()
 	default:
 	}
 
+	if s.stitchingPolicy == TimeStitchingFinestResolution {
+		if segments := s.resolveStitchedSegmentsForQuery(query.Start, query.End); len(segments) > 0 {
+			iters, cleanup, err := s.fetchStitched(ctx, query, options, segments)
+			if err != nil {
+				return nil, nil, noop, err
+			}
+			return iters, resolutionRangesForSegments(segments), cleanup, nil
+		}
+	}
+
 	m3query, err := storage.FetchQueryToM3Query(query)
 	if err != nil {
-		return nil, noop, err
+		return nil, nil, noop, err
 	}
 
 	// NB(r): Since we don't use a single index we fan out to each
@@ -168,7 +196,7 @@ func (s *m3storage) FetchCompressed(
 	// This needs to be optimized, however this is a start.
 	fanout, namespaces, err := s.resolveClusterNamespacesForQuery(query.Start, query.End)
 	if err != nil {
-		return nil, noop, err
+		return nil, nil, noop, err
 	}
 
 	var (
@@ -176,17 +204,24 @@ func (s *m3storage) FetchCompressed(
 		wg   sync.WaitGroup
 	)
 	if len(namespaces) == 0 {
-		return nil, noop, errNoNamespacesConfigured
+		return nil, nil, noop, errNoNamespacesConfigured
 	}
 
 	pools, err := namespaces[0].Session().IteratorPools()
 	if err != nil {
-		return nil, noop, fmt.Errorf("unable to retrieve iterator pools: %v", err)
+		return nil, nil, noop, fmt.Errorf("unable to retrieve iterator pools: %v", err)
 	}
 
 	result := newMultiFetchResult(fanout, pools)
+	ranges := make([]storage.ResolutionRange, 0, len(namespaces))
 	for _, namespace := range namespaces {
 		namespace := namespace // Capture var)
+		ranges = append(ranges, storage.ResolutionRange{
+			Start:      query.Start,
+			End:        query.End,
+			Namespace:  namespace.NamespaceID().String(),
+			Attributes: namespace.Options().Attributes(),
+		})
 
 		wg.Add(1)
 		go func() {
@@ -205,6 +240,66 @@ func (s *m3storage) FetchCompressed(
 	// Check if the query was interrupted.
 	select {
 	case <-ctx.Done():
+		return nil, nil, noop, ctx.Err()
+	default:
+	}
+
+	iters, err := result.FinalResult()
+	if err != nil {
+		result.Close()
+		return nil, nil, noop, err
+	}
+
+	return iters, ranges, result.Close, nil
+}
+
+// fetchStitched fetches each segment of the query range from the namespace
+// serving it concurrently and stitches the results of each series in time order.
+func (s *m3storage) fetchStitched(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+	segments []queryRangeSegment,
+) (encoding.SeriesIterators, Cleanup, error) {
+	m3query, err := storage.FetchQueryToM3Query(query)
+	if err != nil {
+		return nil, noop, err
+	}
+
+	pools, err := segments[0].namespace.Session().IteratorPools()
+	if err != nil {
+		return nil, noop, fmt.Errorf("unable to retrieve iterator pools: %v", err)
+	}
+
+	var (
+		result = newStitchedResult(len(segments), pools)
+		wg     sync.WaitGroup
+	)
+	for i, segment := range segments {
+		var (
+			idx          = i // Capture vars
+			namespace    = segment.namespace
+			segmentQuery = *query
+		)
+		segmentQuery.Start = segment.start
+		segmentQuery.End = segment.end
+		opts := storage.FetchOptionsToM3Options(options, &segmentQuery)
+
+		wg.Add(1)
+		go func() {
+			session := namespace.Session()
+			iters, _, err := session.FetchTagged(namespace.NamespaceID(), m3query, opts)
+			result.Add(idx, iters, err)
+			wg.Done()
+		}()
+	}
+
+	wg.Wait()
+
+	// Check if the query was interrupted.
+	select {
+	case <-ctx.Done():
+		result.Close()
 		return nil, noop, ctx.Err()
 	default:
 	}
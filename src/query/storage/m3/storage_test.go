@@ -122,7 +122,7 @@ func newTestStorage(t *testing.T, clusters Clusters) storage.Storage {
 	require.NoError(t, err)
 	writePool.Init()
 	opts := models.NewTagOptions().SetMetricName([]byte("name"))
-	storage := NewStorage(clusters, nil, writePool, opts)
+	storage := NewStorage(clusters, nil, writePool, opts, DefaultTimeStitchingPolicy)
 	return storage
 }
 
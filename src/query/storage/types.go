@@ -195,6 +195,18 @@ type FetchResult struct {
 	SeriesList ts.SeriesList // The aggregated list of results across all underlying storage calls
 	LocalOnly  bool
 	HasNext    bool
+	// ResolutionRanges describes which namespaces served each part of the
+	// query time range.
+	ResolutionRanges []ResolutionRange
+}
+
+// ResolutionRange describes the namespace and resolution that served
+// a time range of a fetch.
+type ResolutionRange struct {
+	Start      time.Time
+	End        time.Time
+	Namespace  string
+	Attributes Attributes
 }
 
 // QueryResult is the result from a query
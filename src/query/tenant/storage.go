@@ -0,0 +1,93 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tenant
+
+import (
+	"context"
+
+	"github.com/m3db/m3/src/query/block"
+	"github.com/m3db/m3/src/query/storage"
+)
+
+type tenantStorage struct {
+	store storage.Storage
+}
+
+// NewStorage returns a storage that serves requests made for a tenant from
+// the storage of the tenant, and other requests from the store.
+func NewStorage(store storage.Storage) storage.Storage {
+	return &tenantStorage{store: store}
+}
+
+func (s *tenantStorage) storage(ctx context.Context) storage.Storage {
+	if t, ok := FromContext(ctx); ok {
+		return t.Storage
+	}
+	return s.store
+}
+
+func (s *tenantStorage) Fetch(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (*storage.FetchResult, error) {
+	return s.storage(ctx).Fetch(ctx, query, options)
+}
+
+func (s *tenantStorage) FetchBlocks(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (block.Result, error) {
+	return s.storage(ctx).FetchBlocks(ctx, query, options)
+}
+
+func (s *tenantStorage) FetchTags(
+	ctx context.Context,
+	query *storage.FetchQuery,
+	options *storage.FetchOptions,
+) (*storage.SearchResults, error) {
+	return s.storage(ctx).FetchTags(ctx, query, options)
+}
+
+func (s *tenantStorage) CompleteTags(
+	ctx context.Context,
+	query *storage.CompleteTagsQuery,
+	options *storage.FetchOptions,
+) (*storage.CompleteTagsResult, error) {
+	return s.storage(ctx).CompleteTags(ctx, query, options)
+}
+
+func (s *tenantStorage) Write(
+	ctx context.Context,
+	query *storage.WriteQuery,
+) error {
+	return s.storage(ctx).Write(ctx, query)
+}
+
+func (s *tenantStorage) Type() storage.Type {
+	return s.store.Type()
+}
+
+// Close closes the store, the storages of tenants are closed by their owner.
+func (s *tenantStorage) Close() error {
+	return s.store.Close()
+}
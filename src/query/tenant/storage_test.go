@@ -0,0 +1,68 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tenant
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/m3db/m3/src/query/models"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/mock"
+	"github.com/m3db/m3/src/query/ts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageRoutesTenantRequests(t *testing.T) {
+	var (
+		defaultStore = mock.NewMockStorage()
+		tenantStore  = mock.NewMockStorage()
+		store        = NewStorage(defaultStore)
+		tenant       = &Tenant{ID: "foo", Storage: tenantStore}
+	)
+
+	write := &storage.WriteQuery{
+		Tags: models.NewTags(1, nil).AddTag(models.Tag{
+			Name:  []byte("foo"),
+			Value: []byte("bar"),
+		}),
+		Datapoints: ts.Datapoints{{Timestamp: time.Now(), Value: 42}},
+	}
+
+	require.NoError(t, store.Write(context.Background(), write))
+	assert.Equal(t, 1, len(defaultStore.Writes()))
+	assert.Equal(t, 0, len(tenantStore.Writes()))
+
+	ctx := NewContext(context.Background(), tenant)
+	require.NoError(t, store.Write(ctx, write))
+	assert.Equal(t, 1, len(defaultStore.Writes()))
+	assert.Equal(t, 1, len(tenantStore.Writes()))
+
+	defaultStore.SetFetchResult(&storage.FetchResult{}, nil)
+	tenantResult := &storage.FetchResult{LocalOnly: true}
+	tenantStore.SetFetchResult(tenantResult, nil)
+	result, err := store.Fetch(ctx, &storage.FetchQuery{}, storage.NewFetchOptions())
+	require.NoError(t, err)
+	assert.True(t, result == tenantResult)
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/m3db/m3/src/cmd/services/m3coordinator/downsample"
+	"github.com/m3db/m3/src/cmd/services/m3query/config"
+	"github.com/m3db/m3/src/query/auth"
+	"github.com/m3db/m3/src/query/storage"
+	"github.com/m3db/m3/src/query/storage/m3"
+	"github.com/m3db/m3/src/x/net/http"
+)
+
+const (
+	// DefaultHeader is the default request header carrying the tenant ID.
+	DefaultHeader = "M3-Tenant"
+)
+
+var (
+	errTenantRequired = errors.New("request must be made for a tenant")
+	errNoTenantID     = errors.New("tenant has no ID")
+)
+
+type contextKey struct{}
+
+// Tenant is a tenant of the coordinator, with its own cluster namespaces,
+// downsampling rules namespace and limits.
+type Tenant struct {
+	// ID is the tenant ID.
+	ID string
+
+	// Clusters are the cluster namespaces of the tenant.
+	Clusters m3.Clusters
+
+	// Storage reads from and writes to the cluster namespaces of the tenant.
+	Storage storage.Storage
+
+	// Downsampler downsamples writes to the aggregated cluster namespaces
+	// of the tenant, nil if the tenant has no aggregated cluster namespaces.
+	Downsampler downsample.Downsampler
+
+	// Limits are the per-query resource usage limits of the tenant.
+	Limits *config.LimitsConfiguration
+}
+
+// NewContext returns a context carrying the tenant.
+func NewContext(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant carried by the context, if any.
+func FromContext(ctx context.Context) (*Tenant, bool) {
+	t, ok := ctx.Value(contextKey{}).(*Tenant)
+	return t, ok && t != nil
+}
+
+// Registry resolves the tenants requests are made for.
+type Registry struct {
+	header   string
+	required bool
+	tenants  map[string]*Tenant
+}
+
+// NewRegistry returns a new registry of the tenants, resolving the tenant of
+// requests from the header. Requests not made for a tenant are rejected if
+// a tenant is required.
+func NewRegistry(
+	header string,
+	required bool,
+	tenants []*Tenant,
+) (*Registry, error) {
+	if header == "" {
+		header = DefaultHeader
+	}
+	r := &Registry{
+		header:   header,
+		required: required,
+		tenants:  make(map[string]*Tenant, len(tenants)),
+	}
+	for _, t := range tenants {
+		if t.ID == "" {
+			return nil, errNoTenantID
+		}
+		if _, ok := r.tenants[t.ID]; ok {
+			return nil, fmt.Errorf("duplicate tenant: %s", t.ID)
+		}
+		r.tenants[t.ID] = t
+	}
+	return r, nil
+}
+
+// Tenant returns the tenant with the ID, if any.
+func (r *Registry) Tenant(id string) (*Tenant, bool) {
+	t, ok := r.tenants[id]
+	return t, ok
+}
+
+// Tenants returns the tenants sorted by ID.
+func (r *Registry) Tenants() []*Tenant {
+	tenants := make([]*Tenant, 0, len(r.tenants))
+	for _, t := range r.tenants {
+		tenants = append(tenants, t)
+	}
+	sort.Slice(tenants, func(i, j int) bool {
+		return tenants[i].ID < tenants[j].ID
+	})
+	return tenants
+}
+
+// Resolve returns the tenant the request is made for, nil if it is not made
+// for a tenant and a tenant is not required. Requests of callers restricted
+// to a tenant are made for that tenant.
+func (r *Registry) Resolve(req *http.Request) (*Tenant, error) {
+	id := req.Header.Get(r.header)
+	if identity, ok := auth.FromContext(req.Context()); ok && identity.Tenant != "" {
+		if id != "" && id != identity.Tenant {
+			return nil, fmt.Errorf("%s cannot make requests for tenant: %s",
+				identity.Name, id)
+		}
+		id = identity.Tenant
+	}
+	if id == "" {
+		if r.required {
+			return nil, errTenantRequired
+		}
+		return nil, nil
+	}
+	t, ok := r.tenants[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown tenant: %s", id)
+	}
+	return t, nil
+}
+
+// Middleware returns a handler that serves requests with the tenant they are
+// made for in the request context, rejecting requests if the tenant cannot
+// be resolved. Requests are served as is by a nil registry.
+func (r *Registry) Middleware(next http.Handler) http.Handler {
+	if r == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t, err := r.Resolve(req)
+		if err != nil {
+			xhttp.Error(w, err, http.StatusBadRequest)
+			return
+		}
+		if t != nil {
+			req = req.WithContext(NewContext(req.Context(), t))
+		}
+		next.ServeHTTP(w, req)
+	})
+}
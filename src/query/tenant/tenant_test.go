@@ -0,0 +1,163 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tenant
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/m3db/m3/src/query/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRegistry(t *testing.T, required bool) *Registry {
+	r, err := NewRegistry("", required, []*Tenant{
+		{ID: "foo"},
+		{ID: "bar"},
+	})
+	require.NoError(t, err)
+	return r
+}
+
+func TestNewRegistryValidatesTenants(t *testing.T) {
+	_, err := NewRegistry("", false, []*Tenant{{ID: "foo"}, {ID: "foo"}})
+	assert.Error(t, err)
+
+	_, err = NewRegistry("", false, []*Tenant{{}})
+	assert.Error(t, err)
+}
+
+func TestRegistryTenants(t *testing.T) {
+	r := newTestRegistry(t, false)
+
+	tenants := r.Tenants()
+	require.Equal(t, 2, len(tenants))
+	assert.Equal(t, "bar", tenants[0].ID)
+	assert.Equal(t, "foo", tenants[1].ID)
+
+	foo, ok := r.Tenant("foo")
+	require.True(t, ok)
+	assert.Equal(t, "foo", foo.ID)
+
+	_, ok = r.Tenant("baz")
+	assert.False(t, ok)
+}
+
+func TestRegistryMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		required       bool
+		header         string
+		identityTenant string
+		expectedCode   int
+		expectedTenant string
+	}{
+		{
+			name:           "tenant",
+			header:         "foo",
+			expectedCode:   http.StatusOK,
+			expectedTenant: "foo",
+		},
+		{
+			name:         "no tenant",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "no tenant when required",
+			required:     true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown tenant",
+			header:       "baz",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:           "identity tenant",
+			identityTenant: "bar",
+			expectedCode:   http.StatusOK,
+			expectedTenant: "bar",
+		},
+		{
+			name:           "identity tenant with same tenant",
+			header:         "bar",
+			identityTenant: "bar",
+			expectedCode:   http.StatusOK,
+			expectedTenant: "bar",
+		},
+		{
+			name:           "identity tenant with other tenant",
+			header:         "foo",
+			identityTenant: "bar",
+			expectedCode:   http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				served bool
+				tenant string
+			)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				served = true
+				if t, ok := FromContext(r.Context()); ok {
+					tenant = t.ID
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.header != "" {
+				req.Header.Set(DefaultHeader, test.header)
+			}
+			if test.identityTenant != "" {
+				req = req.WithContext(auth.NewContext(req.Context(), &auth.Identity{
+					Name:   "test",
+					Tenant: test.identityTenant,
+				}))
+			}
+			recorder := httptest.NewRecorder()
+			newTestRegistry(t, test.required).Middleware(next).ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedCode == http.StatusOK, served)
+			assert.Equal(t, test.expectedTenant, tenant)
+		})
+	}
+}
+
+func TestNilRegistryMiddleware(t *testing.T) {
+	var (
+		r      *Registry
+		served bool
+	)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(DefaultHeader, "foo")
+	r.Middleware(next).ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, served)
+}
@@ -60,6 +60,6 @@ func NewStorageAndSession(
 	require.NoError(t, err)
 	writePool.Init()
 	tagOptions := models.NewTagOptions().SetMetricName([]byte("name"))
-	storage := m3.NewStorage(clusters, nil, writePool, tagOptions)
+	storage := m3.NewStorage(clusters, nil, writePool, tagOptions, m3.DefaultTimeStitchingPolicy)
 	return storage, session
 }
@@ -122,8 +122,13 @@ func blockReplicasFromSeriesIterator(
 	bounds models.Bounds,
 	pools encoding.IteratorPools,
 ) (seriesBlocks, error) {
+	replicas := seriesIterator.Replicas()
+	if err := seriesIterator.Err(); err != nil {
+		return nil, err
+	}
+
 	blocks := make(seriesBlocks, 0, bounds.Steps())
-	for _, replica := range seriesIterator.Replicas() {
+	for _, replica := range replicas {
 		perBlockSliceReaders := replica.Readers()
 		for next := true; next; next = perBlockSliceReaders.Next() {
 			l, start, bs := perBlockSliceReaders.CurrentReaders()
@@ -153,6 +153,10 @@ func compressedSeriesFromSeriesIterator(
 	iterPools encoding.IteratorPools,
 ) (*rpc.Series, error) {
 	replicas := it.Replicas()
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
 	compressedReplicas := make([]*rpc.M3CompressedValuesReplica, 0, len(replicas))
 	for _, replica := range replicas {
 		replicaSegments := make([]*rpc.M3Segments, 0, len(replicas))
@@ -283,6 +283,7 @@ func TestConversionDoesNotCloseSeriesIterator(t *testing.T) {
 	mockIter := encoding.NewMockSeriesIterator(ctrl)
 	mockIter.EXPECT().Close().Times(0)
 	mockIter.EXPECT().Replicas().Return([]encoding.MultiReaderIterator{}).Times(1)
+	mockIter.EXPECT().Err().Return(nil).Times(1)
 	mockIter.EXPECT().Start().Return(time.Now()).Times(1)
 	mockIter.EXPECT().End().Return(time.Now()).Times(1)
 	mockIter.EXPECT().Tags().Return(ident.NewTagsIterator(ident.NewTags())).Times(1)
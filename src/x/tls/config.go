@@ -0,0 +1,219 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package xtls provides TLS configuration for servers and clients, with
+// certificates that are reloaded when the files they are loaded from change.
+package xtls
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultReloadInterval is the default interval between checks of
+	// the certificate files for changes.
+	defaultReloadInterval = time.Minute
+)
+
+var (
+	errServerCertificateRequired = errors.New("tls server requires a certFile and keyFile")
+	errCertificateKeyMismatch    = errors.New("tls requires both or neither of certFile and keyFile")
+	errClientAuthRequiresCA      = errors.New("tls client certificate verification requires a caFile")
+)
+
+// ClientAuthType is the verification mode of client certificates by servers.
+type ClientAuthType uint
+
+const (
+	// ClientAuthNone does not request client certificates.
+	ClientAuthNone ClientAuthType = iota
+	// ClientAuthRequest requests but does not require or verify
+	// client certificates.
+	ClientAuthRequest
+	// ClientAuthRequireAny requires but does not verify client certificates.
+	ClientAuthRequireAny
+	// ClientAuthVerifyIfGiven verifies client certificates if given.
+	ClientAuthVerifyIfGiven
+	// ClientAuthRequireAndVerify requires and verifies client certificates.
+	ClientAuthRequireAndVerify
+)
+
+var validClientAuthTypes = []ClientAuthType{
+	ClientAuthNone,
+	ClientAuthRequest,
+	ClientAuthRequireAny,
+	ClientAuthVerifyIfGiven,
+	ClientAuthRequireAndVerify,
+}
+
+func (t ClientAuthType) String() string {
+	switch t {
+	case ClientAuthNone:
+		return "none"
+	case ClientAuthRequest:
+		return "request"
+	case ClientAuthRequireAny:
+		return "require_any"
+	case ClientAuthVerifyIfGiven:
+		return "verify_if_given"
+	case ClientAuthRequireAndVerify:
+		return "require_and_verify"
+	default:
+		return "unknown"
+	}
+}
+
+// UnmarshalYAML unmarshals a client auth type.
+func (t *ClientAuthType) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	for _, valid := range validClientAuthTypes {
+		if str == valid.String() {
+			*t = valid
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid ClientAuthType '%s' valid types are: %v",
+		str, validClientAuthTypes)
+}
+
+func (t ClientAuthType) tlsClientAuthType() tls.ClientAuthType {
+	switch t {
+	case ClientAuthRequest:
+		return tls.RequestClientCert
+	case ClientAuthRequireAny:
+		return tls.RequireAnyClientCert
+	case ClientAuthVerifyIfGiven:
+		return tls.VerifyClientCertIfGiven
+	case ClientAuthRequireAndVerify:
+		return tls.RequireAndVerifyClientCert
+	default:
+		return tls.NoClientCert
+	}
+}
+
+// Configuration is the TLS configuration of a server or client.
+type Configuration struct {
+	// CertFile is the path to the PEM encoded certificate presented to peers,
+	// required for servers and to authenticate clients.
+	CertFile string `yaml:"certFile"`
+
+	// KeyFile is the path to the PEM encoded private key of the certificate.
+	KeyFile string `yaml:"keyFile"`
+
+	// CAFile is the path to the PEM encoded certificate authorities used to
+	// verify peer certificates, clients use the system roots if not set.
+	CAFile string `yaml:"caFile"`
+
+	// ClientAuth is the verification mode of client certificates by servers,
+	// defaults to require_and_verify if a caFile is set and none otherwise.
+	ClientAuth *ClientAuthType `yaml:"clientAuth"`
+
+	// ServerName is the name clients verify server certificates against,
+	// defaults to the host of the address dialed.
+	ServerName string `yaml:"serverName"`
+
+	// InsecureSkipVerify disables the verification of server certificates
+	// by clients, only intended for testing.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify"`
+
+	// ReloadInterval is the interval between checks of the certificate
+	// files for changes, which are reloaded if changed, defaults to one
+	// minute and zero disables reloading.
+	ReloadInterval *time.Duration `yaml:"reloadInterval"`
+}
+
+func (c Configuration) clientAuthType() ClientAuthType {
+	if c.ClientAuth != nil {
+		return *c.ClientAuth
+	}
+	if c.CAFile != "" {
+		return ClientAuthRequireAndVerify
+	}
+	return ClientAuthNone
+}
+
+func (c Configuration) reloadInterval() time.Duration {
+	if c.ReloadInterval != nil {
+		return *c.ReloadInterval
+	}
+	return defaultReloadInterval
+}
+
+// NewServerConfig returns a TLS config for servers, the certificates
+// are reloaded on new connections if the files have changed.
+func (c Configuration) NewServerConfig() (*tls.Config, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, errServerCertificateRequired
+	}
+	clientAuth := c.clientAuthType()
+	switch clientAuth {
+	case ClientAuthVerifyIfGiven, ClientAuthRequireAndVerify:
+		if c.CAFile == "" {
+			return nil, errClientAuthRequiresCA
+		}
+	}
+
+	reloader, err := newCertReloader(c.CertFile, c.KeyFile, c.CAFile,
+		c.reloadInterval(), time.Now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, _ := reloader.current()
+			return cert, nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, caPool := reloader.current()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientCAs:    caPool,
+				ClientAuth:   clientAuth.tlsClientAuthType(),
+			}, nil
+		},
+	}, nil
+}
+
+// NewDialer returns a dialer of TLS client connections, the certificates
+// are reloaded on new connections if the files have changed.
+func (c Configuration) NewDialer() (*Dialer, error) {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, errCertificateKeyMismatch
+	}
+	reloader, err := newCertReloader(c.CertFile, c.KeyFile, c.CAFile,
+		c.reloadInterval(), time.Now)
+	if err != nil {
+		return nil, err
+	}
+	return &Dialer{
+		reloader:           reloader,
+		serverName:         c.ServerName,
+		insecureSkipVerify: c.InsecureSkipVerify,
+	}, nil
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package xtls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue writes a certificate for localhost signed by the CA and its key
+// to the directory, returning their paths.
+func (ca testCA) issue(t *testing.T, dir, name string, serial int64) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	require.NoError(t, ioutil.WriteFile(certFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+func (ca testCA) write(t *testing.T, dir string) string {
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, ioutil.WriteFile(caFile, ca.pem, 0600))
+	return caFile
+}
+
+func newTestDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "xtls")
+	require.NoError(t, err)
+	return dir
+}
+
+// serveTestHandshakes accepts connections completing TLS handshakes until
+// the listener is closed, returning the handshake errors.
+func serveTestHandshakes(listener net.Listener) <-chan error {
+	errCh := make(chan error, 16)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				close(errCh)
+				return
+			}
+			errCh <- conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	return errCh
+}
+
+func TestClientAuthTypeUnmarshalYAML(t *testing.T) {
+	for _, clientAuth := range validClientAuthTypes {
+		var parsed ClientAuthType
+		require.NoError(t, yaml.Unmarshal([]byte(clientAuth.String()), &parsed))
+		assert.Equal(t, clientAuth, parsed)
+	}
+
+	var parsed ClientAuthType
+	require.Error(t, yaml.Unmarshal([]byte("verify"), &parsed))
+}
+
+func TestConfigurationDefaultClientAuth(t *testing.T) {
+	assert.Equal(t, ClientAuthNone, Configuration{}.clientAuthType())
+	assert.Equal(t, ClientAuthRequireAndVerify,
+		Configuration{CAFile: "ca.crt"}.clientAuthType())
+
+	request := ClientAuthRequest
+	assert.Equal(t, ClientAuthRequest,
+		Configuration{CAFile: "ca.crt", ClientAuth: &request}.clientAuthType())
+}
+
+func TestNewServerConfigValidates(t *testing.T) {
+	_, err := Configuration{}.NewServerConfig()
+	assert.Equal(t, errServerCertificateRequired, err)
+
+	_, err = Configuration{CertFile: "a.crt", KeyFile: "a.key"}.NewServerConfig()
+	assert.Error(t, err)
+
+	verify := ClientAuthVerifyIfGiven
+	_, err = Configuration{
+		CertFile:   "a.crt",
+		KeyFile:    "a.key",
+		ClientAuth: &verify,
+	}.NewServerConfig()
+	assert.Equal(t, errClientAuthRequiresCA, err)
+
+	_, err = Configuration{CertFile: "a.crt"}.NewDialer()
+	assert.Equal(t, errCertificateKeyMismatch, err)
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t)
+	caFile := ca.write(t, dir)
+	serverCert, serverKey := ca.issue(t, dir, "server", 2)
+	clientCert, clientKey := ca.issue(t, dir, "client", 3)
+
+	serverConfig, err := Configuration{
+		CertFile: serverCert,
+		KeyFile:  serverKey,
+		CAFile:   caFile,
+	}.NewServerConfig()
+	require.NoError(t, err)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	require.NoError(t, err)
+	defer listener.Close()
+	serverErrs := serveTestHandshakes(listener)
+	address := listener.Addr().String()
+
+	// Client with a certificate signed by the CA.
+	dialer, err := Configuration{
+		CertFile: clientCert,
+		KeyFile:  clientKey,
+		CAFile:   caFile,
+	}.NewDialer()
+	require.NoError(t, err)
+	conn, err := dialer.DialContext(context.Background(), "tcp", address)
+	require.NoError(t, err)
+	require.NoError(t, <-serverErrs)
+	conn.Close()
+
+	// Client without a certificate is rejected.
+	dialer, err = Configuration{CAFile: caFile}.NewDialer()
+	require.NoError(t, err)
+	conn, err = dialer.DialContext(context.Background(), "tcp", address)
+	if err == nil {
+		// NB: with TLS 1.3 the client handshake completes before the server
+		// verifies the client certificate.
+		conn.Close()
+	}
+	assert.Error(t, <-serverErrs)
+
+	// Client that does not trust the server CA is rejected.
+	otherDir := newTestDir(t)
+	defer os.RemoveAll(otherDir)
+	dialer, err = Configuration{CAFile: newTestCA(t).write(t, otherDir)}.NewDialer()
+	require.NoError(t, err)
+	_, err = dialer.DialContext(context.Background(), "tcp", address)
+	assert.Error(t, err)
+	assert.Error(t, <-serverErrs)
+}
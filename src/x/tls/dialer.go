@@ -0,0 +1,98 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package xtls
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+)
+
+// Dialer dials TLS client connections.
+type Dialer struct {
+	reloader           *certReloader
+	serverName         string
+	insecureSkipVerify bool
+}
+
+// Config returns a TLS config for a client connection to the address
+// with the current certificates.
+func (d *Dialer) Config(address string) *tls.Config {
+	cert, caPool := d.reloader.current()
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		RootCAs:            caPool,
+		ServerName:         d.serverName,
+		InsecureSkipVerify: d.insecureSkipVerify,
+	}
+	if cert != nil {
+		config.Certificates = []tls.Certificate{*cert}
+	}
+	if config.ServerName == "" {
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			config.ServerName = host
+		} else {
+			config.ServerName = address
+		}
+	}
+	return config
+}
+
+// DialContext dials a TLS client connection to the address, completing
+// the handshake before the context deadline.
+func (d *Dialer) DialContext(
+	ctx context.Context,
+	network, address string,
+) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = deadline.Sub(time.Now())
+	}
+	return d.Client(conn, address, timeout)
+}
+
+// Client returns a TLS client connection over an established connection to
+// the address, completing the handshake within the timeout if positive. The
+// connection is closed if the handshake fails.
+func (d *Dialer) Client(
+	conn net.Conn,
+	address string,
+	timeout time.Duration,
+) (net.Conn, error) {
+	tlsConn := tls.Client(conn, d.Config(address))
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout)) // nolint: errcheck
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close() // nolint: errcheck
+		return nil, err
+	}
+	if timeout > 0 {
+		conn.SetDeadline(time.Time{}) // nolint: errcheck
+	}
+	return tlsConn, nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package xtls
+
+import (
+	"crypto/tls"
+	"net"
+	"time"
+
+	xserver "github.com/m3db/m3x/server"
+)
+
+const (
+	// tcpKeepAlivePeriod is the keep alive period of accepted connections,
+	// matching that of net/http servers.
+	tcpKeepAlivePeriod = 3 * time.Minute
+)
+
+// Listen returns a TLS listener on the TCP address.
+func Listen(address string, config *tls.Config) (net.Listener, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	return NewListener(listener, config), nil
+}
+
+// NewListener returns a TLS listener over the listener, TCP keep alives are
+// enabled on accepted TCP connections as they can no longer be enabled on
+// the TLS connections returned.
+func NewListener(listener net.Listener, config *tls.Config) net.Listener {
+	return tls.NewListener(keepAliveListener{listener}, config)
+}
+
+type keepAliveListener struct {
+	net.Listener
+}
+
+func (l keepAliveListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		tcpConn.SetKeepAlive(true)                     // nolint: errcheck
+		tcpConn.SetKeepAlivePeriod(tcpKeepAlivePeriod) // nolint: errcheck
+	}
+	return conn, nil
+}
+
+type server struct {
+	xserver.Server
+
+	address string
+	config  *tls.Config
+}
+
+// NewServer returns a server that serves the server over TLS connections
+// when listening on the address.
+func NewServer(
+	address string,
+	s xserver.Server,
+	config *tls.Config,
+) xserver.Server {
+	return &server{Server: s, address: address, config: config}
+}
+
+func (s *server) ListenAndServe() error {
+	listener, err := Listen(s.address, s.config)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
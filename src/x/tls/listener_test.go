@@ -0,0 +1,61 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package xtls
+
+import (
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenServesTLS(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t)
+	caFile := ca.write(t, dir)
+	certFile, keyFile := ca.issue(t, dir, "server", 2)
+
+	serverConfig, err := Configuration{
+		CertFile: certFile,
+		KeyFile:  keyFile,
+	}.NewServerConfig()
+	require.NoError(t, err)
+
+	listener, err := Listen("127.0.0.1:0", serverConfig)
+	require.NoError(t, err)
+	defer listener.Close()
+	serverErrs := serveTestHandshakes(listener)
+
+	dialer, err := Configuration{CAFile: caFile}.NewDialer()
+	require.NoError(t, err)
+
+	// Handshake over an established connection.
+	raw, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	conn, err := dialer.Client(raw, listener.Addr().String(), 10*time.Second)
+	require.NoError(t, err)
+	require.NoError(t, <-serverErrs)
+	conn.Close()
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package xtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// certReloader loads a certificate and certificate authorities from files,
+// reloading them when the files change.
+type certReloader struct {
+	sync.Mutex
+
+	certFile       string
+	keyFile        string
+	caFile         string
+	reloadInterval time.Duration
+	nowFn          func() time.Time
+
+	lastChecked time.Time
+	modTimes    []time.Time
+	cert        *tls.Certificate
+	caPool      *x509.CertPool
+}
+
+func newCertReloader(
+	certFile, keyFile, caFile string,
+	reloadInterval time.Duration,
+	nowFn func() time.Time,
+) (*certReloader, error) {
+	r := &certReloader{
+		certFile:       certFile,
+		keyFile:        keyFile,
+		caFile:         caFile,
+		reloadInterval: reloadInterval,
+		nowFn:          nowFn,
+		lastChecked:    nowFn(),
+	}
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadWithLock(modTimes); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// current returns the current certificate, which is nil if no certificate
+// file is set, and the current certificate authorities, which are nil if no
+// certificate authorities file is set.
+func (r *certReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.Lock()
+	defer r.Unlock()
+
+	now := r.nowFn()
+	if r.reloadInterval > 0 && now.Sub(r.lastChecked) >= r.reloadInterval {
+		r.lastChecked = now
+		// NB: keep using the previously loaded files if they cannot be
+		// loaded, e.g. if only one of the certificate or key has been
+		// replaced so far, they are loaded again on the next check.
+		if modTimes, err := r.statFiles(); err == nil && r.changedWithLock(modTimes) {
+			r.loadWithLock(modTimes) // nolint: errcheck
+		}
+	}
+	return r.cert, r.caPool
+}
+
+func (r *certReloader) files() []string {
+	return []string{r.certFile, r.keyFile, r.caFile}
+}
+
+func (r *certReloader) statFiles() ([]time.Time, error) {
+	files := r.files()
+	modTimes := make([]time.Time, len(files))
+	for i, file := range files {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func (r *certReloader) changedWithLock(modTimes []time.Time) bool {
+	for i := range modTimes {
+		if !modTimes[i].Equal(r.modTimes[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *certReloader) loadWithLock(modTimes []time.Time) error {
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		loaded, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return fmt.Errorf("could not load tls certificate %s: %v", r.certFile, err)
+		}
+		cert = &loaded
+	}
+
+	var caPool *x509.CertPool
+	if r.caFile != "" {
+		pem, err := ioutil.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("could not read tls ca file %s: %v", r.caFile, err)
+		}
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("could not parse any certificates from tls ca file %s", r.caFile)
+		}
+	}
+
+	r.cert = cert
+	r.caPool = caPool
+	r.modTimes = modTimes
+	return nil
+}
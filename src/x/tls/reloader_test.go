@@ -0,0 +1,108 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package xtls
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testClock struct {
+	sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.Lock()
+	c.now = c.now.Add(d)
+	c.Unlock()
+}
+
+func TestCertReloaderReloadsChangedFiles(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t)
+	caFile := ca.write(t, dir)
+	certFile, keyFile := ca.issue(t, dir, "server", 2)
+
+	clock := &testClock{now: time.Now()}
+	reloader, err := newCertReloader(certFile, keyFile, caFile, time.Minute, clock.Now)
+	require.NoError(t, err)
+
+	cert, caPool := reloader.current()
+	require.NotNil(t, cert)
+	require.NotNil(t, caPool)
+	original := cert.Certificate[0]
+
+	// Replace the certificate, ensuring the modification time changes.
+	ca.issue(t, dir, "server", 3)
+	modTime := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+
+	// Not reloaded until the reload interval has passed.
+	cert, _ = reloader.current()
+	assert.Equal(t, original, cert.Certificate[0])
+
+	clock.Advance(time.Minute)
+	cert, _ = reloader.current()
+	assert.NotEqual(t, original, cert.Certificate[0])
+}
+
+func TestCertReloaderKeepsPreviousOnInvalidFiles(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t)
+	certFile, keyFile := ca.issue(t, dir, "server", 2)
+
+	clock := &testClock{now: time.Now()}
+	reloader, err := newCertReloader(certFile, keyFile, "", time.Minute, clock.Now)
+	require.NoError(t, err)
+	cert, caPool := reloader.current()
+	assert.Nil(t, caPool)
+	original := cert.Certificate[0]
+
+	// Only the certificate has been replaced so far so it no longer
+	// matches the key.
+	otherDir := newTestDir(t)
+	defer os.RemoveAll(otherDir)
+	otherCert, _ := ca.issue(t, otherDir, "server", 3)
+	require.NoError(t, os.Rename(otherCert, certFile))
+	modTime := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+
+	clock.Advance(time.Minute)
+	cert, _ = reloader.current()
+	assert.Equal(t, original, cert.Certificate[0])
+}